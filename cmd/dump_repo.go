@@ -32,7 +32,7 @@ var CmdDumpRepository = &cli.Command{
 		&cli.StringFlag{
 			Name:  "git_service",
 			Value: "",
-			Usage: "Git service, git, github, gitea, gitlab. If clone_addr could be recognized, this could be ignored.",
+			Usage: "Git service, git, github, gitea, gitlab, bitbucket. If clone_addr could be recognized, this could be ignored.",
 		},
 		&cli.StringFlag{
 			Name:    "repo_dir",
@@ -110,6 +110,8 @@ func runDumpRepository(ctx *cli.Context) error {
 		serviceStr = "gitlab"
 	} else if strings.HasPrefix(strings.ToLower(cloneAddr), "https://gitea.com/") {
 		serviceStr = "gitea"
+	} else if strings.HasPrefix(strings.ToLower(cloneAddr), "https://bitbucket.org/") {
+		serviceStr = "bitbucket"
 	}
 	if serviceStr == "" {
 		return errors.New("git_service missed or clone_addr cannot be recognized")
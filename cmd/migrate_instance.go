@@ -0,0 +1,96 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/urfave/cli/v2"
+)
+
+// CmdMigrateInstance represents the available migrate instance sub-command.
+var CmdMigrateInstance = &cli.Command{
+	Name:  "migrate-instance",
+	Usage: "Migrate organizations and users from another Gitea instance",
+	Description: `This is a command for migrating whole organizations and users with their repositories, teams, members,
+webhooks and generic packages from another Gitea instance. The users of the other instance are mapped to the local
+users with the same email, the token of an administrator of the other instance is needed to map them all.
+Run it with --dry-run first to get a report of what would be migrated. With a --state-file, a migration which
+failed or was interrupted can be run again to resume it.`,
+	Action: runMigrateInstance,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "base-url",
+			Usage:    "The URL of the other instance",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "auth-token",
+			Usage: "The personal token to visit the other instance",
+		},
+		&cli.StringSliceFlag{
+			Name:     "owner",
+			Usage:    "The name of an organization or a user to migrate, can be repeated",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "units",
+			Value: "",
+			Usage: `Which repository items will be migrated, one or more units should be separated as comma.
+wiki, issues, labels, releases, release_assets, milestones, pull_requests, comments are allowed. Empty means all units.`,
+		},
+		&cli.StringFlag{
+			Name:  "state-file",
+			Usage: "The file recording the progress of the migration, the items it records are skipped",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only report what would be migrated",
+		},
+	},
+}
+
+func runMigrateInstance(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setting.MustInstalled()
+
+	var units []string
+	if s := c.String("units"); s != "" {
+		units = strings.Split(s, ",")
+	}
+
+	// the migration runs in the server process, which may have another working directory
+	stateFile := c.String("state-file")
+	if stateFile != "" {
+		var err error
+		if stateFile, err = filepath.Abs(stateFile); err != nil {
+			return err
+		}
+	}
+	if stateFile == "" && !c.Bool("dry-run") {
+		return errors.New("--state-file is required to resume the migration if it fails")
+	}
+
+	report, extra := private.MigrateInstance(ctx, private.MigrateInstanceParams{
+		BaseURL:   c.String("base-url"),
+		AuthToken: c.String("auth-token"),
+		Owners:    c.StringSlice("owner"),
+		Units:     units,
+		StateFile: stateFile,
+		DryRun:    c.Bool("dry-run"),
+	})
+	if extra.HasError() {
+		return handleCliResponseExtra(extra)
+	}
+	_, _ = fmt.Print(report)
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// NotificationEvent is a type of event a user can choose how to be notified of
+type NotificationEvent string
+
+const (
+	// NotificationEventIssueOpened is an issue opened in a watched repository
+	NotificationEventIssueOpened NotificationEvent = "issue_opened"
+	// NotificationEventReviewRequested is a review of a pull request requested from the user
+	NotificationEventReviewRequested NotificationEvent = "review_requested"
+	// NotificationEventCIFailed is a failed Actions run of a watched repository or triggered by the user
+	NotificationEventCIFailed NotificationEvent = "ci_failed"
+	// NotificationEventReleasePublished is a release published in a watched repository
+	NotificationEventReleasePublished NotificationEvent = "release_published"
+)
+
+// NotificationEvents are the events which have notification preferences
+var NotificationEvents = []NotificationEvent{
+	NotificationEventIssueOpened,
+	NotificationEventReviewRequested,
+	NotificationEventCIFailed,
+	NotificationEventReleasePublished,
+}
+
+// IsValid checks whether the event has notification preferences
+func (e NotificationEvent) IsValid() bool {
+	for _, event := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery is how the user is notified of an event by email
+type NotificationDelivery string
+
+const (
+	// NotificationDeliveryEmail sends an email for every event
+	NotificationDeliveryEmail NotificationDelivery = "email"
+	// NotificationDeliveryDigest collects the events into the digest email of the user
+	NotificationDeliveryDigest NotificationDelivery = "digest"
+	// NotificationDeliveryNone doesn't send any email for the event
+	NotificationDeliveryNone NotificationDelivery = "none"
+)
+
+// IsValid checks whether the delivery is known
+func (d NotificationDelivery) IsValid() bool {
+	return d == NotificationDeliveryEmail || d == NotificationDeliveryDigest || d == NotificationDeliveryNone
+}
+
+// DefaultNotificationDelivery returns the delivery of an event for the users who have no preference for it,
+// failed runs were never mailed so they still aren't by default
+func DefaultNotificationDelivery(event NotificationEvent) NotificationDelivery {
+	if event == NotificationEventCIFailed {
+		return NotificationDeliveryNone
+	}
+	return NotificationDeliveryEmail
+}
+
+// NotificationPreference is how a user wants to be notified of an event of a repository, a preference with
+// no repository applies to all the repositories without their own preference
+type NotificationPreference struct {
+	ID          int64                `xorm:"pk autoincr"`
+	UserID      int64                `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID      int64                `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Event       NotificationEvent    `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	Delivery    NotificationDelivery `xorm:"VARCHAR(20) NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp   `xorm:"updated"`
+}
+
+// NotificationDigestFrequency is how often the digest email of a user is sent
+type NotificationDigestFrequency string
+
+const (
+	// NotificationDigestDisabled doesn't send digest emails
+	NotificationDigestDisabled NotificationDigestFrequency = ""
+	// NotificationDigestDaily sends a digest email every day
+	NotificationDigestDaily NotificationDigestFrequency = "daily"
+	// NotificationDigestWeekly sends a digest email every week
+	NotificationDigestWeekly NotificationDigestFrequency = "weekly"
+)
+
+// IsValid checks whether the frequency is known
+func (f NotificationDigestFrequency) IsValid() bool {
+	return f == NotificationDigestDisabled || f == NotificationDigestDaily || f == NotificationDigestWeekly
+}
+
+// Interval returns the time between two digests
+func (f NotificationDigestFrequency) Interval() time.Duration {
+	switch f {
+	case NotificationDigestDaily:
+		return 24 * time.Hour
+	case NotificationDigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// NotificationDigest records the digest emails of a user
+type NotificationDigest struct {
+	ID        int64                       `xorm:"pk autoincr"`
+	UserID    int64                       `xorm:"UNIQUE NOT NULL"`
+	Frequency NotificationDigestFrequency `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	// LastSentUnix is the end of the period of the last digest, the next digest begins there
+	LastSentUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// NotificationDigestItem is an event without a notification of its own which is waiting for the next digest
+// of the user
+type NotificationDigestItem struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"INDEX NOT NULL"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	Event       NotificationEvent  `xorm:"VARCHAR(50) NOT NULL"`
+	Title       string             `xorm:"TEXT"`
+	Link        string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(NotificationPreference))
+	db.RegisterModel(new(NotificationDigest))
+	db.RegisterModel(new(NotificationDigestItem))
+}
+
+// GetNotificationPreferences returns the notification preferences of a user, the ones for all repositories first
+func GetNotificationPreferences(ctx context.Context, userID int64) ([]*NotificationPreference, error) {
+	prefs := make([]*NotificationPreference, 0, len(NotificationEvents))
+	return prefs, db.GetEngine(ctx).Where("user_id = ?", userID).OrderBy("repo_id, id").Find(&prefs)
+}
+
+// SetNotificationPreference sets how a user is notified of an event of a repository, or of all the repositories
+// when repoID is 0
+func SetNotificationPreference(ctx context.Context, userID, repoID int64, event NotificationEvent, delivery NotificationDelivery) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		pref := &NotificationPreference{}
+		has, err := db.GetEngine(ctx).Where("user_id = ? AND repo_id = ? AND event = ?", userID, repoID, event).Get(pref)
+		if err != nil {
+			return err
+		}
+		pref.UserID, pref.RepoID, pref.Event, pref.Delivery = userID, repoID, event, delivery
+		if has {
+			_, err = db.GetEngine(ctx).ID(pref.ID).Cols("delivery").Update(pref)
+			return err
+		}
+		return db.Insert(ctx, pref)
+	})
+}
+
+// DeleteNotificationPreference removes the preference of a user for an event of a repository, so the preference
+// for all the repositories applies again
+func DeleteNotificationPreference(ctx context.Context, userID, repoID int64, event NotificationEvent) error {
+	_, err := db.GetEngine(ctx).Where("user_id = ? AND repo_id = ? AND event = ?", userID, repoID, event).Delete(&NotificationPreference{})
+	return err
+}
+
+// GetNotificationDeliveries returns how the users are notified of an event of a repository, resolving their
+// preference for the repository, then the one for all the repositories, then the default one
+func GetNotificationDeliveries(ctx context.Context, userIDs []int64, repoID int64, event NotificationEvent) (map[int64]NotificationDelivery, error) {
+	deliveries := make(map[int64]NotificationDelivery, len(userIDs))
+	for _, id := range userIDs {
+		deliveries[id] = DefaultNotificationDelivery(event)
+	}
+	if len(userIDs) == 0 {
+		return deliveries, nil
+	}
+
+	prefs := make([]*NotificationPreference, 0, len(userIDs))
+	if err := db.GetEngine(ctx).
+		Where(builder.In("user_id", userIDs).And(builder.In("repo_id", 0, repoID)).And(builder.Eq{"event": event})).
+		OrderBy("repo_id").
+		Find(&prefs); err != nil {
+		return nil, err
+	}
+	// the preferences for the repository are last and override the ones for all the repositories
+	for _, pref := range prefs {
+		deliveries[pref.UserID] = pref.Delivery
+	}
+	return deliveries, nil
+}
+
+// GetNotificationDigest returns the digest settings of a user, which are disabled if the user never set them
+func GetNotificationDigest(ctx context.Context, userID int64) (*NotificationDigest, error) {
+	digest := &NotificationDigest{UserID: userID}
+	if _, err := db.GetEngine(ctx).Get(digest); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// SetNotificationDigestFrequency sets how often the digest email of a user is sent, the first digest covers
+// what happens from now on
+func SetNotificationDigestFrequency(ctx context.Context, userID int64, frequency NotificationDigestFrequency) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		digest, err := GetNotificationDigest(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if digest.ID != 0 && digest.Frequency == frequency {
+			return nil
+		}
+		digest.Frequency = frequency
+		digest.LastSentUnix = timeutil.TimeStampNow()
+		if digest.ID == 0 {
+			return db.Insert(ctx, digest)
+		}
+		if _, err := db.GetEngine(ctx).ID(digest.ID).Cols("frequency", "last_sent_unix").Update(digest); err != nil {
+			return err
+		}
+		if frequency == NotificationDigestDisabled {
+			_, err = db.GetEngine(ctx).Delete(&NotificationDigestItem{UserID: userID})
+		}
+		return err
+	})
+}
+
+// FindDueNotificationDigests returns the digests whose period ended before now
+func FindDueNotificationDigests(ctx context.Context, now timeutil.TimeStamp, limit int) ([]*NotificationDigest, error) {
+	cond := builder.NewCond()
+	for _, frequency := range []NotificationDigestFrequency{NotificationDigestDaily, NotificationDigestWeekly} {
+		cond = cond.Or(builder.Eq{"frequency": frequency}.And(builder.Lte{"last_sent_unix": now.AddDuration(-frequency.Interval())}))
+	}
+	digests := make([]*NotificationDigest, 0, limit)
+	return digests, db.GetEngine(ctx).Where(cond).OrderBy("last_sent_unix").Limit(limit).Find(&digests)
+}
+
+// UpdateNotificationDigestSent records the end of the period of the last digest of a user
+func UpdateNotificationDigestSent(ctx context.Context, digest *NotificationDigest, until timeutil.TimeStamp) error {
+	digest.LastSentUnix = until
+	_, err := db.GetEngine(ctx).ID(digest.ID).Cols("last_sent_unix").Update(digest)
+	return err
+}
+
+// AddNotificationDigestItem keeps an event for the next digest of a user
+func AddNotificationDigestItem(ctx context.Context, item *NotificationDigestItem) error {
+	return db.Insert(ctx, item)
+}
+
+// GetNotificationDigestItems returns the events kept for the digest of a user before a time
+func GetNotificationDigestItems(ctx context.Context, userID int64, until timeutil.TimeStamp) ([]*NotificationDigestItem, error) {
+	items := make([]*NotificationDigestItem, 0, 10)
+	return items, db.GetEngine(ctx).Where("user_id = ? AND created_unix < ?", userID, until).OrderBy("repo_id, created_unix").Find(&items)
+}
+
+// DeleteNotificationDigestItems removes the events kept for the digest of a user before a time
+func DeleteNotificationDigestItems(ctx context.Context, userID int64, until timeutil.TimeStamp) error {
+	_, err := db.GetEngine(ctx).Where("user_id = ? AND created_unix < ?", userID, until).Delete(&NotificationDigestItem{})
+	return err
+}
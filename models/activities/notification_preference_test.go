@@ -0,0 +1,88 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities_test
+
+import (
+	"testing"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotificationDeliveries(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	event := activities_model.NotificationEventReleasePublished
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, 2, 0, event, activities_model.NotificationDeliveryDigest))
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, 2, 1, event, activities_model.NotificationDeliveryNone))
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, 4, 0, event, activities_model.NotificationDeliveryNone))
+	// a preference set again is updated
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, 4, 0, event, activities_model.NotificationDeliveryDigest))
+	unittest.AssertCount(t, &activities_model.NotificationPreference{UserID: 4}, 1)
+
+	deliveries, err := activities_model.GetNotificationDeliveries(db.DefaultContext, []int64{2, 4, 5}, 1, event)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]activities_model.NotificationDelivery{
+		2: activities_model.NotificationDeliveryNone,
+		4: activities_model.NotificationDeliveryDigest,
+		5: activities_model.NotificationDeliveryEmail,
+	}, deliveries)
+
+	deliveries, err = activities_model.GetNotificationDeliveries(db.DefaultContext, []int64{2}, 2, event)
+	assert.NoError(t, err)
+	assert.Equal(t, activities_model.NotificationDeliveryDigest, deliveries[2])
+
+	deliveries, err = activities_model.GetNotificationDeliveries(db.DefaultContext, []int64{2}, 1, activities_model.NotificationEventCIFailed)
+	assert.NoError(t, err)
+	assert.Equal(t, activities_model.NotificationDeliveryNone, deliveries[2])
+
+	assert.NoError(t, activities_model.DeleteNotificationPreference(db.DefaultContext, 2, 1, event))
+	deliveries, err = activities_model.GetNotificationDeliveries(db.DefaultContext, []int64{2}, 1, event)
+	assert.NoError(t, err)
+	assert.Equal(t, activities_model.NotificationDeliveryDigest, deliveries[2])
+}
+
+func TestFindDueNotificationDigests(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	digest, err := activities_model.GetNotificationDigest(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, activities_model.NotificationDigestDisabled, digest.Frequency)
+
+	assert.NoError(t, activities_model.SetNotificationDigestFrequency(db.DefaultContext, 2, activities_model.NotificationDigestDaily))
+	assert.NoError(t, activities_model.SetNotificationDigestFrequency(db.DefaultContext, 4, activities_model.NotificationDigestWeekly))
+
+	now := timeutil.TimeStampNow()
+	digests, err := activities_model.FindDueNotificationDigests(db.DefaultContext, now, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, digests)
+
+	// a day later only the daily digest is due
+	digests, err = activities_model.FindDueNotificationDigests(db.DefaultContext, now.AddDuration(24*time.Hour), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, digests, 1) {
+		assert.EqualValues(t, 2, digests[0].UserID)
+	}
+	digests, err = activities_model.FindDueNotificationDigests(db.DefaultContext, now.AddDuration(7*24*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, digests, 2)
+
+	assert.NoError(t, activities_model.AddNotificationDigestItem(db.DefaultContext, &activities_model.NotificationDigestItem{
+		UserID: 4,
+		RepoID: 1,
+		Event:  activities_model.NotificationEventReleasePublished,
+		Title:  "v1.0",
+	}))
+	// disabling the digest drops the events kept for it
+	assert.NoError(t, activities_model.SetNotificationDigestFrequency(db.DefaultContext, 4, activities_model.NotificationDigestDisabled))
+	unittest.AssertNotExistsBean(t, &activities_model.NotificationDigestItem{UserID: 4})
+	digests, err = activities_model.FindDueNotificationDigests(db.DefaultContext, now.AddDuration(7*24*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, digests, 1)
+}
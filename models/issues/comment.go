@@ -111,6 +111,11 @@ const (
 
 	CommentTypePin   // 36 pin Issue
 	CommentTypeUnpin // 37 unpin Issue
+
+	CommentTypeAddSubIssue    // 38 Sub-issue added
+	CommentTypeRemoveSubIssue // 39 Sub-issue removed
+
+	CommentTypeChangeTimeEstimate // 40 Time estimate changed
 )
 
 var commentStrings = []string{
@@ -152,6 +157,9 @@ var commentStrings = []string{
 	"pull_cancel_scheduled_merge",
 	"pin",
 	"unpin",
+	"add_sub_issue",
+	"remove_sub_issue",
+	"change_time_estimate",
 }
 
 func (t CommentType) String() string {
@@ -251,6 +259,7 @@ type Comment struct {
 
 	CommitID        int64
 	Line            int64 // - previous line / + proposed line
+	StartLine       int64 `xorm:"NOT NULL DEFAULT 0"` // first line of a multi-line code comment, signed like Line; 0 for single-line comments
 	TreePath        string
 	Content         string `xorm:"LONGTEXT"`
 	RenderedContent string `xorm:"-"`
@@ -718,6 +727,23 @@ func (c *Comment) UnsignedLine() uint64 {
 	return uint64(c.Line)
 }
 
+// IsMultiLine returns true if the code comment spans more than one line
+func (c *Comment) IsMultiLine() bool {
+	return c.StartLine != 0 && c.StartLine != c.Line
+}
+
+// UnsignedStartLine returns the first LOC of the code comment without + or -.
+// For single-line comments it is the same as UnsignedLine.
+func (c *Comment) UnsignedStartLine() uint64 {
+	if !c.IsMultiLine() {
+		return c.UnsignedLine()
+	}
+	if c.StartLine < 0 {
+		return uint64(c.StartLine * -1)
+	}
+	return uint64(c.StartLine)
+}
+
 // CodeCommentLink returns the url to a comment in code
 func (c *Comment) CodeCommentLink(ctx context.Context) string {
 	err := c.LoadIssue(ctx)
@@ -800,6 +826,7 @@ func CreateComment(ctx context.Context, opts *CreateCommentOptions) (_ *Comment,
 		CommitID:         opts.CommitID,
 		CommitSHA:        opts.CommitSHA,
 		Line:             opts.LineNum,
+		StartLine:        opts.StartLineNum,
 		Content:          opts.Content,
 		OldTitle:         opts.OldTitle,
 		NewTitle:         opts.NewTitle,
@@ -978,6 +1005,7 @@ type CreateCommentOptions struct {
 	CommitSHA        string
 	Patch            string
 	LineNum          int64
+	StartLineNum     int64
 	TreePath         string
 	ReviewID         int64
 	Content          string
@@ -1006,6 +1034,7 @@ func GetCommentByID(ctx context.Context, id int64) (*Comment, error) {
 // FindCommentsOptions describes the conditions to Find comments
 type FindCommentsOptions struct {
 	db.ListOptions
+	IDs         []int64
 	RepoID      int64
 	IssueID     int64
 	ReviewID    int64
@@ -1022,6 +1051,9 @@ type FindCommentsOptions struct {
 // ToConds implements FindOptions interface
 func (opts *FindCommentsOptions) ToConds() builder.Cond {
 	cond := builder.NewCond()
+	if len(opts.IDs) > 0 {
+		cond = cond.And(builder.In("comment.id", opts.IDs))
+	}
 	if opts.RepoID > 0 {
 		cond = cond.And(builder.Eq{"issue.repo_id": opts.RepoID})
 	}
@@ -1092,6 +1124,27 @@ func UpdateCommentInvalidate(ctx context.Context, c *Comment) error {
 	return err
 }
 
+// GetMigratedComment returns the comment of the given type which was created at the given time by a migration of an issue
+func GetMigratedComment(ctx context.Context, issueID int64, tp CommentType, createdUnix timeutil.TimeStamp) (*Comment, error) {
+	c := &Comment{}
+	has, err := db.GetEngine(ctx).
+		Where("issue_id = ? AND `type` = ? AND created_unix = ?", issueID, tp, createdUnix).
+		Asc("id").
+		Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCommentNotExist{0, issueID}
+	}
+	return c, nil
+}
+
+// UpdateMigratedComment updates the content of a comment downloaded again from the original repository
+func UpdateMigratedComment(ctx context.Context, c *Comment) error {
+	_, err := db.GetEngine(ctx).ID(c.ID).NoAutoTime().Cols("content", "updated_unix").Update(c)
+	return err
+}
+
 // UpdateComment updates information of comment.
 func UpdateComment(ctx context.Context, c *Comment, doer *user_model.User) error {
 	ctx, committer, err := db.TxContext(ctx)
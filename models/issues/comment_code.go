@@ -5,6 +5,7 @@ package issues
 
 import (
 	"context"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
@@ -17,6 +18,63 @@ import (
 // CodeComments represents comments on code by using this structure: FILENAME -> LINE (+ == proposed; - == previous) -> COMMENTS
 type CodeComments map[string]map[int64][]*Comment
 
+// Suggestion returns the lines of code proposed by the first "suggestion" fenced code block of a code comment.
+// An empty suggestion proposes to remove the commented lines.
+func (c *Comment) Suggestion() ([]string, bool) {
+	if c.Type != CommentTypeCode {
+		return nil, false
+	}
+	return ParseSuggestion(c.Content)
+}
+
+// HasSuggestion returns true if the code comment contains a suggested change
+func (c *Comment) HasSuggestion() bool {
+	_, ok := c.Suggestion()
+	return ok
+}
+
+// ParseSuggestion extracts the lines of the first "suggestion" fenced code block in a markdown content.
+// Unterminated blocks are ignored, so an incomplete comment is never applied to the code.
+func ParseSuggestion(content string) ([]string, bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		fence, ok := suggestionFence(line)
+		if !ok {
+			continue
+		}
+		var suggestion []string
+		for _, l := range lines[i+1:] {
+			if isClosingFence(l, fence) {
+				return suggestion, true
+			}
+			suggestion = append(suggestion, l)
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+// suggestionFence returns the opening fence of a line like "```suggestion"
+func suggestionFence(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+	if n < 3 || strings.TrimSpace(trimmed[n:]) != "suggestion" {
+		return "", false
+	}
+	return trimmed[:n], true
+}
+
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
 // FetchCodeComments will return a 2d-map: ["Path"]["Line"] = Comments at line
 func FetchCodeComments(ctx context.Context, issue *Issue, currentUser *user_model.User, showOutdatedComments bool) (CodeComments, error) {
 	return fetchCodeCommentsByReview(ctx, issue, currentUser, nil, showOutdatedComments)
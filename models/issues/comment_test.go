@@ -97,3 +97,23 @@ func TestMigrate_InsertIssueComments(t *testing.T) {
 
 	unittest.CheckConsistencyFor(t, &issues_model.Issue{})
 }
+
+func TestParseSuggestion(t *testing.T) {
+	lines, ok := issues_model.ParseSuggestion("Better:\n```suggestion\nfoo := 1\nbar := 2\n```\ntrailing text")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"foo := 1", "bar := 2"}, lines)
+
+	lines, ok = issues_model.ParseSuggestion("Remove it\r\n~~~~ suggestion\r\n~~~~\r\n")
+	assert.True(t, ok)
+	assert.Empty(t, lines)
+
+	lines, ok = issues_model.ParseSuggestion("````suggestion\n```\nnested fence\n```\n````")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"```", "nested fence", "```"}, lines)
+
+	_, ok = issues_model.ParseSuggestion("```go\nfoo := 1\n```")
+	assert.False(t, ok)
+
+	_, ok = issues_model.ParseSuggestion("```suggestion\nunterminated")
+	assert.False(t, ok)
+}
@@ -136,6 +136,11 @@ func addUpdateIssueProject(ctx context.Context, issue *Issue, doer *user_model.U
 		return err
 	}
 
+	// the values of the fields only belong to the project they were set in
+	if err := project_model.DeleteIssueFieldValues(ctx, issue.ID, newProjectID); err != nil {
+		return err
+	}
+
 	if oldProjectID > 0 || newProjectID > 0 {
 		if _, err := CreateComment(ctx, &CreateCommentOptions{
 			Type:         CommentTypeProject,
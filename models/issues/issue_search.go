@@ -33,6 +33,10 @@ type IssuesOptions struct { //nolint
 	MilestoneIDs       []int64
 	ProjectID          int64
 	ProjectBoardID     int64
+	ParentID           int64             // the parent of the sub-issues, db.NoConditionID for the issues without a parent
+	TypeID             int64             // the type of the issues, db.NoConditionID for the issues without a type
+	FieldValues        map[string]string // values of the fields of the type by field id, compared case insensitively
+	SLAState           int64             // the state of the SLA of the issues, db.NoConditionID for the issues without a SLA
 	IsClosed           util.OptionalBool
 	IsPull             util.OptionalBool
 	LabelIDs           []int64
@@ -180,6 +184,37 @@ func applyProjectCondition(sess *xorm.Session, opts *IssuesOptions) *xorm.Sessio
 	return sess
 }
 
+func applyParentCondition(sess *xorm.Session, opts *IssuesOptions) *xorm.Session {
+	if opts.ParentID > 0 { // sub-issues of a specific issue
+		sess.In("issue.id", builder.Select("issue_id").From("issue_parent").Where(builder.Eq{"parent_id": opts.ParentID}))
+	} else if opts.ParentID == db.NoConditionID { // show those that have no parent
+		sess.NotIn("issue.id", builder.Select("issue_id").From("issue_parent"))
+	}
+	return sess
+}
+
+func applySLACondition(sess *xorm.Session, opts *IssuesOptions) *xorm.Session {
+	if opts.SLAState > 0 {
+		sess.In("issue.id", builder.Select("issue_id").From("issue_sla").Where(builder.Eq{"state": opts.SLAState}))
+	} else if opts.SLAState == db.NoConditionID {
+		sess.NotIn("issue.id", builder.Select("issue_id").From("issue_sla"))
+	}
+	return sess
+}
+
+func applyTypeCondition(sess *xorm.Session, opts *IssuesOptions) *xorm.Session {
+	if opts.TypeID > 0 {
+		sess.And("issue.type_id = ?", opts.TypeID)
+	} else if opts.TypeID == db.NoConditionID {
+		sess.And("issue.type_id = 0")
+	}
+	for fieldID, value := range opts.FieldValues {
+		sess.In("issue.id", builder.Select("issue_id").From("issue_field_value").
+			Where(builder.Eq{"field_id": fieldID}.And(builder.Expr("LOWER(value) = ?", strings.ToLower(value)))))
+	}
+	return sess
+}
+
 func applyRepoConditions(sess *xorm.Session, opts *IssuesOptions) *xorm.Session {
 	if len(opts.RepoIDs) == 1 {
 		opts.RepoCond = builder.Eq{"issue.repo_id": opts.RepoIDs[0]}
@@ -248,6 +283,12 @@ func applyConditions(sess *xorm.Session, opts *IssuesOptions) *xorm.Session {
 		}
 	}
 
+	applyParentCondition(sess, opts)
+
+	applyTypeCondition(sess, opts)
+
+	applySLACondition(sess, opts)
+
 	switch opts.IsPull {
 	case util.OptionalBoolTrue:
 		sess.And("issue.is_pull=?", true)
@@ -148,6 +148,12 @@ func applyIssuesOptions(sess *xorm.Session, opts *IssuesOptions, issueIDs []int6
 
 	applyProjectCondition(sess, opts)
 
+	applyParentCondition(sess, opts)
+
+	applyTypeCondition(sess, opts)
+
+	applySLACondition(sess, opts)
+
 	if opts.AssigneeID > 0 {
 		applyAssigneeCondition(sess, opts.AssigneeID)
 	} else if opts.AssigneeID == db.NoConditionID {
@@ -0,0 +1,345 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrIssueTypeNotExist represents a "IssueTypeNotExist" kind of error.
+type ErrIssueTypeNotExist struct {
+	ID    int64
+	OrgID int64
+	Name  string
+}
+
+// IsErrIssueTypeNotExist checks if an error is a ErrIssueTypeNotExist.
+func IsErrIssueTypeNotExist(err error) bool {
+	_, ok := err.(ErrIssueTypeNotExist)
+	return ok
+}
+
+func (err ErrIssueTypeNotExist) Error() string {
+	return fmt.Sprintf("issue type does not exist [id: %d, org_id: %d, name: %s]", err.ID, err.OrgID, err.Name)
+}
+
+func (err ErrIssueTypeNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrIssueTypeAlreadyExist represents a "IssueTypeAlreadyExist" kind of error.
+type ErrIssueTypeAlreadyExist struct {
+	OrgID int64
+	Name  string
+}
+
+// IsErrIssueTypeAlreadyExist checks if an error is a ErrIssueTypeAlreadyExist.
+func IsErrIssueTypeAlreadyExist(err error) bool {
+	_, ok := err.(ErrIssueTypeAlreadyExist)
+	return ok
+}
+
+func (err ErrIssueTypeAlreadyExist) Error() string {
+	return fmt.Sprintf("issue type already exists [org_id: %d, name: %s]", err.OrgID, err.Name)
+}
+
+func (err ErrIssueTypeAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// IssueType represents a type of the issues of the repositories of an organization, like Bug or Feature.
+// Its fields are described by an issue form definition and their values are stored for each issue.
+type IssueType struct {
+	ID          int64  `xorm:"pk autoincr"`
+	OrgID       int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Name        string `xorm:"UNIQUE(s) NOT NULL"`
+	Description string
+	Color       string `xorm:"VARCHAR(7)"`
+	// Template is the yaml definition of the fields, the "body" of an issue form
+	Template    string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// IssueFieldValue represents a value of a field of the type of an issue, the fields with several values,
+// like checkboxes, have a row by value
+type IssueFieldValue struct {
+	ID      int64  `xorm:"pk autoincr"`
+	IssueID int64  `xorm:"INDEX NOT NULL"`
+	FieldID string `xorm:"INDEX NOT NULL"`
+	Value   string `xorm:"TEXT"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueType))
+	db.RegisterModel(new(IssueFieldValue))
+}
+
+// Fields returns the fields of the issue type parsed from its template
+func (t *IssueType) Fields() ([]*api.IssueFormField, error) {
+	return issue_template.UnmarshalFields(t.Template)
+}
+
+// NewIssueType creates a new issue type for an organization
+func NewIssueType(ctx context.Context, t *IssueType) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if _, err := t.Fields(); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid template: %v", err)
+	}
+
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if has, err := db.GetEngine(ctx).Where("org_id = ? AND name = ?", t.OrgID, t.Name).Exist(new(IssueType)); err != nil {
+		return err
+	} else if has {
+		return ErrIssueTypeAlreadyExist{OrgID: t.OrgID, Name: t.Name}
+	}
+	if err := db.Insert(ctx, t); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// UpdateIssueType updates an issue type, the values of the fields removed from the template are kept
+// but aren't displayed anymore
+func UpdateIssueType(ctx context.Context, t *IssueType) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if _, err := t.Fields(); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid template: %v", err)
+	}
+
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if has, err := db.GetEngine(ctx).Where("org_id = ? AND name = ? AND id <> ?", t.OrgID, t.Name, t.ID).Exist(new(IssueType)); err != nil {
+		return err
+	} else if has {
+		return ErrIssueTypeAlreadyExist{OrgID: t.OrgID, Name: t.Name}
+	}
+	if _, err := db.GetEngine(ctx).ID(t.ID).Cols("name", "description", "color", "template").Update(t); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// DeleteIssueType deletes an issue type of an organization, its issues have no type anymore
+func DeleteIssueType(ctx context.Context, orgID, id int64) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if err := deleteIssueTypes(ctx, builder.Eq{"id": id, "org_id": orgID}); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// DeleteIssueTypesByOrgID deletes all the issue types of an organization
+func DeleteIssueTypesByOrgID(ctx context.Context, orgID int64) error {
+	return deleteIssueTypes(ctx, builder.Eq{"org_id": orgID})
+}
+
+func deleteIssueTypes(ctx context.Context, cond builder.Cond) error {
+	typeIDs := builder.Select("id").From("issue_type").Where(cond)
+	if _, err := db.GetEngine(ctx).Where(builder.In("issue_id", builder.Select("id").From("issue").Where(builder.In("type_id", typeIDs)))).
+		Delete(new(IssueFieldValue)); err != nil {
+		return err
+	}
+	if _, err := db.GetEngine(ctx).Where(builder.In("type_id", typeIDs)).Cols("type_id").NoAutoTime().Update(&Issue{}); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where(cond).Delete(new(IssueType))
+	return err
+}
+
+// GetIssueTypeByID returns the issue type by its id
+func GetIssueTypeByID(ctx context.Context, id int64) (*IssueType, error) {
+	t := new(IssueType)
+	has, err := db.GetEngine(ctx).ID(id).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueTypeNotExist{ID: id}
+	}
+	return t, nil
+}
+
+// GetIssueTypeInOrg returns an issue type of an organization by its id
+func GetIssueTypeInOrg(ctx context.Context, orgID, id int64) (*IssueType, error) {
+	t := new(IssueType)
+	has, err := db.GetEngine(ctx).ID(id).Where("org_id = ?", orgID).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueTypeNotExist{ID: id, OrgID: orgID}
+	}
+	return t, nil
+}
+
+// GetIssueTypeByName returns an issue type of an organization by its name, case insensitively
+func GetIssueTypeByName(ctx context.Context, orgID int64, name string) (*IssueType, error) {
+	t := new(IssueType)
+	has, err := db.GetEngine(ctx).Where("org_id = ? AND LOWER(name) = ?", orgID, strings.ToLower(name)).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueTypeNotExist{OrgID: orgID, Name: name}
+	}
+	return t, nil
+}
+
+// GetIssueTypesByOrgID returns the issue types of an organization ordered by name
+func GetIssueTypesByOrgID(ctx context.Context, orgID int64) ([]*IssueType, error) {
+	types := make([]*IssueType, 0, 5)
+	return types, db.GetEngine(ctx).Where("org_id = ?", orgID).Asc("name").Find(&types)
+}
+
+// GetIssueIDsByTypeID returns the ids of the issues of a type
+func GetIssueIDsByTypeID(ctx context.Context, typeID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, db.GetEngine(ctx).Table("issue").Where("type_id = ?", typeID).Cols("id").Find(&ids)
+}
+
+// LoadType loads the type of the issue
+func (issue *Issue) LoadType(ctx context.Context) (err error) {
+	if issue.Type == nil && issue.TypeID > 0 {
+		issue.Type, err = GetIssueTypeByID(ctx, issue.TypeID)
+		if IsErrIssueTypeNotExist(err) {
+			issue.TypeID = 0
+			return nil
+		}
+	}
+	return err
+}
+
+// GetIssueFieldValues returns the values of the fields of the type of an issue, by field id
+func GetIssueFieldValues(ctx context.Context, issueID int64) (map[string][]string, error) {
+	fieldValues := make([]*IssueFieldValue, 0, 10)
+	if err := db.GetEngine(ctx).Where("issue_id = ?", issueID).Asc("id").Find(&fieldValues); err != nil {
+		return nil, err
+	}
+	values := make(map[string][]string, len(fieldValues))
+	for _, v := range fieldValues {
+		values[v.FieldID] = append(values[v.FieldID], v.Value)
+	}
+	return values, nil
+}
+
+// SetIssueType changes the type of an issue and replaces the values of its fields, the values must have been
+// validated against the fields of the type. A zero type id removes the type of the issue.
+func SetIssueType(ctx context.Context, issue *Issue, typeID int64, values map[string][]string) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	issue.TypeID = typeID
+	issue.Type = nil
+	if _, err := db.GetEngine(ctx).ID(issue.ID).Cols("type_id").NoAutoTime().Update(issue); err != nil {
+		return err
+	}
+	if _, err := db.GetEngine(ctx).Where("issue_id = ?", issue.ID).Delete(new(IssueFieldValue)); err != nil {
+		return err
+	}
+	if typeID > 0 {
+		fieldValues := make([]*IssueFieldValue, 0, len(values))
+		for fieldID, vs := range values {
+			for _, v := range vs {
+				fieldValues = append(fieldValues, &IssueFieldValue{IssueID: issue.ID, FieldID: fieldID, Value: v})
+			}
+		}
+		if len(fieldValues) > 0 {
+			if err := db.Insert(ctx, fieldValues); err != nil {
+				return err
+			}
+		}
+	}
+	return committer.Commit()
+}
+
+// RemoveIssueTypesFromRepo removes the types of the issues of a repository, when the repository changes owner
+func RemoveIssueTypesFromRepo(ctx context.Context, repoID int64) error {
+	inRepo := builder.Select("id").From("issue").Where(builder.Eq{"repo_id": repoID})
+	if _, err := db.GetEngine(ctx).Where(builder.In("issue_id", inRepo)).Delete(new(IssueFieldValue)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("repo_id = ? AND type_id > 0", repoID).Cols("type_id").NoAutoTime().Update(&Issue{})
+	return err
+}
+
+// issueTypeKeywordPattern matches the `type:` and `field:` filters of a search keyword, a value can be quoted
+var issueTypeKeywordPattern = regexp.MustCompile(`(?:^|\s)(type|field):((?:[^\s"]|"[^"]*")+)`)
+
+// ParseIssueTypeKeyword extracts the type and field filters of a search keyword: `type:Bug` filters the issues
+// of a type of the organization owning the repositories, `type:none` the issues without type and
+// `field:severity=high` or `field:severity="very high"` the issues with a value of a field. It returns the keyword
+// without the filters, the type id for IssuesOptions.TypeID and the values by field id. A type filter is kept
+// in the keyword when the owner isn't given or has no such type.
+func ParseIssueTypeKeyword(ctx context.Context, keyword string, ownerID int64) (string, int64, map[string]string, error) {
+	var (
+		typeID      int64
+		fieldValues map[string]string
+		err         error
+	)
+	keyword = issueTypeKeywordPattern.ReplaceAllStringFunc(keyword, func(match string) string {
+		if err != nil {
+			return match
+		}
+		m := issueTypeKeywordPattern.FindStringSubmatch(match)
+		value := strings.ReplaceAll(m[2], `"`, "")
+		switch m[1] {
+		case "type":
+			if strings.EqualFold(value, "none") {
+				typeID = db.NoConditionID
+				return ""
+			}
+			if ownerID <= 0 {
+				return match
+			}
+			var t *IssueType
+			if t, err = GetIssueTypeByName(ctx, ownerID, value); err != nil {
+				if IsErrIssueTypeNotExist(err) {
+					err = nil
+				}
+				return match
+			}
+			typeID = t.ID
+		case "field":
+			fieldID, fieldValue, ok := strings.Cut(value, "=")
+			if !ok || fieldID == "" || fieldValue == "" {
+				return match
+			}
+			if fieldValues == nil {
+				fieldValues = make(map[string]string)
+			}
+			fieldValues[fieldID] = fieldValue
+		}
+		return ""
+	})
+	if err != nil {
+		return "", 0, nil, err
+	}
+	return strings.TrimSpace(keyword), typeID, fieldValues, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueTypes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	types, err := issues_model.GetIssueTypesByOrgID(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, types, 2) {
+		assert.EqualValues(t, "Bug", types[0].Name)
+		assert.EqualValues(t, "Feature", types[1].Name)
+	}
+
+	bug, err := issues_model.GetIssueTypeByName(db.DefaultContext, 3, "bug")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, bug.ID)
+	fields, err := bug.Fields()
+	assert.NoError(t, err)
+	assert.Len(t, fields, 3)
+
+	_, err = issues_model.GetIssueTypeInOrg(db.DefaultContext, 2, bug.ID)
+	assert.True(t, issues_model.IsErrIssueTypeNotExist(err))
+
+	err = issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{OrgID: 3, Name: "Bug", Template: bug.Template})
+	assert.True(t, issues_model.IsErrIssueTypeAlreadyExist(err))
+	err = issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{OrgID: 3, Name: "Incident", Template: "body:\n  - type: input\n"})
+	assert.Error(t, err)
+
+	incident := &issues_model.IssueType{OrgID: 3, Name: "Incident", Template: "body:\n  - type: input\n    id: impact\n    attributes:\n      label: Impact\n"}
+	assert.NoError(t, issues_model.NewIssueType(db.DefaultContext, incident))
+	incident.Name = "Feature"
+	assert.True(t, issues_model.IsErrIssueTypeAlreadyExist(issues_model.UpdateIssueType(db.DefaultContext, incident)))
+
+	assert.NoError(t, issues_model.DeleteIssueType(db.DefaultContext, 3, bug.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueType{ID: bug.ID})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueFieldValue{IssueID: 6})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6, TypeID: 0})
+}
+
+func TestSetIssueType(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	assert.NoError(t, issue.LoadType(db.DefaultContext))
+	if assert.NotNil(t, issue.Type) {
+		assert.EqualValues(t, "Bug", issue.Type.Name)
+	}
+
+	values, err := issues_model.GetIssueFieldValues(db.DefaultContext, issue.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string][]string{
+		"severity":  {"High"},
+		"platforms": {"Linux", "Windows"},
+	}, values)
+
+	assert.NoError(t, issues_model.SetIssueType(db.DefaultContext, issue, 2, map[string][]string{"motivation": {"faster"}}))
+	values, err = issues_model.GetIssueFieldValues(db.DefaultContext, issue.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string][]string{"motivation": {"faster"}}, values)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6, TypeID: 2})
+
+	assert.NoError(t, issues_model.SetIssueType(db.DefaultContext, issue, 0, nil))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueFieldValue{IssueID: 6})
+}
+
+func TestParseIssueTypeKeyword(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	keyword, typeID, fieldValues, err := issues_model.ParseIssueTypeKeyword(db.DefaultContext, `crash type:bug field:severity=high field:version="1.2 beta"`, 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "crash", keyword)
+	assert.EqualValues(t, 1, typeID)
+	assert.EqualValues(t, map[string]string{"severity": "high", "version": "1.2 beta"}, fieldValues)
+
+	keyword, typeID, _, err = issues_model.ParseIssueTypeKeyword(db.DefaultContext, "type:none", 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "", keyword)
+	assert.EqualValues(t, db.NoConditionID, typeID)
+
+	// unknown types are kept in the keyword
+	keyword, typeID, _, err = issues_model.ParseIssueTypeKeyword(db.DefaultContext, "type:incident", 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "type:incident", keyword)
+	assert.EqualValues(t, 0, typeID)
+
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		TypeID:      1,
+		FieldValues: map[string]string{"platforms": "linux"},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 6, issues[0].ID)
+	}
+}
@@ -6,6 +6,7 @@ package issues
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
@@ -286,7 +287,7 @@ func NewIssueWithIndex(ctx context.Context, doer *user_model.User, opts NewIssue
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
 
 	if opts.Issue.MilestoneID > 0 {
-		milestone, err := GetMilestoneByRepoID(ctx, opts.Issue.RepoID, opts.Issue.MilestoneID)
+		milestone, err := GetMilestoneForRepo(ctx, opts.Issue.RepoID, opts.Issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
 			return fmt.Errorf("getMilestoneByID: %w", err)
 		}
@@ -510,6 +511,37 @@ func UpdateIssueDeadline(ctx context.Context, issue *Issue, deadlineUnix timeuti
 	return committer.Commit()
 }
 
+// UpdateIssueTimeEstimate updates the time estimate of an issue in seconds, zero removes the estimate
+func UpdateIssueTimeEstimate(ctx context.Context, issue *Issue, estimate int64, doer *user_model.User) error {
+	if issue.TimeEstimate == estimate {
+		return nil
+	}
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	issue.TimeEstimate = estimate
+	if err := UpdateIssueCols(ctx, issue, "time_estimate"); err != nil {
+		return err
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if _, err := CreateComment(ctx, &CreateCommentOptions{
+		Type:    CommentTypeChangeTimeEstimate,
+		Doer:    doer,
+		Repo:    issue.Repo,
+		Issue:   issue,
+		Content: strconv.FormatInt(estimate, 10),
+	}); err != nil {
+		return err
+	}
+
+	return committer.Commit()
+}
+
 // DeleteInIssue delete records in beans with external key issue_id = ?
 func DeleteInIssue(ctx context.Context, issueID int64, beans ...any) error {
 	e := db.GetEngine(ctx)
@@ -681,6 +713,54 @@ func UpdateIssuesMigrationsByType(ctx context.Context, gitServiceType api.GitSer
 	return err
 }
 
+// UpdateMigratedIssue updates an issue with the fields downloaded again from its original repository,
+// its labels are replaced without creating comments like the migration inserts them
+func UpdateMigratedIssue(ctx context.Context, issue *Issue, labels []*Label, oldMilestoneID int64) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if _, err := db.GetEngine(ctx).ID(issue.ID).NoAutoTime().
+		Cols("name", "content", "is_closed", "closed_unix", "is_locked", "milestone_id", "updated_unix").
+		Update(issue); err != nil {
+		return err
+	}
+
+	oldLabels, err := GetLabelsByIssueID(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := db.GetEngine(ctx).Where("issue_id = ?", issue.ID).Delete(&IssueLabel{}); err != nil {
+		return err
+	}
+	issueLabels := make([]*IssueLabel, 0, len(labels))
+	for _, label := range labels {
+		issueLabels = append(issueLabels, &IssueLabel{IssueID: issue.ID, LabelID: label.ID})
+	}
+	if len(issueLabels) > 0 {
+		if err := db.Insert(ctx, issueLabels); err != nil {
+			return err
+		}
+	}
+	for _, label := range append(oldLabels, labels...) {
+		if err := updateLabelCols(ctx, label, "num_issues", "num_closed_issue"); err != nil {
+			return err
+		}
+	}
+
+	for _, milestoneID := range []int64{oldMilestoneID, issue.MilestoneID} {
+		if milestoneID > 0 {
+			if err := UpdateMilestoneCounters(ctx, milestoneID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return committer.Commit()
+}
+
 // UpdateReactionsMigrationsByType updates all migrated repositories' reactions from gitServiceType to replace originalAuthorID to posterID
 func UpdateReactionsMigrationsByType(ctx context.Context, gitServiceType api.GitServiceType, originalAuthorID string, userID int64) error {
 	_, err := db.GetEngine(ctx).Table("reaction").
@@ -700,6 +780,11 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 	// so here it uses "DELETE ... WHERE IN" with pre-queried IDs.
 	sess := db.GetEngine(ctx)
 
+	// The milestones of the organization are kept, they must not count the deleted issues anymore
+	if err := RemoveOrgMilestonesFromRepo(ctx, repoID); err != nil {
+		return nil, err
+	}
+
 	for {
 		issueIDs := make([]int64, 0, db.DefaultMaxInSize)
 
@@ -736,6 +821,16 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 			return nil, err
 		}
 
+		// Sub-issues, including the ones in other repositories
+		if err = deleteIssueParents(ctx, issueIDs); err != nil {
+			return nil, err
+		}
+
+		_, err = sess.In("issue_id", issueIDs).Delete(&IssueFieldValue{})
+		if err != nil {
+			return nil, err
+		}
+
 		_, err = sess.In("issue_id", issueIDs).Delete(&IssueUser{})
 		if err != nil {
 			return nil, err
@@ -766,6 +861,11 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 			return nil, err
 		}
 
+		_, err = sess.In("issue_id", issueIDs).Delete(&project_model.FieldValue{})
+		if err != nil {
+			return nil, err
+		}
+
 		_, err = sess.In("dependent_issue_id", issueIDs).Delete(&Comment{})
 		if err != nil {
 			return nil, err
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	project_model "code.gitea.io/gitea/models/project"
 	"code.gitea.io/gitea/modules/label"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -269,6 +270,10 @@ func DeleteLabel(ctx context.Context, id, labelID int64) error {
 		return err
 	}
 
+	if err = project_model.DeleteAutomationsByLabelID(ctx, labelID); err != nil {
+		return err
+	}
+
 	return committer.Commit()
 }
 
@@ -21,6 +21,7 @@ import (
 type ErrMilestoneNotExist struct {
 	ID     int64
 	RepoID int64
+	OrgID  int64
 	Name   string
 }
 
@@ -34,6 +35,9 @@ func (err ErrMilestoneNotExist) Error() string {
 	if len(err.Name) > 0 {
 		return fmt.Sprintf("milestone does not exist [name: %s, repo_id: %d]", err.Name, err.RepoID)
 	}
+	if err.OrgID > 0 {
+		return fmt.Sprintf("milestone does not exist [id: %d, org_id: %d]", err.ID, err.OrgID)
+	}
 	return fmt.Sprintf("milestone does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
 }
 
@@ -41,10 +45,12 @@ func (err ErrMilestoneNotExist) Unwrap() error {
 	return util.ErrNotExist
 }
 
-// Milestone represents a milestone of repository.
+// Milestone represents a milestone of a repository, or of an organization
+// when RepoID is 0, which the issues of all its repositories can be assigned to.
 type Milestone struct {
 	ID              int64                  `xorm:"pk autoincr"`
 	RepoID          int64                  `xorm:"INDEX"`
+	OrgID           int64                  `xorm:"INDEX"`
 	Repo            *repo_model.Repository `xorm:"-"`
 	Name            string
 	Content         string `xorm:"TEXT"`
@@ -94,6 +100,11 @@ func (m *Milestone) AfterLoad() {
 	}
 }
 
+// BelongsToOrg returns true if the milestone belongs to an organization
+func (m *Milestone) BelongsToOrg() bool {
+	return m.RepoID == 0 && m.OrgID > 0
+}
+
 // State returns string representation of milestone status.
 func (m *Milestone) State() api.StateType {
 	if m.IsClosed {
@@ -102,7 +113,7 @@ func (m *Milestone) State() api.StateType {
 	return api.StateOpen
 }
 
-// NewMilestone creates new milestone of repository.
+// NewMilestone creates new milestone of a repository or of an organization.
 func NewMilestone(ctx context.Context, m *Milestone) (err error) {
 	ctx, committer, err := db.TxContext(ctx)
 	if err != nil {
@@ -116,8 +127,10 @@ func NewMilestone(ctx context.Context, m *Milestone) (err error) {
 		return err
 	}
 
-	if _, err = db.Exec(ctx, "UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
-		return err
+	if m.RepoID > 0 {
+		if _, err = db.Exec(ctx, "UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
+			return err
+		}
 	}
 	return committer.Commit()
 }
@@ -139,6 +152,46 @@ func GetMilestoneByRepoID(ctx context.Context, repoID, id int64) (*Milestone, er
 	return m, nil
 }
 
+// GetMilestoneByOrgID returns the milestone of an organization.
+func GetMilestoneByOrgID(ctx context.Context, orgID, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.GetEngine(ctx).ID(id).Where("repo_id = 0 AND org_id = ?", orgID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id, OrgID: orgID}
+	}
+	return m, nil
+}
+
+// milestonesForRepoCond returns the condition of the milestones the issues of a repository can be assigned to:
+// the ones of the repository and the ones of the organization owning it
+func milestonesForRepoCond(repoID int64) builder.Cond {
+	return builder.Eq{"repo_id": repoID}.Or(builder.And(
+		builder.Eq{"repo_id": 0},
+		builder.Neq{"org_id": 0},
+		builder.In("org_id", builder.Select("owner_id").From("repository").Where(builder.Eq{"id": repoID})),
+	))
+}
+
+// HasMilestoneForRepo returns if the issues of the repository can be assigned to the milestone.
+func HasMilestoneForRepo(ctx context.Context, repoID, id int64) (bool, error) {
+	return db.GetEngine(ctx).ID(id).Where(milestonesForRepoCond(repoID)).Exist(new(Milestone))
+}
+
+// GetMilestoneForRepo returns a milestone the issues of the repository can be assigned to,
+// a milestone of the repository or of its owner organization.
+func GetMilestoneForRepo(ctx context.Context, repoID, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.GetEngine(ctx).ID(id).Where(milestonesForRepoCond(repoID)).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id, RepoID: repoID}
+	}
+	return m, nil
+}
+
 // GetMilestoneByRepoIDANDName return a milestone if one exist by name and repo
 func GetMilestoneByRepoIDANDName(ctx context.Context, repoID int64, name string) (*Milestone, error) {
 	var mile Milestone
@@ -322,7 +375,72 @@ func DeleteMilestoneByRepoID(ctx context.Context, repoID, id int64) error {
 	return committer.Commit()
 }
 
+// DeleteMilestoneByOrgID deletes a milestone from an organization and unassigns its issues.
+func DeleteMilestoneByOrgID(ctx context.Context, orgID, id int64) error {
+	m, err := GetMilestoneByOrgID(ctx, orgID, id)
+	if err != nil {
+		if IsErrMilestoneNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if _, err = db.GetEngine(ctx).ID(m.ID).Delete(new(Milestone)); err != nil {
+		return err
+	}
+	if _, err = db.Exec(ctx, "UPDATE `issue` SET milestone_id = 0 WHERE milestone_id = ?", m.ID); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// DeleteMilestonesByOrgID deletes all the milestones of an organization
+func DeleteMilestonesByOrgID(ctx context.Context, orgID int64) error {
+	if _, err := db.Exec(ctx, "UPDATE `issue` SET milestone_id = 0 WHERE milestone_id IN (SELECT id FROM milestone WHERE repo_id = 0 AND org_id = ?)", orgID); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("repo_id = 0 AND org_id = ?", orgID).Delete(new(Milestone))
+	return err
+}
+
+// RemoveOrgMilestonesFromRepo unassigns the issues of a repository from the milestones of organizations,
+// when it leaves its organization or its issues are deleted, and updates the counters of these milestones.
+func RemoveOrgMilestonesFromRepo(ctx context.Context, repoID int64) error {
+	milestoneIDs := make([]int64, 0, 5)
+	if err := db.GetEngine(ctx).Table("issue").
+		Join("INNER", "milestone", "issue.milestone_id = milestone.id").
+		Where("issue.repo_id = ? AND milestone.repo_id = 0", repoID).
+		Distinct("issue.milestone_id").
+		Find(&milestoneIDs); err != nil {
+		return err
+	}
+	if len(milestoneIDs) == 0 {
+		return nil
+	}
+
+	if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).In("milestone_id", milestoneIDs).
+		Cols("milestone_id").NoAutoTime().Update(&Issue{MilestoneID: 0}); err != nil {
+		return err
+	}
+	for _, id := range milestoneIDs {
+		if err := UpdateMilestoneCounters(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func updateRepoMilestoneNum(ctx context.Context, repoID int64) error {
+	if repoID == 0 {
+		// the milestones of organizations are not counted by any repository
+		return nil
+	}
 	_, err := db.GetEngine(ctx).Exec("UPDATE `repository` SET num_milestones=(SELECT count(*) FROM milestone WHERE repo_id=?),num_closed_milestones=(SELECT count(*) FROM milestone WHERE repo_id=? AND is_closed=?) WHERE id=?",
 		repoID,
 		repoID,
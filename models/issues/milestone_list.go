@@ -29,6 +29,7 @@ func (milestones MilestoneList) getMilestoneIDs() []int64 {
 type GetMilestonesOption struct {
 	db.ListOptions
 	RepoID   int64
+	OrgID    int64
 	State    api.StateType
 	Name     string
 	SortType string
@@ -39,6 +40,9 @@ func (opts GetMilestonesOption) toCond() builder.Cond {
 	if opts.RepoID != 0 {
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
 	}
+	if opts.OrgID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": 0, "org_id": opts.OrgID})
+	}
 
 	switch opts.State {
 	case api.StateClosed:
@@ -0,0 +1,145 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MilestoneRepoStat is the progress of the issues of a repository assigned to a milestone
+type MilestoneRepoStat struct {
+	RepoID          int64
+	NumIssues       int64
+	NumClosedIssues int64
+}
+
+// NumOpenIssues returns the number of the open issues of the repository in the milestone
+func (s *MilestoneRepoStat) NumOpenIssues() int64 {
+	return s.NumIssues - s.NumClosedIssues
+}
+
+// Completeness returns the percentage of the closed issues of the repository in the milestone
+func (s *MilestoneRepoStat) Completeness() int64 {
+	if s.NumIssues == 0 {
+		return 0
+	}
+	return s.NumClosedIssues * 100 / s.NumIssues
+}
+
+// GetMilestoneRepoStats returns the progress of a milestone per repository, only counting the given repositories
+func GetMilestoneRepoStats(ctx context.Context, milestoneID int64, repoIDs []int64) ([]*MilestoneRepoStat, error) {
+	counts := make([]*struct {
+		RepoID   int64
+		IsClosed bool
+		Count    int64
+	}, 0, len(repoIDs))
+	if len(repoIDs) == 0 {
+		return []*MilestoneRepoStat{}, nil
+	}
+	if err := db.GetEngine(ctx).Table("issue").
+		Where(builder.Eq{"milestone_id": milestoneID}).
+		And(builder.In("repo_id", repoIDs)).
+		Select("repo_id, is_closed, COUNT(*) AS count").
+		GroupBy("repo_id, is_closed").
+		OrderBy("repo_id").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+
+	stats := make([]*MilestoneRepoStat, 0, len(counts))
+	statsMap := make(map[int64]*MilestoneRepoStat, len(counts))
+	for _, c := range counts {
+		stat, ok := statsMap[c.RepoID]
+		if !ok {
+			stat = &MilestoneRepoStat{RepoID: c.RepoID}
+			statsMap[c.RepoID] = stat
+			stats = append(stats, stat)
+		}
+		stat.NumIssues += c.Count
+		if c.IsClosed {
+			stat.NumClosedIssues += c.Count
+		}
+	}
+	return stats, nil
+}
+
+// maxBurndownPoints is the maximum number of the points of a burndown, long milestones are sampled
+const maxBurndownPoints = 90
+
+// MilestoneBurndownPoint is the number of the issues of a milestone at the end of a day
+type MilestoneBurndownPoint struct {
+	Date      time.Time
+	NumIssues int64
+	NumOpen   int64
+}
+
+// GetMilestoneBurndown returns the number of open issues of a milestone at the end of each day, from the creation
+// of the milestone or of its first issue to its closing or today. The issues are counted from their creation and
+// until their closing, only the issues of the given repositories are counted.
+func GetMilestoneBurndown(ctx context.Context, m *Milestone, repoIDs []int64) ([]*MilestoneBurndownPoint, error) {
+	issues := make([]*struct {
+		CreatedUnix timeutil.TimeStamp
+		ClosedUnix  timeutil.TimeStamp
+		IsClosed    bool
+	}, 0, m.NumIssues)
+	if len(repoIDs) > 0 {
+		if err := db.GetEngine(ctx).Table("issue").
+			Where(builder.Eq{"milestone_id": m.ID}).
+			And(builder.In("repo_id", repoIDs)).
+			Cols("created_unix", "closed_unix", "is_closed").
+			Find(&issues); err != nil {
+			return nil, err
+		}
+	}
+
+	start := m.CreatedUnix
+	for _, issue := range issues {
+		if issue.CreatedUnix < start {
+			start = issue.CreatedUnix
+		}
+	}
+	end := timeutil.TimeStampNow()
+	if m.IsClosed && m.ClosedDateUnix > start {
+		end = m.ClosedDateUnix
+	}
+
+	startDay := startOfDay(start.AsLocalTime())
+	endDay := startOfDay(end.AsLocalTime())
+	days := int(endDay.Sub(startDay).Hours()/24+0.5) + 1
+	step := (days + maxBurndownPoints - 1) / maxBurndownPoints
+
+	points := make([]*MilestoneBurndownPoint, 0, days/step+1)
+	for day := 0; ; day += step {
+		if day >= days {
+			day = days - 1
+		}
+		date := startDay.AddDate(0, 0, day)
+		dayEnd := timeutil.TimeStamp(date.AddDate(0, 0, 1).Unix())
+		point := &MilestoneBurndownPoint{Date: date}
+		for _, issue := range issues {
+			if issue.CreatedUnix >= dayEnd {
+				continue
+			}
+			point.NumIssues++
+			if !issue.IsClosed || issue.ClosedUnix >= dayEnd {
+				point.NumOpen++
+			}
+		}
+		points = append(points, point)
+		if day == days-1 {
+			break
+		}
+	}
+	return points, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
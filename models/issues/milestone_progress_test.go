@@ -0,0 +1,137 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMilestoneForRepo(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// a milestone of the repository
+	milestone, err := issues_model.GetMilestoneForRepo(db.DefaultContext, 1, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, milestone.RepoID)
+
+	// a milestone of the organization owning the repository
+	milestone, err = issues_model.GetMilestoneForRepo(db.DefaultContext, 3, 6)
+	assert.NoError(t, err)
+	assert.True(t, milestone.BelongsToOrg())
+	has, err := issues_model.HasMilestoneForRepo(db.DefaultContext, 5, 6)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	// the milestones of other repositories and organizations
+	_, err = issues_model.GetMilestoneForRepo(db.DefaultContext, 3, 1)
+	assert.True(t, issues_model.IsErrMilestoneNotExist(err))
+	has, err = issues_model.HasMilestoneForRepo(db.DefaultContext, 1, 6)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	// a milestone of an organization is not a milestone of its repositories
+	_, err = issues_model.GetMilestoneByRepoID(db.DefaultContext, 3, 6)
+	assert.True(t, issues_model.IsErrMilestoneNotExist(err))
+}
+
+func TestOrgMilestones(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	milestone := &issues_model.Milestone{OrgID: 3, Name: "release"}
+	assert.NoError(t, issues_model.NewMilestone(db.DefaultContext, milestone))
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: 3}, &issues_model.Milestone{})
+
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{OrgID: 3, State: api.StateAll, SortType: "id"})
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 2) {
+		assert.EqualValues(t, 6, milestones[0].ID)
+		assert.EqualValues(t, milestone.ID, milestones[1].ID)
+	}
+
+	_, err = issues_model.GetMilestoneByOrgID(db.DefaultContext, 3, 1)
+	assert.True(t, issues_model.IsErrMilestoneNotExist(err))
+
+	assert.NoError(t, issues_model.ChangeMilestoneStatus(db.DefaultContext, milestone, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: milestone.ID}, "is_closed=1")
+
+	assert.NoError(t, issues_model.DeleteMilestoneByOrgID(db.DefaultContext, 3, milestone.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.Milestone{ID: milestone.ID})
+}
+
+func assignIssuesToMilestone(t *testing.T, milestoneID int64, issueIDs ...int64) {
+	_, err := db.GetEngine(db.DefaultContext).In("id", issueIDs).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: milestoneID})
+	assert.NoError(t, err)
+	assert.NoError(t, issues_model.UpdateMilestoneCounters(db.DefaultContext, milestoneID))
+}
+
+func TestGetMilestoneRepoStats(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issue 6 and pull 12 of repo3, issue 15 of repo5
+	assignIssuesToMilestone(t, 6, 6, 12, 15)
+	_, err := db.GetEngine(db.DefaultContext).ID(12).Cols("is_closed").Update(&issues_model.Issue{IsClosed: true})
+	assert.NoError(t, err)
+
+	stats, err := issues_model.GetMilestoneRepoStats(db.DefaultContext, 6, []int64{3, 5})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.EqualValues(t, 3, stats[0].RepoID)
+		assert.EqualValues(t, 2, stats[0].NumIssues)
+		assert.EqualValues(t, 1, stats[0].NumOpenIssues())
+		assert.EqualValues(t, 50, stats[0].Completeness())
+		assert.EqualValues(t, 5, stats[1].RepoID)
+		assert.EqualValues(t, 1, stats[1].NumIssues)
+	}
+
+	// only the visible repositories are counted
+	stats, err = issues_model.GetMilestoneRepoStats(db.DefaultContext, 6, []int64{5})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+}
+
+func TestGetMilestoneBurndown(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	day := int64(24 * 60 * 60)
+	now := timeutil.TimeStampNow()
+	assignIssuesToMilestone(t, 6, 6, 15)
+	_, err := db.Exec(db.DefaultContext, "UPDATE `issue` SET created_unix = ? WHERE id = 6", int64(now)-3*day)
+	assert.NoError(t, err)
+	_, err = db.Exec(db.DefaultContext, "UPDATE `issue` SET created_unix = ?, is_closed = ?, closed_unix = ? WHERE id = 15", int64(now)-2*day, true, int64(now)-day)
+	assert.NoError(t, err)
+
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 6})
+	milestone.CreatedUnix = now
+	points, err := issues_model.GetMilestoneBurndown(db.DefaultContext, milestone, []int64{3, 5})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 4) {
+		assert.EqualValues(t, 1, points[0].NumIssues)
+		assert.EqualValues(t, 1, points[0].NumOpen)
+		assert.EqualValues(t, 2, points[1].NumIssues)
+		assert.EqualValues(t, 2, points[1].NumOpen)
+		assert.EqualValues(t, 2, points[2].NumIssues)
+		assert.EqualValues(t, 1, points[2].NumOpen)
+		assert.EqualValues(t, 1, points[3].NumOpen)
+	}
+}
+
+func TestRemoveOrgMilestonesFromRepo(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assignIssuesToMilestone(t, 6, 6, 15)
+	assert.NoError(t, issues_model.RemoveOrgMilestonesFromRepo(db.DefaultContext, 3))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6}, "milestone_id=0")
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 15, MilestoneID: 6})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 6, NumIssues: 1})
+	unittest.CheckConsistencyFor(t, &issues_model.Milestone{})
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrSavedSearchNotExist represents a "SavedSearchNotExist" kind of error.
+type ErrSavedSearchNotExist struct {
+	ID int64
+}
+
+// IsErrSavedSearchNotExist checks if an error is a ErrSavedSearchNotExist.
+func IsErrSavedSearchNotExist(err error) bool {
+	_, ok := err.(ErrSavedSearchNotExist)
+	return ok
+}
+
+func (err ErrSavedSearchNotExist) Error() string {
+	return fmt.Sprintf("saved search does not exist [id: %d]", err.ID)
+}
+
+func (err ErrSavedSearchNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrSavedSearchAlreadyExist represents a "SavedSearchAlreadyExist" kind of error.
+type ErrSavedSearchAlreadyExist struct {
+	OwnerID int64
+	Name    string
+}
+
+// IsErrSavedSearchAlreadyExist checks if an error is a ErrSavedSearchAlreadyExist.
+func IsErrSavedSearchAlreadyExist(err error) bool {
+	_, ok := err.(ErrSavedSearchAlreadyExist)
+	return ok
+}
+
+func (err ErrSavedSearchAlreadyExist) Error() string {
+	return fmt.Sprintf("saved search already exists [owner_id: %d, name: %s]", err.OwnerID, err.Name)
+}
+
+func (err ErrSavedSearchAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// savedSearchQueryKeys are the query parameters of the issues overview kept by a saved search
+var savedSearchQueryKeys = []string{"q", "type", "state", "sort", "labels", "milestone", "repos"}
+
+// SavedSearch is a named search of the issues or pull requests of the repositories of a user or an organization.
+// The searches of a user are only visible to them and the searches of an organization to its members, unless they
+// are public. The filters relative to the viewer, like "assigned to me", apply to whoever runs the search.
+type SavedSearch struct {
+	ID        int64  `xorm:"pk autoincr"`
+	OwnerID   int64  `xorm:"UNIQUE(s) NOT NULL"`
+	CreatorID int64  `xorm:"NOT NULL"`
+	Name      string `xorm:"UNIQUE(s) NOT NULL"`
+	IsPull    bool   `xorm:"NOT NULL DEFAULT false"`
+	// Query is the url query of the issues overview, see SavedSearch.Values
+	Query       string `xorm:"TEXT"`
+	IsPublic    bool   `xorm:"NOT NULL DEFAULT false"`
+	OnDashboard bool   `xorm:"INDEX NOT NULL DEFAULT false"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(SavedSearch))
+}
+
+// Values returns the parameters of the query of the saved search
+func (s *SavedSearch) Values() url.Values {
+	values, _ := url.ParseQuery(s.Query)
+	return values
+}
+
+// Keyword returns the keyword of the saved search
+func (s *SavedSearch) Keyword() string {
+	return s.Values().Get("q")
+}
+
+// Link returns the relative link to the results of the saved search
+func (s *SavedSearch) Link() string {
+	return fmt.Sprintf("%s/searches/%d", setting.AppSubURL, s.ID)
+}
+
+// HTMLURL returns the absolute link to the results of the saved search
+func (s *SavedSearch) HTMLURL() string {
+	return fmt.Sprintf("%ssearches/%d", setting.AppURL, s.ID)
+}
+
+// CleanSavedSearchQuery keeps only the parameters of a query of the issues overview which filter or sort the issues,
+// the paging is dropped
+func CleanSavedSearchQuery(values url.Values) string {
+	query := make(url.Values, len(savedSearchQueryKeys))
+	for _, key := range savedSearchQueryKeys {
+		if value := strings.TrimSpace(values.Get(key)); value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query.Encode()
+}
+
+// CreateSavedSearch creates a saved search
+func CreateSavedSearch(ctx context.Context, s *SavedSearch) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return util.NewInvalidArgumentErrorf("the name of a saved search can't be empty")
+	}
+
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if has, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ?", s.OwnerID, s.Name).Exist(new(SavedSearch)); err != nil {
+		return err
+	} else if has {
+		return ErrSavedSearchAlreadyExist{OwnerID: s.OwnerID, Name: s.Name}
+	}
+	if err := db.Insert(ctx, s); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// UpdateSavedSearch updates the name, the query and the sharing of a saved search
+func UpdateSavedSearch(ctx context.Context, s *SavedSearch) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return util.NewInvalidArgumentErrorf("the name of a saved search can't be empty")
+	}
+
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if has, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ? AND id <> ?", s.OwnerID, s.Name, s.ID).Exist(new(SavedSearch)); err != nil {
+		return err
+	} else if has {
+		return ErrSavedSearchAlreadyExist{OwnerID: s.OwnerID, Name: s.Name}
+	}
+	if _, err := db.GetEngine(ctx).ID(s.ID).Cols("name", "query", "is_public", "on_dashboard").Update(s); err != nil {
+		return err
+	}
+	return committer.Commit()
+}
+
+// DeleteSavedSearch deletes a saved search of an owner
+func DeleteSavedSearch(ctx context.Context, ownerID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(new(SavedSearch))
+	return err
+}
+
+// DeleteSavedSearchesByOwnerID deletes all the saved searches of a user or an organization
+func DeleteSavedSearchesByOwnerID(ctx context.Context, ownerID int64) error {
+	_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(new(SavedSearch))
+	return err
+}
+
+// GetSavedSearchByID returns a saved search by its id
+func GetSavedSearchByID(ctx context.Context, id int64) (*SavedSearch, error) {
+	s := new(SavedSearch)
+	has, err := db.GetEngine(ctx).ID(id).Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSavedSearchNotExist{ID: id}
+	}
+	return s, nil
+}
+
+// GetSavedSearchByOwner returns a saved search of a user or an organization by its id
+func GetSavedSearchByOwner(ctx context.Context, ownerID, id int64) (*SavedSearch, error) {
+	s := new(SavedSearch)
+	has, err := db.GetEngine(ctx).ID(id).Where("owner_id = ?", ownerID).Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSavedSearchNotExist{ID: id}
+	}
+	return s, nil
+}
+
+// FindSavedSearchesOptions represents the options to find the saved searches of a user or an organization
+type FindSavedSearchesOptions struct {
+	OwnerID     int64
+	IsPull      util.OptionalBool
+	OnDashboard util.OptionalBool
+}
+
+// FindSavedSearches returns the saved searches of a user or an organization ordered by name
+func FindSavedSearches(ctx context.Context, opts FindSavedSearchesOptions) ([]*SavedSearch, error) {
+	sess := db.GetEngine(ctx).Where("owner_id = ?", opts.OwnerID)
+	if !opts.IsPull.IsNone() {
+		sess.And("is_pull = ?", opts.IsPull.IsTrue())
+	}
+	if !opts.OnDashboard.IsNone() {
+		sess.And("on_dashboard = ?", opts.OnDashboard.IsTrue())
+	}
+	searches := make([]*SavedSearch, 0, 10)
+	return searches, sess.Asc("name").Find(&searches)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanSavedSearchQuery(t *testing.T) {
+	values, err := url.ParseQuery("q=+crash+&type=assigned&page=3&state=closed&repos=[1,2]&foo=bar")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "q=crash&repos=%5B1%2C2%5D&state=closed&type=assigned", issues_model.CleanSavedSearchQuery(values))
+}
+
+func TestSavedSearches(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s, err := issues_model.GetSavedSearchByID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "My open issues", s.Name)
+	assert.EqualValues(t, "", s.Keyword())
+	assert.EqualValues(t, "assigned", s.Values().Get("type"))
+
+	_, err = issues_model.GetSavedSearchByOwner(db.DefaultContext, 3, 1)
+	assert.True(t, issues_model.IsErrSavedSearchNotExist(err))
+
+	searches, err := issues_model.FindSavedSearches(db.DefaultContext, issues_model.FindSavedSearchesOptions{OwnerID: 2, OnDashboard: util.OptionalBoolTrue})
+	assert.NoError(t, err)
+	assert.Len(t, searches, 1)
+
+	err = issues_model.CreateSavedSearch(db.DefaultContext, &issues_model.SavedSearch{OwnerID: 2, CreatorID: 2, Name: " My open issues "})
+	assert.True(t, issues_model.IsErrSavedSearchAlreadyExist(err))
+
+	s2 := &issues_model.SavedSearch{OwnerID: 2, CreatorID: 2, Name: "Bugs", Query: "q=bug"}
+	assert.NoError(t, issues_model.CreateSavedSearch(db.DefaultContext, s2))
+	searches, err = issues_model.FindSavedSearches(db.DefaultContext, issues_model.FindSavedSearchesOptions{OwnerID: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, searches, 2) {
+		assert.EqualValues(t, "Bugs", searches[0].Name)
+	}
+
+	s2.Name = "My open issues"
+	assert.True(t, issues_model.IsErrSavedSearchAlreadyExist(issues_model.UpdateSavedSearch(db.DefaultContext, s2)))
+	s2.Name = "Open bugs"
+	s2.IsPublic = true
+	assert.NoError(t, issues_model.UpdateSavedSearch(db.DefaultContext, s2))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.SavedSearch{ID: s2.ID, Name: "Open bugs", IsPublic: true})
+
+	assert.NoError(t, issues_model.DeleteSavedSearch(db.DefaultContext, 3, s2.ID))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.SavedSearch{ID: s2.ID})
+	assert.NoError(t, issues_model.DeleteSavedSearch(db.DefaultContext, 2, s2.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.SavedSearch{ID: s2.ID})
+}
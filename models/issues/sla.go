@@ -0,0 +1,447 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrSLAPolicyNotExist represents a "SLAPolicyNotExist" kind of error.
+type ErrSLAPolicyNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrSLAPolicyNotExist checks if an error is a ErrSLAPolicyNotExist.
+func IsErrSLAPolicyNotExist(err error) bool {
+	_, ok := err.(ErrSLAPolicyNotExist)
+	return ok
+}
+
+func (err ErrSLAPolicyNotExist) Error() string {
+	return fmt.Sprintf("sla policy does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+func (err ErrSLAPolicyNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// SLAPolicy is a response time policy of the issues of a repository: the issues must get a first response from
+// someone who can write to them and be closed within the durations of the policy. A policy with a label only
+// applies to the issues with this label, and is preferred to the policies without a label.
+type SLAPolicy struct {
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"INDEX NOT NULL"`
+	Name   string `xorm:"NOT NULL"`
+	// LabelID is the label of the issues the policy applies to, zero for all the issues of the repository
+	LabelID int64 `xorm:"NOT NULL DEFAULT 0"`
+	// FirstResponseSeconds and ResolveSeconds are the durations since the creation of the issues, zero for no limit
+	FirstResponseSeconds int64 `xorm:"NOT NULL DEFAULT 0"`
+	ResolveSeconds       int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	// The users notified when the policy is breached
+	NotifyAssignees bool               `xorm:"NOT NULL DEFAULT true"`
+	EscalateUserIDs []int64            `xorm:"JSON TEXT"`
+	EscalateTeamIDs []int64            `xorm:"JSON TEXT"`
+	Label           *Label             `xorm:"-"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+}
+
+// SLAState represents the state of the SLA of an issue
+type SLAState int
+
+const (
+	// SLAStateNone means the issue isn't tracked by a SLA policy
+	SLAStateNone SLAState = iota
+	// SLAStateRunning means the issue is waiting for a response or a resolution within the deadlines
+	SLAStateRunning
+	// SLAStateMet means the issue got its response and resolution within the deadlines
+	SLAStateMet
+	// SLAStateBreached means a deadline of the issue has been missed
+	SLAStateBreached
+)
+
+// IsRunning checks if the state is SLAStateRunning
+func (s SLAState) IsRunning() bool {
+	return s == SLAStateRunning
+}
+
+// IsMet checks if the state is SLAStateMet
+func (s SLAState) IsMet() bool {
+	return s == SLAStateMet
+}
+
+// IsBreached checks if the state is SLAStateBreached
+func (s SLAState) IsBreached() bool {
+	return s == SLAStateBreached
+}
+
+var slaStateNames = map[string]SLAState{
+	"running":  SLAStateRunning,
+	"met":      SLAStateMet,
+	"breached": SLAStateBreached,
+}
+
+// IssueSLA tracks the deadlines of an issue given by a SLA policy
+type IssueSLA struct {
+	ID       int64    `xorm:"pk autoincr"`
+	IssueID  int64    `xorm:"UNIQUE NOT NULL"`
+	RepoID   int64    `xorm:"INDEX NOT NULL"`
+	PolicyID int64    `xorm:"INDEX NOT NULL"`
+	State    SLAState `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+	FirstResponseDeadline  timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	FirstResponseUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	FirstResponseEscalated bool               `xorm:"NOT NULL DEFAULT false"`
+	ResolveDeadline        timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	ResolvedUnix           timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	ResolveEscalated       bool               `xorm:"NOT NULL DEFAULT false"`
+
+	Policy *SLAPolicy `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(SLAPolicy))
+	db.RegisterModel(new(IssueSLA))
+}
+
+// FirstResponseDuration returns the time allowed for the first response, zero for no limit
+func (p *SLAPolicy) FirstResponseDuration() time.Duration {
+	return time.Duration(p.FirstResponseSeconds) * time.Second
+}
+
+// ResolveDuration returns the time allowed to close the issues, zero for no limit
+func (p *SLAPolicy) ResolveDuration() time.Duration {
+	return time.Duration(p.ResolveSeconds) * time.Second
+}
+
+// LoadLabel loads the label of the policy, if any
+func (p *SLAPolicy) LoadLabel(ctx context.Context) (err error) {
+	if p.LabelID == 0 || p.Label != nil {
+		return nil
+	}
+	p.Label, err = GetLabelInRepoByID(ctx, p.RepoID, p.LabelID)
+	if IsErrRepoLabelNotExist(err) {
+		// an organization label
+		p.Label, err = GetLabelByID(ctx, p.LabelID)
+	}
+	return err
+}
+
+// AppliesTo checks if the policy applies to an issue with the given labels
+func (p *SLAPolicy) AppliesTo(labelIDs []int64) bool {
+	if p.LabelID == 0 {
+		return true
+	}
+	for _, id := range labelIDs {
+		if id == p.LabelID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchSLAPolicy returns the policy applying to an issue with the given labels or nil: the oldest of the
+// policies with one of the labels, or else the oldest of the policies without a label
+func MatchSLAPolicy(policies []*SLAPolicy, labelIDs []int64) *SLAPolicy {
+	var matched *SLAPolicy
+	for _, p := range policies {
+		if !p.AppliesTo(labelIDs) {
+			continue
+		}
+		if matched == nil || (matched.LabelID == 0 && p.LabelID != 0) ||
+			((matched.LabelID == 0) == (p.LabelID == 0) && p.ID < matched.ID) {
+			matched = p
+		}
+	}
+	return matched
+}
+
+// ParseSLADuration parses a duration of a SLA policy like "30m", "4h" or "2d12h" to seconds, empty for no limit
+func ParseSLADuration(s string) (int64, error) {
+	duration := strings.TrimSpace(s)
+	if duration == "" {
+		return 0, nil
+	}
+	var days int64
+	if before, after, ok := strings.Cut(duration, "d"); ok {
+		d, err := strconv.ParseInt(before, 10, 64)
+		if err != nil || d < 0 {
+			return 0, util.NewInvalidArgumentErrorf("invalid duration %q", s)
+		}
+		days, duration = d, after
+	}
+	var rest time.Duration
+	if duration != "" {
+		var err error
+		if rest, err = time.ParseDuration(duration); err != nil || rest < 0 {
+			return 0, util.NewInvalidArgumentErrorf("invalid duration %q", s)
+		}
+	}
+	return days*24*3600 + int64(rest/time.Second), nil
+}
+
+// FormatSLADuration formats a duration of a SLA policy in seconds the way ParseSLADuration parses it
+func FormatSLADuration(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, unit := range []struct {
+		suffix  string
+		seconds int64
+	}{{"d", 24 * 3600}, {"h", 3600}, {"m", 60}, {"s", 1}} {
+		if n := seconds / unit.seconds; n > 0 {
+			sb.WriteString(strconv.FormatInt(n, 10))
+			sb.WriteString(unit.suffix)
+			seconds -= n * unit.seconds
+		}
+	}
+	return sb.String()
+}
+
+// CreateSLAPolicy creates a SLA policy
+func CreateSLAPolicy(ctx context.Context, p *SLAPolicy) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return util.NewInvalidArgumentErrorf("the name of a sla policy can't be empty")
+	}
+	if p.FirstResponseSeconds < 0 || p.ResolveSeconds < 0 || (p.FirstResponseSeconds == 0 && p.ResolveSeconds == 0) {
+		return util.NewInvalidArgumentErrorf("a sla policy needs a first response or a resolution time")
+	}
+	return db.Insert(ctx, p)
+}
+
+// UpdateSLAPolicy updates a SLA policy
+func UpdateSLAPolicy(ctx context.Context, p *SLAPolicy) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return util.NewInvalidArgumentErrorf("the name of a sla policy can't be empty")
+	}
+	if p.FirstResponseSeconds < 0 || p.ResolveSeconds < 0 || (p.FirstResponseSeconds == 0 && p.ResolveSeconds == 0) {
+		return util.NewInvalidArgumentErrorf("a sla policy needs a first response or a resolution time")
+	}
+	_, err := db.GetEngine(ctx).ID(p.ID).Cols("name", "label_id", "first_response_seconds", "resolve_seconds",
+		"notify_assignees", "escalate_user_ids", "escalate_team_ids").Update(p)
+	return err
+}
+
+// DeleteSLAPolicy deletes a SLA policy of a repository, the SLAs of the issues tracked by it are kept
+func DeleteSLAPolicy(ctx context.Context, repoID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(SLAPolicy))
+	return err
+}
+
+// GetSLAPolicyByID returns a SLA policy of a repository
+func GetSLAPolicyByID(ctx context.Context, repoID, id int64) (*SLAPolicy, error) {
+	p := new(SLAPolicy)
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSLAPolicyNotExist{ID: id, RepoID: repoID}
+	}
+	return p, nil
+}
+
+// GetSLAPoliciesByRepoID returns the SLA policies of a repository
+func GetSLAPoliciesByRepoID(ctx context.Context, repoID int64) ([]*SLAPolicy, error) {
+	policies := make([]*SLAPolicy, 0, 5)
+	return policies, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("id").Find(&policies)
+}
+
+// IsFirstResponseBreached checks if the first response deadline has been missed
+func (s *IssueSLA) IsFirstResponseBreached(now timeutil.TimeStamp) bool {
+	return deadlineState(s.FirstResponseDeadline, s.FirstResponseUnix, now) == SLAStateBreached
+}
+
+// IsResolveBreached checks if the resolution deadline has been missed
+func (s *IssueSLA) IsResolveBreached(now timeutil.TimeStamp) bool {
+	return deadlineState(s.ResolveDeadline, s.ResolvedUnix, now) == SLAStateBreached
+}
+
+// deadlineState returns the state of a deadline done at a time, zero if not done yet
+func deadlineState(deadline, done, now timeutil.TimeStamp) SLAState {
+	switch {
+	case deadline == 0:
+		return SLAStateNone
+	case done > 0 && done <= deadline:
+		return SLAStateMet
+	case done > 0 || now > deadline:
+		return SLAStateBreached
+	default:
+		return SLAStateRunning
+	}
+}
+
+// FirstResponseState returns the current state of the first response deadline
+func (s *IssueSLA) FirstResponseState() SLAState {
+	return deadlineState(s.FirstResponseDeadline, s.FirstResponseUnix, timeutil.TimeStampNow())
+}
+
+// ResolveState returns the current state of the resolution deadline
+func (s *IssueSLA) ResolveState() SLAState {
+	return deadlineState(s.ResolveDeadline, s.ResolvedUnix, timeutil.TimeStampNow())
+}
+
+// IsPendingFirstResponse checks if the issue is waiting for its first response
+func (s *IssueSLA) IsPendingFirstResponse() bool {
+	return s.FirstResponseDeadline > 0 && s.FirstResponseUnix == 0
+}
+
+// IsPendingResolve checks if the issue is waiting for its resolution
+func (s *IssueSLA) IsPendingResolve() bool {
+	return s.ResolveDeadline > 0 && s.ResolvedUnix == 0
+}
+
+// NextDeadline returns the next deadline the issue is waiting for, zero if none
+func (s *IssueSLA) NextDeadline() timeutil.TimeStamp {
+	if s.IsPendingFirstResponse() {
+		return s.FirstResponseDeadline
+	}
+	if s.IsPendingResolve() {
+		return s.ResolveDeadline
+	}
+	return 0
+}
+
+// IsOverdue checks if the issue is waiting for a deadline which is already past, the state is updated by the
+// escalation so it may not be breached yet
+func (s *IssueSLA) IsOverdue() bool {
+	next := s.NextDeadline()
+	return next > 0 && next < timeutil.TimeStampNow()
+}
+
+// UpdateState computes the state of the SLA at a time
+func (s *IssueSLA) UpdateState(now timeutil.TimeStamp) {
+	switch {
+	case s.IsFirstResponseBreached(now) || s.IsResolveBreached(now):
+		s.State = SLAStateBreached
+	case s.IsPendingFirstResponse() || s.IsPendingResolve():
+		s.State = SLAStateRunning
+	default:
+		s.State = SLAStateMet
+	}
+}
+
+// ApplyPolicy computes the deadlines of an issue created at a time with a policy, a deadline which changes can
+// be escalated again
+func (s *IssueSLA) ApplyPolicy(p *SLAPolicy, createdUnix timeutil.TimeStamp) {
+	s.PolicyID = p.ID
+	s.Policy = p
+
+	var firstResponseDeadline, resolveDeadline timeutil.TimeStamp
+	if p.FirstResponseSeconds > 0 {
+		firstResponseDeadline = createdUnix.Add(p.FirstResponseSeconds)
+	}
+	if p.ResolveSeconds > 0 {
+		resolveDeadline = createdUnix.Add(p.ResolveSeconds)
+	}
+	if firstResponseDeadline != s.FirstResponseDeadline {
+		s.FirstResponseDeadline = firstResponseDeadline
+		s.FirstResponseEscalated = false
+	}
+	if resolveDeadline != s.ResolveDeadline {
+		s.ResolveDeadline = resolveDeadline
+		s.ResolveEscalated = false
+	}
+}
+
+// LoadPolicy loads the policy of the SLA
+func (s *IssueSLA) LoadPolicy(ctx context.Context) (err error) {
+	if s.Policy == nil {
+		s.Policy, err = GetSLAPolicyByID(ctx, s.RepoID, s.PolicyID)
+	}
+	return err
+}
+
+// GetIssueSLA returns the SLA of an issue, nil if the issue isn't tracked
+func GetIssueSLA(ctx context.Context, issueID int64) (*IssueSLA, error) {
+	s := new(IssueSLA)
+	has, err := db.GetEngine(ctx).Where("issue_id = ?", issueID).Get(s)
+	if err != nil || !has {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveIssueSLA inserts or updates the SLA of an issue
+func SaveIssueSLA(ctx context.Context, s *IssueSLA) error {
+	if s.ID == 0 {
+		return db.Insert(ctx, s)
+	}
+	_, err := db.GetEngine(ctx).ID(s.ID).AllCols().Update(s)
+	return err
+}
+
+// DeleteIssueSLA stops tracking the SLA of an issue
+func DeleteIssueSLA(ctx context.Context, issueID int64) error {
+	_, err := db.GetEngine(ctx).Where("issue_id = ?", issueID).Delete(new(IssueSLA))
+	return err
+}
+
+// FindOverdueIssueSLAs returns the SLAs of the issues which missed a deadline and haven't been escalated yet
+func FindOverdueIssueSLAs(ctx context.Context, now timeutil.TimeStamp, limit int) ([]*IssueSLA, error) {
+	cond := builder.Or(
+		builder.Eq{"first_response_unix": 0, "first_response_escalated": false}.
+			And(builder.Gt{"first_response_deadline": 0}, builder.Lt{"first_response_deadline": now}),
+		builder.Eq{"resolved_unix": 0, "resolve_escalated": false}.
+			And(builder.Gt{"resolve_deadline": 0}, builder.Lt{"resolve_deadline": now}),
+	)
+	slas := make([]*IssueSLA, 0, limit)
+	return slas, db.GetEngine(ctx).Where(cond).Asc("id").Limit(limit).Find(&slas)
+}
+
+// LoadSLAs loads the SLAs of the issues, the issues which aren't tracked get none
+func (issues IssueList) LoadSLAs(ctx context.Context) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	slas := make(map[int64]*IssueSLA, len(issues))
+	if err := db.GetEngine(ctx).In("issue_id", issues.getIssueIDs()).Find(&slas); err != nil {
+		return err
+	}
+	byIssueID := make(map[int64]*IssueSLA, len(slas))
+	for _, s := range slas {
+		byIssueID[s.IssueID] = s
+	}
+	for _, issue := range issues {
+		issue.SLA = byIssueID[issue.ID]
+	}
+	return nil
+}
+
+// ParseSLAKeyword extracts the SLA filter of a search keyword: `sla:running`, `sla:met` or `sla:breached` filter
+// the issues by the state of their SLA and `sla:none` the issues which aren't tracked. It returns the keyword
+// without the filter and the state for IssuesOptions.SLAState, an unknown state is kept in the keyword.
+func ParseSLAKeyword(keyword string) (string, int64) {
+	fields := strings.Fields(keyword)
+	for i, field := range fields {
+		name, ok := strings.CutPrefix(field, "sla:")
+		if !ok {
+			continue
+		}
+		var state int64
+		if name == "none" {
+			state = db.NoConditionID
+		} else if s, ok := slaStateNames[strings.ToLower(name)]; ok {
+			state = int64(s)
+		} else {
+			continue
+		}
+		return strings.Join(append(fields[:i:i], fields[i+1:]...), " "), state
+	}
+	return keyword, 0
+}
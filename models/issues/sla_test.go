@@ -0,0 +1,151 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSLADuration(t *testing.T) {
+	for input, expected := range map[string]int64{
+		"":       0,
+		"30m":    1800,
+		"4h":     4 * 3600,
+		"2d":     2 * 24 * 3600,
+		"2d12h":  2*24*3600 + 12*3600,
+		" 1h30m": 5400,
+	} {
+		seconds, err := issues_model.ParseSLADuration(input)
+		assert.NoError(t, err, input)
+		assert.EqualValues(t, expected, seconds, input)
+		if seconds > 0 {
+			back, err := issues_model.ParseSLADuration(issues_model.FormatSLADuration(seconds))
+			assert.NoError(t, err)
+			assert.EqualValues(t, seconds, back)
+		}
+	}
+	assert.Equal(t, "2d12h", issues_model.FormatSLADuration(2*24*3600+12*3600))
+
+	for _, input := range []string{"d", "2x", "-1h", "1d-2h"} {
+		_, err := issues_model.ParseSLADuration(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestMatchSLAPolicy(t *testing.T) {
+	all := &issues_model.SLAPolicy{ID: 1}
+	bug := &issues_model.SLAPolicy{ID: 2, LabelID: 10}
+	urgent := &issues_model.SLAPolicy{ID: 3, LabelID: 20}
+	policies := []*issues_model.SLAPolicy{urgent, bug, all}
+
+	assert.Equal(t, all, issues_model.MatchSLAPolicy(policies, nil))
+	assert.Equal(t, bug, issues_model.MatchSLAPolicy(policies, []int64{10}))
+	assert.Equal(t, bug, issues_model.MatchSLAPolicy(policies, []int64{20, 10}))
+	assert.Equal(t, urgent, issues_model.MatchSLAPolicy(policies, []int64{20, 30}))
+	assert.Nil(t, issues_model.MatchSLAPolicy([]*issues_model.SLAPolicy{bug}, []int64{30}))
+}
+
+func TestParseSLAKeyword(t *testing.T) {
+	keyword, state := issues_model.ParseSLAKeyword("crash sla:breached")
+	assert.Equal(t, "crash", keyword)
+	assert.EqualValues(t, issues_model.SLAStateBreached, state)
+
+	keyword, state = issues_model.ParseSLAKeyword("sla:none")
+	assert.Equal(t, "", keyword)
+	assert.EqualValues(t, db.NoConditionID, state)
+
+	keyword, state = issues_model.ParseSLAKeyword("sla:unknown crash")
+	assert.Equal(t, "sla:unknown crash", keyword)
+	assert.EqualValues(t, 0, state)
+}
+
+func TestIssueSLAState(t *testing.T) {
+	s := &issues_model.IssueSLA{}
+	s.ApplyPolicy(&issues_model.SLAPolicy{ID: 1, FirstResponseSeconds: 100, ResolveSeconds: 1000}, 1000)
+	assert.EqualValues(t, 1100, s.FirstResponseDeadline)
+	assert.EqualValues(t, 2000, s.ResolveDeadline)
+	assert.EqualValues(t, 1100, s.NextDeadline())
+
+	s.UpdateState(1050)
+	assert.Equal(t, issues_model.SLAStateRunning, s.State)
+	s.UpdateState(1200)
+	assert.Equal(t, issues_model.SLAStateBreached, s.State)
+
+	s.FirstResponseUnix = 1090
+	s.UpdateState(1200)
+	assert.Equal(t, issues_model.SLAStateRunning, s.State)
+	assert.EqualValues(t, 2000, s.NextDeadline())
+
+	s.ResolvedUnix = 1500
+	s.UpdateState(3000)
+	assert.Equal(t, issues_model.SLAStateMet, s.State)
+	assert.EqualValues(t, 0, s.NextDeadline())
+
+	// a deadline which changes can be escalated again
+	s.ResolveEscalated = true
+	s.ApplyPolicy(&issues_model.SLAPolicy{ID: 2, FirstResponseSeconds: 100, ResolveSeconds: 400}, 1000)
+	assert.False(t, s.ResolveEscalated)
+	s.UpdateState(3000)
+	assert.Equal(t, issues_model.SLAStateBreached, s.State)
+}
+
+func TestIssueSLAs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	policies, err := issues_model.GetSLAPoliciesByRepoID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+
+	_, err = issues_model.GetSLAPolicyByID(db.DefaultContext, 2, 1)
+	assert.True(t, issues_model.IsErrSLAPolicyNotExist(err))
+
+	s, err := issues_model.GetIssueSLA(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	s = &issues_model.IssueSLA{IssueID: 1, RepoID: 1}
+	s.ApplyPolicy(policies[0], 946684800)
+	s.UpdateState(timeutil.TimeStampNow())
+	assert.NoError(t, issues_model.SaveIssueSLA(db.DefaultContext, s))
+
+	overdue, err := issues_model.FindOverdueIssueSLAs(db.DefaultContext, timeutil.TimeStampNow(), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, overdue, 1) {
+		assert.EqualValues(t, 1, overdue[0].IssueID)
+	}
+
+	s.FirstResponseEscalated = true
+	s.ResolveEscalated = true
+	assert.NoError(t, issues_model.SaveIssueSLA(db.DefaultContext, s))
+	overdue, err = issues_model.FindOverdueIssueSLAs(db.DefaultContext, timeutil.TimeStampNow(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, overdue)
+
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5}),
+	}
+	assert.NoError(t, issues.LoadSLAs(db.DefaultContext))
+	if assert.NotNil(t, issues[0].SLA) {
+		assert.True(t, issues[0].SLA.State.IsBreached())
+	}
+	assert.Nil(t, issues[1].SLA)
+
+	count, err := issues_model.CountIssues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoIDs:  []int64{1},
+		SLAState: int64(issues_model.SLAStateBreached),
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	assert.NoError(t, issues_model.DeleteIssueSLA(db.DefaultContext, 1))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueSLA{IssueID: 1})
+}
@@ -0,0 +1,390 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrSubIssueExists represents a "SubIssueExists" kind of error, an issue can only have one parent.
+type ErrSubIssueExists struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueExists checks if an error is a ErrSubIssueExists.
+func IsErrSubIssueExists(err error) bool {
+	_, ok := err.(ErrSubIssueExists)
+	return ok
+}
+
+func (err ErrSubIssueExists) Error() string {
+	return fmt.Sprintf("issue has already a parent [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+func (err ErrSubIssueExists) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// ErrSubIssueNotExist represents a "SubIssueNotExist" kind of error.
+type ErrSubIssueNotExist struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueNotExist checks if an error is a ErrSubIssueNotExist.
+func IsErrSubIssueNotExist(err error) bool {
+	_, ok := err.(ErrSubIssueNotExist)
+	return ok
+}
+
+func (err ErrSubIssueNotExist) Error() string {
+	return fmt.Sprintf("issue is not a sub-issue [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+func (err ErrSubIssueNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrCircularSubIssue represents a "CircularSubIssue" kind of error, the parent is the issue itself or one of its sub-issues.
+type ErrCircularSubIssue struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrCircularSubIssue checks if an error is a ErrCircularSubIssue.
+func IsErrCircularSubIssue(err error) bool {
+	_, ok := err.(ErrCircularSubIssue)
+	return ok
+}
+
+func (err ErrCircularSubIssue) Error() string {
+	return fmt.Sprintf("circular sub-issues (the parent is a sub-issue of the issue) [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+func (err ErrCircularSubIssue) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// ErrSubIssueNotSameOwner represents a "SubIssueNotSameOwner" kind of error,
+// the sub-issues must belong to a repository of the owner of the repository of their parent.
+type ErrSubIssueNotSameOwner struct {
+	IssueID  int64
+	ParentID int64
+}
+
+// IsErrSubIssueNotSameOwner checks if an error is a ErrSubIssueNotSameOwner.
+func IsErrSubIssueNotSameOwner(err error) bool {
+	_, ok := err.(ErrSubIssueNotSameOwner)
+	return ok
+}
+
+func (err ErrSubIssueNotSameOwner) Error() string {
+	return fmt.Sprintf("sub-issue and parent belong to repositories of different owners [issue id: %d, parent id: %d]", err.IssueID, err.ParentID)
+}
+
+func (err ErrSubIssueNotSameOwner) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// IssueParent represents the parent of a sub-issue, the parent and the sub-issue are issues (not pull requests)
+// of repositories of the same owner
+type IssueParent struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL"`
+	IssueID     int64              `xorm:"UNIQUE NOT NULL"`
+	ParentID    int64              `xorm:"INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueParent))
+}
+
+// GetParentIssueID returns the id of the parent of an issue, zero if it is not a sub-issue
+func GetParentIssueID(ctx context.Context, issueID int64) (int64, error) {
+	var parentID int64
+	_, err := db.GetEngine(ctx).Table("issue_parent").Where("issue_id = ?", issueID).Cols("parent_id").Get(&parentID)
+	return parentID, err
+}
+
+// GetParentIssue returns the parent of an issue, nil if it is not a sub-issue
+func GetParentIssue(ctx context.Context, issueID int64) (*Issue, error) {
+	parentID, err := GetParentIssueID(ctx, issueID)
+	if err != nil || parentID == 0 {
+		return nil, err
+	}
+	return GetIssueByID(ctx, parentID)
+}
+
+// GetSubIssues returns the sub-issues of an issue, in the order they were added
+func GetSubIssues(ctx context.Context, parentID int64) (IssueList, error) {
+	issues := make(IssueList, 0, 10)
+	return issues, db.GetEngine(ctx).
+		Join("INNER", "issue_parent", "issue_parent.issue_id = issue.id").
+		Where("issue_parent.parent_id = ?", parentID).
+		OrderBy("issue_parent.id").
+		Find(&issues)
+}
+
+// SubIssueProgress is the progress of an issue computed from the state of its sub-issues, and of theirs
+type SubIssueProgress struct {
+	Total  int64
+	Closed int64
+}
+
+// Completeness returns the percentage of the closed sub-issues
+func (p *SubIssueProgress) Completeness() int64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Closed * 100 / p.Total
+}
+
+// subIssueNode is an issue of a sub-issue hierarchy
+type subIssueNode struct {
+	ID       int64
+	RepoID   int64
+	IsClosed bool
+}
+
+// walkSubIssues calls fn for every sub-issue of the hierarchy of an issue matching cond,
+// the sub-issues of an issue not matching cond are not walked
+func walkSubIssues(ctx context.Context, issueID int64, cond builder.Cond, fn func(*subIssueNode)) error {
+	visited := make(container.Set[int64])
+	visited.Add(issueID)
+	parentIDs := []int64{issueID}
+	for len(parentIDs) > 0 {
+		children := make([]*subIssueNode, 0, len(parentIDs))
+		if err := db.GetEngine(ctx).Table("issue").
+			Join("INNER", "issue_parent", "issue_parent.issue_id = issue.id").
+			In("issue_parent.parent_id", parentIDs).
+			And(cond).
+			Cols("issue.id", "issue.repo_id", "issue.is_closed").
+			Find(&children); err != nil {
+			return err
+		}
+
+		parentIDs = parentIDs[:0]
+		for _, child := range children {
+			if !visited.Add(child.ID) {
+				continue
+			}
+			fn(child)
+			parentIDs = append(parentIDs, child.ID)
+		}
+	}
+	return nil
+}
+
+// GetSubIssueRepoIDs returns the ids of the repositories of all the sub-issues of the hierarchy of an issue
+func GetSubIssueRepoIDs(ctx context.Context, issueID int64) ([]int64, error) {
+	repoIDs := make(container.Set[int64])
+	if err := walkSubIssues(ctx, issueID, builder.NewCond(), func(child *subIssueNode) {
+		repoIDs.Add(child.RepoID)
+	}); err != nil {
+		return nil, err
+	}
+	return repoIDs.Values(), nil
+}
+
+// GetSubIssueProgress returns the progress of an issue, the sub-issues of the hierarchy in the readable repositories
+// are counted. A sub-issue in another repository is skipped with its own sub-issues, like in the list of sub-issues.
+func GetSubIssueProgress(ctx context.Context, issueID int64, readableRepoIDs []int64) (*SubIssueProgress, error) {
+	progress := &SubIssueProgress{}
+	if len(readableRepoIDs) == 0 {
+		return progress, nil
+	}
+	if err := walkSubIssues(ctx, issueID, builder.In("issue.repo_id", readableRepoIDs), func(child *subIssueNode) {
+		progress.Total++
+		if child.IsClosed {
+			progress.Closed++
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+var parentKeywordPattern = regexp.MustCompile(`^parent:(?:([\w.-]+)/([\w.-]+))?#?(\d+)$`)
+
+// ParseParentKeyword extracts the parent filter of a search keyword: `parent:12` or `parent:#12` filters the
+// sub-issues of an issue of the repository, `parent:owner/repo#12` the ones of an issue of another repository
+// and `parent:none` the issues without a parent. It returns the keyword without the filter and the parent id
+// for IssuesOptions.ParentID, a filter whose issue doesn't exist is kept in the keyword.
+func ParseParentKeyword(ctx context.Context, keyword string, repo *repo_model.Repository) (string, int64, error) {
+	fields := strings.Fields(keyword)
+	for i, field := range fields {
+		var parentID int64
+		if field == "parent:none" {
+			parentID = db.NoConditionID
+		} else if m := parentKeywordPattern.FindStringSubmatch(field); m != nil {
+			parentRepo := repo
+			if m[1] != "" {
+				var err error
+				if parentRepo, err = repo_model.GetRepositoryByOwnerAndName(ctx, m[1], m[2]); err != nil {
+					if repo_model.IsErrRepoNotExist(err) {
+						continue
+					}
+					return "", 0, err
+				}
+			}
+			if parentRepo == nil {
+				continue
+			}
+			index, err := strconv.ParseInt(m[3], 10, 64)
+			if err != nil {
+				continue
+			}
+			parent, err := GetIssueByIndex(ctx, parentRepo.ID, index)
+			if err != nil {
+				if IsErrIssueNotExist(err) {
+					continue
+				}
+				return "", 0, err
+			}
+			parentID = parent.ID
+		} else {
+			continue
+		}
+		return strings.Join(append(fields[:i:i], fields[i+1:]...), " "), parentID, nil
+	}
+	return keyword, 0, nil
+}
+
+// isSubIssueOf checks if an issue is a sub-issue of the parent at any depth of the hierarchy
+func isSubIssueOf(ctx context.Context, issueID, parentID int64) (bool, error) {
+	visited := make(container.Set[int64])
+	for id := issueID; id > 0 && visited.Add(id); {
+		var err error
+		if id, err = GetParentIssueID(ctx, id); err != nil {
+			return false, err
+		}
+		if id == parentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddSubIssue makes an issue a sub-issue of the parent
+func AddSubIssue(ctx context.Context, doer *user_model.User, parent, issue *Issue) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	if parent.ID == issue.ID {
+		return ErrCircularSubIssue{issue.ID, parent.ID}
+	}
+	if parent.IsPull || issue.IsPull {
+		return util.NewInvalidArgumentErrorf("pull requests can't have a parent or sub-issues")
+	}
+	if err := parent.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if parent.Repo.OwnerID != issue.Repo.OwnerID {
+		return ErrSubIssueNotSameOwner{issue.ID, parent.ID}
+	}
+
+	parentID, err := GetParentIssueID(ctx, issue.ID)
+	if err != nil {
+		return err
+	} else if parentID > 0 {
+		return ErrSubIssueExists{issue.ID, parentID}
+	}
+	circular, err := isSubIssueOf(ctx, parent.ID, issue.ID)
+	if err != nil {
+		return err
+	} else if circular {
+		return ErrCircularSubIssue{issue.ID, parent.ID}
+	}
+
+	if err := db.Insert(ctx, &IssueParent{
+		UserID:   doer.ID,
+		IssueID:  issue.ID,
+		ParentID: parent.ID,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := CreateComment(ctx, &CreateCommentOptions{
+		Type:             CommentTypeAddSubIssue,
+		Doer:             doer,
+		Repo:             parent.Repo,
+		Issue:            parent,
+		DependentIssueID: issue.ID,
+	}); err != nil {
+		return err
+	}
+
+	return committer.Commit()
+}
+
+// RemoveSubIssue removes an issue from the sub-issues of the parent
+func RemoveSubIssue(ctx context.Context, doer *user_model.User, parent, issue *Issue) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	affected, err := db.GetEngine(ctx).Delete(&IssueParent{IssueID: issue.ID, ParentID: parent.ID})
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrSubIssueNotExist{issue.ID, parent.ID}
+	}
+
+	if err := parent.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if _, err := CreateComment(ctx, &CreateCommentOptions{
+		Type:             CommentTypeRemoveSubIssue,
+		Doer:             doer,
+		Repo:             parent.Repo,
+		Issue:            parent,
+		DependentIssueID: issue.ID,
+	}); err != nil {
+		return err
+	}
+
+	return committer.Commit()
+}
+
+// deleteIssueParents deletes the sub-issue relations of the issues, as parents and as sub-issues
+func deleteIssueParents(ctx context.Context, issueIDs []int64) error {
+	_, err := db.GetEngine(ctx).Where(builder.In("issue_id", issueIDs).Or(builder.In("parent_id", issueIDs))).Delete(new(IssueParent))
+	return err
+}
+
+// DeleteIssueParentsOutsideRepo deletes the sub-issue relations between the issues of a repository and the issues
+// of the other repositories, it is used when the repository changes owner
+func DeleteIssueParentsOutsideRepo(ctx context.Context, repoID int64) error {
+	inRepo := builder.Select("id").From("issue").Where(builder.Eq{"repo_id": repoID})
+	_, err := db.GetEngine(ctx).Where(
+		builder.Or(
+			builder.In("issue_id", inRepo).And(builder.NotIn("parent_id", inRepo)),
+			builder.In("parent_id", inRepo).And(builder.NotIn("issue_id", inRepo)),
+		),
+	).Delete(new(IssueParent))
+	return err
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	parent := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	closedChild := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+	otherRepoIssue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 7})
+	otherOwnerIssue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+
+	parentID, err := issues_model.GetParentIssueID(db.DefaultContext, closedChild.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, parent.ID, parentID)
+
+	progress, err := issues_model.GetSubIssueProgress(db.DefaultContext, parent.ID, []int64{parent.RepoID})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &issues_model.SubIssueProgress{Total: 1, Closed: 1}, progress)
+
+	// a sub-issue can be in another repository of the same owner
+	assert.NoError(t, issues_model.AddSubIssue(db.DefaultContext, doer, parent, otherRepoIssue))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{
+		Type:             issues_model.CommentTypeAddSubIssue,
+		IssueID:          parent.ID,
+		DependentIssueID: otherRepoIssue.ID,
+	})
+
+	subIssues, err := issues_model.GetSubIssues(db.DefaultContext, parent.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, subIssues, 2) {
+		assert.EqualValues(t, closedChild.ID, subIssues[0].ID)
+		assert.EqualValues(t, otherRepoIssue.ID, subIssues[1].ID)
+	}
+
+	repoIDs, err := issues_model.GetSubIssueRepoIDs(db.DefaultContext, parent.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int64{parent.RepoID, otherRepoIssue.RepoID}, repoIDs)
+
+	progress, err = issues_model.GetSubIssueProgress(db.DefaultContext, parent.ID, repoIDs)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, progress.Total)
+	assert.EqualValues(t, 1, progress.Closed)
+	assert.EqualValues(t, 50, progress.Completeness())
+
+	// the sub-issues of the repositories the viewer can't read are not counted
+	progress, err = issues_model.GetSubIssueProgress(db.DefaultContext, parent.ID, []int64{parent.RepoID})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &issues_model.SubIssueProgress{Total: 1, Closed: 1}, progress)
+	progress, err = issues_model.GetSubIssueProgress(db.DefaultContext, parent.ID, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, &issues_model.SubIssueProgress{}, progress)
+
+	err = issues_model.AddSubIssue(db.DefaultContext, doer, parent, otherRepoIssue)
+	assert.True(t, issues_model.IsErrSubIssueExists(err))
+	err = issues_model.AddSubIssue(db.DefaultContext, doer, otherRepoIssue, parent)
+	assert.True(t, issues_model.IsErrCircularSubIssue(err))
+	err = issues_model.AddSubIssue(db.DefaultContext, doer, parent, parent)
+	assert.True(t, issues_model.IsErrCircularSubIssue(err))
+	err = issues_model.AddSubIssue(db.DefaultContext, doer, parent, otherOwnerIssue)
+	assert.True(t, issues_model.IsErrSubIssueNotSameOwner(err))
+
+	assert.NoError(t, issues_model.RemoveSubIssue(db.DefaultContext, doer, parent, otherRepoIssue))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueParent{IssueID: otherRepoIssue.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{
+		Type:             issues_model.CommentTypeRemoveSubIssue,
+		IssueID:          parent.ID,
+		DependentIssueID: otherRepoIssue.ID,
+	})
+	err = issues_model.RemoveSubIssue(db.DefaultContext, doer, parent, otherRepoIssue)
+	assert.True(t, issues_model.IsErrSubIssueNotExist(err))
+}
+
+func TestParseParentKeyword(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	kases := []struct {
+		keyword  string
+		repo     *repo_model.Repository
+		expected string
+		parentID int64
+	}{
+		{keyword: "bug parent:1", repo: repo, expected: "bug", parentID: 1},
+		{keyword: "parent:#4 bug", repo: repo, expected: "bug", parentID: 5},
+		{keyword: "parent:user2/repo1#1", expected: "", parentID: 1},
+		{keyword: "parent:user2/repo2#2 bug", repo: repo, expected: "bug", parentID: 7},
+		{keyword: "parent:none", expected: "", parentID: db.NoConditionID},
+		{keyword: "parent:1", expected: "parent:1"},
+		{keyword: "parent:9999", repo: repo, expected: "parent:9999"},
+		{keyword: "parent:user2/notexist#1", repo: repo, expected: "parent:user2/notexist#1"},
+		{keyword: "bug", repo: repo, expected: "bug"},
+	}
+	for _, kase := range kases {
+		t.Run(kase.keyword, func(t *testing.T) {
+			keyword, parentID, err := issues_model.ParseParentKeyword(db.DefaultContext, kase.keyword, kase.repo)
+			assert.NoError(t, err)
+			assert.Equal(t, kase.expected, keyword)
+			assert.EqualValues(t, kase.parentID, parentID)
+		})
+	}
+}
@@ -87,7 +87,9 @@ type FindTrackedTimesOptions struct {
 	IssueID           int64
 	UserID            int64
 	RepositoryID      int64
+	RepositoryIDs     []int64
 	MilestoneID       int64
+	LabelID           int64
 	CreatedAfterUnix  int64
 	CreatedBeforeUnix int64
 }
@@ -104,9 +106,15 @@ func (opts *FindTrackedTimesOptions) toCond() builder.Cond {
 	if opts.RepositoryID != 0 {
 		cond = cond.And(builder.Eq{"issue.repo_id": opts.RepositoryID})
 	}
+	if opts.RepositoryIDs != nil {
+		cond = cond.And(builder.In("issue.repo_id", opts.RepositoryIDs))
+	}
 	if opts.MilestoneID != 0 {
 		cond = cond.And(builder.Eq{"issue.milestone_id": opts.MilestoneID})
 	}
+	if opts.LabelID != 0 {
+		cond = cond.And(builder.In("tracked_time.issue_id", builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": opts.LabelID})))
+	}
 	if opts.CreatedAfterUnix != 0 {
 		cond = cond.And(builder.Gte{"tracked_time.created_unix": opts.CreatedAfterUnix})
 	}
@@ -116,10 +124,15 @@ func (opts *FindTrackedTimesOptions) toCond() builder.Cond {
 	return cond
 }
 
+// joinsIssue returns whether the conditions need the tracked times to be joined with their issues
+func (opts *FindTrackedTimesOptions) joinsIssue() bool {
+	return opts.RepositoryID > 0 || opts.RepositoryIDs != nil || opts.MilestoneID > 0
+}
+
 // toSession will convert the given options to a xorm Session by using the conditions from toCond and joining with issue table if required
 func (opts *FindTrackedTimesOptions) toSession(e db.Engine) db.Engine {
 	sess := e
-	if opts.RepositoryID > 0 || opts.MilestoneID > 0 {
+	if opts.joinsIssue() {
 		sess = e.Join("INNER", "issue", "issue.id = tracked_time.issue_id")
 	}
 
@@ -141,7 +154,7 @@ func GetTrackedTimes(ctx context.Context, options *FindTrackedTimesOptions) (tra
 // CountTrackedTimes returns count of tracked times that fit to the given options.
 func CountTrackedTimes(ctx context.Context, opts *FindTrackedTimesOptions) (int64, error) {
 	sess := db.GetEngine(ctx).Where(opts.toCond())
-	if opts.RepositoryID > 0 || opts.MilestoneID > 0 {
+	if opts.joinsIssue() {
 		sess = sess.Join("INNER", "issue", "issue.id = tracked_time.issue_id")
 	}
 	return sess.Count(&TrackedTime{})
@@ -0,0 +1,204 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// TrackedTimeReportGroup is how the tracked times of a report are grouped
+type TrackedTimeReportGroup string
+
+// The groups of a tracked time report
+const (
+	TrackedTimeReportGroupUser      TrackedTimeReportGroup = "user"
+	TrackedTimeReportGroupRepo      TrackedTimeReportGroup = "repo"
+	TrackedTimeReportGroupMilestone TrackedTimeReportGroup = "milestone"
+	TrackedTimeReportGroupLabel     TrackedTimeReportGroup = "label"
+	TrackedTimeReportGroupIssue     TrackedTimeReportGroup = "issue"
+)
+
+// TrackedTimeReportGroups are the valid groups of a tracked time report
+var TrackedTimeReportGroups = []TrackedTimeReportGroup{
+	TrackedTimeReportGroupUser,
+	TrackedTimeReportGroupRepo,
+	TrackedTimeReportGroupMilestone,
+	TrackedTimeReportGroupLabel,
+	TrackedTimeReportGroupIssue,
+}
+
+// IsValid returns whether the group is a valid group of a report
+func (g TrackedTimeReportGroup) IsValid() bool {
+	for _, group := range TrackedTimeReportGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// TrackedTimeReportEntry is the time tracked on the issues of a group
+type TrackedTimeReportEntry struct {
+	// ID is the id of the user, the repository, the milestone, the label or the issue of the group. It is zero
+	// for the issues without milestone or without label.
+	ID   int64
+	Name string
+	Link string
+	// Time is the tracked time in seconds
+	Time int64
+	// Estimate is the sum of the time estimates of the issues in seconds
+	Estimate int64
+	// Issues is the number of issues with tracked time
+	Issues int
+}
+
+// TrackedTimeReport is the time tracked on issues grouped by user, repository, milestone, label or issue
+type TrackedTimeReport struct {
+	Group   TrackedTimeReportGroup
+	Entries []*TrackedTimeReportEntry
+	// Time, Estimate and Issues are the totals of the report, the time of an issue with several labels is only
+	// counted once
+	Time     int64
+	Estimate int64
+	Issues   int
+}
+
+// trackedTimeReportRow is the time tracked by a user on an issue
+type trackedTimeReportRow struct {
+	IssueID int64
+	UserID  int64
+	Time    int64
+}
+
+// GetTrackedTimeReport returns the times matching the options grouped by the given group. The pagination of
+// the options is ignored.
+func GetTrackedTimeReport(ctx context.Context, opts *FindTrackedTimesOptions, group TrackedTimeReportGroup) (*TrackedTimeReport, error) {
+	var rows []*trackedTimeReportRow
+	if err := db.GetEngine(ctx).Table("tracked_time").
+		Select("tracked_time.issue_id, tracked_time.user_id, SUM(tracked_time.time) AS time").
+		Join("INNER", "issue", "issue.id = tracked_time.issue_id").
+		Where(opts.toCond()).
+		GroupBy("tracked_time.issue_id, tracked_time.user_id").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+
+	issueIDs := make(container.Set[int64])
+	for _, row := range rows {
+		issueIDs.Add(row.IssueID)
+	}
+	issues, err := GetIssuesByIDs(ctx, issueIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+	issuesByID := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+
+	report := &TrackedTimeReport{Group: group}
+	for _, issue := range issues {
+		report.Estimate += issue.TimeEstimate
+	}
+	report.Issues = len(issues)
+
+	entries := make(map[int64]*TrackedTimeReportEntry)
+	entryIssues := make(map[int64]container.Set[int64])
+	add := func(id int64, issue *Issue, time int64) {
+		entry, ok := entries[id]
+		if !ok {
+			entry = &TrackedTimeReportEntry{ID: id}
+			entries[id] = entry
+			entryIssues[id] = make(container.Set[int64])
+		}
+		entry.Time += time
+		if entryIssues[id].Add(issue.ID) {
+			entry.Estimate += issue.TimeEstimate
+			entry.Issues++
+		}
+	}
+
+	switch group {
+	case TrackedTimeReportGroupLabel:
+		if err := issues.loadLabels(ctx); err != nil {
+			return nil, err
+		}
+	case TrackedTimeReportGroupMilestone:
+		if err := issues.loadMilestones(ctx); err != nil {
+			return nil, err
+		}
+	case TrackedTimeReportGroupRepo, TrackedTimeReportGroupIssue:
+		if _, err := issues.LoadRepositories(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range rows {
+		issue, ok := issuesByID[row.IssueID]
+		if !ok {
+			continue
+		}
+		report.Time += row.Time
+		switch group {
+		case TrackedTimeReportGroupUser:
+			add(row.UserID, issue, row.Time)
+		case TrackedTimeReportGroupRepo:
+			add(issue.RepoID, issue, row.Time)
+			entries[issue.RepoID].Name, entries[issue.RepoID].Link = issue.Repo.FullName(), issue.Repo.Link()
+		case TrackedTimeReportGroupMilestone:
+			add(issue.MilestoneID, issue, row.Time)
+			if issue.Milestone != nil {
+				entries[issue.MilestoneID].Name = issue.Milestone.Name
+			}
+		case TrackedTimeReportGroupLabel:
+			if len(issue.Labels) == 0 {
+				add(0, issue, row.Time)
+			}
+			for _, label := range issue.Labels {
+				add(label.ID, issue, row.Time)
+				entries[label.ID].Name = label.Name
+			}
+		case TrackedTimeReportGroupIssue:
+			add(issue.ID, issue, row.Time)
+			entries[issue.ID].Name = fmt.Sprintf("%s#%d %s", issue.Repo.FullName(), issue.Index, issue.Title)
+			entries[issue.ID].Link = issue.Link()
+		}
+	}
+
+	if group == TrackedTimeReportGroupUser {
+		userIDs := make([]int64, 0, len(entries))
+		for id := range entries {
+			userIDs = append(userIDs, id)
+		}
+		users, err := user_model.GetUsersByIDs(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			entry.Name = user_model.NewGhostUser().Name
+		}
+		for _, user := range users {
+			entries[user.ID].Name, entries[user.ID].Link = user.Name, user.HomeLink()
+		}
+	}
+
+	report.Entries = make([]*TrackedTimeReportEntry, 0, len(entries))
+	for _, entry := range entries {
+		report.Entries = append(report.Entries, entry)
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Time != report.Entries[j].Time {
+			return report.Entries[i].Time > report.Entries[j].Time
+		}
+		return strings.ToLower(report.Entries[i].Name) < strings.ToLower(report.Entries[j].Name)
+	})
+	return report, nil
+}
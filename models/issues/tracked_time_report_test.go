@@ -0,0 +1,81 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateIssueTimeEstimate(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+
+	assert.NoError(t, issues_model.UpdateIssueTimeEstimate(db.DefaultContext, issue, 5400, doer))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.EqualValues(t, 5400, issue.TimeEstimate)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{Type: issues_model.CommentTypeChangeTimeEstimate, IssueID: 2, Content: "5400"})
+
+	// an unchanged estimate doesn't add a comment
+	assert.NoError(t, issues_model.UpdateIssueTimeEstimate(db.DefaultContext, issue, 5400, doer))
+	unittest.AssertCount(t, &issues_model.Comment{Type: issues_model.CommentTypeChangeTimeEstimate, IssueID: 2}, 1)
+}
+
+func TestGetTrackedTimeReport(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issues_model.UpdateIssueTimeEstimate(db.DefaultContext, issue, 3600, doer))
+
+	type entry struct {
+		ID       int64
+		Time     int64
+		Estimate int64
+		Issues   int
+	}
+	report := func(group issues_model.TrackedTimeReportGroup, opts *issues_model.FindTrackedTimesOptions) (*issues_model.TrackedTimeReport, []entry) {
+		report, err := issues_model.GetTrackedTimeReport(db.DefaultContext, opts, group)
+		assert.NoError(t, err)
+		entries := make([]entry, 0, len(report.Entries))
+		for _, e := range report.Entries {
+			entries = append(entries, entry{e.ID, e.Time, e.Estimate, e.Issues})
+		}
+		return report, entries
+	}
+
+	r, entries := report(issues_model.TrackedTimeReportGroupUser, &issues_model.FindTrackedTimesOptions{RepositoryIDs: []int64{1}})
+	assert.EqualValues(t, 4083, r.Time)
+	assert.EqualValues(t, 3600, r.Estimate)
+	assert.Equal(t, 3, r.Issues)
+	assert.Equal(t, []entry{{2, 3663, 3600, 2}, {1, 420, 3600, 2}}, entries)
+	assert.Equal(t, "user2", r.Entries[0].Name)
+
+	// an issue with several labels is counted in each of its labels but only once in the totals
+	r, entries = report(issues_model.TrackedTimeReportGroupLabel, &issues_model.FindTrackedTimesOptions{RepositoryIDs: []int64{1}})
+	assert.EqualValues(t, 4083, r.Time)
+	assert.Equal(t, []entry{{1, 4082, 3600, 2}, {4, 3682, 3600, 1}, {2, 1, 0, 1}}, entries)
+
+	_, entries = report(issues_model.TrackedTimeReportGroupMilestone, &issues_model.FindTrackedTimesOptions{RepositoryIDs: []int64{1}})
+	assert.Equal(t, []entry{{1, 3682, 3600, 1}, {0, 401, 0, 2}}, entries)
+
+	r, entries = report(issues_model.TrackedTimeReportGroupRepo, &issues_model.FindTrackedTimesOptions{UserID: 1})
+	assert.Equal(t, []entry{{1, 420, 3600, 2}, {2, 71, 0, 1}}, entries)
+	assert.Equal(t, "user2/repo1", r.Entries[0].Name)
+
+	_, entries = report(issues_model.TrackedTimeReportGroupIssue, &issues_model.FindTrackedTimesOptions{RepositoryIDs: []int64{1}, LabelID: 1})
+	assert.Equal(t, []entry{{2, 3682, 3600, 1}, {1, 400, 0, 1}}, entries)
+
+	r, entries = report(issues_model.TrackedTimeReportGroupUser, &issues_model.FindTrackedTimesOptions{RepositoryIDs: []int64{}})
+	assert.EqualValues(t, 0, r.Time)
+	assert.Empty(t, entries)
+}
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/models/migrations/v1_19"
 	"code.gitea.io/gitea/models/migrations/v1_20"
 	"code.gitea.io/gitea/models/migrations/v1_21"
+	"code.gitea.io/gitea/models/migrations/v1_22"
 	"code.gitea.io/gitea/models/migrations/v1_6"
 	"code.gitea.io/gitea/models/migrations/v1_7"
 	"code.gitea.io/gitea/models/migrations/v1_8"
@@ -540,6 +541,43 @@ var migrations = []Migration{
 	NewMigration("Add Index to comment.dependent_issue_id", v1_21.AddIndexToCommentDependentIssueID),
 	// v279 -> v280
 	NewMigration("Add Index to action.user_id", v1_21.AddIndexToActionUserID),
+
+	// Gitea 1.21.0 ends at 280
+
+	// v280 -> v281
+	NewMigration("Add StartLine to comment table", v1_22.AddStartLineToComment),
+	// v281 -> v282
+	NewMigration("Add indexed branches and tags for the code indexer", v1_22.AddCodeIndexerRefs),
+	// v282 -> v283
+	NewMigration("Create project field tables", v1_22.CreateProjectFieldTables),
+	// v283 -> v284
+	NewMigration("Create project view table", v1_22.CreateProjectViewTable),
+	// v284 -> v285
+	NewMigration("Create project automation table", v1_22.CreateProjectAutomationTable),
+	// v285 -> v286
+	NewMigration("Add org_id to milestone", v1_22.AddOrgIDToMilestone),
+	// v286 -> v287
+	NewMigration("Create issue parent table", v1_22.CreateIssueParentTable),
+	// v287 -> v288
+	NewMigration("Add issue types", v1_22.AddIssueTypes),
+	// v288 -> v289
+	NewMigration("Create saved search table", v1_22.CreateSavedSearchTable),
+	// v289 -> v290
+	NewMigration("Create SLA policy and issue SLA tables", v1_22.CreateSLATables),
+	// v290 -> v291
+	NewMigration("Add time estimate to issue", v1_22.AddTimeEstimateToIssue),
+	// v291 -> v292
+	NewMigration("Add webhook delivery retries", v1_22.AddWebhookRetries),
+	// v292 -> v293
+	NewMigration("Add ref filters and failure history to push mirrors", v1_22.AddPushMirrorRefFiltersAndFailures),
+	// v293 -> v294
+	NewMigration("Create partial mirror table", v1_22.CreatePartialMirrorTable),
+	// v294 -> v295
+	NewMigration("Create migration sync tables", v1_22.CreateMigrationSyncTables),
+	// v295 -> v296
+	NewMigration("Create notification preference and digest tables", v1_22.CreateNotificationPreferenceTables),
+	// v296 -> v297
+	NewMigration("Add original uuid to hook task", v1_22.AddOriginalUUIDToHookTask),
 }
 
 // GetCurrentDBVersion returns the current db version
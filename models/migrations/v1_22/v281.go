@@ -0,0 +1,23 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddCodeIndexerRefs(x *xorm.Engine) error {
+	type Repository struct {
+		CodeIndexerBranches []string `xorm:"TEXT JSON"`
+		CodeIndexerTags     []string `xorm:"TEXT JSON"`
+	}
+	if err := x.Sync(new(Repository)); err != nil {
+		return err
+	}
+
+	type RepoIndexerStatus struct {
+		Ref string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	}
+	return x.Sync(new(RepoIndexerStatus))
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateProjectFieldTables(x *xorm.Engine) error {
+	type ProjectField struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ProjectID   int64              `xorm:"INDEX NOT NULL"`
+		Name        string             `xorm:"VARCHAR(255) NOT NULL"`
+		Type        uint8              `xorm:"NOT NULL"`
+		Sorting     int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type ProjectFieldOption struct {
+		ID        int64  `xorm:"pk autoincr"`
+		FieldID   int64  `xorm:"INDEX NOT NULL"`
+		Name      string `xorm:"VARCHAR(255) NOT NULL"`
+		Color     string `xorm:"VARCHAR(7)"`
+		Sorting   int64  `xorm:"NOT NULL DEFAULT 0"`
+		StartUnix timeutil.TimeStamp
+		EndUnix   timeutil.TimeStamp
+	}
+
+	type ProjectFieldValue struct {
+		ID          int64  `xorm:"pk autoincr"`
+		ProjectID   int64  `xorm:"INDEX NOT NULL"`
+		FieldID     int64  `xorm:"UNIQUE(s) NOT NULL"`
+		IssueID     int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Text        string `xorm:"TEXT"`
+		Number      float64
+		DateUnix    timeutil.TimeStamp
+		OptionID    int64
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ProjectField), new(ProjectFieldOption), new(ProjectFieldValue))
+}
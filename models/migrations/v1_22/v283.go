@@ -0,0 +1,28 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateProjectViewTable(x *xorm.Engine) error {
+	type ProjectView struct {
+		ID           int64  `xorm:"pk autoincr"`
+		ProjectID    int64  `xorm:"INDEX NOT NULL"`
+		Name         string `xorm:"VARCHAR(255) NOT NULL"`
+		Type         uint8  `xorm:"NOT NULL"`
+		Sorting      int64  `xorm:"NOT NULL DEFAULT 0"`
+		GroupBy      string `xorm:"VARCHAR(20)"`
+		SortBy       string `xorm:"VARCHAR(20)"`
+		SortDesc     bool   `xorm:"NOT NULL DEFAULT false"`
+		StartFieldID int64
+		CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync(new(ProjectView))
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateProjectAutomationTable(x *xorm.Engine) error {
+	type ProjectAutomation struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ProjectID   int64              `xorm:"INDEX NOT NULL"`
+		Event       uint8              `xorm:"INDEX NOT NULL"`
+		BoardID     int64              `xorm:"INDEX"`
+		LabelID     int64              `xorm:"INDEX"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(ProjectAutomation))
+}
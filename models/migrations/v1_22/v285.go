@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddOrgIDToMilestone(x *xorm.Engine) error {
+	type Milestone struct {
+		OrgID int64 `xorm:"INDEX"`
+	}
+
+	return x.Sync(new(Milestone))
+}
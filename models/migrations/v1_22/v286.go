@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateIssueParentTable(x *xorm.Engine) error {
+	type IssueParent struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"NOT NULL"`
+		IssueID     int64              `xorm:"UNIQUE NOT NULL"`
+		ParentID    int64              `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(IssueParent))
+}
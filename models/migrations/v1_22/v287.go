@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueTypes(x *xorm.Engine) error {
+	type IssueType struct {
+		ID          int64  `xorm:"pk autoincr"`
+		OrgID       int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Name        string `xorm:"UNIQUE(s) NOT NULL"`
+		Description string
+		Color       string             `xorm:"VARCHAR(7)"`
+		Template    string             `xorm:"LONGTEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type IssueFieldValue struct {
+		ID      int64  `xorm:"pk autoincr"`
+		IssueID int64  `xorm:"INDEX NOT NULL"`
+		FieldID string `xorm:"INDEX NOT NULL"`
+		Value   string `xorm:"TEXT"`
+	}
+
+	type Issue struct {
+		TypeID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(IssueType), new(IssueFieldValue), new(Issue))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateSavedSearchTable(x *xorm.Engine) error {
+	type SavedSearch struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"UNIQUE(s) NOT NULL"`
+		CreatorID   int64              `xorm:"NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) NOT NULL"`
+		IsPull      bool               `xorm:"NOT NULL DEFAULT false"`
+		Query       string             `xorm:"TEXT"`
+		IsPublic    bool               `xorm:"NOT NULL DEFAULT false"`
+		OnDashboard bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync(new(SavedSearch))
+}
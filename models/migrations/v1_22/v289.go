@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateSLATables(x *xorm.Engine) error {
+	type SLAPolicy struct {
+		ID                   int64              `xorm:"pk autoincr"`
+		RepoID               int64              `xorm:"INDEX NOT NULL"`
+		Name                 string             `xorm:"NOT NULL"`
+		LabelID              int64              `xorm:"NOT NULL DEFAULT 0"`
+		FirstResponseSeconds int64              `xorm:"NOT NULL DEFAULT 0"`
+		ResolveSeconds       int64              `xorm:"NOT NULL DEFAULT 0"`
+		NotifyAssignees      bool               `xorm:"NOT NULL DEFAULT true"`
+		EscalateUserIDs      []int64            `xorm:"JSON TEXT"`
+		EscalateTeamIDs      []int64            `xorm:"JSON TEXT"`
+		CreatedUnix          timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix          timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueSLA struct {
+		ID                     int64              `xorm:"pk autoincr"`
+		IssueID                int64              `xorm:"UNIQUE NOT NULL"`
+		RepoID                 int64              `xorm:"INDEX NOT NULL"`
+		PolicyID               int64              `xorm:"INDEX NOT NULL"`
+		State                  int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		FirstResponseDeadline  timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		FirstResponseUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		FirstResponseEscalated bool               `xorm:"NOT NULL DEFAULT false"`
+		ResolveDeadline        timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ResolvedUnix           timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		ResolveEscalated       bool               `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(SLAPolicy), new(IssueSLA))
+}
@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddWebhookRetries(x *xorm.Engine) error {
+	type HookTask struct {
+		Attempt   int                `xorm:"NOT NULL DEFAULT 0"`
+		RetryUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+	type Webhook struct {
+		FailureCount int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(HookTask), new(Webhook))
+}
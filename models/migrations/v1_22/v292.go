@@ -0,0 +1,28 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPushMirrorRefFiltersAndFailures(x *xorm.Engine) error {
+	type PushMirror struct {
+		RefIncludes    string `xorm:"TEXT"`
+		RefExcludes    string `xorm:"TEXT"`
+		NonDestructive bool   `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type PushMirrorFailure struct {
+		ID           int64              `xorm:"pk autoincr"`
+		PushMirrorID int64              `xorm:"INDEX NOT NULL"`
+		RepoID       int64              `xorm:"INDEX NOT NULL"`
+		Error        string             `xorm:"TEXT"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(PushMirror), new(PushMirrorFailure))
+}
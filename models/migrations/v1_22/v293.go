@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreatePartialMirrorTable(x *xorm.Engine) error {
+	type PartialMirror struct {
+		ID            int64 `xorm:"pk autoincr"`
+		RepoID        int64 `xorm:"INDEX NOT NULL"`
+		CreatorID     int64 `xorm:"NOT NULL DEFAULT 0"`
+		RemoteName    string
+		RemoteAddress string `xorm:"VARCHAR(2048)"`
+
+		BranchMappings string `xorm:"TEXT"`
+		Divergence     string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'skip'"`
+		LFS            bool   `xorm:"lfs_enabled NOT NULL DEFAULT false"`
+		LFSEndpoint    string `xorm:"lfs_endpoint TEXT"`
+
+		Interval         time.Duration
+		CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix      timeutil.TimeStamp `xorm:"INDEX"`
+		NextUpdateUnix   timeutil.TimeStamp `xorm:"INDEX"`
+		LastError        string             `xorm:"TEXT"`
+		DivergedBranches []string           `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(PartialMirror))
+}
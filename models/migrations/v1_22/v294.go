@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateMigrationSyncTables(x *xorm.Engine) error {
+	type MigrationSync struct {
+		ID                    int64 `xorm:"pk autoincr"`
+		RepoID                int64 `xorm:"UNIQUE NOT NULL"`
+		DoerID                int64 `xorm:"NOT NULL DEFAULT 0"`
+		GitServiceType        int
+		CloneAddr             string `xorm:"VARCHAR(2048)"`
+		AuthUsername          string
+		AuthPasswordEncrypted string `xorm:"TEXT"`
+		AuthTokenEncrypted    string `xorm:"TEXT"`
+
+		Milestones   bool `xorm:"NOT NULL DEFAULT false"`
+		Labels       bool `xorm:"NOT NULL DEFAULT false"`
+		Issues       bool `xorm:"NOT NULL DEFAULT false"`
+		Comments     bool `xorm:"NOT NULL DEFAULT false"`
+		PullRequests bool `xorm:"NOT NULL DEFAULT false"`
+		Releases     bool `xorm:"NOT NULL DEFAULT false"`
+
+		Interval     time.Duration
+		MigratedUnix timeutil.TimeStamp
+		LastSyncUnix timeutil.TimeStamp
+		NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+		LastError    string             `xorm:"TEXT"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type MigrationSyncRef struct {
+		ID        int64  `xorm:"pk autoincr"`
+		RepoID    int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Type      string `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+		ForeignID int64  `xorm:"UNIQUE(s) NOT NULL"`
+		LocalID   int64  `xorm:"NOT NULL"`
+	}
+
+	return x.Sync(new(MigrationSync), new(MigrationSyncRef))
+}
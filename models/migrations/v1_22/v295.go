@@ -0,0 +1,40 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateNotificationPreferenceTables(x *xorm.Engine) error {
+	type NotificationPreference struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Event       string             `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+		Delivery    string             `xorm:"VARCHAR(20) NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type NotificationDigest struct {
+		ID           int64              `xorm:"pk autoincr"`
+		UserID       int64              `xorm:"UNIQUE NOT NULL"`
+		Frequency    string             `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		LastSentUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type NotificationDigestItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		Event       string             `xorm:"VARCHAR(50) NOT NULL"`
+		Title       string             `xorm:"TEXT"`
+		Link        string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+	}
+
+	return x.Sync(new(NotificationPreference), new(NotificationDigest), new(NotificationDigestItem))
+}
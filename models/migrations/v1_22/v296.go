@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_22 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddOriginalUUIDToHookTask(x *xorm.Engine) error {
+	type HookTask struct {
+		OriginalUUID string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	}
+
+	return x.Sync(new(HookTask))
+}
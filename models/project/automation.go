@@ -0,0 +1,194 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// AutomationEvent is the event of an issue or a pull request of a project which triggers an automation
+type AutomationEvent uint8
+
+const (
+	// AutomationEventItemAdded is triggered when an issue or a pull request is added to the project
+	AutomationEventItemAdded AutomationEvent = iota + 1
+	// AutomationEventItemClosed is triggered when an issue or a pull request of the project is closed
+	AutomationEventItemClosed
+	// AutomationEventItemReopened is triggered when an issue or a pull request of the project is reopened
+	AutomationEventItemReopened
+	// AutomationEventPullRequestOpened is triggered when a pull request of the project is opened,
+	// or when an open pull request is added to the project
+	AutomationEventPullRequestOpened
+	// AutomationEventPullRequestMerged is triggered when a pull request of the project is merged
+	AutomationEventPullRequestMerged
+	// AutomationEventReviewRequested is triggered when a review is requested on a pull request of the project
+	AutomationEventReviewRequested
+)
+
+var automationEventNames = map[AutomationEvent]string{
+	AutomationEventItemAdded:         "item_added",
+	AutomationEventItemClosed:        "item_closed",
+	AutomationEventItemReopened:      "item_reopened",
+	AutomationEventPullRequestOpened: "pull_request_opened",
+	AutomationEventPullRequestMerged: "pull_request_merged",
+	AutomationEventReviewRequested:   "review_requested",
+}
+
+// AutomationEvents returns all the automation events
+func AutomationEvents() []AutomationEvent {
+	return []AutomationEvent{
+		AutomationEventItemAdded,
+		AutomationEventItemClosed,
+		AutomationEventItemReopened,
+		AutomationEventPullRequestOpened,
+		AutomationEventPullRequestMerged,
+		AutomationEventReviewRequested,
+	}
+}
+
+// String returns the name of the automation event
+func (e AutomationEvent) String() string {
+	return automationEventNames[e]
+}
+
+// ParseAutomationEvent returns the automation event of the given name
+func ParseAutomationEvent(name string) (AutomationEvent, bool) {
+	for e, n := range automationEventNames {
+		if n == name {
+			return e, true
+		}
+	}
+	return 0, false
+}
+
+// ErrProjectAutomationNotExist represents a "ProjectAutomationNotExist" kind of error.
+type ErrProjectAutomationNotExist struct {
+	ID int64
+}
+
+// IsErrProjectAutomationNotExist checks if an error is a ErrProjectAutomationNotExist
+func IsErrProjectAutomationNotExist(err error) bool {
+	_, ok := err.(ErrProjectAutomationNotExist)
+	return ok
+}
+
+func (err ErrProjectAutomationNotExist) Error() string {
+	return fmt.Sprintf("project automation does not exist [id: %d]", err.ID)
+}
+
+func (err ErrProjectAutomationNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Automation is a rule of a project, it moves the issues of the project to a board
+// and/or adds a label to them when the event happens
+type Automation struct {
+	ID        int64           `xorm:"pk autoincr"`
+	ProjectID int64           `xorm:"INDEX NOT NULL"`
+	Event     AutomationEvent `xorm:"INDEX NOT NULL"`
+	BoardID   int64           `xorm:"INDEX"`
+	LabelID   int64           `xorm:"INDEX"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// TableName return the real table name
+func (Automation) TableName() string {
+	return "project_automation"
+}
+
+func init() {
+	db.RegisterModel(new(Automation))
+}
+
+// GetAutomations returns the automations of a project
+func GetAutomations(ctx context.Context, projectID int64) ([]*Automation, error) {
+	automations := make([]*Automation, 0, 5)
+	return automations, db.GetEngine(ctx).Where("project_id = ?", projectID).OrderBy("event, id").Find(&automations)
+}
+
+// GetAutomationByID returns an automation of a project
+func GetAutomationByID(ctx context.Context, projectID, id int64) (*Automation, error) {
+	automation := &Automation{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND project_id = ?", id, projectID).Get(automation)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectAutomationNotExist{ID: id}
+	}
+	return automation, nil
+}
+
+// FindAutomations returns the automations of the projects triggered by the event
+func FindAutomations(ctx context.Context, projectIDs []int64, event AutomationEvent) ([]*Automation, error) {
+	automations := make([]*Automation, 0, 5)
+	if len(projectIDs) == 0 {
+		return automations, nil
+	}
+	return automations, db.GetEngine(ctx).
+		In("project_id", projectIDs).
+		And("event = ?", event).
+		OrderBy("id").
+		Find(&automations)
+}
+
+// NewAutomation adds an automation to a project, the label must have been checked
+// to be usable by the issues of the project
+func NewAutomation(ctx context.Context, automation *Automation) error {
+	if _, ok := automationEventNames[automation.Event]; !ok {
+		return util.NewInvalidArgumentErrorf("unknown automation event %d", automation.Event)
+	}
+	if automation.BoardID == 0 && automation.LabelID == 0 {
+		return util.NewInvalidArgumentErrorf("an automation must move the issues to a column or add a label to them")
+	}
+	if automation.BoardID > 0 {
+		board, err := GetBoard(ctx, automation.BoardID)
+		if err != nil {
+			if IsErrProjectBoardNotExist(err) {
+				return util.NewInvalidArgumentErrorf("the column doesn't exist")
+			}
+			return err
+		}
+		if board.ProjectID != automation.ProjectID {
+			return util.NewInvalidArgumentErrorf("the column doesn't belong to the project")
+		}
+	}
+	return db.Insert(ctx, automation)
+}
+
+// DeleteAutomation deletes an automation of a project
+func DeleteAutomation(ctx context.Context, automation *Automation) error {
+	_, err := db.GetEngine(ctx).ID(automation.ID).Delete(new(Automation))
+	return err
+}
+
+// DeleteAutomationsByLabelID stops the automations adding a deleted label, the ones left without an action are deleted
+func DeleteAutomationsByLabelID(ctx context.Context, labelID int64) error {
+	if _, err := db.GetEngine(ctx).Where("label_id = ? AND board_id = 0", labelID).Delete(new(Automation)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("label_id = ?", labelID).Cols("label_id").Update(&Automation{})
+	return err
+}
+
+// deleteAutomationsByBoardID stops the automations moving the issues to a deleted board, the ones left without an action are deleted
+func deleteAutomationsByBoardID(ctx context.Context, boardID int64) error {
+	if _, err := db.GetEngine(ctx).Where("board_id = ? AND label_id = 0", boardID).Delete(new(Automation)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("board_id = ?", boardID).Cols("board_id").Update(&Automation{})
+	return err
+}
+
+func deleteAutomationsByProjectIDs(ctx context.Context, projectIDs builder.Cond) error {
+	_, err := db.GetEngine(ctx).Where(builder.In("project_id", builder.Select("id").From("project").Where(projectIDs))).Delete(new(Automation))
+	return err
+}
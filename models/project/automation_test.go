@@ -0,0 +1,55 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAutomations(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	automations, err := FindAutomations(db.DefaultContext, []int64{1, 2}, AutomationEventItemClosed)
+	assert.NoError(t, err)
+	if assert.Len(t, automations, 1) {
+		assert.EqualValues(t, 3, automations[0].BoardID)
+	}
+
+	automations, err = FindAutomations(db.DefaultContext, []int64{1}, AutomationEventPullRequestMerged)
+	assert.NoError(t, err)
+	assert.Empty(t, automations)
+}
+
+func TestNewAutomation(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	automation := &Automation{ProjectID: 1, Event: AutomationEventPullRequestMerged, BoardID: 3}
+	assert.NoError(t, NewAutomation(db.DefaultContext, automation))
+	unittest.AssertExistsAndLoadBean(t, &Automation{ID: automation.ID, Event: AutomationEventPullRequestMerged})
+
+	// the column must belong to the project
+	err := NewAutomation(db.DefaultContext, &Automation{ProjectID: 1, Event: AutomationEventItemAdded, BoardID: 4})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	err = NewAutomation(db.DefaultContext, &Automation{ProjectID: 1, Event: AutomationEventItemAdded})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestDeleteBoardAutomations(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, DeleteBoardByID(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &Automation{ID: 1})
+
+	assert.NoError(t, DeleteBoardByID(db.DefaultContext, 2))
+	automation := unittest.AssertExistsAndLoadBean(t, &Automation{ID: 2})
+	assert.Zero(t, automation.BoardID)
+	assert.EqualValues(t, 2, automation.LabelID)
+}
@@ -180,6 +180,10 @@ func deleteBoardByID(ctx context.Context, boardID int64) error {
 		return err
 	}
 
+	if err = deleteAutomationsByBoardID(ctx, board.ID); err != nil {
+		return err
+	}
+
 	if _, err := db.GetEngine(ctx).ID(board.ID).NoAutoCondition().Delete(board); err != nil {
 		return err
 	}
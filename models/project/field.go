@@ -0,0 +1,579 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// FieldType is the type of the values of a project field
+type FieldType uint8
+
+const (
+	// FieldTypeText is a field holding a free text
+	FieldTypeText FieldType = iota + 1
+	// FieldTypeNumber is a field holding a number, like an estimate
+	FieldTypeNumber
+	// FieldTypeDate is a field holding a date
+	FieldTypeDate
+	// FieldTypeSingleSelect is a field holding one of its options, like a status or a priority
+	FieldTypeSingleSelect
+	// FieldTypeIteration is a field holding one of its iterations, which are options with a start and an end date
+	FieldTypeIteration
+)
+
+// FieldDateLayout is the layout of the values of the date fields
+const FieldDateLayout = "2006-01-02"
+
+var fieldTypeNames = map[FieldType]string{
+	FieldTypeText:         "text",
+	FieldTypeNumber:       "number",
+	FieldTypeDate:         "date",
+	FieldTypeSingleSelect: "single_select",
+	FieldTypeIteration:    "iteration",
+}
+
+// FieldTypes returns all the field types
+func FieldTypes() []FieldType {
+	return []FieldType{FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeSingleSelect, FieldTypeIteration}
+}
+
+// String returns the name of the field type
+func (t FieldType) String() string {
+	return fieldTypeNames[t]
+}
+
+// HasOptions returns true if the values of the fields of this type are chosen from their options
+func (t FieldType) HasOptions() bool {
+	return t == FieldTypeSingleSelect || t == FieldTypeIteration
+}
+
+// ParseFieldType returns the field type of the given name
+func ParseFieldType(name string) (FieldType, bool) {
+	for t, n := range fieldTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// ErrProjectFieldNotExist represents a "ProjectFieldNotExist" kind of error.
+type ErrProjectFieldNotExist struct {
+	ID int64
+}
+
+// IsErrProjectFieldNotExist checks if an error is a ErrProjectFieldNotExist
+func IsErrProjectFieldNotExist(err error) bool {
+	_, ok := err.(ErrProjectFieldNotExist)
+	return ok
+}
+
+func (err ErrProjectFieldNotExist) Error() string {
+	return fmt.Sprintf("project field does not exist [id: %d]", err.ID)
+}
+
+func (err ErrProjectFieldNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrProjectFieldOptionNotExist represents a "ProjectFieldOptionNotExist" kind of error.
+type ErrProjectFieldOptionNotExist struct {
+	ID int64
+}
+
+// IsErrProjectFieldOptionNotExist checks if an error is a ErrProjectFieldOptionNotExist
+func IsErrProjectFieldOptionNotExist(err error) bool {
+	_, ok := err.(ErrProjectFieldOptionNotExist)
+	return ok
+}
+
+func (err ErrProjectFieldOptionNotExist) Error() string {
+	return fmt.Sprintf("project field option does not exist [id: %d]", err.ID)
+}
+
+func (err ErrProjectFieldOptionNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Field is a custom field of a project, each issue of the project can have a value for it
+type Field struct {
+	ID        int64     `xorm:"pk autoincr"`
+	ProjectID int64     `xorm:"INDEX NOT NULL"`
+	Name      string    `xorm:"VARCHAR(255) NOT NULL"`
+	Type      FieldType `xorm:"NOT NULL"`
+	Sorting   int64     `xorm:"NOT NULL DEFAULT 0"`
+
+	Options []*FieldOption `xorm:"-"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// TableName return the real table name
+func (Field) TableName() string {
+	return "project_field"
+}
+
+// FieldOption is an option of a single select field, or an iteration of an iteration field
+type FieldOption struct {
+	ID      int64  `xorm:"pk autoincr"`
+	FieldID int64  `xorm:"INDEX NOT NULL"`
+	Name    string `xorm:"VARCHAR(255) NOT NULL"`
+	Color   string `xorm:"VARCHAR(7)"`
+	Sorting int64  `xorm:"NOT NULL DEFAULT 0"`
+
+	// the dates of an iteration
+	StartUnix timeutil.TimeStamp
+	EndUnix   timeutil.TimeStamp
+}
+
+// TableName return the real table name
+func (FieldOption) TableName() string {
+	return "project_field_option"
+}
+
+// FieldValue is the value of a field for an issue of the project, only the column of the type of the field is used
+type FieldValue struct {
+	ID        int64 `xorm:"pk autoincr"`
+	ProjectID int64 `xorm:"INDEX NOT NULL"`
+	FieldID   int64 `xorm:"UNIQUE(s) NOT NULL"`
+	IssueID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+
+	Text     string `xorm:"TEXT"`
+	Number   float64
+	DateUnix timeutil.TimeStamp
+	OptionID int64
+
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName return the real table name
+func (FieldValue) TableName() string {
+	return "project_field_value"
+}
+
+func init() {
+	db.RegisterModel(new(Field))
+	db.RegisterModel(new(FieldOption))
+	db.RegisterModel(new(FieldValue))
+}
+
+// ParseFieldDate parses a date written as YYYY-MM-DD in the default location
+func ParseFieldDate(s string) (timeutil.TimeStamp, error) {
+	date, err := time.ParseInLocation(FieldDateLayout, strings.TrimSpace(s), setting.DefaultUILocation)
+	if err != nil {
+		return 0, util.NewInvalidArgumentErrorf("%q is not a date written as YYYY-MM-DD", s)
+	}
+	return timeutil.TimeStamp(date.Unix()), nil
+}
+
+// FormatStartDate returns the start date of an iteration
+func (o *FieldOption) FormatStartDate() string {
+	if o.StartUnix == 0 {
+		return ""
+	}
+	return o.StartUnix.AsLocalTime().Format(FieldDateLayout)
+}
+
+// FormatEndDate returns the end date of an iteration
+func (o *FieldOption) FormatEndDate() string {
+	if o.EndUnix == 0 {
+		return ""
+	}
+	return o.EndUnix.AsLocalTime().Format(FieldDateLayout)
+}
+
+// LoadOptions loads the options of the field, sorted
+func (f *Field) LoadOptions(ctx context.Context) error {
+	if !f.Type.HasOptions() || f.Options != nil {
+		return nil
+	}
+	f.Options = make([]*FieldOption, 0, 5)
+	return db.GetEngine(ctx).Where("field_id = ?", f.ID).OrderBy("sorting, id").Find(&f.Options)
+}
+
+// Option returns the option of the field with the given id, or nil
+func (f *Field) Option(id int64) *FieldOption {
+	for _, option := range f.Options {
+		if option.ID == id {
+			return option
+		}
+	}
+	return nil
+}
+
+// FormatValue returns the value as it is shown and edited, the date layout for the dates and the option id for the options
+func (f *Field) FormatValue(v *FieldValue) string {
+	if v == nil {
+		return ""
+	}
+	switch f.Type {
+	case FieldTypeNumber:
+		return strconv.FormatFloat(v.Number, 'f', -1, 64)
+	case FieldTypeDate:
+		return v.DateUnix.AsLocalTime().Format(FieldDateLayout)
+	case FieldTypeSingleSelect, FieldTypeIteration:
+		return strconv.FormatInt(v.OptionID, 10)
+	}
+	return v.Text
+}
+
+// DisplayValue returns the value as it is shown to the users, the options are shown by their names
+func (f *Field) DisplayValue(v *FieldValue) string {
+	if v == nil {
+		return ""
+	}
+	if f.Type.HasOptions() {
+		if option := f.Option(v.OptionID); option != nil {
+			return option.Name
+		}
+		return ""
+	}
+	return f.FormatValue(v)
+}
+
+// ParseValue parses a value of the field as it is edited, see FormatValue
+func (f *Field) ParseValue(raw string) (*FieldValue, error) {
+	raw = strings.TrimSpace(raw)
+	v := &FieldValue{ProjectID: f.ProjectID, FieldID: f.ID}
+	switch f.Type {
+	case FieldTypeText:
+		v.Text = raw
+	case FieldTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("%q is not a number", raw)
+		}
+		v.Number = number
+	case FieldTypeDate:
+		date, err := ParseFieldDate(raw)
+		if err != nil {
+			return nil, err
+		}
+		v.DateUnix = date
+	case FieldTypeSingleSelect, FieldTypeIteration:
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || f.Option(id) == nil {
+			return nil, util.NewInvalidArgumentErrorf("%q is not an option of the field %q", raw, f.Name)
+		}
+		v.OptionID = id
+	default:
+		return nil, util.NewInvalidArgumentErrorf("unknown field type %d", f.Type)
+	}
+	return v, nil
+}
+
+// CompareValues compares the values of the field for sorting, the missing values are sorted last
+func (f *Field) CompareValues(a, b *FieldValue) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	switch f.Type {
+	case FieldTypeNumber:
+		return compare(a.Number, b.Number)
+	case FieldTypeDate:
+		return compare(a.DateUnix, b.DateUnix)
+	case FieldTypeSingleSelect, FieldTypeIteration:
+		// the options are sorted in the order they are shown
+		return compare(f.optionIndex(a.OptionID), f.optionIndex(b.OptionID))
+	}
+	return compare(strings.ToLower(a.Text), strings.ToLower(b.Text))
+}
+
+// MatchValue returns true if the value equals the raw value as it is edited, see FormatValue,
+// or as it is shown for the options. The texts are matched case-insensitively
+// and an empty raw value matches the missing values.
+func (f *Field) MatchValue(v *FieldValue, raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if v == nil || raw == "" {
+		return v == nil && raw == ""
+	}
+	if f.Type == FieldTypeNumber {
+		number, err := strconv.ParseFloat(raw, 64)
+		return err == nil && number == v.Number
+	}
+	if f.Type.HasOptions() && strings.EqualFold(f.DisplayValue(v), raw) {
+		return true
+	}
+	return strings.EqualFold(f.FormatValue(v), raw)
+}
+
+func (f *Field) optionIndex(id int64) int {
+	for i, option := range f.Options {
+		if option.ID == id {
+			return i
+		}
+	}
+	return len(f.Options)
+}
+
+func compare[T int | float64 | string | timeutil.TimeStamp](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// FieldList is a list of fields
+type FieldList []*Field
+
+// GetField returns the field with the given id, or nil
+func (fields FieldList) GetField(id int64) *Field {
+	for _, field := range fields {
+		if field.ID == id {
+			return field
+		}
+	}
+	return nil
+}
+
+// GetFields returns the fields of a project with their options, sorted
+func GetFields(ctx context.Context, projectID int64) (FieldList, error) {
+	fields := make(FieldList, 0, 5)
+	if err := db.GetEngine(ctx).Where("project_id = ?", projectID).OrderBy("sorting, id").Find(&fields); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if err := field.LoadOptions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// GetFieldByID returns a field of a project with its options
+func GetFieldByID(ctx context.Context, projectID, fieldID int64) (*Field, error) {
+	field := &Field{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND project_id = ?", fieldID, projectID).Get(field)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectFieldNotExist{ID: fieldID}
+	}
+	return field, field.LoadOptions(ctx)
+}
+
+func validateFieldName(ctx context.Context, projectID, fieldID int64, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return util.NewInvalidArgumentErrorf("the name of a field can't be empty")
+	}
+	exist, err := db.GetEngine(ctx).Where("project_id = ? AND id <> ? AND name = ?", projectID, fieldID, name).Exist(new(Field))
+	if err != nil {
+		return err
+	} else if exist {
+		return util.NewInvalidArgumentErrorf("the project already has a field named %q", name)
+	}
+	return nil
+}
+
+// NewField creates a field with its options
+func NewField(ctx context.Context, field *Field) error {
+	if _, ok := fieldTypeNames[field.Type]; !ok {
+		return util.NewInvalidArgumentErrorf("unknown field type %d", field.Type)
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := validateFieldName(ctx, field.ProjectID, 0, field.Name); err != nil {
+			return err
+		}
+		if err := db.Insert(ctx, field); err != nil {
+			return err
+		}
+		options := field.Options
+		field.Options = nil
+		for _, option := range options {
+			if err := NewFieldOption(ctx, field, option); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateField updates the name and the sorting of a field
+func UpdateField(ctx context.Context, field *Field) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := validateFieldName(ctx, field.ProjectID, field.ID, field.Name); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(field.ID).Cols("name", "sorting").Update(field)
+		return err
+	})
+}
+
+// DeleteField deletes a field with its options and values
+func DeleteField(ctx context.Context, field *Field) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := clearViewsStartField(ctx, field.ID); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Where("field_id = ?", field.ID).Delete(new(FieldValue)); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Where("field_id = ?", field.ID).Delete(new(FieldOption)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(field.ID).Delete(new(Field))
+		return err
+	})
+}
+
+func validateFieldOption(field *Field, option *FieldOption) error {
+	if !field.Type.HasOptions() {
+		return util.NewInvalidArgumentErrorf("the field %q has no options", field.Name)
+	}
+	if strings.TrimSpace(option.Name) == "" {
+		return util.NewInvalidArgumentErrorf("the name of an option can't be empty")
+	}
+	if field.Type != FieldTypeIteration {
+		option.StartUnix, option.EndUnix = 0, 0
+	} else if option.StartUnix == 0 || option.EndUnix < option.StartUnix {
+		return util.NewInvalidArgumentErrorf("an iteration must start before it ends")
+	}
+	return nil
+}
+
+// NewFieldOption adds an option to a field, after its other options
+func NewFieldOption(ctx context.Context, field *Field, option *FieldOption) error {
+	if err := validateFieldOption(field, option); err != nil {
+		return err
+	}
+	if err := field.LoadOptions(ctx); err != nil {
+		return err
+	}
+	option.FieldID = field.ID
+	if option.Sorting == 0 {
+		option.Sorting = int64(len(field.Options)) + 1
+	}
+	if err := db.Insert(ctx, option); err != nil {
+		return err
+	}
+	field.Options = append(field.Options, option)
+	return nil
+}
+
+// UpdateFieldOption updates an option of a field
+func UpdateFieldOption(ctx context.Context, field *Field, option *FieldOption) error {
+	if err := validateFieldOption(field, option); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(option.ID).Cols("name", "color", "sorting", "start_unix", "end_unix").Update(option)
+	return err
+}
+
+// DeleteFieldOption deletes an option of a field, the values using it are removed
+func DeleteFieldOption(ctx context.Context, option *FieldOption) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("field_id = ? AND option_id = ?", option.FieldID, option.ID).Delete(new(FieldValue)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(option.ID).Delete(new(FieldOption))
+		return err
+	})
+}
+
+// FieldValueMap maps the ids of the issues to their values of the fields, by field id
+type FieldValueMap map[int64]map[int64]*FieldValue
+
+// Get returns the value of a field for an issue, or nil
+func (m FieldValueMap) Get(issueID, fieldID int64) *FieldValue {
+	return m[issueID][fieldID]
+}
+
+// GetFieldValues returns the values of the fields of a project, for all its issues if issueIDs is empty
+func GetFieldValues(ctx context.Context, projectID int64, issueIDs ...int64) (FieldValueMap, error) {
+	values := make([]*FieldValue, 0, 10)
+	sess := db.GetEngine(ctx).Where("project_id = ?", projectID)
+	if len(issueIDs) > 0 {
+		sess.In("issue_id", issueIDs)
+	}
+	if err := sess.Find(&values); err != nil {
+		return nil, err
+	}
+	m := make(FieldValueMap, len(values))
+	for _, v := range values {
+		if m[v.IssueID] == nil {
+			m[v.IssueID] = make(map[int64]*FieldValue)
+		}
+		m[v.IssueID][v.FieldID] = v
+	}
+	return m, nil
+}
+
+// SetFieldValue sets the value of a field for an issue of the project, an empty value removes it
+func SetFieldValue(ctx context.Context, field *Field, issueID int64, raw string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		inProject, err := db.GetEngine(ctx).Where("project_id = ? AND issue_id = ?", field.ProjectID, issueID).Exist(new(ProjectIssue))
+		if err != nil {
+			return err
+		} else if !inProject {
+			return util.NewInvalidArgumentErrorf("the issue is not in the project")
+		}
+
+		if _, err := db.GetEngine(ctx).Where("field_id = ? AND issue_id = ?", field.ID, issueID).Delete(new(FieldValue)); err != nil {
+			return err
+		}
+		if strings.TrimSpace(raw) == "" {
+			return nil
+		}
+		if err := field.LoadOptions(ctx); err != nil {
+			return err
+		}
+		value, err := field.ParseValue(raw)
+		if err != nil {
+			return err
+		}
+		value.IssueID = issueID
+		return db.Insert(ctx, value)
+	})
+}
+
+// DeleteIssueFieldValues deletes the values of the fields of the other projects than the given one for an issue
+func DeleteIssueFieldValues(ctx context.Context, issueID, keptProjectID int64) error {
+	_, err := db.GetEngine(ctx).Where("issue_id = ? AND project_id <> ?", issueID, keptProjectID).Delete(new(FieldValue))
+	return err
+}
+
+func deleteFieldsByProjectIDs(ctx context.Context, projectIDs builder.Cond) error {
+	if _, err := db.GetEngine(ctx).Where(builder.In("project_id", builder.Select("id").From("project").Where(projectIDs))).Delete(new(FieldValue)); err != nil {
+		return err
+	}
+	fieldIDs := builder.Select("id").From("project_field").Where(builder.In("project_id", builder.Select("id").From("project").Where(projectIDs)))
+	if _, err := db.GetEngine(ctx).Where(builder.In("field_id", fieldIDs)).Delete(new(FieldOption)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where(builder.In("project_id", builder.Select("id").From("project").Where(projectIDs))).Delete(new(Field))
+	return err
+}
+
+// SortIssueIDsByField sorts the ids of issues by their values of a field, the order of the issues with the same value is kept
+func SortIssueIDsByField(issueIDs []int64, field *Field, values FieldValueMap, desc bool) {
+	sort.SliceStable(issueIDs, func(i, j int) bool {
+		a, b := values.Get(issueIDs[i], field.ID), values.Get(issueIDs[j], field.ID)
+		if desc && a != nil && b != nil {
+			return field.CompareValues(b, a) < 0
+		}
+		return field.CompareValues(a, b) < 0
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFields(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	fields, err := GetFields(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, fields, 2) {
+		assert.Equal(t, "Status", fields[0].Name)
+		assert.Equal(t, FieldTypeSingleSelect, fields[0].Type)
+		assert.Len(t, fields[0].Options, 3)
+		assert.Equal(t, "Estimate", fields[1].Name)
+		assert.Nil(t, fields[1].Options)
+	}
+
+	_, err = GetFieldByID(db.DefaultContext, 2, 1)
+	assert.True(t, IsErrProjectFieldNotExist(err))
+}
+
+func TestNewField(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	field := &Field{
+		ProjectID: 1,
+		Name:      "Sprint",
+		Type:      FieldTypeIteration,
+		Options: []*FieldOption{
+			{Name: "Sprint 1", StartUnix: 1688947200, EndUnix: 1690156800},
+		},
+	}
+	assert.NoError(t, NewField(db.DefaultContext, field))
+	unittest.AssertExistsAndLoadBean(t, &FieldOption{FieldID: field.ID, Name: "Sprint 1", Sorting: 1})
+
+	// the names are unique in a project
+	err := NewField(db.DefaultContext, &Field{ProjectID: 1, Name: "Status", Type: FieldTypeText})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	// an iteration must have dates
+	err = NewFieldOption(db.DefaultContext, field, &FieldOption{Name: "Sprint 2"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestSetFieldValue(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	status, err := GetFieldByID(db.DefaultContext, 1, 1)
+	assert.NoError(t, err)
+	estimate, err := GetFieldByID(db.DefaultContext, 1, 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SetFieldValue(db.DefaultContext, status, 1, "3"))
+	unittest.AssertExistsAndLoadBean(t, &FieldValue{FieldID: 1, IssueID: 1, OptionID: 3})
+
+	assert.NoError(t, SetFieldValue(db.DefaultContext, estimate, 1, "1.5"))
+	unittest.AssertExistsAndLoadBean(t, &FieldValue{FieldID: 2, IssueID: 1, Number: 1.5})
+
+	// an empty value removes it
+	assert.NoError(t, SetFieldValue(db.DefaultContext, estimate, 1, ""))
+	unittest.AssertNotExistsBean(t, &FieldValue{FieldID: 2, IssueID: 1})
+
+	assert.ErrorIs(t, SetFieldValue(db.DefaultContext, status, 1, "42"), util.ErrInvalidArgument)
+	assert.ErrorIs(t, SetFieldValue(db.DefaultContext, estimate, 1, "a lot"), util.ErrInvalidArgument)
+	// issue 4 is not in the project
+	assert.ErrorIs(t, SetFieldValue(db.DefaultContext, estimate, 4, "1"), util.ErrInvalidArgument)
+}
+
+func TestDeleteFieldOption(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, DeleteFieldOption(db.DefaultContext, &FieldOption{ID: 2, FieldID: 1}))
+	unittest.AssertNotExistsBean(t, &FieldOption{ID: 2})
+	unittest.AssertNotExistsBean(t, &FieldValue{ID: 1})
+	unittest.AssertExistsAndLoadBean(t, &FieldValue{ID: 3})
+}
+
+func TestDeleteProjectFields(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, DeleteProjectByID(db.DefaultContext, 1))
+	unittest.AssertCount(t, &Field{}, 0)
+	unittest.AssertCount(t, &FieldOption{}, 0)
+	unittest.AssertCount(t, &FieldValue{}, 0)
+}
+
+func TestSortIssueIDsByField(t *testing.T) {
+	status := &Field{ID: 1, Type: FieldTypeSingleSelect, Options: []*FieldOption{{ID: 3}, {ID: 1}, {ID: 2}}}
+	due := &Field{ID: 2, Type: FieldTypeDate}
+	values := FieldValueMap{
+		1: {1: {OptionID: 1}, 2: {DateUnix: timeutil.TimeStamp(300)}},
+		2: {1: {OptionID: 2}, 2: {DateUnix: timeutil.TimeStamp(100)}},
+		3: {1: {OptionID: 3}},
+	}
+
+	ids := []int64{1, 2, 3, 4}
+	SortIssueIDsByField(ids, status, values, false)
+	assert.Equal(t, []int64{3, 1, 2, 4}, ids)
+
+	ids = []int64{1, 2, 3, 4}
+	SortIssueIDsByField(ids, due, values, false)
+	assert.Equal(t, []int64{2, 1, 3, 4}, ids)
+
+	// the issues without a value stay last
+	SortIssueIDsByField(ids, due, values, true)
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids)
+}
@@ -17,8 +17,13 @@ func TestMain(m *testing.M) {
 		GiteaRootPath: filepath.Join("..", ".."),
 		FixtureFiles: []string{
 			"project.yml",
+			"project_automation.yml",
 			"project_board.yml",
+			"project_field.yml",
+			"project_field_option.yml",
+			"project_field_value.yml",
 			"project_issue.yml",
+			"project_view.yml",
 			"repository.yml",
 		},
 	})
@@ -432,6 +432,18 @@ func DeleteProjectByID(ctx context.Context, id int64) error {
 			return err
 		}
 
+		if err := deleteFieldsByProjectIDs(ctx, builder.Eq{"id": id}); err != nil {
+			return err
+		}
+
+		if err := deleteViewsByProjectIDs(ctx, builder.Eq{"id": id}); err != nil {
+			return err
+		}
+
+		if err := deleteAutomationsByProjectIDs(ctx, builder.Eq{"id": id}); err != nil {
+			return err
+		}
+
 		if _, err = db.GetEngine(ctx).ID(p.ID).Delete(new(Project)); err != nil {
 			return err
 		}
@@ -441,6 +453,16 @@ func DeleteProjectByID(ctx context.Context, id int64) error {
 }
 
 func DeleteProjectByRepoID(ctx context.Context, repoID int64) error {
+	if err := deleteFieldsByProjectIDs(ctx, builder.Eq{"repo_id": repoID}); err != nil {
+		return err
+	}
+	if err := deleteViewsByProjectIDs(ctx, builder.Eq{"repo_id": repoID}); err != nil {
+		return err
+	}
+	if err := deleteAutomationsByProjectIDs(ctx, builder.Eq{"repo_id": repoID}); err != nil {
+		return err
+	}
+
 	switch {
 	case setting.Database.Type.IsSQLite3():
 		if _, err := db.GetEngine(ctx).Exec("DELETE FROM project_issue WHERE project_issue.id IN (SELECT project_issue.id FROM project_issue INNER JOIN project WHERE project.id = project_issue.project_id AND project.repo_id = ?)", repoID); err != nil {
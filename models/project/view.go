@@ -0,0 +1,202 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ViewType is the layout of a saved view of a project, the board is the default view and is not saved
+type ViewType uint8
+
+const (
+	// ViewTypeTable shows the issues of the project as the rows of a table
+	ViewTypeTable ViewType = iota + 1
+	// ViewTypeRoadmap shows the issues of the project on a timeline, from their start to their due date
+	ViewTypeRoadmap
+)
+
+var viewTypeNames = map[ViewType]string{
+	ViewTypeTable:   "table",
+	ViewTypeRoadmap: "roadmap",
+}
+
+// ViewTypes returns all the view types
+func ViewTypes() []ViewType {
+	return []ViewType{ViewTypeTable, ViewTypeRoadmap}
+}
+
+// String returns the name of the view type
+func (t ViewType) String() string {
+	return viewTypeNames[t]
+}
+
+// ParseViewType returns the view type of the given name
+func ParseViewType(name string) (ViewType, bool) {
+	for t, n := range viewTypeNames {
+		if n == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// The issue attributes a view can group or sort its issues by
+const (
+	ViewKeyLabel     = "label"
+	ViewKeyAssignee  = "assignee"
+	ViewKeyMilestone = "milestone"
+	ViewKeyBoard     = "board"
+	ViewKeyTitle     = "title"
+	ViewKeyCreated   = "created"
+	ViewKeyDeadline  = "deadline"
+)
+
+// ViewGroupKeys returns the keys the issues of a view can be grouped by
+func ViewGroupKeys() []string {
+	return []string{ViewKeyLabel, ViewKeyAssignee, ViewKeyMilestone, ViewKeyBoard}
+}
+
+// ViewSortKeys returns the keys the issues of a view can be sorted by
+func ViewSortKeys() []string {
+	return []string{ViewKeyTitle, ViewKeyCreated, ViewKeyDeadline, ViewKeyLabel, ViewKeyAssignee, ViewKeyMilestone, ViewKeyBoard}
+}
+
+// ErrProjectViewNotExist represents a "ProjectViewNotExist" kind of error.
+type ErrProjectViewNotExist struct {
+	ID int64
+}
+
+// IsErrProjectViewNotExist checks if an error is a ErrProjectViewNotExist
+func IsErrProjectViewNotExist(err error) bool {
+	_, ok := err.(ErrProjectViewNotExist)
+	return ok
+}
+
+func (err ErrProjectViewNotExist) Error() string {
+	return fmt.Sprintf("project view does not exist [id: %d]", err.ID)
+}
+
+func (err ErrProjectViewNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// View is a saved view of the issues of a project
+type View struct {
+	ID        int64    `xorm:"pk autoincr"`
+	ProjectID int64    `xorm:"INDEX NOT NULL"`
+	Name      string   `xorm:"VARCHAR(255) NOT NULL"`
+	Type      ViewType `xorm:"NOT NULL"`
+	Sorting   int64    `xorm:"NOT NULL DEFAULT 0"`
+
+	GroupBy  string `xorm:"VARCHAR(20)"`
+	SortBy   string `xorm:"VARCHAR(20)"`
+	SortDesc bool   `xorm:"NOT NULL DEFAULT false"`
+
+	// the date field of the project holding the start dates of the issues on a roadmap,
+	// the issues start when they are created without it
+	StartFieldID int64
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// TableName return the real table name
+func (View) TableName() string {
+	return "project_view"
+}
+
+func init() {
+	db.RegisterModel(new(View))
+}
+
+// GetViews returns the saved views of a project, sorted
+func GetViews(ctx context.Context, projectID int64) ([]*View, error) {
+	views := make([]*View, 0, 5)
+	return views, db.GetEngine(ctx).Where("project_id = ?", projectID).OrderBy("sorting, id").Find(&views)
+}
+
+// GetViewByID returns a saved view of a project
+func GetViewByID(ctx context.Context, projectID, viewID int64) (*View, error) {
+	view := &View{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND project_id = ?", viewID, projectID).Get(view)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProjectViewNotExist{ID: viewID}
+	}
+	return view, nil
+}
+
+func validateView(ctx context.Context, view *View) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return util.NewInvalidArgumentErrorf("the name of a view can't be empty")
+	}
+	if _, ok := viewTypeNames[view.Type]; !ok {
+		return util.NewInvalidArgumentErrorf("unknown view type %d", view.Type)
+	}
+	if view.GroupBy != "" && !util.SliceContainsString(ViewGroupKeys(), view.GroupBy) {
+		return util.NewInvalidArgumentErrorf("the issues can't be grouped by %q", view.GroupBy)
+	}
+	if view.SortBy != "" && !util.SliceContainsString(ViewSortKeys(), view.SortBy) {
+		return util.NewInvalidArgumentErrorf("the issues can't be sorted by %q", view.SortBy)
+	}
+	if view.Type != ViewTypeRoadmap {
+		view.StartFieldID = 0
+	} else if view.StartFieldID > 0 {
+		field, err := GetFieldByID(ctx, view.ProjectID, view.StartFieldID)
+		if err != nil {
+			if IsErrProjectFieldNotExist(err) {
+				return util.NewInvalidArgumentErrorf("the start field doesn't exist")
+			}
+			return err
+		}
+		if field.Type != FieldTypeDate {
+			return util.NewInvalidArgumentErrorf("the start field %q is not a date field", field.Name)
+		}
+	}
+	return nil
+}
+
+// NewView saves a view of a project
+func NewView(ctx context.Context, view *View) error {
+	if err := validateView(ctx, view); err != nil {
+		return err
+	}
+	return db.Insert(ctx, view)
+}
+
+// UpdateView updates a saved view of a project
+func UpdateView(ctx context.Context, view *View) error {
+	if err := validateView(ctx, view); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(view.ID).Cols("name", "type", "sorting", "group_by", "sort_by", "sort_desc", "start_field_id").Update(view)
+	return err
+}
+
+// DeleteView deletes a saved view of a project
+func DeleteView(ctx context.Context, view *View) error {
+	_, err := db.GetEngine(ctx).ID(view.ID).Delete(new(View))
+	return err
+}
+
+func deleteViewsByProjectIDs(ctx context.Context, projectIDs builder.Cond) error {
+	_, err := db.GetEngine(ctx).Where(builder.In("project_id", builder.Select("id").From("project").Where(projectIDs))).Delete(new(View))
+	return err
+}
+
+// clearViewsStartField makes the roadmaps starting at a deleted field start with the creation of the issues
+func clearViewsStartField(ctx context.Context, fieldID int64) error {
+	_, err := db.GetEngine(ctx).Where("start_field_id = ?", fieldID).Cols("start_field_id").Update(&View{})
+	return err
+}
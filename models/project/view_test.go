@@ -0,0 +1,55 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetViews(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	views, err := GetViews(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, views, 1) {
+		assert.Equal(t, ViewTypeTable, views[0].Type)
+		assert.Equal(t, ViewKeyLabel, views[0].GroupBy)
+	}
+
+	_, err = GetViewByID(db.DefaultContext, 2, 1)
+	assert.True(t, IsErrProjectViewNotExist(err))
+}
+
+func TestNewView(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	due := &Field{ProjectID: 1, Name: "Start", Type: FieldTypeDate}
+	assert.NoError(t, NewField(db.DefaultContext, due))
+
+	view := &View{ProjectID: 1, Name: "Roadmap", Type: ViewTypeRoadmap, GroupBy: ViewKeyMilestone, StartFieldID: due.ID}
+	assert.NoError(t, NewView(db.DefaultContext, view))
+	unittest.AssertExistsAndLoadBean(t, &View{ID: view.ID, StartFieldID: due.ID})
+
+	// the start field must be a date field of the project
+	err := NewView(db.DefaultContext, &View{ProjectID: 1, Name: "Roadmap", Type: ViewTypeRoadmap, StartFieldID: 2})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	err = NewView(db.DefaultContext, &View{ProjectID: 1, Name: "Table", Type: ViewTypeTable, GroupBy: "title"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	// the roadmap starts with the creation of the issues once its start field is deleted
+	assert.NoError(t, DeleteField(db.DefaultContext, due))
+	view, err = GetViewByID(db.DefaultContext, 1, view.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, view.StartFieldID)
+
+	assert.NoError(t, DeleteProjectByID(db.DefaultContext, 1))
+	unittest.AssertNotExistsBean(t, &View{ProjectID: 1})
+}
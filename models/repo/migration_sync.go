@@ -0,0 +1,209 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrMigrationSyncNotExist migration sync does not exist error
+var ErrMigrationSyncNotExist = util.NewNotExistErrorf("MigrationSync does not exist")
+
+// MigrationSync periodically downloads again what was updated in the original repository of a migrated repository
+type MigrationSync struct {
+	ID                    int64       `xorm:"pk autoincr"`
+	RepoID                int64       `xorm:"UNIQUE NOT NULL"`
+	Repo                  *Repository `xorm:"-"`
+	DoerID                int64       `xorm:"NOT NULL DEFAULT 0"`
+	GitServiceType        api.GitServiceType
+	CloneAddr             string `xorm:"VARCHAR(2048)"`
+	AuthUsername          string
+	AuthPasswordEncrypted string `xorm:"TEXT"`
+	AuthTokenEncrypted    string `xorm:"TEXT"`
+
+	Milestones   bool `xorm:"NOT NULL DEFAULT false"`
+	Labels       bool `xorm:"NOT NULL DEFAULT false"`
+	Issues       bool `xorm:"NOT NULL DEFAULT false"`
+	Comments     bool `xorm:"NOT NULL DEFAULT false"`
+	PullRequests bool `xorm:"NOT NULL DEFAULT false"`
+	Releases     bool `xorm:"NOT NULL DEFAULT false"`
+
+	Interval time.Duration
+	// MigratedUnix is when the repository was migrated, the issues and comments created before it are the copies
+	// made by the migration of the upstream ones with the same index or creation time
+	MigratedUnix timeutil.TimeStamp
+	// LastSyncUnix is when the last successful sync started, the next sync downloads what was updated since then
+	LastSyncUnix timeutil.TimeStamp
+	NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+	LastError    string             `xorm:"TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+// MigrationSyncRefType is the type of the upstream records mapped by a MigrationSyncRef
+type MigrationSyncRefType string
+
+const (
+	// MigrationSyncRefIssue maps the number of an upstream issue or pull request to the index of the local one
+	MigrationSyncRefIssue MigrationSyncRefType = "issue"
+	// MigrationSyncRefComment maps the ID of an upstream comment to the ID of the local one
+	MigrationSyncRefComment MigrationSyncRefType = "comment"
+	// MigrationSyncRefReview maps the ID of an upstream review to the ID of the local one
+	MigrationSyncRefReview MigrationSyncRefType = "review"
+)
+
+// MigrationSyncRef maps an upstream record to the local record a sync of the repository created or matched for it
+type MigrationSyncRef struct {
+	ID        int64                `xorm:"pk autoincr"`
+	RepoID    int64                `xorm:"UNIQUE(s) NOT NULL"`
+	Type      MigrationSyncRefType `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+	ForeignID int64                `xorm:"UNIQUE(s) NOT NULL"`
+	LocalID   int64                `xorm:"NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(MigrationSync))
+	db.RegisterModel(new(MigrationSyncRef))
+}
+
+// GetRepository returns the repository of the migration sync
+func (m *MigrationSync) GetRepository(ctx context.Context) (*Repository, error) {
+	if m.Repo != nil {
+		return m.Repo, nil
+	}
+	var err error
+	m.Repo, err = GetRepositoryByID(ctx, m.RepoID)
+	return m.Repo, err
+}
+
+// SetAuth encrypts the password and the token used to access the original repository
+func (m *MigrationSync) SetAuth(username, password, token string) (err error) {
+	m.AuthUsername = username
+	if m.AuthPasswordEncrypted, err = secret.EncryptSecret(setting.SecretKey, password); err != nil {
+		return err
+	}
+	m.AuthTokenEncrypted, err = secret.EncryptSecret(setting.SecretKey, token)
+	return err
+}
+
+// DecryptAuth returns the password and the token used to access the original repository
+func (m *MigrationSync) DecryptAuth() (password, token string, err error) {
+	if m.AuthPasswordEncrypted != "" {
+		if password, err = secret.DecryptSecret(setting.SecretKey, m.AuthPasswordEncrypted); err != nil {
+			return "", "", err
+		}
+	}
+	if m.AuthTokenEncrypted != "" {
+		if token, err = secret.DecryptSecret(setting.SecretKey, m.AuthTokenEncrypted); err != nil {
+			return "", "", err
+		}
+	}
+	return password, token, nil
+}
+
+// ScheduleNextSync calculates and sets next sync time.
+func (m *MigrationSync) ScheduleNextSync() {
+	if m.Interval != 0 {
+		m.NextSyncUnix = timeutil.TimeStampNow().AddDuration(m.Interval)
+	} else {
+		m.NextSyncUnix = 0
+	}
+}
+
+// InsertMigrationSync inserts the migration sync of a repository, its first sync is due immediately
+func InsertMigrationSync(ctx context.Context, m *MigrationSync) error {
+	m.NextSyncUnix = timeutil.TimeStampNow()
+	return db.Insert(ctx, m)
+}
+
+// UpdateMigrationSync updates the migration sync
+func UpdateMigrationSync(ctx context.Context, m *MigrationSync) error {
+	_, err := db.GetEngine(ctx).ID(m.ID).AllCols().Update(m)
+	return err
+}
+
+// GetMigrationSyncByID returns a migration sync
+func GetMigrationSyncByID(ctx context.Context, id int64) (*MigrationSync, error) {
+	m := &MigrationSync{}
+	has, err := db.GetEngine(ctx).ID(id).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMigrationSyncNotExist
+	}
+	return m, nil
+}
+
+// GetMigrationSyncByRepoID returns the migration sync of a repository
+func GetMigrationSyncByRepoID(ctx context.Context, repoID int64) (*MigrationSync, error) {
+	m := &MigrationSync{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMigrationSyncNotExist
+	}
+	return m, nil
+}
+
+// DeleteMigrationSync deletes the migration sync of a repository and its references
+func DeleteMigrationSync(ctx context.Context, repoID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(&MigrationSync{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(&MigrationSyncRef{})
+		return err
+	})
+}
+
+// MigrationSyncsIterate iterates the migration syncs which are due
+func MigrationSyncsIterate(ctx context.Context, limit int, f func(idx int, bean any) error) error {
+	sess := db.GetEngine(ctx).
+		Where("next_sync_unix<=?", time.Now().Unix()).
+		And("next_sync_unix!=0").
+		OrderBy("next_sync_unix ASC")
+	if limit > 0 {
+		sess = sess.Limit(limit)
+	}
+	return sess.Iterate(new(MigrationSync), f)
+}
+
+// GetMigrationSyncRefs returns the local IDs of the given upstream records of a repository by their foreign ID
+func GetMigrationSyncRefs(ctx context.Context, repoID int64, tp MigrationSyncRefType, foreignIDs []int64) (map[int64]int64, error) {
+	refs := make([]*MigrationSyncRef, 0, len(foreignIDs))
+	if len(foreignIDs) > 0 {
+		if err := db.GetEngine(ctx).
+			Where(builder.Eq{"repo_id": repoID, "type": tp}).
+			And(builder.In("foreign_id", foreignIDs)).
+			Find(&refs); err != nil {
+			return nil, err
+		}
+	}
+	localIDs := make(map[int64]int64, len(refs))
+	for _, ref := range refs {
+		localIDs[ref.ForeignID] = ref.LocalID
+	}
+	return localIDs, nil
+}
+
+// InsertMigrationSyncRef records the local record of an upstream record
+func InsertMigrationSyncRef(ctx context.Context, repoID int64, tp MigrationSyncRefType, foreignID, localID int64) error {
+	return db.Insert(ctx, &MigrationSyncRef{
+		RepoID:    repoID,
+		Type:      tp,
+		ForeignID: foreignID,
+		LocalID:   localID,
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationSync(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	m := &repo_model.MigrationSync{
+		RepoID:    1,
+		DoerID:    2,
+		CloneAddr: "https://github.com/go-gitea/test_repo",
+		Issues:    true,
+		Interval:  time.Hour,
+	}
+	assert.NoError(t, m.SetAuth("user", "", "token"))
+	assert.NoError(t, repo_model.InsertMigrationSync(db.DefaultContext, m))
+
+	loaded, err := repo_model.GetMigrationSyncByRepoID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, m.ID, loaded.ID)
+	password, token, err := loaded.DecryptAuth()
+	assert.NoError(t, err)
+	assert.Empty(t, password)
+	assert.Equal(t, "token", token)
+
+	var due []int64
+	assert.NoError(t, repo_model.MigrationSyncsIterate(db.DefaultContext, 0, func(idx int, bean any) error {
+		due = append(due, bean.(*repo_model.MigrationSync).ID)
+		return nil
+	}))
+	assert.Equal(t, []int64{m.ID}, due)
+
+	loaded.ScheduleNextSync()
+	assert.NoError(t, repo_model.UpdateMigrationSync(db.DefaultContext, loaded))
+	due = due[:0]
+	assert.NoError(t, repo_model.MigrationSyncsIterate(db.DefaultContext, 0, func(idx int, bean any) error {
+		due = append(due, bean.(*repo_model.MigrationSync).ID)
+		return nil
+	}))
+	assert.Empty(t, due)
+
+	assert.NoError(t, repo_model.InsertMigrationSyncRef(db.DefaultContext, 1, repo_model.MigrationSyncRefIssue, 42, 6))
+	assert.NoError(t, repo_model.InsertMigrationSyncRef(db.DefaultContext, 1, repo_model.MigrationSyncRefComment, 42, 100))
+	refs, err := repo_model.GetMigrationSyncRefs(db.DefaultContext, 1, repo_model.MigrationSyncRefIssue, []int64{42, 43})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{42: 6}, refs)
+
+	assert.NoError(t, repo_model.DeleteMigrationSync(db.DefaultContext, 1))
+	_, err = repo_model.GetMigrationSyncByID(db.DefaultContext, m.ID)
+	assert.ErrorIs(t, err, repo_model.ErrMigrationSyncNotExist)
+	unittest.AssertNotExistsBean(t, &repo_model.MigrationSyncRef{RepoID: 1})
+}
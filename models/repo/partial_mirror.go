@@ -0,0 +1,189 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrPartialMirrorNotExist partial mirror does not exist error
+var ErrPartialMirrorNotExist = util.NewNotExistErrorf("PartialMirror does not exist")
+
+// DivergenceStrategy is what a partial mirror does when a target branch has commits which aren't upstream
+type DivergenceStrategy string
+
+const (
+	// DivergenceSkip leaves the diverged branch untouched
+	DivergenceSkip DivergenceStrategy = "skip"
+	// DivergenceForce overwrites the diverged branch with the upstream branch
+	DivergenceForce DivergenceStrategy = "force"
+	// DivergencePullRequest pushes the upstream branch to a sync branch and opens a pull request into the diverged branch
+	DivergencePullRequest DivergenceStrategy = "pull_request"
+)
+
+// IsValid returns whether the strategy is known
+func (s DivergenceStrategy) IsValid() bool {
+	switch s {
+	case DivergenceSkip, DivergenceForce, DivergencePullRequest:
+		return true
+	}
+	return false
+}
+
+// PartialMirror pulls some branches of a remote repository into branches of a normal, writable repository
+type PartialMirror struct {
+	ID            int64       `xorm:"pk autoincr"`
+	RepoID        int64       `xorm:"INDEX NOT NULL"`
+	Repo          *Repository `xorm:"-"`
+	CreatorID     int64       `xorm:"NOT NULL DEFAULT 0"`
+	RemoteName    string
+	RemoteAddress string `xorm:"VARCHAR(2048)"`
+
+	// BranchMappings are newline separated "upstream:target" branch names, a mapping without target keeps the
+	// name of the upstream branch. Both sides may contain one "*" which matches any part of a branch name.
+	BranchMappings string             `xorm:"TEXT"`
+	Divergence     DivergenceStrategy `xorm:"VARCHAR(20) NOT NULL DEFAULT 'skip'"`
+	LFS            bool               `xorm:"lfs_enabled NOT NULL DEFAULT false"`
+	LFSEndpoint    string             `xorm:"lfs_endpoint TEXT"`
+
+	Interval       time.Duration
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"INDEX"`
+	NextUpdateUnix timeutil.TimeStamp `xorm:"INDEX"`
+	LastError      string             `xorm:"TEXT"`
+	// DivergedBranches are the target branches which diverged from upstream during the last sync
+	DivergedBranches []string `xorm:"JSON TEXT"`
+}
+
+func init() {
+	db.RegisterModel(new(PartialMirror))
+}
+
+// BranchMapping maps the upstream branches matched by Source to the Target branches of the repository
+type BranchMapping struct {
+	Source string
+	Target string
+}
+
+// ParseBranchMappings parses and validates newline separated "upstream:target" branch mappings
+func ParseBranchMappings(s string) ([]BranchMapping, error) {
+	var mappings []BranchMapping
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		source, target, ok := strings.Cut(line, ":")
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		if !ok {
+			target = source
+		}
+		if strings.Count(source, "*") > 1 || strings.Count(source, "*") != strings.Count(target, "*") {
+			return nil, util.NewInvalidArgumentErrorf("invalid branch mapping %q: both sides must have the same single wildcard", line)
+		}
+		for _, name := range []string{source, target} {
+			if name == "" || !git.IsValidRefPattern(strings.Replace(name, "*", "x", 1)) {
+				return nil, util.NewInvalidArgumentErrorf("invalid branch mapping %q: %q is not a valid branch name", line, name)
+			}
+		}
+		mappings = append(mappings, BranchMapping{Source: source, Target: target})
+	}
+	if len(mappings) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no branch mapping")
+	}
+	return mappings, nil
+}
+
+// Match returns the target branch of an upstream branch and whether the mapping matches it
+func (bm BranchMapping) Match(branch string) (string, bool) {
+	prefix, suffix, hasWildcard := strings.Cut(bm.Source, "*")
+	if !hasWildcard {
+		return bm.Target, branch == bm.Source
+	}
+	if len(branch) <= len(prefix)+len(suffix) || !strings.HasPrefix(branch, prefix) || !strings.HasSuffix(branch, suffix) {
+		return "", false
+	}
+	return strings.Replace(bm.Target, "*", branch[len(prefix):len(branch)-len(suffix)], 1), true
+}
+
+// Refspec returns the refspec which fetches the upstream branches of the mapping to the remote-tracking refs of the mirror
+func (bm BranchMapping) Refspec(remoteName string) string {
+	return fmt.Sprintf("+%s%s:refs/remotes/%s/%s", git.BranchPrefix, bm.Source, remoteName, bm.Source)
+}
+
+// GetRepository returns the repository of the partial mirror
+func (m *PartialMirror) GetRepository(ctx context.Context) (*Repository, error) {
+	if m.Repo != nil {
+		return m.Repo, nil
+	}
+	var err error
+	m.Repo, err = GetRepositoryByID(ctx, m.RepoID)
+	return m.Repo, err
+}
+
+// ScheduleNextUpdate calculates and sets next update time.
+func (m *PartialMirror) ScheduleNextUpdate() {
+	if m.Interval != 0 {
+		m.NextUpdateUnix = timeutil.TimeStampNow().AddDuration(m.Interval)
+	} else {
+		m.NextUpdateUnix = 0
+	}
+}
+
+// InsertPartialMirror inserts a partial mirror, its first sync is due immediately
+func InsertPartialMirror(ctx context.Context, m *PartialMirror) error {
+	m.UpdatedUnix = timeutil.TimeStampNow()
+	m.NextUpdateUnix = m.UpdatedUnix
+	return db.Insert(ctx, m)
+}
+
+// UpdatePartialMirror updates the partial mirror
+func UpdatePartialMirror(ctx context.Context, m *PartialMirror) error {
+	_, err := db.GetEngine(ctx).ID(m.ID).AllCols().Update(m)
+	return err
+}
+
+// GetPartialMirrorByID returns a partial mirror
+func GetPartialMirrorByID(ctx context.Context, id int64) (*PartialMirror, error) {
+	m := &PartialMirror{}
+	has, err := db.GetEngine(ctx).ID(id).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPartialMirrorNotExist
+	}
+	return m, nil
+}
+
+// GetPartialMirrorsByRepoID returns the partial mirrors of a repository
+func GetPartialMirrorsByRepoID(ctx context.Context, repoID int64) ([]*PartialMirror, error) {
+	mirrors := make([]*PartialMirror, 0, 2)
+	return mirrors, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("id").Find(&mirrors)
+}
+
+// DeletePartialMirror deletes a partial mirror of a repository
+func DeletePartialMirror(ctx context.Context, repoID, id int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ? AND id = ?", repoID, id).Delete(&PartialMirror{})
+	return err
+}
+
+// PartialMirrorsIterate iterates the partial mirrors which are due for a sync
+func PartialMirrorsIterate(ctx context.Context, limit int, f func(idx int, bean any) error) error {
+	sess := db.GetEngine(ctx).
+		Where("next_update_unix<=?", time.Now().Unix()).
+		And("next_update_unix!=0").
+		OrderBy("updated_unix ASC")
+	if limit > 0 {
+		sess = sess.Limit(limit)
+	}
+	return sess.Iterate(new(PartialMirror), f)
+}
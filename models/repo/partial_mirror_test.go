@@ -0,0 +1,90 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBranchMappings(t *testing.T) {
+	mappings, err := repo_model.ParseBranchMappings("main:vendor/main\n\n  release/* : vendor/release/*  \ndevelop\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []repo_model.BranchMapping{
+		{Source: "main", Target: "vendor/main"},
+		{Source: "release/*", Target: "vendor/release/*"},
+		{Source: "develop", Target: "develop"},
+	}, mappings)
+
+	for _, s := range []string{"", "\n", "main:", ":main", "release/*:vendor", "*/*:*/*", "main:bad..name"} {
+		_, err := repo_model.ParseBranchMappings(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBranchMappingMatch(t *testing.T) {
+	cases := []struct {
+		mapping repo_model.BranchMapping
+		branch  string
+		target  string
+		ok      bool
+	}{
+		{repo_model.BranchMapping{Source: "main", Target: "vendor/main"}, "main", "vendor/main", true},
+		{repo_model.BranchMapping{Source: "main", Target: "vendor/main"}, "main2", "", false},
+		{repo_model.BranchMapping{Source: "release/*", Target: "vendor/release/*"}, "release/1.0", "vendor/release/1.0", true},
+		{repo_model.BranchMapping{Source: "release/*", Target: "vendor/release/*"}, "release/", "", false},
+		{repo_model.BranchMapping{Source: "release/*", Target: "vendor/release/*"}, "feature/1.0", "", false},
+		{repo_model.BranchMapping{Source: "v*-lts", Target: "lts/*"}, "v1.20-lts", "lts/1.20", true},
+	}
+	for _, c := range cases {
+		target, ok := c.mapping.Match(c.branch)
+		assert.Equal(t, c.ok, ok, c.branch)
+		if c.ok {
+			assert.Equal(t, c.target, target, c.branch)
+		}
+	}
+
+	assert.Equal(t, "+refs/heads/release/*:refs/remotes/upstream/release/*", repo_model.BranchMapping{Source: "release/*", Target: "vendor/*"}.Refspec("upstream"))
+}
+
+func TestPartialMirror(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	m := &repo_model.PartialMirror{
+		RepoID:         1,
+		RemoteName:     "partial_mirror_test",
+		BranchMappings: "main:vendor/main",
+		Divergence:     repo_model.DivergencePullRequest,
+	}
+	assert.NoError(t, repo_model.InsertPartialMirror(db.DefaultContext, m))
+
+	m.DivergedBranches = []string{"vendor/main"}
+	assert.NoError(t, repo_model.UpdatePartialMirror(db.DefaultContext, m))
+
+	loaded, err := repo_model.GetPartialMirrorByID(db.DefaultContext, m.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, repo_model.DivergencePullRequest, loaded.Divergence)
+	assert.Equal(t, []string{"vendor/main"}, loaded.DivergedBranches)
+
+	var due []int64
+	assert.NoError(t, repo_model.PartialMirrorsIterate(db.DefaultContext, 0, func(idx int, bean any) error {
+		due = append(due, bean.(*repo_model.PartialMirror).ID)
+		return nil
+	}))
+	assert.Equal(t, []int64{m.ID}, due)
+
+	assert.NoError(t, repo_model.DeletePartialMirror(db.DefaultContext, 2, m.ID))
+	mirrors, err := repo_model.GetPartialMirrorsByRepoID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Len(t, mirrors, 1)
+
+	assert.NoError(t, repo_model.DeletePartialMirror(db.DefaultContext, 1, m.ID))
+	_, err = repo_model.GetPartialMirrorByID(db.DefaultContext, m.ID)
+	assert.ErrorIs(t, err, repo_model.ErrPartialMirrorNotExist)
+}
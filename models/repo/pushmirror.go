@@ -5,13 +5,16 @@ package repo
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/gobwas/glob"
 	"xorm.io/builder"
 )
 
@@ -31,6 +34,99 @@ type PushMirror struct {
 	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
 	LastUpdateUnix timeutil.TimeStamp `xorm:"INDEX last_update"`
 	LastError      string             `xorm:"text"`
+
+	// RefIncludes and RefExcludes are newline separated glob patterns of the branches and tags to push, all of
+	// them are pushed if there are no include patterns
+	RefIncludes string `xorm:"TEXT"`
+	RefExcludes string `xorm:"TEXT"`
+	// NonDestructive mirrors never delete or force-update the refs of the remote
+	NonDestructive bool `xorm:"NOT NULL DEFAULT false"`
+
+	globsLoaded  bool      `xorm:"-"`
+	includeGlobs []refGlob `xorm:"-"`
+	excludeGlobs []refGlob `xorm:"-"`
+}
+
+// refGlob matches the full name of a ref if its pattern starts with "refs/", the short name of a branch or tag otherwise
+type refGlob struct {
+	fullName bool
+	glob     glob.Glob
+}
+
+func (g refGlob) match(refName string) bool {
+	if g.fullName {
+		return g.glob.Match(refName)
+	}
+	return g.glob.Match(git.RefName(refName).ShortName())
+}
+
+// SplitRefPatterns returns the non-empty lines of a list of ref patterns
+func SplitRefPatterns(patterns string) []string {
+	var result []string
+	for _, pattern := range strings.Split(patterns, "\n") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			result = append(result, pattern)
+		}
+	}
+	return result
+}
+
+func compileRefPatterns(patterns string) ([]refGlob, error) {
+	var globs []refGlob
+	for _, pattern := range SplitRefPatterns(patterns) {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid ref pattern %q: %v", pattern, err)
+		}
+		globs = append(globs, refGlob{fullName: strings.HasPrefix(pattern, "refs/"), glob: g})
+	}
+	return globs, nil
+}
+
+// ValidateRefPatterns checks that a list of ref patterns can be compiled
+func ValidateRefPatterns(patterns string) error {
+	_, err := compileRefPatterns(patterns)
+	return err
+}
+
+// IsRefFiltered returns whether the mirror only pushes some of the branches and tags
+func (m *PushMirror) IsRefFiltered() bool {
+	return len(SplitRefPatterns(m.RefIncludes)) > 0 || len(SplitRefPatterns(m.RefExcludes)) > 0
+}
+
+func (m *PushMirror) loadGlobs() {
+	if m.globsLoaded {
+		return
+	}
+	m.globsLoaded = true
+	var err error
+	if m.includeGlobs, err = compileRefPatterns(m.RefIncludes); err != nil {
+		log.Warn("Invalid ref include patterns for PushMirror[%d]: %v", m.ID, err)
+	}
+	if m.excludeGlobs, err = compileRefPatterns(m.RefExcludes); err != nil {
+		log.Warn("Invalid ref exclude patterns for PushMirror[%d]: %v", m.ID, err)
+	}
+}
+
+// MatchRef returns whether the mirror pushes a ref: it must match one of the include patterns, if there are any,
+// and none of the exclude patterns
+func (m *PushMirror) MatchRef(refName string) bool {
+	m.loadGlobs()
+	for _, g := range m.excludeGlobs {
+		if g.match(refName) {
+			return false
+		}
+	}
+	if len(m.includeGlobs) == 0 {
+		// invalid include patterns match nothing rather than everything
+		return len(SplitRefPatterns(m.RefIncludes)) == 0
+	}
+	for _, g := range m.includeGlobs {
+		if g.match(refName) {
+			return true
+		}
+	}
+	return false
 }
 
 type PushMirrorOptions struct {
@@ -87,16 +183,28 @@ func UpdatePushMirror(ctx context.Context, m *PushMirror) error {
 	return err
 }
 
-// UpdatePushMirrorInterval updates the push-mirror
-func UpdatePushMirrorInterval(ctx context.Context, m *PushMirror) error {
-	_, err := db.GetEngine(ctx).ID(m.ID).Cols("interval").Update(m)
+// UpdatePushMirrorSettings updates the sync interval, the ref patterns and the non-destructive mode of the push-mirror
+func UpdatePushMirrorSettings(ctx context.Context, m *PushMirror) error {
+	_, err := db.GetEngine(ctx).ID(m.ID).Cols("interval", "ref_includes", "ref_excludes", "non_destructive").Update(m)
 	return err
 }
 
 func DeletePushMirrors(ctx context.Context, opts PushMirrorOptions) error {
 	if opts.RepoID > 0 {
-		_, err := db.GetEngine(ctx).Where(opts.toConds()).Delete(&PushMirror{})
-		return err
+		return db.WithTx(ctx, func(ctx context.Context) error {
+			var ids []int64
+			if err := db.GetEngine(ctx).Table("push_mirror").Where(opts.toConds()).Cols("id").Find(&ids); err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			if _, err := db.GetEngine(ctx).In("push_mirror_id", ids).Delete(&PushMirrorFailure{}); err != nil {
+				return err
+			}
+			_, err := db.GetEngine(ctx).In("id", ids).Delete(&PushMirror{})
+			return err
+		})
 	}
 	return util.NewInvalidArgumentErrorf("repoID required and must be set")
 }
@@ -0,0 +1,63 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MaxPushMirrorFailures is the number of failed syncs kept for each push mirror
+const MaxPushMirrorFailures = 10
+
+// PushMirrorFailure records a failed sync of a push mirror
+type PushMirrorFailure struct {
+	ID           int64              `xorm:"pk autoincr"`
+	PushMirrorID int64              `xorm:"INDEX NOT NULL"`
+	RepoID       int64              `xorm:"INDEX NOT NULL"`
+	Error        string             `xorm:"TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(PushMirrorFailure))
+}
+
+// InsertPushMirrorFailure records a failed sync of the push mirror, only the latest failures are kept
+func InsertPushMirrorFailure(ctx context.Context, m *PushMirror, errMsg string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.Insert(ctx, &PushMirrorFailure{PushMirrorID: m.ID, RepoID: m.RepoID, Error: errMsg}); err != nil {
+			return err
+		}
+
+		var ids []int64
+		if err := db.GetEngine(ctx).Table("push_mirror_failure").
+			Where("push_mirror_id = ?", m.ID).
+			Desc("id").
+			Cols("id").
+			Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) <= MaxPushMirrorFailures {
+			return nil
+		}
+		_, err := db.GetEngine(ctx).In("id", ids[MaxPushMirrorFailures:]).Delete(&PushMirrorFailure{})
+		return err
+	})
+}
+
+// GetPushMirrorFailures returns the latest failed syncs of the push mirrors of a repository by mirror, newest first
+func GetPushMirrorFailures(ctx context.Context, repoID int64) (map[int64][]*PushMirrorFailure, error) {
+	failures := make([]*PushMirrorFailure, 0, 10)
+	if err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Desc("id").Find(&failures); err != nil {
+		return nil, err
+	}
+	result := make(map[int64][]*PushMirrorFailure)
+	for _, f := range failures {
+		result[f.PushMirrorID] = append(result[f.PushMirrorID], f)
+	}
+	return result, nil
+}
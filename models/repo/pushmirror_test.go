@@ -4,6 +4,7 @@
 package repo_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -49,3 +50,47 @@ func TestPushMirrorsIterate(t *testing.T) {
 		return nil
 	})
 }
+
+func TestPushMirrorMatchRef(t *testing.T) {
+	m := &repo_model.PushMirror{}
+	assert.False(t, m.IsRefFiltered())
+	assert.True(t, m.MatchRef("refs/heads/main"))
+
+	m = &repo_model.PushMirror{RefIncludes: "main\nrelease/*\n\nrefs/tags/v1.*", RefExcludes: "release/private"}
+	assert.True(t, m.IsRefFiltered())
+	assert.True(t, m.MatchRef("refs/heads/main"))
+	assert.True(t, m.MatchRef("refs/heads/release/1.0"))
+	assert.False(t, m.MatchRef("refs/heads/release/private"))
+	assert.False(t, m.MatchRef("refs/heads/release/1.0/rc"))
+	assert.True(t, m.MatchRef("refs/tags/v1.2"))
+	assert.False(t, m.MatchRef("refs/tags/v2.0"))
+	assert.False(t, m.MatchRef("refs/heads/feature"))
+
+	m = &repo_model.PushMirror{RefExcludes: "private/**"}
+	assert.True(t, m.MatchRef("refs/heads/feature"))
+	assert.False(t, m.MatchRef("refs/heads/private/a/b"))
+
+	assert.NoError(t, repo_model.ValidateRefPatterns("main\nrelease/*"))
+	assert.Error(t, repo_model.ValidateRefPatterns("release/[a"))
+}
+
+func TestPushMirrorFailures(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	m := &repo_model.PushMirror{RepoID: 1, RemoteName: "test-failures"}
+	assert.NoError(t, repo_model.InsertPushMirror(db.DefaultContext, m))
+
+	for i := 0; i < repo_model.MaxPushMirrorFailures+2; i++ {
+		assert.NoError(t, repo_model.InsertPushMirrorFailure(db.DefaultContext, m, fmt.Sprintf("failure %d", i)))
+	}
+
+	failures, err := repo_model.GetPushMirrorFailures(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, failures[m.ID], repo_model.MaxPushMirrorFailures) {
+		assert.Equal(t, fmt.Sprintf("failure %d", repo_model.MaxPushMirrorFailures+1), failures[m.ID][0].Error)
+		assert.Equal(t, "failure 2", failures[m.ID][repo_model.MaxPushMirrorFailures-1].Error)
+	}
+
+	assert.NoError(t, repo_model.DeletePushMirrors(db.DefaultContext, repo_model.PushMirrorOptions{ID: m.ID, RepoID: m.RepoID}))
+	unittest.AssertNotExistsBean(t, &repo_model.PushMirrorFailure{PushMirrorID: m.ID})
+}
@@ -180,6 +180,10 @@ type Repository struct {
 	CloseIssuesViaCommitInAnyBranch bool               `xorm:"NOT NULL DEFAULT false"`
 	Topics                          []string           `xorm:"TEXT JSON"`
 
+	// CodeIndexerBranches and CodeIndexerTags are the glob patterns of the branches and tags indexed besides the default branch
+	CodeIndexerBranches []string `xorm:"TEXT JSON"`
+	CodeIndexerTags     []string `xorm:"TEXT JSON"`
+
 	TrustModel TrustModelType
 
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
@@ -8,7 +8,9 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
 
+	"github.com/gobwas/glob"
 	"xorm.io/builder"
 )
 
@@ -20,15 +22,18 @@ const (
 	RepoIndexerTypeCode RepoIndexerType = iota // 0
 	// RepoIndexerTypeStats repository stats indexer
 	RepoIndexerTypeStats // 1
+	// RepoIndexerTypeCommits commit indexer
+	RepoIndexerTypeCommits // 2
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
-// For now, implicitly refers to default branch
 type RepoIndexerStatus struct { //revive:disable-line:exported
 	ID          int64           `xorm:"pk autoincr"`
 	RepoID      int64           `xorm:"INDEX(s)"`
 	CommitSha   string          `xorm:"VARCHAR(40)"`
 	IndexerType RepoIndexerType `xorm:"INDEX(s) NOT NULL DEFAULT 0"`
+	// Ref is the full name of the indexed branch or tag, it is empty for the default branch
+	Ref string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
 }
 
 func init() {
@@ -43,7 +48,7 @@ func GetUnindexedRepos(indexerType RepoIndexerType, maxRepoID int64, page, pageS
 	}).And(builder.Eq{
 		"repository.is_empty": false,
 	})
-	sess := db.GetEngine(db.DefaultContext).Table("repository").Join("LEFT OUTER", "repo_indexer_status", "repository.id = repo_indexer_status.repo_id AND repo_indexer_status.indexer_type = ?", indexerType)
+	sess := db.GetEngine(db.DefaultContext).Table("repository").Join("LEFT OUTER", "repo_indexer_status", "repository.id = repo_indexer_status.repo_id AND repo_indexer_status.indexer_type = ? AND repo_indexer_status.`ref` = ''", indexerType)
 	if maxRepoID > 0 {
 		cond = builder.And(cond, builder.Lte{
 			"repository.id": maxRepoID,
@@ -75,7 +80,7 @@ func GetIndexerStatus(ctx context.Context, repo *Repository, indexerType RepoInd
 		}
 	}
 	status := &RepoIndexerStatus{RepoID: repo.ID}
-	if has, err := db.GetEngine(ctx).Where("`indexer_type` = ?", indexerType).Get(status); err != nil {
+	if has, err := db.GetEngine(ctx).Where("`indexer_type` = ?", indexerType).And("`ref` = ''").Get(status); err != nil {
 		return nil, err
 	} else if !has {
 		status.IndexerType = indexerType
@@ -112,3 +117,64 @@ func UpdateIndexerStatus(ctx context.Context, repo *Repository, indexerType Repo
 	}
 	return nil
 }
+
+// DeleteIndexerStatuses deletes the indexer statuses of all the repositories, the index is going to be populated again
+func DeleteIndexerStatuses(ctx context.Context, indexerType RepoIndexerType) error {
+	_, err := db.GetEngine(ctx).Where("indexer_type = ?", indexerType).Delete(new(RepoIndexerStatus))
+	return err
+}
+
+// IsCodeIndexerRef returns true if the branch or tag is indexed by the code indexer besides the default branch
+func (repo *Repository) IsCodeIndexerRef(ref git.RefName) bool {
+	var patterns []string
+	switch {
+	case ref.IsBranch():
+		if ref.BranchName() == repo.DefaultBranch {
+			return false
+		}
+		patterns = repo.CodeIndexerBranches
+	case ref.IsTag():
+		patterns = repo.CodeIndexerTags
+	}
+	for _, pattern := range patterns {
+		if g, err := glob.Compile(pattern, '/'); err == nil && g.Match(ref.ShortName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCodeIndexerRefStatuses returns the code indexer statuses of the indexed branches and tags other than the default branch
+func GetCodeIndexerRefStatuses(ctx context.Context, repoID int64) ([]*RepoIndexerStatus, error) {
+	statuses := make([]*RepoIndexerStatus, 0, 5)
+	return statuses, db.GetEngine(ctx).
+		Where("repo_id = ? AND indexer_type = ? AND `ref` <> ''", repoID, RepoIndexerTypeCode).
+		Asc("ref").
+		Find(&statuses)
+}
+
+// UpdateCodeIndexerRefStatus updates the code indexer status of a branch or tag other than the default branch
+func UpdateCodeIndexerRefStatus(ctx context.Context, repoID int64, ref, sha string) error {
+	status := &RepoIndexerStatus{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND indexer_type = ? AND `ref` = ?", repoID, RepoIndexerTypeCode, ref).Get(status)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return db.Insert(ctx, &RepoIndexerStatus{
+			RepoID:      repoID,
+			CommitSha:   sha,
+			IndexerType: RepoIndexerTypeCode,
+			Ref:         ref,
+		})
+	}
+	status.CommitSha = sha
+	_, err = db.GetEngine(ctx).ID(status.ID).Cols("commit_sha").Update(status)
+	return err
+}
+
+// DeleteCodeIndexerRefStatus deletes the code indexer status of a branch or tag which isn't indexed anymore
+func DeleteCodeIndexerRefStatus(ctx context.Context, repoID int64, ref string) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ? AND indexer_type = ? AND `ref` = ?", repoID, RepoIndexerTypeCode, ref).Delete(new(RepoIndexerStatus))
+	return err
+}
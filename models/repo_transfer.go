@@ -371,6 +371,19 @@ func TransferOwnership(ctx context.Context, doer *user_model.User, newOwnerName
 		) AS il_too)`, issues_model.CommentTypeLabel, repo.ID, newOwner.ID); err != nil {
 			return fmt.Errorf("Unable to remove old org label comments: %w", err)
 		}
+
+		if err := issues_model.RemoveOrgMilestonesFromRepo(ctx, repo.ID); err != nil {
+			return fmt.Errorf("Unable to remove old org milestones: %w", err)
+		}
+
+		if err := issues_model.RemoveIssueTypesFromRepo(ctx, repo.ID); err != nil {
+			return fmt.Errorf("Unable to remove old org issue types: %w", err)
+		}
+	}
+
+	// The sub-issues can only belong to repositories of the same owner
+	if err := issues_model.DeleteIssueParentsOutsideRepo(ctx, repo.ID); err != nil {
+		return fmt.Errorf("Unable to remove sub-issues of other repositories: %w", err)
 	}
 
 	// Rename remote repository to new path and delete local copy.
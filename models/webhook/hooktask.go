@@ -49,6 +49,12 @@ type HookTask struct {
 	EventType      webhook_module.HookEventType
 	IsDelivered    bool
 	Delivered      timeutil.TimeStampNano
+	// Attempt is the number of the automatic retry of a failed delivery, 0 for the first delivery
+	Attempt int `xorm:"NOT NULL DEFAULT 0"`
+	// RetryUnix is when a retry is due, it isn't delivered before
+	RetryUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// OriginalUUID is the UUID of the first delivery of a retry, it is sent as the delivery id of all the retries
+	OriginalUUID string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
 
 	// History info.
 	IsSucceed       bool
@@ -171,7 +177,28 @@ func ReplayHookTask(ctx context.Context, hookID int64, uuid string) (*HookTask,
 	})
 }
 
-// FindUndeliveredHookTaskIDs will find the next 100 undelivered hook tasks with ID greater than the provided lowerID
+// DeliveryUUID returns the id sent with the delivery, the retries of a delivery are sent with the same id
+func (t *HookTask) DeliveryUUID() string {
+	if t.OriginalUUID != "" {
+		return t.OriginalUUID
+	}
+	return t.UUID
+}
+
+// CreateRetryHookTask creates a copy of a failed hook task to get re-delivered at the given time
+func CreateRetryHookTask(ctx context.Context, t *HookTask, retryAt timeutil.TimeStamp) (*HookTask, error) {
+	return CreateHookTask(ctx, &HookTask{
+		HookID:         t.HookID,
+		PayloadContent: t.PayloadContent,
+		EventType:      t.EventType,
+		Attempt:        t.Attempt + 1,
+		RetryUnix:      retryAt,
+		OriginalUUID:   t.DeliveryUUID(),
+	})
+}
+
+// FindUndeliveredHookTaskIDs will find the next 100 undelivered hook tasks with ID greater than the provided lowerID,
+// the retries which aren't due yet are skipped
 func FindUndeliveredHookTaskIDs(ctx context.Context, lowerID int64) ([]int64, error) {
 	const batchSize = 100
 
@@ -180,12 +207,54 @@ func FindUndeliveredHookTaskIDs(ctx context.Context, lowerID int64) ([]int64, er
 		Select("id").
 		Table(new(HookTask)).
 		Where("is_delivered=?", false).
+		And("retry_unix <= ?", timeutil.TimeStampNow()).
 		And("id > ?", lowerID).
 		Asc("id").
 		Limit(batchSize).
 		Find(&tasks)
 }
 
+// FindDueRetryHookTaskIDs finds at most limit retries which are due and not delivered yet
+func FindDueRetryHookTaskIDs(ctx context.Context, limit int) ([]int64, error) {
+	tasks := make([]int64, 0, limit)
+	return tasks, db.GetEngine(ctx).
+		Select("id").
+		Table(new(HookTask)).
+		Where("is_delivered=?", false).
+		And("retry_unix > 0 AND retry_unix <= ?", timeutil.TimeStampNow()).
+		Asc("retry_unix", "id").
+		Limit(limit).
+		Find(&tasks)
+}
+
+// PostponeRetryHookTasks makes the undelivered retries due at the given time, a retry waiting in the sending queue
+// is postponed so it isn't found due again before it is delivered
+func PostponeRetryHookTasks(ctx context.Context, ids []int64, retryAt timeutil.TimeStamp) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).
+		In("id", ids).
+		And("is_delivered=?", false).
+		Cols("retry_unix").
+		Update(&HookTask{RetryUnix: retryAt})
+	return err
+}
+
+// FindPendingRetryHookTasks returns the retries which are not delivered yet, the next due first
+func FindPendingRetryHookTasks(ctx context.Context, opts db.ListOptions) ([]*HookTask, int64, error) {
+	sess := db.GetEngine(ctx).
+		Where("is_delivered=?", false).
+		And("retry_unix > 0").
+		Asc("retry_unix", "id")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	tasks := make([]*HookTask, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&tasks)
+	return tasks, count, err
+}
+
 func MarkTaskDelivered(ctx context.Context, task *HookTask) (bool, error) {
 	count, err := db.GetEngine(ctx).ID(task.ID).Where("is_delivered = ?", false).Cols("is_delivered").Update(&HookTask{
 		ID:          task.ID,
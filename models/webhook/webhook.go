@@ -134,6 +134,7 @@ type Webhook struct {
 	Type                      webhook_module.HookType   `xorm:"VARCHAR(16) 'type'"`
 	Meta                      string                    `xorm:"TEXT"` // store hook-specific attributes
 	LastStatus                webhook_module.HookStatus // Last delivery status
+	FailureCount              int                       `xorm:"NOT NULL DEFAULT 0"` // Number of consecutive failed deliveries
 
 	// HeaderAuthorizationEncrypted should be accessed using HeaderAuthorization() and SetHeaderAuthorization()
 	HeaderAuthorizationEncrypted string `xorm:"TEXT"`
@@ -298,6 +299,48 @@ func (w *Webhook) HasPackageEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Package)
 }
 
+// HasWorkflowRunEvent returns if hook enabled workflow run event.
+func (w *Webhook) HasWorkflowRunEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.WorkflowRun)
+}
+
+// HasWorkflowJobEvent returns if hook enabled workflow job event.
+func (w *Webhook) HasWorkflowJobEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.WorkflowJob)
+}
+
+// HasBranchProtectionEvent returns if hook enabled branch protection event.
+func (w *Webhook) HasBranchProtectionEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.BranchProtection)
+}
+
+// HasMemberEvent returns if hook enabled collaborator event.
+func (w *Webhook) HasMemberEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Member)
+}
+
+// HasMembershipEvent returns if hook enabled team membership event.
+func (w *Webhook) HasMembershipEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Membership)
+}
+
+// HasDeployKeyEvent returns if hook enabled deploy key event.
+func (w *Webhook) HasDeployKeyEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.DeployKey)
+}
+
+// HasRepositoryVisibilityEvent returns if hook enabled repository visibility event.
+func (w *Webhook) HasRepositoryVisibilityEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.RepositoryVisibility)
+}
+
 // HasPullRequestReviewRequestEvent returns true if hook enabled pull request review request event.
 func (w *Webhook) HasPullRequestReviewRequestEvent() bool {
 	return w.SendEverything ||
@@ -336,6 +379,13 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasReleaseEvent, webhook_module.HookEventRelease},
 		{w.HasPackageEvent, webhook_module.HookEventPackage},
 		{w.HasPullRequestReviewRequestEvent, webhook_module.HookEventPullRequestReviewRequest},
+		{w.HasWorkflowRunEvent, webhook_module.HookEventWorkflowRun},
+		{w.HasWorkflowJobEvent, webhook_module.HookEventWorkflowJob},
+		{w.HasBranchProtectionEvent, webhook_module.HookEventBranchProtection},
+		{w.HasMemberEvent, webhook_module.HookEventMember},
+		{w.HasMembershipEvent, webhook_module.HookEventMembership},
+		{w.HasDeployKeyEvent, webhook_module.HookEventDeployKey},
+		{w.HasRepositoryVisibilityEvent, webhook_module.HookEventRepositoryVisibility},
 	}
 }
 
@@ -483,6 +533,50 @@ func UpdateWebhookLastStatus(w *Webhook) error {
 	return err
 }
 
+// IncreaseWebhookFailureCount counts a failed delivery of a webhook and loads its new number of consecutive failed
+// deliveries
+func IncreaseWebhookFailureCount(ctx context.Context, w *Webhook) error {
+	if _, err := db.GetEngine(ctx).ID(w.ID).Incr("failure_count").NoAutoTime().Update(new(Webhook)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(w.ID).Cols("failure_count").NoAutoCondition().Get(w)
+	return err
+}
+
+// ResetWebhookFailureCount resets the number of consecutive failed deliveries of a webhook after a successful
+// delivery
+func ResetWebhookFailureCount(ctx context.Context, w *Webhook) error {
+	if w.FailureCount == 0 {
+		return nil
+	}
+	w.FailureCount = 0
+	_, err := db.GetEngine(ctx).ID(w.ID).Cols("failure_count").NoAutoTime().Update(w)
+	return err
+}
+
+// DisableWebhook deactivates a webhook, it returns false if the webhook was already inactive
+func DisableWebhook(ctx context.Context, w *Webhook) (bool, error) {
+	w.IsActive = false
+	count, err := db.GetEngine(ctx).ID(w.ID).Where("is_active = ?", true).Cols("is_active").Update(w)
+	return count > 0, err
+}
+
+// GetFailingWebhooks returns the webhooks whose last deliveries failed, the most failures first
+func GetFailingWebhooks(ctx context.Context) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 10)
+	return webhooks, db.GetEngine(ctx).
+		Where("failure_count > 0").
+		Desc("failure_count").
+		Asc("id").
+		Find(&webhooks)
+}
+
+// GetWebhooksByIDs returns the webhooks with the given ids
+func GetWebhooksByIDs(ctx context.Context, ids []int64) (map[int64]*Webhook, error) {
+	webhooks := make(map[int64]*Webhook, len(ids))
+	return webhooks, db.GetEngine(ctx).In("id", ids).Find(&webhooks)
+}
+
 // DeleteWebhookByID uses argument bean as query condition,
 // ID must be specified and do not assign unnecessary fields.
 func DeleteWebhookByID(ctx context.Context, id int64) (err error) {
@@ -73,7 +73,8 @@ func TestWebhook_EventsArray(t *testing.T) {
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
 		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
 		"pull_request_review_comment", "pull_request_sync", "wiki", "repository", "release",
-		"package", "pull_request_review_request",
+		"package", "pull_request_review_request", "workflow_run", "workflow_job",
+		"branch_protection", "member", "membership", "deploy_key", "repository_visibility",
 	},
 		(&Webhook{
 			HookEvent: &webhook_module.HookEvent{SendEverything: true},
@@ -328,3 +329,76 @@ func TestCleanupHookTaskTable_OlderThan_LeavesTaskEarlierThanAgeToDelete(t *test
 	assert.NoError(t, CleanupHookTaskTable(context.Background(), OlderThan, 168*time.Hour, 0))
 	unittest.AssertExistsAndLoadBean(t, hookTask)
 }
+
+func TestCreateRetryHookTask(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	hookTask, err := CreateHookTask(db.DefaultContext, &HookTask{
+		HookID:      3,
+		Payloader:   &api.PushPayload{},
+		IsDelivered: true,
+	})
+	assert.NoError(t, err)
+
+	due, err := CreateRetryHookTask(db.DefaultContext, hookTask, timeutil.TimeStampNow()-1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, due.Attempt)
+	assert.Equal(t, hookTask.PayloadContent, due.PayloadContent)
+	// the retries are sent with the id of the first delivery
+	assert.Equal(t, hookTask.UUID, due.DeliveryUUID())
+	later, err := CreateRetryHookTask(db.DefaultContext, due, timeutil.TimeStampNow()+3600)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, later.Attempt)
+	assert.Equal(t, hookTask.UUID, later.DeliveryUUID())
+	assert.Equal(t, hookTask.UUID, unittest.AssertExistsAndLoadBean(t, &HookTask{ID: later.ID}).OriginalUUID)
+
+	// a retry is only delivered once it is due
+	undelivered, err := FindUndeliveredHookTaskIDs(db.DefaultContext, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, undelivered, due.ID)
+	assert.NotContains(t, undelivered, later.ID)
+
+	dueIDs, err := FindDueRetryHookTaskIDs(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{due.ID}, dueIDs)
+
+	// a retry postponed while it waits in the queue isn't due again
+	assert.NoError(t, PostponeRetryHookTasks(db.DefaultContext, dueIDs, timeutil.TimeStampNow()+60))
+	dueIDs, err = FindDueRetryHookTaskIDs(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, dueIDs)
+
+	pending, count, err := FindPendingRetryHookTasks(db.DefaultContext, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, due.ID, pending[0].ID)
+		assert.Equal(t, later.ID, pending[1].ID)
+	}
+}
+
+func TestWebhookFailureCount(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	hook := unittest.AssertExistsAndLoadBean(t, &Webhook{ID: 1})
+
+	assert.NoError(t, IncreaseWebhookFailureCount(db.DefaultContext, hook))
+	assert.NoError(t, IncreaseWebhookFailureCount(db.DefaultContext, hook))
+	assert.Equal(t, 2, hook.FailureCount)
+	assert.Equal(t, 2, unittest.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).FailureCount)
+
+	failing, err := GetFailingWebhooks(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, failing, 1) {
+		assert.EqualValues(t, 1, failing[0].ID)
+	}
+
+	disabled, err := DisableWebhook(db.DefaultContext, hook)
+	assert.NoError(t, err)
+	assert.True(t, disabled)
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).IsActive)
+	disabled, err = DisableWebhook(db.DefaultContext, hook)
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+
+	assert.NoError(t, ResetWebhookFailureCount(db.DefaultContext, hook))
+	assert.Equal(t, 0, unittest.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).FailureCount)
+}
@@ -230,6 +230,19 @@ func (c *Commit) HasPreviousCommit(commitHash SHA1) (bool, error) {
 	return false, err
 }
 
+// IsAncestor returns true if the ancestor commit is reachable from the descendant commit
+func IsAncestor(ctx context.Context, repoPath, ancestor, descendant string) (bool, error) {
+	_, _, err := NewCommand(ctx, "merge-base", "--is-ancestor").AddDynamicArguments(ancestor, descendant).RunStdString(&RunOpts{Dir: repoPath})
+	if err == nil {
+		return true, nil
+	}
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ProcessState.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
 // IsForcePush returns true if a push from oldCommitHash to this is a force push
 func (c *Commit) IsForcePush(oldCommitID string) (bool, error) {
 	if oldCommitID == EmptySHA {
@@ -5,6 +5,8 @@ package git
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	giturl "code.gitea.io/gitea/modules/git/url"
 )
@@ -37,3 +39,39 @@ func GetRemoteURL(ctx context.Context, repoPath, remoteName string) (*giturl.Git
 	}
 	return giturl.Parse(addr)
 }
+
+// GetRemoteRefs returns the object ids of the branches and tags of a remote of the repository by ref name
+func GetRemoteRefs(ctx context.Context, repoPath, remote string, timeout time.Duration) (map[string]string, error) {
+	stdout, stderr, err := NewCommand(ctx, "ls-remote", "--heads", "--tags").AddDashesAndList(remote).RunStdString(&RunOpts{Dir: repoPath, Timeout: timeout})
+	if err != nil {
+		return nil, ConcatenateError(err, stderr)
+	}
+	return parseRefList(stdout), nil
+}
+
+// GetBranchAndTagRefs returns the object ids of the branches and tags of the repository by ref name
+func GetBranchAndTagRefs(ctx context.Context, repoPath string) (map[string]string, error) {
+	return GetRefsByPrefix(ctx, repoPath, BranchPrefix, TagPrefix)
+}
+
+// GetRefsByPrefix returns the object ids of the refs of the repository starting with one of the prefixes by ref name
+func GetRefsByPrefix(ctx context.Context, repoPath string, prefixes ...string) (map[string]string, error) {
+	stdout, stderr, err := NewCommand(ctx, "for-each-ref", "--format=%(objectname)%09%(refname)").AddDynamicArguments(prefixes...).RunStdString(&RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, ConcatenateError(err, stderr)
+	}
+	return parseRefList(stdout), nil
+}
+
+// parseRefList parses lines of "<object id> TAB <ref name>", the peeled entries of annotated tags are skipped
+func parseRefList(s string) map[string]string {
+	refs := make(map[string]string)
+	for _, line := range strings.Split(s, "\n") {
+		objectID, refName, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || strings.HasSuffix(refName, "^{}") {
+			continue
+		}
+		refs[refName] = objectID
+	}
+	return refs
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefList(t *testing.T) {
+	refs := parseRefList(`2c54faec6c45d31c1abfaecdab471eac6633738a	refs/heads/master
+b7c5e03d8e2a4c1b3f0f1e0d9c8b7a6f5e4d3c2b	refs/tags/v1.0
+37991dec2c8e592043f47155ce4808d4580f9123	refs/tags/v1.0^{}
+`)
+	assert.Equal(t, map[string]string{
+		"refs/heads/master": "2c54faec6c45d31c1abfaecdab471eac6633738a",
+		"refs/tags/v1.0":    "b7c5e03d8e2a4c1b3f0f1e0d9c8b7a6f5e4d3c2b",
+	}, refs)
+}
+
+func TestGetBranchAndTagRefs(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	refs, err := GetBranchAndTagRefs(DefaultContext, bareRepo1Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "ce064814f4a0d337b333e646ece456cd39fab612", refs["refs/heads/master"])
+	for refName := range refs {
+		assert.True(t, RefName(refName).IsBranch() || RefName(refName).IsTag(), refName)
+	}
+}
@@ -182,17 +182,25 @@ func CloneWithArgs(ctx context.Context, args TrustedCmdArgs, from, to string, op
 
 // PushOptions options when push to remote
 type PushOptions struct {
-	Remote  string
-	Branch  string
-	Force   bool
-	Mirror  bool
-	Env     []string
-	Timeout time.Duration
+	Remote   string
+	Branch   string
+	Refspecs []string
+	Force    bool
+	Mirror   bool
+	Env      []string
+	Timeout  time.Duration
+
+	// IgnoreRemoteMirror allows to push refspecs to a remote configured as a mirror
+	IgnoreRemoteMirror bool
 }
 
 // Push pushs local commits to given remote branch.
 func Push(ctx context.Context, repoPath string, opts PushOptions) error {
-	cmd := NewCommand(ctx, "push")
+	cmd := NewCommand(ctx)
+	if opts.IgnoreRemoteMirror {
+		cmd.AddOptionValues("-c", "remote."+opts.Remote+".mirror=false")
+	}
+	cmd.AddArguments("push")
 	if opts.Force {
 		cmd.AddArguments("-f")
 	}
@@ -203,6 +211,7 @@ func Push(ctx context.Context, repoPath string, opts PushOptions) error {
 	if len(opts.Branch) > 0 {
 		remoteBranchArgs = append(remoteBranchArgs, opts.Branch)
 	}
+	remoteBranchArgs = append(remoteBranchArgs, opts.Refspecs...)
 	cmd.AddDashesAndList(remoteBranchArgs...)
 
 	if strings.Contains(opts.Remote, "://") && strings.Contains(opts.Remote, "@") {
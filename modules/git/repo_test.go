@@ -54,3 +54,21 @@ func TestRepoGetDivergingCommits(t *testing.T) {
 		Behind: 2,
 	}, do)
 }
+
+func TestPushIgnoreRemoteMirror(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "src.git")
+	assert.NoError(t, Clone(DefaultContext, filepath.Join(testReposDir, "repo1_bare"), srcPath, CloneRepoOptions{Bare: true}))
+	dstPath := filepath.Join(t.TempDir(), "dst.git")
+	assert.NoError(t, InitRepository(DefaultContext, dstPath, true))
+	_, _, err := NewCommand(DefaultContext, "remote", "add", "--mirror=push", "mirror").AddDynamicArguments(dstPath).RunStdString(&RunOpts{Dir: srcPath})
+	assert.NoError(t, err)
+
+	opts := PushOptions{Remote: "mirror", Refspecs: []string{"refs/heads/branch2:refs/heads/branch2"}}
+	assert.Error(t, Push(DefaultContext, srcPath, opts))
+
+	opts.IgnoreRemoteMirror = true
+	assert.NoError(t, Push(DefaultContext, srcPath, opts))
+	branches, _, err := NewCommand(DefaultContext, "for-each-ref", "--format=%(refname)").RunStdString(&RunOpts{Dir: dstPath})
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/heads/branch2\n", branches)
+}
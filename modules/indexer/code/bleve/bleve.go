@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/symbols"
 	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
 	inner_bleve "code.gitea.io/gitea/modules/indexer/internal/bleve"
 	"code.gitea.io/gitea/modules/log"
@@ -51,9 +52,12 @@ func addUnicodeNormalizeTokenFilter(m *mapping.IndexMappingImpl) error {
 // RepoIndexerData data stored in the repo indexer
 type RepoIndexerData struct {
 	RepoID    int64
+	Ref       string
 	CommitID  string
+	Filename  string
 	Content   string
 	Language  string
+	Symbols   []string
 	UpdatedAt time.Time
 }
 
@@ -65,7 +69,7 @@ func (d *RepoIndexerData) Type() string {
 const (
 	repoIndexerAnalyzer      = "repoIndexerAnalyzer"
 	repoIndexerDocType       = "repoIndexerDocType"
-	repoIndexerLatestVersion = 6
+	repoIndexerLatestVersion = 8
 )
 
 // generateBleveIndexMapping generates a bleve index mapping for the repo indexer
@@ -83,7 +87,10 @@ func generateBleveIndexMapping() (mapping.IndexMapping, error) {
 	termFieldMapping.IncludeInAll = false
 	termFieldMapping.Analyzer = analyzer_keyword.Name
 	docMapping.AddFieldMappingsAt("Language", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Ref", termFieldMapping)
 	docMapping.AddFieldMappingsAt("CommitID", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Filename", termFieldMapping)
+	docMapping.AddFieldMappingsAt("Symbols", termFieldMapping)
 
 	timeFieldMapping := bleve.NewDateTimeFieldMapping()
 	timeFieldMapping.IncludeInAll = false
@@ -124,7 +131,7 @@ func NewIndexer(indexDir string) *Indexer {
 	}
 }
 
-func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, commitSha string,
+func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, ref, commitSha string,
 	update internal.FileUpdate, repo *repo_model.Repository, batch *inner_bleve.FlushingBatch,
 ) error {
 	// Ignore vendored files in code search
@@ -147,7 +154,7 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	}
 
 	if size > setting.Indexer.MaxIndexerFileSize {
-		return b.addDelete(update.Filename, repo, batch)
+		return b.addDelete(ref, update.Filename, repo, batch)
 	}
 
 	if _, err := batchWriter.Write([]byte(update.BlobSha + "\n")); err != nil {
@@ -170,23 +177,28 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	if _, err = batchReader.Discard(1); err != nil {
 		return err
 	}
-	id := internal.FilenameIndexerID(repo.ID, update.Filename)
+	id := internal.FilenameIndexerID(repo.ID, ref, update.Filename)
+	content := string(charset.ToUTF8DropErrors(fileContents))
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
 	return batch.Index(id, &RepoIndexerData{
 		RepoID:    repo.ID,
+		Ref:       ref,
 		CommitID:  commitSha,
-		Content:   string(charset.ToUTF8DropErrors(fileContents)),
-		Language:  analyze.GetCodeLanguage(update.Filename, fileContents),
+		Filename:  update.Filename,
+		Content:   content,
+		Language:  language,
+		Symbols:   symbols.Names(symbols.Extract(language, content)),
 		UpdatedAt: time.Now().UTC(),
 	})
 }
 
-func (b *Indexer) addDelete(filename string, repo *repo_model.Repository, batch *inner_bleve.FlushingBatch) error {
-	id := internal.FilenameIndexerID(repo.ID, filename)
+func (b *Indexer) addDelete(ref, filename string, repo *repo_model.Repository, batch *inner_bleve.FlushingBatch) error {
+	id := internal.FilenameIndexerID(repo.ID, ref, filename)
 	return batch.Delete(id)
 }
 
 // Index indexes the data
-func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha string, changes *internal.RepoChanges) error {
+func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, ref, sha string, changes *internal.RepoChanges) error {
 	batch := inner_bleve.NewFlushingBatch(b.inner.Indexer, maxBatchSize)
 	if len(changes.Updates) > 0 {
 
@@ -200,14 +212,14 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 		defer cancel()
 
 		for _, update := range changes.Updates {
-			if err := b.addUpdate(ctx, batchWriter, batchReader, sha, update, repo, batch); err != nil {
+			if err := b.addUpdate(ctx, batchWriter, batchReader, ref, sha, update, repo, batch); err != nil {
 				return err
 			}
 		}
 		cancel()
 	}
 	for _, filename := range changes.RemovedFilenames {
-		if err := b.addDelete(filename, repo, batch); err != nil {
+		if err := b.addDelete(ref, filename, repo, batch); err != nil {
 			return err
 		}
 	}
@@ -216,7 +228,24 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 
 // Delete deletes indexes by ids
 func (b *Indexer) Delete(_ context.Context, repoID int64) error {
-	query := inner_bleve.NumericEqualityQuery(repoID, "RepoID")
+	return b.deleteByQuery(inner_bleve.NumericEqualityQuery(repoID, "RepoID"))
+}
+
+// DeleteRef deletes the files of a branch or tag
+func (b *Indexer) DeleteRef(_ context.Context, repoID int64, ref string) error {
+	return b.deleteByQuery(bleve.NewConjunctionQuery(
+		inner_bleve.NumericEqualityQuery(repoID, "RepoID"),
+		refQuery(ref),
+	))
+}
+
+func refQuery(ref string) query.Query {
+	q := bleve.NewTermQuery(ref)
+	q.FieldVal = "Ref"
+	return q
+}
+
+func (b *Indexer) deleteByQuery(query query.Query) error {
 	searchRequest := bleve.NewSearchRequestOptions(query, 2147483647, 0, false)
 	result, err := b.inner.Indexer.Search(searchRequest)
 	if err != nil {
@@ -233,39 +262,50 @@ func (b *Indexer) Delete(_ context.Context, repoID int64) error {
 
 // Search searches for files in the specified repo.
 // Returns the matching file-paths
-func (b *Indexer) Search(ctx context.Context, repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
-	var (
-		indexerQuery query.Query
-		keywordQuery query.Query
-	)
-
-	if isMatch {
-		prefixQuery := bleve.NewPrefixQuery(keyword)
-		prefixQuery.FieldVal = "Content"
+func (b *Indexer) Search(ctx context.Context, opts *internal.SearchOptions) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	var keywordQuery query.Query
+	switch {
+	case opts.Keyword == "":
+		keywordQuery = bleve.NewMatchAllQuery()
+	case opts.IsSymbol:
+		// symbol names are stored lower-cased so the search is case-insensitive
+		prefixQuery := bleve.NewPrefixQuery(strings.ToLower(opts.Keyword))
+		prefixQuery.FieldVal = "Symbols"
 		keywordQuery = prefixQuery
-	} else {
-		phraseQuery := bleve.NewMatchPhraseQuery(keyword)
+	case opts.IsKeywordFuzzy:
+		phraseQuery := bleve.NewMatchPhraseQuery(opts.Keyword)
 		phraseQuery.FieldVal = "Content"
 		phraseQuery.Analyzer = repoIndexerAnalyzer
 		keywordQuery = phraseQuery
+	default:
+		prefixQuery := bleve.NewPrefixQuery(opts.Keyword)
+		prefixQuery.FieldVal = "Content"
+		keywordQuery = prefixQuery
 	}
 
-	if len(repoIDs) > 0 {
-		repoQueries := make([]query.Query, 0, len(repoIDs))
-		for _, repoID := range repoIDs {
+	queries := []query.Query{keywordQuery, refQuery(opts.Ref)}
+	if len(opts.RepoIDs) > 0 {
+		repoQueries := make([]query.Query, 0, len(opts.RepoIDs))
+		for _, repoID := range opts.RepoIDs {
 			repoQueries = append(repoQueries, inner_bleve.NumericEqualityQuery(repoID, "RepoID"))
 		}
-
-		indexerQuery = bleve.NewConjunctionQuery(
-			bleve.NewDisjunctionQuery(repoQueries...),
-			keywordQuery,
-		)
-	} else {
-		indexerQuery = keywordQuery
+		queries = append(queries, bleve.NewDisjunctionQuery(repoQueries...))
 	}
+	if len(opts.Paths) > 0 {
+		pathQueries := make([]query.Query, 0, len(opts.Paths))
+		for _, path := range opts.Paths {
+			pathQuery := bleve.NewWildcardQuery(indexer_internal.PathWildcard(path))
+			pathQuery.FieldVal = "Filename"
+			pathQueries = append(pathQueries, pathQuery)
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(pathQueries...))
+	}
+
+	var indexerQuery query.Query = bleve.NewConjunctionQuery(queries...)
 
 	// Save for reuse without language filter
 	facetQuery := indexerQuery
+	language := opts.Language
 	if len(language) > 0 {
 		languageQuery := bleve.NewMatchQuery(language)
 		languageQuery.FieldVal = "Language"
@@ -277,6 +317,7 @@ func (b *Indexer) Search(ctx context.Context, repoIDs []int64, language, keyword
 		)
 	}
 
+	page, pageSize := opts.Page, opts.PageSize
 	from := (page - 1) * pageSize
 	searchRequest := bleve.NewSearchRequestOptions(indexerQuery, pageSize, from, false)
 	searchRequest.Fields = []string{"Content", "RepoID", "Language", "CommitID", "UpdatedAt"}
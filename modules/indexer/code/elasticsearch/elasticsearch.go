@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/symbols"
 	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
 	inner_elasticsearch "code.gitea.io/gitea/modules/indexer/internal/elasticsearch"
 	"code.gitea.io/gitea/modules/json"
@@ -29,7 +30,7 @@ import (
 )
 
 const (
-	esRepoIndexerLatestVersion = 1
+	esRepoIndexerLatestVersion = 3
 	// multi-match-types, currently only 2 types are used
 	// Reference: https://www.elastic.co/guide/en/elasticsearch/reference/7.0/query-dsl-multi-match-query.html#multi-match-types
 	esMultiMatchTypeBestFields   = "best_fields"
@@ -67,10 +68,22 @@ const (
 					"term_vector": "with_positions_offsets",
 					"index": true
 				},
+				"ref": {
+					"type": "keyword",
+					"index": true
+				},
 				"commit_id": {
 					"type": "keyword",
 					"index": true
 				},
+				"filename": {
+					"type": "keyword",
+					"index": true
+				},
+				"symbols": {
+					"type": "keyword",
+					"index": true
+				},
 				"language": {
 					"type": "keyword",
 					"index": true
@@ -84,7 +97,7 @@ const (
 	}`
 )
 
-func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, sha string, update internal.FileUpdate, repo *repo_model.Repository) ([]elastic.BulkableRequest, error) {
+func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, ref, sha string, update internal.FileUpdate, repo *repo_model.Repository) ([]elastic.BulkableRequest, error) {
 	// Ignore vendored files in code search
 	if setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
 		return nil, nil
@@ -104,7 +117,7 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	}
 
 	if size > setting.Indexer.MaxIndexerFileSize {
-		return []elastic.BulkableRequest{b.addDelete(update.Filename, repo)}, nil
+		return []elastic.BulkableRequest{b.addDelete(ref, update.Filename, repo)}, nil
 	}
 
 	if _, err := batchWriter.Write([]byte(update.BlobSha + "\n")); err != nil {
@@ -127,7 +140,9 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	if _, err = batchReader.Discard(1); err != nil {
 		return nil, err
 	}
-	id := internal.FilenameIndexerID(repo.ID, update.Filename)
+	id := internal.FilenameIndexerID(repo.ID, ref, update.Filename)
+	content := string(charset.ToUTF8DropErrors(fileContents))
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
 
 	return []elastic.BulkableRequest{
 		elastic.NewBulkIndexRequest().
@@ -135,23 +150,26 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 			Id(id).
 			Doc(map[string]any{
 				"repo_id":    repo.ID,
-				"content":    string(charset.ToUTF8DropErrors(fileContents)),
+				"ref":        ref,
+				"filename":   update.Filename,
+				"content":    content,
 				"commit_id":  sha,
-				"language":   analyze.GetCodeLanguage(update.Filename, fileContents),
+				"language":   language,
+				"symbols":    symbols.Names(symbols.Extract(language, content)),
 				"updated_at": timeutil.TimeStampNow(),
 			}),
 	}, nil
 }
 
-func (b *Indexer) addDelete(filename string, repo *repo_model.Repository) elastic.BulkableRequest {
-	id := internal.FilenameIndexerID(repo.ID, filename)
+func (b *Indexer) addDelete(ref, filename string, repo *repo_model.Repository) elastic.BulkableRequest {
+	id := internal.FilenameIndexerID(repo.ID, ref, filename)
 	return elastic.NewBulkDeleteRequest().
 		Index(b.inner.VersionedIndexName()).
 		Id(id)
 }
 
 // Index will save the index data
-func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha string, changes *internal.RepoChanges) error {
+func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, ref, sha string, changes *internal.RepoChanges) error {
 	reqs := make([]elastic.BulkableRequest, 0)
 	if len(changes.Updates) > 0 {
 		// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
@@ -164,7 +182,7 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 		defer cancel()
 
 		for _, update := range changes.Updates {
-			updateReqs, err := b.addUpdate(ctx, batchWriter, batchReader, sha, update, repo)
+			updateReqs, err := b.addUpdate(ctx, batchWriter, batchReader, ref, sha, update, repo)
 			if err != nil {
 				return err
 			}
@@ -176,7 +194,7 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 	}
 
 	for _, filename := range changes.RemovedFilenames {
-		reqs = append(reqs, b.addDelete(filename, repo))
+		reqs = append(reqs, b.addDelete(ref, filename, repo))
 	}
 
 	if len(reqs) > 0 {
@@ -197,6 +215,17 @@ func (b *Indexer) Delete(ctx context.Context, repoID int64) error {
 	return err
 }
 
+// DeleteRef deletes the files of a branch or tag
+func (b *Indexer) DeleteRef(ctx context.Context, repoID int64, ref string) error {
+	_, err := b.inner.Client.DeleteByQuery(b.inner.VersionedIndexName()).
+		Query(elastic.NewBoolQuery().Filter(
+			elastic.NewTermQuery("repo_id", repoID),
+			elastic.NewTermQuery("ref", ref),
+		)).
+		Do(ctx)
+	return err
+}
+
 // indexPos find words positions for start and the following end on content. It will
 // return the beginning position of the first start and the ending position of the
 // first end following the start string.
@@ -219,7 +248,8 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 		// FIXME: There is no way to get the position the keyword on the content currently on the same request.
 		// So we get it from content, this may made the query slower. See
 		// https://discuss.elastic.co/t/fetching-position-of-keyword-in-matched-document/94291
-		var startIndex, endIndex int
+		// the searches without keyword or on the symbols have no highlight on the content
+		startIndex, endIndex := -1, -1
 		c, ok := hit.Highlight["content"]
 		if ok && len(c) > 0 {
 			// FIXME: Since the highlighting content will include <em> and </em> for the keywords,
@@ -229,11 +259,10 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 			if startIndex == -1 {
 				panic(fmt.Sprintf("1===%s,,,%#v,,,%s", kw, hit.Highlight, c[0]))
 			}
-		} else {
-			panic(fmt.Sprintf("2===%#v", hit.Highlight))
+			endIndex -= 9 // remove the length <em></em> since we give Content the original data
 		}
 
-		repoID, fileName := internal.ParseIndexerID(hit.Id)
+		repoID, _, fileName := internal.ParseIndexerID(hit.Id)
 		res := make(map[string]any)
 		if err := json.Unmarshal(hit.Source, &res); err != nil {
 			return 0, nil, nil, err
@@ -249,7 +278,7 @@ func convertResult(searchResult *elastic.SearchResult, kw string, pageSize int)
 			UpdatedUnix: timeutil.TimeStamp(res["updated_at"].(float64)),
 			Language:    language,
 			StartIndex:  startIndex,
-			EndIndex:    endIndex - 9, // remove the length <em></em> since we give Content the original data
+			EndIndex:    endIndex,
 			Color:       enry.GetColor(language),
 		})
 	}
@@ -275,27 +304,46 @@ func extractAggs(searchResult *elastic.SearchResult) []*internal.SearchResultLan
 }
 
 // Search searches for codes and language stats by given conditions.
-func (b *Indexer) Search(ctx context.Context, repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
-	searchType := esMultiMatchTypeBestFields
-	if isMatch {
-		searchType = esMultiMatchTypePhrasePrefix
+func (b *Indexer) Search(ctx context.Context, opts *internal.SearchOptions) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	var kwQuery elastic.Query
+	switch {
+	case opts.Keyword == "":
+		kwQuery = elastic.NewMatchAllQuery()
+	case opts.IsSymbol:
+		// symbol names are stored lower-cased so the search is case-insensitive
+		kwQuery = elastic.NewPrefixQuery("symbols", strings.ToLower(opts.Keyword))
+	default:
+		searchType := esMultiMatchTypePhrasePrefix
+		if opts.IsKeywordFuzzy {
+			searchType = esMultiMatchTypeBestFields
+		}
+		kwQuery = elastic.NewMultiMatchQuery(opts.Keyword, "content").Type(searchType)
 	}
 
-	kwQuery := elastic.NewMultiMatchQuery(keyword, "content").Type(searchType)
 	query := elastic.NewBoolQuery()
-	query = query.Must(kwQuery)
-	if len(repoIDs) > 0 {
-		repoStrs := make([]any, 0, len(repoIDs))
-		for _, repoID := range repoIDs {
+	query = query.Must(kwQuery, elastic.NewTermQuery("ref", opts.Ref))
+	if len(opts.RepoIDs) > 0 {
+		repoStrs := make([]any, 0, len(opts.RepoIDs))
+		for _, repoID := range opts.RepoIDs {
 			repoStrs = append(repoStrs, repoID)
 		}
 		repoQuery := elastic.NewTermsQuery("repo_id", repoStrs...)
 		query = query.Must(repoQuery)
 	}
+	if len(opts.Paths) > 0 {
+		pathQuery := elastic.NewBoolQuery()
+		for _, path := range opts.Paths {
+			pathQuery = pathQuery.Should(elastic.NewWildcardQuery("filename", indexer_internal.PathWildcard(path)))
+		}
+		query = query.Must(pathQuery.MinimumNumberShouldMatch(1))
+	}
 
 	var (
 		start       int
-		kw          = "<em>" + keyword + "</em>"
+		page        = opts.Page
+		pageSize    = opts.PageSize
+		language    = opts.Language
+		kw          = "<em>" + opts.Keyword + "</em>"
 		aggregation = elastic.NewTermsAggregation().Field("language").Size(10).OrderByCountDesc()
 	)
 
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/indexer/code/internal"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository) (string, error) {
@@ -23,24 +24,99 @@ func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository) (stri
 	return strings.TrimSpace(stdout), nil
 }
 
-// getRepoChanges returns changes to repo since last indexer update
-func getRepoChanges(ctx context.Context, repo *repo_model.Repository, revision string) (*internal.RepoChanges, error) {
-	status, err := repo_model.GetIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCode)
+// indexedRef is a branch or tag indexed besides the default branch
+type indexedRef struct {
+	Name     git.RefName
+	CommitID string
+}
+
+// getIndexedRefs returns the branches and tags matching the code indexer patterns of the repository,
+// the most recent ones first
+func getIndexedRefs(ctx context.Context, repo *repo_model.Repository) ([]*indexedRef, error) {
+	if len(repo.CodeIndexerBranches) == 0 && len(repo.CodeIndexerTags) == 0 {
+		return nil, nil
+	}
+
+	stdout, _, err := git.NewCommand(ctx, "for-each-ref", "--sort=-creatordate", "--format=%(objectname) %(*objectname) %(refname)").
+		AddDynamicArguments(git.BranchPrefix, git.TagPrefix).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*indexedRef
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		name := git.RefName(fields[2])
+		if !repo.IsCodeIndexerRef(name) {
+			continue
+		}
+		if len(refs) >= setting.Indexer.MaxIndexedRefs {
+			log.Warn("Repository %-v has more than %d branches and tags to index, %s is skipped", repo, setting.Indexer.MaxIndexedRefs, name)
+			continue
+		}
+		// annotated tags are peeled to their commit
+		commitID := fields[1]
+		if commitID == "" {
+			commitID = fields[0]
+		}
+		refs = append(refs, &indexedRef{Name: name, CommitID: commitID})
+	}
+	return refs, nil
+}
+
+// IndexedRefs returns the branches and tags of the repository indexed besides the default branch, the branches first
+func IndexedRefs(ctx context.Context, repo *repo_model.Repository) ([]git.RefName, error) {
+	statuses, err := repo_model.GetCodeIndexerRefStatuses(ctx, repo.ID)
 	if err != nil {
 		return nil, err
 	}
+	refs := make([]git.RefName, 0, len(statuses))
+	for _, status := range statuses {
+		refs = append(refs, git.RefName(status.Ref))
+	}
+	return refs, nil
+}
+
+// ResolveRef returns the full name of the indexed branch or tag to search, it is empty for the default branch.
+// A branch is preferred to a tag with the same name.
+func ResolveRef(ctx context.Context, repo *repo_model.Repository, name string) (string, error) {
+	if name == "" || name == repo.DefaultBranch {
+		return "", nil
+	}
+	refs, err := IndexedRefs(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.ShortName() == name {
+			return ref.String(), nil
+		}
+	}
+	return "", util.NewInvalidArgumentErrorf("branch or tag %q is not indexed", name)
+}
 
-	needGenesis := len(status.CommitSha) == 0
+// getRepoChanges returns changes to the ref of the repo since the last indexed commit
+func getRepoChanges(ctx context.Context, repo *repo_model.Repository, ref, indexedSha, revision string) (*internal.RepoChanges, error) {
+	needGenesis := len(indexedSha) == 0
 	if !needGenesis {
-		hasAncestorCmd := git.NewCommand(ctx, "merge-base").AddDynamicArguments(repo.CodeIndexerStatus.CommitSha, revision)
+		hasAncestorCmd := git.NewCommand(ctx, "merge-base").AddDynamicArguments(indexedSha, revision)
 		stdout, _, _ := hasAncestorCmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
-		needGenesis = len(stdout) == 0
+		if len(stdout) == 0 {
+			// the ref now points to an unrelated history, remove its files before indexing it again
+			if err := (*globalIndexer.Load()).DeleteRef(ctx, repo.ID, ref); err != nil {
+				return nil, err
+			}
+			needGenesis = true
+		}
 	}
 
 	if needGenesis {
 		return genesisChanges(ctx, repo, revision)
 	}
-	return nonGenesisChanges(ctx, repo, revision)
+	return nonGenesisChanges(ctx, repo, ref, indexedSha, revision)
 }
 
 func isIndexable(entry *git.TreeEntry) bool {
@@ -97,14 +173,14 @@ func genesisChanges(ctx context.Context, repo *repo_model.Repository, revision s
 }
 
 // nonGenesisChanges get changes since the previous indexer update
-func nonGenesisChanges(ctx context.Context, repo *repo_model.Repository, revision string) (*internal.RepoChanges, error) {
-	diffCmd := git.NewCommand(ctx, "diff", "--name-status").AddDynamicArguments(repo.CodeIndexerStatus.CommitSha, revision)
+func nonGenesisChanges(ctx context.Context, repo *repo_model.Repository, ref, indexedSha, revision string) (*internal.RepoChanges, error) {
+	diffCmd := git.NewCommand(ctx, "diff", "--name-status").AddDynamicArguments(indexedSha, revision)
 	stdout, _, runErr := diffCmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
 	if runErr != nil {
 		// previous commit sha may have been removed by a force push, so
 		// try rebuilding from scratch
 		log.Warn("git diff: %v", runErr)
-		if err := (*globalIndexer.Load()).Delete(ctx, repo.ID); err != nil {
+		if err := (*globalIndexer.Load()).DeleteRef(ctx, repo.ID, ref); err != nil {
 			return nil, err
 		}
 		return genesisChanges(ctx, repo, revision)
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/indexer/code/bleve"
 	"code.gitea.io/gitea/modules/indexer/code/elasticsearch"
 	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/trigram"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/queue"
@@ -77,18 +78,72 @@ func index(ctx context.Context, indexer internal.Indexer, repoID int64) error {
 	if err != nil {
 		return err
 	}
-	changes, err := getRepoChanges(ctx, repo, sha)
+	status, err := repo_model.GetIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCode)
+	if err != nil {
+		return err
+	}
+	changes, err := getRepoChanges(ctx, repo, "", status.CommitSha, sha)
 	if err != nil {
 		return err
 	} else if changes == nil {
 		return nil
 	}
 
-	if err := indexer.Index(ctx, repo, sha, changes); err != nil {
+	if err := indexer.Index(ctx, repo, "", sha, changes); err != nil {
 		return err
 	}
 
-	return repo_model.UpdateIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCode, sha)
+	if err := repo_model.UpdateIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCode, sha); err != nil {
+		return err
+	}
+
+	return indexRefs(ctx, indexer, repo)
+}
+
+// indexRefs updates the branches and tags indexed besides the default branch, and removes the ones not indexed anymore
+func indexRefs(ctx context.Context, indexer internal.Indexer, repo *repo_model.Repository) error {
+	refs, err := getIndexedRefs(ctx, repo)
+	if err != nil {
+		return err
+	}
+	statuses, err := repo_model.GetCodeIndexerRefStatuses(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	indexedShas := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		indexedShas[status.Ref] = status.CommitSha
+	}
+
+	for _, ref := range refs {
+		name := ref.Name.String()
+		indexedSha := indexedShas[name]
+		delete(indexedShas, name)
+		if indexedSha == ref.CommitID {
+			continue
+		}
+
+		changes, err := getRepoChanges(ctx, repo, name, indexedSha, ref.CommitID)
+		if err != nil {
+			return err
+		}
+		if err := indexer.Index(ctx, repo, name, ref.CommitID, changes); err != nil {
+			return err
+		}
+		if err := repo_model.UpdateCodeIndexerRefStatus(ctx, repo.ID, name, ref.CommitID); err != nil {
+			return err
+		}
+	}
+
+	for name := range indexedShas {
+		if err := indexer.DeleteRef(ctx, repo.ID, name); err != nil {
+			return err
+		}
+		if err := repo_model.DeleteCodeIndexerRefStatus(ctx, repo.ID, name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Init initialize the repo indexer
@@ -117,7 +172,7 @@ func Init() {
 
 	// Create the Queue
 	switch setting.Indexer.RepoType {
-	case "bleve", "elasticsearch":
+	case "bleve", "elasticsearch", "trigram":
 		handler := func(items ...*internal.IndexerData) (unhandled []*internal.IndexerData) {
 			indexer := *globalIndexer.Load()
 			for _, indexerData := range items {
@@ -167,6 +222,16 @@ func Init() {
 				close(waitChannel)
 				log.Fatal("PID: %d Unable to initialize the bleve Repository Indexer at path: %s Error: %v", os.Getpid(), setting.Indexer.RepoPath, err)
 			}
+		case "trigram":
+			log.Info("PID: %d Initializing Repository Indexer at: %s", os.Getpid(), setting.Indexer.RepoPath)
+			rIndexer = trigram.NewIndexer(setting.Indexer.RepoPath)
+			existed, err = rIndexer.Init(ctx)
+			if err != nil {
+				cancel()
+				(*globalIndexer.Load()).Close()
+				close(waitChannel)
+				log.Fatal("PID: %d Unable to initialize the trigram Repository Indexer at path: %s Error: %v", os.Getpid(), setting.Indexer.RepoPath, err)
+			}
 		case "elasticsearch":
 			log.Info("PID: %d Initializing Repository Indexer at: %s", os.Getpid(), setting.Indexer.RepoConnStr)
 			defer func() {
@@ -267,9 +332,8 @@ func populateRepoIndexer(ctx context.Context) {
 	}
 
 	// if there is any existing repo indexer metadata in the DB, delete it
-	// since we are starting afresh. Also, xorm requires deletes to have a
-	// condition, and we want to delete everything, thus 1=1.
-	if err := db.DeleteAllRecords("repo_indexer_status"); err != nil {
+	// since we are starting afresh. The statuses of the other indexers are kept.
+	if err := repo_model.DeleteIndexerStatuses(ctx, repo_model.RepoIndexerTypeCode); err != nil {
 		log.Fatal("System error: %v", err)
 	}
 
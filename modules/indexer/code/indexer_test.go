@@ -9,11 +9,14 @@ import (
 	"path/filepath"
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/indexer/code/bleve"
 	"code.gitea.io/gitea/modules/indexer/code/elasticsearch"
 	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/trigram"
 
 	_ "code.gitea.io/gitea/models"
 	_ "code.gitea.io/gitea/models/actions"
@@ -36,6 +39,7 @@ func testIndexer(name string, t *testing.T, indexer internal.Indexer) {
 		keywords := []struct {
 			RepoIDs []int64
 			Keyword string
+			Paths   []string
 			IDs     []int64
 			Langs   int
 		}{
@@ -69,11 +73,32 @@ func testIndexer(name string, t *testing.T, indexer internal.Indexer) {
 				IDs:     []int64{},
 				Langs:   0,
 			},
+			{
+				RepoIDs: nil,
+				Keyword: "Description",
+				Paths:   []string{"READ"},
+				IDs:     []int64{repoID},
+				Langs:   1,
+			},
+			{
+				RepoIDs: nil,
+				Keyword: "Description",
+				Paths:   []string{"*.go", "docs/*"},
+				IDs:     []int64{},
+				Langs:   0,
+			},
 		}
 
 		for _, kw := range keywords {
 			t.Run(kw.Keyword, func(t *testing.T) {
-				total, res, langs, err := indexer.Search(context.TODO(), kw.RepoIDs, "", kw.Keyword, 1, 10, false)
+				total, res, langs, err := indexer.Search(context.TODO(), &internal.SearchOptions{
+					RepoIDs:        kw.RepoIDs,
+					Keyword:        kw.Keyword,
+					Paths:          kw.Paths,
+					IsKeywordFuzzy: true,
+					Page:           1,
+					PageSize:       10,
+				})
 				assert.NoError(t, err)
 				assert.Len(t, kw.IDs, int(total))
 				assert.Len(t, langs, kw.Langs)
@@ -87,6 +112,33 @@ func testIndexer(name string, t *testing.T, indexer internal.Indexer) {
 			})
 		}
 
+		t.Run("refs", func(t *testing.T) {
+			search := func(ref string) int64 {
+				total, _, _, err := indexer.Search(context.TODO(), &internal.SearchOptions{
+					Ref:            ref,
+					Keyword:        "second",
+					IsKeywordFuzzy: true,
+					Page:           1,
+					PageSize:       10,
+				})
+				assert.NoError(t, err)
+				return total
+			}
+
+			repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repoID})
+			repo.CodeIndexerBranches = []string{"branch*"}
+			assert.NoError(t, repo_model.UpdateRepositoryCols(db.DefaultContext, repo, "code_indexer_branches"))
+			assert.NoError(t, index(git.DefaultContext, indexer, repoID))
+			assert.EqualValues(t, 1, search("refs/heads/branch2"))
+			assert.EqualValues(t, 0, search(""))
+
+			// the branch isn't indexed anymore
+			repo.CodeIndexerBranches = nil
+			assert.NoError(t, repo_model.UpdateRepositoryCols(db.DefaultContext, repo, "code_indexer_branches"))
+			assert.NoError(t, index(git.DefaultContext, indexer, repoID))
+			assert.EqualValues(t, 0, search("refs/heads/branch2"))
+		})
+
 		assert.NoError(t, indexer.Delete(context.Background(), repoID))
 	})
 }
@@ -110,6 +162,19 @@ func TestBleveIndexAndSearch(t *testing.T) {
 	testIndexer("beleve", t, idx)
 }
 
+func TestTrigramIndexAndSearch(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	idx := trigram.NewIndexer(t.TempDir())
+	if _, err := idx.Init(context.Background()); err != nil {
+		assert.Fail(t, "Unable to create trigram indexer Error: %v", err)
+		return
+	}
+	defer idx.Close()
+
+	testIndexer("trigram", t, idx)
+}
+
 func TestESIndexAndSearch(t *testing.T) {
 	unittest.PrepareTestEnv(t)
 
@@ -6,6 +6,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/indexer/internal"
@@ -14,9 +15,18 @@ import (
 // Indexer defines an interface to index and search code contents
 type Indexer interface {
 	internal.Indexer
-	Index(ctx context.Context, repo *repo_model.Repository, sha string, changes *RepoChanges) error
+	// Index updates the files of a branch or tag, ref is empty for the default branch
+	Index(ctx context.Context, repo *repo_model.Repository, ref, sha string, changes *RepoChanges) error
 	Delete(ctx context.Context, repoID int64) error
-	Search(ctx context.Context, repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int64, []*SearchResult, []*SearchResultLanguages, error)
+	// DeleteRef deletes the files of a branch or tag which isn't indexed anymore
+	DeleteRef(ctx context.Context, repoID int64, ref string) error
+	Search(ctx context.Context, opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error)
+}
+
+// RegexpSearcher is implemented by the indexers which can match a regular expression on the file contents.
+// The regular expression searches on the other indexers only check a limited number of prefiltered files.
+type RegexpSearcher interface {
+	SearchRegexp(ctx context.Context, opts *SearchOptions, re *regexp.Regexp) (int64, []*SearchResult, []*SearchResultLanguages, error)
 }
 
 // NewDummyIndexer returns a dummy indexer
@@ -30,7 +40,7 @@ type dummyIndexer struct {
 	internal.Indexer
 }
 
-func (d *dummyIndexer) Index(ctx context.Context, repo *repo_model.Repository, sha string, changes *RepoChanges) error {
+func (d *dummyIndexer) Index(ctx context.Context, repo *repo_model.Repository, ref, sha string, changes *RepoChanges) error {
 	return fmt.Errorf("indexer is not ready")
 }
 
@@ -38,6 +48,10 @@ func (d *dummyIndexer) Delete(ctx context.Context, repoID int64) error {
 	return fmt.Errorf("indexer is not ready")
 }
 
-func (d *dummyIndexer) Search(ctx context.Context, repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int64, []*SearchResult, []*SearchResultLanguages, error) {
+func (d *dummyIndexer) DeleteRef(ctx context.Context, repoID int64, ref string) error {
+	return fmt.Errorf("indexer is not ready")
+}
+
+func (d *dummyIndexer) Search(ctx context.Context, opts *SearchOptions) (int64, []*SearchResult, []*SearchResultLanguages, error) {
 	return 0, nil, nil, fmt.Errorf("indexer is not ready")
 }
@@ -23,6 +23,24 @@ type IndexerData struct {
 	RepoID int64
 }
 
+// SearchOptions represents the conditions of a code search
+type SearchOptions struct {
+	RepoIDs []int64
+	// Ref is the full name of the searched branch or tag, the default branch is searched when it is empty
+	Ref      string
+	Keyword  string
+	Language string
+	// Paths are glob patterns, one of them must match the file path
+	Paths []string
+
+	IsKeywordFuzzy bool
+	// IsSymbol searches the keyword in the symbol names instead of the file contents
+	IsSymbol bool
+
+	Page     int
+	PageSize int
+}
+
 // SearchResult result of performing a search in a repo
 type SearchResult struct {
 	RepoID      int64
@@ -4,29 +4,87 @@
 package internal
 
 import (
+	"regexp/syntax"
+	"sort"
 	"strings"
 
 	"code.gitea.io/gitea/modules/indexer/internal"
 	"code.gitea.io/gitea/modules/log"
+
+	"github.com/go-enry/go-enry/v2"
 )
 
-func FilenameIndexerID(repoID int64, filename string) string {
-	return internal.Base36(repoID) + "_" + filename
+// FilenameIndexerID returns the id of a file in the indexer, the ref of the default branch is empty
+func FilenameIndexerID(repoID int64, ref, filename string) string {
+	if ref == "" {
+		return internal.Base36(repoID) + "_" + filename
+	}
+	// ref names can't contain colons
+	return internal.Base36(repoID) + "@" + ref + ":" + filename
 }
 
-func ParseIndexerID(indexerID string) (int64, string) {
-	index := strings.IndexByte(indexerID, '_')
+// ParseIndexerID returns the repository id, the ref and the filename of an id in the indexer
+func ParseIndexerID(indexerID string) (repoID int64, ref, filename string) {
+	index := strings.IndexAny(indexerID, "_@")
 	if index == -1 {
 		log.Error("Unexpected ID in repo indexer: %s", indexerID)
+		return 0, "", indexerID
 	}
-	repoID, _ := internal.ParseBase36(indexerID[:index])
-	return repoID, indexerID[index+1:]
+	repoID, _ = internal.ParseBase36(indexerID[:index])
+	if indexerID[index] == '_' {
+		return repoID, "", indexerID[index+1:]
+	}
+	ref, filename, ok := strings.Cut(indexerID[index+1:], ":")
+	if !ok {
+		log.Error("Unexpected ID in repo indexer: %s", indexerID)
+	}
+	return repoID, ref, filename
 }
 
 func FilenameOfIndexerID(indexerID string) string {
-	index := strings.IndexByte(indexerID, '_')
-	if index == -1 {
-		log.Error("Unexpected ID in repo indexer: %s", indexerID)
+	_, _, filename := ParseIndexerID(indexerID)
+	return filename
+}
+
+// RegexpLiterals returns the literal strings all the matches of the regular expression contain
+func RegexpLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return RegexpLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return RegexpLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var literals []string
+		for _, sub := range re.Sub {
+			literals = append(literals, RegexpLiterals(sub)...)
+		}
+		return literals
+	}
+	return nil
+}
+
+// TopLanguages returns the 10 languages with the most results from their counts
+func TopLanguages(counts map[string]int) []*SearchResultLanguages {
+	languages := make([]*SearchResultLanguages, 0, len(counts))
+	for language, count := range counts {
+		languages = append(languages, &SearchResultLanguages{
+			Language: language,
+			Color:    enry.GetColor(language),
+			Count:    count,
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Count != languages[j].Count {
+			return languages[i].Count > languages[j].Count
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	if len(languages) > 10 {
+		languages = languages[:10]
 	}
-	return indexerID[index+1:]
+	return languages
 }
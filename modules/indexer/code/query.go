@@ -0,0 +1,166 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package code
+
+import (
+	"context"
+	"regexp/syntax"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
+)
+
+// SearchMode is the way the keyword of a code search is matched
+type SearchMode string
+
+const (
+	// SearchModeFuzzy includes the results that closely match the keyword
+	SearchModeFuzzy SearchMode = ""
+	// SearchModeExact includes only the results that match the exact keyword
+	SearchModeExact SearchMode = "match"
+	// SearchModeRegexp matches the keyword as a regular expression
+	SearchModeRegexp SearchMode = "regexp"
+	// SearchModeSymbol searches the keyword in the function and type definitions
+	SearchModeSymbol SearchMode = "symbol"
+)
+
+// ParseSearchMode returns the search mode of the given name, unknown names fall back to fuzzy
+func ParseSearchMode(name string) SearchMode {
+	switch mode := SearchMode(name); mode {
+	case SearchModeExact, SearchModeRegexp, SearchModeSymbol:
+		return mode
+	}
+	return SearchModeFuzzy
+}
+
+// Query is a code search query with its operators extracted
+type Query struct {
+	Keyword  string
+	Language string
+	Paths    []string
+	Repos    []string
+	// IsRegexp is set when the keyword is written as /regexp/
+	IsRegexp bool
+}
+
+// ParseQuery extracts the "path:", "lang:" and "repo:" operators from a search query.
+// The operator values can be quoted to contain spaces, and a keyword surrounded by
+// slashes is a regular expression.
+func ParseQuery(q string) *Query {
+	query := &Query{}
+	var keywords []string
+	for _, field := range indexer_internal.SplitQueryFields(q) {
+		name, value, ok := strings.Cut(field, ":")
+		value = indexer_internal.UnquoteQueryValue(value)
+		switch {
+		case ok && name == "path" && value != "":
+			query.Paths = append(query.Paths, value)
+		case ok && name == "lang" && value != "":
+			query.Language = value
+		case ok && name == "repo" && value != "":
+			query.Repos = append(query.Repos, value)
+		default:
+			keywords = append(keywords, field)
+		}
+	}
+	query.Keyword = strings.Join(keywords, " ")
+	if len(query.Keyword) > 2 && strings.HasPrefix(query.Keyword, "/") && strings.HasSuffix(query.Keyword, "/") {
+		query.Keyword = query.Keyword[1 : len(query.Keyword)-1]
+		query.IsRegexp = true
+	}
+	return query
+}
+
+// ToSearchOptions returns the options to perform the search of the query with. The language and
+// the mode chosen in the search form take precedence over the operators of the query.
+func (q *Query) ToSearchOptions(language string, mode SearchMode) *SearchOptions {
+	if language == "" {
+		language = q.Language
+	}
+	if q.IsRegexp {
+		mode = SearchModeRegexp
+	}
+	return &SearchOptions{
+		Keyword:  q.Keyword,
+		Language: language,
+		Paths:    q.Paths,
+		Mode:     mode,
+	}
+}
+
+// FilterRepoIDs returns the ids of the repositories named by the "repo:" operators which
+// are in allowedRepoIDs. A name without owner is looked up in the repositories of defaultOwner.
+// If allowAll is set, any existing repository can be returned.
+func FilterRepoIDs(ctx context.Context, names []string, defaultOwner string, allowedRepoIDs []int64, allowAll bool) ([]int64, error) {
+	allowed := make(container.Set[int64], len(allowedRepoIDs))
+	allowed.AddMultiple(allowedRepoIDs...)
+
+	repoIDs := make([]int64, 0, len(names))
+	for _, name := range names {
+		ownerName, repoName, ok := strings.Cut(name, "/")
+		if !ok {
+			ownerName, repoName = defaultOwner, name
+		}
+		if ownerName == "" || repoName == "" {
+			continue
+		}
+		repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if allowAll || allowed.Contains(repo.ID) {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	return repoIDs, nil
+}
+
+// regexpPrefilterWord returns a word which must be in any content matched by the regular expression,
+// so the indexers can narrow down the files to check. It returns "" if there is no such word.
+func regexpPrefilterWord(re *syntax.Regexp) string {
+	var best string
+	for _, literal := range internal.RegexpLiterals(re.Simplify()) {
+		for _, word := range completeWords(literal) {
+			if len(word) > len(best) {
+				best = word
+			}
+		}
+	}
+	return best
+}
+
+// completeWords returns the words of a literal which are delimited by separators inside the literal,
+// the words at its ends might be a part of a longer word in the content.
+func completeWords(literal string) []string {
+	var words []string
+	start := -1
+	for i, r := range literal {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start > 0 && isSeparatorRune(r) && isSeparatorRune(rune(literal[start-1])) {
+			words = append(words, literal[start:i])
+		}
+		start = -1
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
+
+// isSeparatorRune reports whether the rune always separates the words for the indexer analyzers
+func isSeparatorRune(r rune) bool {
+	return strings.ContainsRune(" \t\r\n(){}[]<>;,=+-*/!&|^%~\"`#@", r)
+}
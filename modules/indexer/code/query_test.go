@@ -0,0 +1,75 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package code
+
+import (
+	"regexp/syntax"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		q        string
+		expected *Query
+	}{
+		{
+			q:        "func main",
+			expected: &Query{Keyword: "func main"},
+		},
+		{
+			q: `path:modules/ lang:Go repo:user2/repo1 NewServer path:"docs/my notes/*.md"`,
+			expected: &Query{
+				Keyword:  "NewServer",
+				Language: "Go",
+				Paths:    []string{"modules/", "docs/my notes/*.md"},
+				Repos:    []string{"user2/repo1"},
+			},
+		},
+		{
+			q:        `/func \w+\(/ path:*.go`,
+			expected: &Query{Keyword: `func \w+\(`, Paths: []string{"*.go"}, IsRegexp: true},
+		},
+		{
+			q:        "http://example.com path:",
+			expected: &Query{Keyword: "http://example.com path:"},
+		},
+		{
+			q:        "//",
+			expected: &Query{Keyword: "//"},
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ParseQuery(c.q), c.q)
+	}
+}
+
+func TestParseSearchMode(t *testing.T) {
+	assert.Equal(t, SearchModeFuzzy, ParseSearchMode(""))
+	assert.Equal(t, SearchModeFuzzy, ParseSearchMode("unknown"))
+	assert.Equal(t, SearchModeExact, ParseSearchMode("match"))
+	assert.Equal(t, SearchModeRegexp, ParseSearchMode("regexp"))
+	assert.Equal(t, SearchModeSymbol, ParseSearchMode("symbol"))
+}
+
+func TestRegexpPrefilterWord(t *testing.T) {
+	cases := map[string]string{
+		`func NewServer\(`:        "NewServer",
+		`func (NewServer)\(`:      "",
+		` NewServer\(`:            "NewServer",
+		`if err != nil \{`:        "err",
+		`(foo|bar) = baz;`:        "baz",
+		`x = (?:longer_word)+ ;`:  "",
+		`return nil, err\n`:       "nil",
+		`\.ToLower\(name\)`:       "name",
+		`[a-z]+`:                  "",
+		`(?i) BUILD (debug|prod)`: "BUILD",
+	}
+	for expr, expected := range cases {
+		re, err := syntax.Parse(expr, syntax.Perl)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, regexpPrefilterWord(re), expr)
+	}
+}
@@ -7,13 +7,39 @@ import (
 	"bytes"
 	"context"
 	"html/template"
+	"regexp"
+	"regexp/syntax"
 	"strings"
 
 	"code.gitea.io/gitea/modules/highlight"
 	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/symbols"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 )
 
+// RegexpMaxCandidates is the maximum number of files a regular expression is checked against when
+// the indexer can't match regular expressions, the files are fetched and checked one by one.
+const RegexpMaxCandidates = 500
+
+// SearchOptions represents the options of a code search
+type SearchOptions struct {
+	RepoIDs []int64
+	// Ref is the full name of the searched branch or tag, the default branch is searched when it is empty.
+	// The branches and tags other than the default branch are only indexed when they match the patterns
+	// configured in the repository.
+	Ref      string
+	Keyword  string
+	Language string
+	// Paths are glob patterns, a file must match one of them. A pattern without wildcards
+	// matches the files whose path contains it.
+	Paths []string
+	Mode  SearchMode
+
+	Page     int
+	PageSize int
+}
+
 // Result a search result to display
 type Result struct {
 	RepoID         int64
@@ -23,7 +49,10 @@ type Result struct {
 	Language       string
 	Color          string
 	LineNumbers    []int
+	RawLines       []string
 	FormattedLines template.HTML
+	// Symbol is the matched definition of a symbol search
+	Symbol *symbols.Symbol
 }
 
 type SearchResultLanguages = internal.SearchResultLanguages
@@ -71,6 +100,7 @@ func searchResult(result *internal.SearchResult, startIndex, endIndex int) (*Res
 
 	contentLines := strings.SplitAfter(result.Content[startIndex:endIndex], "\n")
 	lineNumbers := make([]int, len(contentLines))
+	rawLines := make([]string, len(contentLines))
 	index := startIndex
 	for i, line := range contentLines {
 		var err error
@@ -94,6 +124,7 @@ func searchResult(result *internal.SearchResult, startIndex, endIndex int) (*Res
 		}
 
 		lineNumbers[i] = startLineNum + i
+		rawLines[i] = strings.TrimRight(line, "\r\n")
 		index += len(line)
 	}
 
@@ -107,29 +138,123 @@ func searchResult(result *internal.SearchResult, startIndex, endIndex int) (*Res
 		Language:       result.Language,
 		Color:          result.Color,
 		LineNumbers:    lineNumbers,
+		RawLines:       rawLines,
 		FormattedLines: highlighted,
 	}, nil
 }
 
-// PerformSearch perform a search on a repository
-func PerformSearch(ctx context.Context, repoIDs []int64, language, keyword string, page, pageSize int, isMatch bool) (int, []*Result, []*internal.SearchResultLanguages, error) {
-	if len(keyword) == 0 {
-		return 0, nil, nil, nil
+// PerformSearch perform a search on a repository. A regular expression search may only check some of the files,
+// then truncated is true and the results and their total only cover the checked files.
+func PerformSearch(ctx context.Context, opts *SearchOptions) (total int, displayResults []*Result, resultLanguages []*internal.SearchResultLanguages, truncated bool, err error) {
+	if len(opts.Keyword) == 0 {
+		return 0, nil, nil, false, nil
 	}
 
-	total, results, resultLanguages, err := (*globalIndexer.Load()).Search(ctx, repoIDs, language, keyword, page, pageSize, isMatch)
+	var (
+		searchTotal int64
+		results     []*internal.SearchResult
+	)
+	if opts.Mode == SearchModeRegexp {
+		searchTotal, results, resultLanguages, truncated, err = searchRegexp(ctx, opts)
+	} else {
+		searchTotal, results, resultLanguages, err = (*globalIndexer.Load()).Search(ctx, &internal.SearchOptions{
+			RepoIDs:        opts.RepoIDs,
+			Ref:            opts.Ref,
+			Keyword:        opts.Keyword,
+			Language:       opts.Language,
+			Paths:          opts.Paths,
+			IsKeywordFuzzy: opts.Mode == SearchModeFuzzy,
+			IsSymbol:       opts.Mode == SearchModeSymbol,
+			Page:           opts.Page,
+			PageSize:       opts.PageSize,
+		})
+	}
 	if err != nil {
-		return 0, nil, nil, err
+		return 0, nil, nil, false, err
 	}
 
-	displayResults := make([]*Result, len(results))
+	displayResults = make([]*Result, len(results))
 
 	for i, result := range results {
+		var symbol *symbols.Symbol
+		if opts.Mode == SearchModeSymbol {
+			// the indexers only know which files define the symbol, find the definition to display it
+			if s, ok := symbols.Match(symbols.Extract(result.Language, result.Content), opts.Keyword, true); ok {
+				symbol = &s
+				result.StartIndex, result.EndIndex = s.Offset, s.Offset+len(s.Name)
+			}
+		}
+		if result.StartIndex < 0 || result.EndIndex < result.StartIndex {
+			result.StartIndex, result.EndIndex = 0, 0
+		}
 		startIndex, endIndex := indices(result.Content, result.StartIndex, result.EndIndex)
 		displayResults[i], err = searchResult(result, startIndex, endIndex)
 		if err != nil {
-			return 0, nil, nil, err
+			return 0, nil, nil, false, err
 		}
+		displayResults[i].Symbol = symbol
+	}
+	return int(searchTotal), displayResults, resultLanguages, truncated, nil
+}
+
+// searchRegexp searches a regular expression. If the indexer can't match regular expressions, the files
+// the regular expression might match are fetched from the indexer and checked, only the first
+// RegexpMaxCandidates files are checked and the search is truncated when there are more.
+func searchRegexp(ctx context.Context, opts *SearchOptions) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, bool, error) {
+	parsed, err := syntax.Parse(opts.Keyword, syntax.Perl)
+	if err != nil {
+		return 0, nil, nil, false, util.NewInvalidArgumentErrorf("invalid regular expression: %v", err)
 	}
-	return int(total), displayResults, resultLanguages, nil
+	re, err := regexp.Compile(opts.Keyword)
+	if err != nil {
+		return 0, nil, nil, false, util.NewInvalidArgumentErrorf("invalid regular expression: %v", err)
+	}
+
+	indexer := *globalIndexer.Load()
+	if searcher, ok := indexer.(internal.RegexpSearcher); ok {
+		total, results, resultLanguages, err := searcher.SearchRegexp(ctx, &internal.SearchOptions{
+			RepoIDs:  opts.RepoIDs,
+			Ref:      opts.Ref,
+			Language: opts.Language,
+			Paths:    opts.Paths,
+			Page:     opts.Page,
+			PageSize: opts.PageSize,
+		}, re)
+		return total, results, resultLanguages, false, err
+	}
+
+	// the language is filtered here so the language counts include all the matched files
+	candidatesTotal, candidates, _, err := indexer.Search(ctx, &internal.SearchOptions{
+		RepoIDs:        opts.RepoIDs,
+		Ref:            opts.Ref,
+		Keyword:        regexpPrefilterWord(parsed),
+		Paths:          opts.Paths,
+		IsKeywordFuzzy: true,
+		Page:           1,
+		PageSize:       RegexpMaxCandidates,
+	})
+	if err != nil {
+		return 0, nil, nil, false, err
+	}
+
+	languageCounts := make(map[string]int)
+	matched := make([]*internal.SearchResult, 0, len(candidates))
+	for _, candidate := range candidates {
+		loc := re.FindStringIndex(candidate.Content)
+		if loc == nil {
+			continue
+		}
+		if candidate.Language != "" {
+			languageCounts[candidate.Language]++
+		}
+		if opts.Language != "" && candidate.Language != opts.Language {
+			continue
+		}
+		candidate.StartIndex, candidate.EndIndex = loc[0], loc[1]
+		matched = append(matched, candidate)
+	}
+
+	resultLanguages := internal.TopLanguages(languageCounts)
+	truncated := candidatesTotal > int64(len(candidates))
+	return int64(len(matched)), util.PaginateSlice(matched, opts.Page, opts.PageSize).([]*internal.SearchResult), resultLanguages, truncated, nil
 }
@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package code
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// candidatesIndexer returns the first files of a fixed number of files for any search
+type candidatesIndexer struct {
+	internal.Indexer
+	files int
+}
+
+func (c *candidatesIndexer) Search(ctx context.Context, opts *internal.SearchOptions) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	results := make([]*internal.SearchResult, 0, opts.PageSize)
+	for i := 0; i < c.files && i < opts.PageSize; i++ {
+		results = append(results, &internal.SearchResult{
+			RepoID:   1,
+			Filename: fmt.Sprintf("file%d.txt", i),
+			Content:  fmt.Sprintf("line %d\n", i),
+		})
+	}
+	return int64(c.files), results, nil, nil
+}
+
+func TestPerformSearchRegexpTruncated(t *testing.T) {
+	defer func(indexer internal.Indexer) {
+		globalIndexer.Store(&indexer)
+	}(*globalIndexer.Load())
+
+	search := func(files int) (int, bool) {
+		var indexer internal.Indexer = &candidatesIndexer{Indexer: internal.NewDummyIndexer(), files: files}
+		globalIndexer.Store(&indexer)
+		total, _, _, truncated, err := PerformSearch(context.Background(), &SearchOptions{
+			Keyword:  `line \d+`,
+			Mode:     SearchModeRegexp,
+			Page:     1,
+			PageSize: 10,
+		})
+		assert.NoError(t, err)
+		return total, truncated
+	}
+
+	total, truncated := search(RegexpMaxCandidates)
+	assert.Equal(t, RegexpMaxCandidates, total)
+	assert.False(t, truncated)
+
+	// only the first files are checked
+	total, truncated = search(RegexpMaxCandidates + 1)
+	assert.Equal(t, RegexpMaxCandidates, total)
+	assert.True(t, truncated)
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package symbols
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxSymbolsPerFile limits the symbols extracted from generated or very large files
+const maxSymbolsPerFile = 1000
+
+// Kind is the kind of definition a symbol is
+type Kind string
+
+// The kinds of symbols
+const (
+	KindFunction  Kind = "function"
+	KindMethod    Kind = "method"
+	KindClass     Kind = "class"
+	KindStruct    Kind = "struct"
+	KindInterface Kind = "interface"
+	KindEnum      Kind = "enum"
+	KindType      Kind = "type"
+	KindTrait     Kind = "trait"
+	KindModule    Kind = "module"
+	KindConstant  Kind = "constant"
+	KindVariable  Kind = "variable"
+	KindMacro     Kind = "macro"
+)
+
+// Symbol is a definition found in a file
+type Symbol struct {
+	Name string
+	Kind Kind
+	// Line is the 1-based line number of the definition
+	Line int
+	// Offset is the byte offset of the name in the content
+	Offset int
+}
+
+// pattern matches a definition on a single line, the name of the symbol is the group "name".
+// If kind is empty, the kind is taken from the group "kind".
+type pattern struct {
+	re   *regexp.Regexp
+	kind Kind
+}
+
+func p(kind Kind, expr string) pattern {
+	return pattern{re: regexp.MustCompile(expr), kind: kind}
+}
+
+const (
+	javaModifiers   = `(?:(?:public|private|protected|internal|static|final|abstract|sealed|partial|readonly|data|open|inner|override|virtual|async|synchronized|native|unsafe|extern|new)\s+)*`
+	cFunctionPrefix = `^(?:(?:static|inline|extern|const|unsigned|signed|struct|virtual|constexpr)\s+)*[A-Za-z_][\w:<>,]*(?:\s*[\*&]+\s*|\s+)`
+)
+
+var (
+	goPatterns = []pattern{
+		p(KindMethod, `^func\s+\([^)]*\)\s*(?P<name>\w+)\s*[\[(]`),
+		p(KindFunction, `^func\s+(?P<name>\w+)\s*[\[(]`),
+		p(KindInterface, `^\s*type\s+(?P<name>\w+)(?:\[[^\]]*\])?\s+interface\b`),
+		p(KindStruct, `^\s*type\s+(?P<name>\w+)(?:\[[^\]]*\])?\s+struct\b`),
+		p(KindType, `^\s*type\s+(?P<name>\w+)\b`),
+		p(KindConstant, `^const\s+(?P<name>\w+)\b`),
+		p(KindVariable, `^var\s+(?P<name>\w+)\b`),
+	}
+	pythonPatterns = []pattern{
+		p(KindMethod, `^\s+(?:async\s+)?def\s+(?P<name>\w+)\s*\(`),
+		p(KindFunction, `^(?:async\s+)?def\s+(?P<name>\w+)\s*\(`),
+		p(KindClass, `^\s*class\s+(?P<name>\w+)\b`),
+	}
+	javaScriptPatterns = []pattern{
+		p(KindFunction, `^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(?P<name>[\w$]+)\s*[<(]`),
+		p(KindClass, `^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(?P<name>[\w$]+)\b`),
+		p(KindFunction, `^\s*(?:export\s+)?(?:const|let|var)\s+(?P<name>[\w$]+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|[\w$]+\s*=>)`),
+	}
+	typeScriptPatterns = append([]pattern{
+		p(KindInterface, `^\s*(?:export\s+)?(?:declare\s+)?interface\s+(?P<name>[\w$]+)\b`),
+		p(KindType, `^\s*(?:export\s+)?(?:declare\s+)?type\s+(?P<name>[\w$]+)\b[^=]*=`),
+		p(KindEnum, `^\s*(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+(?P<name>[\w$]+)\b`),
+	}, javaScriptPatterns...)
+	javaPatterns = []pattern{
+		p("", `^\s*`+javaModifiers+`(?P<kind>class|interface|enum|record|struct)\s+(?P<name>\w+)\b`),
+		p(KindMethod, `^\s*`+javaModifiers+`(?:<[^>]+>\s+)?[\w.<>\[\],?]+\s+(?P<name>\w+)\s*\([^;]*$`),
+	}
+	kotlinPatterns = []pattern{
+		p("", `^\s*`+javaModifiers+`(?P<kind>class|interface|object)\s+(?P<name>\w+)\b`),
+		p(KindFunction, `^\s*`+javaModifiers+`fun\s+(?:<[^>]+>\s*)?(?:[\w.]+\.)?(?P<name>\w+)\s*\(`),
+	}
+	cPatterns = []pattern{
+		p(KindMacro, `^\s*#\s*define\s+(?P<name>\w+)`),
+		p("", `^\s*(?:typedef\s+)?(?P<kind>struct|union|enum|class)\s+(?P<name>\w+)\s*(?:[:{]|$)`),
+		p(KindFunction, cFunctionPrefix+`(?P<name>[A-Za-z_][\w:~]*)\s*\([^;]*$`),
+	}
+	rustPatterns = []pattern{
+		p(KindFunction, `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:(?:async|const|unsafe|extern\s+"[^"]*")\s+)*fn\s+(?P<name>\w+)`),
+		p("", `^\s*(?:pub(?:\([^)]*\))?\s+)?(?:unsafe\s+)?(?P<kind>struct|enum|trait|type|mod|const|static|macro_rules!)\s*(?P<name>\w+)`),
+	}
+	rubyPatterns = []pattern{
+		p(KindMethod, `^\s*def\s+(?:self\.)?(?P<name>\w+[?!=]?)`),
+		p("", `^\s*(?P<kind>class|module)\s+(?:\w+::)*(?P<name>\w+)`),
+	}
+	phpPatterns = []pattern{
+		p(KindFunction, `^\s*(?:(?:public|private|protected|static|abstract|final)\s+)*function\s+&?(?P<name>\w+)\s*\(`),
+		p("", `^\s*(?:(?:abstract|final|readonly)\s+)*(?P<kind>class|interface|trait|enum)\s+(?P<name>\w+)\b`),
+	}
+	shellPatterns = []pattern{
+		p(KindFunction, `^\s*function\s+(?P<name>[\w-]+)`),
+		p(KindFunction, `^\s*(?P<name>[\w-]+)\s*\(\)\s*\{?`),
+	}
+
+	// languagePatterns are the patterns of the languages supported by the symbol index, keyed by enry language name
+	languagePatterns = map[string][]pattern{
+		"Go":          goPatterns,
+		"Python":      pythonPatterns,
+		"JavaScript":  javaScriptPatterns,
+		"JSX":         javaScriptPatterns,
+		"TypeScript":  typeScriptPatterns,
+		"TSX":         typeScriptPatterns,
+		"Java":        javaPatterns,
+		"C#":          javaPatterns,
+		"Kotlin":      kotlinPatterns,
+		"C":           cPatterns,
+		"C++":         cPatterns,
+		"Objective-C": cPatterns,
+		"Rust":        rustPatterns,
+		"Ruby":        rubyPatterns,
+		"PHP":         phpPatterns,
+		"Shell":       shellPatterns,
+	}
+
+	kindAliases = map[string]Kind{
+		"class":        KindClass,
+		"object":       KindClass,
+		"record":       KindClass,
+		"struct":       KindStruct,
+		"union":        KindStruct,
+		"interface":    KindInterface,
+		"enum":         KindEnum,
+		"type":         KindType,
+		"trait":        KindTrait,
+		"mod":          KindModule,
+		"module":       KindModule,
+		"const":        KindConstant,
+		"static":       KindVariable,
+		"macro_rules!": KindMacro,
+	}
+
+	// keywords which look like a function call but are not definitions
+	notSymbols = map[string]bool{
+		"if": true, "for": true, "while": true, "switch": true, "return": true, "catch": true,
+		"sizeof": true, "else": true, "do": true, "case": true, "new": true, "delete": true, "throw": true,
+	}
+)
+
+// IsSupported returns whether symbols can be extracted from the given language
+func IsSupported(language string) bool {
+	_, ok := languagePatterns[language]
+	return ok
+}
+
+// Extract extracts the definitions from the content of a file written in the given language.
+// The parsing is line based like ctags does, so definitions spanning several lines are only
+// found by their first line.
+func Extract(language, content string) []Symbol {
+	patterns, ok := languagePatterns[language]
+	if !ok {
+		return nil
+	}
+
+	var symbols []Symbol
+	offset := 0
+	for lineNum := 1; offset < len(content) && len(symbols) < maxSymbolsPerFile; lineNum++ {
+		line := content[offset:]
+		if i := strings.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		if symbol, ok := matchLine(patterns, strings.TrimRight(line, "\r\n")); ok {
+			symbol.Line = lineNum
+			symbol.Offset += offset
+			symbols = append(symbols, symbol)
+		}
+		offset += len(line)
+	}
+	return symbols
+}
+
+func matchLine(patterns []pattern, line string) (Symbol, bool) {
+	for _, pat := range patterns {
+		m := pat.re.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		nameIdx := pat.re.SubexpIndex("name")
+		name := line[m[2*nameIdx]:m[2*nameIdx+1]]
+		if notSymbols[name] {
+			continue
+		}
+		kind := pat.kind
+		if kind == "" {
+			kindIdx := pat.re.SubexpIndex("kind")
+			kind = kindAliases[line[m[2*kindIdx]:m[2*kindIdx+1]]]
+		}
+		return Symbol{Name: name, Kind: kind, Offset: m[2*nameIdx]}, true
+	}
+	return Symbol{}, false
+}
+
+// Names returns the distinct lower-cased names of the symbols, as they are stored in the indexers
+func Names(symbols []Symbol) []string {
+	names := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		name := strings.ToLower(symbol.Name)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Match returns the first symbol whose name is the keyword, or starts with it if isPrefix is set.
+// The comparison is case-insensitive.
+func Match(symbols []Symbol, keyword string, isPrefix bool) (Symbol, bool) {
+	keyword = strings.ToLower(keyword)
+	for _, symbol := range symbols {
+		name := strings.ToLower(symbol.Name)
+		if name == keyword || (isPrefix && strings.HasPrefix(name, keyword)) {
+			return symbol, true
+		}
+	}
+	return Symbol{}, false
+}
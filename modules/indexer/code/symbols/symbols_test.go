@@ -0,0 +1,122 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package symbols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	type sym struct {
+		Name string
+		Kind Kind
+		Line int
+	}
+	cases := []struct {
+		language string
+		content  string
+		expected []sym
+	}{
+		{
+			language: "Go",
+			content: `package main
+
+type Server struct {
+	addr string
+}
+
+type Handler interface {
+	Serve()
+}
+
+func (s *Server) Start() error {
+	return nil
+}
+
+func NewServer[T any](addr string) *Server {
+	if addr == "" {
+		return nil
+	}
+	return &Server{addr: addr}
+}
+`,
+			expected: []sym{
+				{"Server", KindStruct, 3},
+				{"Handler", KindInterface, 7},
+				{"Start", KindMethod, 11},
+				{"NewServer", KindFunction, 15},
+			},
+		},
+		{
+			language: "Python",
+			content:  "class Repo(object):\n    def __init__(self):\n        pass\n\nasync def fetch(url):\n    return url\n",
+			expected: []sym{
+				{"Repo", KindClass, 1},
+				{"__init__", KindMethod, 2},
+				{"fetch", KindFunction, 5},
+			},
+		},
+		{
+			language: "TypeScript",
+			content:  "export interface Options {}\nexport type ID = number;\nexport const load = async (id: ID) => {};\nfunction render(el) {\n}\n",
+			expected: []sym{
+				{"Options", KindInterface, 1},
+				{"ID", KindType, 2},
+				{"load", KindFunction, 3},
+				{"render", KindFunction, 4},
+			},
+		},
+		{
+			language: "C",
+			content:  "#define MAX 10\nstruct point {\n\tint x;\n};\nstatic int add(int a, int b)\n{\n\tif (a)\n\t\treturn add(a, b);\n}\nint sub(int a, int b);\n",
+			expected: []sym{
+				{"MAX", KindMacro, 1},
+				{"point", KindStruct, 2},
+				{"add", KindFunction, 5},
+			},
+		},
+		{
+			language: "Rust",
+			content:  "pub struct Config {}\npub(crate) async fn run() {}\ntrait Named {}\n",
+			expected: []sym{
+				{"Config", KindStruct, 1},
+				{"run", KindFunction, 2},
+				{"Named", KindTrait, 3},
+			},
+		},
+		{
+			language: "Markdown",
+			content:  "# func main() {}\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			var actual []sym
+			for _, s := range Extract(c.language, c.content) {
+				actual = append(actual, sym{s.Name, s.Kind, s.Line})
+				assert.Equal(t, s.Name, c.content[s.Offset:s.Offset+len(s.Name)])
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	symbols := Extract("Go", "func NewServer() {}\nfunc newServerWithOptions() {}\n")
+	assert.Equal(t, []string{"newserver", "newserverwithoptions"}, Names(symbols))
+
+	s, ok := Match(symbols, "newserverwith", false)
+	assert.False(t, ok)
+
+	s, ok = Match(symbols, "newserverwith", true)
+	assert.True(t, ok)
+	assert.Equal(t, "newServerWithOptions", s.Name)
+
+	s, ok = Match(symbols, "NEWSERVER", false)
+	assert.True(t, ok)
+	assert.Equal(t, 1, s.Line)
+}
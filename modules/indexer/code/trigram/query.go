@@ -0,0 +1,145 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package trigram
+
+import (
+	"bytes"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
+)
+
+// matcher finds the first match of a query in a content, it returns nil if there is none
+type matcher func(content []byte) []int
+
+// keywordTrigrams returns the distinct trigrams of the keywords
+func keywordTrigrams(keywords ...string) []trigram {
+	set := make(map[trigram]struct{})
+	for _, keyword := range keywords {
+		for t := range trigramsOf([]byte(keyword)) {
+			set[t] = struct{}{}
+		}
+	}
+	trigrams := make([]trigram, 0, len(set))
+	for t := range set {
+		trigrams = append(trigrams, t)
+	}
+	return trigrams
+}
+
+// regexpTrigrams returns the trigrams all the matches of the regular expression contain.
+// Only the trigrams made of ASCII characters are used, the others can't be case folded byte by byte.
+func regexpTrigrams(re *syntax.Regexp) []trigram {
+	var trigrams []trigram
+	for _, t := range keywordTrigrams(internal.RegexpLiterals(re.Simplify())...) {
+		if t&0x808080 == 0 {
+			trigrams = append(trigrams, t)
+		}
+	}
+	return trigrams
+}
+
+// indexFold returns the index of the first case-insensitive occurrence of the lower-cased keyword, ASCII
+// letters only are folded so the positions are the ones of the content
+func indexFold(content, lowerKeyword []byte) int {
+	if len(lowerKeyword) == 0 {
+		return 0
+	}
+	for i := 0; i+len(lowerKeyword) <= len(content); i++ {
+		j := 0
+		for ; j < len(lowerKeyword) && lowerASCII(content[i+j]) == lowerKeyword[j]; j++ {
+		}
+		if j == len(lowerKeyword) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lowerKeyword(keyword string) []byte {
+	b := []byte(keyword)
+	for i := range b {
+		b[i] = lowerASCII(b[i])
+	}
+	return b
+}
+
+// substringMatcher matches the exact keyword, case-insensitively
+func substringMatcher(keyword string) matcher {
+	lower := lowerKeyword(keyword)
+	return func(content []byte) []int {
+		if i := indexFold(content, lower); i >= 0 {
+			return []int{i, i + len(lower)}
+		}
+		return nil
+	}
+}
+
+// wordsMatcher matches the contents containing all the words of the keyword in any order,
+// the match is the first occurrence of the first word
+func wordsMatcher(words []string) matcher {
+	lowers := make([][]byte, len(words))
+	for i, word := range words {
+		lowers[i] = lowerKeyword(word)
+	}
+	return func(content []byte) []int {
+		var loc []int
+		for i, lower := range lowers {
+			idx := indexFold(content, lower)
+			if idx < 0 {
+				return nil
+			}
+			if i == 0 {
+				loc = []int{idx, idx + len(lower)}
+			}
+		}
+		return loc
+	}
+}
+
+func regexpMatcher(re *regexp.Regexp) matcher {
+	return re.FindIndex
+}
+
+// symbolMatcher matches the documents defining a symbol starting with the keyword
+func symbolMatcher(keyword string) func(doc *document) bool {
+	keyword = strings.ToLower(keyword)
+	return func(doc *document) bool {
+		for _, name := range doc.Symbols {
+			if strings.HasPrefix(name, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// pathMatcher converts the path filters to a regular expression, "*" and "?" being the only wildcards
+func pathMatcher(paths []string) *regexp.Regexp {
+	if len(paths) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`^(?:`)
+	for i, path := range paths {
+		if i > 0 {
+			buf.WriteByte('|')
+		}
+		for _, part := range strings.SplitAfter(indexer_internal.PathWildcard(path), "") {
+			switch part {
+			case "*":
+				buf.WriteString(`.*`)
+			case "?":
+				buf.WriteString(`.`)
+			default:
+				buf.WriteString(regexp.QuoteMeta(part))
+			}
+		}
+	}
+	buf.WriteString(`)$`)
+	return regexp.MustCompile(buf.String())
+}
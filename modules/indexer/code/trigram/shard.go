@@ -0,0 +1,244 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package trigram
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// A shard file is laid out as:
+//
+//	contents of the documents, one after another
+//	gob encoded shardIndex
+//	offset of the shardIndex (uint64, little endian)
+//	shardMagic
+const shardMagic = "GTRIGRAM"
+
+const shardTrailerSize = 8 + len(shardMagic)
+
+// trigram is three consecutive bytes of a content, ASCII letters are lower-cased
+type trigram uint32
+
+// document is the metadata of an indexed file, its content is stored in the shard file
+type document struct {
+	Filename    string
+	CommitID    string
+	Language    string
+	Symbols     []string
+	UpdatedUnix timeutil.TimeStamp
+	Offset      int64
+	Size        int64
+}
+
+// shardIndex is the part of a shard kept in memory
+type shardIndex struct {
+	Version int
+	RepoID  int64
+	// Ref is the full name of the branch or tag of the documents, it is empty for the default branch
+	Ref       string
+	Documents []*document
+	// Postings are the sorted indexes of the documents containing each trigram
+	Postings map[trigram][]uint32
+}
+
+type shard struct {
+	shardIndex
+	path string
+	seq  int
+}
+
+// pendingDocument is a document with its content, waiting to be written in a shard
+type pendingDocument struct {
+	document
+	Content []byte
+}
+
+func lowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// trigramsOf returns the distinct trigrams of the content
+func trigramsOf(content []byte) map[trigram]struct{} {
+	trigrams := make(map[trigram]struct{})
+	for i := 0; i+3 <= len(content); i++ {
+		trigrams[trigram(lowerASCII(content[i]))<<16|trigram(lowerASCII(content[i+1]))<<8|trigram(lowerASCII(content[i+2]))] = struct{}{}
+	}
+	return trigrams
+}
+
+func shardFileName(seq int) string {
+	return fmt.Sprintf("%08d.shard", seq)
+}
+
+// writeShard writes the documents in a new shard file, the file is renamed in place once complete
+func writeShard(dir string, repoID int64, ref string, seq int, docs []*pendingDocument) (*shard, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, "tmp-*.shard")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = util.Remove(tmp.Name())
+	}()
+
+	s := &shard{
+		shardIndex: shardIndex{
+			Version:   trigramIndexerLatestVersion,
+			RepoID:    repoID,
+			Ref:       ref,
+			Documents: make([]*document, 0, len(docs)),
+			Postings:  make(map[trigram][]uint32),
+		},
+		path: filepath.Join(dir, shardFileName(seq)),
+		seq:  seq,
+	}
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Filename < docs[j].Filename })
+
+	w := bufio.NewWriter(tmp)
+	var offset int64
+	for i, doc := range docs {
+		if _, err := w.Write(doc.Content); err != nil {
+			return nil, err
+		}
+		d := doc.document
+		d.Offset, d.Size = offset, int64(len(doc.Content))
+		offset += d.Size
+		s.Documents = append(s.Documents, &d)
+
+		// the documents are added in order so the posting lists stay sorted
+		for t := range trigramsOf(doc.Content) {
+			s.Postings[t] = append(s.Postings[t], uint32(i))
+		}
+	}
+	if err := gob.NewEncoder(w).Encode(&s.shardIndex); err != nil {
+		return nil, err
+	}
+	var trailer [shardTrailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[:8], uint64(offset))
+	copy(trailer[8:], shardMagic)
+	if _, err := w.Write(trailer[:]); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// readShard reads the index of a shard file, the contents are read on demand
+func readShard(path string, seq int) (*shard, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() < int64(shardTrailerSize) {
+		return nil, fmt.Errorf("shard %s is truncated", path)
+	}
+	var trailer [shardTrailerSize]byte
+	if _, err := f.ReadAt(trailer[:], stat.Size()-int64(shardTrailerSize)); err != nil {
+		return nil, err
+	}
+	if string(trailer[8:]) != shardMagic {
+		return nil, fmt.Errorf("%s is not a shard file", path)
+	}
+	offset := int64(binary.LittleEndian.Uint64(trailer[:8]))
+	if offset > stat.Size()-int64(shardTrailerSize) {
+		return nil, fmt.Errorf("shard %s is corrupted", path)
+	}
+
+	s := &shard{path: path, seq: seq}
+	r := io.NewSectionReader(f, offset, stat.Size()-int64(shardTrailerSize)-offset)
+	if err := gob.NewDecoder(bufio.NewReader(r)).Decode(&s.shardIndex); err != nil {
+		return nil, fmt.Errorf("unable to decode shard %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// candidates returns the indexes of the documents containing all the trigrams, or all the documents if there is none
+func (s *shard) candidates(trigrams []trigram) []uint32 {
+	if len(trigrams) == 0 {
+		all := make([]uint32, len(s.Documents))
+		for i := range all {
+			all[i] = uint32(i)
+		}
+		return all
+	}
+
+	lists := make([][]uint32, 0, len(trigrams))
+	for _, t := range trigrams {
+		list, ok := s.Postings[t]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	// intersect the shortest lists first
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+	result := lists[0]
+	for _, list := range lists[1:] {
+		result = intersect(result, list)
+		if len(result) == 0 {
+			return nil
+		}
+	}
+	return result
+}
+
+func intersect(a, b []uint32) []uint32 {
+	result := make([]uint32, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// readContent reads the content of a document from the opened shard file
+func readContent(f *os.File, doc *document) ([]byte, error) {
+	content := make([]byte, doc.Size)
+	if _, err := f.ReadAt(content, doc.Offset); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
@@ -0,0 +1,644 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package trigram
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/analyze"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/indexer/code/symbols"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/ethantkoenig/rupture"
+	"github.com/go-enry/go-enry/v2"
+)
+
+const (
+	trigramIndexerLatestVersion = 2
+	// maxShardSize is the size of the contents after which a shard is full
+	maxShardSize = 32 * 1024 * 1024
+)
+
+var (
+	_ internal.Indexer        = &Indexer{}
+	_ internal.RegexpSearcher = &Indexer{}
+)
+
+// errNotInitialized is returned when the indexer isn't initialized yet or has been closed
+var errNotInitialized = errors.New("indexer is not initialized")
+
+// Indexer is an embedded code indexer looking up the files by the trigrams of their contents.
+// Each repository is stored in its own directory as shard files of up to maxShardSize of contents,
+// a shard only contains the files of one branch or tag and an update only rewrites the shards containing changed files.
+type Indexer struct {
+	indexDir string
+
+	// writeMu serializes the updates of the index
+	writeMu sync.Mutex
+	// mu protects shards, it is held for reading during the searches so the shard files aren't removed meanwhile
+	mu     sync.RWMutex
+	shards map[int64][]*shard
+}
+
+// NewIndexer creates a new trigram indexer
+func NewIndexer(indexDir string) *Indexer {
+	return &Indexer{
+		indexDir: indexDir,
+	}
+}
+
+// Init initializes the indexer
+func (b *Indexer) Init(_ context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.shards != nil {
+		return false, fmt.Errorf("indexer is already initialized")
+	}
+
+	existed, err := b.open()
+	if err != nil {
+		return false, err
+	}
+	if !existed {
+		if err := os.MkdirAll(b.indexDir, os.ModePerm); err != nil {
+			return false, err
+		}
+		if err := rupture.WriteIndexMetadata(b.indexDir, &rupture.IndexMetadata{
+			Version: trigramIndexerLatestVersion,
+		}); err != nil {
+			return false, err
+		}
+		b.shards = make(map[int64][]*shard)
+	}
+	return existed, nil
+}
+
+// open loads the shards of an existing index, it returns false if the index needs to be created
+func (b *Indexer) open() (bool, error) {
+	exist, err := util.IsExist(b.indexDir)
+	if err != nil || !exist {
+		return false, err
+	}
+
+	metadata, err := rupture.ReadIndexMetadata(b.indexDir)
+	if err != nil {
+		return false, err
+	}
+	if metadata.Version < trigramIndexerLatestVersion {
+		log.Warn("Found older trigram index with version %d, Gitea will remove it and rebuild", metadata.Version)
+		return false, util.RemoveAll(b.indexDir)
+	}
+
+	entries, err := os.ReadDir(b.indexDir)
+	if err != nil {
+		return false, err
+	}
+	shards := make(map[int64][]*shard)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		repoID, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		repoShards, err := loadRepoShards(filepath.Join(b.indexDir, entry.Name()))
+		if err != nil {
+			return false, err
+		}
+		if len(repoShards) > 0 {
+			shards[repoID] = repoShards
+		}
+	}
+	b.shards = shards
+	return true, nil
+}
+
+func loadRepoShards(dir string) ([]*shard, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var shards []*shard
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "tmp-") {
+			// left by an interrupted update
+			if err := util.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		seq, err := strconv.Atoi(strings.TrimSuffix(name, ".shard"))
+		if err != nil || !strings.HasSuffix(name, ".shard") {
+			continue
+		}
+		s, err := readShard(filepath.Join(dir, name), seq)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, s)
+	}
+	return shards, nil
+}
+
+// Ping checks if the indexer is available
+func (b *Indexer) Ping(_ context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.shards == nil {
+		return errNotInitialized
+	}
+	return nil
+}
+
+// Close closes the indexer
+func (b *Indexer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.shards = nil
+}
+
+func (b *Indexer) repoDir(repoID int64) string {
+	return filepath.Join(b.indexDir, strconv.FormatInt(repoID, 10))
+}
+
+// shardWriter packs the documents in new shards
+type shardWriter struct {
+	dir     string
+	repoID  int64
+	ref     string
+	seq     int
+	pending []*pendingDocument
+	size    int64
+	written []*shard
+}
+
+func (w *shardWriter) add(doc *pendingDocument) error {
+	w.pending = append(w.pending, doc)
+	w.size += int64(len(doc.Content))
+	if w.size >= maxShardSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *shardWriter) flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	s, err := writeShard(w.dir, w.repoID, w.ref, w.seq, w.pending)
+	if err != nil {
+		return err
+	}
+	w.seq++
+	w.written = append(w.written, s)
+	w.pending, w.size = nil, 0
+	return nil
+}
+
+// discard removes the shards written so far
+func (w *shardWriter) discard() {
+	for _, s := range w.written {
+		if err := util.Remove(s.path); err != nil {
+			log.Error("Unable to remove trigram shard %s: %v", s.path, err)
+		}
+	}
+}
+
+func (s *shard) contentSize() int64 {
+	var size int64
+	for _, doc := range s.Documents {
+		size += doc.Size
+	}
+	return size
+}
+
+func (b *Indexer) readUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, commitSha string,
+	update internal.FileUpdate, repo *repo_model.Repository,
+) (*pendingDocument, error) {
+	// Ignore vendored files in code search
+	if setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
+		return nil, nil
+	}
+
+	size := update.Size
+
+	var err error
+	if !update.Sized {
+		var stdout string
+		stdout, _, err = git.NewCommand(ctx, "cat-file", "-s").AddDynamicArguments(update.BlobSha).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+		if err != nil {
+			return nil, err
+		}
+		if size, err = strconv.ParseInt(strings.TrimSpace(stdout), 10, 64); err != nil {
+			return nil, fmt.Errorf("misformatted git cat-file output: %w", err)
+		}
+	}
+
+	if size > setting.Indexer.MaxIndexerFileSize {
+		return nil, nil
+	}
+
+	if _, err := batchWriter.Write([]byte(update.BlobSha + "\n")); err != nil {
+		return nil, err
+	}
+
+	_, _, size, err = git.ReadBatchLine(batchReader)
+	if err != nil {
+		return nil, err
+	}
+
+	fileContents, err := io.ReadAll(io.LimitReader(batchReader, size))
+	if err != nil {
+		return nil, err
+	} else if !typesniffer.DetectContentType(fileContents).IsText() {
+		// FIXME: UTF-16 files will probably fail here
+		return nil, nil
+	}
+
+	if _, err = batchReader.Discard(1); err != nil {
+		return nil, err
+	}
+
+	content := charset.ToUTF8DropErrors(fileContents)
+	language := analyze.GetCodeLanguage(update.Filename, fileContents)
+	return &pendingDocument{
+		document: document{
+			Filename:    update.Filename,
+			CommitID:    commitSha,
+			Language:    language,
+			Symbols:     symbols.Names(symbols.Extract(language, string(content))),
+			UpdatedUnix: timeutil.TimeStampNow(),
+		},
+		Content: content,
+	}, nil
+}
+
+// Index indexes the data
+func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, ref, sha string, changes *internal.RepoChanges) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.RLock()
+	if b.shards == nil {
+		b.mu.RUnlock()
+		return errNotInitialized
+	}
+	repoShards := b.shards[repo.ID]
+	b.mu.RUnlock()
+
+	// the shards of the other refs are kept as they are
+	var oldShards, kept, obsolete []*shard
+	for _, s := range repoShards {
+		if s.Ref == ref {
+			oldShards = append(oldShards, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+
+	changed := make(container.Set[string], len(changes.Updates)+len(changes.RemovedFilenames))
+	for _, update := range changes.Updates {
+		changed.Add(update.Filename)
+	}
+	changed.AddMultiple(changes.RemovedFilenames...)
+
+	w := &shardWriter{dir: b.repoDir(repo.ID), repoID: repo.ID, ref: ref, seq: 1}
+	for _, s := range repoShards {
+		w.seq = max(w.seq, s.seq+1)
+	}
+
+	// the shards containing changed files are rewritten, and the small ones are merged together
+	smallShards := 0
+	for _, s := range oldShards {
+		if s.contentSize() < maxShardSize/2 {
+			smallShards++
+		}
+	}
+	for _, s := range oldShards {
+		rewrite := smallShards > 1 && s.contentSize() < maxShardSize/2
+		for _, doc := range s.Documents {
+			if rewrite {
+				break
+			}
+			rewrite = changed.Contains(doc.Filename)
+		}
+		if rewrite {
+			obsolete = append(obsolete, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+
+	err := func() error {
+		for _, s := range obsolete {
+			if err := b.carryOver(s, changed, w); err != nil {
+				return err
+			}
+		}
+
+		if len(changes.Updates) > 0 {
+			// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
+			if err := git.EnsureValidGitRepository(ctx, repo.RepoPath()); err != nil {
+				log.Error("Unable to open git repo: %s for %-v: %v", repo.RepoPath(), repo, err)
+				return err
+			}
+
+			batchWriter, batchReader, cancel := git.CatFileBatch(ctx, repo.RepoPath())
+			defer cancel()
+
+			for _, update := range changes.Updates {
+				doc, err := b.readUpdate(ctx, batchWriter, batchReader, sha, update, repo)
+				if err != nil {
+					return err
+				}
+				if doc != nil {
+					if err := w.add(doc); err != nil {
+						return err
+					}
+				}
+			}
+			cancel()
+		}
+		return w.flush()
+	}()
+	if err != nil {
+		w.discard()
+		return err
+	}
+
+	b.mu.Lock()
+	if b.shards == nil {
+		// the indexer has been closed meanwhile
+		b.mu.Unlock()
+		w.discard()
+		return errNotInitialized
+	}
+	if newShards := append(kept, w.written...); len(newShards) > 0 {
+		b.shards[repo.ID] = newShards
+	} else {
+		delete(b.shards, repo.ID)
+	}
+	b.mu.Unlock()
+
+	for _, s := range obsolete {
+		if err := util.Remove(s.path); err != nil {
+			log.Error("Unable to remove trigram shard %s: %v", s.path, err)
+		}
+	}
+	return nil
+}
+
+// carryOver adds the unchanged documents of a shard to the writer
+func (b *Indexer) carryOver(s *shard, changed container.Set[string], w *shardWriter) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, doc := range s.Documents {
+		if changed.Contains(doc.Filename) {
+			continue
+		}
+		content, err := readContent(f, doc)
+		if err != nil {
+			return err
+		}
+		if err := w.add(&pendingDocument{document: *doc, Content: content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes indexes by ids
+func (b *Indexer) Delete(_ context.Context, repoID int64) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.shards == nil {
+		return errNotInitialized
+	}
+	delete(b.shards, repoID)
+	return util.RemoveAll(b.repoDir(repoID))
+}
+
+// DeleteRef deletes the files of a branch or tag
+func (b *Indexer) DeleteRef(_ context.Context, repoID int64, ref string) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.Lock()
+	if b.shards == nil {
+		b.mu.Unlock()
+		return errNotInitialized
+	}
+	var kept, obsolete []*shard
+	for _, s := range b.shards[repoID] {
+		if s.Ref == ref {
+			obsolete = append(obsolete, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) > 0 {
+		b.shards[repoID] = kept
+	} else {
+		delete(b.shards, repoID)
+	}
+	b.mu.Unlock()
+
+	for _, s := range obsolete {
+		if err := util.Remove(s.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search searches for files in the specified repo.
+// Returns the matching file-paths
+func (b *Indexer) Search(ctx context.Context, opts *internal.SearchOptions) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	switch {
+	case opts.Keyword == "":
+		return b.search(ctx, opts, nil, nil, nil)
+	case opts.IsSymbol:
+		return b.search(ctx, opts, nil, symbolMatcher(opts.Keyword), nil)
+	case opts.IsKeywordFuzzy:
+		words := strings.Fields(opts.Keyword)
+		return b.search(ctx, opts, keywordTrigrams(words...), nil, wordsMatcher(words))
+	default:
+		return b.search(ctx, opts, keywordTrigrams(opts.Keyword), nil, substringMatcher(opts.Keyword))
+	}
+}
+
+// SearchRegexp searches for files whose content matches the regular expression
+func (b *Indexer) SearchRegexp(ctx context.Context, opts *internal.SearchOptions, re *regexp.Regexp) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return 0, nil, nil, util.NewInvalidArgumentErrorf("invalid regular expression: %v", err)
+	}
+	return b.search(ctx, opts, regexpTrigrams(parsed), nil, regexpMatcher(re))
+}
+
+type hit struct {
+	repoID     int64
+	shard      *shard
+	doc        *document
+	startIndex int
+	endIndex   int
+}
+
+func (b *Indexer) search(ctx context.Context, opts *internal.SearchOptions, trigrams []trigram, filter func(*document) bool, match matcher) (int64, []*internal.SearchResult, []*internal.SearchResultLanguages, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.shards == nil {
+		return 0, nil, nil, errNotInitialized
+	}
+
+	repoIDs := opts.RepoIDs
+	if len(repoIDs) == 0 {
+		repoIDs = make([]int64, 0, len(b.shards))
+		for repoID := range b.shards {
+			repoIDs = append(repoIDs, repoID)
+		}
+	}
+	pathRegexp := pathMatcher(opts.Paths)
+
+	var hits []*hit
+	languageCounts := make(map[string]int)
+	for _, repoID := range repoIDs {
+		for _, s := range b.shards[repoID] {
+			if s.Ref != opts.Ref {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return 0, nil, nil, err
+			}
+			shardHits, err := searchShard(s, trigrams, pathRegexp, filter, match)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			for _, h := range shardHits {
+				h.repoID = repoID
+				if h.doc.Language != "" {
+					languageCounts[h.doc.Language]++
+				}
+				if opts.Language == "" || h.doc.Language == opts.Language {
+					hits = append(hits, h)
+				}
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].repoID != hits[j].repoID {
+			return hits[i].repoID < hits[j].repoID
+		}
+		return hits[i].doc.Filename < hits[j].doc.Filename
+	})
+	total := int64(len(hits))
+	hits = util.PaginateSlice(hits, opts.Page, opts.PageSize).([]*hit)
+
+	results := make([]*internal.SearchResult, 0, len(hits))
+	for _, h := range hits {
+		content, err := readShardContent(h.shard, h.doc)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		results = append(results, &internal.SearchResult{
+			RepoID:      h.repoID,
+			StartIndex:  h.startIndex,
+			EndIndex:    h.endIndex,
+			Filename:    h.doc.Filename,
+			Content:     string(content),
+			CommitID:    h.doc.CommitID,
+			UpdatedUnix: h.doc.UpdatedUnix,
+			Language:    h.doc.Language,
+			Color:       enry.GetColor(h.doc.Language),
+		})
+	}
+	return total, results, internal.TopLanguages(languageCounts), nil
+}
+
+// searchShard returns the documents of the shard matching the conditions
+func searchShard(s *shard, trigrams []trigram, pathRegexp *regexp.Regexp, filter func(*document) bool, match matcher) ([]*hit, error) {
+	candidates := s.candidates(trigrams)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	var hits []*hit
+	for _, idx := range candidates {
+		doc := s.Documents[idx]
+		if pathRegexp != nil && !pathRegexp.MatchString(doc.Filename) {
+			continue
+		}
+		if filter != nil && !filter(doc) {
+			continue
+		}
+		h := &hit{shard: s, doc: doc, startIndex: -1, endIndex: -1}
+		if match != nil {
+			if f == nil {
+				var err error
+				if f, err = os.Open(s.path); err != nil {
+					return nil, err
+				}
+			}
+			content, err := readContent(f, doc)
+			if err != nil {
+				return nil, err
+			}
+			loc := match(content)
+			if loc == nil {
+				continue
+			}
+			h.startIndex, h.endIndex = loc[0], loc[1]
+		}
+		hits = append(hits, h)
+	}
+	return hits, nil
+}
+
+func readShardContent(s *shard, doc *document) ([]byte, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readContent(f, doc)
+}
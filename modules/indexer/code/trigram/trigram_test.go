@@ -0,0 +1,131 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package trigram
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pending(filename, language, content string, symbols ...string) *pendingDocument {
+	return &pendingDocument{
+		document: document{Filename: filename, Language: language, CommitID: "abc", Symbols: symbols},
+		Content:  []byte(content),
+	}
+}
+
+func TestShard(t *testing.T) {
+	dir := t.TempDir()
+	written, err := writeShard(dir, 1, "", 3, []*pendingDocument{
+		pending("main.go", "Go", "package main\n\nfunc NewServer() {}\n", "newserver"),
+		pending("README.md", "Markdown", "# Server\n"),
+	})
+	require.NoError(t, err)
+
+	s, err := readShard(written.path, 3)
+	require.NoError(t, err)
+	assert.Equal(t, written.shardIndex, s.shardIndex)
+	assert.Equal(t, int64(1), s.RepoID)
+	// the documents are sorted by file name
+	assert.Equal(t, "README.md", s.Documents[0].Filename)
+	assert.Equal(t, "main.go", s.Documents[1].Filename)
+
+	assert.Equal(t, []uint32{0, 1}, s.candidates(keywordTrigrams("SERVER")))
+	assert.Equal(t, []uint32{1}, s.candidates(keywordTrigrams("func", "server")))
+	assert.Empty(t, s.candidates(keywordTrigrams("client")))
+	assert.Equal(t, []uint32{0, 1}, s.candidates(nil))
+
+	content, err := readShardContent(s, s.Documents[1])
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc NewServer() {}\n", string(content))
+}
+
+func TestMatchers(t *testing.T) {
+	content := []byte("func NewServer(addr string) *Server {")
+
+	assert.Equal(t, []int{5, 14}, substringMatcher("newserver")(content))
+	assert.Nil(t, substringMatcher("new server")(content))
+	assert.Equal(t, []int{15, 19}, wordsMatcher([]string{"ADDR", "func"})(content))
+	assert.Nil(t, wordsMatcher([]string{"addr", "client"})(content))
+	assert.Equal(t, []int{5, 14}, regexpMatcher(regexp.MustCompile(`New\w+`))(content))
+
+	paths := pathMatcher([]string{"cmd/", "*.md"})
+	assert.True(t, paths.MatchString("cmd/web.go"))
+	assert.True(t, paths.MatchString("docs/README.md"))
+	assert.False(t, paths.MatchString("main.go"))
+	assert.False(t, paths.MatchString("README.mdx"))
+}
+
+func TestIndexerSearch(t *testing.T) {
+	indexer := NewIndexer(t.TempDir())
+	existed, err := indexer.Init(context.Background())
+	require.NoError(t, err)
+	assert.False(t, existed)
+
+	// documents are added directly, reading them from a git repository is tested with the other indexers
+	w := &shardWriter{dir: indexer.repoDir(1), repoID: 1, seq: 1}
+	require.NoError(t, w.add(pending("server.go", "Go", "package web\n\nfunc NewServer() {}\n", "newserver")))
+	require.NoError(t, w.add(pending("docs/server.md", "Markdown", "Run the server with `gitea web`\n")))
+	require.NoError(t, w.flush())
+	tagWriter := &shardWriter{dir: indexer.repoDir(1), repoID: 1, ref: "refs/tags/v1.0", seq: 2}
+	require.NoError(t, tagWriter.add(pending("server.go", "Go", "package web\n\nfunc NewServerV1() {}\n", "newserverv1")))
+	require.NoError(t, tagWriter.flush())
+	indexer.shards[1] = append(w.written, tagWriter.written...)
+
+	search := func(opts *internal.SearchOptions) []string {
+		opts.Page, opts.PageSize = 1, 10
+		_, results, _, err := indexer.Search(context.Background(), opts)
+		require.NoError(t, err)
+		var filenames []string
+		for _, result := range results {
+			filenames = append(filenames, result.Filename)
+		}
+		return filenames
+	}
+
+	assert.Equal(t, []string{"docs/server.md", "server.go"}, search(&internal.SearchOptions{Keyword: "server"}))
+	assert.Equal(t, []string{"server.go"}, search(&internal.SearchOptions{Keyword: "server", Language: "Go"}))
+	assert.Equal(t, []string{"docs/server.md"}, search(&internal.SearchOptions{Keyword: "server", Paths: []string{"docs/"}}))
+	assert.Equal(t, []string{"docs/server.md"}, search(&internal.SearchOptions{Keyword: "the server"}))
+	assert.Equal(t, []string{"docs/server.md"}, search(&internal.SearchOptions{Keyword: "server run", IsKeywordFuzzy: true}))
+	assert.Equal(t, []string{"server.go"}, search(&internal.SearchOptions{Keyword: "NewServ", IsSymbol: true}))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "server", RepoIDs: []int64{2}}))
+	assert.Equal(t, []string{"server.go"}, search(&internal.SearchOptions{Keyword: "server", Ref: "refs/tags/v1.0"}))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "serverv1"}))
+
+	total, results, languages, err := indexer.SearchRegexp(context.Background(), &internal.SearchOptions{Page: 1, PageSize: 10}, regexp.MustCompile(`func \w+Server\(`))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, "server.go", results[0].Filename)
+	assert.Equal(t, "func NewServer(", results[0].Content[results[0].StartIndex:results[0].EndIndex])
+	assert.Len(t, languages, 1)
+
+	// the index is loaded again from the shard files
+	indexer.Close()
+	existed, err = indexer.Init(context.Background())
+	require.NoError(t, err)
+	assert.True(t, existed)
+	assert.Equal(t, []string{"docs/server.md", "server.go"}, search(&internal.SearchOptions{Keyword: "server"}))
+
+	require.NoError(t, indexer.DeleteRef(context.Background(), 1, "refs/tags/v1.0"))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "server", Ref: "refs/tags/v1.0"}))
+	assert.Equal(t, []string{"docs/server.md", "server.go"}, search(&internal.SearchOptions{Keyword: "server"}))
+
+	require.NoError(t, indexer.Delete(context.Background(), 1))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "server"}))
+
+	// a closed indexer returns errors instead of panicking
+	indexer.Close()
+	assert.ErrorIs(t, indexer.Ping(context.Background()), errNotInitialized)
+	assert.ErrorIs(t, indexer.Index(context.Background(), &repo_model.Repository{ID: 1}, "", "abc", &internal.RepoChanges{}), errNotInitialized)
+	assert.ErrorIs(t, indexer.DeleteRef(context.Background(), 1, "refs/tags/v1.0"), errNotInitialized)
+	assert.ErrorIs(t, indexer.Delete(context.Background(), 1), errNotInitialized)
+}
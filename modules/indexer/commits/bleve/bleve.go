@@ -0,0 +1,213 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package bleve
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
+	inner_bleve "code.gitea.io/gitea/modules/indexer/internal/bleve"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	analyzer_keyword "github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/token/camelcase"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+const (
+	commitIndexerAnalyzer      = "commitIndexer"
+	commitIndexerDocType       = "commitIndexerDocType"
+	commitIndexerLatestVersion = 1
+)
+
+const unicodeNormalizeName = "unicodeNormalize"
+
+func addUnicodeNormalizeTokenFilter(m *mapping.IndexMappingImpl) error {
+	return m.AddCustomTokenFilter(unicodeNormalizeName, map[string]any{
+		"type": unicodenorm.Name,
+		"form": unicodenorm.NFC,
+	})
+}
+
+const maxBatchSize = 16
+
+// IndexerData an update to the commit indexer
+type IndexerData internal.IndexerData
+
+// Type returns the document type, for bleve's mapping.Classifier interface.
+func (i *IndexerData) Type() string {
+	return commitIndexerDocType
+}
+
+// generateCommitIndexMapping generates the bleve index mapping for commits
+func generateCommitIndexMapping() (mapping.IndexMapping, error) {
+	mapping := bleve.NewIndexMapping()
+	docMapping := bleve.NewDocumentMapping()
+
+	numericFieldMapping := bleve.NewNumericFieldMapping()
+	numericFieldMapping.Store = false
+	numericFieldMapping.IncludeInAll = false
+	docMapping.AddFieldMappingsAt("repo_id", numericFieldMapping)
+	docMapping.AddFieldMappingsAt("committed_unix", numericFieldMapping)
+
+	textFieldMapping := bleve.NewTextFieldMapping()
+	textFieldMapping.Store = false
+	textFieldMapping.IncludeInAll = false
+	docMapping.AddFieldMappingsAt("message", textFieldMapping)
+	docMapping.AddFieldMappingsAt("author", textFieldMapping)
+
+	keywordFieldMapping := bleve.NewTextFieldMapping()
+	keywordFieldMapping.Store = false
+	keywordFieldMapping.IncludeInAll = false
+	keywordFieldMapping.Analyzer = analyzer_keyword.Name
+	docMapping.AddFieldMappingsAt("commit_id", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("paths", keywordFieldMapping)
+
+	if err := addUnicodeNormalizeTokenFilter(mapping); err != nil {
+		return nil, err
+	} else if err = mapping.AddCustomAnalyzer(commitIndexerAnalyzer, map[string]any{
+		"type":          custom.Name,
+		"char_filters":  []string{},
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{unicodeNormalizeName, camelcase.Name, lowercase.Name},
+	}); err != nil {
+		return nil, err
+	}
+
+	mapping.DefaultAnalyzer = commitIndexerAnalyzer
+	mapping.AddDocumentMapping(commitIndexerDocType, docMapping)
+	mapping.AddDocumentMapping("_all", bleve.NewDocumentDisabledMapping())
+	mapping.DefaultMapping = bleve.NewDocumentDisabledMapping() // disable default mapping, avoid indexing unexpected structs
+
+	return mapping, nil
+}
+
+var _ internal.Indexer = &Indexer{}
+
+// Indexer implements Indexer interface
+type Indexer struct {
+	inner                    *inner_bleve.Indexer
+	indexer_internal.Indexer // do not composite inner_bleve.Indexer directly to avoid exposing too much
+}
+
+// NewIndexer creates a new bleve local indexer
+func NewIndexer(indexDir string) *Indexer {
+	inner := inner_bleve.NewIndexer(indexDir, commitIndexerLatestVersion, generateCommitIndexMapping)
+	return &Indexer{
+		Indexer: inner,
+		inner:   inner,
+	}
+}
+
+// Index will save the index data
+func (b *Indexer) Index(_ context.Context, commits ...*internal.IndexerData) error {
+	batch := inner_bleve.NewFlushingBatch(b.inner.Indexer, maxBatchSize)
+	for _, commit := range commits {
+		if err := batch.Index(commit.DocumentID(), (*IndexerData)(commit)); err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// Delete deletes the commits of a repository
+func (b *Indexer) Delete(_ context.Context, repoID int64) error {
+	searchRequest := bleve.NewSearchRequestOptions(inner_bleve.NumericEqualityQuery(repoID, "repo_id"), 2147483647, 0, false)
+	result, err := b.inner.Indexer.Search(searchRequest)
+	if err != nil {
+		return err
+	}
+	batch := inner_bleve.NewFlushingBatch(b.inner.Indexer, maxBatchSize)
+	for _, hit := range result.Hits {
+		if err = batch.Delete(hit.ID); err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// Search searches for commits by given conditions.
+// Returns the matching commits, the most recent first
+func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (*internal.SearchResult, error) {
+	var queries []query.Query
+
+	if options.Keyword != "" {
+		keywordQueries := []query.Query{
+			inner_bleve.MatchPhraseQuery(options.Keyword, "message", commitIndexerAnalyzer),
+		}
+		if internal.IsCommitIDPrefix(options.Keyword) {
+			commitIDQuery := bleve.NewPrefixQuery(options.Keyword)
+			commitIDQuery.FieldVal = "commit_id"
+			keywordQueries = append(keywordQueries, commitIDQuery)
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(keywordQueries...))
+	}
+
+	if len(options.RepoIDs) > 0 {
+		repoQueries := make([]query.Query, 0, len(options.RepoIDs))
+		for _, repoID := range options.RepoIDs {
+			repoQueries = append(repoQueries, inner_bleve.NumericEqualityQuery(repoID, "repo_id"))
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(repoQueries...))
+	}
+
+	if len(options.Authors) > 0 {
+		authorQueries := make([]query.Query, 0, len(options.Authors))
+		for _, author := range options.Authors {
+			authorQueries = append(authorQueries, inner_bleve.MatchPhraseQuery(author, "author", commitIndexerAnalyzer))
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(authorQueries...))
+	}
+
+	if len(options.Paths) > 0 {
+		pathQueries := make([]query.Query, 0, len(options.Paths))
+		for _, path := range options.Paths {
+			pathQuery := bleve.NewWildcardQuery(indexer_internal.PathWildcard(path))
+			pathQuery.FieldVal = "paths"
+			pathQueries = append(pathQueries, pathQuery)
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(pathQueries...))
+	}
+
+	if options.CommittedAfterUnix != nil || options.CommittedBeforeUnix != nil {
+		queries = append(queries, inner_bleve.NumericRangeInclusiveQuery(options.CommittedAfterUnix, options.CommittedBeforeUnix, "committed_unix"))
+	}
+
+	var indexerQuery query.Query = bleve.NewConjunctionQuery(queries...)
+	if len(queries) == 0 {
+		indexerQuery = bleve.NewMatchAllQuery()
+	}
+
+	skip, limit := indexer_internal.ParsePaginator(options.Paginator)
+	search := bleve.NewSearchRequestOptions(indexerQuery, limit, skip, false)
+	search.SortBy([]string{"-committed_unix", "-_id"})
+
+	result, err := b.inner.Indexer.SearchInContext(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &internal.SearchResult{
+		Total: int64(result.Total),
+		Hits:  make([]internal.Match, 0, len(result.Hits)),
+	}
+	for _, hit := range result.Hits {
+		repoID, commitID, err := internal.ParseDocumentID(hit.ID)
+		if err != nil {
+			return nil, err
+		}
+		ret.Hits = append(ret.Hits, internal.Match{
+			RepoID:   repoID,
+			CommitID: commitID,
+			Score:    hit.Score,
+		})
+	}
+	return ret, nil
+}
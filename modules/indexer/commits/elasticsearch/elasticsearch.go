@@ -0,0 +1,179 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package elasticsearch
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
+	inner_elasticsearch "code.gitea.io/gitea/modules/indexer/internal/elasticsearch"
+
+	"github.com/olivere/elastic/v7"
+)
+
+const (
+	commitIndexerLatestVersion = 1
+)
+
+var _ internal.Indexer = &Indexer{}
+
+// Indexer implements Indexer interface
+type Indexer struct {
+	inner                    *inner_elasticsearch.Indexer
+	indexer_internal.Indexer // do not composite inner_elasticsearch.Indexer directly to avoid exposing too much
+}
+
+// NewIndexer creates a new elasticsearch indexer
+func NewIndexer(url, indexerName string) *Indexer {
+	inner := inner_elasticsearch.NewIndexer(url, indexerName, commitIndexerLatestVersion, defaultMapping)
+	indexer := &Indexer{
+		inner:   inner,
+		Indexer: inner,
+	}
+	return indexer
+}
+
+const (
+	defaultMapping = `
+{
+	"mappings": {
+		"properties": {
+			"repo_id": { "type": "long", "index": true },
+			"commit_id": { "type": "keyword", "index": true },
+
+			"message": { "type": "text", "index": true },
+
+			"author": { "type": "text", "index": true },
+			"paths": { "type": "keyword", "index": true },
+			"committed_unix": { "type": "long", "index": true }
+		}
+	}
+}
+`
+)
+
+// Index will save the index data
+func (b *Indexer) Index(ctx context.Context, commits ...*internal.IndexerData) error {
+	if len(commits) == 0 {
+		return nil
+	} else if len(commits) == 1 {
+		commit := commits[0]
+		_, err := b.inner.Client.Index().
+			Index(b.inner.VersionedIndexName()).
+			Id(commit.DocumentID()).
+			BodyJson(commit).
+			Do(ctx)
+		return err
+	}
+
+	reqs := make([]elastic.BulkableRequest, 0, len(commits))
+	for _, commit := range commits {
+		reqs = append(reqs,
+			elastic.NewBulkIndexRequest().
+				Index(b.inner.VersionedIndexName()).
+				Id(commit.DocumentID()).
+				Doc(commit),
+		)
+	}
+
+	_, err := b.inner.Client.Bulk().
+		Index(b.inner.VersionedIndexName()).
+		Add(reqs...).
+		Do(graceful.GetManager().HammerContext())
+	return err
+}
+
+// Delete deletes the commits of a repository
+func (b *Indexer) Delete(ctx context.Context, repoID int64) error {
+	_, err := b.inner.Client.DeleteByQuery(b.inner.VersionedIndexName()).
+		Query(elastic.NewTermQuery("repo_id", repoID)).
+		Do(ctx)
+	return err
+}
+
+// Search searches for commits by given conditions.
+// Returns the matching commits, the most recent first
+func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (*internal.SearchResult, error) {
+	query := elastic.NewBoolQuery()
+
+	if options.Keyword != "" {
+		q := elastic.NewBoolQuery().Should(elastic.NewMatchPhraseQuery("message", options.Keyword))
+		if internal.IsCommitIDPrefix(options.Keyword) {
+			q.Should(elastic.NewPrefixQuery("commit_id", options.Keyword))
+		}
+		query.Must(q.MinimumNumberShouldMatch(1))
+	}
+
+	if len(options.RepoIDs) > 0 {
+		query.Must(elastic.NewTermsQuery("repo_id", toAnySlice(options.RepoIDs)...))
+	}
+
+	if len(options.Authors) > 0 {
+		q := elastic.NewBoolQuery()
+		for _, author := range options.Authors {
+			q.Should(elastic.NewMatchPhraseQuery("author", author))
+		}
+		query.Must(q.MinimumNumberShouldMatch(1))
+	}
+
+	if len(options.Paths) > 0 {
+		q := elastic.NewBoolQuery()
+		for _, path := range options.Paths {
+			q.Should(elastic.NewWildcardQuery("paths", indexer_internal.PathWildcard(path)))
+		}
+		query.Must(q.MinimumNumberShouldMatch(1))
+	}
+
+	if options.CommittedAfterUnix != nil || options.CommittedBeforeUnix != nil {
+		q := elastic.NewRangeQuery("committed_unix")
+		if options.CommittedAfterUnix != nil {
+			q.Gte(*options.CommittedAfterUnix)
+		}
+		if options.CommittedBeforeUnix != nil {
+			q.Lte(*options.CommittedBeforeUnix)
+		}
+		query.Must(q)
+	}
+
+	// See https://stackoverflow.com/questions/35206409/elasticsearch-2-1-result-window-is-too-large-index-max-result-window/35221900
+	const maxPageSize = 10000
+
+	skip, limit := indexer_internal.ParsePaginator(options.Paginator, maxPageSize)
+	searchResult, err := b.inner.Client.Search().
+		Index(b.inner.VersionedIndexName()).
+		Query(query).
+		SortBy(elastic.NewFieldSort("committed_unix").Desc(), elastic.NewFieldSort("commit_id").Desc()).
+		From(skip).Size(limit).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]internal.Match, 0, len(searchResult.Hits.Hits))
+	for _, hit := range searchResult.Hits.Hits {
+		repoID, commitID, err := internal.ParseDocumentID(hit.Id)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, internal.Match{
+			RepoID:   repoID,
+			CommitID: commitID,
+		})
+	}
+
+	return &internal.SearchResult{
+		Total: searchResult.TotalHits(),
+		Hits:  hits,
+	}, nil
+}
+
+func toAnySlice[T any](s []T) []any {
+	ret := make([]any, 0, len(s))
+	for _, item := range s {
+		ret = append(ret, item)
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/djherbis/buffer"
+	"github.com/djherbis/nio/v3"
+)
+
+const (
+	// maxIndexedPaths is the maximum number of files touched by a commit which are indexed, huge commits
+	// like the imports of whole projects would otherwise make big documents for little use
+	maxIndexedPaths = 1000
+	// maxBatchSize is the number of commits sent to the indexer at once
+	maxBatchSize = 100
+)
+
+// the fields of a commit are separated by NULs and the commits by the record separator,
+// the names of the touched files follow the fields of each commit
+const logFormat = "--format=%x1e%H%x00%an <%ae>%x00%ct%x00%B%x00"
+
+func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository) (string, error) {
+	stdout, _, err := git.NewCommand(ctx, "show-ref", "-s").AddDynamicArguments(git.BranchPrefix + repo.DefaultBranch).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// isAncestor returns true if the indexed commit is still in the history of the default branch
+func isAncestor(ctx context.Context, repo *repo_model.Repository, indexedSha, sha string) bool {
+	_, _, err := git.NewCommand(ctx, "merge-base", "--is-ancestor").AddDynamicArguments(indexedSha, sha).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+	return err == nil
+}
+
+// walkCommits calls fn with the commits reachable from sha but not from indexedSha, by batches
+func walkCommits(ctx context.Context, repo *repo_model.Repository, indexedSha, sha string, fn func([]*internal.IndexerData) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stdoutReader, stdoutWriter := nio.Pipe(buffer.New(32 * 1024))
+	defer stdoutReader.Close()
+
+	cmd := git.NewCommand(ctx, "log", "--name-only", "--no-renames", "-z", logFormat)
+	if indexedSha != "" {
+		cmd.AddDynamicArguments(indexedSha + ".." + sha)
+	} else {
+		cmd.AddDynamicArguments(sha)
+	}
+
+	go func() {
+		stderr := strings.Builder{}
+		err := cmd.Run(&git.RunOpts{
+			Dir:    repo.RepoPath(),
+			Stdout: stdoutWriter,
+			Stderr: &stderr,
+		})
+		if err != nil {
+			_ = stdoutWriter.CloseWithError(git.ConcatenateError(err, stderr.String()))
+			return
+		}
+		_ = stdoutWriter.Close()
+	}()
+
+	batch := make([]*internal.IndexerData, 0, maxBatchSize)
+	rd := bufio.NewReader(stdoutReader)
+	for {
+		record, err := rd.ReadString('\x1e')
+		if record = strings.TrimSuffix(record, "\x1e"); record != "" {
+			if commit := parseLogRecord(repo.ID, record); commit != nil {
+				batch = append(batch, commit)
+			}
+			if len(batch) == maxBatchSize {
+				if err := fn(batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// parseLogRecord parses the output of git log for a commit, it returns nil if the record is malformed
+func parseLogRecord(repoID int64, record string) *internal.IndexerData {
+	fields := strings.Split(record, "\x00")
+	if len(fields) < 4 {
+		return nil
+	}
+	committed, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+	commit := &internal.IndexerData{
+		RepoID:        repoID,
+		CommitID:      fields[0],
+		Author:        fields[1],
+		Message:       strings.TrimSpace(fields[3]),
+		CommittedUnix: timeutil.TimeStamp(committed),
+	}
+	for _, name := range fields[4:] {
+		// the list of names is preceded by an empty line
+		if name = strings.TrimPrefix(name, "\n"); name == "" {
+			continue
+		}
+		if len(commit.Paths) == maxIndexedPaths {
+			break
+		}
+		commit.Paths = append(commit.Paths, name)
+	}
+	return commit
+}
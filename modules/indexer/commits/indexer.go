@@ -0,0 +1,284 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"context"
+	"os"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/indexer/commits/bleve"
+	"code.gitea.io/gitea/modules/indexer/commits/elasticsearch"
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	// indexerQueue queue of the ids of the repositories to index
+	indexerQueue *queue.WorkerPoolQueue[int64]
+	// globalIndexer is the global indexer, it cannot be nil.
+	// When the real indexer is not ready, it will be a dummy indexer which will return error to explain it's not ready.
+	// So it's always safe use it as *globalIndexer.Load() and call its methods.
+	globalIndexer atomic.Pointer[internal.Indexer]
+	dummyIndexer  *internal.Indexer
+)
+
+func init() {
+	i := internal.NewDummyIndexer()
+	dummyIndexer = &i
+	globalIndexer.Store(dummyIndexer)
+}
+
+// index indexes the commits of the default branch of a repository which aren't indexed yet
+func index(ctx context.Context, indexer internal.Indexer, repoID int64) error {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if repo_model.IsErrRepoNotExist(err) {
+		return indexer.Delete(ctx, repoID)
+	}
+	if err != nil {
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	sha, err := getDefaultBranchSha(ctx, repo)
+	if err != nil {
+		return err
+	}
+	status, err := repo_model.GetIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCommits)
+	if err != nil {
+		return err
+	}
+	indexedSha := status.CommitSha
+	if indexedSha == sha {
+		return nil
+	}
+	// the history was rewritten or the default branch changed, index the whole history again
+	if indexedSha != "" && !isAncestor(ctx, repo, indexedSha, sha) {
+		if err := indexer.Delete(ctx, repo.ID); err != nil {
+			return err
+		}
+		indexedSha = ""
+	}
+
+	if err := walkCommits(ctx, repo, indexedSha, sha, func(commits []*internal.IndexerData) error {
+		return indexer.Index(ctx, commits...)
+	}); err != nil {
+		return err
+	}
+
+	return repo_model.UpdateIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCommits, sha)
+}
+
+// Init initialize the commit indexer
+func Init() {
+	if !setting.Indexer.CommitIndexerEnabled {
+		(*globalIndexer.Load()).Close()
+		return
+	}
+
+	ctx, cancel, finished := process.GetManager().AddTypedContext(context.Background(), "Service: CommitIndexer", process.SystemProcessType, false)
+
+	graceful.GetManager().RunAtTerminate(func() {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		cancel()
+		log.Debug("Closing commit indexer")
+		(*globalIndexer.Load()).Close()
+		log.Info("PID: %d Commit Indexer closed", os.Getpid())
+		finished()
+	})
+
+	waitChannel := make(chan time.Duration, 1)
+
+	// Create the Queue
+	switch setting.Indexer.CommitType {
+	case "bleve", "elasticsearch":
+		handler := func(items ...int64) (unhandled []int64) {
+			indexer := *globalIndexer.Load()
+			for _, repoID := range items {
+				log.Trace("Commit indexer process repo: %d", repoID)
+				if err := index(ctx, indexer, repoID); err != nil {
+					unhandled = append(unhandled, repoID)
+					if !setting.IsInTesting {
+						log.Error("Commit indexer handler: index error for repo %v: %v", repoID, err)
+					}
+				}
+			}
+			return unhandled
+		}
+
+		indexerQueue = queue.CreateUniqueQueue(ctx, "commit_indexer", handler)
+		if indexerQueue == nil {
+			log.Fatal("Unable to create commit indexer queue")
+		}
+	default:
+		log.Fatal("Unknown commit indexer type; %s", setting.Indexer.CommitType)
+	}
+
+	go func() {
+		pprof.SetGoroutineLabels(ctx)
+		start := time.Now()
+		var (
+			cIndexer internal.Indexer
+			existed  bool
+			err      error
+		)
+		switch setting.Indexer.CommitType {
+		case "bleve":
+			log.Info("PID: %d Initializing Commit Indexer at: %s", os.Getpid(), setting.Indexer.CommitPath)
+			defer func() {
+				if err := recover(); err != nil {
+					log.Error("PANIC whilst initializing commit indexer: %v\nStacktrace: %s", err, log.Stack(2))
+					log.Error("The indexer files are likely corrupted and may need to be deleted")
+					log.Error("You can completely remove the \"%s\" directory to make Gitea recreate the indexes", setting.Indexer.CommitPath)
+				}
+			}()
+
+			cIndexer = bleve.NewIndexer(setting.Indexer.CommitPath)
+			existed, err = cIndexer.Init(ctx)
+			if err != nil {
+				cancel()
+				(*globalIndexer.Load()).Close()
+				close(waitChannel)
+				log.Fatal("PID: %d Unable to initialize the bleve Commit Indexer at path: %s Error: %v", os.Getpid(), setting.Indexer.CommitPath, err)
+			}
+		case "elasticsearch":
+			log.Info("PID: %d Initializing Commit Indexer at: %s", os.Getpid(), setting.Indexer.CommitConnStr)
+			cIndexer = elasticsearch.NewIndexer(setting.Indexer.CommitConnStr, setting.Indexer.CommitIndexerName)
+			existed, err = cIndexer.Init(ctx)
+			if err != nil {
+				cancel()
+				(*globalIndexer.Load()).Close()
+				close(waitChannel)
+				log.Fatal("PID: %d Unable to initialize the elasticsearch Commit Indexer connstr: %s Error: %v", os.Getpid(), setting.Indexer.CommitConnStr, err)
+			}
+		default:
+			log.Fatal("PID: %d Unknown Indexer type: %s", os.Getpid(), setting.Indexer.CommitType)
+		}
+
+		globalIndexer.Store(&cIndexer)
+
+		// Start processing the queue
+		go graceful.GetManager().RunWithCancel(indexerQueue)
+
+		if !existed { // populate the index because it's created for the first time
+			go graceful.GetManager().RunWithShutdownContext(populateCommitIndexer)
+		}
+		select {
+		case waitChannel <- time.Since(start):
+		case <-graceful.GetManager().IsShutdown():
+		}
+
+		close(waitChannel)
+	}()
+
+	if setting.Indexer.StartupTimeout > 0 {
+		go func() {
+			pprof.SetGoroutineLabels(ctx)
+			timeout := setting.Indexer.StartupTimeout
+			if graceful.GetManager().IsChild() && setting.GracefulHammerTime > 0 {
+				timeout += setting.GracefulHammerTime
+			}
+			select {
+			case <-graceful.GetManager().IsShutdown():
+				log.Warn("Shutdown before Commit Indexer completed initialization")
+				cancel()
+				(*globalIndexer.Load()).Close()
+			case duration, ok := <-waitChannel:
+				if !ok {
+					log.Warn("Commit Indexer Initialization failed")
+					cancel()
+					(*globalIndexer.Load()).Close()
+					return
+				}
+				log.Info("Commit Indexer Initialization took %v", duration)
+			case <-time.After(timeout):
+				cancel()
+				(*globalIndexer.Load()).Close()
+				log.Fatal("Commit Indexer Initialization Timed-Out after: %v", timeout)
+			}
+		}()
+	}
+}
+
+// UpdateRepoIndexer queues a repository to index its new commits, or to remove them if it has been deleted
+func UpdateRepoIndexer(repo *repo_model.Repository) {
+	if err := indexerQueue.Push(repo.ID); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Update commit index of repo %d failed: %v", repo.ID, err)
+	}
+}
+
+// IsAvailable checks if the commit indexer is available
+func IsAvailable(ctx context.Context) bool {
+	return (*globalIndexer.Load()).Ping(ctx) == nil
+}
+
+// populateCommitIndexer populate the commit indexer with pre-existing data. This
+// should only be run when the indexer is created for the first time.
+func populateCommitIndexer(ctx context.Context) {
+	log.Info("Populating the commit indexer with existing repositories")
+
+	exist, err := db.IsTableNotEmpty("repository")
+	if err != nil {
+		log.Fatal("System error: %v", err)
+	} else if !exist {
+		return
+	}
+
+	// the repositories indexed before are indexed again from scratch
+	if err := repo_model.DeleteIndexerStatuses(ctx, repo_model.RepoIndexerTypeCommits); err != nil {
+		log.Fatal("System error: %v", err)
+	}
+
+	var maxRepoID int64
+	if maxRepoID, err = db.GetMaxID("repository"); err != nil {
+		log.Fatal("System error: %v", err)
+	}
+
+	// start with the maximum existing repo ID and work backwards, so that we
+	// don't include repos that are created after gitea starts; such repos will
+	// already be added to the indexer, and we don't need to add them again.
+	for maxRepoID > 0 {
+		select {
+		case <-ctx.Done():
+			log.Info("Commit Indexer population shutdown before completion")
+			return
+		default:
+		}
+		ids, err := repo_model.GetUnindexedRepos(repo_model.RepoIndexerTypeCommits, maxRepoID, 0, 50)
+		if err != nil {
+			log.Error("populateCommitIndexer: %v", err)
+			return
+		} else if len(ids) == 0 {
+			break
+		}
+		for _, id := range ids {
+			select {
+			case <-ctx.Done():
+				log.Info("Commit Indexer population shutdown before completion")
+				return
+			default:
+			}
+			if err := indexerQueue.Push(id); err != nil && err != queue.ErrAlreadyInQueue {
+				log.Error("indexerQueue.Push: %v", err)
+				return
+			}
+			maxRepoID = id - 1
+		}
+	}
+	log.Info("Done (re)populating the commit indexer with existing repositories")
+}
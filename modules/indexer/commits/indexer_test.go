@@ -0,0 +1,87 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/indexer/commits/bleve"
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+
+	_ "code.gitea.io/gitea/models"
+	_ "code.gitea.io/gitea/models/actions"
+	_ "code.gitea.io/gitea/models/activities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	unittest.MainTest(m, &unittest.TestOptions{
+		GiteaRootPath: filepath.Join("..", "..", ".."),
+	})
+}
+
+func TestBleveIndexAndSearch(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	indexer := bleve.NewIndexer(t.TempDir())
+	defer indexer.Close()
+	_, err := indexer.Init(context.Background())
+	require.NoError(t, err)
+
+	// the repository "commits_search_test" has 5 commits, one a day from 2019-03-01 (UTC) to 2019-03-05
+	var repoID int64 = 36
+	require.NoError(t, index(git.DefaultContext, indexer, repoID))
+
+	search := func(opts *internal.SearchOptions) []string {
+		opts.Paginator = &db.ListOptions{Page: 1, PageSize: 10}
+		result, err := indexer.Search(context.Background(), opts)
+		require.NoError(t, err)
+		assert.Len(t, result.Hits, int(result.Total))
+		messages := make([]string, 0, len(result.Hits))
+		for _, hit := range result.Hits {
+			assert.EqualValues(t, repoID, hit.RepoID)
+			messages = append(messages, hit.CommitID[:7])
+		}
+		return messages
+	}
+	unix := func(i int64) *int64 { return &i }
+
+	assert.Equal(t, []string{"9800fe7", "58e97d1", "0a8499a", "c20caf7", "6e8eabd"}, search(&internal.SearchOptions{Keyword: "commit"}))
+	assert.Equal(t, []string{"0a8499a"}, search(&internal.SearchOptions{Keyword: "commit-3"}))
+	assert.Equal(t, []string{"58e97d1", "c20caf7"}, search(&internal.SearchOptions{Authors: []string{"bob"}}))
+	assert.Equal(t, []string{"c20caf7"}, search(&internal.SearchOptions{Authors: []string{"Bob@example.com"}, CommittedBeforeUnix: unix(1551600000)}))
+	assert.Equal(t, []string{"58e97d1", "0a8499a"}, search(&internal.SearchOptions{CommittedAfterUnix: unix(1551500000), CommittedBeforeUnix: unix(1551700000)}))
+	assert.Len(t, search(&internal.SearchOptions{Paths: []string{"README"}}), 5)
+	assert.Empty(t, search(&internal.SearchOptions{Paths: []string{"*.go"}}))
+	assert.Equal(t, []string{"58e97d1"}, search(&internal.SearchOptions{Keyword: "58e97d1a"}))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "commit", RepoIDs: []int64{1}}))
+
+	// nothing new to index
+	require.NoError(t, index(git.DefaultContext, indexer, repoID))
+	assert.Len(t, search(&internal.SearchOptions{Keyword: "commit"}), 5)
+
+	require.NoError(t, indexer.Delete(context.Background(), repoID))
+	assert.Empty(t, search(&internal.SearchOptions{Keyword: "commit"}))
+}
+
+func TestParseLogRecord(t *testing.T) {
+	commit := parseLogRecord(1, "9800fe78cabf4fe774fcf376f97fa2a0ed06987b\x00Bill <Bill@example.com>\x001551759132\x00commit-5\n\nbody\n\x00\x00\nREADME.md\x00docs/a b.md\x00")
+	assert.Equal(t, &internal.IndexerData{
+		RepoID:        1,
+		CommitID:      "9800fe78cabf4fe774fcf376f97fa2a0ed06987b",
+		Message:       "commit-5\n\nbody",
+		Author:        "Bill <Bill@example.com>",
+		Paths:         []string{"README.md", "docs/a b.md"},
+		CommittedUnix: 1551759132,
+	}, commit)
+
+	assert.Nil(t, parseLogRecord(1, "9800fe78cabf4fe774fcf376f97fa2a0ed06987b\x00Bill"))
+}
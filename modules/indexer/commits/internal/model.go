@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/indexer/internal"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IndexerData data stored in the commit indexer
+type IndexerData struct {
+	RepoID   int64  `json:"repo_id"`
+	CommitID string `json:"commit_id"`
+
+	// Fields used for keyword searching
+	Message string `json:"message"`
+
+	// Fields used for filtering
+	Author string   `json:"author"` // name and email of the author, "Name <email>"
+	Paths  []string `json:"paths"`
+
+	// CommittedUnix is both used for filtering and sorting
+	CommittedUnix timeutil.TimeStamp `json:"committed_unix"`
+}
+
+// DocumentID returns the id of the commit in the index, the same commit can be in several repositories
+func (d *IndexerData) DocumentID() string {
+	return DocumentID(d.RepoID, d.CommitID)
+}
+
+// DocumentID returns the id in the index of a commit of a repository
+func DocumentID(repoID int64, commitID string) string {
+	return internal.Base36(repoID) + "_" + commitID
+}
+
+// ParseDocumentID returns the repository id and the commit id of a document id
+func ParseDocumentID(id string) (int64, string, error) {
+	repoID, commitID, ok := strings.Cut(id, "_")
+	if !ok {
+		return 0, "", fmt.Errorf("invalid commit indexer id %q", id)
+	}
+	parsed, err := internal.ParseBase36(repoID)
+	if err != nil {
+		return 0, "", err
+	}
+	return parsed, commitID, nil
+}
+
+// IsCommitIDPrefix returns true if the keyword looks like an abbreviated commit id,
+// the commits are then searched by their id too
+func IsCommitIDPrefix(keyword string) bool {
+	if len(keyword) < 7 || len(keyword) > 64 {
+		return false
+	}
+	for _, r := range keyword {
+		if !('0' <= r && r <= '9') && !('a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Match represents one search result
+type Match struct {
+	RepoID   int64   `json:"repo_id"`
+	CommitID string  `json:"commit_id"`
+	Score    float64 `json:"score"`
+}
+
+// SearchResult represents search results
+type SearchResult struct {
+	Total int64
+	Hits  []Match
+}
+
+// SearchOptions represents the options of a commit search, the results are sorted by the commit date, the most recent first
+type SearchOptions struct {
+	Keyword string  // keyword to search in the commit messages
+	RepoIDs []int64 // repository IDs which the commits belong to, all the repositories if empty
+
+	Authors []string // the author name or email must match one of them
+	Paths   []string // one of the touched files must match one of them, "*" and "?" are wildcards
+
+	CommittedAfterUnix  *int64
+	CommittedBeforeUnix *int64
+
+	db.Paginator
+}
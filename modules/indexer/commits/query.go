@@ -0,0 +1,75 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"strings"
+	"time"
+
+	indexer_internal "code.gitea.io/gitea/modules/indexer/internal"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// Query is a commit search query with its operators extracted
+type Query struct {
+	Keyword string
+	Authors []string
+	Paths   []string
+	Repos   []string
+	// After and Before are the unix times of the bounds of the commit dates, inclusive
+	After  *int64
+	Before *int64
+}
+
+// ParseQuery extracts the "author:", "path:", "repo:", "after:" and "before:" operators from a search query.
+// The operator values can be quoted to contain spaces, the dates are written as YYYY-MM-DD.
+func ParseQuery(q string) (*Query, error) {
+	query := &Query{}
+	var keywords []string
+	for _, field := range indexer_internal.SplitQueryFields(q) {
+		name, value, ok := strings.Cut(field, ":")
+		value = indexer_internal.UnquoteQueryValue(value)
+		if !ok || value == "" {
+			keywords = append(keywords, field)
+			continue
+		}
+		switch name {
+		case "author":
+			query.Authors = append(query.Authors, value)
+		case "path":
+			query.Paths = append(query.Paths, value)
+		case "repo":
+			query.Repos = append(query.Repos, value)
+		case "after", "before":
+			date, err := time.ParseInLocation("2006-01-02", value, setting.DefaultUILocation)
+			if err != nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid date %q, dates are written as YYYY-MM-DD", value)
+			}
+			if name == "after" {
+				after := date.Unix()
+				query.After = &after
+			} else {
+				// the whole day is included
+				before := date.AddDate(0, 0, 1).Unix() - 1
+				query.Before = &before
+			}
+		default:
+			keywords = append(keywords, field)
+		}
+	}
+	query.Keyword = strings.Join(keywords, " ")
+	return query, nil
+}
+
+// ToSearchOptions returns the options to perform the search of the query with
+func (q *Query) ToSearchOptions() *SearchOptions {
+	return &SearchOptions{
+		Keyword:             q.Keyword,
+		Authors:             q.Authors,
+		Paths:               q.Paths,
+		CommittedAfterUnix:  q.After,
+		CommittedBeforeUnix: q.Before,
+	}
+}
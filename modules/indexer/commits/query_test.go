@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02", s, setting.DefaultUILocation)
+		assert.NoError(t, err)
+		return d
+	}
+
+	q, err := ParseQuery(`fix crash author:"Alice Smith" path:docs/ repo:user2/repo1 after:2023-01-01 before:2023-01-31`)
+	assert.NoError(t, err)
+	assert.Equal(t, "fix crash", q.Keyword)
+	assert.Equal(t, []string{"Alice Smith"}, q.Authors)
+	assert.Equal(t, []string{"docs/"}, q.Paths)
+	assert.Equal(t, []string{"user2/repo1"}, q.Repos)
+	assert.Equal(t, date("2023-01-01").Unix(), *q.After)
+	assert.Equal(t, date("2023-02-01").Unix()-1, *q.Before)
+
+	opts := q.ToSearchOptions()
+	assert.Equal(t, "fix crash", opts.Keyword)
+	assert.Equal(t, q.After, opts.CommittedAfterUnix)
+
+	// unknown operators and empty values are a part of the keyword
+	q, err = ParseQuery(`fix: author: crash`)
+	assert.NoError(t, err)
+	assert.Equal(t, "fix: author: crash", q.Keyword)
+	assert.Empty(t, q.Authors)
+
+	_, err = ParseQuery(`after:yesterday`)
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package commits
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/indexer/commits/internal"
+)
+
+// SearchOptions indicates the options for searching commits
+type SearchOptions = internal.SearchOptions
+
+// Match is a commit found by a search
+type Match = internal.Match
+
+// SearchCommits searches the indexed commits, the most recent first
+func SearchCommits(ctx context.Context, opts *SearchOptions) ([]Match, int64, error) {
+	result, err := (*globalIndexer.Load()).Search(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.Hits, result.Total, nil
+}
@@ -80,6 +80,13 @@ func NewFilterEq[T bool | int64](field string, value T) FilterEq {
 	return FilterEq(fmt.Sprintf("%s = %v", field, value))
 }
 
+// NewFilterEqString creates a new FilterEq for a string value, its double quotes and backslashes are escaped.
+func NewFilterEqString(field, value string) FilterEq {
+	return FilterEq(fmt.Sprintf(`%s = "%s"`, field, filterStringEscaper.Replace(value)))
+}
+
+var filterStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 func (f FilterEq) Statement() string {
 	return string(f)
 }
@@ -0,0 +1,15 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package internal
+
+import "strings"
+
+// PathWildcard converts a path filter to a wildcard pattern on the whole file path,
+// a filter without any wildcard matches the files containing it.
+func PathWildcard(path string) string {
+	if strings.ContainsAny(path, "*?") {
+		return path
+	}
+	return "*" + path + "*"
+}
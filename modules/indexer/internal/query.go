@@ -0,0 +1,39 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package internal
+
+import "strings"
+
+// SplitQueryFields splits the query on the spaces which aren't quoted
+func SplitQueryFields(q string) []string {
+	var fields []string
+	var sb strings.Builder
+	inQuote := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			sb.WriteRune(r)
+		case !inQuote && (r == ' ' || r == '\t'):
+			if sb.Len() > 0 {
+				fields = append(fields, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if sb.Len() > 0 {
+		fields = append(fields, sb.String())
+	}
+	return fields
+}
+
+// UnquoteQueryValue removes the quotes around the value of a query operator
+func UnquoteQueryValue(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	analyzer_keyword "github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
 	"github.com/blevesearch/bleve/v2/analysis/token/camelcase"
 	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
 	"github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
@@ -23,7 +24,7 @@ import (
 const (
 	issueIndexerAnalyzer      = "issueIndexer"
 	issueIndexerDocType       = "issueIndexerDocType"
-	issueIndexerLatestVersion = 4
+	issueIndexerLatestVersion = 7
 )
 
 const unicodeNormalizeName = "unicodeNormalize"
@@ -67,6 +68,11 @@ func generateIssueIndexMapping() (mapping.IndexMapping, error) {
 	numberFieldMapping.Store = false
 	numberFieldMapping.IncludeInAll = false
 
+	keywordFieldMapping := bleve.NewTextFieldMapping()
+	keywordFieldMapping.Store = false
+	keywordFieldMapping.IncludeInAll = false
+	keywordFieldMapping.Analyzer = analyzer_keyword.Name
+
 	docMapping.AddFieldMappingsAt("is_public", boolFieldMapping)
 
 	docMapping.AddFieldMappingsAt("title", textFieldMapping)
@@ -80,6 +86,10 @@ func generateIssueIndexMapping() (mapping.IndexMapping, error) {
 	docMapping.AddFieldMappingsAt("milestone_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("project_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("project_board_id", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("parent_id", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("type_id", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("field_values", keywordFieldMapping)
+	docMapping.AddFieldMappingsAt("sla_state", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("poster_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("assignee_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("mention_ids", numberFieldMapping)
@@ -223,6 +233,22 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 	if options.ProjectBoardID != nil {
 		queries = append(queries, inner_bleve.NumericEqualityQuery(*options.ProjectBoardID, "project_board_id"))
 	}
+	if options.ParentID != nil {
+		queries = append(queries, inner_bleve.NumericEqualityQuery(*options.ParentID, "parent_id"))
+	}
+
+	if options.TypeID != nil {
+		queries = append(queries, inner_bleve.NumericEqualityQuery(*options.TypeID, "type_id"))
+	}
+	for fieldID, value := range options.FieldValues {
+		q := bleve.NewTermQuery(internal.FieldValueToken(fieldID, value))
+		q.SetField("field_values")
+		queries = append(queries, q)
+	}
+
+	if options.SLAState != nil {
+		queries = append(queries, inner_bleve.NumericEqualityQuery(*options.SLAState, "sla_state"))
+	}
 
 	if options.PosterID != nil {
 		queries = append(queries, inner_bleve.NumericEqualityQuery(*options.PosterID, "poster_id"))
@@ -64,6 +64,10 @@ func ToDBOptions(ctx context.Context, options *internal.SearchOptions) (*issue_m
 		SubscriberID:       convertID(options.SubscriberID),
 		ProjectID:          convertID(options.ProjectID),
 		ProjectBoardID:     convertID(options.ProjectBoardID),
+		ParentID:           convertID(options.ParentID),
+		TypeID:             convertID(options.TypeID),
+		FieldValues:        options.FieldValues,
+		SLAState:           convertID(options.SLAState),
 		IsClosed:           options.IsClosed,
 		IsPull:             options.IsPull,
 		IncludedLabelNames: nil,
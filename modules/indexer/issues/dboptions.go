@@ -51,6 +51,10 @@ func ToSearchOptions(keyword string, opts *issues_model.IssuesOptions) *SearchOp
 
 	searchOpt.ProjectID = convertID(opts.ProjectID)
 	searchOpt.ProjectBoardID = convertID(opts.ProjectBoardID)
+	searchOpt.ParentID = convertID(opts.ParentID)
+	searchOpt.TypeID = convertID(opts.TypeID)
+	searchOpt.FieldValues = opts.FieldValues
+	searchOpt.SLAState = convertID(opts.SLAState)
 	searchOpt.PosterID = convertID(opts.PosterID)
 	searchOpt.AssigneeID = convertID(opts.AssigneeID)
 	searchOpt.MentionID = convertID(opts.MentionedID)
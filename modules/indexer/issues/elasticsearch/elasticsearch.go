@@ -18,7 +18,7 @@ import (
 )
 
 const (
-	issueIndexerLatestVersion = 1
+	issueIndexerLatestVersion = 4
 )
 
 var _ internal.Indexer = &Indexer{}
@@ -59,6 +59,10 @@ const (
 			"milestone_id": { "type": "integer", "index": true },
 			"project_id": { "type": "integer", "index": true },
 			"project_board_id": { "type": "integer", "index": true },
+			"parent_id": { "type": "integer", "index": true },
+			"type_id": { "type": "integer", "index": true },
+			"field_values": { "type": "keyword", "index": true },
+			"sla_state": { "type": "integer", "index": true },
 			"poster_id": { "type": "integer", "index": true },
 			"assignee_id": { "type": "integer", "index": true },
 			"mention_ids": { "type": "integer", "index": true },
@@ -191,6 +195,20 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 	if options.ProjectBoardID != nil {
 		query.Must(elastic.NewTermQuery("project_board_id", *options.ProjectBoardID))
 	}
+	if options.ParentID != nil {
+		query.Must(elastic.NewTermQuery("parent_id", *options.ParentID))
+	}
+
+	if options.TypeID != nil {
+		query.Must(elastic.NewTermQuery("type_id", *options.TypeID))
+	}
+	for fieldID, value := range options.FieldValues {
+		query.Must(elastic.NewTermQuery("field_values", internal.FieldValueToken(fieldID, value)))
+	}
+
+	if options.SLAState != nil {
+		query.Must(elastic.NewTermQuery("sla_state", *options.SLAState))
+	}
 
 	if options.PosterID != nil {
 		query.Must(elastic.NewTermQuery("poster_id", *options.PosterID))
@@ -4,6 +4,8 @@
 package internal
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -28,6 +30,10 @@ type IndexerData struct {
 	MilestoneID        int64              `json:"milestone_id"`
 	ProjectID          int64              `json:"project_id"`
 	ProjectBoardID     int64              `json:"project_board_id"`
+	ParentID           int64              `json:"parent_id"`
+	TypeID             int64              `json:"type_id"`
+	FieldValues        []string           `json:"field_values"` // values of the fields of the type, see FieldValueToken
+	SLAState           int64              `json:"sla_state"`    // state of the SLA, zero means no SLA
 	PosterID           int64              `json:"poster_id"`
 	AssigneeID         int64              `json:"assignee_id"`
 	MentionIDs         []int64            `json:"mention_ids"`
@@ -44,6 +50,12 @@ type IndexerData struct {
 	CommentCount int64              `json:"comment_count"`
 }
 
+// FieldValueToken returns the token indexed for a value of a field of the type of an issue,
+// the values are compared case insensitively
+func FieldValueToken(fieldID, value string) string {
+	return strings.ToLower(fieldID + "=" + value)
+}
+
 // Match represents on search result
 type Match struct {
 	ID    int64   `json:"id"`
@@ -90,6 +102,13 @@ type SearchOptions struct {
 	ProjectID      *int64 // project the issues belong to
 	ProjectBoardID *int64 // project board the issues belong to
 
+	ParentID *int64 // parent of the sub-issues, zero means no parent
+
+	TypeID      *int64            // type of the issues, zero means no type
+	FieldValues map[string]string // values of the fields of the type by field id, the issues must have all of them
+
+	SLAState *int64 // state of the SLA of the issues, zero means no SLA
+
 	PosterID *int64 // poster of the issues
 
 	AssigneeID *int64 // assignee of the issues, zero means no assignee
@@ -378,6 +378,112 @@ var cases = []*testIndexerCase{
 			}), result.Total)
 		},
 	},
+	{
+		Name: "ParentID",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			ParentID: func() *int64 {
+				id := int64(1)
+				return &id
+			}(),
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Equal(t, int64(1), data[v.ID].ParentID)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.ParentID == 1
+			}), result.Total)
+		},
+	},
+	{
+		Name: "no ParentID",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			ParentID: func() *int64 {
+				id := int64(0)
+				return &id
+			}(),
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Equal(t, int64(0), data[v.ID].ParentID)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.ParentID == 0
+			}), result.Total)
+		},
+	},
+	{
+		Name: "TypeID",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			TypeID: func() *int64 {
+				id := int64(1)
+				return &id
+			}(),
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Equal(t, int64(1), data[v.ID].TypeID)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.TypeID == 1
+			}), result.Total)
+		},
+	},
+	{
+		Name: "FieldValues",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			FieldValues: map[string]string{
+				"severity":  "HIGH",
+				"platforms": "Linux",
+			},
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Contains(t, data[v.ID].FieldValues, "severity=high")
+				assert.Contains(t, data[v.ID].FieldValues, "platforms=linux")
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return util.SliceContainsString(v.FieldValues, "severity=high") && util.SliceContainsString(v.FieldValues, "platforms=linux")
+			}), result.Total)
+		},
+	},
+	{
+		Name: "SLAState",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			SLAState: func() *int64 {
+				state := int64(3)
+				return &state
+			}(),
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Equal(t, int64(3), data[v.ID].SLAState)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.SLAState == 3
+			}), result.Total)
+		},
+	},
 	{
 		Name: "PosterID",
 		SearchOptions: &internal.SearchOptions{
@@ -741,6 +847,15 @@ func generateDefaultIndexerData() []*internal.IndexerData {
 				subscriberIDs[i] = int64(i) + 1 // SubscriberID should not be 0
 			}
 
+			var fieldValues []string
+			typeID := issueIndex % 3
+			if typeID > 0 {
+				fieldValues = append(fieldValues, internal.FieldValueToken("severity", []string{"Low", "High"}[id%2]))
+				if id%3 == 0 {
+					fieldValues = append(fieldValues, internal.FieldValueToken("platforms", "Linux"), internal.FieldValueToken("platforms", "Windows"))
+				}
+			}
+
 			data = append(data, &internal.IndexerData{
 				ID:                 id,
 				RepoID:             repoID,
@@ -755,6 +870,10 @@ func generateDefaultIndexerData() []*internal.IndexerData {
 				MilestoneID:        issueIndex % 4,
 				ProjectID:          issueIndex % 5,
 				ProjectBoardID:     issueIndex % 6,
+				ParentID:           issueIndex % 7,
+				TypeID:             typeID,
+				FieldValues:        fieldValues,
+				SLAState:           issueIndex % 4,
 				PosterID:           id%10 + 1, // PosterID should not be 0
 				AssigneeID:         issueIndex % 10,
 				MentionIDs:         mentionIDs,
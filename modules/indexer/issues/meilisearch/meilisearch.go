@@ -16,7 +16,7 @@ import (
 )
 
 const (
-	issueIndexerLatestVersion = 2
+	issueIndexerLatestVersion = 5
 
 	// TODO: make this configurable if necessary
 	maxTotalHits = 10000
@@ -58,6 +58,10 @@ func NewIndexer(url, apiKey, indexerName string) *Indexer {
 			"milestone_id",
 			"project_id",
 			"project_board_id",
+			"parent_id",
+			"type_id",
+			"field_values",
+			"sla_state",
 			"poster_id",
 			"assignee_id",
 			"mention_ids",
@@ -169,6 +173,20 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 	if options.ProjectBoardID != nil {
 		query.And(inner_meilisearch.NewFilterEq("project_board_id", *options.ProjectBoardID))
 	}
+	if options.ParentID != nil {
+		query.And(inner_meilisearch.NewFilterEq("parent_id", *options.ParentID))
+	}
+
+	if options.TypeID != nil {
+		query.And(inner_meilisearch.NewFilterEq("type_id", *options.TypeID))
+	}
+	for fieldID, value := range options.FieldValues {
+		query.And(inner_meilisearch.NewFilterEqString("field_values", internal.FieldValueToken(fieldID, value)))
+	}
+
+	if options.SLAState != nil {
+		query.And(inner_meilisearch.NewFilterEq("sla_state", *options.SLAState))
+	}
 
 	if options.PosterID != nil {
 		query.And(inner_meilisearch.NewFilterEq("poster_id", *options.PosterID))
@@ -94,6 +94,33 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 		projectID = issue.Project.ID
 	}
 
+	parentID, err := issue_model.GetParentIssueID(ctx, issue.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var fieldValues []string
+	if issue.TypeID > 0 {
+		values, err := issue_model.GetIssueFieldValues(ctx, issue.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		for fieldID, vs := range values {
+			for _, v := range vs {
+				fieldValues = append(fieldValues, internal.FieldValueToken(fieldID, v))
+			}
+		}
+	}
+
+	var slaState int64
+	sla, err := issue_model.GetIssueSLA(ctx, issue.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if sla != nil {
+		slaState = int64(sla.State)
+	}
+
 	return &internal.IndexerData{
 		ID:                 issue.ID,
 		RepoID:             issue.RepoID,
@@ -108,6 +135,10 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 		MilestoneID:        issue.MilestoneID,
 		ProjectID:          projectID,
 		ProjectBoardID:     issue.ProjectBoardID(),
+		ParentID:           parentID,
+		TypeID:             issue.TypeID,
+		FieldValues:        fieldValues,
+		SLAState:           slaState,
 		PosterID:           issue.PosterID,
 		AssigneeID:         issue.AssigneeID,
 		MentionIDs:         mentionIDs,
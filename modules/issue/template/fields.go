@@ -0,0 +1,124 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/container"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalFields parses out the valid fields of a form definition, the content is the yaml of an issue form
+// with only its "body" key. Unlike the templates, the ids of the fields which have a value are required since
+// they are the keys of the values stored for the issues.
+func UnmarshalFields(content string) ([]*api.IssueFormField, error) {
+	form := &struct {
+		Fields []*api.IssueFormField `yaml:"body"`
+	}{}
+	if err := yaml.Unmarshal([]byte(content), form); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal: %w", err)
+	}
+	if err := validateYaml(&api.IssueTemplate{Fields: form.Fields}); err != nil {
+		return nil, err
+	}
+	return form.Fields, nil
+}
+
+// FieldValues returns the values of the fields submitted with a form, by field id. They are the text of the inputs
+// and textareas and the labels of the selected options of the dropdowns and checkboxes, the fields without value
+// are omitted.
+func FieldValues(fields []*api.IssueFormField, values url.Values) map[string][]string {
+	ret := make(map[string][]string, len(fields))
+	for _, field := range fields {
+		f := &valuedField{
+			IssueFormField: field,
+			Values:         values,
+		}
+		var fieldValues []string
+		switch f.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if value := f.Value(); value != "" {
+				fieldValues = []string{value}
+			}
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			for _, option := range f.Options() {
+				if option.IsChecked() {
+					fieldValues = append(fieldValues, option.Label())
+				}
+			}
+		}
+		if len(fieldValues) > 0 {
+			ret[f.ID] = fieldValues
+		}
+	}
+	return ret
+}
+
+// ValidateFieldValues checks the values of the fields by field id, as returned by FieldValues, against the
+// definition of the fields: the required fields, the numbers and the patterns of the inputs and the options.
+func ValidateFieldValues(fields []*api.IssueFormField, values map[string][]string) error {
+	known := make(container.Set[string], len(fields))
+	for _, field := range fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		known.Add(field.ID)
+		f := &valuedField{IssueFormField: field}
+		fieldValues := values[field.ID]
+
+		if required, _ := field.Validations["required"].(bool); required && len(fieldValues) == 0 {
+			return fmt.Errorf("field %q is required", field.ID)
+		}
+
+		switch field.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if len(fieldValues) > 1 {
+				return fmt.Errorf("field %q accepts only one value", field.ID)
+			}
+			if field.Type != api.IssueFormFieldTypeInput || len(fieldValues) == 0 {
+				continue
+			}
+			if isNumber, _ := field.Validations["is_number"].(bool); isNumber {
+				if _, err := strconv.ParseFloat(fieldValues[0], 64); err != nil {
+					return fmt.Errorf("field %q should be a number", field.ID)
+				}
+			}
+			if pattern, _ := field.Validations["regex"].(string); pattern != "" {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return fmt.Errorf("field %q has an invalid regex: %w", field.ID, err)
+				}
+				if !re.MatchString(fieldValues[0]) {
+					return fmt.Errorf("field %q should match %q", field.ID, pattern)
+				}
+			}
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			if multiple, _ := field.Attributes["multiple"].(bool); !multiple && field.Type == api.IssueFormFieldTypeDropdown && len(fieldValues) > 1 {
+				return fmt.Errorf("field %q accepts only one value", field.ID)
+			}
+			labels := make(container.Set[string])
+			for _, option := range f.Options() {
+				labels.Add(option.Label())
+			}
+			for _, value := range fieldValues {
+				if !labels.Contains(value) {
+					return fmt.Errorf("field %q has no option %q", field.ID, value)
+				}
+			}
+		}
+	}
+
+	for id := range values {
+		if !known.Contains(id) {
+			return fmt.Errorf("field %q doesn't exist", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFields = `
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for the report
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      is_number: true
+  - type: dropdown
+    id: severity
+    attributes:
+      label: Severity
+      options:
+        - Low
+        - High
+    validations:
+      required: true
+  - type: checkboxes
+    id: platforms
+    attributes:
+      label: Platforms
+      options:
+        - label: Linux
+        - label: Windows
+`
+
+func TestUnmarshalFields(t *testing.T) {
+	fields, err := UnmarshalFields(testFields)
+	require.NoError(t, err)
+	assert.Len(t, fields, 4)
+
+	_, err = UnmarshalFields("body: []")
+	assert.EqualError(t, err, "'body' is required")
+
+	_, err = UnmarshalFields(`
+body:
+  - type: input
+    attributes:
+      label: Version
+`)
+	assert.EqualError(t, err, "body[0](input): 'id' is required")
+}
+
+func TestFieldValues(t *testing.T) {
+	fields, err := UnmarshalFields(testFields)
+	require.NoError(t, err)
+
+	values := FieldValues(fields, url.Values{
+		"form-field-version":     {" 1.21 "},
+		"form-field-severity":    {"1"},
+		"form-field-platforms-0": {"on"},
+		"form-field-platforms-1": {"on"},
+	})
+	assert.Equal(t, map[string][]string{
+		"version":   {"1.21"},
+		"severity":  {"High"},
+		"platforms": {"Linux", "Windows"},
+	}, values)
+	assert.NoError(t, ValidateFieldValues(fields, values))
+
+	values = FieldValues(fields, url.Values{"form-field-severity": {"0"}})
+	assert.Equal(t, map[string][]string{"severity": {"Low"}}, values)
+	assert.NoError(t, ValidateFieldValues(fields, values))
+}
+
+func TestValidateFieldValues(t *testing.T) {
+	fields, err := UnmarshalFields(testFields)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		values  map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "missing required",
+			values:  map[string][]string{"version": {"1"}},
+			wantErr: `field "severity" is required`,
+		},
+		{
+			name:    "not a number",
+			values:  map[string][]string{"severity": {"Low"}, "version": {"latest"}},
+			wantErr: `field "version" should be a number`,
+		},
+		{
+			name:    "unknown option",
+			values:  map[string][]string{"severity": {"Critical"}},
+			wantErr: `field "severity" has no option "Critical"`,
+		},
+		{
+			name:    "several values",
+			values:  map[string][]string{"severity": {"Low", "High"}},
+			wantErr: `field "severity" accepts only one value`,
+		},
+		{
+			name:    "unknown field",
+			values:  map[string][]string{"severity": {"Low"}, "owner": {"me"}},
+			wantErr: `field "owner" doesn't exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, ValidateFieldValues(fields, tt.values), tt.wantErr)
+		})
+	}
+}
@@ -14,17 +14,17 @@ type Commentable interface {
 
 // Comment is a standard comment information
 type Comment struct {
-	IssueIndex  int64 `yaml:"issue_index"`
-	Index       int64
-	CommentType string `yaml:"comment_type"` // see `commentStrings` in models/issues/comment.go
-	PosterID    int64  `yaml:"poster_id"`
-	PosterName  string `yaml:"poster_name"`
-	PosterEmail string `yaml:"poster_email"`
-	Created     time.Time
-	Updated     time.Time
-	Content     string
-	Reactions   []*Reaction
-	Meta        map[string]any `yaml:"meta,omitempty"` // see models/issues/comment.go for fields in Comment struct
+	IssueIndex  int64          `yaml:"issue_index" json:"issue_index"`
+	Index       int64          `json:"index"`
+	CommentType string         `yaml:"comment_type" json:"comment_type"` // see `commentStrings` in models/issues/comment.go
+	PosterID    int64          `yaml:"poster_id" json:"poster_id"`
+	PosterName  string         `yaml:"poster_name" json:"poster_name"`
+	PosterEmail string         `yaml:"poster_email" json:"poster_email"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated"`
+	Content     string         `json:"content"`
+	Reactions   []*Reaction    `json:"reactions"`
+	Meta        map[string]any `yaml:"meta,omitempty" json:"meta,omitempty"` // see models/issues/comment.go for fields in Comment struct
 }
 
 // GetExternalName ExternalUserMigrated interface
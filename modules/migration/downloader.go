@@ -6,6 +6,7 @@ package migration
 
 import (
 	"context"
+	"time"
 
 	"code.gitea.io/gitea/modules/structs"
 )
@@ -27,6 +28,12 @@ type Downloader interface {
 	FormatCloneURL(opts MigrateOptions, remoteAddr string) (string, error)
 }
 
+// IncrementalDownloader is implemented by the downloaders which can restrict the issues, comments and pull requests
+// they return to those updated since a time, the sync of migrated repositories uses it to download only the changes
+type IncrementalDownloader interface {
+	SetSince(since time.Time)
+}
+
 // DownloaderFactory defines an interface to match a downloader implementation and create a downloader
 type DownloaderFactory interface {
 	New(ctx context.Context, opts MigrateOptions) (Downloader, error)
@@ -34,6 +34,15 @@ func Load(filename string, data any, validation bool) error {
 	return unmarshal(bs, data, isJSON)
 }
 
+// LoadIssueExport reads an export of issues in JSON, or else in YAML
+func LoadIssueExport(bs []byte, isJSON bool) (*IssueExport, error) {
+	export := &IssueExport{}
+	if err := unmarshal(bs, export, isJSON); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
 func unmarshal(bs []byte, data any, isJSON bool) error {
 	if isJSON {
 		return json.Unmarshal(bs, data)
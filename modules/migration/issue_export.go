@@ -0,0 +1,15 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migration
+
+// IssueExport is a set of issues exported from a repository with the labels, milestones and comments they
+// reference, it can be imported into another repository
+type IssueExport struct {
+	// OriginalURL is the link of the exported repository, the users of an export from the same instance are kept
+	OriginalURL string       `yaml:"original_url" json:"original_url"`
+	Labels      []*Label     `json:"labels"`
+	Milestones  []*Milestone `json:"milestones"`
+	Issues      []*Issue     `json:"issues"`
+	Comments    []*Comment   `json:"comments"`
+}
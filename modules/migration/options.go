@@ -38,4 +38,5 @@ type MigrateOptions struct {
 	ReleaseAssets   bool
 	MigrateToRepoID int64
 	MirrorInterval  string `json:"mirror_interval"`
+	SyncInterval    string `json:"sync_interval"`
 }
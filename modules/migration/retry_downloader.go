@@ -8,7 +8,10 @@ import (
 	"time"
 )
 
-var _ Downloader = &RetryDownloader{}
+var (
+	_ Downloader            = &RetryDownloader{}
+	_ IncrementalDownloader = &RetryDownloader{}
+)
 
 // RetryDownloader retry the downloads
 type RetryDownloader struct {
@@ -55,6 +58,13 @@ func (d *RetryDownloader) SetContext(ctx context.Context) {
 	d.Downloader.SetContext(ctx)
 }
 
+// SetSince sets the time since which the wrapped downloader returns the updates, if it supports it
+func (d *RetryDownloader) SetSince(since time.Time) {
+	if downloader, ok := d.Downloader.(IncrementalDownloader); ok {
+		downloader.SetSince(since)
+	}
+}
+
 // GetRepoInfo returns a repository information with retry
 func (d *RetryDownloader) GetRepoInfo() (*Repository, error) {
 	var (
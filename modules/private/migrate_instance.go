@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// MigrateInstanceParams are the parameters of a migration of organizations and users from another instance
+type MigrateInstanceParams struct {
+	BaseURL   string
+	AuthToken string
+	Owners    []string
+	Units     []string
+	StateFile string
+	DryRun    bool
+}
+
+// MigrateInstance calls the internal MigrateInstance function, it returns the report of the migration
+func MigrateInstance(ctx context.Context, params MigrateInstanceParams) (string, ResponseExtra) {
+	reqURL := setting.LocalURL + "api/internal/migrate_instance"
+
+	req := newInternalRequest(ctx, reqURL, "POST", params)
+	req.SetTimeout(3*time.Second, 0) // since the request will spend much time, don't timeout
+	resp, extra := requestJSONResp(req, &responseText{})
+	if extra.HasError() {
+		return "", extra
+	}
+	return resp.Text, extra
+}
@@ -29,10 +29,17 @@ var Indexer = struct {
 	RepoPath             string
 	RepoConnStr          string
 	RepoIndexerName      string
+	MaxIndexedRefs       int
 	MaxIndexerFileSize   int64
 	IncludePatterns      []glob.Glob
 	ExcludePatterns      []glob.Glob
 	ExcludeVendored      bool
+
+	CommitIndexerEnabled bool
+	CommitType           string
+	CommitPath           string
+	CommitConnStr        string
+	CommitIndexerName    string
 }{
 	IssueType:        "bleve",
 	IssuePath:        "indexers/issues.bleve",
@@ -46,8 +53,15 @@ var Indexer = struct {
 	RepoPath:             "indexers/repos.bleve",
 	RepoConnStr:          "",
 	RepoIndexerName:      "gitea_codes",
+	MaxIndexedRefs:       20,
 	MaxIndexerFileSize:   1024 * 1024,
 	ExcludeVendored:      true,
+
+	CommitIndexerEnabled: false,
+	CommitType:           "bleve",
+	CommitPath:           "indexers/commits.bleve",
+	CommitConnStr:        "",
+	CommitIndexerName:    "gitea_commits",
 }
 
 func loadIndexerFrom(rootCfg ConfigProvider) {
@@ -75,18 +89,32 @@ func loadIndexerFrom(rootCfg ConfigProvider) {
 	Indexer.RepoIndexerEnabled = sec.Key("REPO_INDEXER_ENABLED").MustBool(false)
 	Indexer.RepoIndexerRepoTypes = strings.Split(sec.Key("REPO_INDEXER_REPO_TYPES").MustString("sources,forks,mirrors,templates"), ",")
 	Indexer.RepoType = sec.Key("REPO_INDEXER_TYPE").MustString("bleve")
-	Indexer.RepoPath = filepath.ToSlash(sec.Key("REPO_INDEXER_PATH").MustString(filepath.ToSlash(filepath.Join(AppDataPath, "indexers/repos.bleve"))))
+	defaultRepoPath := "indexers/repos.bleve"
+	if Indexer.RepoType == "trigram" {
+		defaultRepoPath = "indexers/repos.trigram"
+	}
+	Indexer.RepoPath = filepath.ToSlash(sec.Key("REPO_INDEXER_PATH").MustString(filepath.ToSlash(filepath.Join(AppDataPath, defaultRepoPath))))
 	if !filepath.IsAbs(Indexer.RepoPath) {
 		Indexer.RepoPath = filepath.ToSlash(filepath.Join(AppWorkPath, Indexer.RepoPath))
 	}
 	Indexer.RepoConnStr = sec.Key("REPO_INDEXER_CONN_STR").MustString("")
 	Indexer.RepoIndexerName = sec.Key("REPO_INDEXER_NAME").MustString("gitea_codes")
+	Indexer.MaxIndexedRefs = sec.Key("REPO_INDEXER_MAX_REFS").MustInt(20)
 
 	Indexer.IncludePatterns = IndexerGlobFromString(sec.Key("REPO_INDEXER_INCLUDE").MustString(""))
 	Indexer.ExcludePatterns = IndexerGlobFromString(sec.Key("REPO_INDEXER_EXCLUDE").MustString(""))
 	Indexer.ExcludeVendored = sec.Key("REPO_INDEXER_EXCLUDE_VENDORED").MustBool(true)
 	Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(1024 * 1024)
 	Indexer.StartupTimeout = sec.Key("STARTUP_TIMEOUT").MustDuration(30 * time.Second)
+
+	Indexer.CommitIndexerEnabled = sec.Key("COMMIT_INDEXER_ENABLED").MustBool(false)
+	Indexer.CommitType = sec.Key("COMMIT_INDEXER_TYPE").MustString("bleve")
+	Indexer.CommitPath = filepath.ToSlash(sec.Key("COMMIT_INDEXER_PATH").MustString(filepath.ToSlash(filepath.Join(AppDataPath, "indexers/commits.bleve"))))
+	if !filepath.IsAbs(Indexer.CommitPath) {
+		Indexer.CommitPath = filepath.ToSlash(filepath.Join(AppWorkPath, Indexer.CommitPath))
+	}
+	Indexer.CommitConnStr = sec.Key("COMMIT_INDEXER_CONN_STR").MustString("")
+	Indexer.CommitIndexerName = sec.Key("COMMIT_INDEXER_NAME").MustString("gitea_commits")
 }
 
 // IndexerGlobFromString parses a comma separated list of patterns and returns a glob.Glob slice suited for repo indexing
@@ -5,6 +5,7 @@ package setting
 
 import (
 	"net/url"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -20,6 +21,15 @@ var Webhook = struct {
 	ProxyURL        string
 	ProxyURLFixed   *url.URL
 	ProxyHosts      []string
+	// MaxRetries is the number of automatic retries of a failed delivery
+	MaxRetries int
+	// RetryInterval is the delay before the first retry, it doubles for each following retry up to MaxRetryInterval
+	RetryInterval time.Duration
+	// MaxRetryInterval is the maximum delay between two retries, 0 doesn't limit the delay
+	MaxRetryInterval time.Duration
+	// DisableAfterFailures is the number of consecutive failed deliveries after which a webhook is disabled, 0
+	// never disables webhooks
+	DisableAfterFailures int
 }{
 	QueueLength:    1000,
 	DeliverTimeout: 5,
@@ -27,6 +37,10 @@ var Webhook = struct {
 	PagingNum:      10,
 	ProxyURL:       "",
 	ProxyHosts:     []string{},
+
+	MaxRetries:       3,
+	RetryInterval:    time.Minute,
+	MaxRetryInterval: time.Hour,
 }
 
 func loadWebhookFrom(rootCfg ConfigProvider) {
@@ -35,7 +49,7 @@ func loadWebhookFrom(rootCfg ConfigProvider) {
 	Webhook.DeliverTimeout = sec.Key("DELIVER_TIMEOUT").MustInt(5)
 	Webhook.SkipTLSVerify = sec.Key("SKIP_TLS_VERIFY").MustBool()
 	Webhook.AllowedHostList = sec.Key("ALLOWED_HOST_LIST").MustString("")
-	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk", "telegram", "msteams", "feishu", "matrix", "wechatwork", "packagist"}
+	Webhook.Types = []string{"gitea", "gogs", "slack", "discord", "dingtalk", "telegram", "msteams", "feishu", "matrix", "wechatwork", "packagist", "custom"}
 	Webhook.PagingNum = sec.Key("PAGING_NUM").MustInt(10)
 	Webhook.ProxyURL = sec.Key("PROXY_URL").MustString("")
 	if Webhook.ProxyURL != "" {
@@ -47,4 +61,12 @@ func loadWebhookFrom(rootCfg ConfigProvider) {
 		}
 	}
 	Webhook.ProxyHosts = sec.Key("PROXY_HOSTS").Strings(",")
+	Webhook.MaxRetries = sec.Key("MAX_RETRIES").MustInt(3)
+	Webhook.RetryInterval = sec.Key("RETRY_INTERVAL").MustDuration(time.Minute)
+	Webhook.MaxRetryInterval = sec.Key("MAX_RETRY_INTERVAL").MustDuration(time.Hour)
+	if Webhook.RetryInterval <= 0 {
+		log.Error("Webhook RETRY_INTERVAL must be positive")
+		Webhook.RetryInterval = time.Minute
+	}
+	Webhook.DisableAfterFailures = sec.Key("DISABLE_AFTER_FAILURES").MustInt(0)
 }
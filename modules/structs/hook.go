@@ -41,7 +41,7 @@ type CreateHookOptionConfig map[string]string
 // CreateHookOption options when create a hook
 type CreateHookOption struct {
 	// required: true
-	// enum: dingtalk,discord,gitea,gogs,msteams,slack,telegram,feishu,wechatwork,packagist
+	// enum: dingtalk,discord,gitea,gogs,msteams,slack,telegram,feishu,wechatwork,packagist,custom
 	Type string `json:"type" binding:"Required"`
 	// required: true
 	Config              CreateHookOptionConfig `json:"config" binding:"Required"`
@@ -114,6 +114,12 @@ var (
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
 	_ Payloader = &PackagePayload{}
+	_ Payloader = &WorkflowRunPayload{}
+	_ Payloader = &WorkflowJobPayload{}
+	_ Payloader = &BranchProtectionPayload{}
+	_ Payloader = &MemberPayload{}
+	_ Payloader = &MembershipPayload{}
+	_ Payloader = &DeployKeyPayload{}
 )
 
 // _________                        __
@@ -456,6 +462,10 @@ const (
 	HookRepoCreated HookRepoAction = "created"
 	// HookRepoDeleted deleted
 	HookRepoDeleted HookRepoAction = "deleted"
+	// HookRepoPublicized made public
+	HookRepoPublicized HookRepoAction = "publicized"
+	// HookRepoPrivatized made private
+	HookRepoPrivatized HookRepoAction = "privatized"
 )
 
 // RepositoryPayload payload for repository webhooks
@@ -494,3 +504,126 @@ type PackagePayload struct {
 func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// HookWorkflowAction an action that happens to a workflow run or job
+type HookWorkflowAction string
+
+const (
+	// HookWorkflowRequested a run has been requested
+	HookWorkflowRequested HookWorkflowAction = "requested"
+	// HookWorkflowQueued a job is waiting for a runner
+	HookWorkflowQueued HookWorkflowAction = "queued"
+	// HookWorkflowInProgress a run or job has started
+	HookWorkflowInProgress HookWorkflowAction = "in_progress"
+	// HookWorkflowCompleted a run or job has completed
+	HookWorkflowCompleted HookWorkflowAction = "completed"
+)
+
+// WorkflowRunPayload represents a payload information of workflow run event.
+type WorkflowRunPayload struct {
+	Action       HookWorkflowAction `json:"action"`
+	WorkflowRun  *ActionWorkflowRun `json:"workflow_run"`
+	Repository   *Repository        `json:"repository"`
+	Organization *User              `json:"organization,omitempty"`
+	Sender       *User              `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowRunPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// WorkflowJobPayload represents a payload information of workflow job event.
+type WorkflowJobPayload struct {
+	Action       HookWorkflowAction `json:"action"`
+	WorkflowJob  *ActionWorkflowJob `json:"workflow_job"`
+	Repository   *Repository        `json:"repository"`
+	Organization *User              `json:"organization,omitempty"`
+	Sender       *User              `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowJobPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookBranchProtectionAction an action that happens to a protected branch rule
+type HookBranchProtectionAction string
+
+const (
+	// HookBranchProtectionCreated created
+	HookBranchProtectionCreated HookBranchProtectionAction = "created"
+	// HookBranchProtectionEdited edited
+	HookBranchProtectionEdited HookBranchProtectionAction = "edited"
+	// HookBranchProtectionDeleted deleted
+	HookBranchProtectionDeleted HookBranchProtectionAction = "deleted"
+)
+
+// BranchProtectionPayload represents a payload information of branch protection event.
+type BranchProtectionPayload struct {
+	Action     HookBranchProtectionAction `json:"action"`
+	Rule       *BranchProtection          `json:"rule"`
+	Repository *Repository                `json:"repository"`
+	Sender     *User                      `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *BranchProtectionPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookMemberAction an action that happens to a collaborator or a team member
+type HookMemberAction string
+
+const (
+	// HookMemberAdded added
+	HookMemberAdded HookMemberAction = "added"
+	// HookMemberRemoved removed
+	HookMemberRemoved HookMemberAction = "removed"
+)
+
+// MemberPayload represents a payload information of collaborator event.
+type MemberPayload struct {
+	Action     HookMemberAction `json:"action"`
+	Member     *User            `json:"member"`
+	Repository *Repository      `json:"repository"`
+	Sender     *User            `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *MemberPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// MembershipPayload represents a payload information of team membership event.
+type MembershipPayload struct {
+	Action       HookMemberAction `json:"action"`
+	Member       *User            `json:"member"`
+	Team         *Team            `json:"team"`
+	Organization *User            `json:"organization"`
+	Sender       *User            `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *MembershipPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookDeployKeyAction an action that happens to a deploy key
+type HookDeployKeyAction string
+
+// HookDeployKeyCreated created
+const HookDeployKeyCreated HookDeployKeyAction = "created"
+
+// DeployKeyPayload represents a payload information of deploy key event.
+type DeployKeyPayload struct {
+	Action     HookDeployKeyAction `json:"action"`
+	Key        *DeployKey          `json:"key"`
+	Repository *Repository         `json:"repository"`
+	Sender     *User               `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *DeployKeyPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
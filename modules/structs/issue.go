@@ -72,6 +72,8 @@ type Issue struct {
 	Closed *time.Time `json:"closed_at"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
+	// time estimate in seconds
+	TimeEstimate int64 `json:"time_estimate"`
 
 	PullRequest *PullRequestMeta `json:"pull_request"`
 	Repo        *RepositoryMeta  `json:"repository"`
@@ -110,6 +112,8 @@ type EditIssueOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// time estimate in seconds, zero removes the estimate
+	TimeEstimate *int64 `json:"time_estimate"`
 }
 
 // EditDeadlineOption options for creating a deadline
@@ -237,3 +241,9 @@ type IssueMeta struct {
 	Owner string `json:"owner"`
 	Name  string `json:"repo"`
 }
+
+// SubIssueProgress represents the progress of an issue computed from the state of its sub-issues, and of theirs
+type SubIssueProgress struct {
+	Total  int64 `json:"total"`
+	Closed int64 `json:"closed"`
+}
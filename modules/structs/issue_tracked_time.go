@@ -35,3 +35,30 @@ type TrackedTime struct {
 
 // TrackedTimeList represents a list of tracked times
 type TrackedTimeList []*TrackedTime
+
+// TrackedTimeReportEntry represents the time tracked on the issues of a group of a report
+type TrackedTimeReportEntry struct {
+	// id of the user, the repository, the milestone, the label or the issue of the group, zero for the issues
+	// without milestone or without label
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// tracked time in seconds
+	Time int64 `json:"time"`
+	// sum of the time estimates of the issues in seconds
+	Estimate int64 `json:"estimate"`
+	// number of issues with tracked time
+	Issues int `json:"issues"`
+}
+
+// TrackedTimeReport represents the time tracked on issues grouped by user, repository, milestone, label or issue
+type TrackedTimeReport struct {
+	// enum: user,repo,milestone,label,issue
+	Group   string                    `json:"group"`
+	Entries []*TrackedTimeReportEntry `json:"entries"`
+	// total tracked time in seconds
+	Time int64 `json:"time"`
+	// sum of the time estimates of the issues in seconds
+	Estimate int64 `json:"estimate"`
+	// number of issues with tracked time
+	Issues int `json:"issues"`
+}
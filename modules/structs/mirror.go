@@ -10,17 +10,26 @@ type CreatePushMirrorOption struct {
 	RemotePassword string `json:"remote_password"`
 	Interval       string `json:"interval"`
 	SyncOnCommit   bool   `json:"sync_on_commit"`
+	// glob patterns of the branches and tags to push, all of them are pushed if empty
+	RefIncludes []string `json:"ref_includes"`
+	// glob patterns of the branches and tags not to push
+	RefExcludes []string `json:"ref_excludes"`
+	// never delete or force-update the refs of the remote
+	NonDestructive bool `json:"non_destructive"`
 }
 
 // PushMirror represents information of a push mirror
 // swagger:model
 type PushMirror struct {
-	RepoName       string `json:"repo_name"`
-	RemoteName     string `json:"remote_name"`
-	RemoteAddress  string `json:"remote_address"`
-	CreatedUnix    string `json:"created"`
-	LastUpdateUnix string `json:"last_update"`
-	LastError      string `json:"last_error"`
-	Interval       string `json:"interval"`
-	SyncOnCommit   bool   `json:"sync_on_commit"`
+	RepoName       string   `json:"repo_name"`
+	RemoteName     string   `json:"remote_name"`
+	RemoteAddress  string   `json:"remote_address"`
+	CreatedUnix    string   `json:"created"`
+	LastUpdateUnix string   `json:"last_update"`
+	LastError      string   `json:"last_error"`
+	Interval       string   `json:"interval"`
+	SyncOnCommit   bool     `json:"sync_on_commit"`
+	RefIncludes    []string `json:"ref_includes"`
+	RefExcludes    []string `json:"ref_excludes"`
+	NonDestructive bool     `json:"non_destructive"`
 }
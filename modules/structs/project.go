@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// Project represents a project board of a repository
+type Project struct {
+	ID           int64     `json:"id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	State        StateType `json:"state"`
+	OpenIssues   int       `json:"open_issues"`
+	ClosedIssues int       `json:"closed_issues"`
+	HTMLURL      string    `json:"html_url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Closed *time.Time `json:"closed_at"`
+}
+
+// ProjectField represents a custom field of a project
+type ProjectField struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: text,number,date,single_select,iteration
+	Type    string                      `json:"type"`
+	Sorting int64                       `json:"sorting"`
+	Options []*ProjectFieldSelectOption `json:"options"`
+}
+
+// ProjectFieldSelectOption represents an option of a single select field, or an iteration of an iteration field
+type ProjectFieldSelectOption struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	Sorting int64  `json:"sorting"`
+	// the dates of an iteration, as YYYY-MM-DD
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+// CreateProjectFieldOption options for creating a project field
+type CreateProjectFieldOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	// enum: text,number,date,single_select,iteration
+	Type    string                            `json:"type" binding:"Required"`
+	Sorting int64                             `json:"sorting"`
+	Options []*CreateProjectFieldSelectOption `json:"options"`
+}
+
+// EditProjectFieldOption options for editing a project field
+type EditProjectFieldOption struct {
+	Name    *string `json:"name" binding:"MaxSize(255)"`
+	Sorting *int64  `json:"sorting"`
+}
+
+// CreateProjectFieldSelectOption options for adding an option or an iteration to a project field
+type CreateProjectFieldSelectOption struct {
+	// required: true
+	Name    string `json:"name" binding:"Required;MaxSize(255)"`
+	Color   string `json:"color" binding:"MaxSize(7)"`
+	Sorting int64  `json:"sorting"`
+	// the dates of an iteration, as YYYY-MM-DD
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// EditProjectFieldSelectOption options for editing an option or an iteration of a project field
+type EditProjectFieldSelectOption struct {
+	Name      *string `json:"name" binding:"MaxSize(255)"`
+	Color     *string `json:"color" binding:"MaxSize(7)"`
+	Sorting   *int64  `json:"sorting"`
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+}
+
+// ProjectFieldValue represents the value of a project field for an issue
+type ProjectFieldValue struct {
+	FieldID int64 `json:"field_id"`
+	// the value as it is set: a text, a number, a date as YYYY-MM-DD or the id of an option
+	Value string `json:"value"`
+	// the value as it is shown, the name of the option for the single select and iteration fields
+	DisplayValue string `json:"display_value"`
+}
+
+// SetProjectFieldValueOption options for setting the value of a project field for an issue
+type SetProjectFieldValueOption struct {
+	// the value as it is set: a text, a number, a date as YYYY-MM-DD or the id of an option,
+	// an empty value removes it
+	Value string `json:"value"`
+}
+
+// ProjectItem represents an issue of a project with the values of its fields
+type ProjectItem struct {
+	Issue       *Issue               `json:"issue"`
+	BoardID     int64                `json:"board_id"`
+	FieldValues []*ProjectFieldValue `json:"field_values"`
+}
@@ -65,6 +65,10 @@ type PullReviewComment struct {
 	DiffHunk     string `json:"diff_hunk"`
 	LineNum      uint64 `json:"position"`
 	OldLineNum   uint64 `json:"original_position"`
+	// first line of a multi-line comment on the new file, 0 for single-line comments
+	StartLineNum uint64 `json:"start_position"`
+	// first line of a multi-line comment on the old file, 0 for single-line comments
+	OldStartLineNum uint64 `json:"original_start_position"`
 
 	HTMLURL     string `json:"html_url"`
 	HTMLPullURL string `json:"pull_request_url"`
@@ -87,6 +91,18 @@ type CreatePullReviewComment struct {
 	OldLineNum int64 `json:"old_position"`
 	// if comment to new file line or 0
 	NewLineNum int64 `json:"new_position"`
+	// first old file line of a multi-line comment ending at old_position, or 0
+	OldStartLineNum int64 `json:"old_start_position"`
+	// first new file line of a multi-line comment ending at new_position, or 0
+	NewStartLineNum int64 `json:"new_start_position"`
+}
+
+// ApplyPullReviewSuggestionsOptions are options to commit the suggested changes of review comments
+type ApplyPullReviewSuggestionsOptions struct {
+	// ids of the review comments whose suggestions are committed together
+	CommentIDs []int64 `json:"comment_ids" binding:"Required"`
+	// commit message, a default one is used if empty
+	Message string `json:"message"`
 }
 
 // SubmitPullReviewOptions are options to submit a pending pull review
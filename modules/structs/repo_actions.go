@@ -0,0 +1,63 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// ActionWorkflowRun represents a run of an Actions workflow
+type ActionWorkflowRun struct {
+	ID int64 `json:"id"`
+	// the number of the run in its repository
+	RunNumber int64 `json:"run_number"`
+	// the name of the workflow file
+	WorkflowID   string `json:"workflow_id"`
+	DisplayTitle string `json:"display_title"`
+	// the event which triggered the run
+	Event      string `json:"event"`
+	HeadBranch string `json:"head_branch"`
+	HeadSha    string `json:"head_sha"`
+	// enum: queued,in_progress,completed
+	Status string `json:"status"`
+	// the result of a completed run
+	// enum: success,failure,cancelled,skipped
+	Conclusion string `json:"conclusion,omitempty"`
+	HTMLURL    string `json:"html_url"`
+	Actor      *User  `json:"actor"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"run_started_at"`
+	// swagger:strfmt date-time
+	Completed *time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowJob represents a job of a run of an Actions workflow
+type ActionWorkflowJob struct {
+	ID         int64  `json:"id"`
+	RunID      int64  `json:"run_id"`
+	RunNumber  int64  `json:"run_number"`
+	RunAttempt int64  `json:"run_attempt"`
+	Name       string `json:"name"`
+	HeadBranch string `json:"head_branch"`
+	HeadSha    string `json:"head_sha"`
+	// enum: queued,in_progress,completed
+	Status string `json:"status"`
+	// the result of a completed job
+	// enum: success,failure,cancelled,skipped
+	Conclusion string   `json:"conclusion,omitempty"`
+	HTMLURL    string   `json:"html_url"`
+	Labels     []string `json:"labels"`
+	RunnerID   int64    `json:"runner_id"`
+	RunnerName string   `json:"runner_name"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	Completed *time.Time `json:"completed_at"`
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// CodeSearchResults represents the results of a code search
+type CodeSearchResults struct {
+	TotalCount int64                 `json:"total_count"`
+	Items      []*CodeSearchResult   `json:"items"`
+	Languages  []*CodeSearchLanguage `json:"languages"`
+	// whether a regular expression search only checked some of the files, the items and the total only cover them
+	Truncated bool `json:"truncated"`
+}
+
+// CodeSearchResult represents a file matching a code search
+type CodeSearchResult struct {
+	Path     string            `json:"path"`
+	CommitID string            `json:"commit_id"`
+	Language string            `json:"language"`
+	HTMLURL  string            `json:"html_url"`
+	Lines    []*CodeSearchLine `json:"lines"`
+	// the definition found by a symbol search
+	Symbol *CodeSymbol `json:"symbol,omitempty"`
+}
+
+// CodeSearchLine represents a line of a file around a code search match
+type CodeSearchLine struct {
+	Number  int    `json:"number"`
+	Content string `json:"content"`
+}
+
+// CodeSymbol represents a function or type definition
+type CodeSymbol struct {
+	Name string `json:"name"`
+	// enum: function,method,class,struct,interface,enum,type,trait,module,constant,variable,macro
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+}
+
+// CodeSearchLanguage represents the number of files of a language matching a code search
+type CodeSearchLanguage struct {
+	Language string `json:"language"`
+	Color    string `json:"color"`
+	Count    int    `json:"count"`
+}
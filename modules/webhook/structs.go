@@ -26,6 +26,13 @@ type HookEvents struct {
 	Repository               bool `json:"repository"`
 	Release                  bool `json:"release"`
 	Package                  bool `json:"package"`
+	WorkflowRun              bool `json:"workflow_run"`
+	WorkflowJob              bool `json:"workflow_job"`
+	BranchProtection         bool `json:"branch_protection"`
+	Member                   bool `json:"member"`
+	Membership               bool `json:"membership"`
+	DeployKey                bool `json:"deploy_key"`
+	RepositoryVisibility     bool `json:"repository_visibility"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -31,6 +31,13 @@ const (
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
 	HookEventPackage                   HookEventType = "package"
+	HookEventWorkflowRun               HookEventType = "workflow_run"
+	HookEventWorkflowJob               HookEventType = "workflow_job"
+	HookEventBranchProtection          HookEventType = "branch_protection"
+	HookEventMember                    HookEventType = "member"
+	HookEventMembership                HookEventType = "membership"
+	HookEventDeployKey                 HookEventType = "deploy_key"
+	HookEventRepositoryVisibility      HookEventType = "repository_visibility"
 )
 
 // Event returns the HookEventType as an event string
@@ -63,6 +70,20 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventWorkflowRun:
+		return "workflow_run"
+	case HookEventWorkflowJob:
+		return "workflow_job"
+	case HookEventBranchProtection:
+		return "branch_protection"
+	case HookEventMember:
+		return "member"
+	case HookEventMembership:
+		return "membership"
+	case HookEventDeployKey:
+		return "deploy_key"
+	case HookEventRepositoryVisibility:
+		return "repository_visibility"
 	}
 	return ""
 }
@@ -83,6 +104,7 @@ const (
 	MATRIX     HookType = "matrix"
 	WECHATWORK HookType = "wechatwork"
 	PACKAGIST  HookType = "packagist"
+	CUSTOM     HookType = "custom"
 )
 
 // HookStatus is the status of a web hook
@@ -32,6 +32,7 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	}
 
 	actions.CreateCommitStatus(ctx, t.Job)
+	actions.NotifyWorkflowJobsStatus(ctx, t.Job)
 
 	task := &runnerv1.Task{
 		Id:              t.ID,
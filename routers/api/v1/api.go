@@ -424,6 +424,30 @@ func reqAnyRepoReader() func(ctx *context.APIContext) {
 }
 
 // reqOrgOwnership user should be an organization owner, or a site admin
+// reqOrgUnitReader user should be able to read the unit in the organization
+func reqOrgUnitReader(unitType unit.Type) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.IsUserSiteAdmin() {
+			return
+		}
+		if ctx.Org.Organization.UnitPermission(ctx, ctx.Doer, unitType) < perm.AccessModeRead {
+			ctx.NotFound()
+		}
+	}
+}
+
+// reqOrgUnitWriter user should be able to write the unit in the organization
+func reqOrgUnitWriter(unitType unit.Type) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.IsUserSiteAdmin() {
+			return
+		}
+		if ctx.Org.Organization.UnitPermission(ctx, ctx.Doer, unitType) < perm.AccessModeWrite {
+			ctx.Error(http.StatusForbidden, "", "Must have write access to the "+unitType.String()+" of the organization")
+		}
+	}
+}
+
 func reqOrgOwnership() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
 		if ctx.IsUserSiteAdmin() {
@@ -914,6 +938,27 @@ func Routes() *web.Route {
 						Post(bind(api.CreateAccessTokenOption{}), reqToken(), user.CreateAccessToken)
 					m.Combo("/{id}").Delete(reqToken(), user.DeleteAccessToken)
 				}, reqSelfOrAdmin(), reqBasicOrRevProxyAuth())
+				m.Group("/projects", func() {
+					m.Get("", user.ListProjects)
+					m.Group("/{id}", func() {
+						m.Get("", user.GetProject)
+						m.Group("/fields", func() {
+							m.Combo("").Get(user.ListProjectFields).
+								Post(reqToken(), reqSelfOrAdmin(), bind(api.CreateProjectFieldOption{}), user.CreateProjectField)
+							m.Group("/{field_id}", func() {
+								m.Combo("").Patch(bind(api.EditProjectFieldOption{}), user.EditProjectField).
+									Delete(user.DeleteProjectField)
+								m.Post("/options", bind(api.CreateProjectFieldSelectOption{}), user.CreateProjectFieldSelectOption)
+								m.Combo("/options/{option_id}").Patch(bind(api.EditProjectFieldSelectOption{}), user.EditProjectFieldSelectOption).
+									Delete(user.DeleteProjectFieldSelectOption)
+							}, reqToken(), reqSelfOrAdmin())
+						})
+						m.Get("/items", user.ListProjectItems)
+						m.Combo("/items/{issue_id}/fields/{field_id}", reqToken(), reqSelfOrAdmin()).
+							Put(bind(api.SetProjectFieldValueOption{}), user.SetProjectItemFieldValue).
+							Delete(user.DeleteProjectItemFieldValue)
+					})
+				})
 
 				m.Get("/activities/feeds", user.ListUserActivityFeeds)
 			}, context_service.UserAssignmentAPI(), individualPermsChecker)
@@ -1127,6 +1172,7 @@ func Routes() *web.Route {
 				}, reqToken(), reqAdmin())
 				m.Group("/times", func() {
 					m.Combo("").Get(repo.ListTrackedTimesByRepository)
+					m.Get("/report", repo.GetTrackedTimeReportByRepository)
 					m.Combo("/{timetrackingusername}").Get(repo.ListTrackedTimesByUser)
 				}, mustEnableIssues, reqToken())
 				m.Group("/wiki", func() {
@@ -1210,6 +1256,7 @@ func Routes() *web.Route {
 								m.Post("/undismissals", reqToken(), repo.UnDismissPullReview)
 							})
 						})
+						m.Post("/suggestions", reqToken(), mustNotBeArchived, bind(api.ApplyPullReviewSuggestionsOptions{}), repo.ApplyPullReviewSuggestions)
 						m.Combo("/requested_reviewers", reqToken()).
 							Delete(bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
@@ -1262,6 +1309,7 @@ func Routes() *web.Route {
 				m.Get("/issue_config", context.ReferencesGitRepo(), repo.GetIssueConfig)
 				m.Get("/issue_config/validate", context.ReferencesGitRepo(), repo.ValidateIssueConfig)
 				m.Get("/languages", reqRepoReader(unit.TypeCode), repo.GetLanguages)
+				m.Get("/search/code", reqRepoReader(unit.TypeCode), repo.SearchCode)
 				m.Get("/activities/feeds", repo.ListRepoActivityFeeds)
 				m.Get("/new_pin_allowed", repo.AreNewIssuePinsAllowed)
 				m.Group("/avatar", func() {
@@ -1369,6 +1417,14 @@ func Routes() *web.Route {
 							Get(repo.GetIssueBlocks).
 							Post(reqToken(), bind(api.IssueMeta{}), repo.CreateIssueBlocking).
 							Delete(reqToken(), bind(api.IssueMeta{}), repo.RemoveIssueBlocking)
+						m.Group("/sub_issues", func() {
+							m.Combo("").
+								Get(repo.ListSubIssues).
+								Post(reqToken(), mustNotBeArchived, bind(api.IssueMeta{}), repo.AddSubIssue).
+								Delete(reqToken(), mustNotBeArchived, bind(api.IssueMeta{}), repo.RemoveSubIssue)
+							m.Get("/progress", repo.GetSubIssueProgress)
+						})
+						m.Get("/parent", repo.GetParentIssue)
 						m.Group("/pin", func() {
 							m.Combo("").
 								Post(reqToken(), reqAdmin(), repo.PinIssue).
@@ -1391,6 +1447,27 @@ func Routes() *web.Route {
 						Patch(reqToken(), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), repo.DeleteMilestone)
 				})
+				m.Group("/projects", func() {
+					m.Get("", repo.ListProjects)
+					m.Group("/{id}", func() {
+						m.Get("", repo.GetProject)
+						m.Group("/fields", func() {
+							m.Combo("").Get(repo.ListProjectFields).
+								Post(reqToken(), reqRepoWriter(unit.TypeProjects), bind(api.CreateProjectFieldOption{}), repo.CreateProjectField)
+							m.Group("/{field_id}", func() {
+								m.Combo("").Patch(bind(api.EditProjectFieldOption{}), repo.EditProjectField).
+									Delete(repo.DeleteProjectField)
+								m.Post("/options", bind(api.CreateProjectFieldSelectOption{}), repo.CreateProjectFieldSelectOption)
+								m.Combo("/options/{option_id}").Patch(bind(api.EditProjectFieldSelectOption{}), repo.EditProjectFieldSelectOption).
+									Delete(repo.DeleteProjectFieldSelectOption)
+							}, reqToken(), reqRepoWriter(unit.TypeProjects))
+						})
+						m.Get("/items", repo.ListProjectItems)
+						m.Combo("/items/{index}/fields/{field_id}", reqToken(), reqRepoWriter(unit.TypeProjects)).
+							Put(bind(api.SetProjectFieldValueOption{}), repo.SetProjectItemFieldValue).
+							Delete(repo.DeleteProjectItemFieldValue)
+					})
+				}, reqRepoReader(unit.TypeProjects))
 			}, repoAssignment())
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryIssue))
 
@@ -1435,6 +1512,10 @@ func Routes() *web.Route {
 					Put(reqToken(), reqOrgMembership(), org.PublicizeMember).
 					Delete(reqToken(), reqOrgMembership(), org.ConcealMember)
 			})
+			m.Group("/times", func() {
+				m.Get("", org.ListTrackedTimes)
+				m.Get("/report", org.GetTrackedTimeReport)
+			}, reqToken(), reqOrgMembership())
 			m.Group("/teams", func() {
 				m.Get("", org.ListTeams)
 				m.Post("", reqOrgOwnership(), bind(api.CreateTeamOption{}), org.CreateTeam)
@@ -1447,6 +1528,34 @@ func Routes() *web.Route {
 					Patch(reqToken(), reqOrgOwnership(), bind(api.EditLabelOption{}), org.EditLabel).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteLabel)
 			})
+			m.Group("/milestones", func() {
+				m.Get("", org.ListMilestones)
+				m.Post("", reqToken(), reqOrgUnitWriter(unit.TypeIssues), bind(api.CreateMilestoneOption{}), org.CreateMilestone)
+				m.Combo("/{id}").Get(org.GetMilestone).
+					Patch(reqToken(), reqOrgUnitWriter(unit.TypeIssues), bind(api.EditMilestoneOption{}), org.EditMilestone).
+					Delete(reqToken(), reqOrgUnitWriter(unit.TypeIssues), org.DeleteMilestone)
+			}, reqOrgUnitReader(unit.TypeIssues))
+			m.Group("/projects", func() {
+				m.Get("", org.ListProjects)
+				m.Group("/{id}", func() {
+					m.Get("", org.GetProject)
+					m.Group("/fields", func() {
+						m.Combo("").Get(org.ListProjectFields).
+							Post(reqToken(), reqOrgUnitWriter(unit.TypeProjects), bind(api.CreateProjectFieldOption{}), org.CreateProjectField)
+						m.Group("/{field_id}", func() {
+							m.Combo("").Patch(bind(api.EditProjectFieldOption{}), org.EditProjectField).
+								Delete(org.DeleteProjectField)
+							m.Post("/options", bind(api.CreateProjectFieldSelectOption{}), org.CreateProjectFieldSelectOption)
+							m.Combo("/options/{option_id}").Patch(bind(api.EditProjectFieldSelectOption{}), org.EditProjectFieldSelectOption).
+								Delete(org.DeleteProjectFieldSelectOption)
+						}, reqToken(), reqOrgUnitWriter(unit.TypeProjects))
+					})
+					m.Get("/items", org.ListProjectItems)
+					m.Combo("/items/{issue_id}/fields/{field_id}", reqToken(), reqOrgUnitWriter(unit.TypeProjects)).
+						Put(bind(api.SetProjectFieldValueOption{}), org.SetProjectItemFieldValue).
+						Delete(org.DeleteProjectItemFieldValue)
+				})
+			}, reqOrgUnitReader(unit.TypeProjects))
 			m.Group("/hooks", func() {
 				m.Combo("").Get(org.ListHooks).
 					Post(bind(api.CreateHookOption{}), org.CreateHook)
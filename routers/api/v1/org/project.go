@@ -0,0 +1,544 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListProjects list the projects of an organization
+func ListProjects(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/projects organization orgListProjects
+	// ---
+	// summary: List an organization's projects
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: Project state, Recognized values are open, closed and all. Defaults to "open"
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListProjects(ctx, ctx.Org.Organization.ID, 0, project_model.TypeOrganization)
+}
+
+// GetProject get a project of an organization
+func GetProject(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/projects/{id} organization orgGetProject
+	// ---
+	// summary: Get a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProject(ctx, project))
+}
+
+// ListProjectFields list the fields of a project
+func ListProjectFields(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/projects/{id}/fields organization orgListProjectFields
+	// ---
+	// summary: List the custom fields of a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectFieldList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.ListProjectFields(ctx, project)
+}
+
+// CreateProjectField create a field of a project
+func CreateProjectField(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/projects/{id}/fields organization orgCreateProjectField
+	// ---
+	// summary: Create a custom field of a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectFieldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectField"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.CreateProjectField(ctx, project)
+}
+
+// EditProjectField edit a field of a project
+func EditProjectField(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/projects/{id}/fields/{field_id} organization orgEditProjectField
+	// ---
+	// summary: Edit a custom field of a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectFieldOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectField"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.EditProjectField(ctx, project)
+}
+
+// DeleteProjectField delete a field of a project
+func DeleteProjectField(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/projects/{id}/fields/{field_id} organization orgDeleteProjectField
+	// ---
+	// summary: Delete a custom field of a project with its values
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.DeleteProjectField(ctx, project)
+}
+
+// CreateProjectFieldSelectOption add an option to a field of a project
+func CreateProjectFieldSelectOption(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/projects/{id}/fields/{field_id}/options organization orgCreateProjectFieldOption
+	// ---
+	// summary: Add an option to a single select field, or an iteration to an iteration field
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectFieldSelectOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectFieldSelectOption"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.CreateProjectFieldSelectOption(ctx, project)
+}
+
+// EditProjectFieldSelectOption edit an option of a field of a project
+func EditProjectFieldSelectOption(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/projects/{id}/fields/{field_id}/options/{option_id} organization orgEditProjectFieldOption
+	// ---
+	// summary: Edit an option or an iteration of a project field
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: option_id
+	//   in: path
+	//   description: id of the option
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectFieldSelectOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectFieldSelectOption"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.EditProjectFieldSelectOption(ctx, project)
+}
+
+// DeleteProjectFieldSelectOption delete an option of a field of a project
+func DeleteProjectFieldSelectOption(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/projects/{id}/fields/{field_id}/options/{option_id} organization orgDeleteProjectFieldOption
+	// ---
+	// summary: Delete an option or an iteration of a project field, the values using it are removed
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: option_id
+	//   in: path
+	//   description: id of the option
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.DeleteProjectFieldSelectOption(ctx, project)
+}
+
+// ListProjectItems list the issues of a project with the values of their fields
+func ListProjectItems(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/projects/{id}/items organization orgListProjectItems
+	// ---
+	// summary: List the issues of a project with the values of their custom fields
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: filter_field
+	//   in: query
+	//   description: id of a field to filter the issues by
+	//   type: integer
+	//   format: int64
+	// - name: filter_value
+	//   in: query
+	//   description: the value of the filter field, the name or the id of an option, empty for the issues without value
+	//   type: string
+	// - name: sort_field
+	//   in: query
+	//   description: id of a field to sort the issues by, the issues without value are last
+	//   type: integer
+	//   format: int64
+	// - name: sort_order
+	//   in: query
+	//   description: order of the sort
+	//   type: string
+	//   enum: [asc, desc]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectItemList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.ListProjectItems(ctx, project)
+}
+
+// SetProjectItemFieldValue set the value of a field of a project for an issue
+func SetProjectItemFieldValue(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/projects/{id}/items/{issue_id}/fields/{field_id} organization orgSetProjectItemFieldValue
+	// ---
+	// summary: Set the value of a custom field of a project for one of its issues
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: issue_id
+	//   in: path
+	//   description: id of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetProjectFieldValueOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectFieldValue"
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetProjectFieldValueOption)
+	setProjectItemFieldValue(ctx, form.Value)
+}
+
+// DeleteProjectItemFieldValue remove the value of a field of a project for an issue
+func DeleteProjectItemFieldValue(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/projects/{id}/items/{issue_id}/fields/{field_id} organization orgDeleteProjectItemFieldValue
+	// ---
+	// summary: Remove the value of a custom field of a project for one of its issues
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: issue_id
+	//   in: path
+	//   description: id of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: field_id
+	//   in: path
+	//   description: id of the field
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	setProjectItemFieldValue(ctx, "")
+}
+
+func setProjectItemFieldValue(ctx *context.APIContext, raw string) {
+	project := getProject(ctx)
+	if ctx.Written() {
+		return
+	}
+	issue, err := issues_model.GetIssueByID(ctx, ctx.ParamsInt64(":issue_id"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		}
+		return
+	}
+	utils.SetProjectItemFieldValue(ctx, project, issue, raw)
+}
+
+func getProject(ctx *context.APIContext) *project_model.Project {
+	return utils.GetProject(ctx, ctx.Org.Organization.ID, 0)
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// trackedTimesOptions returns the options of the times tracked on the issues of the repositories of the
+// organization the doer can access, and whether the doer can view the times of all the users
+func trackedTimesOptions(ctx *context.APIContext) (*issues_model.FindTrackedTimesOptions, bool) {
+	repoIDs, err := issue_service.TrackedTimeRepoIDs(ctx, ctx.Org.Organization.AsUser(), ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "TrackedTimeRepoIDs", err)
+		return nil, false
+	}
+	isOwner, err := ctx.Org.Organization.IsOwnedBy(ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsOwnedBy", err)
+		return nil, false
+	}
+	return &issues_model.FindTrackedTimesOptions{RepositoryIDs: repoIDs}, ctx.Doer.IsAdmin || isOwner
+}
+
+// ListTrackedTimes lists the times tracked on the issues of the repositories of an organization
+func ListTrackedTimes(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/times organization orgTrackedTimes
+	// ---
+	// summary: List the times tracked on the issues of an organization's repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: user
+	//   in: query
+	//   description: optional filter by user (available for organization owners)
+	//   type: string
+	// - name: milestone
+	//   in: query
+	//   description: optional filter by milestone id
+	//   type: integer
+	//   format: int64
+	// - name: label
+	//   in: query
+	//   description: optional filter by label id
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: Only show times tracked after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only show times tracked before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TrackedTimeList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts, canViewAll := trackedTimesOptions(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !utils.SetTrackedTimesFilters(ctx, opts, canViewAll) {
+		return
+	}
+	opts.ListOptions = utils.GetListOptions(ctx)
+
+	count, err := issues_model.CountTrackedTimes(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	trackedTimes, err := issues_model.GetTrackedTimes(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTrackedTimes", err)
+		return
+	}
+	if err = trackedTimes.LoadAttributes(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
+}
+
+// GetTrackedTimeReport reports the times tracked on the issues of the repositories of an organization
+func GetTrackedTimeReport(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/times/report organization orgTrackedTimeReport
+	// ---
+	// summary: Report the times tracked on the issues of an organization's repositories grouped by user, repository, milestone, label or issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group
+	//   in: query
+	//   description: how the times are grouped, by user if not set
+	//   type: string
+	//   enum: [user, repo, milestone, label, issue]
+	// - name: user
+	//   in: query
+	//   description: optional filter by user (available for organization owners)
+	//   type: string
+	// - name: milestone
+	//   in: query
+	//   description: optional filter by milestone id
+	//   type: integer
+	//   format: int64
+	// - name: label
+	//   in: query
+	//   description: optional filter by label id
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: Only report times tracked after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only report times tracked before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TrackedTimeReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts, canViewAll := trackedTimesOptions(ctx)
+	if ctx.Written() {
+		return
+	}
+	utils.TrackedTimeReport(ctx, opts, canViewAll)
+}
@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
+	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
@@ -641,6 +642,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
 		return
 	}
+	notify_service.UpdateProtectedBranch(ctx, ctx.Doer, ctx.Repo.Repository, protectBranch, true)
 
 	if isBranchExist {
 		if err = pull_service.CheckPRsForBaseBranch(ctx, ctx.Repo.Repository, ruleName); err != nil {
@@ -906,6 +908,7 @@ func EditBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
 		return
 	}
+	notify_service.UpdateProtectedBranch(ctx, ctx.Doer, ctx.Repo.Repository, protectBranch, false)
 
 	isPlainRule := !git_model.IsRuleNameSpecial(bpName)
 	var isBranchExist bool
@@ -1007,6 +1010,7 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteProtectedBranch", err)
 		return
 	}
+	notify_service.DeleteProtectedBranch(ctx, ctx.Doer, ctx.Repo.Repository, bp)
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -0,0 +1,110 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// SearchCode searches the code of a repository
+func SearchCode(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/search/code repository repoSearchCode
+	// ---
+	// summary: Search the code of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: search query, supports the "path:" and "lang:" operators and /regexp/
+	//   type: string
+	//   required: true
+	// - name: mode
+	//   in: query
+	//   description: how the keyword is matched, defaults to fuzzy
+	//   type: string
+	//   enum: [fuzzy, match, regexp, symbol]
+	// - name: language
+	//   in: query
+	//   description: only return the files written in this language
+	//   type: string
+	// - name: ref
+	//   in: query
+	//   description: name of the indexed branch or tag to search, defaults to the default branch
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeSearchResults"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !setting.Indexer.RepoIndexerEnabled {
+		ctx.NotFound("code indexer is disabled")
+		return
+	}
+
+	keyword := ctx.FormTrim("q")
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "search query is empty")
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := code_indexer.ParseQuery(keyword).ToSearchOptions(ctx.FormTrim("language"), code_indexer.ParseSearchMode(ctx.FormTrim("mode")))
+	opts.RepoIDs = []int64{ctx.Repo.Repository.ID}
+	opts.Page = listOptions.Page
+	opts.PageSize = listOptions.PageSize
+
+	var err error
+	opts.Ref, err = code_indexer.ResolveRef(ctx, ctx.Repo.Repository, ctx.FormTrim("ref"))
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "ResolveRef", err)
+		return
+	}
+
+	total, results, languages, truncated, err := code_indexer.PerformSearch(ctx, opts)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "PerformSearch", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(total))
+	ctx.JSON(http.StatusOK, convert.ToCodeSearchResults(ctx.Repo.Repository, total, results, languages, truncated))
+}
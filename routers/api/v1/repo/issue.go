@@ -53,7 +53,7 @@ func SearchIssues(ctx *context.APIContext) {
 	//   type: string
 	// - name: q
 	//   in: query
-	//   description: search string
+	//   description: search string, `parent:owner/repo#index` or `parent:none` filters on the parent issue
 	//   type: string
 	// - name: priority_repo_id
 	//   in: query
@@ -203,6 +203,11 @@ func SearchIssues(ctx *context.APIContext) {
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
+	keyword, parentID, err := issues_model.ParseParentKeyword(ctx, keyword, nil)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ParseParentKeyword", err)
+		return
+	}
 
 	var isPull util.OptionalBool
 	switch ctx.FormString("type") {
@@ -266,6 +271,11 @@ func SearchIssues(ctx *context.APIContext) {
 		MilestoneIDs:        includedMilestones,
 		SortBy:              issue_indexer.SortByCreatedDesc,
 	}
+	if parentID > 0 {
+		searchOpt.ParentID = &parentID
+	} else if parentID == db.NoConditionID {
+		searchOpt.ParentID = new(int64)
+	}
 
 	if since != 0 {
 		searchOpt.UpdatedAfterUnix = &since
@@ -342,7 +352,7 @@ func ListIssues(ctx *context.APIContext) {
 	//   type: string
 	// - name: q
 	//   in: query
-	//   description: search string
+	//   description: search string, `parent:index`, `parent:owner/repo#index` or `parent:none` filters on the parent issue
 	//   type: string
 	// - name: type
 	//   in: query
@@ -410,6 +420,11 @@ func ListIssues(ctx *context.APIContext) {
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
+	keyword, parentID, err := issues_model.ParseParentKeyword(ctx, keyword, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ParseParentKeyword", err)
+		return
+	}
 
 	var labelIDs []int64
 	if splitted := strings.Split(ctx.FormString("labels"), ","); len(splitted) > 0 {
@@ -438,7 +453,7 @@ func ListIssues(ctx *context.APIContext) {
 			if err != nil {
 				continue
 			}
-			mile, err = issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, id)
+			mile, err = issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, id)
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -535,6 +550,11 @@ func ListIssues(ctx *context.APIContext) {
 	if mentionedByID > 0 {
 		searchOpt.MentionID = &mentionedByID
 	}
+	if parentID > 0 {
+		searchOpt.ParentID = &parentID
+	} else if parentID == db.NoConditionID {
+		searchOpt.ParentID = new(int64)
+	}
 
 	ids, total, err := issue_indexer.SearchIssues(ctx, searchOpt)
 	if err != nil {
@@ -832,6 +852,18 @@ func EditIssue(ctx *context.APIContext) {
 		issue.DeadlineUnix = deadlineUnix
 	}
 
+	// Update or remove the time estimate, only if set and allowed
+	if form.TimeEstimate != nil && canWrite && ctx.Repo.Repository.IsTimetrackerEnabled(ctx) {
+		if *form.TimeEstimate < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "TimeEstimate", "time estimate must not be negative")
+			return
+		}
+		if err := issues_model.UpdateIssueTimeEstimate(ctx, issue, *form.TimeEstimate, ctx.Doer); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueTimeEstimate", err)
+			return
+		}
+	}
+
 	// Add/delete assignees
 
 	// Deleting is done the GitHub way (quote from their api documentation):
@@ -0,0 +1,388 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListSubIssues list the sub-issues of an issue
+func ListSubIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueListSubIssues
+	// ---
+	// summary: List the sub-issues of an issue, the ones in repositories the user can't read are not listed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getSubIssuesParent(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	subIssues, err := issues_model.GetSubIssues(ctx, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssues", err)
+		return
+	}
+	if _, err := subIssues.LoadRepositories(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepositories", err)
+		return
+	}
+
+	readable := make(issues_model.IssueList, 0, len(subIssues))
+	readableRepos := make(map[int64]bool)
+	for _, subIssue := range subIssues {
+		canRead, ok := readableRepos[subIssue.RepoID]
+		if !ok {
+			perm := getPermissionForRepo(ctx, subIssue.Repo)
+			if ctx.Written() {
+				return
+			}
+			canRead = perm.CanReadIssuesOrPulls(false)
+			readableRepos[subIssue.RepoID] = canRead
+		}
+		if canRead {
+			readable = append(readable, subIssue)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, readable))
+}
+
+// GetSubIssueProgress get the progress of an issue computed from its sub-issues
+func GetSubIssueProgress(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/sub_issues/progress issue issueGetSubIssueProgress
+	// ---
+	// summary: Get the progress of an issue, the sub-issues of its hierarchy in the repositories the user can read are counted
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SubIssueProgress"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getSubIssuesParent(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	repoIDs, err := issues_model.GetSubIssueRepoIDs(ctx, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssueRepoIDs", err)
+		return
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+	// the progress only counts the sub-issues listed to the doer
+	readableRepoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		perm := getPermissionForRepo(ctx, repo)
+		if ctx.Written() {
+			return
+		}
+		if perm.CanReadIssuesOrPulls(false) {
+			readableRepoIDs = append(readableRepoIDs, repo.ID)
+		}
+	}
+
+	progress, err := issues_model.GetSubIssueProgress(ctx, issue.ID, readableRepoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssueProgress", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.SubIssueProgress{
+		Total:  progress.Total,
+		Closed: progress.Closed,
+	})
+}
+
+// AddSubIssue add a sub-issue to an issue
+func AddSubIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueAddSubIssue
+	// ---
+	// summary: Add a sub-issue to an issue, it can be an issue of any repository of the same owner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue := getSubIssuesParent(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(false) {
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "user should have permission to write issues")
+		return
+	}
+	subIssue := getSubIssueFromForm(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue_service.AddSubIssue(ctx, ctx.Doer, issue, subIssue); err != nil {
+		if issues_model.IsErrSubIssueExists(err) || issues_model.IsErrCircularSubIssue(err) || issues_model.IsErrSubIssueNotSameOwner(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "AddSubIssue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddSubIssue", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(ctx, subIssue))
+}
+
+// RemoveSubIssue remove a sub-issue from an issue
+func RemoveSubIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueRemoveSubIssue
+	// ---
+	// summary: Remove a sub-issue from an issue
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getSubIssuesParent(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(false) {
+		ctx.Error(http.StatusForbidden, "CanWriteIssuesOrPulls", "user should have permission to write issues")
+		return
+	}
+	subIssue := getSubIssueFromForm(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue_service.RemoveSubIssue(ctx, ctx.Doer, issue, subIssue); err != nil {
+		if issues_model.IsErrSubIssueNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveSubIssue", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetParentIssue get the parent of an issue
+func GetParentIssue(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/parent issue issueGetParentIssue
+	// ---
+	// summary: Get the parent of a sub-issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getSubIssuesParent(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	parent, err := issues_model.GetParentIssue(ctx, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetParentIssue", err)
+		return
+	} else if parent == nil {
+		ctx.NotFound()
+		return
+	}
+	if err := parent.LoadRepo(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+		return
+	}
+	perm := getPermissionForRepo(ctx, parent.Repo)
+	if ctx.Written() {
+		return
+	}
+	if !perm.CanReadIssuesOrPulls(false) {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, parent))
+}
+
+// getSubIssuesParent returns the issue of the path, pull requests have no sub-issues
+func getSubIssuesParent(ctx *context.APIContext) *issues_model.Issue {
+	issue := getParamsIssue(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	if issue.IsPull || !ctx.Repo.CanReadIssuesOrPulls(false) {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
+
+// getSubIssueFromForm returns the issue of the form, it must be readable by the doer
+func getSubIssueFromForm(ctx *context.APIContext) *issues_model.Issue {
+	form := web.GetForm(ctx).(*api.IssueMeta)
+
+	repo := ctx.Repo.Repository
+	if form.Owner != "" && form.Name != "" && (form.Owner != repo.OwnerName || form.Name != repo.Name) {
+		var err error
+		repo, err = repo_model.GetRepositoryByOwnerAndName(ctx, form.Owner, form.Name)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				ctx.NotFound("IsErrRepoNotExist", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return nil
+		}
+	}
+
+	issue, err := issues_model.GetIssueByIndex(ctx, repo.ID, form.Index)
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound("IsErrIssueNotExist", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	issue.Repo = repo
+
+	perm := getPermissionForRepo(ctx, repo)
+	if ctx.Written() {
+		return nil
+	}
+	if issue.IsPull || !perm.CanReadIssuesOrPulls(false) {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
@@ -631,3 +631,71 @@ func ListMyTrackedTimes(ctx *context.APIContext) {
 	ctx.SetTotalCountHeader(count)
 	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
 }
+
+// GetTrackedTimeReportByRepository reports the times tracked on the issues of a repository
+func GetTrackedTimeReportByRepository(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/times/report repository repoTrackedTimeReport
+	// ---
+	// summary: Report a repo's tracked times grouped by user, repository, milestone, label or issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: group
+	//   in: query
+	//   description: how the times are grouped, by user if not set
+	//   type: string
+	//   enum: [user, repo, milestone, label, issue]
+	// - name: user
+	//   in: query
+	//   description: optional filter by user (available for issue managers)
+	//   type: string
+	// - name: milestone
+	//   in: query
+	//   description: optional filter by milestone id
+	//   type: integer
+	//   format: int64
+	// - name: label
+	//   in: query
+	//   description: optional filter by label id
+	//   type: integer
+	//   format: int64
+	// - name: since
+	//   in: query
+	//   description: Only report times tracked after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only report times tracked before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TrackedTimeReport"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !ctx.Repo.Repository.IsTimetrackerEnabled(ctx) {
+		ctx.Error(http.StatusBadRequest, "", "time tracking disabled")
+		return
+	}
+
+	canViewAll := ctx.Doer.IsAdmin || ctx.IsUserRepoWriter([]unit.Type{unit.TypeIssues})
+	utils.TrackedTimeReport(ctx, &issues_model.FindTrackedTimesOptions{RepositoryID: ctx.Repo.Repository.ID}, canViewAll)
+}
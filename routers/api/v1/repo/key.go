@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/convert"
+	notify_service "code.gitea.io/gitea/services/notify"
 )
 
 // appendPrivateInformation appends the owner and key type information to api.PublicKey
@@ -249,6 +250,7 @@ func CreateDeployKey(ctx *context.APIContext) {
 		HandleAddKeyError(ctx, err)
 		return
 	}
+	notify_service.AddDeployKey(ctx, ctx.Doer, ctx.Repo.Repository, key)
 
 	key.Content = content
 	apiLink := composeDeployKeysAPILink(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
@@ -140,6 +141,13 @@ func Migrate(ctx *context.APIContext) {
 		}
 	}
 
+	if form.SyncInterval != "" && !form.Mirror {
+		if interval, err := time.ParseDuration(form.SyncInterval); err != nil || interval < setting.Mirror.MinInterval {
+			ctx.Error(http.StatusUnprocessableEntity, "SyncInterval", fmt.Errorf("invalid sync interval %q, the minimum interval is %v", form.SyncInterval, setting.Mirror.MinInterval))
+			return
+		}
+	}
+
 	opts := migrations.MigrateOptions{
 		CloneAddr:      remoteAddr,
 		RepoName:       form.RepoName,
@@ -160,8 +168,10 @@ func Migrate(ctx *context.APIContext) {
 		Releases:       form.Releases,
 		GitServiceType: gitServiceType,
 		MirrorInterval: form.MirrorInterval,
+		SyncInterval:   form.SyncInterval,
 	}
 	if opts.Mirror {
+		opts.SyncInterval = ""
 		opts.Issues = false
 		opts.Milestones = false
 		opts.Labels = false
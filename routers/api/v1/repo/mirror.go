@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models"
@@ -338,6 +339,13 @@ func CreatePushMirror(ctx *context.APIContext, mirrorOption *api.CreatePushMirro
 		return
 	}
 
+	refIncludes := strings.Join(mirrorOption.RefIncludes, "\n")
+	refExcludes := strings.Join(mirrorOption.RefExcludes, "\n")
+	if err := repo_model.ValidateRefPatterns(refIncludes + "\n" + refExcludes); err != nil {
+		ctx.Error(http.StatusBadRequest, "CreatePushMirror", err)
+		return
+	}
+
 	address, err := forms.ParseRemoteAddr(mirrorOption.RemoteAddress, mirrorOption.RemoteUsername, mirrorOption.RemotePassword)
 	if err == nil {
 		err = migrations.IsMigrateURLAllowed(address, ctx.ContextUser)
@@ -360,12 +368,15 @@ func CreatePushMirror(ctx *context.APIContext, mirrorOption *api.CreatePushMirro
 	}
 
 	pushMirror := &repo_model.PushMirror{
-		RepoID:        repo.ID,
-		Repo:          repo,
-		RemoteName:    fmt.Sprintf("remote_mirror_%s", remoteSuffix),
-		Interval:      interval,
-		SyncOnCommit:  mirrorOption.SyncOnCommit,
-		RemoteAddress: remoteAddress,
+		RepoID:         repo.ID,
+		Repo:           repo,
+		RemoteName:     fmt.Sprintf("remote_mirror_%s", remoteSuffix),
+		Interval:       interval,
+		SyncOnCommit:   mirrorOption.SyncOnCommit,
+		RemoteAddress:  remoteAddress,
+		RefIncludes:    refIncludes,
+		RefExcludes:    refExcludes,
+		NonDestructive: mirrorOption.NonDestructive,
 	}
 
 	if err = repo_model.InsertPushMirror(ctx, pushMirror); err != nil {
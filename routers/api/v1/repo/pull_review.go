@@ -4,14 +4,17 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"code.gitea.io/gitea/models"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
@@ -20,6 +23,7 @@ import (
 	"code.gitea.io/gitea/services/convert"
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
 )
 
 // ListPullReviews lists all reviews of a pull request
@@ -348,22 +352,26 @@ func CreatePullReview(ctx *context.APIContext) {
 
 	// create review comments
 	for _, c := range opts.Comments {
-		line := c.NewLineNum
+		startLine, line := c.NewStartLineNum, c.NewLineNum
 		if c.OldLineNum > 0 {
-			line = c.OldLineNum * -1
+			startLine, line = c.OldStartLineNum*-1, c.OldLineNum*-1
 		}
 
 		if _, err := pull_service.CreateCodeComment(ctx,
 			ctx.Doer,
 			ctx.Repo.GitRepo,
 			pr.Issue,
+			startLine,
 			line,
 			c.Body,
 			c.Path,
 			true, // pending review
 			0,    // no reply
 			opts.CommitID,
-		); err != nil {
+		); errors.Is(err, pull_service.ErrInvalidCommentLineRange) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateCodeComment", err)
+			return
+		} else if err != nil {
 			ctx.Error(http.StatusInternalServerError, "CreateCodeComment", err)
 			return
 		}
@@ -385,6 +393,107 @@ func CreatePullReview(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiReview)
 }
 
+// ApplyPullReviewSuggestions commits the suggested changes of review comments to the head branch of a pull request
+func ApplyPullReviewSuggestions(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/suggestions repository repoApplyPullReviewSuggestions
+	// ---
+	// summary: Commit the suggested changes of review comments to the head branch of a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyPullReviewSuggestionsOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FilesResponse"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.ApplyPullReviewSuggestionsOptions)
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	allowed, err := pull_service.IsUserAllowedToApplySuggestions(ctx, pr, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToApplySuggestions", err)
+		return
+	}
+	if !allowed {
+		ctx.Error(http.StatusForbidden, "ApplyPullReviewSuggestions", "user is not allowed to push to the head branch")
+		return
+	}
+
+	commentIDs := container.SetOf(opts.CommentIDs...).Values()
+	if len(commentIDs) == 0 {
+		ctx.Error(http.StatusBadRequest, "ApplyPullReviewSuggestions", "no code comment is given")
+		return
+	}
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IDs:     commentIDs,
+		IssueID: pr.IssueID,
+		Type:    issues_model.CommentTypeCode,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindComments", err)
+		return
+	}
+	if len(comments) != len(commentIDs) {
+		ctx.NotFound("FindComments")
+		return
+	}
+
+	filesResponse, err := files_service.ApplySuggestions(ctx, ctx.Doer, pr, comments, opts.Message)
+	if err != nil {
+		if files_service.IsErrSuggestionNotApplicable(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ApplySuggestions", err)
+		} else if models.IsErrCommitIDDoesNotMatch(err) || models.IsErrSHADoesNotMatch(err) || git.IsErrPushOutOfDate(err) {
+			ctx.Error(http.StatusConflict, "ApplySuggestions", err)
+		} else if git.IsErrPushRejected(err) || models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) {
+			ctx.Error(http.StatusForbidden, "ApplySuggestions", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ApplySuggestions", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, filesResponse)
+}
+
 // SubmitPullReview submit a pending review to an pull request
 func SubmitPullReview(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repository repoSubmitPullReview
@@ -32,6 +32,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
 	"code.gitea.io/gitea/services/issue"
+	notify_service "code.gitea.io/gitea/services/notify"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
@@ -742,6 +743,9 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		ctx.Error(http.StatusInternalServerError, "UpdateRepository", err)
 		return err
 	}
+	if visibilityChanged {
+		notify_service.ChangeRepositoryVisibility(ctx, ctx.Doer, repo)
+	}
 
 	log.Trace("Repository basic settings updated: %s/%s", owner.Name, repo.Name)
 	return nil
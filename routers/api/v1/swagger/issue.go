@@ -28,6 +28,13 @@ type swaggerResponseComment struct {
 	Body api.Comment `json:"body"`
 }
 
+// SubIssueProgress
+// swagger:response SubIssueProgress
+type swaggerResponseSubIssueProgress struct {
+	// in:body
+	Body api.SubIssueProgress `json:"body"`
+}
+
 // CommentList
 // swagger:response CommentList
 type swaggerResponseCommentList struct {
@@ -70,6 +77,55 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// Project
+// swagger:response Project
+type swaggerResponseProject struct {
+	// in:body
+	Body api.Project `json:"body"`
+}
+
+// ProjectList
+// swagger:response ProjectList
+type swaggerResponseProjectList struct {
+	// in:body
+	Body []api.Project `json:"body"`
+}
+
+// ProjectField
+// swagger:response ProjectField
+type swaggerResponseProjectField struct {
+	// in:body
+	Body api.ProjectField `json:"body"`
+}
+
+// ProjectFieldList
+// swagger:response ProjectFieldList
+type swaggerResponseProjectFieldList struct {
+	// in:body
+	Body []api.ProjectField `json:"body"`
+}
+
+// ProjectFieldSelectOption
+// swagger:response ProjectFieldSelectOption
+type swaggerResponseProjectFieldSelectOption struct {
+	// in:body
+	Body api.ProjectFieldSelectOption `json:"body"`
+}
+
+// ProjectFieldValue
+// swagger:response ProjectFieldValue
+type swaggerResponseProjectFieldValue struct {
+	// in:body
+	Body api.ProjectFieldValue `json:"body"`
+}
+
+// ProjectItemList
+// swagger:response ProjectItemList
+type swaggerResponseProjectItemList struct {
+	// in:body
+	Body []api.ProjectItem `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
@@ -84,6 +140,13 @@ type swaggerResponseTrackedTimeList struct {
 	Body []api.TrackedTime `json:"body"`
 }
 
+// TrackedTimeReport
+// swagger:response TrackedTimeReport
+type swaggerResponseTrackedTimeReport struct {
+	// in:body
+	Body api.TrackedTimeReport `json:"body"`
+}
+
 // IssueDeadline
 // swagger:response IssueDeadline
 type swaggerIssueDeadline struct {
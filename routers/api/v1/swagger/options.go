@@ -68,6 +68,17 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditMilestoneOption api.EditMilestoneOption
 
+	// in:body
+	CreateProjectFieldOption api.CreateProjectFieldOption
+	// in:body
+	EditProjectFieldOption api.EditProjectFieldOption
+	// in:body
+	CreateProjectFieldSelectOption api.CreateProjectFieldSelectOption
+	// in:body
+	EditProjectFieldSelectOption api.EditProjectFieldSelectOption
+	// in:body
+	SetProjectFieldValueOption api.SetProjectFieldValueOption
+
 	// in:body
 	CreateOrgOption api.CreateOrgOption
 	// in:body
@@ -161,6 +172,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	DismissPullReviewOptions api.DismissPullReviewOptions
 
+	// in:body
+	ApplyPullReviewSuggestionsOptions api.ApplyPullReviewSuggestionsOptions
+
 	// in:body
 	MigrateRepoOptions api.MigrateRepoOptions
 
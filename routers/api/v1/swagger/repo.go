@@ -345,6 +345,13 @@ type swaggerLanguageStatistics struct {
 	Body map[string]int64 `json:"body"`
 }
 
+// CodeSearchResults
+// swagger:response CodeSearchResults
+type swaggerCodeSearchResults struct {
+	// in: body
+	Body api.CodeSearchResults `json:"body"`
+}
+
 // CombinedStatus
 // swagger:response CombinedStatus
 type swaggerCombinedStatus struct {
@@ -199,6 +199,13 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 				Wiki:                     util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true),
 				Repository:               util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true),
 				Release:                  util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true),
+				WorkflowRun:              util.SliceContainsString(form.Events, string(webhook_module.HookEventWorkflowRun), true),
+				WorkflowJob:              util.SliceContainsString(form.Events, string(webhook_module.HookEventWorkflowJob), true),
+				BranchProtection:         util.SliceContainsString(form.Events, string(webhook_module.HookEventBranchProtection), true),
+				Member:                   util.SliceContainsString(form.Events, string(webhook_module.HookEventMember), true),
+				Membership:               util.SliceContainsString(form.Events, string(webhook_module.HookEventMembership), true),
+				DeployKey:                util.SliceContainsString(form.Events, string(webhook_module.HookEventDeployKey), true),
+				RepositoryVisibility:     util.SliceContainsString(form.Events, string(webhook_module.HookEventRepositoryVisibility), true),
 			},
 			BranchFilter: form.BranchFilter,
 		},
@@ -235,6 +242,13 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 		}
 		w.Meta = string(meta)
 	}
+	if w.Type == webhook_module.CUSTOM {
+		meta, ok := customHookMeta(ctx, &webhook_service.CustomMeta{}, form.Config, w)
+		if !ok {
+			return nil, false
+		}
+		w.Meta = meta
+	}
 
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
@@ -246,6 +260,30 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 	return w, true
 }
 
+// customHookMeta updates the templates of a custom webhook with the given config options and checks them against
+// the events of the webhook. If they are invalid, write to `ctx` accordingly. Return (meta, ok)
+func customHookMeta(ctx *context.APIContext, meta *webhook_service.CustomMeta, config map[string]string, w *webhook.Webhook) (string, bool) {
+	if tmpl, ok := config["content_type_template"]; ok {
+		meta.ContentType = tmpl
+	}
+	if tmpl, ok := config["headers_template"]; ok {
+		meta.Headers = tmpl
+	}
+	if tmpl, ok := config["body_template"]; ok {
+		meta.Body = tmpl
+	}
+	if err := webhook_service.ValidateCustomMeta(meta, w); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("Invalid template: %v", err))
+		return "", false
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "custom: JSON marshal failed", err)
+		return "", false
+	}
+	return string(data), true
+}
+
 // EditSystemHook edit system webhook `w` according to `form`. Writes to `ctx` accordingly
 func EditSystemHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
 	hook, err := webhook.GetSystemOrDefaultWebhook(ctx, hookID)
@@ -358,6 +396,13 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.Repository = util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true)
 	w.Wiki = util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true)
 	w.Release = util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true)
+	w.WorkflowRun = util.SliceContainsString(form.Events, string(webhook_module.HookEventWorkflowRun), true)
+	w.WorkflowJob = util.SliceContainsString(form.Events, string(webhook_module.HookEventWorkflowJob), true)
+	w.BranchProtection = util.SliceContainsString(form.Events, string(webhook_module.HookEventBranchProtection), true)
+	w.Member = util.SliceContainsString(form.Events, string(webhook_module.HookEventMember), true)
+	w.Membership = util.SliceContainsString(form.Events, string(webhook_module.HookEventMembership), true)
+	w.DeployKey = util.SliceContainsString(form.Events, string(webhook_module.HookEventDeployKey), true)
+	w.RepositoryVisibility = util.SliceContainsString(form.Events, string(webhook_module.HookEventRepositoryVisibility), true)
 	w.BranchFilter = form.BranchFilter
 
 	err := w.SetHeaderAuthorization(form.AuthorizationHeader)
@@ -383,12 +428,24 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.PullRequestReviewRequest = pullHook(form.Events, string(webhook_module.HookEventPullRequestReviewRequest))
 	w.PullRequestSync = pullHook(form.Events, string(webhook_module.HookEventPullRequestSync))
 
+	if w.Type == webhook_module.CUSTOM {
+		meta, ok := customHookMeta(ctx, webhook_service.GetCustomHook(w), form.Config, w)
+		if !ok {
+			return false
+		}
+		w.Meta = meta
+	}
+
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
 		return false
 	}
 
 	if form.Active != nil {
+		if *form.Active && !w.IsActive {
+			// a webhook activated again starts over counting its failed deliveries
+			w.FailureCount = 0
+		}
 		w.IsActive = *form.Active
 	}
 
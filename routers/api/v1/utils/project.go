@@ -0,0 +1,410 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListProjects lists the projects of a repository, or of a user or an organization when repoID is 0
+func ListProjects(ctx *context.APIContext, ownerID, repoID int64, projectType project_model.Type) {
+	isClosed := util.OptionalBoolFalse
+	switch ctx.FormString("state") {
+	case "closed":
+		isClosed = util.OptionalBoolTrue
+	case "all":
+		isClosed = util.OptionalBoolNone
+	}
+
+	opts := project_model.SearchOptions{
+		RepoID:   repoID,
+		Page:     ctx.FormInt("page"),
+		IsClosed: isClosed,
+		Type:     projectType,
+		OrderBy:  project_model.GetSearchOrderByBySortType(""),
+	}
+	if repoID == 0 {
+		opts.OwnerID = ownerID
+	}
+	projects, total, err := project_model.FindProjects(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindProjects", err)
+		return
+	}
+
+	apiProjects := make([]*api.Project, 0, len(projects))
+	for _, p := range projects {
+		apiProjects = append(apiProjects, convert.ToAPIProject(ctx, p))
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, &apiProjects)
+}
+
+// GetProject returns the project of the request, it must belong to the repository, or to the user or the
+// organization when repoID is 0
+func GetProject(ctx *context.APIContext, ownerID, repoID int64) *project_model.Project {
+	project, err := project_model.GetProjectByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if project_model.IsErrProjectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectByID", err)
+		}
+		return nil
+	}
+	if (repoID > 0 && project.RepoID != repoID) || (repoID == 0 && (project.RepoID != 0 || project.OwnerID != ownerID)) {
+		ctx.NotFound()
+		return nil
+	}
+	return project
+}
+
+// ListProjectFields lists the custom fields of a project
+func ListProjectFields(ctx *context.APIContext, project *project_model.Project) {
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFields", err)
+		return
+	}
+
+	apiFields := make([]*api.ProjectField, 0, len(fields))
+	for _, field := range fields {
+		apiFields = append(apiFields, convert.ToAPIProjectField(field))
+	}
+	ctx.JSON(http.StatusOK, &apiFields)
+}
+
+// CreateProjectField creates a custom field of a project
+func CreateProjectField(ctx *context.APIContext, project *project_model.Project) {
+	form := web.GetForm(ctx).(*api.CreateProjectFieldOption)
+
+	fieldType, ok := project_model.ParseFieldType(form.Type)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid field type")
+		return
+	}
+	field := &project_model.Field{
+		ProjectID: project.ID,
+		Name:      strings.TrimSpace(form.Name),
+		Type:      fieldType,
+		Sorting:   form.Sorting,
+	}
+	for _, opt := range form.Options {
+		option, err := toFieldOption(opt.Name, opt.Color, opt.Sorting, opt.StartDate, opt.EndDate)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		field.Options = append(field.Options, option)
+	}
+
+	if err := project_model.NewField(ctx, field); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewField", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIProjectField(field))
+}
+
+// EditProjectField edits a custom field of a project
+func EditProjectField(ctx *context.APIContext, project *project_model.Project) {
+	form := web.GetForm(ctx).(*api.EditProjectFieldOption)
+	field := getProjectField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		field.Name = strings.TrimSpace(*form.Name)
+	}
+	if form.Sorting != nil {
+		field.Sorting = *form.Sorting
+	}
+	if err := project_model.UpdateField(ctx, field); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateField", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProjectField(field))
+}
+
+// DeleteProjectField deletes a custom field of a project with its values
+func DeleteProjectField(ctx *context.APIContext, project *project_model.Project) {
+	field := getProjectField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if err := project_model.DeleteField(ctx, field); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteField", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateProjectFieldSelectOption adds an option to a select or an iteration field of a project
+func CreateProjectFieldSelectOption(ctx *context.APIContext, project *project_model.Project) {
+	form := web.GetForm(ctx).(*api.CreateProjectFieldSelectOption)
+	field := getProjectField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	option, err := toFieldOption(form.Name, form.Color, form.Sorting, form.StartDate, form.EndDate)
+	if err == nil {
+		err = project_model.NewFieldOption(ctx, field, option)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewFieldOption", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIProjectFieldSelectOption(option))
+}
+
+// EditProjectFieldSelectOption edits an option of a select or an iteration field of a project
+func EditProjectFieldSelectOption(ctx *context.APIContext, project *project_model.Project) {
+	form := web.GetForm(ctx).(*api.EditProjectFieldSelectOption)
+	field, option := getProjectFieldOption(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		option.Name = strings.TrimSpace(*form.Name)
+	}
+	if form.Color != nil {
+		option.Color = *form.Color
+	}
+	if form.Sorting != nil {
+		option.Sorting = *form.Sorting
+	}
+	var err error
+	if form.StartDate != nil {
+		option.StartUnix, err = project_model.ParseFieldDate(*form.StartDate)
+	}
+	if err == nil && form.EndDate != nil {
+		option.EndUnix, err = project_model.ParseFieldDate(*form.EndDate)
+	}
+	if err == nil {
+		err = project_model.UpdateFieldOption(ctx, field, option)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateFieldOption", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIProjectFieldSelectOption(option))
+}
+
+// DeleteProjectFieldSelectOption deletes an option of a select or an iteration field of a project
+func DeleteProjectFieldSelectOption(ctx *context.APIContext, project *project_model.Project) {
+	_, option := getProjectFieldOption(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	if err := project_model.DeleteFieldOption(ctx, option); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteFieldOption", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListProjectItems lists the issues of a project the doer can read with the values of their fields
+func ListProjectItems(ctx *context.APIContext, project *project_model.Project) {
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFields", err)
+		return
+	}
+	values, err := project_model.GetFieldValues(ctx, project.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFieldValues", err)
+		return
+	}
+
+	opts := &issues_model.IssuesOptions{
+		ProjectID: project.ID,
+		SortType:  "project-column-sorting",
+	}
+	if project.RepoID > 0 {
+		opts.RepoIDs = []int64{project.RepoID}
+	}
+	issues, err := issues_model.Issues(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Issues", err)
+		return
+	}
+
+	filterField := fields.GetField(ctx.FormInt64("filter_field"))
+	filterValue := ctx.FormTrim("filter_value")
+	perms := make(map[int64]access_model.Permission)
+	issueIDs := make([]int64, 0, len(issues))
+	issuesByID := make(map[int64]*issues_model.Issue, len(issues))
+	for _, issue := range issues {
+		readable := CanReadProjectIssue(ctx, perms, issue)
+		if ctx.Written() {
+			return
+		}
+		if !readable {
+			continue
+		}
+		if filterField != nil && !filterField.MatchValue(values.Get(issue.ID, filterField.ID), filterValue) {
+			continue
+		}
+		issueIDs = append(issueIDs, issue.ID)
+		issuesByID[issue.ID] = issue
+	}
+	if sortField := fields.GetField(ctx.FormInt64("sort_field")); sortField != nil {
+		project_model.SortIssueIDsByField(issueIDs, sortField, values, ctx.FormString("sort_order") == "desc")
+	}
+
+	listOptions := GetListOptions(ctx)
+	start, end := listOptions.GetStartEnd()
+	total := len(issueIDs)
+	start, end = min(start, total), min(end, total)
+
+	items := make([]*api.ProjectItem, 0, end-start)
+	for _, issueID := range issueIDs[start:end] {
+		items = append(items, convert.ToAPIProjectItem(ctx, issuesByID[issueID], fields, values))
+	}
+
+	ctx.SetTotalCountHeader(int64(total))
+	ctx.JSON(http.StatusOK, &items)
+}
+
+// CanReadProjectIssue checks if the doer can read an issue of a project, the permissions of the repositories
+// are kept in perms for the next issues
+func CanReadProjectIssue(ctx *context.APIContext, perms map[int64]access_model.Permission, issue *issues_model.Issue) bool {
+	perm, ok := perms[issue.RepoID]
+	if !ok {
+		if ctx.Repo.Repository != nil && ctx.Repo.Repository.ID == issue.RepoID {
+			perm = ctx.Repo.Permission
+		} else {
+			if err := issue.LoadRepo(ctx); err != nil {
+				ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+				return false
+			}
+			var err error
+			if perm, err = access_model.GetUserRepoPermission(ctx, issue.Repo, ctx.Doer); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return false
+			}
+		}
+		perms[issue.RepoID] = perm
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull)
+}
+
+// SetProjectItemFieldValue sets the value of a custom field of a project for one of its issues, an empty value
+// removes it
+func SetProjectItemFieldValue(ctx *context.APIContext, project *project_model.Project, issue *issues_model.Issue, raw string) {
+	field := getProjectField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	readable := CanReadProjectIssue(ctx, make(map[int64]access_model.Permission), issue)
+	if ctx.Written() {
+		return
+	}
+	if !readable {
+		ctx.NotFound()
+		return
+	}
+
+	if err := project_model.SetFieldValue(ctx, field, issue.ID, raw); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetFieldValue", err)
+		}
+		return
+	}
+	if strings.TrimSpace(raw) == "" {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	values, err := project_model.GetFieldValues(ctx, field.ProjectID, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFieldValues", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIProjectFieldValue(field, values.Get(issue.ID, field.ID)))
+}
+
+func getProjectField(ctx *context.APIContext, project *project_model.Project) *project_model.Field {
+	field, err := project_model.GetFieldByID(ctx, project.ID, ctx.ParamsInt64(":field_id"))
+	if err != nil {
+		if project_model.IsErrProjectFieldNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetFieldByID", err)
+		}
+		return nil
+	}
+	return field
+}
+
+func getProjectFieldOption(ctx *context.APIContext, project *project_model.Project) (*project_model.Field, *project_model.FieldOption) {
+	field := getProjectField(ctx, project)
+	if ctx.Written() {
+		return nil, nil
+	}
+	option := field.Option(ctx.ParamsInt64(":option_id"))
+	if option == nil {
+		ctx.NotFound()
+		return nil, nil
+	}
+	return field, option
+}
+
+func toFieldOption(name, color string, sorting int64, startDate, endDate string) (*project_model.FieldOption, error) {
+	option := &project_model.FieldOption{
+		Name:    strings.TrimSpace(name),
+		Color:   color,
+		Sorting: sorting,
+	}
+	var err error
+	if startDate != "" {
+		if option.StartUnix, err = project_model.ParseFieldDate(startDate); err != nil {
+			return nil, err
+		}
+	}
+	if endDate != "" {
+		if option.EndUnix, err = project_model.ParseFieldDate(endDate); err != nil {
+			return nil, err
+		}
+	}
+	return option, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// SetTrackedTimesFilters sets the user, the period, the milestone and the label filters of tracked times from the
+// query. A doer who can't view the times of all the users only gets their own times. It writes an error and
+// returns false if the filters are invalid.
+func SetTrackedTimesFilters(ctx *context.APIContext, opts *issues_model.FindTrackedTimesOptions, canViewAll bool) bool {
+	if name := ctx.FormTrim("user"); name != "" {
+		user, err := user_model.GetUserByName(ctx, name)
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusNotFound, "User does not exist", err)
+			return false
+		} else if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			return false
+		}
+		opts.UserID = user.ID
+	}
+	if !canViewAll {
+		if opts.UserID != 0 && opts.UserID != ctx.Doer.ID {
+			ctx.Error(http.StatusForbidden, "", fmt.Errorf("query by user not allowed; not enough rights"))
+			return false
+		}
+		opts.UserID = ctx.Doer.ID
+	}
+
+	var err error
+	if opts.CreatedBeforeUnix, opts.CreatedAfterUnix, err = context.GetQueryBeforeSince(ctx.Base); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return false
+	}
+	opts.MilestoneID = ctx.FormInt64("milestone")
+	opts.LabelID = ctx.FormInt64("label")
+	return true
+}
+
+// TrackedTimeReport responds with the report of the tracked times matching the options and the query, grouped
+// by the group of the query
+func TrackedTimeReport(ctx *context.APIContext, opts *issues_model.FindTrackedTimesOptions, canViewAll bool) {
+	group := issues_model.TrackedTimeReportGroupUser
+	if g := ctx.FormString("group"); g != "" {
+		group = issues_model.TrackedTimeReportGroup(g)
+		if !group.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid group %q", g))
+			return
+		}
+	}
+	if !SetTrackedTimesFilters(ctx, opts, canViewAll) {
+		return
+	}
+
+	report, err := issues_model.GetTrackedTimeReport(ctx, opts, group)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTrackedTimeReport", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTrackedTimeReport(report))
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	myCtx "code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/services/migrations"
+)
+
+// MigrateInstance migrates organizations and users from another instance and responds with the report
+func MigrateInstance(ctx *myCtx.PrivateContext) {
+	var params private.MigrateInstanceParams
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&params); err != nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
+	report, err := migrations.MigrateInstance(ctx, migrations.InstanceMigrationOptions{
+		BaseURL:   params.BaseURL,
+		AuthToken: params.AuthToken,
+		Owners:    params.Owners,
+		Units:     params.Units,
+		StateFile: params.StateFile,
+		DryRun:    params.DryRun,
+	})
+	if err != nil {
+		resp := private.Response{Err: err.Error()}
+		if report != nil {
+			resp.UserMsg = report.String()
+		}
+		ctx.JSON(http.StatusInternalServerError, resp)
+		return
+	}
+	if n := report.NumFailed(); n > 0 {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err:     fmt.Sprintf("the migration of %d items failed, run it again with the same state file to retry them", n),
+			UserMsg: report.String(),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, report.String())
+}
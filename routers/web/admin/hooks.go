@@ -6,16 +6,20 @@ package admin
 import (
 	"net/http"
 
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
+	webhook_service "code.gitea.io/gitea/services/webhook"
 )
 
 const (
 	// tplAdminHooks template path to render hook settings
 	tplAdminHooks base.TplName = "admin/hooks"
+	// tplAdminHookDeliveries template path to render the failing webhooks and the pending retries
+	tplAdminHookDeliveries base.TplName = "admin/hook_deliveries"
 )
 
 // DefaultOrSystemWebhooks renders both admin default and system webhook list pages
@@ -69,3 +73,84 @@ func DeleteDefaultOrSystemWebhook(ctx *context.Context) {
 
 	ctx.JSONRedirect(setting.AppSubURL + "/admin/hooks")
 }
+
+// hookDelivery is a webhook of the deliveries page with the name of its repository or owner and its settings link
+type hookDelivery struct {
+	Webhook *webhook.Webhook
+	Task    *webhook.HookTask
+	Name    string
+	Link    string
+}
+
+// WebhookDeliveries renders the webhooks whose last deliveries failed and the retries of failed deliveries
+func WebhookDeliveries(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.hooks.deliveries")
+	ctx.Data["PageIsAdminHookDeliveries"] = true
+
+	links := make(map[int64]*hookDelivery)
+	delivery := func(w *webhook.Webhook, t *webhook.HookTask) (*hookDelivery, error) {
+		if d, ok := links[w.ID]; ok {
+			return &hookDelivery{Webhook: w, Task: t, Name: d.Name, Link: d.Link}, nil
+		}
+		name, link, err := webhook_service.SettingsLink(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		d := &hookDelivery{Webhook: w, Task: t, Name: name, Link: setting.AppSubURL + "/" + link}
+		links[w.ID] = d
+		return d, nil
+	}
+
+	failing, err := webhook.GetFailingWebhooks(ctx)
+	if err != nil {
+		ctx.ServerError("GetFailingWebhooks", err)
+		return
+	}
+	failingHooks := make([]*hookDelivery, 0, len(failing))
+	for _, w := range failing {
+		d, err := delivery(w, nil)
+		if err != nil {
+			ctx.ServerError("SettingsLink", err)
+			return
+		}
+		failingHooks = append(failingHooks, d)
+	}
+	ctx.Data["FailingHooks"] = failingHooks
+
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	tasks, total, err := webhook.FindPendingRetryHookTasks(ctx, db.ListOptions{Page: page, PageSize: setting.UI.Admin.NoticePagingNum})
+	if err != nil {
+		ctx.ServerError("FindPendingRetryHookTasks", err)
+		return
+	}
+	hookIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		hookIDs = append(hookIDs, t.HookID)
+	}
+	hooks, err := webhook.GetWebhooksByIDs(ctx, hookIDs)
+	if err != nil {
+		ctx.ServerError("GetWebhooksByIDs", err)
+		return
+	}
+	retries := make([]*hookDelivery, 0, len(tasks))
+	for _, t := range tasks {
+		w, ok := hooks[t.HookID]
+		if !ok {
+			continue
+		}
+		d, err := delivery(w, t)
+		if err != nil {
+			ctx.ServerError("SettingsLink", err)
+			return
+		}
+		retries = append(retries, d)
+	}
+	ctx.Data["Retries"] = retries
+	ctx.Data["Total"] = total
+	ctx.Data["Page"] = context.NewPagination(int(total), setting.UI.Admin.NoticePagingNum, page, 5)
+
+	ctx.HTML(http.StatusOK, tplAdminHookDeliveries)
+}
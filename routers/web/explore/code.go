@@ -4,6 +4,7 @@
 package explore
 
 import (
+	"errors"
 	"net/http"
 
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -11,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 const (
@@ -27,6 +29,7 @@ func Code(ctx *context.Context) {
 
 	ctx.Data["UsersIsDisabled"] = setting.Service.Explore.DisableUsersPage
 	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["IsCommitIndexerEnabled"] = setting.Indexer.CommitIndexerEnabled
 	ctx.Data["Title"] = ctx.Tr("explore")
 	ctx.Data["PageIsExplore"] = true
 	ctx.Data["PageIsExploreCode"] = true
@@ -35,10 +38,11 @@ func Code(ctx *context.Context) {
 	keyword := ctx.FormTrim("q")
 
 	queryType := ctx.FormTrim("t")
-	isMatch := queryType == "match"
+	query := code_indexer.ParseQuery(keyword)
+	opts := query.ToSearchOptions(language, code_indexer.ParseSearchMode(queryType))
 
 	ctx.Data["Keyword"] = keyword
-	ctx.Data["Language"] = language
+	ctx.Data["Language"] = opts.Language
 	ctx.Data["queryType"] = queryType
 	ctx.Data["PageIsViewCode"] = true
 
@@ -69,16 +73,34 @@ func Code(ctx *context.Context) {
 			return
 		}
 	}
+	if len(query.Repos) > 0 {
+		repoIDs, err = code_indexer.FilterRepoIDs(ctx, query.Repos, "", repoIDs, isAdmin)
+		if err != nil {
+			ctx.ServerError("FilterRepoIDs", err)
+			return
+		}
+		// none of the repositories can be searched, don't fall back to all of them
+		isAdmin = isAdmin && len(repoIDs) > 0
+	}
 
 	var (
 		total                 int
 		searchResults         []*code_indexer.Result
 		searchResultLanguages []*code_indexer.SearchResultLanguages
+		truncated             bool
 	)
 
 	if (len(repoIDs) > 0) || isAdmin {
-		total, searchResults, searchResultLanguages, err = code_indexer.PerformSearch(ctx, repoIDs, language, keyword, page, setting.UI.RepoSearchPagingNum, isMatch)
+		opts.RepoIDs = repoIDs
+		opts.Page = page
+		opts.PageSize = setting.UI.RepoSearchPagingNum
+		total, searchResults, searchResultLanguages, truncated, err = code_indexer.PerformSearch(ctx, opts)
 		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Data["SearchError"] = ctx.Tr("explore.code_search_invalid_query", err.Error())
+				ctx.HTML(http.StatusOK, tplExploreCode)
+				return
+			}
 			if code_indexer.IsAvailable(ctx) {
 				ctx.ServerError("SearchResults", err)
 				return
@@ -125,6 +147,8 @@ func Code(ctx *context.Context) {
 
 	ctx.Data["SearchResults"] = searchResults
 	ctx.Data["SearchResultLanguages"] = searchResultLanguages
+	ctx.Data["SearchResultsTruncated"] = truncated
+	ctx.Data["RegexpMaxCandidates"] = code_indexer.RegexpMaxCandidates
 
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
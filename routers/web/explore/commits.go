@@ -0,0 +1,174 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package explore
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	commit_indexer "code.gitea.io/gitea/modules/indexer/commits"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// tplExploreCommits explore commits page template
+	tplExploreCommits base.TplName = "explore/commits"
+)
+
+// CommitSearchResult is a commit found by the commit search with its repository
+type CommitSearchResult struct {
+	Repo   *repo_model.Repository
+	Commit *user_model.UserCommit
+}
+
+// Commits render explore commits page
+func Commits(ctx *context.Context) {
+	if !setting.Indexer.CommitIndexerEnabled {
+		ctx.Redirect(setting.AppSubURL + "/explore")
+		return
+	}
+
+	ctx.Data["UsersIsDisabled"] = setting.Service.Explore.DisableUsersPage
+	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["IsCommitIndexerEnabled"] = setting.Indexer.CommitIndexerEnabled
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreCommits"] = true
+
+	keyword := ctx.FormTrim("q")
+	ctx.Data["Keyword"] = keyword
+	if keyword == "" {
+		ctx.HTML(http.StatusOK, tplExploreCommits)
+		return
+	}
+
+	query, err := commit_indexer.ParseQuery(keyword)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Data["SearchError"] = ctx.Tr("explore.code_search_invalid_query", err.Error())
+			ctx.HTML(http.StatusOK, tplExploreCommits)
+			return
+		}
+		ctx.ServerError("ParseQuery", err)
+		return
+	}
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	var (
+		repoIDs []int64
+		isAdmin = ctx.Doer != nil && ctx.Doer.IsAdmin
+	)
+	// the commits are a part of the code of the repositories
+	if !isAdmin {
+		repoIDs, err = repo_model.FindUserCodeAccessibleRepoIDs(ctx, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("FindUserCodeAccessibleRepoIDs", err)
+			return
+		}
+	}
+	if len(query.Repos) > 0 {
+		repoIDs, err = code_indexer.FilterRepoIDs(ctx, query.Repos, "", repoIDs, isAdmin)
+		if err != nil {
+			ctx.ServerError("FilterRepoIDs", err)
+			return
+		}
+		// none of the repositories can be searched, don't fall back to all of them
+		isAdmin = isAdmin && len(repoIDs) > 0
+	}
+
+	var (
+		total   int64
+		matches []commit_indexer.Match
+	)
+	if len(repoIDs) > 0 || isAdmin {
+		opts := query.ToSearchOptions()
+		opts.RepoIDs = repoIDs
+		opts.Paginator = &db.ListOptions{Page: page, PageSize: setting.UI.RepoSearchPagingNum}
+		matches, total, err = commit_indexer.SearchCommits(ctx, opts)
+		if err != nil {
+			if commit_indexer.IsAvailable(ctx) {
+				ctx.ServerError("SearchCommits", err)
+				return
+			}
+			ctx.Data["CommitIndexerUnavailable"] = true
+		}
+	}
+
+	results, err := loadCommitSearchResults(ctx, matches)
+	if err != nil {
+		ctx.ServerError("loadCommitSearchResults", err)
+		return
+	}
+	ctx.Data["SearchResults"] = results
+
+	pager := context.NewPagination(int(total), setting.UI.RepoSearchPagingNum, page, 5)
+	pager.SetDefaultParams(ctx)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplExploreCommits)
+}
+
+// loadCommitSearchResults reads the matching commits from their repositories, the commits of the
+// deleted repositories and the commits removed from the repositories since they were indexed are skipped
+func loadCommitSearchResults(ctx *context.Context, matches []commit_indexer.Match) ([]*CommitSearchResult, error) {
+	repoIDs := make(container.Set[int64], len(matches))
+	for _, match := range matches {
+		repoIDs.Add(match.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+
+	gitRepos := make(map[int64]*git.Repository, len(repos))
+	defer func() {
+		for _, gitRepo := range gitRepos {
+			gitRepo.Close()
+		}
+	}()
+
+	commits := make([]*git.Commit, 0, len(matches))
+	commitRepos := make([]*repo_model.Repository, 0, len(matches))
+	for _, match := range matches {
+		repo, ok := repos[match.RepoID]
+		if !ok {
+			continue
+		}
+		gitRepo, ok := gitRepos[repo.ID]
+		if !ok {
+			if gitRepo, err = git.OpenRepository(ctx, repo.RepoPath()); err != nil {
+				return nil, err
+			}
+			gitRepos[repo.ID] = gitRepo
+		}
+		commit, err := gitRepo.GetCommit(match.CommitID)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		commits = append(commits, commit)
+		commitRepos = append(commitRepos, repo)
+	}
+
+	results := make([]*CommitSearchResult, 0, len(commits))
+	for i, commit := range user_model.ValidateCommitsWithEmails(ctx, commits) {
+		results = append(results, &CommitSearchResult{Repo: commitRepos[i], Commit: commit})
+	}
+	return results, nil
+}
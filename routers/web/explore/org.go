@@ -18,6 +18,7 @@ func Organizations(ctx *context.Context) {
 	ctx.Data["PageIsExplore"] = true
 	ctx.Data["PageIsExploreOrganizations"] = true
 	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["IsCommitIndexerEnabled"] = setting.Indexer.CommitIndexerEnabled
 
 	visibleTypes := []structs.VisibleType{structs.VisibleTypePublic}
 	if ctx.Doer != nil {
@@ -0,0 +1,78 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package feed
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"github.com/gorilla/feeds"
+)
+
+// ShowSavedSearchFeed shows the issues of a saved search as RSS / Atom feed, they are the ones the doer can access
+func ShowSavedSearchFeed(ctx *context.Context, s *issues_model.SavedSearch, formatType string) {
+	issues, _, err := issue_service.FindSavedSearchIssues(ctx, ctx.Doer, s, db.ListOptions{
+		Page:     1,
+		PageSize: setting.UI.FeedPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("FindSavedSearchIssues", err)
+		return
+	}
+
+	feed := &feeds.Feed{
+		Title:   ctx.Tr("home.saved_search.feed_title", s.Name),
+		Link:    &feeds.Link{Href: s.HTMLURL()},
+		Created: time.Now(),
+	}
+
+	feed.Items, err = issuesToFeedItems(ctx, issues)
+	if err != nil {
+		ctx.ServerError("issuesToFeedItems", err)
+		return
+	}
+
+	writeFeed(ctx, feed, formatType)
+}
+
+func issuesToFeedItems(ctx *context.Context, issues issues_model.IssueList) (items []*feeds.Item, err error) {
+	if err := issues.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		content, err := markdown.RenderString(&markup.RenderContext{
+			Ctx:       ctx,
+			URLPrefix: issue.Repo.Link(),
+			Metas:     issue.Repo.ComposeMetas(),
+		}, issue.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		link := &feeds.Link{Href: issue.HTMLURL()}
+		items = append(items, &feeds.Item{
+			Title:   fmt.Sprintf("%s#%d: %s", issue.Repo.FullName(), issue.Index, issue.Title),
+			Link:    link,
+			Created: issue.CreatedUnix.AsTime(),
+			Updated: issue.UpdatedUnix.AsTime(),
+			Author: &feeds.Author{
+				Name:  issue.Poster.DisplayName(),
+				Email: issue.Poster.GetEmail(),
+			},
+			Id:      fmt.Sprintf("%d: %s", issue.ID, link.Href),
+			Content: content,
+		})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const (
+	tplSettingsIssueTypes    base.TplName = "org/settings/issue_types"
+	tplSettingsIssueTypeEdit base.TplName = "org/settings/issue_type_edit"
+)
+
+// defaultIssueTypeTemplate is the template proposed for a new issue type
+const defaultIssueTypeTemplate = `body:
+  - type: textarea
+    id: description
+    attributes:
+      label: Description
+    validations:
+      required: true
+`
+
+func issueTypesLink(ctx *context.Context) string {
+	return ctx.Org.OrgLink + "/settings/issue_types"
+}
+
+// IssueTypes renders the issue types of an organization
+func IssueTypes(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.issue_types")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsOrgSettingsIssueTypes"] = true
+
+	issueTypes, err := issues_model.GetIssueTypesByOrgID(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueTypesByOrgID", err)
+		return
+	}
+	ctx.Data["IssueTypes"] = issueTypes
+
+	ctx.HTML(http.StatusOK, tplSettingsIssueTypes)
+}
+
+// NewIssueType renders the page to create an issue type
+func NewIssueType(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.issue_types.new")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsOrgSettingsIssueTypes"] = true
+	ctx.Data["color"] = "#84b6eb"
+	ctx.Data["template"] = defaultIssueTypeTemplate
+
+	ctx.HTML(http.StatusOK, tplSettingsIssueTypeEdit)
+}
+
+// NewIssueTypePost creates an issue type
+func NewIssueTypePost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.IssueTypeForm)
+	ctx.Data["Title"] = ctx.Tr("org.settings.issue_types.new")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsOrgSettingsIssueTypes"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsIssueTypeEdit)
+		return
+	}
+
+	issueType := &issues_model.IssueType{
+		OrgID:       ctx.Org.Organization.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		Color:       form.Color,
+		Template:    form.Template,
+	}
+	if err := issues_model.NewIssueType(ctx, issueType); err != nil {
+		handleIssueTypeError(ctx, "NewIssueType", form, err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("org.settings.issue_types.create_success", issueType.Name))
+	ctx.Redirect(issueTypesLink(ctx))
+}
+
+func getOrgIssueType(ctx *context.Context) *issues_model.IssueType {
+	issueType, err := issues_model.GetIssueTypeInOrg(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrIssueTypeNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetIssueTypeInOrg", err)
+		}
+		return nil
+	}
+	return issueType
+}
+
+// EditIssueType renders the page to edit an issue type
+func EditIssueType(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.issue_types.edit")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsOrgSettingsIssueTypes"] = true
+	ctx.Data["PageIsEditIssueType"] = true
+
+	issueType := getOrgIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["name"] = issueType.Name
+	ctx.Data["description"] = issueType.Description
+	ctx.Data["color"] = issueType.Color
+	ctx.Data["template"] = issueType.Template
+
+	ctx.HTML(http.StatusOK, tplSettingsIssueTypeEdit)
+}
+
+// EditIssueTypePost updates an issue type
+func EditIssueTypePost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.IssueTypeForm)
+	ctx.Data["Title"] = ctx.Tr("org.settings.issue_types.edit")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsOrgSettingsIssueTypes"] = true
+	ctx.Data["PageIsEditIssueType"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsIssueTypeEdit)
+		return
+	}
+
+	issueType := getOrgIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+	issueType.Name = form.Name
+	issueType.Description = form.Description
+	issueType.Color = form.Color
+	issueType.Template = form.Template
+	if err := issues_model.UpdateIssueType(ctx, issueType); err != nil {
+		handleIssueTypeError(ctx, "UpdateIssueType", form, err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("org.settings.issue_types.edit_success", issueType.Name))
+	ctx.Redirect(issueTypesLink(ctx))
+}
+
+func handleIssueTypeError(ctx *context.Context, name string, form *forms.IssueTypeForm, err error) {
+	switch {
+	case issues_model.IsErrIssueTypeAlreadyExist(err):
+		ctx.Data["Err_Name"] = true
+		ctx.RenderWithErr(ctx.Tr("org.settings.issue_types.name_in_use"), tplSettingsIssueTypeEdit, form)
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.Data["Err_Template"] = true
+		ctx.RenderWithErr(ctx.Tr("org.settings.issue_types.invalid_template", err.Error()), tplSettingsIssueTypeEdit, form)
+	default:
+		ctx.ServerError(name, err)
+	}
+}
+
+// DeleteIssueType deletes an issue type, its issues have no type anymore
+func DeleteIssueType(ctx *context.Context) {
+	if err := issue_service.DeleteIssueType(ctx, ctx.Org.Organization.ID, ctx.FormInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteIssueType: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("org.settings.issue_types.deletion_success"))
+	}
+
+	ctx.JSONRedirect(issueTypesLink(ctx))
+}
@@ -0,0 +1,480 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+	"code.gitea.io/gitea/services/forms"
+)
+
+const (
+	tplMilestones    base.TplName = "org/milestone/list"
+	tplMilestoneNew  base.TplName = "org/milestone/new"
+	tplMilestoneView base.TplName = "org/milestone/view"
+)
+
+// burndownWidth and burndownHeight are the size of the burndown chart of a milestone
+const (
+	burndownWidth  = 600
+	burndownHeight = 200
+)
+
+// MustBeOrganization makes the milestones pages only available to organizations
+func MustBeOrganization(ctx *context.Context) {
+	if !ctx.ContextUser.IsOrganization() {
+		ctx.NotFound("MustBeOrganization", nil)
+	}
+}
+
+func milestonesLink(ctx *context.Context) string {
+	return ctx.ContextUser.HomeLink() + "/-/milestones"
+}
+
+func prepareMilestonesPage(ctx *context.Context) bool {
+	shared_user.RenderUserHeader(ctx)
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return false
+	}
+	ctx.Data["PageIsViewMilestones"] = true
+	ctx.Data["MilestonesLink"] = milestonesLink(ctx)
+	ctx.Data["CanWriteIssues"] = ctx.Org.CanWriteUnit(ctx, unit.TypeIssues)
+	return true
+}
+
+func renderMilestoneContent(ctx *context.Context, m *issues_model.Milestone) (err error) {
+	m.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
+		URLPrefix: ctx.ContextUser.HomeLink(),
+		Ctx:       ctx,
+	}, m.Content)
+	return err
+}
+
+// milestoneRepoIDs returns the repositories of the organization whose issues or pull requests the doer can read
+func milestoneRepoIDs(ctx *context.Context) ([]int64, error) {
+	repoIDs := make(container.Set[int64])
+	for _, unitType := range []unit.Type{unit.TypeIssues, unit.TypePullRequests} {
+		ids, _, err := repo_model.SearchRepositoryIDs(&repo_model.SearchRepoOptions{
+			Actor:       ctx.Doer,
+			OwnerID:     ctx.ContextUser.ID,
+			Private:     ctx.IsSigned,
+			Collaborate: util.OptionalBoolNone,
+			UnitType:    unitType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		repoIDs.AddMultiple(ids...)
+	}
+	return repoIDs.Values(), nil
+}
+
+// Milestones renders the milestones of an organization
+func Milestones(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.milestones")
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+
+	isShowClosed := ctx.FormString("state") == "closed"
+	sortType := ctx.FormString("sort")
+	keyword := ctx.FormTrim("q")
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	state := structs.StateOpen
+	if isShowClosed {
+		state = structs.StateClosed
+	}
+
+	miles, total, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		ListOptions: db.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.IssuePagingNum,
+		},
+		OrgID:    ctx.ContextUser.ID,
+		State:    state,
+		SortType: sortType,
+		Name:     keyword,
+	})
+	if err != nil {
+		ctx.ServerError("GetMilestones", err)
+		return
+	}
+
+	opState := structs.StateClosed
+	if isShowClosed {
+		opState = structs.StateOpen
+	}
+	opTotal, err := issues_model.CountMilestones(ctx, issues_model.GetMilestonesOption{
+		OrgID: ctx.ContextUser.ID,
+		State: opState,
+		Name:  keyword,
+	})
+	if err != nil {
+		ctx.ServerError("CountMilestones", err)
+		return
+	}
+	if isShowClosed {
+		ctx.Data["OpenCount"] = opTotal
+		ctx.Data["ClosedCount"] = total
+		ctx.Data["State"] = "closed"
+	} else {
+		ctx.Data["OpenCount"] = total
+		ctx.Data["ClosedCount"] = opTotal
+		ctx.Data["State"] = "open"
+	}
+
+	for _, m := range miles {
+		if err := renderMilestoneContent(ctx, m); err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+	}
+	ctx.Data["Milestones"] = miles
+	ctx.Data["SortType"] = sortType
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["IsShowClosed"] = isShowClosed
+
+	pager := context.NewPagination(int(total), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParam(ctx, "state", "State")
+	pager.AddParam(ctx, "q", "Keyword")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplMilestones)
+}
+
+// NewMilestone renders the page to create a milestone of an organization
+func NewMilestone(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.milestones.new")
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplMilestoneNew)
+}
+
+func parseMilestoneDeadline(ctx *context.Context, form *forms.CreateMilestoneForm) (timeutil.TimeStamp, bool) {
+	if len(form.Deadline) == 0 {
+		form.Deadline = "9999-12-31"
+	}
+	deadline, err := time.ParseInLocation("2006-01-02", form.Deadline, time.Local)
+	if err != nil {
+		ctx.Data["Err_Deadline"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.milestones.invalid_due_date_format"), tplMilestoneNew, form)
+		return 0, false
+	}
+	deadline = time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 23, 59, 59, 0, deadline.Location())
+	return timeutil.TimeStamp(deadline.Unix()), true
+}
+
+// NewMilestonePost creates a milestone of an organization
+func NewMilestonePost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateMilestoneForm)
+	ctx.Data["Title"] = ctx.Tr("repo.milestones.new")
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplMilestoneNew)
+		return
+	}
+
+	deadline, ok := parseMilestoneDeadline(ctx, form)
+	if !ok {
+		return
+	}
+	if err := issues_model.NewMilestone(ctx, &issues_model.Milestone{
+		OrgID:        ctx.ContextUser.ID,
+		Name:         form.Title,
+		Content:      form.Content,
+		DeadlineUnix: deadline,
+	}); err != nil {
+		ctx.ServerError("NewMilestone", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.milestones.create_success", form.Title))
+	ctx.Redirect(milestonesLink(ctx))
+}
+
+func getOrgMilestone(ctx *context.Context) *issues_model.Milestone {
+	m, err := issues_model.GetMilestoneByOrgID(ctx, ctx.ContextUser.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrMilestoneNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetMilestoneByOrgID", err)
+		}
+		return nil
+	}
+	return m
+}
+
+// EditMilestone renders the page to edit a milestone of an organization
+func EditMilestone(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.milestones.edit")
+	ctx.Data["PageIsEditMilestone"] = true
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+
+	m := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["title"] = m.Name
+	ctx.Data["content"] = m.Content
+	if len(m.DeadlineString) > 0 {
+		ctx.Data["deadline"] = m.DeadlineString
+	}
+	ctx.HTML(http.StatusOK, tplMilestoneNew)
+}
+
+// EditMilestonePost updates a milestone of an organization
+func EditMilestonePost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.CreateMilestoneForm)
+	ctx.Data["Title"] = ctx.Tr("repo.milestones.edit")
+	ctx.Data["PageIsEditMilestone"] = true
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplMilestoneNew)
+		return
+	}
+
+	deadline, ok := parseMilestoneDeadline(ctx, form)
+	if !ok {
+		return
+	}
+	m := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+	m.Name = form.Title
+	m.Content = form.Content
+	m.DeadlineUnix = deadline
+	if err := issues_model.UpdateMilestone(ctx, m, m.IsClosed); err != nil {
+		ctx.ServerError("UpdateMilestone", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.milestones.edit_success", m.Name))
+	ctx.Redirect(milestonesLink(ctx))
+}
+
+// ChangeMilestoneStatus opens or closes a milestone of an organization
+func ChangeMilestoneStatus(ctx *context.Context) {
+	m := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := issues_model.ChangeMilestoneStatus(ctx, m, ctx.Params(":action") == "close"); err != nil {
+		ctx.ServerError("ChangeMilestoneStatus", err)
+		return
+	}
+	ctx.JSONRedirect(milestonesLink(ctx) + "?state=" + url.QueryEscape(ctx.Params(":action")))
+}
+
+// DeleteMilestone deletes a milestone of an organization
+func DeleteMilestone(ctx *context.Context) {
+	if err := issues_model.DeleteMilestoneByOrgID(ctx, ctx.ContextUser.ID, ctx.FormInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteMilestoneByOrgID: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.milestones.deletion_success"))
+	}
+
+	ctx.JSONRedirect(milestonesLink(ctx))
+}
+
+// MilestoneRepoProgress is the progress of a milestone in a repository of the organization
+type MilestoneRepoProgress struct {
+	Repo *repo_model.Repository
+	*issues_model.MilestoneRepoStat
+}
+
+// BurndownChart is the burndown of a milestone drawn as SVG polylines
+type BurndownChart struct {
+	Width, Height int
+	MaxIssues     int64
+	Start, End    time.Time
+	IssuesLine    string
+	OpenLine      string
+}
+
+func newBurndownChart(points []*issues_model.MilestoneBurndownPoint) *BurndownChart {
+	chart := &BurndownChart{
+		Width:  burndownWidth,
+		Height: burndownHeight,
+		Start:  points[0].Date,
+		End:    points[len(points)-1].Date,
+	}
+	for _, p := range points {
+		chart.MaxIssues = max(chart.MaxIssues, p.NumIssues)
+	}
+
+	var issuesLine, openLine strings.Builder
+	for i, p := range points {
+		x := 0
+		if len(points) > 1 {
+			x = i * burndownWidth / (len(points) - 1)
+		}
+		y := func(n int64) int64 {
+			if chart.MaxIssues == 0 {
+				return burndownHeight
+			}
+			return burndownHeight - n*burndownHeight/chart.MaxIssues
+		}
+		fmt.Fprintf(&issuesLine, "%d,%d ", x, y(p.NumIssues))
+		fmt.Fprintf(&openLine, "%d,%d ", x, y(p.NumOpen))
+	}
+	chart.IssuesLine = strings.TrimSpace(issuesLine.String())
+	chart.OpenLine = strings.TrimSpace(openLine.String())
+	return chart
+}
+
+// ViewMilestone renders a milestone of an organization, with its progress in each repository,
+// its burndown and its issues and pull requests
+func ViewMilestone(ctx *context.Context) {
+	if !prepareMilestonesPage(ctx) {
+		return
+	}
+	m := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := renderMilestoneContent(ctx, m); err != nil {
+		ctx.ServerError("RenderString", err)
+		return
+	}
+	ctx.Data["Title"] = m.Name
+	ctx.Data["Milestone"] = m
+
+	repoIDs, err := milestoneRepoIDs(ctx)
+	if err != nil {
+		ctx.ServerError("milestoneRepoIDs", err)
+		return
+	}
+
+	stats, err := issues_model.GetMilestoneRepoStats(ctx, m.ID, repoIDs)
+	if err != nil {
+		ctx.ServerError("GetMilestoneRepoStats", err)
+		return
+	}
+	statRepoIDs := make([]int64, 0, len(stats))
+	for _, stat := range stats {
+		statRepoIDs = append(statRepoIDs, stat.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(statRepoIDs)
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+	progress := make([]*MilestoneRepoProgress, 0, len(stats))
+	var numIssues, numClosedIssues int64
+	for _, stat := range stats {
+		if repo, ok := repos[stat.RepoID]; ok {
+			progress = append(progress, &MilestoneRepoProgress{Repo: repo, MilestoneRepoStat: stat})
+			numIssues += stat.NumIssues
+			numClosedIssues += stat.NumClosedIssues
+		}
+	}
+	ctx.Data["RepoProgress"] = progress
+	ctx.Data["NumIssues"] = numIssues
+	ctx.Data["NumOpenIssues"] = numIssues - numClosedIssues
+	ctx.Data["NumClosedIssues"] = numClosedIssues
+
+	points, err := issues_model.GetMilestoneBurndown(ctx, m, repoIDs)
+	if err != nil {
+		ctx.ServerError("GetMilestoneBurndown", err)
+		return
+	}
+	ctx.Data["Burndown"] = newBurndownChart(points)
+
+	isShowClosed := ctx.FormString("state") == "closed"
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	if len(repoIDs) == 0 {
+		repoIDs = []int64{0}
+	}
+	opts := &issues_model.IssuesOptions{
+		Paginator: &db.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.IssuePagingNum,
+		},
+		RepoIDs:      repoIDs,
+		MilestoneIDs: []int64{m.ID},
+		IsClosed:     util.OptionalBoolOf(isShowClosed),
+		SortType:     "latest",
+	}
+	issues, err := issues_model.Issues(ctx, opts)
+	if err != nil {
+		ctx.ServerError("Issues", err)
+		return
+	}
+	if err := issues.LoadAttributes(ctx); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	approvalCounts, err := issues.GetApprovalCounts(ctx)
+	if err != nil {
+		ctx.ServerError("ApprovalCounts", err)
+		return
+	}
+	ctx.Data["ApprovalCounts"] = func(issueID int64, typ string) int64 {
+		reviewTyp := issues_model.ReviewTypeApprove
+		if typ == "reject" {
+			reviewTyp = issues_model.ReviewTypeReject
+		} else if typ == "waiting" {
+			reviewTyp = issues_model.ReviewTypeRequest
+		}
+		for _, count := range approvalCounts[issueID] {
+			if count.Type == reviewTyp {
+				return count.Count
+			}
+		}
+		return 0
+	}
+	ctx.Data["Issues"] = issues
+	ctx.Data["IsShowClosed"] = isShowClosed
+
+	shownIssues := numIssues - numClosedIssues
+	if isShowClosed {
+		ctx.Data["State"] = "closed"
+		shownIssues = numClosedIssues
+	} else {
+		ctx.Data["State"] = "open"
+	}
+	pager := context.NewPagination(int(shownIssues), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParam(ctx, "state", "State")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplMilestoneView)
+}
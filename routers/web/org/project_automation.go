@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	shared_project "code.gitea.io/gitea/routers/web/shared/project"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+)
+
+const tplProjectAutomation base.TplName = "org/projects/automation"
+
+func projectAutomationLink(ctx *context.Context, project *project_model.Project) string {
+	return projectLink(ctx, project) + "/automation"
+}
+
+// ProjectAutomation renders the page managing the automations of a project
+func ProjectAutomation(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.SetAutomationContext(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["PageIsViewProjects"] = true
+	ctx.Data["ProjectLink"] = projectLink(ctx, project)
+	shared_user.RenderUserHeader(ctx)
+
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplProjectAutomation)
+}
+
+// NewProjectAutomationPost adds an automation to a project
+func NewProjectAutomationPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.NewAutomationPost(ctx, project, projectAutomationLink(ctx, project))
+}
+
+// DeleteProjectAutomationPost deletes an automation of a project
+func DeleteProjectAutomationPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.DeleteAutomationPost(ctx, project, projectAutomationLink(ctx, project))
+}
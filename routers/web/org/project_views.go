@@ -0,0 +1,89 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	shared_project "code.gitea.io/gitea/routers/web/shared/project"
+	shared_user "code.gitea.io/gitea/routers/web/shared/user"
+)
+
+const tplProjectSavedView base.TplName = "org/projects/saved_view"
+
+func projectLink(ctx *context.Context, project *project_model.Project) string {
+	return fmt.Sprintf("%s/-/projects/%d", ctx.ContextUser.HomeLink(), project.ID)
+}
+
+// ViewProjectView renders a saved table or roadmap view of a project
+func ViewProjectView(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+
+	boards, err := project.GetBoards(ctx)
+	if err != nil {
+		ctx.ServerError("GetProjectBoards", err)
+		return
+	}
+	if boards[0].ID == 0 {
+		boards[0].Title = ctx.Tr("repo.projects.type.uncategorized")
+	}
+	issuesMap, err := issues_model.LoadIssuesFromBoardList(ctx, boards)
+	if err != nil {
+		ctx.ServerError("LoadIssuesOfBoards", err)
+		return
+	}
+
+	shared_project.PrepareView(ctx, project, boards, issuesMap)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["PageIsViewProjects"] = true
+	ctx.Data["CanWriteProjects"] = canWriteProjects(ctx)
+	ctx.Data["Project"] = project
+	ctx.Data["ProjectLink"] = projectLink(ctx, project)
+	shared_user.RenderUserHeader(ctx)
+
+	if err := shared_user.LoadHeaderCount(ctx); err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplProjectSavedView)
+}
+
+// NewProjectViewPost saves a view of a project
+func NewProjectViewPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.NewViewPost(ctx, project, projectLink(ctx, project))
+}
+
+// EditProjectViewPost changes a saved view of a project
+func EditProjectViewPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.EditViewPost(ctx, project, projectLink(ctx, project))
+}
+
+// DeleteProjectViewPost deletes a saved view of a project
+func DeleteProjectViewPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, ctx.ContextUser.ID, 0)
+	if ctx.Written() {
+		return
+	}
+	shared_project.DeleteViewPost(ctx, project, projectLink(ctx, project))
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/web/shared/timetracking"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const tplTimes base.TplName = "org/times"
+
+// Times shows the report and the timesheet of the times tracked on the issues of the repositories of an
+// organization, the members who don't own the organization only see their own times
+func Times(ctx *context.Context) {
+	org := ctx.Org.Organization
+	ctx.Data["Title"] = ctx.Tr("org.times")
+	ctx.Data["PageIsOrgTimes"] = true
+
+	repoIDs, err := issue_service.TrackedTimeRepoIDs(ctx, org.AsUser(), ctx.Doer)
+	if err != nil {
+		ctx.ServerError("TrackedTimeRepoIDs", err)
+		return
+	}
+
+	labels, err := issues_model.GetLabelsByOrgID(ctx, org.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetLabelsByOrgID", err)
+		return
+	}
+	ctx.Data["Labels"] = labels
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		OrgID: org.ID,
+		State: api.StateAll,
+	})
+	if err != nil {
+		ctx.ServerError("GetMilestones", err)
+		return
+	}
+	ctx.Data["Milestones"] = milestones
+
+	timetracking.Times(ctx, timetracking.Options{
+		Find:       &issues_model.FindTrackedTimesOptions{RepositoryIDs: repoIDs},
+		CanViewAll: ctx.Doer.IsAdmin || ctx.Org.IsOwner,
+		Name:       org.Name,
+		Template:   tplTimes,
+	})
+}
@@ -294,6 +294,7 @@ func Rerun(ctx *context_module.Context) {
 			return
 		}
 	}
+	actions_service.NotifyWorkflowRunRequested(ctx, run.ID)
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
@@ -317,6 +318,7 @@ func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob) erro
 	}
 
 	actions_service.CreateCommitStatus(ctx, job)
+	actions_service.NotifyWorkflowJobsStatus(ctx, job)
 	return nil
 }
 
@@ -377,12 +379,14 @@ func Cancel(ctx *context_module.Context) {
 		return
 	}
 
+	var cancelled []*actions_model.ActionRunJob
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		for _, job := range jobs {
 			status := job.Status
 			if status.IsDone() {
 				continue
 			}
+			cancelled = append(cancelled, job)
 			if job.TaskID == 0 {
 				job.Status = actions_model.StatusCancelled
 				job.Stopped = timeutil.TimeStampNow()
@@ -406,6 +410,7 @@ func Cancel(ctx *context_module.Context) {
 	}
 
 	actions_service.CreateCommitStatus(ctx, jobs...)
+	actions_service.NotifyWorkflowJobsStatus(ctx, cancelled...)
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
@@ -420,6 +425,7 @@ func Approve(ctx *context_module.Context) {
 	run := current.Run
 	doer := ctx.Doer
 
+	var approved []*actions_model.ActionRunJob
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		run.NeedApproval = false
 		run.ApprovedBy = doer.ID
@@ -433,6 +439,7 @@ func Approve(ctx *context_module.Context) {
 				if err != nil {
 					return err
 				}
+				approved = append(approved, job)
 			}
 		}
 		return nil
@@ -442,6 +449,7 @@ func Approve(ctx *context_module.Context) {
 	}
 
 	actions_service.CreateCommitStatus(ctx, jobs...)
+	actions_service.NotifyWorkflowJobsStatus(ctx, approved...)
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
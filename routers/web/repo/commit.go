@@ -20,8 +20,10 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitgraph"
+	commit_indexer "code.gitea.io/gitea/modules/indexer/commits"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/gitdiff"
 	git_service "code.gitea.io/gitea/services/repository"
 )
@@ -48,6 +50,7 @@ func RefCommits(ctx *context.Context) {
 // Commits render branch's commits
 func Commits(ctx *context.Context) {
 	ctx.Data["PageIsCommits"] = true
+	ctx.Data["IsCommitIndexerEnabled"] = setting.Indexer.CommitIndexerEnabled
 	if ctx.Repo.Commit == nil {
 		ctx.NotFound("Commit not found", nil)
 		return
@@ -187,6 +190,21 @@ func SearchCommits(ctx *context.Context) {
 	}
 
 	all := ctx.FormBool("all")
+	ctx.Data["IsCommitIndexerEnabled"] = setting.Indexer.CommitIndexerEnabled
+	// the commit indexer only contains the commits of the default branch
+	if setting.Indexer.CommitIndexerEnabled && !all && ctx.Repo.IsViewBranch && ctx.Repo.BranchName == ctx.Repo.Repository.DefaultBranch {
+		if searched, err := searchIndexedCommits(ctx, query); err != nil {
+			ctx.ServerError("searchIndexedCommits", err)
+			return
+		} else if searched {
+			ctx.Data["Keyword"] = query
+			ctx.Data["Username"] = ctx.Repo.Owner.Name
+			ctx.Data["Reponame"] = ctx.Repo.Repository.Name
+			ctx.HTML(http.StatusOK, tplCommits)
+			return
+		}
+	}
+
 	opts := git.NewSearchCommitsOptions(query, all)
 	commits, err := ctx.Repo.Commit.SearchCommits(opts)
 	if err != nil {
@@ -205,6 +223,55 @@ func SearchCommits(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplCommits)
 }
 
+// searchIndexedCommits searches the commits of the default branch with the commit indexer. It returns false
+// if the search should fall back to git, when the indexer isn't available or the query isn't understood by it.
+func searchIndexedCommits(ctx *context.Context, keyword string) (bool, error) {
+	query, err := commit_indexer.ParseQuery(keyword)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := setting.Git.CommitsRangeSize
+
+	opts := query.ToSearchOptions()
+	opts.RepoIDs = []int64{ctx.Repo.Repository.ID}
+	opts.Paginator = &db.ListOptions{Page: page, PageSize: pageSize}
+	matches, total, err := commit_indexer.SearchCommits(ctx, opts)
+	if err != nil {
+		if !commit_indexer.IsAvailable(ctx) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	commits := make([]*git.Commit, 0, len(matches))
+	for _, match := range matches {
+		commit, err := ctx.Repo.GitRepo.GetCommit(match.CommitID)
+		if err != nil {
+			// the commit has been removed since it was indexed
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return false, err
+		}
+		commits = append(commits, commit)
+	}
+	ctx.Data["CommitCount"] = total
+	ctx.Data["Commits"] = git_model.ConvertFromGitCommit(ctx, commits, ctx.Repo.Repository)
+
+	pager := context.NewPagination(int(total), pageSize, page, 5)
+	pager.SetDefaultParams(ctx)
+	ctx.Data["Page"] = pager
+	return true, nil
+}
+
 // FileHistory show a file's reversions
 func FileHistory(ctx *context.Context) {
 	ctx.Data["IsRepoToolbarCommits"] = true
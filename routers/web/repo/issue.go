@@ -191,6 +191,17 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	if bytes.Contains([]byte(keyword), []byte{0x00}) {
 		keyword = ""
 	}
+	searchKeyword, parentID, err := issues_model.ParseParentKeyword(ctx, keyword, repo)
+	if err != nil {
+		ctx.ServerError("ParseParentKeyword", err)
+		return
+	}
+	searchKeyword, typeID, fieldValues, err := issues_model.ParseIssueTypeKeyword(ctx, searchKeyword, repo.OwnerID)
+	if err != nil {
+		ctx.ServerError("ParseIssueTypeKeyword", err)
+		return
+	}
+	searchKeyword, slaState := issues_model.ParseSLAKeyword(searchKeyword)
 
 	var mileIDs []int64
 	if milestoneID > 0 || milestoneID == db.NoConditionID { // -1 to get those issues which have no any milestone assigned
@@ -204,6 +215,10 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			LabelIDs:          labelIDs,
 			MilestoneIDs:      mileIDs,
 			ProjectID:         projectID,
+			ParentID:          parentID,
+			TypeID:            typeID,
+			FieldValues:       fieldValues,
+			SLAState:          slaState,
 			AssigneeID:        assigneeID,
 			MentionedID:       mentionedID,
 			PosterID:          posterID,
@@ -212,8 +227,8 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			IsPull:            isPullOption,
 			IssueIDs:          nil,
 		}
-		if keyword != "" {
-			allIssueIDs, err := issueIDsFromSearch(ctx, keyword, statsOpts)
+		if searchKeyword != "" {
+			allIssueIDs, err := issueIDsFromSearch(ctx, searchKeyword, statsOpts)
 			if err != nil {
 				if issue_indexer.IsAvailable(ctx) {
 					ctx.ServerError("issueIDsFromSearch", err)
@@ -224,7 +239,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			}
 			statsOpts.IssueIDs = allIssueIDs
 		}
-		if keyword != "" && len(statsOpts.IssueIDs) == 0 {
+		if searchKeyword != "" && len(statsOpts.IssueIDs) == 0 {
 			// So it did search with the keyword, but no issue found.
 			// Just set issueStats to empty.
 			issueStats = &issues_model.IssueStats{}
@@ -261,7 +276,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 
 	var issues issues_model.IssueList
 	{
-		ids, err := issueIDsFromSearch(ctx, keyword, &issues_model.IssuesOptions{
+		ids, err := issueIDsFromSearch(ctx, searchKeyword, &issues_model.IssuesOptions{
 			Paginator: &db.ListOptions{
 				Page:     pager.Paginater.Current(),
 				PageSize: setting.UI.IssuePagingNum,
@@ -274,6 +289,10 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			ReviewedID:        reviewedID,
 			MilestoneIDs:      mileIDs,
 			ProjectID:         projectID,
+			ParentID:          parentID,
+			TypeID:            typeID,
+			FieldValues:       fieldValues,
+			SLAState:          slaState,
 			IsClosed:          util.OptionalBoolOf(isShowClosed),
 			IsPull:            isPullOption,
 			LabelIDs:          labelIDs,
@@ -321,6 +340,10 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 		ctx.ServerError("issues.LoadAttributes", err)
 		return
 	}
+	if err := issues.LoadSLAs(ctx); err != nil {
+		ctx.ServerError("issues.LoadSLAs", err)
+		return
+	}
 
 	ctx.Data["Issues"] = issues
 	ctx.Data["CommitLastStatus"] = lastStatus
@@ -493,12 +516,28 @@ func Issues(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplIssues)
 }
 
-func renderMilestones(ctx *context.Context) {
-	// Get milestones
+// getMilestonesForRepo returns the milestones of a repository followed by the ones of its owner organization
+func getMilestonesForRepo(repo *repo_model.Repository, state api.StateType) (issues_model.MilestoneList, error) {
 	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
-		RepoID: ctx.Repo.Repository.ID,
-		State:  api.StateAll,
+		RepoID: repo.ID,
+		State:  state,
+	})
+	if err != nil {
+		return nil, err
+	}
+	orgMilestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		OrgID: repo.OwnerID,
+		State: state,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return append(milestones, orgMilestones...), nil
+}
+
+func renderMilestones(ctx *context.Context) {
+	// Get milestones
+	milestones, err := getMilestonesForRepo(ctx.Repo.Repository, api.StateAll)
 	if err != nil {
 		ctx.ServerError("GetAllRepoMilestones", err)
 		return
@@ -519,18 +558,12 @@ func renderMilestones(ctx *context.Context) {
 // RetrieveRepoMilestonesAndAssignees find all the milestones and assignees of a repository
 func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *repo_model.Repository) {
 	var err error
-	ctx.Data["OpenMilestones"], _, err = issues_model.GetMilestones(issues_model.GetMilestonesOption{
-		RepoID: repo.ID,
-		State:  api.StateOpen,
-	})
+	ctx.Data["OpenMilestones"], err = getMilestonesForRepo(repo, api.StateOpen)
 	if err != nil {
 		ctx.ServerError("GetMilestones", err)
 		return
 	}
-	ctx.Data["ClosedMilestones"], _, err = issues_model.GetMilestones(issues_model.GetMilestonesOption{
-		RepoID: repo.ID,
-		State:  api.StateClosed,
-	})
+	ctx.Data["ClosedMilestones"], err = getMilestonesForRepo(repo, api.StateClosed)
 	if err != nil {
 		ctx.ServerError("GetMilestones", err)
 		return
@@ -907,10 +940,50 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleFiles
 	return false, templateErrs
 }
 
+// getRepoIssueTypes returns the issue types of the organization owning the repository
+func getRepoIssueTypes(ctx *context.Context) []*issues_model.IssueType {
+	if !ctx.Repo.Owner.IsOrganization() {
+		return nil
+	}
+	issueTypes, err := issues_model.GetIssueTypesByOrgID(ctx, ctx.Repo.Owner.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueTypesByOrgID", err)
+		return nil
+	}
+	return issueTypes
+}
+
+// getFormIssueType returns the issue type chosen for a new issue and its fields, nil if none was chosen
+func getFormIssueType(ctx *context.Context) (*issues_model.IssueType, []*api.IssueFormField) {
+	typeID := ctx.FormInt64("issue_type")
+	if typeID <= 0 || !ctx.Repo.Owner.IsOrganization() {
+		return nil, nil
+	}
+	issueType, err := issues_model.GetIssueTypeInOrg(ctx, ctx.Repo.Owner.ID, typeID)
+	if err != nil {
+		if issues_model.IsErrIssueTypeNotExist(err) {
+			ctx.NotFound("GetIssueTypeInOrg", err)
+		} else {
+			ctx.ServerError("GetIssueTypeInOrg", err)
+		}
+		return nil, nil
+	}
+	fields, err := issueType.Fields()
+	if err != nil {
+		ctx.ServerError("Fields", err)
+		return nil, nil
+	}
+	return issueType, fields
+}
+
 // NewIssue render creating issue page
 func NewIssue(ctx *context.Context) {
 	issueConfig, _ := issue_service.GetTemplateConfigFromDefaultBranch(ctx.Repo.Repository, ctx.Repo.GitRepo)
-	hasTemplates := issue_service.HasTemplatesOrContactLinks(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	issueTypes := getRepoIssueTypes(ctx)
+	if ctx.Written() {
+		return
+	}
+	hasTemplates := issue_service.HasTemplatesOrContactLinks(ctx.Repo.Repository, ctx.Repo.GitRepo) || len(issueTypes) > 0
 
 	ctx.Data["Title"] = ctx.Tr("repo.issues.new")
 	ctx.Data["PageIsIssueList"] = true
@@ -928,7 +1001,7 @@ func NewIssue(ctx *context.Context) {
 
 	milestoneID := ctx.FormInt64("milestone")
 	if milestoneID > 0 {
-		milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+		milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 		if err != nil {
 			log.Error("GetMilestoneByID: %d: %v", milestoneID, err)
 		} else {
@@ -963,17 +1036,35 @@ func NewIssue(ctx *context.Context) {
 	}
 	ctx.Data["Tags"] = tags
 
-	_, templateErrs := issue_service.GetTemplatesFromDefaultBranch(ctx.Repo.Repository, ctx.Repo.GitRepo)
-	templateLoaded, errs := setTemplateIfExists(ctx, issueTemplateKey, IssueTemplateCandidates)
-	if len(errs) > 0 {
-		for k, v := range errs {
-			templateErrs[k] = v
-		}
-	}
+	issueType, fields := getFormIssueType(ctx)
 	if ctx.Written() {
 		return
 	}
 
+	_, templateErrs := issue_service.GetTemplatesFromDefaultBranch(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	templateLoaded := issueType != nil
+	if issueType != nil {
+		// Replace field default values by values from query, like for the templates
+		for _, field := range fields {
+			if fieldValue := ctx.FormString("field:" + field.ID); fieldValue != "" {
+				field.Attributes["value"] = fieldValue
+			}
+		}
+		ctx.Data["IssueType"] = issueType
+		ctx.Data["Fields"] = fields
+	} else {
+		var errs map[string]error
+		templateLoaded, errs = setTemplateIfExists(ctx, issueTemplateKey, IssueTemplateCandidates)
+		if len(errs) > 0 {
+			for k, v := range errs {
+				templateErrs[k] = v
+			}
+		}
+		if ctx.Written() {
+			return
+		}
+	}
+
 	if len(templateErrs) > 0 {
 		ctx.Flash.Warning(renderErrorOfTemplates(ctx, templateErrs), true)
 	}
@@ -1021,11 +1112,17 @@ func NewIssueChooseTemplate(ctx *context.Context) {
 	issueTemplates, errs := issue_service.GetTemplatesFromDefaultBranch(ctx.Repo.Repository, ctx.Repo.GitRepo)
 	ctx.Data["IssueTemplates"] = issueTemplates
 
+	issueTypes := getRepoIssueTypes(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["IssueTypes"] = issueTypes
+
 	if len(errs) > 0 {
 		ctx.Flash.Warning(renderErrorOfTemplates(ctx, errs), true)
 	}
 
-	if !issue_service.HasTemplatesOrContactLinks(ctx.Repo.Repository, ctx.Repo.GitRepo) {
+	if !issue_service.HasTemplatesOrContactLinks(ctx.Repo.Repository, ctx.Repo.GitRepo) && len(issueTypes) == 0 {
 		// The "issues/new" and "issues/new/choose" share the same query parameters "project" and "milestone", if no template here, just redirect to the "issues/new" page with these parameters.
 		ctx.Redirect(fmt.Sprintf("%s/issues/new?%s", ctx.Repo.Repository.Link(), ctx.Req.URL.RawQuery), http.StatusSeeOther)
 		return
@@ -1099,15 +1196,11 @@ func ValidateRepoMetas(ctx *context.Context, form forms.CreateIssueForm, isPull
 	// Check milestone.
 	milestoneID := form.MilestoneID
 	if milestoneID > 0 {
-		milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+		milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 		if err != nil {
 			ctx.ServerError("GetMilestoneByID", err)
 			return nil, nil, 0, 0
 		}
-		if milestone.RepoID != repo.ID {
-			ctx.ServerError("GetMilestoneByID", err)
-			return nil, nil, 0, 0
-		}
 		ctx.Data["Milestone"] = milestone
 		ctx.Data["milestone_id"] = milestoneID
 	}
@@ -1198,8 +1291,23 @@ func NewIssuePost(ctx *context.Context) {
 		return
 	}
 
+	issueType, fields := getFormIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+	var fieldValues map[string][]string
+	if issueType != nil {
+		fieldValues = issue_template.FieldValues(fields, ctx.Req.Form)
+		if err := issue_template.ValidateFieldValues(fields, fieldValues); err != nil {
+			ctx.JSONError(ctx.Tr("repo.issues.issue_type.invalid_fields", err.Error()))
+			return
+		}
+	}
+
 	content := form.Content
-	if filename := ctx.Req.Form.Get("template-file"); filename != "" {
+	if issueType != nil && len(fields) > 0 {
+		content = issue_template.RenderToMarkdown(&api.IssueTemplate{Fields: fields}, ctx.Req.Form)
+	} else if filename := ctx.Req.Form.Get("template-file"); filename != "" {
 		if template, err := issue_template.UnmarshalFromRepo(ctx.Repo.GitRepo, ctx.Repo.Repository.DefaultBranch, filename); err == nil {
 			content = issue_template.RenderToMarkdown(template, ctx.Req.Form)
 		}
@@ -1225,13 +1333,20 @@ func NewIssuePost(ctx *context.Context) {
 		return
 	}
 
+	if issueType != nil {
+		if err := issue_service.SetIssueType(ctx, ctx.Doer, issue, issueType, fieldValues); err != nil {
+			ctx.ServerError("SetIssueType", err)
+			return
+		}
+	}
+
 	if projectID > 0 {
 		if !ctx.Repo.CanRead(unit.TypeProjects) {
 			// User must also be able to see the project.
 			ctx.Error(http.StatusBadRequest, "user hasn't permissions to read projects")
 			return
 		}
-		if err := issues_model.ChangeProjectAssign(issue, ctx.Doer, projectID); err != nil {
+		if err := issue_service.ChangeProjectAssign(ctx, issue, ctx.Doer, projectID); err != nil {
 			ctx.ServerError("ChangeProjectAssign", err)
 			return
 		}
@@ -1495,6 +1610,21 @@ func ViewIssue(ctx *context.Context) {
 		}
 	}
 
+	if issue.Project != nil {
+		fields, err := project_model.GetFields(ctx, issue.Project.ID)
+		if err != nil {
+			ctx.ServerError("GetFields", err)
+			return
+		}
+		values, err := project_model.GetFieldValues(ctx, issue.Project.ID, issue.ID)
+		if err != nil {
+			ctx.ServerError("GetFieldValues", err)
+			return
+		}
+		ctx.Data["ProjectFields"] = fields
+		ctx.Data["ProjectFieldValues"] = values
+	}
+
 	if issue.IsPull {
 		canChooseReviewer := ctx.Repo.CanWrite(unit.TypePullRequests)
 		if ctx.Doer != nil && ctx.IsSigned {
@@ -1657,7 +1787,8 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadAssigneeUserAndTeam", err)
 				return
 			}
-		} else if comment.Type == issues_model.CommentTypeRemoveDependency || comment.Type == issues_model.CommentTypeAddDependency {
+		} else if comment.Type == issues_model.CommentTypeRemoveDependency || comment.Type == issues_model.CommentTypeAddDependency ||
+			comment.Type == issues_model.CommentTypeRemoveSubIssue || comment.Type == issues_model.CommentTypeAddSubIssue {
 			if err = comment.LoadDepIssueDetails(ctx); err != nil {
 				if !issues_model.IsErrIssueNotExist(err) {
 					ctx.ServerError("LoadDepIssueDetails", err)
@@ -1953,6 +2084,21 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	if !issue.IsPull {
+		prepareSubIssues(ctx, issue)
+		if ctx.Written() {
+			return
+		}
+		prepareIssueType(ctx, issue)
+		if ctx.Written() {
+			return
+		}
+		prepareIssueSLA(ctx, issue)
+		if ctx.Written() {
+			return
+		}
+	}
+
 	var pinAllowed bool
 	if !issue.IsPinned() {
 		pinAllowed, err = issues_model.IsNewPinAllowed(ctx, issue.RepoID, issue.IsPull)
@@ -2738,7 +2884,7 @@ func ListIssues(ctx *context.Context) {
 			if err != nil {
 				continue
 			}
-			mile, err = issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, id)
+			mile, err = issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, id)
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
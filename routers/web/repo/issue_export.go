@@ -0,0 +1,249 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/migrations"
+)
+
+const (
+	tplImportIssues base.TplName = "repo/issue/import"
+
+	exportIssuesPageSize = 50
+)
+
+// ExportIssues exports the issues of a repository matching the filters of the issue list, as CSV or in the
+// format of the migrations as JSON
+func ExportIssues(ctx *context.Context) {
+	format := ctx.FormString("format")
+	if format != "csv" && format != "json" {
+		ctx.NotFound("ExportIssues", nil)
+		return
+	}
+
+	opts, keyword, err := exportIssuesOptions(ctx)
+	if err != nil {
+		ctx.ServerError("exportIssuesOptions", err)
+		return
+	}
+	if ctx.Written() {
+		return
+	}
+
+	// the issues are exported in pages to not rely on the limits of the indexers
+	var ids []int64
+	searchOpts := issue_indexer.ToSearchOptions(keyword, opts)
+	for page := 1; ; page++ {
+		searchOpts.Paginator = &db.ListOptions{Page: page, PageSize: exportIssuesPageSize}
+		pageIDs, total, err := issue_indexer.SearchIssues(ctx, searchOpts)
+		if err != nil {
+			ctx.ServerError("SearchIssues", err)
+			return
+		}
+		ids = append(ids, pageIDs...)
+		if len(pageIDs) < exportIssuesPageSize || int64(len(ids)) >= total {
+			break
+		}
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, ids, true)
+	if err != nil {
+		ctx.ServerError("GetIssuesByIDs", err)
+		return
+	}
+
+	export, err := migrations.ExportIssues(ctx, ctx.Repo.Repository, issues)
+	if err != nil {
+		ctx.ServerError("ExportIssues", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv"
+	if format == "csv" {
+		err = migrations.WriteIssuesCSV(&buf, export)
+	} else {
+		contentType = "application/json"
+		var data []byte
+		if data, err = json.MarshalIndent(export, "", "  "); err == nil {
+			_, err = buf.Write(data)
+		}
+	}
+	if err != nil {
+		ctx.ServerError("Write", err)
+		return
+	}
+
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		ContentType:        contentType,
+		ContentTypeCharset: "utf-8",
+		Filename:           ctx.Repo.Repository.Name + "-issues." + format,
+	})
+	if _, err := ctx.Resp.Write(buf.Bytes()); err != nil {
+		log.Error("Write: %v", err)
+	}
+}
+
+// exportIssuesOptions returns the options of the issues to export and the keyword to search, from the filters
+// of the issue list
+func exportIssuesOptions(ctx *context.Context) (*issues_model.IssuesOptions, string, error) {
+	opts := &issues_model.IssuesOptions{
+		RepoIDs:    []int64{ctx.Repo.Repository.ID},
+		IsPull:     util.OptionalBoolFalse,
+		AssigneeID: ctx.FormInt64("assignee"),
+		PosterID:   ctx.FormInt64("poster"),
+		ProjectID:  ctx.FormInt64("project"),
+		SortType:   "oldest",
+	}
+	switch ctx.FormString("state") {
+	case "open":
+		opts.IsClosed = util.OptionalBoolFalse
+	case "closed":
+		opts.IsClosed = util.OptionalBoolTrue
+	}
+	if labels := ctx.FormString("labels"); labels != "" {
+		labelIDs, err := base.StringsToInt64s(strings.Split(labels, ","))
+		if err != nil {
+			ctx.NotFound("StringsToInt64s", err)
+			return nil, "", nil
+		}
+		opts.LabelIDs = labelIDs
+	}
+	if milestoneID := ctx.FormInt64("milestone"); milestoneID > 0 || milestoneID == db.NoConditionID {
+		opts.MilestoneIDs = []int64{milestoneID}
+	}
+
+	keyword := strings.TrimSpace(ctx.FormString("q"))
+	keyword, parentID, err := issues_model.ParseParentKeyword(ctx, keyword, ctx.Repo.Repository)
+	if err != nil {
+		return nil, "", err
+	}
+	opts.ParentID = parentID
+	if keyword, opts.TypeID, opts.FieldValues, err = issues_model.ParseIssueTypeKeyword(ctx, keyword, ctx.Repo.Repository.OwnerID); err != nil {
+		return nil, "", err
+	}
+	keyword, opts.SLAState = issues_model.ParseSLAKeyword(keyword)
+	return opts, keyword, nil
+}
+
+// ImportIssues renders the page to import issues into a repository
+func ImportIssues(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.issues.import")
+	ctx.Data["PageIsIssueList"] = true
+
+	ctx.HTML(http.StatusOK, tplImportIssues)
+}
+
+// ImportIssuesPost imports issues exported as CSV, or in the format of the migrations as JSON or YAML
+func ImportIssuesPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ImportIssuesForm)
+	ctx.Data["Title"] = ctx.Tr("repo.issues.import")
+	ctx.Data["PageIsIssueList"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplImportIssues)
+		return
+	}
+
+	userMap, err := parseImportUserMap(form.UserMap)
+	if err != nil {
+		ctx.Data["Err_UserMap"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.issues.import.invalid_user_map", err.Error()), tplImportIssues, form)
+		return
+	}
+
+	if form.File == nil || form.File.Filename == "" {
+		ctx.Data["Err_File"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.issues.import.no_file"), tplImportIssues, form)
+		return
+	}
+	if form.File.Size > setting.Attachment.MaxSize<<20 {
+		ctx.Data["Err_File"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.issues.import.file_too_big", setting.Attachment.MaxSize), tplImportIssues, form)
+		return
+	}
+	f, err := form.File.Open()
+	if err != nil {
+		ctx.ServerError("Open", err)
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		ctx.ServerError("ReadAll", err)
+		return
+	}
+
+	var export *migration.IssueExport
+	switch strings.ToLower(path.Ext(form.File.Filename)) {
+	case ".csv":
+		export, err = migrations.ReadIssuesCSV(bytes.NewReader(data))
+	case ".yml", ".yaml":
+		export, err = migration.LoadIssueExport(data, false)
+	default:
+		export, err = migration.LoadIssueExport(data, true)
+	}
+	if err != nil {
+		ctx.Data["Err_File"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.issues.import.invalid_file", err.Error()), tplImportIssues, form)
+		return
+	}
+
+	count, err := migrations.ImportIssues(ctx, ctx.Doer, ctx.Repo.Repository, export, migrations.ImportIssuesOptions{
+		UserMap: userMap,
+	})
+	if err != nil {
+		switch {
+		case user_model.IsErrUserNotExist(err):
+			ctx.Data["Err_UserMap"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.issues.import.user_not_exist", err.(user_model.ErrUserNotExist).Name), tplImportIssues, form)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Data["Err_File"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.issues.import.invalid_file", err.Error()), tplImportIssues, form)
+		default:
+			ctx.ServerError("ImportIssues", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.import.success", count))
+	ctx.Redirect(ctx.Repo.RepoLink + "/issues?state=all")
+}
+
+// parseImportUserMap parses the user mapping of an import, one `name=local_name` per line
+func parseImportUserMap(s string) (map[string]string, error) {
+	userMap := make(map[string]string)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, localName, ok := strings.Cut(line, "=")
+		name, localName = strings.TrimSpace(name), strings.TrimSpace(localName)
+		if !ok || name == "" || localName == "" {
+			return nil, util.NewInvalidArgumentErrorf("%q isn't a name=local_name mapping", line)
+		}
+		userMap[name] = localName
+	}
+	return userMap, nil
+}
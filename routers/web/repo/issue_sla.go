@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// prepareIssueSLA loads the SLA tracking an issue and its policy for the sidebar
+func prepareIssueSLA(ctx *context.Context, issue *issues_model.Issue) {
+	s, err := issues_model.GetIssueSLA(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueSLA", err)
+		return
+	}
+	if s == nil {
+		return
+	}
+	if err := s.LoadPolicy(ctx); err != nil && !issues_model.IsErrSLAPolicyNotExist(err) {
+		ctx.ServerError("LoadPolicy", err)
+		return
+	}
+	issue.SLA = s
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// prepareSubIssues sets the parent, the sub-issues and the progress of an issue for the sidebar,
+// only the issues the doer can read are listed
+func prepareSubIssues(ctx *context.Context, issue *issues_model.Issue) {
+	readableRepos := make(map[int64]bool)
+	canRead := func(repo *repo_model.Repository) bool {
+		if repo.ID == ctx.Repo.Repository.ID {
+			return ctx.Repo.CanReadIssuesOrPulls(false)
+		}
+		if readable, ok := readableRepos[repo.ID]; ok {
+			return readable
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return false
+		}
+		readableRepos[repo.ID] = perm.CanReadIssuesOrPulls(false)
+		return readableRepos[repo.ID]
+	}
+
+	parent, err := issues_model.GetParentIssue(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetParentIssue", err)
+		return
+	}
+	if parent != nil {
+		if err := parent.LoadRepo(ctx); err != nil {
+			ctx.ServerError("LoadRepo", err)
+			return
+		}
+		if canRead(parent.Repo) {
+			ctx.Data["ParentIssue"] = parent
+		}
+		if ctx.Written() {
+			return
+		}
+	}
+
+	subIssues, err := issues_model.GetSubIssues(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetSubIssues", err)
+		return
+	}
+	if _, err := subIssues.LoadRepositories(ctx); err != nil {
+		ctx.ServerError("LoadRepositories", err)
+		return
+	}
+	permitted := make(issues_model.IssueList, 0, len(subIssues))
+	for _, subIssue := range subIssues {
+		readable := canRead(subIssue.Repo)
+		if ctx.Written() {
+			return
+		}
+		if readable {
+			permitted = append(permitted, subIssue)
+		}
+	}
+	ctx.Data["SubIssues"] = permitted
+	ctx.Data["SubIssuesNotPermitted"] = len(subIssues) - len(permitted)
+
+	if len(subIssues) > 0 {
+		repoIDs, err := issues_model.GetSubIssueRepoIDs(ctx, issue.ID)
+		if err != nil {
+			ctx.ServerError("GetSubIssueRepoIDs", err)
+			return
+		}
+		repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+		if err != nil {
+			ctx.ServerError("GetRepositoriesMapByIDs", err)
+			return
+		}
+		readableRepoIDs := make([]int64, 0, len(repos))
+		for _, repo := range repos {
+			readable := canRead(repo)
+			if ctx.Written() {
+				return
+			}
+			if readable {
+				readableRepoIDs = append(readableRepoIDs, repo.ID)
+			}
+		}
+		// the progress only counts the sub-issues listed to the doer
+		progress, err := issues_model.GetSubIssueProgress(ctx, issue.ID, readableRepoIDs)
+		if err != nil {
+			ctx.ServerError("GetSubIssueProgress", err)
+			return
+		}
+		if progress.Total > 0 {
+			ctx.Data["SubIssueProgress"] = progress
+		}
+	}
+
+	canEdit := ctx.IsSigned && ctx.Repo.CanWriteIssuesOrPulls(false)
+	ctx.Data["CanEditSubIssues"] = canEdit
+	if canEdit {
+		ctx.Data["OpenTasks"] = issue_service.OpenTasks(issue.Content)
+	}
+}
+
+// AddSubIssue makes an issue a sub-issue of the current issue
+func AddSubIssue(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	defer ctx.Redirect(issue.Link())
+
+	subIssue, err := issues_model.GetIssueByID(ctx, ctx.FormInt64("newSubIssue"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_not_exist"))
+			return
+		}
+		ctx.ServerError("GetIssueByID", err)
+		return
+	}
+	if subIssue.IsPull {
+		ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_pull"))
+		return
+	}
+	if subIssue.RepoID != issue.RepoID {
+		if err := subIssue.LoadRepo(ctx); err != nil {
+			ctx.ServerError("LoadRepo", err)
+			return
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, subIssue.Repo, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if !perm.CanReadIssuesOrPulls(false) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_not_exist"))
+			return
+		}
+	}
+
+	if err := issue_service.AddSubIssue(ctx, ctx.Doer, issue, subIssue); err != nil {
+		switch {
+		case issues_model.IsErrSubIssueExists(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_has_parent"))
+		case issues_model.IsErrCircularSubIssue(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_circular"))
+		case issues_model.IsErrSubIssueNotSameOwner(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.sub_issue.add_error_not_same_owner"))
+		default:
+			ctx.ServerError("AddSubIssue", err)
+		}
+	}
+}
+
+// RemoveSubIssue removes an issue from the sub-issues of the current issue
+func RemoveSubIssue(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	subIssue, err := issues_model.GetIssueByID(ctx, ctx.FormInt64("id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", issues_model.IsErrIssueNotExist, err)
+		return
+	}
+	if err := issue_service.RemoveSubIssue(ctx, ctx.Doer, issue, subIssue); err != nil {
+		ctx.NotFoundOrServerError("RemoveSubIssue", issues_model.IsErrSubIssueNotExist, err)
+		return
+	}
+
+	ctx.JSONRedirect(issue.Link())
+}
+
+// ConvertTaskToSubIssue creates a sub-issue from a task of the current issue
+func ConvertTaskToSubIssue(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsPull {
+		ctx.NotFound("ConvertTaskToSubIssue", nil)
+		return
+	}
+
+	subIssue, err := issue_service.ConvertTaskToSubIssue(ctx, ctx.Doer, issue, ctx.FormInt("task"))
+	if err != nil {
+		ctx.NotFoundOrServerError("ConvertTaskToSubIssue", func(err error) bool {
+			return errors.Is(err, util.ErrNotExist)
+		}, err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.sub_issue.convert_success", subIssue.Index))
+	ctx.Redirect(issue.Link())
+}
@@ -9,12 +9,18 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/web/shared/timetracking"
 	"code.gitea.io/gitea/services/forms"
 )
 
+const tplTimes base.TplName = "repo/times"
+
 // AddTimeManually tracks time manually
 func AddTimeManually(c *context.Context) {
 	form := web.GetForm(c).(*forms.AddTimeManuallyForm)
@@ -85,3 +91,71 @@ func DeleteTime(c *context.Context) {
 	c.Flash.Success(c.Tr("repo.issues.del_time_history", util.SecToTime(t.Time)))
 	c.Redirect(issue.Link())
 }
+
+// UpdateTimeEstimate sets or removes the time estimate of an issue
+func UpdateTimeEstimate(c *context.Context) {
+	form := web.GetForm(c).(*forms.TimeEstimateForm)
+	issue := GetActionIssue(c)
+	if c.Written() {
+		return
+	}
+	if !c.Repo.Repository.IsTimetrackerEnabled(c) || !c.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		c.NotFound("CanWriteIssuesOrPulls", nil)
+		return
+	}
+
+	if c.HasError() {
+		c.Flash.Error(c.GetErrMsg())
+		c.Redirect(issue.Link())
+		return
+	}
+
+	estimate := time.Duration(form.Hours)*time.Hour + time.Duration(form.Minutes)*time.Minute
+	if err := issues_model.UpdateIssueTimeEstimate(c, issue, int64(estimate.Seconds()), c.Doer); err != nil {
+		c.ServerError("UpdateIssueTimeEstimate", err)
+		return
+	}
+
+	c.Redirect(issue.Link(), http.StatusSeeOther)
+}
+
+// Times shows the report and the timesheet of the times tracked on the issues of a repository
+func Times(ctx *context.Context) {
+	if !ctx.Repo.Repository.IsTimetrackerEnabled(ctx) {
+		ctx.NotFound("IsTimetrackerEnabled", nil)
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("repo.times")
+	ctx.Data["PageIsTimes"] = true
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, ctx.Repo.Repository.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetLabelsByRepoID", err)
+		return
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		orgLabels, err := issues_model.GetLabelsByOrgID(ctx, ctx.Repo.Owner.ID, "", db.ListOptions{})
+		if err != nil {
+			ctx.ServerError("GetLabelsByOrgID", err)
+			return
+		}
+		labels = append(labels, orgLabels...)
+	}
+	ctx.Data["Labels"] = labels
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		RepoID: ctx.Repo.Repository.ID,
+		State:  api.StateAll,
+	})
+	if err != nil {
+		ctx.ServerError("GetMilestones", err)
+		return
+	}
+	ctx.Data["Milestones"] = milestones
+
+	timetracking.Times(ctx, timetracking.Options{
+		Find:       &issues_model.FindTrackedTimesOptions{RepositoryID: ctx.Repo.Repository.ID},
+		CanViewAll: ctx.Doer.IsAdmin || ctx.IsUserRepoWriter([]unit.Type{unit.TypeIssues}),
+		Name:       ctx.Repo.Repository.Name,
+		Template:   tplTimes,
+	})
+}
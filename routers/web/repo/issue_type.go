@@ -0,0 +1,55 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// issueTypeFieldValues are the values of a field of the type of an issue, as displayed in the sidebar
+type issueTypeFieldValues struct {
+	Label  string
+	Values []string
+}
+
+// prepareIssueType loads the type of an issue and the values of its fields for the sidebar
+func prepareIssueType(ctx *context.Context, issue *issues_model.Issue) {
+	if err := issue.LoadType(ctx); err != nil {
+		ctx.ServerError("LoadType", err)
+		return
+	}
+	if issue.Type == nil {
+		return
+	}
+	ctx.Data["ViewIssueType"] = issue.Type
+
+	fields, err := issue.Type.Fields()
+	if err != nil {
+		// the template was valid when saved, don't break the issue page for it
+		return
+	}
+	values, err := issues_model.GetIssueFieldValues(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueFieldValues", err)
+		return
+	}
+
+	fieldValues := make([]*issueTypeFieldValues, 0, len(fields))
+	for _, field := range fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown || len(values[field.ID]) == 0 {
+			continue
+		}
+		label, _ := field.Attributes["label"].(string)
+		if label == "" {
+			label = field.ID
+		}
+		fieldValues = append(fieldValues, &issueTypeFieldValues{
+			Label:  label,
+			Values: values[field.ID],
+		})
+	}
+	ctx.Data["IssueTypeFieldValues"] = fieldValues
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	admin_model "code.gitea.io/gitea/models/admin"
@@ -202,6 +203,14 @@ func MigratePost(ctx *context.Context) {
 		}
 	}
 
+	if form.SyncInterval != "" && !form.Mirror {
+		if interval, err := time.ParseDuration(form.SyncInterval); err != nil || interval < setting.Mirror.MinInterval {
+			ctx.Data["Err_SyncInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.migrate_options_sync_interval.invalid", setting.Mirror.MinInterval), tpl, &form)
+			return
+		}
+	}
+
 	opts := migrations.MigrateOptions{
 		OriginalURL:    form.CloneAddr,
 		GitServiceType: form.Service,
@@ -222,8 +231,10 @@ func MigratePost(ctx *context.Context) {
 		Comments:       form.Issues || form.PullRequests,
 		PullRequests:   form.PullRequests,
 		Releases:       form.Releases,
+		SyncInterval:   form.SyncInterval,
 	}
 	if opts.Mirror {
+		opts.SyncInterval = ""
 		opts.Issues = false
 		opts.Milestones = false
 		opts.Labels = false
@@ -253,6 +264,8 @@ func setMigrationContextData(ctx *context.Context, serviceType structs.GitServic
 	ctx.Data["LFSActive"] = setting.LFS.StartServer
 	ctx.Data["IsForcedPrivate"] = setting.Repository.ForcePrivate
 	ctx.Data["DisableNewPullMirrors"] = setting.Mirror.DisableNewPull
+	ctx.Data["SupportsMigrationSync"] = serviceType != structs.PlainGitService
+	ctx.Data["MinimumMirrorInterval"] = setting.Mirror.MinInterval
 
 	// Plain git should be first
 	ctx.Data["Services"] = append([]structs.GitServiceType{structs.PlainGitService}, structs.SupportedFullGitService...)
@@ -6,6 +6,7 @@ package repo
 import (
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -263,7 +264,7 @@ func DeleteMilestone(ctx *context.Context) {
 func MilestoneIssuesAndPulls(ctx *context.Context) {
 	milestoneID := ctx.ParamsInt64(":id")
 	projectID := ctx.FormInt64("project")
-	milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+	milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 	if err != nil {
 		if issues_model.IsErrMilestoneNotExist(err) {
 			ctx.NotFound("GetMilestoneByID", err)
@@ -273,6 +274,10 @@ func MilestoneIssuesAndPulls(ctx *context.Context) {
 		ctx.ServerError("GetMilestoneByID", err)
 		return
 	}
+	if milestone.BelongsToOrg() {
+		ctx.Redirect(ctx.Repo.Owner.HomeLink() + "/-/milestones/" + strconv.FormatInt(milestone.ID, 10))
+		return
+	}
 
 	milestone.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
 		URLPrefix: ctx.Repo.RepoLink,
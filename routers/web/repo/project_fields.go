@@ -0,0 +1,103 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	shared_project "code.gitea.io/gitea/routers/web/shared/project"
+)
+
+const tplProjectFields base.TplName = "repo/projects/fields"
+
+func projectFieldsLink(ctx *context.Context, project *project_model.Project) string {
+	return fmt.Sprintf("%s/projects/%d/fields", ctx.Repo.RepoLink, project.ID)
+}
+
+// ProjectFields renders the page managing the fields of a project
+func ProjectFields(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.SetFieldsContext(ctx, project)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["IsProjectsPage"] = true
+	ctx.Data["ProjectLink"] = projectLink(ctx, project)
+	ctx.HTML(http.StatusOK, tplProjectFields)
+}
+
+// NewProjectFieldPost creates a field of a project
+func NewProjectFieldPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.NewFieldPost(ctx, project, projectFieldsLink(ctx, project))
+}
+
+// EditProjectFieldPost edits a field of a project
+func EditProjectFieldPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.EditFieldPost(ctx, project, projectFieldsLink(ctx, project))
+}
+
+// DeleteProjectFieldPost deletes a field of a project
+func DeleteProjectFieldPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.DeleteFieldPost(ctx, project, projectFieldsLink(ctx, project))
+}
+
+// NewProjectFieldOptionPost adds an option to a field of a project
+func NewProjectFieldOptionPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.NewFieldOptionPost(ctx, project, projectFieldsLink(ctx, project))
+}
+
+// DeleteProjectFieldOptionPost deletes an option of a field of a project
+func DeleteProjectFieldOptionPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.DeleteFieldOptionPost(ctx, project, projectFieldsLink(ctx, project))
+}
+
+// UpdateProjectItemFieldsPost sets the values of the fields of a project for one of its issues from the board
+func UpdateProjectItemFieldsPost(ctx *context.Context) {
+	project := shared_project.GetProject(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+	shared_project.UpdateIssueFieldsPost(ctx, project, ctx.ParamsInt64(":issueID"), fmt.Sprintf("%s/projects/%d", ctx.Repo.RepoLink, project.ID))
+}
+
+// UpdateIssueProjectFields sets the values of the fields of the project of an issue from its sidebar
+func UpdateIssueProjectFields(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.Project == nil {
+		ctx.NotFound("", nil)
+		return
+	}
+	shared_project.UpdateIssueFieldsPost(ctx, issue.Project, issue.ID, issue.Link())
+}
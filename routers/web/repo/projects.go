@@ -23,7 +23,9 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	shared_project "code.gitea.io/gitea/routers/web/shared/project"
 	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 const (
@@ -318,6 +320,16 @@ func ViewProject(ctx *context.Context) {
 		return
 	}
 
+	shared_project.LoadBoardFields(ctx, project, issuesMap)
+	if ctx.Written() {
+		return
+	}
+	shared_project.LoadViews(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["ProjectLink"] = projectLink(ctx, project)
+
 	if project.CardType != project_model.CardTypeTextOnly {
 		issuesAttachmentMap := make(map[int64][]*attachment_model.Attachment)
 		for _, issuesList := range issuesMap {
@@ -392,7 +404,7 @@ func UpdateIssueProject(ctx *context.Context) {
 			}
 		}
 
-		if err := issues_model.ChangeProjectAssign(issue, ctx.Doer, projectID); err != nil {
+		if err := issue_service.ChangeProjectAssign(ctx, issue, ctx.Doer, projectID); err != nil {
 			ctx.ServerError("ChangeProjectAssign", err)
 			return
 		}
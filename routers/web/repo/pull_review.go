@@ -7,17 +7,22 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"code.gitea.io/gitea/models"
 	issues_model "code.gitea.io/gitea/models/issues"
 	pull_model "code.gitea.io/gitea/models/pull"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
 )
 
 const (
@@ -68,8 +73,9 @@ func CreateCodeComment(ctx *context.Context) {
 		return
 	}
 
-	signedLine := form.Line
+	signedStartLine, signedLine := form.StartLine, form.Line
 	if form.Side == "previous" {
+		signedStartLine *= -1
 		signedLine *= -1
 	}
 
@@ -77,6 +83,7 @@ func CreateCodeComment(ctx *context.Context) {
 		ctx.Doer,
 		ctx.Repo.GitRepo,
 		issue,
+		signedStartLine,
 		signedLine,
 		form.Content,
 		form.TreePath,
@@ -84,7 +91,11 @@ func CreateCodeComment(ctx *context.Context) {
 		form.Reply,
 		form.LatestCommitID,
 	)
-	if err != nil {
+	if errors.Is(err, pull_service.ErrInvalidCommentLineRange) {
+		ctx.Flash.Error(ctx.Tr("repo.diff.comment.invalid_line_range"))
+		ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		return
+	} else if err != nil {
 		ctx.ServerError("CreateCodeComment", err)
 		return
 	}
@@ -104,6 +115,82 @@ func CreateCodeComment(ctx *context.Context) {
 	ctx.Redirect(comment.Link(ctx))
 }
 
+// ApplySuggestions commits the suggested changes of the selected code comments to the head branch
+func ApplySuggestions(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ApplySuggestionsForm)
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+
+	filesLink := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.JSONRedirect(filesLink)
+		return
+	}
+
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		ctx.ServerError("LoadPullRequest", err)
+		return
+	}
+	allowed, err := pull_service.IsUserAllowedToApplySuggestions(ctx, issue.PullRequest, ctx.Doer)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToApplySuggestions", err)
+		return
+	}
+	if !allowed {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	ids, err := base.StringsToInt64s(strings.Split(form.CommentIDs, ","))
+	if err != nil {
+		ctx.Error(http.StatusBadRequest)
+		return
+	}
+	commentIDs := container.SetOf(ids...).Values()
+	if len(commentIDs) == 0 {
+		ctx.Error(http.StatusBadRequest)
+		return
+	}
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IDs:     commentIDs,
+		IssueID: issue.ID,
+		Type:    issues_model.CommentTypeCode,
+	})
+	if err != nil {
+		ctx.ServerError("FindComments", err)
+		return
+	}
+	if len(comments) != len(commentIDs) {
+		ctx.NotFound("FindComments", nil)
+		return
+	}
+
+	if _, err := files_service.ApplySuggestions(ctx, ctx.Doer, issue.PullRequest, comments, form.Message); err != nil {
+		if files_service.IsErrSuggestionNotApplicable(err) {
+			ctx.Flash.Error(ctx.Tr("repo.diff.suggestion.not_applicable", err.(files_service.ErrSuggestionNotApplicable).Reason))
+		} else if models.IsErrCommitIDDoesNotMatch(err) || models.IsErrSHADoesNotMatch(err) || git.IsErrPushOutOfDate(err) {
+			ctx.Flash.Error(ctx.Tr("repo.diff.suggestion.out_of_date"))
+		} else if git.IsErrPushRejected(err) || models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) {
+			ctx.Flash.Error(ctx.Tr("repo.diff.suggestion.rejected"))
+		} else {
+			ctx.ServerError("ApplySuggestions", err)
+			return
+		}
+		ctx.JSONRedirect(filesLink)
+		return
+	}
+
+	ctx.Flash.Success(ctx.TrN(len(comments), "repo.diff.suggestion.applied_1", "repo.diff.suggestion.applied_n", len(comments)))
+	ctx.JSONRedirect(filesLink)
+}
+
 // UpdateResolveConversation add or remove an Conversation resolved mark
 func UpdateResolveConversation(ctx *context.Context) {
 	origin := ctx.FormString("origin")
@@ -179,6 +266,10 @@ func renderConversation(ctx *context.Context, comment *issues_model.Comment) {
 		return
 	}
 	ctx.Data["AfterCommitID"] = pullHeadCommitID
+	if ctx.Data["CanApplySuggestions"], err = pull_service.IsUserAllowedToApplySuggestions(ctx, comment.Issue.PullRequest, ctx.Doer); err != nil {
+		ctx.ServerError("IsUserAllowedToApplySuggestions", err)
+		return
+	}
 	ctx.HTML(http.StatusOK, tplConversation)
 }
 
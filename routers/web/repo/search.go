@@ -4,12 +4,15 @@
 package repo
 
 import (
+	"errors"
 	"net/http"
 
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 )
 
 const tplSearch base.TplName = "repo/search"
@@ -25,13 +28,32 @@ func Search(ctx *context.Context) {
 	keyword := ctx.FormTrim("q")
 
 	queryType := ctx.FormTrim("t")
-	isMatch := queryType == "match"
+	opts := code_indexer.ParseQuery(keyword).ToSearchOptions(language, code_indexer.ParseSearchMode(queryType))
 
 	ctx.Data["Keyword"] = keyword
-	ctx.Data["Language"] = language
+	ctx.Data["Language"] = opts.Language
 	ctx.Data["queryType"] = queryType
 	ctx.Data["PageIsViewCode"] = true
 
+	indexedRefs, err := code_indexer.IndexedRefs(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.ServerError("IndexedRefs", err)
+		return
+	}
+	ctx.Data["IndexedRefs"] = indexedRefs
+	ref := ctx.FormTrim("ref")
+	if ref == ctx.Repo.Repository.DefaultBranch {
+		ref = ""
+	}
+	ctx.Data["SearchRef"] = ref
+	ctx.Data["SearchRefName"] = git.RefNameFromBranch(ctx.Repo.Repository.DefaultBranch)
+	for _, indexedRef := range indexedRefs {
+		if indexedRef.ShortName() == ref {
+			ctx.Data["SearchRefName"] = indexedRef
+			break
+		}
+	}
+
 	if keyword == "" {
 		ctx.HTML(http.StatusOK, tplSearch)
 		return
@@ -42,9 +64,27 @@ func Search(ctx *context.Context) {
 		page = 1
 	}
 
-	total, searchResults, searchResultLanguages, err := code_indexer.PerformSearch(ctx, []int64{ctx.Repo.Repository.ID},
-		language, keyword, page, setting.UI.RepoSearchPagingNum, isMatch)
+	// the repo: operators don't apply, the search is limited to the current repository
+	opts.RepoIDs = []int64{ctx.Repo.Repository.ID}
+	opts.Ref, err = code_indexer.ResolveRef(ctx, ctx.Repo.Repository, ref)
 	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Data["SearchError"] = ctx.Tr("repo.search.invalid_query", err.Error())
+			ctx.HTML(http.StatusOK, tplSearch)
+			return
+		}
+		ctx.ServerError("ResolveRef", err)
+		return
+	}
+	opts.Page = page
+	opts.PageSize = setting.UI.RepoSearchPagingNum
+	total, searchResults, searchResultLanguages, truncated, err := code_indexer.PerformSearch(ctx, opts)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Data["SearchError"] = ctx.Tr("repo.search.invalid_query", err.Error())
+			ctx.HTML(http.StatusOK, tplSearch)
+			return
+		}
 		if code_indexer.IsAvailable(ctx) {
 			ctx.ServerError("SearchResults", err)
 			return
@@ -57,10 +97,13 @@ func Search(ctx *context.Context) {
 	ctx.Data["SourcePath"] = ctx.Repo.Repository.Link()
 	ctx.Data["SearchResults"] = searchResults
 	ctx.Data["SearchResultLanguages"] = searchResultLanguages
+	ctx.Data["SearchResultsTruncated"] = truncated
+	ctx.Data["RegexpMaxCandidates"] = code_indexer.RegexpMaxCandidates
 
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "l", "Language")
+	pager.AddParam(ctx, "ref", "SearchRef")
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(http.StatusOK, tplSearch)
@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/forms"
+	notify_service "code.gitea.io/gitea/services/notify"
 )
 
 // DeployKeys render the deploy keys list of a repository page
@@ -91,6 +92,7 @@ func DeployKeysPost(ctx *context.Context) {
 		}
 		return
 	}
+	notify_service.AddDeployKey(ctx, ctx.Doer, ctx.Repo.Repository, key)
 
 	log.Trace("Deploy key added: %d", ctx.Repo.Repository.ID)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
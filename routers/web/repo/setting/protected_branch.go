@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/web/repo"
 	"code.gitea.io/gitea/services/forms"
+	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 	"code.gitea.io/gitea/services/repository"
 
@@ -233,6 +234,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 	protectBranch.UnprotectedFilePatterns = f.UnprotectedFilePatterns
 	protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
 
+	isNew := protectBranch.ID == 0
 	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
 		UserIDs:          whitelistUsers,
 		TeamIDs:          whitelistTeams,
@@ -245,6 +247,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 		ctx.ServerError("UpdateProtectBranch", err)
 		return
 	}
+	notify_service.UpdateProtectedBranch(ctx, ctx.Doer, ctx.Repo.Repository, protectBranch, isNew)
 
 	// FIXME: since we only need to recheck files protected rules, we could improve this
 	matchedBranches, err := git_model.FindAllMatchedBranches(ctx, ctx.Repo.Repository.ID, protectBranch.RuleName)
@@ -290,6 +293,7 @@ func DeleteProtectedBranchRulePost(ctx *context.Context) {
 		ctx.JSONRedirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
 		return
 	}
+	notify_service.DeleteProtectedBranch(ctx, ctx.Doer, ctx.Repo.Repository, rule)
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.remove_protected_branch_success", rule.RuleName))
 	ctx.JSONRedirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
@@ -5,6 +5,7 @@
 package setting
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -34,8 +35,11 @@ import (
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	notify_service "code.gitea.io/gitea/services/notify"
 	repo_service "code.gitea.io/gitea/services/repository"
 	wiki_service "code.gitea.io/gitea/services/wiki"
+
+	"github.com/gobwas/glob"
 )
 
 const (
@@ -72,6 +76,12 @@ func SettingsCtxData(ctx *context.Context) {
 				return
 			}
 			ctx.Data["CodeIndexerStatus"] = status
+			refStatuses, err := repo_model.GetCodeIndexerRefStatuses(ctx, ctx.Repo.Repository.ID)
+			if err != nil {
+				ctx.ServerError("repo.indexer_status", err)
+				return
+			}
+			ctx.Data["CodeIndexerRefStatuses"] = refStatuses
 		}
 		status, err := repo_model.GetIndexerStatus(ctx, ctx.Repo.Repository, repo_model.RepoIndexerTypeStats)
 		if err != nil {
@@ -86,6 +96,27 @@ func SettingsCtxData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["PushMirrors"] = pushMirrors
+
+	pushMirrorFailures, err := repo_model.GetPushMirrorFailures(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetPushMirrorFailures", err)
+		return
+	}
+	ctx.Data["PushMirrorFailures"] = pushMirrorFailures
+
+	partialMirrors, err := repo_model.GetPartialMirrorsByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetPartialMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PartialMirrors"] = partialMirrors
+
+	migrationSync, err := repo_model.GetMigrationSyncByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.ServerError("GetMigrationSyncByRepoID", err)
+		return
+	}
+	ctx.Data["MigrationSync"] = migrationSync
 }
 
 // Settings show a repository's settings page
@@ -93,6 +124,22 @@ func Settings(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
+// parseCodeIndexerPatterns parses a comma separated list of branch or tag glob patterns
+func parseCodeIndexerPatterns(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return nil, fmt.Errorf("%s: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
 // SettingsPost response for changes of a repository
 func SettingsPost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.RepoSettingForm)
@@ -179,6 +226,9 @@ func SettingsPost(ctx *context.Context) {
 			ctx.ServerError("UpdateRepository", err)
 			return
 		}
+		if visibilityChanged {
+			notify_service.ChangeRepositoryVisibility(ctx, ctx.Doer, repo)
+		}
 		log.Trace("Repository basic settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
 
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
@@ -321,17 +371,22 @@ func SettingsPost(ctx *context.Context) {
 			return
 		}
 
-		id, err := strconv.ParseInt(form.PushMirrorID, 10, 64)
-		if err != nil {
-			ctx.ServerError("UpdatePushMirrorIntervalPushMirrorID", err)
+		if err := repo_model.ValidateRefPatterns(form.PushMirrorRefIncludes + "\n" + form.PushMirrorRefExcludes); err != nil {
+			ctx.RenderWithErr(ctx.Tr("repo.settings.mirror_settings.push_mirror.ref_patterns_invalid", err.Error()), tplSettingsOptions, &forms.RepoSettingForm{})
 			return
 		}
-		m := &repo_model.PushMirror{
-			ID:       id,
-			Interval: interval,
+
+		m, err := selectPushMirrorByForm(ctx, form, repo)
+		if err != nil {
+			ctx.NotFound("", nil)
+			return
 		}
-		if err := repo_model.UpdatePushMirrorInterval(ctx, m); err != nil {
-			ctx.ServerError("UpdatePushMirrorInterval", err)
+		m.Interval = interval
+		m.RefIncludes = form.PushMirrorRefIncludes
+		m.RefExcludes = form.PushMirrorRefExcludes
+		m.NonDestructive = form.PushMirrorNonDestructive
+		if err := repo_model.UpdatePushMirrorSettings(ctx, m); err != nil {
+			ctx.ServerError("UpdatePushMirrorSettings", err)
 			return
 		}
 		// Background why we are adding it to Queue
@@ -388,6 +443,12 @@ func SettingsPost(ctx *context.Context) {
 			return
 		}
 
+		if err := repo_model.ValidateRefPatterns(form.PushMirrorRefIncludes + "\n" + form.PushMirrorRefExcludes); err != nil {
+			ctx.Data["Err_PushMirrorRefPatterns"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.mirror_settings.push_mirror.ref_patterns_invalid", err.Error()), tplSettingsOptions, &form)
+			return
+		}
+
 		address, err := forms.ParseRemoteAddr(form.PushMirrorAddress, form.PushMirrorUsername, form.PushMirrorPassword)
 		if err == nil {
 			err = migrations.IsMigrateURLAllowed(address, ctx.Doer)
@@ -411,12 +472,15 @@ func SettingsPost(ctx *context.Context) {
 		}
 
 		m := &repo_model.PushMirror{
-			RepoID:        repo.ID,
-			Repo:          repo,
-			RemoteName:    fmt.Sprintf("remote_mirror_%s", remoteSuffix),
-			SyncOnCommit:  form.PushMirrorSyncOnCommit,
-			Interval:      interval,
-			RemoteAddress: remoteAddress,
+			RepoID:         repo.ID,
+			Repo:           repo,
+			RemoteName:     fmt.Sprintf("remote_mirror_%s", remoteSuffix),
+			SyncOnCommit:   form.PushMirrorSyncOnCommit,
+			Interval:       interval,
+			RemoteAddress:  remoteAddress,
+			RefIncludes:    form.PushMirrorRefIncludes,
+			RefExcludes:    form.PushMirrorRefExcludes,
+			NonDestructive: form.PushMirrorNonDestructive,
 		}
 		if err := repo_model.InsertPushMirror(ctx, m); err != nil {
 			ctx.ServerError("InsertPushMirror", err)
@@ -434,6 +498,189 @@ func SettingsPost(ctx *context.Context) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "partial-mirror-sync":
+		if !setting.Mirror.Enabled {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		m, err := selectPartialMirrorByForm(ctx, form, repo)
+		if err != nil {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		mirror_service.AddPartialMirrorToQueue(m.ID)
+
+		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "partial-mirror-remove":
+		if !setting.Mirror.Enabled {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		// This section doesn't require repo_name/RepoName to be set in the form, don't show it
+		// as an error on the UI for this action
+		ctx.Data["Err_RepoName"] = nil
+
+		m, err := selectPartialMirrorByForm(ctx, form, repo)
+		if err != nil {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		if err := mirror_service.RemovePartialMirror(ctx, m); err != nil {
+			ctx.ServerError("RemovePartialMirror", err)
+			return
+		}
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "partial-mirror-add":
+		if setting.Mirror.DisableNewPull || repo.IsMirror || repo.IsArchived {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		// This section doesn't require repo_name/RepoName to be set in the form, don't show it
+		// as an error on the UI for this action
+		ctx.Data["Err_RepoName"] = nil
+
+		interval, err := time.ParseDuration(form.PartialMirrorInterval)
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Data["Err_PartialMirrorInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		if _, err := repo_model.ParseBranchMappings(form.PartialMirrorBranches); err != nil {
+			ctx.Data["Err_PartialMirrorBranches"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.mirror_settings.partial_mirror.branches_invalid", err.Error()), tplSettingsOptions, &form)
+			return
+		}
+
+		divergence := repo_model.DivergenceStrategy(form.PartialMirrorDivergence)
+		if !divergence.IsValid() {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		address, err := forms.ParseRemoteAddr(form.PartialMirrorAddress, form.PartialMirrorUsername, form.PartialMirrorPassword)
+		if err == nil {
+			err = migrations.IsMigrateURLAllowed(address, ctx.Doer)
+		}
+		if err != nil {
+			ctx.Data["Err_PartialMirrorAddress"] = true
+			handleSettingRemoteAddrError(ctx, err, form)
+			return
+		}
+
+		if form.PartialMirrorLFSEndpoint != "" {
+			ep := lfs.DetermineEndpoint("", form.PartialMirrorLFSEndpoint)
+			if ep == nil {
+				ctx.Data["Err_PartialMirrorLFSEndpoint"] = true
+				ctx.RenderWithErr(ctx.Tr("repo.migrate.invalid_lfs_endpoint"), tplSettingsOptions, &form)
+				return
+			}
+			if err := migrations.IsMigrateURLAllowed(ep.String(), ctx.Doer); err != nil {
+				ctx.Data["Err_PartialMirrorLFSEndpoint"] = true
+				handleSettingRemoteAddrError(ctx, err, form)
+				return
+			}
+		}
+
+		remoteSuffix, err := util.CryptoRandomString(10)
+		if err != nil {
+			ctx.ServerError("RandomString", err)
+			return
+		}
+
+		remoteAddress, err := util.SanitizeURL(form.PartialMirrorAddress)
+		if err != nil {
+			ctx.ServerError("SanitizeURL", err)
+			return
+		}
+
+		m := &repo_model.PartialMirror{
+			RepoID:         repo.ID,
+			Repo:           repo,
+			CreatorID:      ctx.Doer.ID,
+			RemoteName:     fmt.Sprintf("partial_mirror_%s", remoteSuffix),
+			RemoteAddress:  remoteAddress,
+			BranchMappings: strings.TrimSpace(form.PartialMirrorBranches),
+			Divergence:     divergence,
+			LFS:            form.PartialMirrorLFS && setting.LFS.StartServer,
+			LFSEndpoint:    form.PartialMirrorLFSEndpoint,
+			Interval:       interval,
+		}
+		if err := repo_model.InsertPartialMirror(ctx, m); err != nil {
+			ctx.ServerError("InsertPartialMirror", err)
+			return
+		}
+
+		if err := mirror_service.AddPartialMirrorRemote(ctx, m, address); err != nil {
+			if err := repo_model.DeletePartialMirror(ctx, m.RepoID, m.ID); err != nil {
+				log.Error("DeletePartialMirror %v", err)
+			}
+			ctx.ServerError("AddPartialMirrorRemote", err)
+			return
+		}
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "migration-sync":
+		m, err := repo_model.GetMigrationSyncByRepoID(ctx, repo.ID)
+		if err != nil {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		migrations.AddMigrationSyncToQueue(m.ID)
+
+		ctx.Flash.Info(ctx.Tr("repo.settings.migration_sync.in_progress"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "migration-sync-update":
+		// This section doesn't require repo_name/RepoName to be set in the form, don't show it
+		// as an error on the UI for this action
+		ctx.Data["Err_RepoName"] = nil
+
+		m, err := repo_model.GetMigrationSyncByRepoID(ctx, repo.ID)
+		if err != nil {
+			ctx.NotFound("", nil)
+			return
+		}
+
+		interval, err := time.ParseDuration(form.MigrationSyncInterval)
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Data["Err_MigrationSyncInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		m.Interval = interval
+		m.ScheduleNextSync()
+		if err := repo_model.UpdateMigrationSync(ctx, m); err != nil {
+			ctx.ServerError("UpdateMigrationSync", err)
+			return
+		}
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "migration-sync-remove":
+		if err := repo_model.DeleteMigrationSync(ctx, repo.ID); err != nil {
+			ctx.ServerError("DeleteMigrationSync", err)
+			return
+		}
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
 		var repoChanged bool
 		var units []repo_model.RepoUnit
@@ -609,6 +856,37 @@ func SettingsPost(ctx *context.Context) {
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
 
+	case "code_indexer":
+		if !setting.Indexer.RepoIndexerEnabled {
+			ctx.Error(http.StatusForbidden)
+			return
+		}
+
+		branches, err := parseCodeIndexerPatterns(form.CodeIndexerBranches)
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.code_indexer_invalid_pattern", err.Error()))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+		tags, err := parseCodeIndexerPatterns(form.CodeIndexerTags)
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.settings.code_indexer_invalid_pattern", err.Error()))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+			return
+		}
+
+		repo.CodeIndexerBranches, repo.CodeIndexerTags = branches, tags
+		if err := repo_model.UpdateRepositoryCols(ctx, repo, "code_indexer_branches", "code_indexer_tags"); err != nil {
+			ctx.ServerError("UpdateRepositoryCols", err)
+			return
+		}
+		// index the new branches and tags, and remove the ones not matched anymore
+		code.UpdateRepoIndexer(repo)
+		log.Trace("Repository code search settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+
 	case "signing":
 		changed := false
 		trustModel := repo_model.ToTrustModel(form.TrustModel)
@@ -956,3 +1234,20 @@ func selectPushMirrorByForm(ctx *context.Context, form *forms.RepoSettingForm, r
 
 	return nil, fmt.Errorf("PushMirror[%v] not associated to repository %v", id, repo)
 }
+
+func selectPartialMirrorByForm(ctx *context.Context, form *forms.RepoSettingForm, repo *repo_model.Repository) (*repo_model.PartialMirror, error) {
+	id, err := strconv.ParseInt(form.PartialMirrorID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := repo_model.GetPartialMirrorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.RepoID != repo.ID {
+		return nil, fmt.Errorf("PartialMirror[%v] not associated to repository %v", id, repo)
+	}
+	m.Repo = repo
+	return m, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	sla_service "code.gitea.io/gitea/services/sla"
+)
+
+const (
+	tplSLA base.TplName = "repo/settings/sla"
+)
+
+// SLAPolicies render the page of the SLA policies of the issues
+func SLAPolicies(ctx *context.Context) {
+	if setSLAContext(ctx) != nil {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplSLA)
+}
+
+// NewSLAPolicyPost handles the creation of a SLA policy
+func NewSLAPolicyPost(ctx *context.Context) {
+	if setSLAContext(ctx) != nil {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSLA)
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.SLAPolicyForm)
+	p := &issues_model.SLAPolicy{RepoID: ctx.Repo.Repository.ID}
+	if !applySLAPolicyForm(ctx, form, p) {
+		return
+	}
+
+	if err := sla_service.CreatePolicy(ctx, p); err != nil {
+		ctx.ServerError("CreatePolicy", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/sla")
+}
+
+// EditSLAPolicy render the page to edit a SLA policy
+func EditSLAPolicy(ctx *context.Context) {
+	if setSLAContext(ctx) != nil {
+		return
+	}
+
+	p := getSLAPolicy(ctx)
+	if p == nil {
+		return
+	}
+
+	ctx.Data["PageIsEditSLAPolicy"] = true
+	ctx.Data["name"] = p.Name
+	ctx.Data["label_id"] = p.LabelID
+	ctx.Data["first_response"] = issues_model.FormatSLADuration(p.FirstResponseSeconds)
+	ctx.Data["resolve"] = issues_model.FormatSLADuration(p.ResolveSeconds)
+	ctx.Data["notify_assignees"] = p.NotifyAssignees
+	ctx.Data["escalate_users"] = strings.Join(base.Int64sToStrings(p.EscalateUserIDs), ",")
+	ctx.Data["escalate_teams"] = strings.Join(base.Int64sToStrings(p.EscalateTeamIDs), ",")
+
+	ctx.HTML(http.StatusOK, tplSLA)
+}
+
+// EditSLAPolicyPost handles the update of a SLA policy
+func EditSLAPolicyPost(ctx *context.Context) {
+	if setSLAContext(ctx) != nil {
+		return
+	}
+
+	ctx.Data["PageIsEditSLAPolicy"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSLA)
+		return
+	}
+
+	p := getSLAPolicy(ctx)
+	if p == nil {
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.SLAPolicyForm)
+	if !applySLAPolicyForm(ctx, form, p) {
+		return
+	}
+
+	if err := sla_service.UpdatePolicy(ctx, p); err != nil {
+		ctx.ServerError("UpdatePolicy", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/sla")
+}
+
+// DeleteSLAPolicyPost handles the deletion of a SLA policy
+func DeleteSLAPolicyPost(ctx *context.Context) {
+	p := getSLAPolicy(ctx)
+	if p == nil {
+		return
+	}
+
+	if err := sla_service.DeletePolicy(ctx, p); err != nil {
+		ctx.ServerError("DeletePolicy", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.sla.deletion_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/sla")
+}
+
+// applySLAPolicyForm sets the fields of a policy from the form, it renders the form with an error and returns
+// false if they are invalid
+func applySLAPolicyForm(ctx *context.Context, form *forms.SLAPolicyForm, p *issues_model.SLAPolicy) bool {
+	firstResponse, err := issues_model.ParseSLADuration(form.FirstResponse)
+	if err != nil {
+		ctx.Data["Err_FirstResponse"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.sla.invalid_duration", form.FirstResponse), tplSLA, form)
+		return false
+	}
+	resolve, err := issues_model.ParseSLADuration(form.Resolve)
+	if err != nil {
+		ctx.Data["Err_Resolve"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.sla.invalid_duration", form.Resolve), tplSLA, form)
+		return false
+	}
+	if firstResponse == 0 && resolve == 0 {
+		ctx.Data["Err_FirstResponse"] = true
+		ctx.Data["Err_Resolve"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.sla.no_deadline"), tplSLA, form)
+		return false
+	}
+	if form.LabelID > 0 && !isSLALabel(ctx, form.LabelID) {
+		ctx.RenderWithErr(ctx.Tr("repo.settings.sla.invalid_label"), tplSLA, form)
+		return false
+	}
+
+	p.Name = form.Name
+	p.LabelID = form.LabelID
+	p.FirstResponseSeconds = firstResponse
+	p.ResolveSeconds = resolve
+	p.NotifyAssignees = form.NotifyAssignees
+	p.EscalateUserIDs, _ = base.StringsToInt64s(splitIDs(form.EscalateUsers))
+	p.EscalateTeamIDs = nil
+	if ctx.Repo.Owner.IsOrganization() {
+		p.EscalateTeamIDs, _ = base.StringsToInt64s(splitIDs(form.EscalateTeams))
+	}
+	return true
+}
+
+func splitIDs(ids string) []string {
+	if strings.TrimSpace(ids) == "" {
+		return nil
+	}
+	return strings.Split(ids, ",")
+}
+
+// isSLALabel checks if a label has been loaded by setSLAContext, the labels of the repository and its owner
+func isSLALabel(ctx *context.Context, labelID int64) bool {
+	labels, _ := ctx.Data["Labels"].([]*issues_model.Label)
+	for _, label := range labels {
+		if label.ID == labelID {
+			return true
+		}
+	}
+	return false
+}
+
+func setSLAContext(ctx *context.Context) error {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.sla")
+	ctx.Data["PageIsSettingsSLA"] = true
+
+	policies, err := issues_model.GetSLAPoliciesByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetSLAPoliciesByRepoID", err)
+		return err
+	}
+	for _, p := range policies {
+		if err := p.LoadLabel(ctx); err != nil && !issues_model.IsErrLabelNotExist(err) {
+			ctx.ServerError("LoadLabel", err)
+			return err
+		}
+	}
+	ctx.Data["SLAPolicies"] = policies
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, ctx.Repo.Repository.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetLabelsByRepoID", err)
+		return err
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		orgLabels, err := issues_model.GetLabelsByOrgID(ctx, ctx.Repo.Owner.ID, "", db.ListOptions{})
+		if err != nil {
+			ctx.ServerError("GetLabelsByOrgID", err)
+			return err
+		}
+		labels = append(labels, orgLabels...)
+	}
+	ctx.Data["Labels"] = labels
+
+	users, err := access_model.GetRepoReaders(ctx.Repo.Repository)
+	if err != nil {
+		ctx.ServerError("Repo.Repository.GetReaders", err)
+		return err
+	}
+	ctx.Data["Users"] = users
+
+	if ctx.Repo.Owner.IsOrganization() {
+		teams, err := organization.OrgFromUser(ctx.Repo.Owner).TeamsWithAccessToRepo(ctx.Repo.Repository.ID, perm.AccessModeRead)
+		if err != nil {
+			ctx.ServerError("Repo.Owner.TeamsWithAccessToRepo", err)
+			return err
+		}
+		ctx.Data["Teams"] = teams
+	}
+
+	return nil
+}
+
+func getSLAPolicy(ctx *context.Context) *issues_model.SLAPolicy {
+	id := ctx.FormInt64("id")
+	if id == 0 {
+		id = ctx.ParamsInt64(":id")
+	}
+
+	p, err := issues_model.GetSLAPolicyByID(ctx, ctx.Repo.Repository.ID, id)
+	if err != nil {
+		if issues_model.IsErrSLAPolicyNotExist(err) {
+			ctx.NotFound("GetSLAPolicyByID", err)
+		} else {
+			ctx.ServerError("GetSLAPolicyByID", err)
+		}
+		return nil
+	}
+	return p
+}
@@ -149,6 +149,10 @@ func WebhooksNew(ctx *context.Context) {
 			"Username": "Gitea",
 		}
 	}
+	if hookType == webhook_module.CUSTOM {
+		ctx.Data["CustomHook"] = &webhook_service.CustomMeta{Body: "{{json .Payload}}"}
+		ctx.Data["SampleEvents"] = webhook_service.SampleEvents
+	}
 	ctx.Data["BaseLink"] = orCtx.LinkNew
 
 	ctx.HTML(http.StatusOK, orCtx.NewTemplate)
@@ -182,6 +186,13 @@ func ParseHookEvent(form forms.WebhookForm) *webhook_module.HookEvent {
 			Wiki:                     form.Wiki,
 			Repository:               form.Repository,
 			Package:                  form.Package,
+			WorkflowRun:              form.WorkflowRun,
+			WorkflowJob:              form.WorkflowJob,
+			BranchProtection:         form.BranchProtection,
+			Member:                   form.Member,
+			Membership:               form.Membership,
+			DeployKey:                form.DeployKey,
+			RepositoryVisibility:     form.RepositoryVisibility,
 		},
 		BranchFilter: form.BranchFilter,
 	}
@@ -287,6 +298,10 @@ func editWebhook(ctx *context.Context, params webhookParams) {
 	w.ContentType = params.ContentType
 	w.Secret = params.Secret
 	w.HookEvent = ParseHookEvent(params.WebhookForm)
+	if params.WebhookForm.Active && !w.IsActive {
+		// a webhook activated again starts over counting its failed deliveries
+		w.FailureCount = 0
+	}
 	w.IsActive = params.WebhookForm.Active
 	w.HTTPMethod = params.HTTPMethod
 	w.Meta = string(meta)
@@ -579,6 +594,125 @@ func packagistHookParams(ctx *context.Context) webhookParams {
 	}
 }
 
+// CustomHooksNewPost response for creating custom webhook
+func CustomHooksNewPost(ctx *context.Context) {
+	params := customHookParams(ctx)
+	if web.GetForm(ctx).(*forms.NewCustomHookForm).Preview && !ctx.HasError() {
+		previewCustomHook(ctx, params, true)
+		return
+	}
+	createWebhook(ctx, params)
+}
+
+// CustomHooksEditPost response for editing custom webhook
+func CustomHooksEditPost(ctx *context.Context) {
+	params := customHookParams(ctx)
+	if web.GetForm(ctx).(*forms.NewCustomHookForm).Preview && !ctx.HasError() {
+		previewCustomHook(ctx, params, false)
+		return
+	}
+	editWebhook(ctx, params)
+}
+
+func customHookParams(ctx *context.Context) webhookParams {
+	form := web.GetForm(ctx).(*forms.NewCustomHookForm)
+
+	meta := &webhook_service.CustomMeta{
+		ContentType: form.ContentTypeTemplate,
+		Headers:     form.HeadersTemplate,
+		Body:        form.BodyTemplate,
+	}
+	// the posted templates are shown again when the form is rendered with an error or a preview
+	ctx.Data["CustomHook"] = meta
+	ctx.Data["SampleEvents"] = webhook_service.SampleEvents
+
+	if !ctx.HasError() && !form.Preview {
+		w := &webhook.Webhook{HookEvent: ParseHookEvent(form.WebhookForm)}
+		if err := webhook_service.ValidateCustomMeta(meta, w); err != nil {
+			var tmplErr webhook_service.CustomTemplateError
+			if errors.As(err, &tmplErr) {
+				ctx.Data["Err_"+customTemplateFields[tmplErr.Field]] = true
+			}
+			ctx.Data["HasError"] = true
+			ctx.Data["ErrorMsg"] = ctx.Tr("repo.settings.custom_template_error", err.Error())
+		}
+	}
+
+	return webhookParams{
+		Type:        webhook_module.CUSTOM,
+		URL:         form.PayloadURL,
+		ContentType: webhook.ContentTypeJSON,
+		Secret:      form.Secret,
+		HTTPMethod:  form.HTTPMethod,
+		WebhookForm: form.WebhookForm,
+		Meta:        meta,
+	}
+}
+
+// customTemplateFields maps the templates of a custom webhook to their form fields
+var customTemplateFields = map[string]string{
+	"content_type": "ContentTypeTemplate",
+	"headers":      "HeadersTemplate",
+	"body":         "BodyTemplate",
+}
+
+// previewCustomHook renders the form of a custom webhook with the request rendered for a sample event, the webhook
+// isn't saved
+func previewCustomHook(ctx *context.Context, params webhookParams, isNew bool) {
+	var orCtx *ownerRepoCtx
+	var w *webhook.Webhook
+	ctx.Data["PageIsSettingsHooks"] = true
+	if isNew {
+		ctx.Data["Title"] = ctx.Tr("repo.settings.add_webhook")
+		ctx.Data["PageIsSettingsHooksNew"] = true
+		ctx.Data["HookType"] = params.Type
+
+		var err error
+		orCtx, err = getOwnerRepoCtx(ctx)
+		if err != nil {
+			ctx.ServerError("getOwnerRepoCtx", err)
+			return
+		}
+		ctx.Data["BaseLink"] = orCtx.LinkNew
+		w = &webhook.Webhook{}
+	} else {
+		ctx.Data["Title"] = ctx.Tr("repo.settings.update_webhook")
+		ctx.Data["PageIsSettingsHooksEdit"] = true
+
+		orCtx, w = checkWebhook(ctx)
+		if ctx.Written() {
+			return
+		}
+	}
+
+	w.URL = params.URL
+	w.Secret = params.Secret
+	w.HTTPMethod = params.HTTPMethod
+	w.HookEvent = ParseHookEvent(params.WebhookForm)
+	w.IsActive = params.WebhookForm.Active
+	if err := w.SetHeaderAuthorization(params.WebhookForm.AuthorizationHeader); err != nil {
+		ctx.ServerError("SetHeaderAuthorization", err)
+		return
+	}
+	ctx.Data["Webhook"] = w
+
+	event := webhook_module.HookEventType(web.GetForm(ctx).(*forms.NewCustomHookForm).PreviewEvent)
+	p := webhook_service.SamplePayload(event)
+	if p == nil {
+		event = webhook_module.HookEventPush
+		p = webhook_service.SamplePayload(event)
+	}
+	ctx.Data["PreviewEvent"] = event
+	preview, err := webhook_service.RenderCustomPayload(params.Meta.(*webhook_service.CustomMeta), p, event)
+	if err != nil {
+		ctx.Data["PreviewError"] = err.Error()
+	} else {
+		ctx.Data["Preview"] = preview
+	}
+
+	ctx.HTML(http.StatusOK, orCtx.NewTemplate)
+}
+
 func checkWebhook(ctx *context.Context) (*ownerRepoCtx, *webhook.Webhook) {
 	orCtx, err := getOwnerRepoCtx(ctx)
 	if err != nil {
@@ -616,6 +750,12 @@ func checkWebhook(ctx *context.Context) (*ownerRepoCtx, *webhook.Webhook) {
 		ctx.Data["MatrixHook"] = webhook_service.GetMatrixHook(w)
 	case webhook_module.PACKAGIST:
 		ctx.Data["PackagistHook"] = webhook_service.GetPackagistHook(w)
+	case webhook_module.CUSTOM:
+		// keep the templates posted with an invalid form or a preview instead of the saved ones
+		if _, ok := ctx.Data["CustomHook"]; !ok {
+			ctx.Data["CustomHook"] = webhook_service.GetCustomHook(w)
+		}
+		ctx.Data["SampleEvents"] = webhook_service.SampleEvents
 	}
 
 	ctx.Data["History"], err = w.History(1)
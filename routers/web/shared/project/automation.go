@@ -0,0 +1,153 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	project_service "code.gitea.io/gitea/services/project"
+)
+
+// getAutomationLabels returns the labels the automations of the project can add,
+// the labels of its repository and the ones of the organization owning them
+func getAutomationLabels(ctx *context.Context, project *project_model.Project) ([]*issues_model.Label, error) {
+	labels := make([]*issues_model.Label, 0, 10)
+	ownerID := project.OwnerID
+	if project.RepoID > 0 {
+		if err := project.LoadRepo(ctx); err != nil {
+			return nil, err
+		}
+		repoLabels, err := issues_model.GetLabelsByRepoID(ctx, project.RepoID, "", db.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, repoLabels...)
+		if err := project.Repo.LoadOwner(ctx); err != nil {
+			return nil, err
+		}
+		if !project.Repo.Owner.IsOrganization() {
+			return labels, nil
+		}
+		ownerID = project.Repo.OwnerID
+	}
+	orgLabels, err := issues_model.GetLabelsByOrgID(ctx, ownerID, "", db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return append(labels, orgLabels...), nil
+}
+
+// SetAutomationContext loads the automations of the project to manage them
+func SetAutomationContext(ctx *context.Context, project *project_model.Project) {
+	automations, err := project_model.GetAutomations(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetAutomations", err)
+		return
+	}
+	boards, err := project.GetBoards(ctx)
+	if err != nil {
+		ctx.ServerError("GetBoards", err)
+		return
+	}
+	// the temporary board of the issues without a board can't be chosen
+	if boards[0].ID == 0 {
+		boards = boards[1:]
+	}
+	labels, err := getAutomationLabels(ctx, project)
+	if err != nil {
+		ctx.ServerError("getAutomationLabels", err)
+		return
+	}
+
+	boardsByID := make(map[int64]*project_model.Board, len(boards))
+	for _, board := range boards {
+		boardsByID[board.ID] = board
+	}
+	labelsByID := make(map[int64]*issues_model.Label, len(labels))
+	for _, label := range labels {
+		labelsByID[label.ID] = label
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.projects.automation")
+	ctx.Data["Project"] = project
+	ctx.Data["Automations"] = automations
+	ctx.Data["AutomationEvents"] = project_model.AutomationEvents()
+	ctx.Data["Boards"] = boards
+	ctx.Data["BoardsByID"] = boardsByID
+	ctx.Data["Labels"] = labels
+	ctx.Data["LabelsByID"] = labelsByID
+}
+
+// NewAutomationPost adds an automation to the project
+func NewAutomationPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	form := web.GetForm(ctx).(*forms.ProjectAutomationForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	event, ok := project_model.ParseAutomationEvent(form.Event)
+	if !ok {
+		ctx.Flash.Error(ctx.Tr("repo.projects.automation.invalid_event"))
+		ctx.Redirect(redirect)
+		return
+	}
+	if form.Label > 0 {
+		label, err := issues_model.GetLabelByID(ctx, form.Label)
+		if err != nil && !issues_model.IsErrLabelNotExist(err) {
+			ctx.ServerError("GetLabelByID", err)
+			return
+		}
+		if err := project.LoadRepo(ctx); err != nil {
+			ctx.ServerError("LoadRepo", err)
+			return
+		}
+		if label == nil || !project_service.CanUseLabel(project, label) {
+			ctx.Flash.Error(ctx.Tr("repo.projects.automation.invalid_label"))
+			ctx.Redirect(redirect)
+			return
+		}
+	}
+
+	automation := &project_model.Automation{
+		ProjectID: project.ID,
+		Event:     event,
+		BoardID:   form.Board,
+		LabelID:   form.Label,
+	}
+	if err := project_model.NewAutomation(ctx, automation); err != nil {
+		if !handleFieldError(ctx, err, redirect) {
+			ctx.ServerError("NewAutomation", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.automation.new_success"))
+	ctx.Redirect(redirect)
+}
+
+// DeleteAutomationPost deletes an automation of the project
+func DeleteAutomationPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	automation, err := project_model.GetAutomationByID(ctx, project.ID, ctx.ParamsInt64(":automationID"))
+	if err != nil {
+		if project_model.IsErrProjectAutomationNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetAutomationByID", err)
+		}
+		return
+	}
+	if err := project_model.DeleteAutomation(ctx, automation); err != nil {
+		ctx.ServerError("DeleteAutomation", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.automation.deletion_success"))
+	ctx.JSONRedirect(redirect)
+}
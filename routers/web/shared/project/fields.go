@@ -0,0 +1,303 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// GetProject returns the project of the request, it must belong to the repository,
+// or to the owner when there is no repository
+func GetProject(ctx *context.Context, ownerID, repoID int64) *project_model.Project {
+	project, err := project_model.GetProjectByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if project_model.IsErrProjectNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetProjectByID", err)
+		}
+		return nil
+	}
+	if (repoID > 0 && project.RepoID != repoID) || (repoID == 0 && project.OwnerID != ownerID) {
+		ctx.NotFound("", nil)
+		return nil
+	}
+	return project
+}
+
+// getField returns the field of the request
+func getField(ctx *context.Context, project *project_model.Project) *project_model.Field {
+	field, err := project_model.GetFieldByID(ctx, project.ID, ctx.ParamsInt64(":fieldID"))
+	if err != nil {
+		if project_model.IsErrProjectFieldNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetFieldByID", err)
+		}
+		return nil
+	}
+	return field
+}
+
+// LoadBoardFields loads the fields of the project with the values of the issues of its board.
+// The issues are filtered by the value of a field with the "filter_field" and "filter_value"
+// parameters and sorted by a field with the "sort_field" and "sort_order" parameters.
+func LoadBoardFields(ctx *context.Context, project *project_model.Project, issuesMap map[int64]issues_model.IssueList) {
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFields", err)
+		return
+	}
+	values, err := project_model.GetFieldValues(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFieldValues", err)
+		return
+	}
+
+	filterField := fields.GetField(ctx.FormInt64("filter_field"))
+	filterValue := ctx.FormTrim("filter_value")
+	sortField := fields.GetField(ctx.FormInt64("sort_field"))
+	sortDesc := ctx.FormString("sort_order") == "desc"
+
+	for boardID, issues := range issuesMap {
+		if filterField != nil {
+			filtered := make(issues_model.IssueList, 0, len(issues))
+			for _, issue := range issues {
+				if filterField.MatchValue(values.Get(issue.ID, filterField.ID), filterValue) {
+					filtered = append(filtered, issue)
+				}
+			}
+			issues = filtered
+		}
+		if sortField != nil {
+			issueIDs := make([]int64, 0, len(issues))
+			issuesByID := make(map[int64]*issues_model.Issue, len(issues))
+			for _, issue := range issues {
+				issueIDs = append(issueIDs, issue.ID)
+				issuesByID[issue.ID] = issue
+			}
+			project_model.SortIssueIDsByField(issueIDs, sortField, values, sortDesc)
+			sorted := make(issues_model.IssueList, 0, len(issues))
+			for _, issueID := range issueIDs {
+				sorted = append(sorted, issuesByID[issueID])
+			}
+			issues = sorted
+		}
+		issuesMap[boardID] = issues
+	}
+
+	ctx.Data["ProjectFields"] = fields
+	ctx.Data["ProjectFieldValues"] = values
+	ctx.Data["FilterField"] = filterField
+	ctx.Data["FilterValue"] = filterValue
+	ctx.Data["SortField"] = sortField
+	ctx.Data["SortOrder"] = ctx.FormString("sort_order")
+	// the cards can't be moved while they are not all shown in their order
+	ctx.Data["IsProjectBoardFiltered"] = filterField != nil || sortField != nil
+}
+
+// SetFieldsContext loads the fields of the project to manage them
+func SetFieldsContext(ctx *context.Context, project *project_model.Project) {
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFields", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.projects.fields")
+	ctx.Data["Project"] = project
+	ctx.Data["ProjectFields"] = fields
+	ctx.Data["FieldTypes"] = project_model.FieldTypes()
+}
+
+// handleFieldError shows the errors of the users to them, and returns false for the others
+func handleFieldError(ctx *context.Context, err error, redirect string) bool {
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.Flash.Error(err.Error())
+		ctx.Redirect(redirect)
+		return true
+	}
+	return false
+}
+
+// NewFieldPost creates a field of the project
+func NewFieldPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	form := web.GetForm(ctx).(*forms.ProjectFieldForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	fieldType, ok := project_model.ParseFieldType(form.Type)
+	if !ok {
+		ctx.Flash.Error(ctx.Tr("repo.projects.fields.invalid_type"))
+		ctx.Redirect(redirect)
+		return
+	}
+	field := &project_model.Field{
+		ProjectID: project.ID,
+		Name:      strings.TrimSpace(form.Name),
+		Type:      fieldType,
+		Sorting:   form.Sorting,
+	}
+	if fieldType == project_model.FieldTypeSingleSelect {
+		for _, name := range strings.Split(form.Options, "\n") {
+			if name = strings.TrimSpace(name); name != "" {
+				field.Options = append(field.Options, &project_model.FieldOption{Name: name})
+			}
+		}
+	}
+
+	if err := project_model.NewField(ctx, field); err != nil {
+		if !handleFieldError(ctx, err, redirect) {
+			ctx.ServerError("NewField", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.fields.new_success", field.Name))
+	ctx.Redirect(redirect)
+}
+
+// EditFieldPost renames or moves a field of the project
+func EditFieldPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	form := web.GetForm(ctx).(*forms.ProjectFieldForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	field := getField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	field.Name = strings.TrimSpace(form.Name)
+	field.Sorting = form.Sorting
+	if err := project_model.UpdateField(ctx, field); err != nil {
+		if !handleFieldError(ctx, err, redirect) {
+			ctx.ServerError("UpdateField", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.fields.edit_success", field.Name))
+	ctx.Redirect(redirect)
+}
+
+// DeleteFieldPost deletes a field of the project with its values
+func DeleteFieldPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	field := getField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	if err := project_model.DeleteField(ctx, field); err != nil {
+		ctx.ServerError("DeleteField", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.fields.deletion_success", field.Name))
+	ctx.JSONRedirect(redirect)
+}
+
+// NewFieldOptionPost adds an option or an iteration to a field of the project
+func NewFieldOptionPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	form := web.GetForm(ctx).(*forms.ProjectFieldOptionForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(redirect)
+		return
+	}
+
+	field := getField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	option := &project_model.FieldOption{
+		Name:  strings.TrimSpace(form.Name),
+		Color: form.Color,
+	}
+	if field.Type == project_model.FieldTypeIteration {
+		var err error
+		if option.StartUnix, err = project_model.ParseFieldDate(form.StartDate); err != nil {
+			handleFieldError(ctx, err, redirect)
+			return
+		}
+		if option.EndUnix, err = project_model.ParseFieldDate(form.EndDate); err != nil {
+			handleFieldError(ctx, err, redirect)
+			return
+		}
+	}
+	if err := project_model.NewFieldOption(ctx, field, option); err != nil {
+		if !handleFieldError(ctx, err, redirect) {
+			ctx.ServerError("NewFieldOption", err)
+		}
+		return
+	}
+
+	ctx.Redirect(redirect)
+}
+
+// DeleteFieldOptionPost deletes an option of a field of the project, the values using it are removed
+func DeleteFieldOptionPost(ctx *context.Context, project *project_model.Project, redirect string) {
+	field := getField(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	option := field.Option(ctx.ParamsInt64(":optionID"))
+	if option == nil {
+		ctx.NotFound("", nil)
+		return
+	}
+	if err := project_model.DeleteFieldOption(ctx, option); err != nil {
+		ctx.ServerError("DeleteFieldOption", err)
+		return
+	}
+
+	ctx.JSONRedirect(redirect)
+}
+
+// UpdateIssueFieldsPost sets the values of the fields of the project for an issue,
+// the value of each field is posted as "field_<id>"
+func UpdateIssueFieldsPost(ctx *context.Context, project *project_model.Project, issueID int64, redirect string) {
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFields", err)
+		return
+	}
+	values, err := project_model.GetFieldValues(ctx, project.ID, issueID)
+	if err != nil {
+		ctx.ServerError("GetFieldValues", err)
+		return
+	}
+
+	for _, field := range fields {
+		key := "field_" + strconv.FormatInt(field.ID, 10)
+		if _, ok := ctx.Req.Form[key]; !ok {
+			continue
+		}
+		raw := ctx.FormTrim(key)
+		if raw == field.FormatValue(values.Get(issueID, field.ID)) {
+			continue
+		}
+		if err := project_model.SetFieldValue(ctx, field, issueID, raw); err != nil {
+			if !handleFieldError(ctx, err, redirect) {
+				ctx.ServerError("SetFieldValue", err)
+			}
+			return
+		}
+	}
+
+	ctx.Redirect(redirect)
+}
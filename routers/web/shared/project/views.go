@@ -0,0 +1,431 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+)
+
+// ViewGroup is a group of the issues of a saved view
+type ViewGroup struct {
+	Title  string
+	Color  string
+	Issues issues_model.IssueList
+	// the issues of the group which are scheduled on a roadmap
+	Bars []*RoadmapBar
+}
+
+// RoadmapBar is an issue on a roadmap, its offset and width are percents of the roadmap
+type RoadmapBar struct {
+	Issue  *issues_model.Issue
+	Start  timeutil.TimeStamp
+	End    timeutil.TimeStamp
+	Offset float64
+	Width  float64
+}
+
+// RoadmapMonth is a month in the header of a roadmap
+type RoadmapMonth struct {
+	Title  string
+	Offset float64
+	Width  float64
+}
+
+// LoadViews loads the saved views of the project for its tabs
+func LoadViews(ctx *context.Context, project *project_model.Project) {
+	views, err := project_model.GetViews(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetViews", err)
+		return
+	}
+	ctx.Data["ProjectViews"] = views
+	ctx.Data["ProjectViewTypes"] = project_model.ViewTypes()
+	ctx.Data["ProjectViewGroupKeys"] = project_model.ViewGroupKeys()
+	ctx.Data["ProjectViewSortKeys"] = project_model.ViewSortKeys()
+}
+
+// getView returns the saved view of the request
+func getView(ctx *context.Context, project *project_model.Project) *project_model.View {
+	view, err := project_model.GetViewByID(ctx, project.ID, ctx.ParamsInt64(":viewID"))
+	if err != nil {
+		if project_model.IsErrProjectViewNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetViewByID", err)
+		}
+		return nil
+	}
+	return view
+}
+
+// PrepareView loads the saved view of the request and lays the issues of the boards of the project out for it
+func PrepareView(ctx *context.Context, project *project_model.Project, boards project_model.BoardList, issuesMap map[int64]issues_model.IssueList) *project_model.View {
+	view := getView(ctx, project)
+	if ctx.Written() {
+		return nil
+	}
+	LoadViews(ctx, project)
+	if ctx.Written() {
+		return nil
+	}
+	fields, err := project_model.GetFields(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFields", err)
+		return nil
+	}
+	values, err := project_model.GetFieldValues(ctx, project.ID)
+	if err != nil {
+		ctx.ServerError("GetFieldValues", err)
+		return nil
+	}
+
+	// the issues are listed in the order of the board by default
+	boardOrder := make(map[int64]int, len(boards))
+	issueBoards := make(map[int64]int64)
+	issueBoardTitles := make(map[int64]string)
+	issues := make(issues_model.IssueList, 0, 20)
+	for i, board := range boards {
+		boardOrder[board.ID] = i
+		for _, issue := range issuesMap[board.ID] {
+			issueBoards[issue.ID] = board.ID
+			issueBoardTitles[issue.ID] = board.Title
+			issues = append(issues, issue)
+		}
+	}
+
+	sortViewIssues(issues, view.SortBy, view.SortDesc, func(issue *issues_model.Issue) int {
+		return boardOrder[issueBoards[issue.ID]]
+	})
+	groups := groupViewIssues(ctx, issues, view.GroupBy, boards, issueBoards)
+
+	if view.Type == project_model.ViewTypeRoadmap {
+		var startField *project_model.Field
+		if view.StartFieldID > 0 {
+			startField = fields.GetField(view.StartFieldID)
+		}
+		layoutRoadmap(ctx, groups, startField, values)
+	}
+
+	ctx.Data["Title"] = fmt.Sprintf("%s - %s", view.Name, project.Title)
+	ctx.Data["ProjectView"] = view
+	ctx.Data["ProjectViewGroups"] = groups
+	ctx.Data["ProjectViewIssueBoards"] = issueBoardTitles
+	ctx.Data["ProjectFields"] = fields
+	ctx.Data["ProjectFieldValues"] = values
+	return view
+}
+
+type viewSortKey struct {
+	missing bool
+	s       string
+	n       int64
+}
+
+func (k viewSortKey) compare(o viewSortKey) int {
+	if c := strings.Compare(k.s, o.s); c != 0 {
+		return c
+	}
+	if k.n < o.n {
+		return -1
+	} else if k.n > o.n {
+		return 1
+	}
+	return 0
+}
+
+func viewIssueSortKey(issue *issues_model.Issue, sortBy string, boardOrder func(*issues_model.Issue) int) viewSortKey {
+	switch sortBy {
+	case project_model.ViewKeyTitle:
+		return viewSortKey{s: strings.ToLower(issue.Title)}
+	case project_model.ViewKeyCreated:
+		return viewSortKey{n: int64(issue.CreatedUnix)}
+	case project_model.ViewKeyDeadline:
+		return viewSortKey{missing: issue.DeadlineUnix == 0, n: int64(issue.DeadlineUnix)}
+	case project_model.ViewKeyLabel:
+		key := viewSortKey{missing: len(issue.Labels) == 0}
+		for i, label := range issue.Labels {
+			if name := strings.ToLower(label.Name); i == 0 || name < key.s {
+				key.s = name
+			}
+		}
+		return key
+	case project_model.ViewKeyAssignee:
+		key := viewSortKey{missing: len(issue.Assignees) == 0}
+		for i, assignee := range issue.Assignees {
+			if name := strings.ToLower(assignee.Name); i == 0 || name < key.s {
+				key.s = name
+			}
+		}
+		return key
+	case project_model.ViewKeyMilestone:
+		if issue.Milestone == nil {
+			return viewSortKey{missing: true}
+		}
+		return viewSortKey{s: strings.ToLower(issue.Milestone.Name)}
+	case project_model.ViewKeyBoard:
+		return viewSortKey{n: int64(boardOrder(issue))}
+	}
+	return viewSortKey{}
+}
+
+// sortViewIssues sorts the issues of a view, the issues without a value to sort by are kept last
+func sortViewIssues(issues issues_model.IssueList, sortBy string, desc bool, boardOrder func(*issues_model.Issue) int) {
+	if sortBy == "" {
+		return
+	}
+	keys := make(map[int64]viewSortKey, len(issues))
+	for _, issue := range issues {
+		keys[issue.ID] = viewIssueSortKey(issue, sortBy, boardOrder)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		ki, kj := keys[issues[i].ID], keys[issues[j].ID]
+		if ki.missing || kj.missing {
+			return !ki.missing && kj.missing
+		}
+		if desc {
+			return ki.compare(kj) > 0
+		}
+		return ki.compare(kj) < 0
+	})
+}
+
+// groupViewIssues groups the issues of a view, keeping their order in each group.
+// An issue with several labels or assignees is listed in the group of each of them.
+func groupViewIssues(ctx *context.Context, issues issues_model.IssueList, groupBy string, boards project_model.BoardList, issueBoards map[int64]int64) []*ViewGroup {
+	if groupBy == project_model.ViewKeyBoard {
+		groups := make([]*ViewGroup, 0, len(boards))
+		for _, board := range boards {
+			group := &ViewGroup{Title: board.Title, Color: board.Color}
+			for _, issue := range issues {
+				if issueBoards[issue.ID] == board.ID {
+					group.Issues = append(group.Issues, issue)
+				}
+			}
+			if len(group.Issues) > 0 {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+
+	type groupKey struct {
+		id    int64
+		title string
+		color string
+	}
+	var keysOf func(issue *issues_model.Issue) []groupKey
+	var noneTitle string
+	switch groupBy {
+	case project_model.ViewKeyLabel:
+		noneTitle = ctx.Tr("repo.projects.views.no_label")
+		keysOf = func(issue *issues_model.Issue) []groupKey {
+			keys := make([]groupKey, 0, len(issue.Labels))
+			for _, label := range issue.Labels {
+				keys = append(keys, groupKey{id: label.ID, title: label.Name, color: label.Color})
+			}
+			return keys
+		}
+	case project_model.ViewKeyAssignee:
+		noneTitle = ctx.Tr("repo.projects.views.no_assignee")
+		keysOf = func(issue *issues_model.Issue) []groupKey {
+			keys := make([]groupKey, 0, len(issue.Assignees))
+			for _, assignee := range issue.Assignees {
+				keys = append(keys, groupKey{id: assignee.ID, title: assignee.GetDisplayName()})
+			}
+			return keys
+		}
+	case project_model.ViewKeyMilestone:
+		noneTitle = ctx.Tr("repo.projects.views.no_milestone")
+		keysOf = func(issue *issues_model.Issue) []groupKey {
+			if issue.Milestone == nil {
+				return nil
+			}
+			return []groupKey{{id: issue.Milestone.ID, title: issue.Milestone.Name}}
+		}
+	default:
+		return []*ViewGroup{{Issues: issues}}
+	}
+
+	groupsByID := make(map[int64]*ViewGroup)
+	groups := make([]*ViewGroup, 0, 10)
+	none := &ViewGroup{Title: noneTitle}
+	for _, issue := range issues {
+		keys := keysOf(issue)
+		if len(keys) == 0 {
+			none.Issues = append(none.Issues, issue)
+			continue
+		}
+		for _, key := range keys {
+			group, ok := groupsByID[key.id]
+			if !ok {
+				group = &ViewGroup{Title: key.title, Color: key.color}
+				groupsByID[key.id] = group
+				groups = append(groups, group)
+			}
+			group.Issues = append(group.Issues, issue)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Title) < strings.ToLower(groups[j].Title)
+	})
+	if len(none.Issues) > 0 {
+		groups = append(groups, none)
+	}
+	return groups
+}
+
+// layoutRoadmap places the issues of the groups on a timeline covering the months of their schedules.
+// An issue ends at its due date and starts at the date of the start field, or when it was created.
+// The issues without a due date are listed as unscheduled.
+func layoutRoadmap(ctx *context.Context, groups []*ViewGroup, startField *project_model.Field, values project_model.FieldValueMap) {
+	var first, last timeutil.TimeStamp
+	unscheduled := make(issues_model.IssueList, 0, 10)
+	seen := make(map[int64]bool)
+	for _, group := range groups {
+		for _, issue := range group.Issues {
+			if issue.DeadlineUnix == 0 {
+				if !seen[issue.ID] {
+					seen[issue.ID] = true
+					unscheduled = append(unscheduled, issue)
+				}
+				continue
+			}
+			bar := &RoadmapBar{Issue: issue, Start: issue.CreatedUnix, End: issue.DeadlineUnix}
+			if startField != nil {
+				if v := values.Get(issue.ID, startField.ID); v != nil && v.DateUnix > 0 {
+					bar.Start = v.DateUnix
+				}
+			}
+			bar.Start = min(bar.Start, bar.End)
+			if first == 0 || bar.Start < first {
+				first = bar.Start
+			}
+			last = max(last, bar.End)
+			group.Bars = append(group.Bars, bar)
+		}
+	}
+	ctx.Data["RoadmapUnscheduled"] = unscheduled
+	if first == 0 {
+		return
+	}
+
+	firstTime := first.AsTimeInLocation(setting.DefaultUILocation)
+	lastTime := last.AsTimeInLocation(setting.DefaultUILocation)
+	rangeStart := time.Date(firstTime.Year(), firstTime.Month(), 1, 0, 0, 0, 0, setting.DefaultUILocation)
+	rangeEnd := time.Date(lastTime.Year(), lastTime.Month()+1, 1, 0, 0, 0, 0, setting.DefaultUILocation)
+	total := float64(rangeEnd.Unix() - rangeStart.Unix())
+	percent := func(seconds int64) float64 {
+		return float64(seconds) / total * 100
+	}
+
+	months := make([]*RoadmapMonth, 0, 12)
+	for month := rangeStart; month.Before(rangeEnd); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		months = append(months, &RoadmapMonth{
+			Title:  month.Format("2006-01"),
+			Offset: percent(month.Unix() - rangeStart.Unix()),
+			Width:  percent(next.Unix() - month.Unix()),
+		})
+	}
+	for _, group := range groups {
+		for _, bar := range group.Bars {
+			bar.Offset = percent(int64(bar.Start) - rangeStart.Unix())
+			// keep the bars of the issues due the day they start visible
+			bar.Width = min(max(percent(int64(bar.End-bar.Start)), 1), 100-bar.Offset)
+		}
+	}
+
+	ctx.Data["RoadmapMonths"] = months
+	if now := time.Now(); now.After(rangeStart) && now.Before(rangeEnd) {
+		ctx.Data["RoadmapToday"] = percent(now.Unix() - rangeStart.Unix())
+	}
+}
+
+// fillView sets the view from the posted form, it returns false when the form is invalid
+func fillView(ctx *context.Context, view *project_model.View, redirect string) bool {
+	form := web.GetForm(ctx).(*forms.ProjectViewForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(redirect)
+		return false
+	}
+	viewType, ok := project_model.ParseViewType(form.Type)
+	if !ok {
+		ctx.Flash.Error(ctx.Tr("repo.projects.views.invalid_type"))
+		ctx.Redirect(redirect)
+		return false
+	}
+	view.Name = strings.TrimSpace(form.Name)
+	view.Type = viewType
+	view.Sorting = form.Sorting
+	view.GroupBy = form.GroupBy
+	view.SortBy = form.SortBy
+	view.SortDesc = form.SortOrder == "desc"
+	view.StartFieldID = form.StartField
+	return true
+}
+
+// NewViewPost saves a view of the project and shows it
+func NewViewPost(ctx *context.Context, project *project_model.Project, projectLink string) {
+	view := &project_model.View{ProjectID: project.ID}
+	if !fillView(ctx, view, projectLink) {
+		return
+	}
+	if err := project_model.NewView(ctx, view); err != nil {
+		if !handleFieldError(ctx, err, projectLink) {
+			ctx.ServerError("NewView", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.views.new_success", view.Name))
+	ctx.Redirect(fmt.Sprintf("%s/views/%d", projectLink, view.ID))
+}
+
+// EditViewPost changes a saved view of the project
+func EditViewPost(ctx *context.Context, project *project_model.Project, projectLink string) {
+	view := getView(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	viewLink := fmt.Sprintf("%s/views/%d", projectLink, view.ID)
+	if !fillView(ctx, view, viewLink) {
+		return
+	}
+	if err := project_model.UpdateView(ctx, view); err != nil {
+		if !handleFieldError(ctx, err, viewLink) {
+			ctx.ServerError("UpdateView", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.views.edit_success", view.Name))
+	ctx.Redirect(viewLink)
+}
+
+// DeleteViewPost deletes a saved view of the project
+func DeleteViewPost(ctx *context.Context, project *project_model.Project, projectLink string) {
+	view := getView(ctx, project)
+	if ctx.Written() {
+		return
+	}
+	if err := project_model.DeleteView(ctx, view); err != nil {
+		ctx.ServerError("DeleteView", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.views.deletion_success", view.Name))
+	ctx.JSONRedirect(projectLink)
+}
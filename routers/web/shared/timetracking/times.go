@@ -0,0 +1,244 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package timetracking
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const timesheetPageSize = 50
+
+// Options are the options of the tracked time pages of a repository or an organization
+type Options struct {
+	// Find selects the repositories of the times, the other filters are set from the form
+	Find *issues_model.FindTrackedTimesOptions
+	// CanViewAll is whether the doer can see the times of all the users, otherwise only their own times are shown
+	CanViewAll bool
+	// Name is the prefix of the names of the exported files
+	Name     string
+	Template base.TplName
+}
+
+// parseDate parses a date of the form in the timezone of the instance
+func parseDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, setting.DefaultUILocation)
+}
+
+// Times renders the times tracked on the issues of repositories as a report, grouped by user, repository,
+// milestone, label or issue, or as a timesheet listing the tracked times. With the format csv or json they are
+// exported instead.
+func Times(ctx *context.Context, opts Options) {
+	find := opts.Find
+
+	// the times of the current month are shown by default
+	now := time.Now().In(setting.DefaultUILocation)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, setting.DefaultUILocation)
+	var to time.Time
+	var err error
+	if s := ctx.FormTrim("from"); s != "" {
+		if from, err = parseDate(s); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.times.invalid_date", s), true)
+			from = time.Time{}
+		}
+	} else if ctx.FormTrim("to") != "" {
+		from = time.Time{}
+	}
+	if s := ctx.FormTrim("to"); s != "" {
+		if to, err = parseDate(s); err != nil {
+			ctx.Flash.Error(ctx.Tr("repo.times.invalid_date", s), true)
+			to = time.Time{}
+		}
+	}
+	if !from.IsZero() {
+		find.CreatedAfterUnix = from.Unix()
+		ctx.Data["From"] = from.Format("2006-01-02")
+	}
+	if !to.IsZero() {
+		find.CreatedBeforeUnix = to.AddDate(0, 0, 1).Unix() - 1
+		ctx.Data["To"] = to.Format("2006-01-02")
+	}
+
+	if name := ctx.FormTrim("user"); name != "" {
+		user, err := user_model.GetUserByName(ctx, name)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			ctx.ServerError("GetUserByName", err)
+			return
+		}
+		if user != nil {
+			find.UserID = user.ID
+		} else {
+			// a user who doesn't exist has no times
+			find.UserID = -1
+		}
+		ctx.Data["TimeUser"] = name
+	}
+	if !opts.CanViewAll {
+		find.UserID = ctx.Doer.ID
+		ctx.Data["TimeUser"] = ctx.Doer.Name
+	}
+	find.MilestoneID = ctx.FormInt64("milestone")
+	find.LabelID = ctx.FormInt64("label")
+	ctx.Data["MilestoneID"] = find.MilestoneID
+	ctx.Data["LabelID"] = find.LabelID
+
+	group := issues_model.TrackedTimeReportGroup(ctx.FormString("group"))
+	if !group.IsValid() {
+		group = issues_model.TrackedTimeReportGroupUser
+	}
+	isTimesheet := ctx.FormString("view") == "timesheet"
+
+	switch format := ctx.FormString("format"); format {
+	case "":
+	case "csv", "json":
+		exportTimes(ctx, opts, format, group, isTimesheet)
+		return
+	default:
+		ctx.NotFound("Times", nil)
+		return
+	}
+
+	ctx.Data["Group"] = string(group)
+	ctx.Data["Groups"] = issues_model.TrackedTimeReportGroups
+	ctx.Data["IsTimesheet"] = isTimesheet
+	ctx.Data["CanViewAllTimes"] = opts.CanViewAll
+
+	filters := url.Values{}
+	for _, key := range []string{"from", "to", "user", "milestone", "label"} {
+		if value := ctx.FormTrim(key); value != "" {
+			filters.Set(key, value)
+		}
+	}
+	ctx.Data["TimesheetLink"] = ctx.Link + "?view=timesheet&" + filters.Encode()
+	filters.Set("group", string(group))
+	ctx.Data["ReportLink"] = ctx.Link + "?" + filters.Encode()
+
+	query := ctx.Req.URL.Query()
+	query.Del("page")
+	query.Set("format", "csv")
+	ctx.Data["ExportCSVLink"] = ctx.Link + "?" + query.Encode()
+	query.Set("format", "json")
+	ctx.Data["ExportJSONLink"] = ctx.Link + "?" + query.Encode()
+
+	if !isTimesheet {
+		report, err := issues_model.GetTrackedTimeReport(ctx, find, group)
+		if err != nil {
+			ctx.ServerError("GetTrackedTimeReport", err)
+			return
+		}
+		ctx.Data["Report"] = report
+		ctx.HTML(http.StatusOK, opts.Template)
+		return
+	}
+
+	total, err := issues_model.CountTrackedTimes(ctx, find)
+	if err != nil {
+		ctx.ServerError("CountTrackedTimes", err)
+		return
+	}
+	totalTime, err := issues_model.GetTrackedSeconds(ctx, *find)
+	if err != nil {
+		ctx.ServerError("GetTrackedSeconds", err)
+		return
+	}
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	find.Page, find.PageSize = page, timesheetPageSize
+	times, err := issues_model.GetTrackedTimes(ctx, find)
+	if err != nil {
+		ctx.ServerError("GetTrackedTimes", err)
+		return
+	}
+	if err := times.LoadAttributes(ctx); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	ctx.Data["Times"] = times
+	ctx.Data["TotalTime"] = totalTime
+
+	pager := context.NewPagination(int(total), timesheetPageSize, page, 5)
+	for _, key := range []string{"view", "from", "to", "user", "milestone", "label"} {
+		if value := ctx.FormTrim(key); value != "" {
+			pager.AddParamString(key, value)
+		}
+	}
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, opts.Template)
+}
+
+// exportTimes writes the report or the timesheet of the times as CSV or JSON
+func exportTimes(ctx *context.Context, opts Options, format string, group issues_model.TrackedTimeReportGroup, isTimesheet bool) {
+	var buf bytes.Buffer
+	var err error
+	name := opts.Name + "-times-" + string(group)
+	if isTimesheet {
+		name = opts.Name + "-timesheet"
+		var times issues_model.TrackedTimeList
+		if times, err = issues_model.GetTrackedTimes(ctx, opts.Find); err != nil {
+			ctx.ServerError("GetTrackedTimes", err)
+			return
+		}
+		if err := times.LoadAttributes(ctx); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+		if format == "csv" {
+			err = issue_service.WriteTimesheetCSV(&buf, times)
+		} else {
+			err = writeJSON(&buf, convert.ToTrackedTimeList(ctx, times))
+		}
+	} else {
+		var report *issues_model.TrackedTimeReport
+		if report, err = issues_model.GetTrackedTimeReport(ctx, opts.Find, group); err != nil {
+			ctx.ServerError("GetTrackedTimeReport", err)
+			return
+		}
+		if format == "csv" {
+			err = issue_service.WriteTrackedTimeReportCSV(&buf, report)
+		} else {
+			err = writeJSON(&buf, convert.ToTrackedTimeReport(report))
+		}
+	}
+	if err != nil {
+		ctx.ServerError("Write", err)
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		ContentType:        contentType,
+		ContentTypeCharset: "utf-8",
+		Filename:           name + "." + format,
+	})
+	if _, err := ctx.Resp.Write(buf.Bytes()); err != nil {
+		log.Error("Write: %v", err)
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = buf.Write(data)
+	return err
+}
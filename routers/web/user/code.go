@@ -4,6 +4,7 @@
 package user
 
 import (
+	"errors"
 	"net/http"
 
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -11,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	shared_user "code.gitea.io/gitea/routers/web/shared/user"
 )
 
@@ -40,10 +42,11 @@ func CodeSearch(ctx *context.Context) {
 	keyword := ctx.FormTrim("q")
 
 	queryType := ctx.FormTrim("t")
-	isMatch := queryType == "match"
+	query := code_indexer.ParseQuery(keyword)
+	opts := query.ToSearchOptions(language, code_indexer.ParseSearchMode(queryType))
 
 	ctx.Data["Keyword"] = keyword
-	ctx.Data["Language"] = language
+	ctx.Data["Language"] = opts.Language
 	ctx.Data["queryType"] = queryType
 	ctx.Data["IsCodePage"] = true
 
@@ -67,16 +70,33 @@ func CodeSearch(ctx *context.Context) {
 		ctx.ServerError("FindUserCodeAccessibleOwnerRepoIDs", err)
 		return
 	}
+	if len(query.Repos) > 0 {
+		// the repositories without owner are the ones of the context user
+		repoIDs, err = code_indexer.FilterRepoIDs(ctx, query.Repos, ctx.ContextUser.Name, repoIDs, false)
+		if err != nil {
+			ctx.ServerError("FilterRepoIDs", err)
+			return
+		}
+	}
 
 	var (
 		total                 int
 		searchResults         []*code_indexer.Result
 		searchResultLanguages []*code_indexer.SearchResultLanguages
+		truncated             bool
 	)
 
 	if len(repoIDs) > 0 {
-		total, searchResults, searchResultLanguages, err = code_indexer.PerformSearch(ctx, repoIDs, language, keyword, page, setting.UI.RepoSearchPagingNum, isMatch)
+		opts.RepoIDs = repoIDs
+		opts.Page = page
+		opts.PageSize = setting.UI.RepoSearchPagingNum
+		total, searchResults, searchResultLanguages, truncated, err = code_indexer.PerformSearch(ctx, opts)
 		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Data["SearchError"] = ctx.Tr("explore.code_search_invalid_query", err.Error())
+				ctx.HTML(http.StatusOK, tplUserCode)
+				return
+			}
 			if code_indexer.IsAvailable(ctx) {
 				ctx.ServerError("SearchResults", err)
 				return
@@ -110,6 +130,8 @@ func CodeSearch(ctx *context.Context) {
 	}
 	ctx.Data["SearchResults"] = searchResults
 	ctx.Data["SearchResultLanguages"] = searchResultLanguages
+	ctx.Data["SearchResultsTruncated"] = truncated
+	ctx.Data["RegexpMaxCandidates"] = code_indexer.RegexpMaxCandidates
 
 	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
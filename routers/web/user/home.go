@@ -31,6 +31,7 @@ import (
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/feed"
 	context_service "code.gitea.io/gitea/services/context"
@@ -134,6 +135,13 @@ func Dashboard(ctx *context.Context) {
 
 	ctx.Data["Feeds"] = feeds
 
+	widgets, err := loadSavedSearchWidgets(ctx, ctxUser)
+	if err != nil {
+		ctx.ServerError("loadSavedSearchWidgets", err)
+		return
+	}
+	ctx.Data["SavedSearchWidgets"] = widgets
+
 	pager := context.NewPagination(int(count), setting.UI.FeedPagingNum, page, 5)
 	pager.AddParam(ctx, "date", "Date")
 	ctx.Data["Page"] = pager
@@ -491,10 +499,50 @@ func buildIssueOverview(ctx *context.Context, unitType unit.Type) {
 		opts.ReviewedID = ctx.Doer.ID
 	}
 
+	// The issues of an organization can be filtered by one of its milestones.
+	var milestoneID int64
+	if ctxUser.IsOrganization() {
+		milestoneID = ctx.FormInt64("milestone")
+		if milestoneID > 0 {
+			opts.MilestoneIDs = []int64{milestoneID}
+		}
+		orgMilestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+			OrgID: ctxUser.ID,
+			State: api.StateOpen,
+		})
+		if err != nil {
+			ctx.ServerError("GetMilestones", err)
+			return
+		}
+		ctx.Data["OrgMilestones"] = orgMilestones
+	}
+
 	// keyword holds the search term entered into the search field.
 	keyword := strings.Trim(ctx.FormString("q"), " ")
 	ctx.Data["Keyword"] = keyword
 
+	// the sub-issues of an issue can be searched with `parent:owner/repo#index`
+	var err error
+	keyword, opts.ParentID, err = issues_model.ParseParentKeyword(ctx, keyword, nil)
+	if err != nil {
+		ctx.ServerError("ParseParentKeyword", err)
+		return
+	}
+
+	// the issues can be filtered by the issue types of an organization with `type:Bug` and their fields with `field:id=value`
+	var typeOwnerID int64
+	if ctxUser.IsOrganization() {
+		typeOwnerID = ctxUser.ID
+	}
+	keyword, opts.TypeID, opts.FieldValues, err = issues_model.ParseIssueTypeKeyword(ctx, keyword, typeOwnerID)
+	if err != nil {
+		ctx.ServerError("ParseIssueTypeKeyword", err)
+		return
+	}
+
+	// the issues tracked by a SLA policy can be filtered by their state with `sla:breached`
+	keyword, opts.SLAState = issues_model.ParseSLAKeyword(keyword)
+
 	// Educated guess: Do or don't show closed issues.
 	isShowClosed := ctx.FormString("state") == "closed"
 	opts.IsClosed = util.OptionalBoolOf(isShowClosed)
@@ -647,6 +695,10 @@ func buildIssueOverview(ctx *context.Context, unitType unit.Type) {
 		ctx.ServerError("issues.LoadAttributes", err)
 		return
 	}
+	if err := issues.LoadSLAs(ctx); err != nil {
+		ctx.ServerError("issues.LoadSLAs", err)
+		return
+	}
 	ctx.Data["Issues"] = issues
 
 	approvalCounts, err := issues.GetApprovalCounts(ctx)
@@ -682,6 +734,7 @@ func buildIssueOverview(ctx *context.Context, unitType unit.Type) {
 	ctx.Data["RepoIDs"] = selectedRepoIDs
 	ctx.Data["IsShowClosed"] = isShowClosed
 	ctx.Data["SelectLabels"] = selectedLabels
+	ctx.Data["MilestoneID"] = milestoneID
 
 	if isShowClosed {
 		ctx.Data["State"] = "closed"
@@ -0,0 +1,367 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/web/feed"
+	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const (
+	tplSavedSearches      base.TplName = "user/dashboard/saved_searches"
+	tplSavedSearch        base.TplName = "user/dashboard/saved_search"
+	tplSavedSearchEdit    base.TplName = "user/dashboard/saved_search_edit"
+	savedSearchWidgetSize              = 5
+)
+
+// SavedSearchWidget is a saved search shown on a dashboard with its first issues
+type SavedSearchWidget struct {
+	Search *issues_model.SavedSearch
+	Issues issues_model.IssueList
+	Total  int64
+}
+
+// savedSearchesLink returns the link to the saved searches of a user or an organization
+func savedSearchesLink(owner *user_model.User) string {
+	if owner.IsOrganization() {
+		return owner.OrganisationLink() + "/searches"
+	}
+	return setting.AppSubURL + "/searches"
+}
+
+// savedSearchOverviewLink returns the link to the issues or pull requests overview with the filters of a saved
+// search, the overview of a user can only be opened by themselves
+func savedSearchOverviewLink(owner, doer *user_model.User, s *issues_model.SavedSearch) string {
+	path := "/issues"
+	if s.IsPull {
+		path = "/pulls"
+	}
+	var link string
+	if owner.IsOrganization() {
+		link = owner.OrganisationLink() + path
+	} else if doer != nil && doer.ID == owner.ID {
+		link = setting.AppSubURL + path
+	} else {
+		return ""
+	}
+	if s.Query != "" {
+		link += "?" + s.Query
+	}
+	return link
+}
+
+// SavedSearches renders the saved searches of the user or the organization of the dashboard
+func SavedSearches(ctx *context.Context) {
+	ctxUser := getDashboardContextUser(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("home.saved_searches")
+	ctx.Data["PageIsSavedSearches"] = true
+
+	searches, err := issues_model.FindSavedSearches(ctx, issues_model.FindSavedSearchesOptions{OwnerID: ctxUser.ID})
+	if err != nil {
+		ctx.ServerError("FindSavedSearches", err)
+		return
+	}
+	ctx.Data["SavedSearches"] = searches
+	// the members of an organization can only change the searches they created
+	ctx.Data["CanManageAllSearches"] = !ctxUser.IsOrganization() || ctx.Org.IsOwner || ctx.Doer.IsAdmin
+
+	ctx.HTML(http.StatusOK, tplSavedSearches)
+}
+
+// NewSavedSearchPost saves a search of the issues or pull requests overview of a user or an organization
+func NewSavedSearchPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.SavedSearchForm)
+	ctxUser := getDashboardContextUser(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.JSONError(ctx.GetErrMsg())
+		return
+	}
+
+	values, err := url.ParseQuery(form.Query)
+	if err != nil {
+		ctx.JSONError(ctx.Tr("home.saved_search.invalid_query"))
+		return
+	}
+	s := &issues_model.SavedSearch{
+		OwnerID:     ctxUser.ID,
+		CreatorID:   ctx.Doer.ID,
+		Name:        form.Name,
+		IsPull:      form.IsPull,
+		Query:       issues_model.CleanSavedSearchQuery(values),
+		IsPublic:    form.IsPublic,
+		OnDashboard: form.OnDashboard,
+	}
+	if err := issues_model.CreateSavedSearch(ctx, s); err != nil {
+		if issues_model.IsErrSavedSearchAlreadyExist(err) {
+			ctx.JSONError(ctx.Tr("home.saved_search.name_in_use"))
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(err.Error())
+		} else {
+			ctx.ServerError("CreateSavedSearch", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("home.saved_search.create_success", s.Name))
+	ctx.JSONRedirect(s.Link())
+}
+
+// getSavedSearch returns the saved search of the request if the doer can view it
+func getSavedSearch(ctx *context.Context) *issues_model.SavedSearch {
+	s, err := issues_model.GetSavedSearchByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrSavedSearchNotExist(err) {
+			ctx.NotFound("GetSavedSearchByID", err)
+		} else {
+			ctx.ServerError("GetSavedSearchByID", err)
+		}
+		return nil
+	}
+	canView, err := issue_service.CanViewSavedSearch(ctx, ctx.Doer, s)
+	if err != nil {
+		ctx.ServerError("CanViewSavedSearch", err)
+		return nil
+	} else if !canView {
+		ctx.NotFound("CanViewSavedSearch", nil)
+		return nil
+	}
+	return s
+}
+
+// getEditableSavedSearch returns the saved search of the request if the doer can change it
+func getEditableSavedSearch(ctx *context.Context) *issues_model.SavedSearch {
+	s := getSavedSearch(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	canEdit, err := issue_service.CanEditSavedSearch(ctx, ctx.Doer, s)
+	if err != nil {
+		ctx.ServerError("CanEditSavedSearch", err)
+		return nil
+	} else if !canEdit {
+		ctx.NotFound("CanEditSavedSearch", nil)
+		return nil
+	}
+	return s
+}
+
+// ViewSavedSearch renders the results of a saved search for the doer
+func ViewSavedSearch(ctx *context.Context) {
+	s := getSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+	owner, err := user_model.GetUserByID(ctx, s.OwnerID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return
+	}
+
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	issues, total, err := issue_service.FindSavedSearchIssues(ctx, ctx.Doer, s, db.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.IssuePagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("FindSavedSearchIssues", err)
+		return
+	}
+	if err := issues.LoadAttributes(ctx); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	approvalCounts, err := issues.GetApprovalCounts(ctx)
+	if err != nil {
+		ctx.ServerError("ApprovalCounts", err)
+		return
+	}
+	ctx.Data["ApprovalCounts"] = func(issueID int64, typ string) int64 {
+		reviewTyp := issues_model.ReviewTypeApprove
+		if typ == "reject" {
+			reviewTyp = issues_model.ReviewTypeReject
+		} else if typ == "waiting" {
+			reviewTyp = issues_model.ReviewTypeRequest
+		}
+		for _, count := range approvalCounts[issueID] {
+			if count.Type == reviewTyp {
+				return count.Count
+			}
+		}
+		return 0
+	}
+	ctx.Data["IssueRefEndNames"], ctx.Data["IssueRefURLs"] = issue_service.GetRefEndNamesAndURLs(issues, "")
+
+	canEdit, err := issue_service.CanEditSavedSearch(ctx, ctx.Doer, s)
+	if err != nil {
+		ctx.ServerError("CanEditSavedSearch", err)
+		return
+	}
+
+	ctx.Data["Title"] = s.Name
+	ctx.Data["SavedSearch"] = s
+	ctx.Data["SavedSearchOwner"] = owner
+	ctx.Data["SavedSearchKeyword"] = s.Keyword()
+	ctx.Data["OverviewLink"] = savedSearchOverviewLink(owner, ctx.Doer, s)
+	ctx.Data["CanEditSavedSearch"] = canEdit
+	ctx.Data["EnableFeed"] = setting.Other.EnableFeed
+	ctx.Data["Issues"] = issues
+	ctx.Data["Total"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.IssuePagingNum, page, 5)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplSavedSearch)
+}
+
+// SavedSearchFeedRSS shows the issues of a saved search as RSS feed
+func SavedSearchFeedRSS(ctx *context.Context) {
+	s := getSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+	feed.ShowSavedSearchFeed(ctx, s, "rss")
+}
+
+// SavedSearchFeedAtom shows the issues of a saved search as Atom feed
+func SavedSearchFeedAtom(ctx *context.Context) {
+	s := getSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+	feed.ShowSavedSearchFeed(ctx, s, "atom")
+}
+
+// EditSavedSearch renders the page to change a saved search
+func EditSavedSearch(ctx *context.Context) {
+	s := getEditableSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("home.saved_search.edit")
+	ctx.Data["SavedSearch"] = s
+	ctx.Data["name"] = s.Name
+	ctx.Data["query"] = s.Query
+	ctx.Data["is_public"] = s.IsPublic
+	ctx.Data["on_dashboard"] = s.OnDashboard
+
+	ctx.HTML(http.StatusOK, tplSavedSearchEdit)
+}
+
+// EditSavedSearchPost changes the name, the filters and the sharing of a saved search
+func EditSavedSearchPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.SavedSearchForm)
+	s := getEditableSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("home.saved_search.edit")
+	ctx.Data["SavedSearch"] = s
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSavedSearchEdit)
+		return
+	}
+
+	values, err := url.ParseQuery(form.Query)
+	if err != nil {
+		ctx.Data["Err_Query"] = true
+		ctx.RenderWithErr(ctx.Tr("home.saved_search.invalid_query"), tplSavedSearchEdit, form)
+		return
+	}
+	s.Name = form.Name
+	s.Query = issues_model.CleanSavedSearchQuery(values)
+	s.IsPublic = form.IsPublic
+	s.OnDashboard = form.OnDashboard
+	if err := issues_model.UpdateSavedSearch(ctx, s); err != nil {
+		if issues_model.IsErrSavedSearchAlreadyExist(err) {
+			ctx.Data["Err_Name"] = true
+			ctx.RenderWithErr(ctx.Tr("home.saved_search.name_in_use"), tplSavedSearchEdit, form)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Data["Err_Name"] = true
+			ctx.RenderWithErr(err.Error(), tplSavedSearchEdit, form)
+		} else {
+			ctx.ServerError("UpdateSavedSearch", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("home.saved_search.edit_success", s.Name))
+	ctx.Redirect(s.Link())
+}
+
+// DeleteSavedSearch deletes a saved search
+func DeleteSavedSearch(ctx *context.Context) {
+	s := getEditableSavedSearch(ctx)
+	if ctx.Written() {
+		return
+	}
+	owner, err := user_model.GetUserByID(ctx, s.OwnerID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return
+	}
+
+	if err := issues_model.DeleteSavedSearch(ctx, s.OwnerID, s.ID); err != nil {
+		ctx.Flash.Error("DeleteSavedSearch: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("home.saved_search.deletion_success"))
+	}
+
+	ctx.JSONRedirect(savedSearchesLink(owner))
+}
+
+// loadSavedSearchWidgets loads the saved searches of a user or an organization shown on its dashboard
+func loadSavedSearchWidgets(ctx *context.Context, owner *user_model.User) ([]*SavedSearchWidget, error) {
+	searches, err := issues_model.FindSavedSearches(ctx, issues_model.FindSavedSearchesOptions{
+		OwnerID:     owner.ID,
+		OnDashboard: util.OptionalBoolTrue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	widgets := make([]*SavedSearchWidget, 0, len(searches))
+	for _, s := range searches {
+		issues, total, err := issue_service.FindSavedSearchIssues(ctx, ctx.Doer, s, db.ListOptions{
+			Page:     1,
+			PageSize: savedSearchWidgetSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := issues.LoadRepositories(ctx); err != nil {
+			return nil, err
+		}
+		widgets = append(widgets, &SavedSearchWidget{
+			Search: s,
+			Issues: issues,
+			Total:  total,
+		})
+	}
+	return widgets, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+	"strings"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsNotifications base.TplName = "user/settings/notifications"
+)
+
+// notificationRepoPreference is a preference of the user for an event of a repository
+type notificationRepoPreference struct {
+	Repo     *repo_model.Repository
+	Event    activities_model.NotificationEvent
+	Delivery activities_model.NotificationDelivery
+}
+
+// Notifications renders the digest and the per event notification preferences of the user
+func Notifications(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.notifications")
+	ctx.Data["PageIsSettingsNotifications"] = true
+	ctx.Data["EnableNotifyMail"] = setting.Service.EnableNotifyMail
+	ctx.Data["EmailNotificationsPreference"] = ctx.Doer.EmailNotifications()
+
+	digest, err := activities_model.GetNotificationDigest(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("GetNotificationDigest", err)
+		return
+	}
+	prefs, err := activities_model.GetNotificationPreferences(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("GetNotificationPreferences", err)
+		return
+	}
+
+	deliveries := make(map[activities_model.NotificationEvent]activities_model.NotificationDelivery, len(activities_model.NotificationEvents))
+	for _, event := range activities_model.NotificationEvents {
+		deliveries[event] = activities_model.DefaultNotificationDelivery(event)
+	}
+	repoIDs := make([]int64, 0, len(prefs))
+	for _, pref := range prefs {
+		if pref.RepoID == 0 {
+			deliveries[pref.Event] = pref.Delivery
+		} else {
+			repoIDs = append(repoIDs, pref.RepoID)
+		}
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+	repoPrefs := make([]*notificationRepoPreference, 0, len(repoIDs))
+	for _, pref := range prefs {
+		if repo, ok := repos[pref.RepoID]; ok {
+			repoPrefs = append(repoPrefs, &notificationRepoPreference{Repo: repo, Event: pref.Event, Delivery: pref.Delivery})
+		}
+	}
+
+	ctx.Data["DigestFrequency"] = digest.Frequency
+	ctx.Data["NotificationEvents"] = activities_model.NotificationEvents
+	ctx.Data["NotificationDeliveries"] = deliveries
+	ctx.Data["RepoPreferences"] = repoPrefs
+
+	ctx.HTML(http.StatusOK, tplSettingsNotifications)
+}
+
+// NotificationsDigestPost sets how often the digest email of the user is sent
+func NotificationsDigestPost(ctx *context.Context) {
+	frequency := activities_model.NotificationDigestFrequency(ctx.FormString("frequency"))
+	if !frequency.IsValid() {
+		ctx.Error(http.StatusBadRequest, "unknown digest frequency")
+		return
+	}
+	if err := activities_model.SetNotificationDigestFrequency(ctx, ctx.Doer.ID, frequency); err != nil {
+		ctx.ServerError("SetNotificationDigestFrequency", err)
+		return
+	}
+
+	log.Trace("Notification digest made %q: %s", frequency, ctx.Doer.Name)
+	ctx.Flash.Success(ctx.Tr("settings.notifications.digest_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+}
+
+// NotificationsPreferencesPost sets how the user is notified of the events of all the repositories
+func NotificationsPreferencesPost(ctx *context.Context) {
+	for _, event := range activities_model.NotificationEvents {
+		delivery := activities_model.NotificationDelivery(ctx.FormString("event_" + string(event)))
+		if !delivery.IsValid() {
+			ctx.Error(http.StatusBadRequest, "unknown notification delivery")
+			return
+		}
+		if err := activities_model.SetNotificationPreference(ctx, ctx.Doer.ID, 0, event, delivery); err != nil {
+			ctx.ServerError("SetNotificationPreference", err)
+			return
+		}
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.notifications.preferences_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+}
+
+// NotificationsRepoPost sets how the user is notified of an event of a repository
+func NotificationsRepoPost(ctx *context.Context) {
+	event := activities_model.NotificationEvent(ctx.FormString("event"))
+	delivery := activities_model.NotificationDelivery(ctx.FormString("delivery"))
+	if !event.IsValid() || !delivery.IsValid() {
+		ctx.Error(http.StatusBadRequest, "unknown notification event or delivery")
+		return
+	}
+
+	ownerName, repoName, _ := strings.Cut(strings.TrimSpace(ctx.FormString("repo")), "/")
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil && !repo_model.IsErrRepoNotExist(err) {
+		ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		return
+	}
+	if repo != nil {
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if !perm.HasAccess() {
+			repo = nil
+		}
+	}
+	if repo == nil {
+		// a repository the user can't see is reported like a missing one
+		ctx.Flash.Error(ctx.Tr("settings.notifications.repo_not_exist"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+		return
+	}
+
+	if err := activities_model.SetNotificationPreference(ctx, ctx.Doer.ID, repo.ID, event, delivery); err != nil {
+		ctx.ServerError("SetNotificationPreference", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.notifications.preferences_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+}
+
+// NotificationsRepoDelete removes a preference of the user for an event of a repository
+func NotificationsRepoDelete(ctx *context.Context) {
+	repoID := ctx.FormInt64("repo_id")
+	if repoID <= 0 {
+		// the preferences for all the repositories are changed, not removed
+		ctx.Error(http.StatusBadRequest, "missing repository")
+		return
+	}
+	event := activities_model.NotificationEvent(ctx.FormString("event"))
+	if err := activities_model.DeleteNotificationPreference(ctx, ctx.Doer.ID, repoID, event); err != nil {
+		ctx.ServerError("DeleteNotificationPreference", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.notifications.repo_deleted"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+}
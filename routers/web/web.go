@@ -400,6 +400,7 @@ func registerRoutes(m *web.Route) {
 		m.Post("/feishu/new", web.Bind(forms.NewFeishuHookForm{}), repo_setting.FeishuHooksNewPost)
 		m.Post("/wechatwork/new", web.Bind(forms.NewWechatWorkHookForm{}), repo_setting.WechatworkHooksNewPost)
 		m.Post("/packagist/new", web.Bind(forms.NewPackagistHookForm{}), repo_setting.PackagistHooksNewPost)
+		m.Post("/custom/new", web.Bind(forms.NewCustomHookForm{}), repo_setting.CustomHooksNewPost)
 	}
 
 	addWebhookEditRoutes := func() {
@@ -414,6 +415,7 @@ func registerRoutes(m *web.Route) {
 		m.Post("/feishu/{id}", web.Bind(forms.NewFeishuHookForm{}), repo_setting.FeishuHooksEditPost)
 		m.Post("/wechatwork/{id}", web.Bind(forms.NewWechatWorkHookForm{}), repo_setting.WechatworkHooksEditPost)
 		m.Post("/packagist/{id}", web.Bind(forms.NewPackagistHookForm{}), repo_setting.PackagistHooksEditPost)
+		m.Post("/custom/{id}", web.Bind(forms.NewCustomHookForm{}), repo_setting.CustomHooksEditPost)
 	}
 
 	addSettingVariablesRoutes := func() {
@@ -475,6 +477,12 @@ func registerRoutes(m *web.Route) {
 				return
 			}
 		}, explore.Code)
+		m.Get("/commits", func(ctx *context.Context) {
+			if unit.TypeCode.UnitGlobalDisabled() {
+				ctx.NotFound(unit.TypeCode.String(), nil)
+				return
+			}
+		}, explore.Commits)
 		m.Get("/topics/search", explore.TopicSearch)
 	}, ignExploreSignIn)
 	m.Group("/issues", func() {
@@ -484,6 +492,16 @@ func registerRoutes(m *web.Route) {
 
 	m.Get("/pulls", reqSignIn, user.Pulls)
 	m.Get("/milestones", reqSignIn, reqMilestonesDashboardPageEnabled, user.Milestones)
+	m.Group("/searches", func() {
+		m.Get("", reqSignIn, user.SavedSearches)
+		m.Post("/new", reqSignIn, web.Bind(forms.SavedSearchForm{}), user.NewSavedSearchPost)
+		m.Get("/{id}", user.ViewSavedSearch)
+		m.Get("/{id}.rss", feedEnabled, user.SavedSearchFeedRSS)
+		m.Get("/{id}.atom", feedEnabled, user.SavedSearchFeedAtom)
+		m.Combo("/{id}/edit", reqSignIn).Get(user.EditSavedSearch).
+			Post(web.Bind(forms.SavedSearchForm{}), user.EditSavedSearchPost)
+		m.Post("/{id}/delete", reqSignIn, user.DeleteSavedSearch)
+	}, ignSignIn)
 
 	// ***** START: User *****
 	// "user/login" doesn't need signOut, then logged-in users can still access this route for redirection purposes by "/user/login?redirec_to=..."
@@ -551,6 +569,13 @@ func registerRoutes(m *web.Route) {
 			m.Post("/email/delete", user_setting.DeleteEmail)
 			m.Post("/delete", user_setting.DeleteAccount)
 		})
+		m.Group("/notifications", func() {
+			m.Get("", user_setting.Notifications)
+			m.Post("/digest", user_setting.NotificationsDigestPost)
+			m.Post("/preferences", user_setting.NotificationsPreferencesPost)
+			m.Post("/repo", user_setting.NotificationsRepoPost)
+			m.Post("/repo/delete", user_setting.NotificationsRepoDelete)
+		})
 		m.Group("/appearance", func() {
 			m.Get("", user_setting.Appearance)
 			m.Post("/language", web.Bind(forms.UpdateLanguageForm{}), user_setting.UpdateUserLang)
@@ -717,6 +742,7 @@ func registerRoutes(m *web.Route) {
 		m.Group("/hooks", func() {
 			m.Get("", admin.DefaultOrSystemWebhooks)
 			m.Post("/delete", admin.DeleteDefaultOrSystemWebhook)
+			m.Get("/deliveries", admin.WebhookDeliveries)
 			m.Group("/{id}", func() {
 				m.Get("", repo_setting.WebHooksEdit)
 				m.Post("/replay/{uuid}", repo_setting.ReplayWebhook)
@@ -840,6 +866,9 @@ func registerRoutes(m *web.Route) {
 			m.Get("/pulls/{team}", user.Pulls)
 			m.Get("/milestones", reqMilestonesDashboardPageEnabled, user.Milestones)
 			m.Get("/milestones/{team}", reqMilestonesDashboardPageEnabled, user.Milestones)
+			m.Get("/searches", user.SavedSearches)
+			m.Post("/searches/new", web.Bind(forms.SavedSearchForm{}), user.NewSavedSearchPost)
+			m.Get("/times", org.Times)
 			m.Post("/members/action/{action}", org.MembersAction)
 			m.Get("/teams", org.Teams)
 		}, context.OrgAssignment(true, false, true))
@@ -898,6 +927,15 @@ func registerRoutes(m *web.Route) {
 					m.Post("/initialize", web.Bind(forms.InitializeLabelsForm{}), org.InitializeLabels)
 				})
 
+				m.Group("/issue_types", func() {
+					m.Get("", org.IssueTypes)
+					m.Combo("/new").Get(org.NewIssueType).
+						Post(web.Bind(forms.IssueTypeForm{}), org.NewIssueTypePost)
+					m.Combo("/{id}").Get(org.EditIssueType).
+						Post(web.Bind(forms.IssueTypeForm{}), org.EditIssueTypePost)
+					m.Post("/delete", org.DeleteIssueType)
+				})
+
 				m.Group("/actions", func() {
 					m.Get("", org_setting.RedirectToDefaultSetting)
 					addSettingsRunnersRoutes()
@@ -966,6 +1004,7 @@ func registerRoutes(m *web.Route) {
 			m.Group("", func() {
 				m.Get("", org.Projects)
 				m.Get("/{id}", org.ViewProject)
+				m.Get("/{id}/views/{viewID}", org.ViewProjectView)
 			}, reqUnitAccess(unit.TypeProjects, perm.AccessModeRead, true))
 			m.Group("", func() { //nolint:dupl
 				m.Get("/new", org.RenderNewProject)
@@ -978,6 +1017,28 @@ func registerRoutes(m *web.Route) {
 					m.Post("/edit", web.Bind(forms.CreateProjectForm{}), org.EditProjectPost)
 					m.Post("/{action:open|close}", org.ChangeProjectStatus)
 
+					m.Group("/fields", func() {
+						m.Get("", org.ProjectFields)
+						m.Post("/new", web.Bind(forms.ProjectFieldForm{}), org.NewProjectFieldPost)
+						m.Group("/{fieldID}", func() {
+							m.Post("/edit", web.Bind(forms.ProjectFieldForm{}), org.EditProjectFieldPost)
+							m.Post("/delete", org.DeleteProjectFieldPost)
+							m.Post("/options/new", web.Bind(forms.ProjectFieldOptionForm{}), org.NewProjectFieldOptionPost)
+							m.Post("/options/{optionID}/delete", org.DeleteProjectFieldOptionPost)
+						})
+					})
+					m.Post("/items/{issueID}/fields", org.UpdateProjectItemFieldsPost)
+					m.Group("/automation", func() {
+						m.Get("", org.ProjectAutomation)
+						m.Post("/new", web.Bind(forms.ProjectAutomationForm{}), org.NewProjectAutomationPost)
+						m.Post("/{automationID}/delete", org.DeleteProjectAutomationPost)
+					})
+					m.Group("/views", func() {
+						m.Post("/new", web.Bind(forms.ProjectViewForm{}), org.NewProjectViewPost)
+						m.Post("/{viewID}/edit", web.Bind(forms.ProjectViewForm{}), org.EditProjectViewPost)
+						m.Post("/{viewID}/delete", org.DeleteProjectViewPost)
+					})
+
 					m.Group("/{boardID}", func() {
 						m.Put("", web.Bind(forms.EditProjectBoardForm{}), org.EditProjectBoard)
 						m.Delete("", org.DeleteProjectBoard)
@@ -995,10 +1056,23 @@ func registerRoutes(m *web.Route) {
 			})
 		}, reqUnitAccess(unit.TypeProjects, perm.AccessModeRead, true), individualPermsChecker)
 
+		m.Group("/milestones", func() {
+			m.Get("", org.Milestones)
+			m.Get("/{id}", org.ViewMilestone)
+			m.Group("", func() {
+				m.Get("/new", org.NewMilestone)
+				m.Post("/new", web.Bind(forms.CreateMilestoneForm{}), org.NewMilestonePost)
+				m.Get("/{id}/edit", org.EditMilestone)
+				m.Post("/{id}/edit", web.Bind(forms.CreateMilestoneForm{}), org.EditMilestonePost)
+				m.Post("/{id}/{action:open|close}", org.ChangeMilestoneStatus)
+				m.Post("/delete", org.DeleteMilestone)
+			}, reqSignIn, reqUnitAccess(unit.TypeIssues, perm.AccessModeWrite, true))
+		}, org.MustBeOrganization, reqUnitAccess(unit.TypeIssues, perm.AccessModeRead, true))
+
 		m.Group("", func() {
 			m.Get("/code", user.CodeSearch)
 		}, reqUnitAccess(unit.TypeCode, perm.AccessModeRead, false), individualPermsChecker)
-	}, ignSignIn, context_service.UserAssignmentWeb(), context.OrgAssignment()) // for "/{username}/-" (packages, projects, code)
+	}, ignSignIn, context_service.UserAssignmentWeb(), context.OrgAssignment()) // for "/{username}/-" (packages, projects, milestones, code)
 
 	m.Group("/{username}/{reponame}", func() {
 		m.Group("/settings", func() {
@@ -1040,6 +1114,14 @@ func registerRoutes(m *web.Route) {
 				m.Post("/{id}", web.Bind(forms.ProtectTagForm{}), context.RepoMustNotBeArchived(), repo_setting.EditProtectedTagPost)
 			})
 
+			m.Group("/sla", func() {
+				m.Get("", repo_setting.SLAPolicies)
+				m.Post("", web.Bind(forms.SLAPolicyForm{}), context.RepoMustNotBeArchived(), repo_setting.NewSLAPolicyPost)
+				m.Post("/delete", context.RepoMustNotBeArchived(), repo_setting.DeleteSLAPolicyPost)
+				m.Get("/{id}", repo_setting.EditSLAPolicy)
+				m.Post("/{id}", web.Bind(forms.SLAPolicyForm{}), context.RepoMustNotBeArchived(), repo_setting.EditSLAPolicyPost)
+			}, repo.MustEnableIssues)
+
 			m.Group("/hooks/git", func() {
 				m.Get("", repo_setting.GitHooks)
 				m.Combo("/{name}").Get(repo_setting.GitHooksEdit).
@@ -1124,7 +1206,11 @@ func registerRoutes(m *web.Route) {
 				m.Get("/choose", context.RepoRef(), repo.NewIssueChooseTemplate)
 			})
 			m.Get("/search", repo.ListIssues)
+			m.Combo("/import", reqRepoAdmin).Get(repo.ImportIssues).
+				Post(web.Bind(forms.ImportIssuesForm{}), repo.ImportIssuesPost)
 		}, context.RepoMustNotBeArchived(), reqRepoIssueReader)
+		m.Get("/issues/export", reqRepoIssueReader, repo.ExportIssues)
+		m.Get("/times", reqRepoIssuesOrPullsReader, repo.Times)
 		// FIXME: should use different URLs but mostly same logic for comments of issue and pull request.
 		// So they can apply their own enable/disable logic on routers.
 		m.Group("/{type:issues|pulls}", func() {
@@ -1134,15 +1220,22 @@ func registerRoutes(m *web.Route) {
 				m.Post("/deadline", web.Bind(structs.EditDeadlineOption{}), repo.UpdateIssueDeadline)
 				m.Post("/watch", repo.IssueWatch)
 				m.Post("/ref", repo.UpdateIssueRef)
+				m.Post("/project_fields", reqRepoIssuesOrPullsWriter, reqRepoProjectsReader, repo.UpdateIssueProjectFields)
 				m.Post("/pin", reqRepoAdmin, repo.IssuePinOrUnpin)
 				m.Post("/viewed-files", repo.UpdateViewedFiles)
 				m.Group("/dependency", func() {
 					m.Post("/add", repo.AddDependency)
 					m.Post("/delete", repo.RemoveDependency)
 				})
+				m.Group("/sub_issues", func() {
+					m.Post("/add", repo.AddSubIssue)
+					m.Post("/remove", repo.RemoveSubIssue)
+					m.Post("/convert", repo.ConvertTaskToSubIssue)
+				}, reqRepoIssuesOrPullsWriter)
 				m.Combo("/comments").Post(repo.MustAllowUserComment, web.Bind(forms.CreateCommentForm{}), repo.NewComment)
 				m.Group("/times", func() {
 					m.Post("/add", web.Bind(forms.AddTimeManuallyForm{}), repo.AddTimeManually)
+					m.Post("/estimate", web.Bind(forms.TimeEstimateForm{}), repo.UpdateTimeEstimate)
 					m.Post("/{timeid}/delete", repo.DeleteTime)
 					m.Group("/stopwatch", func() {
 						m.Post("/toggle", repo.IssueStopwatch)
@@ -1305,6 +1398,7 @@ func registerRoutes(m *web.Route) {
 		m.Group("/projects", func() {
 			m.Get("", repo.Projects)
 			m.Get("/{id}", repo.ViewProject)
+			m.Get("/{id}/views/{viewID}", repo.ViewProjectView)
 			m.Group("", func() { //nolint:dupl
 				m.Get("/new", repo.RenderNewProject)
 				m.Post("/new", web.Bind(forms.CreateProjectForm{}), repo.NewProjectPost)
@@ -1316,6 +1410,28 @@ func registerRoutes(m *web.Route) {
 					m.Post("/edit", web.Bind(forms.CreateProjectForm{}), repo.EditProjectPost)
 					m.Post("/{action:open|close}", repo.ChangeProjectStatus)
 
+					m.Group("/fields", func() {
+						m.Get("", repo.ProjectFields)
+						m.Post("/new", web.Bind(forms.ProjectFieldForm{}), repo.NewProjectFieldPost)
+						m.Group("/{fieldID}", func() {
+							m.Post("/edit", web.Bind(forms.ProjectFieldForm{}), repo.EditProjectFieldPost)
+							m.Post("/delete", repo.DeleteProjectFieldPost)
+							m.Post("/options/new", web.Bind(forms.ProjectFieldOptionForm{}), repo.NewProjectFieldOptionPost)
+							m.Post("/options/{optionID}/delete", repo.DeleteProjectFieldOptionPost)
+						})
+					})
+					m.Post("/items/{issueID}/fields", repo.UpdateProjectItemFieldsPost)
+					m.Group("/automation", func() {
+						m.Get("", repo.ProjectAutomation)
+						m.Post("/new", web.Bind(forms.ProjectAutomationForm{}), repo.NewProjectAutomationPost)
+						m.Post("/{automationID}/delete", repo.DeleteProjectAutomationPost)
+					})
+					m.Group("/views", func() {
+						m.Post("/new", web.Bind(forms.ProjectViewForm{}), repo.NewProjectViewPost)
+						m.Post("/{viewID}/edit", web.Bind(forms.ProjectViewForm{}), repo.EditProjectViewPost)
+						m.Post("/{viewID}/delete", repo.DeleteProjectViewPost)
+					})
+
 					m.Group("/{boardID}", func() {
 						m.Put("", web.Bind(forms.EditProjectBoardForm{}), repo.EditProjectBoard)
 						m.Delete("", repo.DeleteProjectBoard)
@@ -1434,6 +1550,7 @@ func registerRoutes(m *web.Route) {
 				m.Group("/reviews", func() {
 					m.Get("/new_comment", repo.RenderNewCodeCommentForm)
 					m.Post("/comments", web.Bind(forms.CodeCommentForm{}), repo.SetShowOutdatedComments, repo.CreateCodeComment)
+					m.Post("/suggestions", web.Bind(forms.ApplySuggestionsForm{}), repo.ApplySuggestions)
 					m.Post("/submit", web.Bind(forms.SubmitReviewForm{}), repo.SubmitReview)
 				}, context.RepoMustNotBeArchived())
 			})
@@ -68,6 +68,7 @@ func stopTasks(ctx context.Context, opts actions_model.FindTaskOptions) error {
 	}
 
 	CreateCommitStatus(ctx, jobs...)
+	NotifyWorkflowJobsStatus(ctx, jobs...)
 
 	return nil
 }
@@ -95,6 +96,7 @@ func CancelAbandonedJobs(ctx context.Context) error {
 			// go on
 		}
 		CreateCommitStatus(ctx, job)
+		NotifyWorkflowJobsStatus(ctx, job)
 	}
 
 	return nil
@@ -48,6 +48,7 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 	if err != nil {
 		return err
 	}
+	var updated []*actions_model.ActionRunJob
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
 		for _, job := range jobs {
@@ -63,6 +64,7 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 				} else if n != 1 {
 					return fmt.Errorf("no affected for updating blocked job %v", job.ID)
 				}
+				updated = append(updated, job)
 			}
 		}
 		return nil
@@ -70,6 +72,7 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 		return err
 	}
 	CreateCommitStatus(ctx, jobs...)
+	NotifyWorkflowJobsStatus(ctx, updated...)
 	return nil
 }
 
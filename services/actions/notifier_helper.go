@@ -279,6 +279,8 @@ func handleWorkflows(
 			continue
 		}
 		CreateCommitStatus(ctx, alljobs...)
+		NotifyWorkflowRunRequested(ctx, run.ID)
+		NotifyWorkflowJobsStatus(ctx, alljobs...)
 	}
 	return nil
 }
@@ -128,6 +128,13 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 		return err
 	}
 
+	jobs, _, err := actions_model.FindRunJobs(ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		return err
+	}
+	NotifyWorkflowRunRequested(ctx, run.ID)
+	NotifyWorkflowJobsStatus(ctx, jobs...)
+
 	// Return nil if no errors occurred
 	return nil
 }
@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// NotifyWorkflowRunRequested notifies that a run has been queued, either because it has been triggered or
+// because some of its jobs are run again.
+// It won't return an error, but will log it, because it's not critical.
+func NotifyWorkflowRunRequested(ctx context.Context, runID int64) {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		log.Error("Failed to get run %d: %v", runID, err)
+		return
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("Failed to load attributes of run %d: %v", run.ID, err)
+		return
+	}
+	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+}
+
+// NotifyWorkflowJobsStatus notifies the new status of the given jobs. It also notifies the status of their runs
+// when a job starts a run or completes it.
+// It won't return an error, but will log it, because it's not critical.
+func NotifyWorkflowJobsStatus(ctx context.Context, jobs ...*actions_model.ActionRunJob) {
+	runJobs := make(map[int64][]*actions_model.ActionRunJob)
+	for _, v := range jobs {
+		// the job may have been updated by stopping its task, so it is reloaded
+		job, err := actions_model.GetRunJobByID(ctx, v.ID)
+		if err != nil {
+			log.Error("Failed to get job %d: %v", v.ID, err)
+			continue
+		}
+		if err := job.LoadAttributes(ctx); err != nil {
+			log.Error("Failed to load attributes of job %d: %v", job.ID, err)
+			continue
+		}
+		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job)
+		runJobs[job.RunID] = append(runJobs[job.RunID], job)
+	}
+
+	for runID, changed := range runJobs {
+		if err := notifyWorkflowRunStatus(ctx, runID, changed); err != nil {
+			log.Error("Failed to notify the status of run %d: %v", runID, err)
+		}
+	}
+}
+
+// notifyWorkflowRunStatus notifies the status of a run if the changed jobs have started it or completed it
+func notifyWorkflowRunStatus(ctx context.Context, runID int64, changed []*actions_model.ActionRunJob) error {
+	// the status of the run has been aggregated when its jobs were updated, so it is reloaded
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	changedIDs := make(container.Set[int64], len(changed))
+	for _, job := range changed {
+		changedIDs.Add(job.ID)
+	}
+	changedStatus := make(map[actions_model.Status]bool, len(changed))
+	for _, job := range jobs {
+		if changedIDs.Contains(job.ID) {
+			changedStatus[job.Status] = true
+		}
+	}
+
+	switch {
+	case run.Status.IsDone():
+		// only the jobs completing the run may notify its completion
+		for status := range changedStatus {
+			if status.IsDone() {
+				notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+				return nil
+			}
+		}
+	case run.Status.IsRunning():
+		// the run has started if a changed job is running and no other job has run yet
+		if !changedStatus[actions_model.StatusRunning] {
+			return nil
+		}
+		for _, job := range jobs {
+			if !changedIDs.Contains(job.ID) && (job.Status.IsRunning() || job.Status.IsDone()) {
+				return nil
+			}
+		}
+		notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ToActionsStatus converts the status of a run or a job to the status and the conclusion used by the API
+func ToActionsStatus(status actions_model.Status) (string, string) {
+	switch {
+	case status.IsDone():
+		return "completed", status.String()
+	case status.IsRunning():
+		return "in_progress", ""
+	default:
+		return "queued", ""
+	}
+}
+
+func timestampPtr(ts timeutil.TimeStamp) *time.Time {
+	if ts.IsZero() {
+		return nil
+	}
+	return ts.AsTimePtr()
+}
+
+// ToActionWorkflowRun converts an Actions run to API format
+func ToActionWorkflowRun(ctx context.Context, run *actions_model.ActionRun) (*api.ActionWorkflowRun, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	status, conclusion := ToActionsStatus(run.Status)
+	var completed *time.Time
+	if run.Status.IsDone() {
+		completed = timestampPtr(run.Stopped)
+	}
+	return &api.ActionWorkflowRun{
+		ID:           run.ID,
+		RunNumber:    run.Index,
+		WorkflowID:   run.WorkflowID,
+		DisplayTitle: run.Title,
+		Event:        string(run.Event),
+		HeadBranch:   git.RefName(run.Ref).ShortName(),
+		HeadSha:      run.CommitSHA,
+		Status:       status,
+		Conclusion:   conclusion,
+		HTMLURL:      run.HTMLURL(),
+		Actor:        ToUser(ctx, run.TriggerUser, nil),
+		Created:      run.Created.AsTime(),
+		Updated:      run.Updated.AsTime(),
+		Started:      timestampPtr(run.Started),
+		Completed:    completed,
+	}, nil
+}
+
+// ToActionWorkflowJob converts a job of an Actions run to API format
+func ToActionWorkflowJob(ctx context.Context, job *actions_model.ActionRunJob) (*api.ActionWorkflowJob, error) {
+	if err := job.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	// the job is linked by its position in the run
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	for i, v := range jobs {
+		if v.ID == job.ID {
+			index = i
+			break
+		}
+	}
+
+	var runnerID int64
+	var runnerName string
+	if job.TaskID != 0 {
+		task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if runner, err := actions_model.GetRunnerByID(ctx, task.RunnerID); err == nil {
+			runnerID, runnerName = runner.ID, runner.Name
+		}
+	}
+
+	status, conclusion := ToActionsStatus(job.Status)
+	var completed *time.Time
+	if job.Status.IsDone() {
+		completed = timestampPtr(job.Stopped)
+	}
+	return &api.ActionWorkflowJob{
+		ID:         job.ID,
+		RunID:      job.RunID,
+		RunNumber:  job.Run.Index,
+		RunAttempt: job.Attempt,
+		Name:       job.Name,
+		HeadBranch: git.RefName(job.Run.Ref).ShortName(),
+		HeadSha:    job.CommitSHA,
+		Status:     status,
+		Conclusion: conclusion,
+		HTMLURL:    fmt.Sprintf("%s/jobs/%d", job.Run.HTMLURL(), index),
+		Labels:     job.RunsOn,
+		RunnerID:   runnerID,
+		RunnerName: runnerName,
+		Created:    job.Created.AsTime(),
+		Started:    timestampPtr(job.Started),
+		Completed:  completed,
+	}, nil
+}
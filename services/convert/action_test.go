@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToActionsStatus(t *testing.T) {
+	cases := []struct {
+		status     actions_model.Status
+		apiStatus  string
+		conclusion string
+	}{
+		{actions_model.StatusBlocked, "queued", ""},
+		{actions_model.StatusWaiting, "queued", ""},
+		{actions_model.StatusRunning, "in_progress", ""},
+		{actions_model.StatusSuccess, "completed", "success"},
+		{actions_model.StatusCancelled, "completed", "cancelled"},
+	}
+	for _, c := range cases {
+		status, conclusion := ToActionsStatus(c.status)
+		assert.Equal(t, c.apiStatus, status, c.status.String())
+		assert.Equal(t, c.conclusion, conclusion, c.status.String())
+	}
+}
+
+func TestToActionWorkflowRun(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: 791})
+
+	apiRun, err := ToActionWorkflowRun(db.DefaultContext, run)
+	require.NoError(t, err)
+	assert.EqualValues(t, 187, apiRun.RunNumber)
+	assert.Equal(t, "artifact.yaml", apiRun.WorkflowID)
+	assert.Equal(t, "master", apiRun.HeadBranch)
+	assert.Equal(t, "completed", apiRun.Status)
+	assert.Equal(t, "success", apiRun.Conclusion)
+	assert.Equal(t, setting.AppURL+"user5/repo4/actions/runs/187", apiRun.HTMLURL)
+	assert.Equal(t, "user1", apiRun.Actor.UserName)
+	assert.NotNil(t, apiRun.Completed)
+}
+
+func TestToActionWorkflowJob(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+
+	apiJob, err := ToActionWorkflowJob(db.DefaultContext, job)
+	require.NoError(t, err)
+	assert.EqualValues(t, 791, apiJob.RunID)
+	assert.EqualValues(t, 187, apiJob.RunNumber)
+	assert.Equal(t, "job_2", apiJob.Name)
+	assert.Equal(t, "completed", apiJob.Status)
+	assert.Equal(t, "success", apiJob.Conclusion)
+	assert.Equal(t, setting.AppURL+"user5/repo4/actions/runs/187/jobs/0", apiJob.HTMLURL)
+	assert.NotNil(t, apiJob.Started)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ToCodeSearchResult convert a code search result of a repository to api.CodeSearchResult
+func ToCodeSearchResult(repo *repo_model.Repository, result *code_indexer.Result) *api.CodeSearchResult {
+	lines := make([]*api.CodeSearchLine, len(result.LineNumbers))
+	for i, number := range result.LineNumbers {
+		lines[i] = &api.CodeSearchLine{
+			Number:  number,
+			Content: result.RawLines[i],
+		}
+	}
+
+	apiResult := &api.CodeSearchResult{
+		Path:     result.Filename,
+		CommitID: result.CommitID,
+		Language: result.Language,
+		HTMLURL:  repo.HTMLURL() + "/src/commit/" + util.PathEscapeSegments(result.CommitID) + "/" + util.PathEscapeSegments(result.Filename),
+		Lines:    lines,
+	}
+	if result.Symbol != nil {
+		apiResult.Symbol = &api.CodeSymbol{
+			Name: result.Symbol.Name,
+			Kind: string(result.Symbol.Kind),
+			Line: result.Symbol.Line,
+		}
+	}
+	return apiResult
+}
+
+// ToCodeSearchResults convert the results of a code search in a repository to api.CodeSearchResults
+func ToCodeSearchResults(repo *repo_model.Repository, total int, results []*code_indexer.Result, languages []*code_indexer.SearchResultLanguages, truncated bool) *api.CodeSearchResults {
+	apiResults := &api.CodeSearchResults{
+		TotalCount: int64(total),
+		Truncated:  truncated,
+		Items:      make([]*api.CodeSearchResult, len(results)),
+		Languages:  make([]*api.CodeSearchLanguage, len(languages)),
+	}
+	for i, result := range results {
+		apiResults.Items[i] = ToCodeSearchResult(repo, result)
+	}
+	for i, language := range languages {
+		apiResults.Languages[i] = &api.CodeSearchLanguage{
+			Language: language.Language,
+			Color:    language.Color,
+			Count:    language.Count,
+		}
+	}
+	return apiResults
+}
@@ -109,6 +109,7 @@ func toIssue(ctx context.Context, issue *issues_model.Issue, getDownloadURL func
 	if issue.DeadlineUnix != 0 {
 		apiIssue.Deadline = issue.DeadlineUnix.AsTimePtr()
 	}
+	apiIssue.TimeEstimate = issue.TimeEstimate
 
 	return apiIssue
 }
@@ -200,6 +201,27 @@ func ToTrackedTimeList(ctx context.Context, tl issues_model.TrackedTimeList) api
 	return result
 }
 
+// ToTrackedTimeReport converts TrackedTimeReport to API format
+func ToTrackedTimeReport(report *issues_model.TrackedTimeReport) *api.TrackedTimeReport {
+	result := &api.TrackedTimeReport{
+		Group:    string(report.Group),
+		Entries:  make([]*api.TrackedTimeReportEntry, 0, len(report.Entries)),
+		Time:     report.Time,
+		Estimate: report.Estimate,
+		Issues:   report.Issues,
+	}
+	for _, entry := range report.Entries {
+		result.Entries = append(result.Entries, &api.TrackedTimeReportEntry{
+			ID:       entry.ID,
+			Name:     entry.Name,
+			Time:     entry.Time,
+			Estimate: entry.Estimate,
+			Issues:   entry.Issues,
+		})
+	}
+	return result
+}
+
 // ToLabel converts Label to API format
 func ToLabel(label *issues_model.Label, repo *repo_model.Repository, org *user_model.User) *api.Label {
 	result := &api.Label{
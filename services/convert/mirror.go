@@ -20,5 +20,8 @@ func ToPushMirror(pm *repo_model.PushMirror) (*api.PushMirror, error) {
 		LastError:      pm.LastError,
 		Interval:       pm.Interval.String(),
 		SyncOnCommit:   pm.SyncOnCommit,
+		RefIncludes:    repo_model.SplitRefPatterns(pm.RefIncludes),
+		RefExcludes:    repo_model.SplitRefPatterns(pm.RefExcludes),
+		NonDestructive: pm.NonDestructive,
 	}, nil
 }
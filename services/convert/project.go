@@ -0,0 +1,95 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAPIProject converts Project to API format
+func ToAPIProject(ctx context.Context, p *project_model.Project) *api.Project {
+	apiProject := &api.Project{
+		ID:           p.ID,
+		Title:        p.Title,
+		Description:  p.Description,
+		State:        api.StateOpen,
+		OpenIssues:   p.NumOpenIssues(ctx),
+		ClosedIssues: p.NumClosedIssues(ctx),
+		Created:      p.CreatedUnix.AsTime(),
+		Updated:      p.UpdatedUnix.AsTime(),
+	}
+	if p.IsClosed {
+		apiProject.State = api.StateClosed
+		apiProject.Closed = p.ClosedDateUnix.AsTimePtr()
+	}
+	if p.RepoID > 0 {
+		if err := p.LoadRepo(ctx); err != nil {
+			log.Error("LoadRepo: %v", err)
+		} else {
+			apiProject.HTMLURL = fmt.Sprintf("%s/projects/%d", p.Repo.HTMLURL(), p.ID)
+		}
+	} else if err := p.LoadOwner(ctx); err != nil {
+		log.Error("LoadOwner: %v", err)
+	} else {
+		apiProject.HTMLURL = fmt.Sprintf("%s/-/projects/%d", p.Owner.HTMLURL(), p.ID)
+	}
+	return apiProject
+}
+
+// ToAPIProjectFieldSelectOption converts FieldOption to API format
+func ToAPIProjectFieldSelectOption(o *project_model.FieldOption) *api.ProjectFieldSelectOption {
+	return &api.ProjectFieldSelectOption{
+		ID:        o.ID,
+		Name:      o.Name,
+		Color:     o.Color,
+		Sorting:   o.Sorting,
+		StartDate: o.FormatStartDate(),
+		EndDate:   o.FormatEndDate(),
+	}
+}
+
+// ToAPIProjectField converts Field to API format
+func ToAPIProjectField(f *project_model.Field) *api.ProjectField {
+	apiField := &api.ProjectField{
+		ID:      f.ID,
+		Name:    f.Name,
+		Type:    f.Type.String(),
+		Sorting: f.Sorting,
+		Options: make([]*api.ProjectFieldSelectOption, 0, len(f.Options)),
+	}
+	for _, option := range f.Options {
+		apiField.Options = append(apiField.Options, ToAPIProjectFieldSelectOption(option))
+	}
+	return apiField
+}
+
+// ToAPIProjectFieldValue converts FieldValue to API format
+func ToAPIProjectFieldValue(f *project_model.Field, v *project_model.FieldValue) *api.ProjectFieldValue {
+	return &api.ProjectFieldValue{
+		FieldID:      f.ID,
+		Value:        f.FormatValue(v),
+		DisplayValue: f.DisplayValue(v),
+	}
+}
+
+// ToAPIProjectItem converts an issue of a project with the values of its fields to API format
+func ToAPIProjectItem(ctx context.Context, issue *issues_model.Issue, fields project_model.FieldList, values project_model.FieldValueMap) *api.ProjectItem {
+	item := &api.ProjectItem{
+		Issue:       ToAPIIssue(ctx, issue),
+		BoardID:     issue.ProjectBoardID(),
+		FieldValues: make([]*api.ProjectFieldValue, 0, len(fields)),
+	}
+	for _, field := range fields {
+		if v := values.Get(issue.ID, field.ID); v != nil {
+			item.FieldValues = append(item.FieldValues, ToAPIProjectFieldValue(field, v))
+		}
+	}
+	return item
+}
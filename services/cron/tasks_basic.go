@@ -15,11 +15,13 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/auth"
+	"code.gitea.io/gitea/services/mailer"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	sla_service "code.gitea.io/gitea/services/sla"
 )
 
 func registerUpdateMirrorTask() {
@@ -127,6 +129,24 @@ func registerUpdateMigrationPosterID() {
 	})
 }
 
+func registerSyncMigratedRepositories() {
+	type SyncMigratedRepositoriesConfig struct {
+		BaseConfig
+		Limit int
+	}
+
+	RegisterTaskFatal("sync_migrated_repositories", &SyncMigratedRepositoriesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 10m",
+		},
+		Limit: 50,
+	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
+		return migrations.SyncMigratedRepositories(ctx, cfg.(*SyncMigratedRepositoriesConfig).Limit)
+	})
+}
+
 func registerCleanupHookTaskTable() {
 	RegisterTaskFatal("cleanup_hook_task_table", &CleanupHookTaskConfig{
 		BaseConfig: BaseConfig{
@@ -171,6 +191,26 @@ func registerActionsCleanup() {
 	})
 }
 
+func registerEscalateSLAs() {
+	RegisterTaskFatal("escalate_slas", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 10m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return sla_service.EscalateOverdueSLAs(ctx)
+	})
+}
+
+func registerSendNotificationDigests() {
+	RegisterTaskFatal("send_notification_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return mailer.SendNotificationDigests(ctx)
+	})
+}
+
 func initBasicTasks() {
 	if setting.Mirror.Enabled {
 		registerUpdateMirrorTask()
@@ -182,6 +222,7 @@ func initBasicTasks() {
 	registerDeletedBranchesCleanup()
 	if !setting.Repository.DisableMigrations {
 		registerUpdateMigrationPosterID()
+		registerSyncMigratedRepositories()
 	}
 	registerCleanupHookTaskTable()
 	if setting.Packages.Enabled {
@@ -190,4 +231,6 @@ func initBasicTasks() {
 	if setting.Actions.Enabled {
 		registerActionsCleanup()
 	}
+	registerEscalateSLAs()
+	registerSendNotificationDigests()
 }
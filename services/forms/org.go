@@ -74,3 +74,17 @@ func (f *CreateTeamForm) Validate(req *http.Request, errs binding.Errors) bindin
 	ctx := context.GetValidateContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// IssueTypeForm form for creating and editing an issue type
+type IssueTypeForm struct {
+	Name        string `binding:"Required;MaxSize(50)"`
+	Description string `binding:"MaxSize(255)"`
+	Color       string `binding:"MaxSize(7)"`
+	Template    string
+}
+
+// Validate validates the fields
+func (f *IssueTypeForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
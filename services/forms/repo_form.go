@@ -84,6 +84,7 @@ type MigrateRepoForm struct {
 	PullRequests   bool   `json:"pull_requests"`
 	Releases       bool   `json:"releases"`
 	MirrorInterval string `json:"mirror_interval"`
+	SyncInterval   string `json:"sync_interval"`
 }
 
 // Validate validates the fields
@@ -115,24 +116,37 @@ func ParseRemoteAddr(remoteAddr, authUsername, authPassword string) (string, err
 
 // RepoSettingForm form for changing repository settings
 type RepoSettingForm struct {
-	RepoName               string `binding:"Required;AlphaDashDot;MaxSize(100)"`
-	Description            string `binding:"MaxSize(2048)"`
-	Website                string `binding:"ValidUrl;MaxSize(1024)"`
-	Interval               string
-	MirrorAddress          string
-	MirrorUsername         string
-	MirrorPassword         string
-	LFS                    bool   `form:"mirror_lfs"`
-	LFSEndpoint            string `form:"mirror_lfs_endpoint"`
-	PushMirrorID           string
-	PushMirrorAddress      string
-	PushMirrorUsername     string
-	PushMirrorPassword     string
-	PushMirrorSyncOnCommit bool
-	PushMirrorInterval     string
-	Private                bool
-	Template               bool
-	EnablePrune            bool
+	RepoName                 string `binding:"Required;AlphaDashDot;MaxSize(100)"`
+	Description              string `binding:"MaxSize(2048)"`
+	Website                  string `binding:"ValidUrl;MaxSize(1024)"`
+	Interval                 string
+	MirrorAddress            string
+	MirrorUsername           string
+	MirrorPassword           string
+	LFS                      bool   `form:"mirror_lfs"`
+	LFSEndpoint              string `form:"mirror_lfs_endpoint"`
+	PushMirrorID             string
+	PushMirrorAddress        string
+	PushMirrorUsername       string
+	PushMirrorPassword       string
+	PushMirrorSyncOnCommit   bool
+	PushMirrorInterval       string
+	PushMirrorRefIncludes    string
+	PushMirrorRefExcludes    string
+	PushMirrorNonDestructive bool
+	PartialMirrorID          string
+	PartialMirrorAddress     string
+	PartialMirrorUsername    string
+	PartialMirrorPassword    string
+	PartialMirrorBranches    string
+	PartialMirrorDivergence  string
+	PartialMirrorInterval    string
+	PartialMirrorLFS         bool
+	PartialMirrorLFSEndpoint string
+	MigrationSyncInterval    string
+	Private                  bool
+	Template                 bool
+	EnablePrune              bool
 
 	// Advanced settings
 	EnableCode                            bool
@@ -170,6 +184,10 @@ type RepoSettingForm struct {
 	// Signing Settings
 	TrustModel string
 
+	// Code search settings
+	CodeIndexerBranches string
+	CodeIndexerTags     string
+
 	// Admin settings
 	EnableHealthCheck  bool
 	RequestReindexType string
@@ -251,6 +269,13 @@ type WebhookForm struct {
 	Wiki                     bool
 	Repository               bool
 	Package                  bool
+	WorkflowRun              bool
+	WorkflowJob              bool
+	BranchProtection         bool
+	Member                   bool
+	Membership               bool
+	DeployKey                bool
+	RepositoryVisibility     bool
 	Active                   bool
 	BranchFilter             string `binding:"GlobPattern"`
 	AuthorizationHeader      string
@@ -427,6 +452,25 @@ func (f *NewPackagistHookForm) Validate(req *http.Request, errs binding.Errors)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// NewCustomHookForm form for creating custom hook
+type NewCustomHookForm struct {
+	PayloadURL          string `binding:"Required;ValidUrl"`
+	HTTPMethod          string `binding:"Required;In(POST,PUT,PATCH)"`
+	Secret              string
+	ContentTypeTemplate string `binding:"MaxSize(255)"`
+	HeadersTemplate     string
+	BodyTemplate        string
+	PreviewEvent        string
+	Preview             bool
+	WebhookForm
+}
+
+// Validate validates the fields
+func (f *NewCustomHookForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
@@ -537,6 +581,65 @@ type EditProjectBoardForm struct {
 	Color   string `binding:"MaxSize(7)"`
 }
 
+// ProjectFieldForm is a form for creating or editing a project field
+type ProjectFieldForm struct {
+	Name    string `binding:"Required;MaxSize(255)"`
+	Type    string
+	Sorting int64
+	// the options of a new single select field, one per line
+	Options string
+}
+
+// Validate validates the fields
+func (f *ProjectFieldForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ProjectFieldOptionForm is a form for adding an option or an iteration to a project field
+type ProjectFieldOptionForm struct {
+	Name      string `binding:"Required;MaxSize(255)"`
+	Color     string `binding:"MaxSize(7)"`
+	StartDate string
+	EndDate   string
+}
+
+// Validate validates the fields
+func (f *ProjectFieldOptionForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ProjectViewForm is a form for saving a table or a roadmap view of a project
+type ProjectViewForm struct {
+	Name       string `binding:"Required;MaxSize(255)"`
+	Type       string
+	Sorting    int64
+	GroupBy    string
+	SortBy     string
+	SortOrder  string
+	StartField int64
+}
+
+// Validate validates the fields
+func (f *ProjectViewForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ProjectAutomationForm is a form for adding an automation to a project
+type ProjectAutomationForm struct {
+	Event string `binding:"Required"`
+	Board int64
+	Label int64
+}
+
+// Validate validates the fields
+func (f *ProjectAutomationForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //    _____  .__.__                   __
 //   /     \ |__|  |   ____   _______/  |_  ____   ____   ____
 //  /  \ /  \|  |  | _/ __ \ /  ___/\   __\/  _ \ /    \_/ __ \
@@ -624,6 +727,7 @@ type CodeCommentForm struct {
 	Origin         string `binding:"Required;In(timeline,diff)"`
 	Content        string `binding:"Required"`
 	Side           string `binding:"Required;In(previous,proposed)"`
+	StartLine      int64  `form:"start_line"`
 	Line           int64
 	TreePath       string `form:"path" binding:"Required"`
 	SingleReview   bool   `form:"single_review"`
@@ -637,6 +741,18 @@ func (f *CodeCommentForm) Validate(req *http.Request, errs binding.Errors) bindi
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ApplySuggestionsForm form for committing the suggested changes of code comments
+type ApplySuggestionsForm struct {
+	CommentIDs string `form:"comment_ids" binding:"Required"`
+	Message    string
+}
+
+// Validate validates the fields
+func (f *ApplySuggestionsForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // SubmitReviewForm for submitting a finished code review
 type SubmitReviewForm struct {
 	Content  string
@@ -886,6 +1002,18 @@ func (f *AddTimeManuallyForm) Validate(req *http.Request, errs binding.Errors) b
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// TimeEstimateForm form that sets the time estimate of an issue, zero removes the estimate
+type TimeEstimateForm struct {
+	Hours   int `binding:"Range(0,100000)"`
+	Minutes int `binding:"Range(0,1000)"`
+}
+
+// Validate validates the fields
+func (f *TimeEstimateForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // SaveTopicForm form for save topics for repository
 type SaveTopicForm struct {
 	Topics []string `binding:"topics;Required;"`
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package forms
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// ImportIssuesForm form for importing issues into a repository
+type ImportIssuesForm struct {
+	File *multipart.FileHeader
+	// UserMap maps the users of the file to local users, one `name=local_name` per line
+	UserMap string
+}
+
+// Validate validates the fields
+func (f *ImportIssuesForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package forms
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/web/middleware"
+
+	"gitea.com/go-chi/binding"
+)
+
+// SLAPolicyForm form for creating or editing a SLA policy
+type SLAPolicyForm struct {
+	Name            string `binding:"Required;MaxSize(255)"`
+	LabelID         int64
+	FirstResponse   string `binding:"MaxSize(20)"`
+	Resolve         string `binding:"MaxSize(20)"`
+	NotifyAssignees bool
+	EscalateUsers   string
+	EscalateTeams   string
+}
+
+// Validate validates the fields
+func (f *SLAPolicyForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -449,3 +449,18 @@ func (f *PackageSettingForm) Validate(req *http.Request, errs binding.Errors) bi
 	ctx := context.GetValidateContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// SavedSearchForm form for saving a search of the issues or pull requests overview
+type SavedSearchForm struct {
+	Name        string `binding:"Required;MaxSize(255)"`
+	Query       string
+	IsPull      bool
+	IsPublic    bool
+	OnDashboard bool
+}
+
+// Validate validates the fields
+func (f *SavedSearchForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
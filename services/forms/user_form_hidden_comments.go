@@ -52,6 +52,8 @@ var hiddenCommentTypeGroups = hiddenCommentTypeGroupsType{
 	"dependency": {
 		/*19*/ issues_model.CommentTypeAddDependency,
 		/*20*/ issues_model.CommentTypeRemoveDependency,
+		/*38*/ issues_model.CommentTypeAddSubIssue,
+		/*39*/ issues_model.CommentTypeRemoveSubIssue,
 	},
 	"lock": {
 		/*23*/ issues_model.CommentTypeLock,
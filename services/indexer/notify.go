@@ -9,7 +9,9 @@ import (
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	commit_indexer "code.gitea.io/gitea/modules/indexer/commits"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
 	"code.gitea.io/gitea/modules/log"
@@ -72,6 +74,9 @@ func (r *indexerNotifier) DeleteRepository(ctx context.Context, doer *user_model
 	if setting.Indexer.RepoIndexerEnabled {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
+	if setting.Indexer.CommitIndexerEnabled {
+		commit_indexer.UpdateRepoIndexer(repo)
+	}
 }
 
 func (r *indexerNotifier) MigrateRepository(ctx context.Context, doer, u *user_model.User, repo *repo_model.Repository) {
@@ -79,41 +84,78 @@ func (r *indexerNotifier) MigrateRepository(ctx context.Context, doer, u *user_m
 	if setting.Indexer.RepoIndexerEnabled && !repo.IsEmpty {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
+	if setting.Indexer.CommitIndexerEnabled && !repo.IsEmpty {
+		commit_indexer.UpdateRepoIndexer(repo)
+	}
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
 }
 
 func (r *indexerNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	updateCodeIndexer(repo, opts.RefFullName)
+	updateCommitIndexer(repo, opts.RefFullName)
 	if !opts.RefFullName.IsBranch() {
 		return
 	}
 
-	if setting.Indexer.RepoIndexerEnabled && opts.RefFullName.BranchName() == repo.DefaultBranch {
-		code_indexer.UpdateRepoIndexer(repo)
-	}
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
 }
 
 func (r *indexerNotifier) SyncPushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	updateCodeIndexer(repo, opts.RefFullName)
+	updateCommitIndexer(repo, opts.RefFullName)
 	if !opts.RefFullName.IsBranch() {
 		return
 	}
 
-	if setting.Indexer.RepoIndexerEnabled && opts.RefFullName.BranchName() == repo.DefaultBranch {
-		code_indexer.UpdateRepoIndexer(repo)
-	}
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
 }
 
+func (r *indexerNotifier) CreateRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName, refID string) {
+	updateCodeIndexer(repo, refFullName)
+}
+
+func (r *indexerNotifier) DeleteRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName) {
+	updateCodeIndexer(repo, refFullName)
+}
+
+func (r *indexerNotifier) SyncCreateRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName, refID string) {
+	updateCodeIndexer(repo, refFullName)
+}
+
+func (r *indexerNotifier) SyncDeleteRef(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName) {
+	updateCodeIndexer(repo, refFullName)
+}
+
+// updateCodeIndexer queues the repository in the code indexer if the branch or tag is indexed
+func updateCodeIndexer(repo *repo_model.Repository, refFullName git.RefName) {
+	if !setting.Indexer.RepoIndexerEnabled {
+		return
+	}
+	if (refFullName.IsBranch() && refFullName.BranchName() == repo.DefaultBranch) || repo.IsCodeIndexerRef(refFullName) {
+		code_indexer.UpdateRepoIndexer(repo)
+	}
+}
+
+// updateCommitIndexer queues the repository in the commit indexer if the default branch has been pushed
+func updateCommitIndexer(repo *repo_model.Repository, refFullName git.RefName) {
+	if setting.Indexer.CommitIndexerEnabled && refFullName.IsBranch() && refFullName.BranchName() == repo.DefaultBranch {
+		commit_indexer.UpdateRepoIndexer(repo)
+	}
+}
+
 func (r *indexerNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 	if setting.Indexer.RepoIndexerEnabled && !repo.IsEmpty {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
+	if setting.Indexer.CommitIndexerEnabled && !repo.IsEmpty {
+		commit_indexer.UpdateRepoIndexer(repo)
+	}
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
@@ -135,6 +177,14 @@ func (r *indexerNotifier) IssueChangeStatus(ctx context.Context, doer *user_mode
 	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
 }
 
+func (r *indexerNotifier) IssueChangeParent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldParentID int64) {
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+}
+
+func (r *indexerNotifier) IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+}
+
 func (r *indexerNotifier) IssueChangeAssignee(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, assignee *user_model.User, removed bool, comment *issues_model.Comment) {
 	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
 }
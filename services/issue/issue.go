@@ -271,6 +271,8 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 		&issues_model.Comment{},
 		&issues_model.IssueLabel{},
 		&issues_model.IssueDependency{},
+		&issues_model.IssueParent{},
+		&issues_model.IssueFieldValue{},
 		&issues_model.IssueAssignees{},
 		&issues_model.IssueUser{},
 		&activities_model.Notification{},
@@ -279,6 +281,7 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 		&issues_model.Stopwatch{},
 		&issues_model.TrackedTime{},
 		&project_model.ProjectIssue{},
+		&project_model.FieldValue{},
 		&repo_model.Attachment{},
 		&issues_model.PullRequest{},
 	); err != nil {
@@ -299,6 +302,13 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 		return err
 	}
 
+	// Sub-issues, they can be in other repositories
+	if _, err := db.DeleteByBean(ctx, &issues_model.IssueParent{
+		ParentID: issue.ID,
+	}); err != nil {
+		return err
+	}
+
 	// delete from dependent issues
 	if _, err := db.DeleteByBean(ctx, &issues_model.Comment{
 		DependentIssueID: issue.ID,
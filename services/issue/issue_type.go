@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// SetIssueType changes the type of an issue and the values of its fields, a nil type removes the type.
+// The type must belong to the organization owning the repository and the values must be valid for its fields.
+func SetIssueType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, issueType *issues_model.IssueType, values map[string][]string) error {
+	if issue.IsPull {
+		return util.NewInvalidArgumentErrorf("pull requests have no type")
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+
+	var typeID int64
+	if issueType != nil {
+		if issueType.OrgID != issue.Repo.OwnerID {
+			return issues_model.ErrIssueTypeNotExist{ID: issueType.ID, OrgID: issue.Repo.OwnerID}
+		}
+		fields, err := issueType.Fields()
+		if err != nil {
+			return err
+		}
+		if err := issue_template.ValidateFieldValues(fields, values); err != nil {
+			return util.NewInvalidArgumentErrorf("%v", err)
+		}
+		typeID = issueType.ID
+	}
+
+	oldTypeID := issue.TypeID
+	if err := issues_model.SetIssueType(ctx, issue, typeID, values); err != nil {
+		return err
+	}
+	issue.Type = issueType
+
+	notify_service.IssueChangeType(ctx, doer, issue, oldTypeID)
+	return nil
+}
+
+// DeleteIssueType deletes an issue type of an organization and updates the indexer for its issues
+func DeleteIssueType(ctx context.Context, orgID, id int64) error {
+	issueIDs, err := issues_model.GetIssueIDsByTypeID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := issues_model.DeleteIssueType(ctx, orgID, id); err != nil {
+		return err
+	}
+	for _, issueID := range issueIDs {
+		issue_indexer.UpdateIssueIndexer(ctx, issueID)
+	}
+	return nil
+}
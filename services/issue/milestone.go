@@ -16,12 +16,12 @@ import (
 func changeMilestoneAssign(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) error {
 	// Only check if milestone exists if we don't remove it.
 	if issue.MilestoneID > 0 {
-		has, err := issues_model.HasMilestoneByRepoID(ctx, issue.RepoID, issue.MilestoneID)
+		has, err := issues_model.HasMilestoneForRepo(ctx, issue.RepoID, issue.MilestoneID)
 		if err != nil {
-			return fmt.Errorf("HasMilestoneByRepoID: %w", err)
+			return fmt.Errorf("HasMilestoneForRepo: %w", err)
 		}
 		if !has {
-			return fmt.Errorf("HasMilestoneByRepoID: issue doesn't exist")
+			return fmt.Errorf("HasMilestoneForRepo: milestone doesn't exist")
 		}
 	}
 
@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// ChangeProjectAssign changes the project of an issue, 0 removes it from its project
+func ChangeProjectAssign(ctx context.Context, issue *issues_model.Issue, doer *user_model.User, newProjectID int64) error {
+	if err := issue.LoadProject(ctx); err != nil {
+		return err
+	}
+	var oldProjectID int64
+	if issue.Project != nil {
+		oldProjectID = issue.Project.ID
+	}
+
+	if err := issues_model.ChangeProjectAssign(issue, doer, newProjectID); err != nil {
+		return err
+	}
+
+	// the new project is loaded again when it is needed
+	issue.Project = nil
+	notify_service.IssueChangeProject(ctx, doer, issue, oldProjectID)
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CanViewSavedSearch checks if a user, nil for an anonymous viewer, can run a saved search: the public searches
+// can be run by everyone, the searches of a user by themselves and the searches of an organization by its members
+func CanViewSavedSearch(ctx context.Context, doer *user_model.User, s *issues_model.SavedSearch) (bool, error) {
+	if s.IsPublic {
+		return true, nil
+	}
+	if doer == nil {
+		return false, nil
+	}
+	if doer.IsAdmin || doer.ID == s.OwnerID {
+		return true, nil
+	}
+	return organization.IsOrganizationMember(ctx, s.OwnerID, doer.ID)
+}
+
+// CanEditSavedSearch checks if a user can change a saved search: the searches of a user can be changed by
+// themselves and the searches of an organization by their creator and the owners of the organization
+func CanEditSavedSearch(ctx context.Context, doer *user_model.User, s *issues_model.SavedSearch) (bool, error) {
+	if doer == nil {
+		return false, nil
+	}
+	if doer.IsAdmin || doer.ID == s.OwnerID {
+		return true, nil
+	}
+	isMember, err := organization.IsOrganizationMember(ctx, s.OwnerID, doer.ID)
+	if err != nil || !isMember {
+		return false, err
+	}
+	if doer.ID == s.CreatorID {
+		return true, nil
+	}
+	return organization.IsOrganizationOwner(ctx, s.OwnerID, doer.ID)
+}
+
+// SavedSearchOptions returns the options of the issue indexer to run a saved search for a viewer, nil for an
+// anonymous viewer. The issues are limited to the repositories of the owner of the search the viewer can access,
+// and the filters relative to the viewer, like "assigned to me", match nothing for an anonymous viewer.
+func SavedSearchOptions(ctx context.Context, viewer *user_model.User, s *issues_model.SavedSearch) (*issue_indexer.SearchOptions, error) {
+	owner, err := user_model.GetUserByID(ctx, s.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	values := s.Values()
+
+	sortType := values.Get("sort")
+	if sortType == "" {
+		sortType = "recentupdate"
+	}
+	opts := &issues_model.IssuesOptions{
+		IsPull:     util.OptionalBoolOf(s.IsPull),
+		IsClosed:   util.OptionalBoolOf(values.Get("state") == "closed"),
+		SortType:   sortType,
+		IsArchived: util.OptionalBoolFalse,
+		User:       viewer,
+	}
+
+	unitType := unit.TypeIssues
+	if s.IsPull {
+		unitType = unit.TypePullRequests
+	}
+	repoIDs, _, err := repo_model.SearchRepositoryIDs(&repo_model.SearchRepoOptions{
+		Actor:       viewer,
+		OwnerID:     owner.ID,
+		Private:     viewer != nil,
+		Collaborate: util.OptionalBoolNone,
+		UnitType:    unitType,
+		Archived:    util.OptionalBoolFalse,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if selected := parseRepoIDs(values.Get("repos")); len(selected) > 0 {
+		selected = slices.DeleteFunc(selected, func(id int64) bool {
+			return !slices.Contains(repoIDs, id)
+		})
+		repoIDs = selected
+	}
+	opts.RepoIDs = repoIDs
+
+	if viewType := values.Get("type"); viewType != "" && viewType != "your_repositories" {
+		if viewer == nil {
+			opts.RepoIDs = nil
+		} else {
+			switch viewType {
+			case "assigned":
+				opts.AssigneeID = viewer.ID
+			case "created_by":
+				opts.PosterID = viewer.ID
+			case "mentioned":
+				opts.MentionedID = viewer.ID
+			case "review_requested":
+				opts.ReviewRequestedID = viewer.ID
+			case "reviewed_by":
+				opts.ReviewedID = viewer.ID
+			}
+		}
+	}
+	if len(opts.RepoIDs) == 0 {
+		// no repos found, don't let the indexer return all repos
+		opts.RepoIDs = []int64{0}
+	}
+
+	if owner.IsOrganization() {
+		if milestoneID, _ := strconv.ParseInt(values.Get("milestone"), 10, 64); milestoneID > 0 {
+			opts.MilestoneIDs = []int64{milestoneID}
+		}
+	}
+	if labels := values.Get("labels"); labels != "" && labels != "0" {
+		if opts.LabelIDs, err = base.StringsToInt64s(strings.Split(labels, ",")); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid labels: %v", err)
+		}
+	}
+
+	keyword := strings.TrimSpace(values.Get("q"))
+	if keyword, opts.ParentID, err = issues_model.ParseParentKeyword(ctx, keyword, nil); err != nil {
+		return nil, err
+	}
+	var typeOwnerID int64
+	if owner.IsOrganization() {
+		typeOwnerID = owner.ID
+	}
+	if keyword, opts.TypeID, opts.FieldValues, err = issues_model.ParseIssueTypeKeyword(ctx, keyword, typeOwnerID); err != nil {
+		return nil, err
+	}
+	keyword, opts.SLAState = issues_model.ParseSLAKeyword(keyword)
+
+	return issue_indexer.ToSearchOptions(keyword, opts), nil
+}
+
+// FindSavedSearchIssues runs a saved search for a viewer and returns a page of its issues and their total count
+func FindSavedSearchIssues(ctx context.Context, viewer *user_model.User, s *issues_model.SavedSearch, listOptions db.ListOptions) (issues_model.IssueList, int64, error) {
+	opts, err := SavedSearchOptions(ctx, viewer, s)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts.Paginator = &listOptions
+
+	issueIDs, total, err := issue_indexer.SearchIssues(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, issueIDs, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	return issues, total, nil
+}
+
+// parseRepoIDs parses the "repos" parameter of the issues overview, like "[1,2]"
+func parseRepoIDs(reposQuery string) []int64 {
+	reposQuery = strings.Trim(reposQuery, "[]")
+	if reposQuery == "" {
+		return nil
+	}
+	var repoIDs []int64
+	for _, s := range strings.Split(reposQuery, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil && id > 0 {
+			repoIDs = append(repoIDs, id)
+		}
+	}
+	return repoIDs
+}
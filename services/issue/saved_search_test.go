@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedSearchPermissions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+
+	private := unittest.AssertExistsAndLoadBean(t, &issues_model.SavedSearch{ID: 1})
+	for _, c := range []struct {
+		doer             *user_model.User
+		canView, canEdit bool
+	}{
+		{nil, false, false},
+		{user2, true, true},
+		{user4, false, false},
+	} {
+		canView, err := CanViewSavedSearch(db.DefaultContext, c.doer, private)
+		assert.NoError(t, err)
+		assert.Equal(t, c.canView, canView)
+		canEdit, err := CanEditSavedSearch(db.DefaultContext, c.doer, private)
+		assert.NoError(t, err)
+		assert.Equal(t, c.canEdit, canEdit)
+	}
+
+	// the public search of org3 created by user2, user4 is a member of org3 and user5 is not
+	public := unittest.AssertExistsAndLoadBean(t, &issues_model.SavedSearch{ID: 2})
+	for _, c := range []struct {
+		doer             *user_model.User
+		canView, canEdit bool
+	}{
+		{nil, true, false},
+		{user2, true, true},
+		{user4, true, false},
+		{user5, true, false},
+	} {
+		canView, err := CanViewSavedSearch(db.DefaultContext, c.doer, public)
+		assert.NoError(t, err)
+		assert.Equal(t, c.canView, canView)
+		canEdit, err := CanEditSavedSearch(db.DefaultContext, c.doer, public)
+		assert.NoError(t, err)
+		assert.Equal(t, c.canEdit, canEdit)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+var (
+	// openTaskPattern matches an unchecked item of a markdown task list, as counted by Issue.GetTasks
+	openTaskPattern = regexp.MustCompile(`^(\s*[-*]\s\[\s\]\s+)(.*?)\s*$`)
+	// issueRefTaskPattern matches a task which is already an issue reference
+	issueRefTaskPattern = regexp.MustCompile(`^(?:[\w.-]+/[\w.-]+)?#\d+$`)
+)
+
+// AddSubIssue makes an issue a sub-issue of the parent
+func AddSubIssue(ctx context.Context, doer *user_model.User, parent, issue *issues_model.Issue) error {
+	if err := issues_model.AddSubIssue(ctx, doer, parent, issue); err != nil {
+		return err
+	}
+
+	notify_service.IssueChangeParent(ctx, doer, issue, 0)
+	return nil
+}
+
+// RemoveSubIssue removes an issue from the sub-issues of the parent
+func RemoveSubIssue(ctx context.Context, doer *user_model.User, parent, issue *issues_model.Issue) error {
+	if err := issues_model.RemoveSubIssue(ctx, doer, parent, issue); err != nil {
+		return err
+	}
+
+	notify_service.IssueChangeParent(ctx, doer, issue, parent.ID)
+	return nil
+}
+
+// OpenTasks returns the unchecked items of the task lists of an issue content which can be converted to sub-issues,
+// the items which are already issue references are skipped
+func OpenTasks(content string) []string {
+	var tasks []string
+	for _, line := range strings.Split(content, "\n") {
+		m := openTaskPattern.FindStringSubmatch(line)
+		if m == nil || m[2] == "" || issueRefTaskPattern.MatchString(m[2]) {
+			continue
+		}
+		tasks = append(tasks, m[2])
+	}
+	return tasks
+}
+
+// ConvertTaskToSubIssue creates a sub-issue from an unchecked item of the task lists of the parent, the item is
+// replaced by a reference to the new issue. The task is the index of the item in the list returned by OpenTasks.
+func ConvertTaskToSubIssue(ctx context.Context, doer *user_model.User, parent *issues_model.Issue, task int) (*issues_model.Issue, error) {
+	if err := parent.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(parent.Content, "\n")
+	line, title := -1, ""
+	for i, n := 0, 0; i < len(lines); i++ {
+		m := openTaskPattern.FindStringSubmatch(lines[i])
+		if m == nil || m[2] == "" || issueRefTaskPattern.MatchString(m[2]) {
+			continue
+		}
+		if n == task {
+			line, title = i, m[2]
+			break
+		}
+		n++
+	}
+	if line < 0 {
+		return nil, util.NewNotExistErrorf("task %d doesn't exist", task)
+	}
+	title, _ = util.SplitStringAtByteN(title, 255)
+
+	issue := &issues_model.Issue{
+		RepoID:   parent.RepoID,
+		Repo:     parent.Repo,
+		Title:    title,
+		PosterID: doer.ID,
+		Poster:   doer,
+	}
+	if err := NewIssue(ctx, parent.Repo, issue, nil, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := AddSubIssue(ctx, doer, parent, issue); err != nil {
+		return nil, err
+	}
+
+	m := openTaskPattern.FindStringSubmatch(lines[line])
+	lines[line] = fmt.Sprintf("%s#%d", m[1], issue.Index)
+	if strings.HasSuffix(m[0], "\r") {
+		lines[line] += "\r"
+	}
+	if err := ChangeContent(ctx, parent, doer, strings.Join(lines, "\n")); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
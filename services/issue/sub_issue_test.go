@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"fmt"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenTasks(t *testing.T) {
+	content := "Tasks:\n- [ ] first task\n- [x] done task\n* [ ]  second task  \r\n- [ ] #12\n- [ ] user2/repo1#3\n- [ ] \n  - [ ] nested task"
+	assert.EqualValues(t, []string{"first task", "second task", "nested task"}, OpenTasks(content))
+	assert.Empty(t, OpenTasks("no task"))
+}
+
+func TestConvertTaskToSubIssue(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	parent := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	parent.Content = "- [ ] first task\r\n- [ ] second task\r\n"
+
+	subIssue, err := ConvertTaskToSubIssue(db.DefaultContext, doer, parent, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "second task", subIssue.Title)
+	assert.EqualValues(t, parent.RepoID, subIssue.RepoID)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueParent{IssueID: subIssue.ID, ParentID: parent.ID})
+
+	parent = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Equal(t, fmt.Sprintf("- [ ] first task\r\n- [ ] #%d\r\n", subIssue.Index), parent.Content)
+
+	_, err = ConvertTaskToSubIssue(db.DefaultContext, doer, parent, 1)
+	assert.Error(t, err)
+}
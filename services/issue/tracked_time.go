@@ -0,0 +1,89 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"xorm.io/builder"
+)
+
+// TrackedTimeRepoIDs returns the ids of the repositories of an owner whose issues the doer can read, the times
+// tracked on their issues are part of the timesheets of the owner
+func TrackedTimeRepoIDs(ctx context.Context, owner, doer *user_model.User) ([]int64, error) {
+	return repo_model.SearchRepositoryIDsByCondition(ctx, builder.NewCond().And(
+		builder.Eq{"owner_id": owner.ID},
+		repo_model.AccessibleRepositoryCondition(doer, unit.TypeIssues),
+	))
+}
+
+// formatHours formats seconds as hours with two decimals, which spreadsheets can sum
+func formatHours(seconds int64) string {
+	return strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64)
+}
+
+// WriteTrackedTimeReportCSV writes a tracked time report as CSV, one row per group with the spent and the
+// estimated time in hours
+func WriteTrackedTimeReportCSV(w io.Writer, report *issues_model.TrackedTimeReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{string(report.Group) + "_id", string(report.Group), "issues", "spent_hours", "estimate_hours"}); err != nil {
+		return err
+	}
+	for _, entry := range report.Entries {
+		if err := writer.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Name,
+			strconv.Itoa(entry.Issues),
+			formatHours(entry.Time),
+			formatHours(entry.Estimate),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTimesheetCSV writes tracked times as CSV, one row per tracked time. The attributes of the times must be
+// loaded.
+func WriteTimesheetCSV(w io.Writer, times issues_model.TrackedTimeList) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "user", "repository", "issue", "title", "hours"}); err != nil {
+		return err
+	}
+	for _, t := range times {
+		var repoName, index, title string
+		if t.Issue != nil {
+			index, title = strconv.FormatInt(t.Issue.Index, 10), t.Issue.Title
+			if t.Issue.Repo != nil {
+				repoName = t.Issue.Repo.FullName()
+			}
+		}
+		var userName string
+		if t.User != nil {
+			userName = t.User.Name
+		}
+		if err := writer.Write([]string{
+			t.Created.Format(time.RFC3339),
+			userName,
+			repoName,
+			index,
+			title,
+			formatHours(t.Time),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
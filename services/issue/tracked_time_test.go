@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackedTimeRepoIDs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repoIDs, err := TrackedTimeRepoIDs(db.DefaultContext, owner, doer)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, repoIDs)
+
+	// an anonymous doer only gets the public repositories
+	publicIDs, err := TrackedTimeRepoIDs(db.DefaultContext, owner, nil)
+	assert.NoError(t, err)
+	assert.Subset(t, repoIDs, publicIDs)
+	assert.Less(t, len(publicIDs), len(repoIDs))
+}
+
+func TestWriteTrackedTimeReportCSV(t *testing.T) {
+	var sb strings.Builder
+	assert.NoError(t, WriteTrackedTimeReportCSV(&sb, &issues_model.TrackedTimeReport{
+		Group: issues_model.TrackedTimeReportGroupLabel,
+		Entries: []*issues_model.TrackedTimeReportEntry{
+			{ID: 1, Name: "bug, critical", Time: 5400, Estimate: 3600, Issues: 2},
+			{ID: 0, Time: 60, Issues: 1},
+		},
+	}))
+	assert.Equal(t, "label_id,label,issues,spent_hours,estimate_hours\n"+
+		"1,\"bug, critical\",2,1.50,1.00\n"+
+		"0,,1,0.02,0.00\n", sb.String())
+}
+
+func TestWriteTimesheetCSV(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	times, err := issues_model.GetTrackedTimes(db.DefaultContext, &issues_model.FindTrackedTimesOptions{IssueID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, times.LoadAttributes(db.DefaultContext))
+
+	var sb strings.Builder
+	assert.NoError(t, WriteTimesheetCSV(&sb, times))
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "date,user,repository,issue,title,hours", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",user1,user2/repo1,1,issue1,0.11"), lines[1])
+}
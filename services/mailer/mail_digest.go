@@ -0,0 +1,195 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplNotificationDigestMail base.TplName = "notify/digest"
+
+	digestBatchSize = 50
+)
+
+// digestEntry is a line of a digest email
+type digestEntry struct {
+	Kind  string
+	Title string
+	Link  string
+}
+
+// digestRepository groups the lines of a digest email by repository
+type digestRepository struct {
+	FullName string
+	Link     string
+	Entries  []*digestEntry
+}
+
+// SendNotificationDigests mails the digests whose period ended, they aggregate the unread notifications updated
+// during the period and the events kept for the digest
+func SendNotificationDigests(ctx context.Context) error {
+	if setting.MailService == nil || !setting.Service.EnableNotifyMail {
+		return nil
+	}
+
+	now := timeutil.TimeStampNow()
+	for {
+		digests, err := activities_model.FindDueNotificationDigests(ctx, now, digestBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(digests) == 0 {
+			return nil
+		}
+		for _, digest := range digests {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before sending the notification digest of user %d", digest.UserID)
+			default:
+			}
+			if err := sendNotificationDigest(ctx, digest, now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendNotificationDigest(ctx context.Context, digest *activities_model.NotificationDigest, until timeutil.TimeStamp) error {
+	user, err := user_model.GetUserByID(ctx, digest.UserID)
+	if err != nil && !user_model.IsErrUserNotExist(err) {
+		return err
+	}
+
+	if user != nil && user.IsMailable() {
+		notifications, err := activities_model.GetNotifications(ctx, &activities_model.FindNotificationOptions{
+			UserID:            user.ID,
+			Status:            []activities_model.NotificationStatus{activities_model.NotificationStatusUnread},
+			UpdatedAfterUnix:  int64(digest.LastSentUnix),
+			UpdatedBeforeUnix: int64(until) - 1,
+		})
+		if err != nil {
+			return err
+		}
+		if err := notifications.LoadAttributes(ctx); err != nil {
+			return err
+		}
+		items, err := activities_model.GetNotificationDigestItems(ctx, user.ID, until)
+		if err != nil {
+			return err
+		}
+
+		msg, err := composeNotificationDigest(ctx, user, digest, until, notifications, items)
+		if err != nil {
+			return err
+		}
+		if msg != nil {
+			SendAsync(msg)
+		}
+	}
+
+	if err := activities_model.DeleteNotificationDigestItems(ctx, digest.UserID, until); err != nil {
+		return err
+	}
+	return activities_model.UpdateNotificationDigestSent(ctx, digest, until)
+}
+
+// composeNotificationDigest returns the digest email of a user, or nil if there is nothing to tell
+func composeNotificationDigest(ctx context.Context, user *user_model.User, digest *activities_model.NotificationDigest, until timeutil.TimeStamp, notifications activities_model.NotificationList, items []*activities_model.NotificationDigestItem) (*Message, error) {
+	locale := translation.NewLocale(user.Language)
+
+	repos := make([]*digestRepository, 0, 10)
+	reposByID := make(map[int64]*digestRepository)
+	getRepo := func(repoID int64, repo *repo_model.Repository) (*digestRepository, error) {
+		if r, ok := reposByID[repoID]; ok {
+			return r, nil
+		}
+		if repo == nil {
+			var err error
+			if repo, err = repo_model.GetRepositoryByID(ctx, repoID); err != nil {
+				return nil, err
+			}
+		}
+		r := &digestRepository{FullName: repo.FullName(), Link: repo.HTMLURL()}
+		reposByID[repoID] = r
+		repos = append(repos, r)
+		return r, nil
+	}
+
+	count := 0
+	for _, n := range notifications {
+		if n.Repository == nil || (n.Issue == nil && n.Source != activities_model.NotificationSourceRepository) {
+			continue
+		}
+		r, err := getRepo(n.RepoID, n.Repository)
+		if err != nil {
+			return nil, err
+		}
+		entry := &digestEntry{Link: n.HTMLURL(ctx)}
+		switch n.Source {
+		case activities_model.NotificationSourceIssue:
+			entry.Kind = locale.Tr("mail.digest.kind.issue")
+			entry.Title = fmt.Sprintf("#%d %s", n.Issue.Index, n.Issue.Title)
+		case activities_model.NotificationSourcePullRequest:
+			entry.Kind = locale.Tr("mail.digest.kind.pull")
+			entry.Title = fmt.Sprintf("#%d %s", n.Issue.Index, n.Issue.Title)
+		default:
+			entry.Kind = locale.Tr("mail.digest.kind.repo")
+			entry.Title = n.Repository.FullName()
+		}
+		r.Entries = append(r.Entries, entry)
+		count++
+	}
+	for _, item := range items {
+		r, err := getRepo(item.RepoID, nil)
+		if repo_model.IsErrRepoNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		r.Entries = append(r.Entries, &digestEntry{
+			Kind:  locale.Tr("mail.digest.kind." + string(item.Event)),
+			Title: item.Title,
+			Link:  item.Link,
+		})
+		count++
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	subject := locale.Tr("mail.digest."+string(digest.Frequency)+".subject", count)
+	data := map[string]any{
+		"locale":       locale,
+		"Subject":      subject,
+		"Language":     locale.Language(),
+		"Since":        digest.LastSentUnix.AsLocalTime().Format("2006-01-02 15:04"),
+		"Until":        until.AsLocalTime().Format("2006-01-02 15:04"),
+		"Repositories": repos,
+		"Link":         setting.AppURL + "notifications",
+		"SettingsLink": setting.AppURL + "user/settings/notifications",
+	}
+
+	var mailBody bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplNotificationDigestMail), data); err != nil {
+		log.Error("ExecuteTemplate [%s]: %v", string(tplNotificationDigestMail)+"/body", err)
+		return nil, err
+	}
+
+	msg := NewMessage(user.Email, subject, mailBody.String())
+	msg.Info = fmt.Sprintf("UID: %d, notification digest", user.ID)
+	return msg, nil
+}
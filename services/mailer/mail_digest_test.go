@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"html/template"
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitByNotificationDelivery(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	event := activities_model.NotificationEventReleasePublished
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, user2.ID, 0, event, activities_model.NotificationDeliveryDigest))
+	assert.NoError(t, activities_model.SetNotificationDigestFrequency(db.DefaultContext, user2.ID, activities_model.NotificationDigestDaily))
+	// user4 collects the event without receiving digests, so it is mailed right away
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, user4.ID, 0, event, activities_model.NotificationDeliveryDigest))
+	assert.NoError(t, activities_model.SetNotificationPreference(db.DefaultContext, user5.ID, 1, event, activities_model.NotificationDeliveryNone))
+
+	mailed, digested, err := splitByNotificationDelivery(db.DefaultContext, []*user_model.User{user2, user4, user5}, 1, event)
+	assert.NoError(t, err)
+	assert.Equal(t, []*user_model.User{user4}, mailed)
+	assert.Equal(t, []*user_model.User{user2}, digested)
+}
+
+func TestSendNotificationDigests(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.MailService, &setting.Mailer{From: "test@gitea.com"})()
+	defer test.MockVariableValue(&setting.Service.EnableNotifyMail, true)()
+	defer test.MockVariableValue(&bodyTemplates, template.Must(template.New("notify/digest").Parse(
+		`{{range .Repositories}}{{.FullName}}:{{range .Entries}}[{{.Kind}}|{{.Title}}|{{.Link}}]{{end}};{{end}}`)))()
+
+	var msgs []*Message
+	defer test.MockVariableValue(&SendAsync, func(m ...*Message) { msgs = append(msgs, m...) })()
+
+	assert.NoError(t, activities_model.SetNotificationDigestFrequency(db.DefaultContext, 2, activities_model.NotificationDigestDaily))
+	digest, err := activities_model.GetNotificationDigest(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	// the digest covers the unread notifications of the fixtures
+	assert.NoError(t, activities_model.UpdateNotificationDigestSent(db.DefaultContext, digest, 946684800))
+	assert.NoError(t, activities_model.AddNotificationDigestItem(db.DefaultContext, &activities_model.NotificationDigestItem{
+		UserID: 2,
+		RepoID: 1,
+		Event:  activities_model.NotificationEventReleasePublished,
+		Title:  "v1.0",
+		Link:   "https://example.com/release",
+	}))
+	// an event kept in the second the digests are sent waits for the next digest
+	_, err = db.GetEngine(db.DefaultContext).Table("notification_digest_item").Where("user_id = ?", 2).
+		Update(map[string]any{"created_unix": 946684900})
+	assert.NoError(t, err)
+
+	assert.NoError(t, SendNotificationDigests(db.DefaultContext))
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, "user2@example.com", msgs[0].To)
+		assert.Equal(t, "user2/repo2:[mail.digest.kind.issue|#1 issue4|https://try.gitea.io/user2/repo2/issues/1];"+
+			"user2/repo1:[mail.digest.kind.issue|#4 issue5|https://try.gitea.io/user2/repo1/issues/4]"+
+			"[mail.digest.kind.release_published|v1.0|https://example.com/release];", msgs[0].Body)
+	}
+	unittest.AssertNotExistsBean(t, &activities_model.NotificationDigestItem{UserID: 2})
+
+	// the digest isn't due again until a day later
+	msgs = nil
+	assert.NoError(t, SendNotificationDigests(db.DefaultContext))
+	assert.Empty(t, msgs)
+}
@@ -126,6 +126,14 @@ func mailIssueCommentBatch(ctx *mailCommentContext, users []*user_model.User, vi
 		checkUnit = unit.TypePullRequests
 	}
 
+	// the watchers of the repository choose how they are notified of new issues, a mention is always mailed
+	if ctx.ActionType == activities_model.ActionCreateIssue && !fromMention {
+		var err error
+		if users, _, err = splitByNotificationDelivery(ctx, users, ctx.Issue.RepoID, activities_model.NotificationEventIssueOpened); err != nil {
+			return err
+		}
+	}
+
 	langMap := make(map[string][]*user_model.User)
 	for _, user := range users {
 		if !user.IsActive {
@@ -0,0 +1,69 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplMirrorDivergedMail base.TplName = "notify/mirror_diverged"
+)
+
+// MailPartialMirrorDiverged tells the recipients that branches of a repository diverged from the upstream branches
+// they are mirroring
+func MailPartialMirrorDiverged(ctx context.Context, m *repo_model.PartialMirror, branches []string, recipients []*user_model.User) error {
+	if setting.MailService == nil || len(recipients) == 0 || len(branches) == 0 {
+		return nil
+	}
+	repo, err := m.GetRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.mirror.diverged.subject", repo.FullName())
+		data := map[string]any{
+			"locale":     locale,
+			"Repository": repo.FullName(),
+			"Remote":     m.RemoteAddress,
+			"Branches":   strings.Join(branches, ", "),
+			"Strategy":   string(m.Divergence),
+			"Subject":    subject,
+			"Language":   locale.Language(),
+			"Link":       repo.HTMLURL() + "/settings",
+		}
+
+		var mailBody bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplMirrorDivergedMail), data); err != nil {
+			log.Error("ExecuteTemplate [%s]: %v", string(tplMirrorDivergedMail)+"/body", err)
+			return err
+		}
+
+		msgs := make([]*Message, 0, len(tos))
+		for _, to := range tos {
+			msg := NewMessage(to, subject, mailBody.String())
+			msg.Info = fmt.Sprintf("Partial mirror: %d, diverged", m.ID)
+			msgs = append(msgs, msg)
+		}
+		SendAsync(msgs...)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	activities_model "code.gitea.io/gitea/models/activities"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplWorkflowFailedMail base.TplName = "notify/workflow_failed"
+)
+
+// splitByNotificationDelivery returns the users to mail an event of a repository right away and the ones who
+// collect it into their digest, the users who collect it without receiving digests are mailed right away
+func splitByNotificationDelivery(ctx context.Context, users []*user_model.User, repoID int64, event activities_model.NotificationEvent) (mailed, digested []*user_model.User, err error) {
+	ids := make([]int64, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	deliveries, err := activities_model.GetNotificationDeliveries(ctx, ids, repoID, event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mailed = make([]*user_model.User, 0, len(users))
+	for _, user := range users {
+		switch deliveries[user.ID] {
+		case activities_model.NotificationDeliveryEmail:
+			mailed = append(mailed, user)
+		case activities_model.NotificationDeliveryDigest:
+			digest, err := activities_model.GetNotificationDigest(ctx, user.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if digest.Frequency == activities_model.NotificationDigestDisabled {
+				mailed = append(mailed, user)
+			} else {
+				digested = append(digested, user)
+			}
+		}
+	}
+	return mailed, digested, nil
+}
+
+// addNotificationDigestItems keeps an event without a notification of its own for the next digest of the users
+func addNotificationDigestItems(ctx context.Context, users []*user_model.User, repoID int64, event activities_model.NotificationEvent, title, link string) {
+	for _, user := range users {
+		if err := activities_model.AddNotificationDigestItem(ctx, &activities_model.NotificationDigestItem{
+			UserID: user.ID,
+			RepoID: repoID,
+			Event:  event,
+			Title:  title,
+			Link:   link,
+		}); err != nil {
+			log.Error("AddNotificationDigestItem [user: %d, repo: %d]: %v", user.ID, repoID, err)
+		}
+	}
+}
+
+// MailWorkflowRunFailed tells the watchers of a repository and the user who triggered a run that it failed,
+// only the users who chose to be notified of failed runs receive it
+func MailWorkflowRunFailed(ctx context.Context, run *actions_model.ActionRun) error {
+	if setting.MailService == nil {
+		return nil
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	ids, err := repo_model.GetRepoWatchersIDs(ctx, run.RepoID)
+	if err != nil {
+		return err
+	}
+	ids = append(ids, run.TriggerUserID)
+	users, err := user_model.GetMaileableUsersByIDs(ctx, ids, false)
+	if err != nil {
+		return err
+	}
+	readers := make([]*user_model.User, 0, len(users))
+	for _, user := range users {
+		if access_model.CheckRepoUnitUser(ctx, run.Repo, user, unit.TypeActions) {
+			readers = append(readers, user)
+		}
+	}
+
+	mailed, digested, err := splitByNotificationDelivery(ctx, readers, run.RepoID, activities_model.NotificationEventCIFailed)
+	if err != nil {
+		return err
+	}
+	workflow := path.Base(run.WorkflowID)
+	addNotificationDigestItems(ctx, digested, run.RepoID, activities_model.NotificationEventCIFailed, workflow+": "+run.Title, run.HTMLURL())
+
+	langMap := make(map[string][]string)
+	for _, user := range mailed {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.workflow.failed.subject", workflow, run.Repo.FullName())
+		data := map[string]any{
+			"locale":     locale,
+			"Repository": run.Repo.FullName(),
+			"Workflow":   workflow,
+			"Title":      run.Title,
+			"Ref":        run.PrettyRef(),
+			"Subject":    subject,
+			"Language":   locale.Language(),
+			"Link":       run.HTMLURL(),
+		}
+
+		var mailBody bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplWorkflowFailedMail), data); err != nil {
+			log.Error("ExecuteTemplate [%s]: %v", string(tplWorkflowFailedMail)+"/body", err)
+			return err
+		}
+
+		msgs := make([]*Message, 0, len(tos))
+		for _, to := range tos {
+			msg := NewMessage(to, subject, mailBody.String())
+			msg.Info = fmt.Sprintf("Run: %d, failed", run.ID)
+			msgs = append(msgs, msg)
+		}
+		SendAsync(msgs...)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 
+	activities_model "code.gitea.io/gitea/models/activities"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
@@ -40,12 +41,23 @@ func MailNewRelease(ctx context.Context, rel *repo_model.Release) {
 		return
 	}
 
-	langMap := make(map[string][]string)
+	watchers := make([]*user_model.User, 0, len(recipients))
 	for _, user := range recipients {
 		if user.ID != rel.PublisherID {
-			langMap[user.Language] = append(langMap[user.Language], user.Email)
+			watchers = append(watchers, user)
 		}
 	}
+	mailed, digested, err := splitByNotificationDelivery(ctx, watchers, rel.RepoID, activities_model.NotificationEventReleasePublished)
+	if err != nil {
+		log.Error("splitByNotificationDelivery(%d): %v", rel.RepoID, err)
+		return
+	}
+	addNotificationDigestItems(ctx, digested, rel.RepoID, activities_model.NotificationEventReleasePublished, rel.Title, rel.HTMLURL())
+
+	langMap := make(map[string][]string)
+	for _, user := range mailed {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
 
 	for lang, tos := range langMap {
 		mailNewRelease(ctx, lang, tos, rel)
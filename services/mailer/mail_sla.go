@@ -0,0 +1,71 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplSLABreachedMail base.TplName = "issue/sla_breached"
+)
+
+// MailSLABreached sends the escalation of an issue which missed the first response or the resolution deadline of
+// its SLA to the recipients
+func MailSLABreached(ctx context.Context, issue *issues_model.Issue, sla *issues_model.IssueSLA, firstResponse, resolve bool, recipients []*user_model.User) error {
+	if setting.MailService == nil || len(recipients) == 0 {
+		return nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if err := sla.LoadPolicy(ctx); err != nil {
+		return err
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.issue.sla_breached.subject", issue.Repo.FullName(), issue.Title, issue.Index)
+		data := map[string]any{
+			"locale":                locale,
+			"Issue":                 issue,
+			"SLA":                   sla,
+			"Policy":                sla.Policy,
+			"FirstResponseBreached": firstResponse,
+			"ResolveBreached":       resolve,
+			"Subject":               subject,
+			"Language":              locale.Language(),
+			"Link":                  issue.HTMLURL(),
+		}
+
+		var mailBody bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplSLABreachedMail), data); err != nil {
+			log.Error("ExecuteTemplate [%s]: %v", string(tplSLABreachedMail)+"/body", err)
+			return err
+		}
+
+		msgs := make([]*Message, 0, len(tos))
+		for _, to := range tos {
+			msg := NewMessage(to, subject, mailBody.String())
+			msg.Info = fmt.Sprintf("Issue: %d, SLA breached", issue.ID)
+			msgs = append(msgs, msg)
+		}
+		SendAsync(msgs...)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+const (
+	tplWebhookDisabledMail base.TplName = "notify/webhook_disabled"
+)
+
+// MailWebhookDisabled tells the recipients that a webhook of the repository or the owner with the given name, or
+// a system or default webhook if the name is empty, has been disabled after too many failed deliveries
+func MailWebhookDisabled(ctx context.Context, w *webhook_model.Webhook, name, link string, recipients []*user_model.User) error {
+	if setting.MailService == nil || len(recipients) == 0 {
+		return nil
+	}
+	if name == "" {
+		name = setting.AppName
+	}
+
+	// only the host of the webhook is shown as its URL may contain a token
+	host := w.URL
+	if u, err := url.Parse(w.URL); err == nil {
+		host = u.Host
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.webhook.disabled.subject", name)
+		data := map[string]any{
+			"locale":   locale,
+			"Name":     name,
+			"Host":     host,
+			"Failures": w.FailureCount,
+			"Subject":  subject,
+			"Language": locale.Language(),
+			"Link":     link,
+		}
+
+		var mailBody bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&mailBody, string(tplWebhookDisabledMail), data); err != nil {
+			log.Error("ExecuteTemplate [%s]: %v", string(tplWebhookDisabledMail)+"/body", err)
+			return err
+		}
+
+		msgs := make([]*Message, 0, len(tos))
+		for _, to := range tos {
+			msg := NewMessage(to, subject, mailBody.String())
+			msg.Info = fmt.Sprintf("Webhook: %d, disabled", w.ID)
+			msgs = append(msgs, msg)
+		}
+		SendAsync(msgs...)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -124,6 +125,15 @@ func (m *mailNotifier) IssueChangeAssignee(ctx context.Context, doer *user_model
 
 func (m *mailNotifier) PullRequestReviewRequest(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, reviewer *user_model.User, isRequest bool, comment *issues_model.Comment) {
 	if isRequest && doer.ID != reviewer.ID && reviewer.EmailNotifications() != user_model.EmailNotificationsDisabled {
+		mailed, _, err := splitByNotificationDelivery(ctx, []*user_model.User{reviewer}, issue.RepoID, activities_model.NotificationEventReviewRequested)
+		if err != nil {
+			log.Error("splitByNotificationDelivery [issue: %d, reviewer: %d]: %v", issue.ID, reviewer.ID, err)
+			return
+		}
+		if len(mailed) == 0 {
+			// the review request is in the notifications of the reviewer, so the digest has it
+			return
+		}
 		ct := fmt.Sprintf("Requested to review %s.", issue.HTMLURL())
 		if err := SendIssueAssignedMail(ctx, issue, doer, ct, comment, []*user_model.User{reviewer}); err != nil {
 			log.Error("Error in SendIssueAssignedMail for issue[%d] to reviewer[%d]: %v", issue.ID, reviewer.ID, err)
@@ -197,6 +207,15 @@ func (m *mailNotifier) NewRelease(ctx context.Context, rel *repo_model.Release)
 	MailNewRelease(ctx, rel)
 }
 
+func (m *mailNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	if run.Status != actions_model.StatusFailure {
+		return
+	}
+	if err := MailWorkflowRunFailed(ctx, run); err != nil {
+		log.Error("MailWorkflowRunFailed [run: %d]: %v", run.ID, err)
+	}
+}
+
 func (m *mailNotifier) RepoPendingTransfer(ctx context.Context, doer, newOwner *user_model.User, repo *repo_model.Repository) {
 	if err := SendRepoTransferNotifyMail(ctx, doer, newOwner, repo); err != nil {
 		log.Error("SendRepoTransferNotifyMail: %v", err)
@@ -0,0 +1,702 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+var (
+	_ base.Downloader        = &BitbucketDownloader{}
+	_ base.DownloaderFactory = &BitbucketDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&BitbucketDownloaderFactory{})
+}
+
+const (
+	bitbucketCloudHost   = "bitbucket.org"
+	bitbucketCloudAPIURL = "https://api.bitbucket.org/2.0"
+	// bitbucketTokenUser is the username to clone with a repository, project or workspace access token
+	bitbucketTokenUser = "x-token-auth"
+)
+
+// errBitbucketNotFound is returned by the API calls of the resources which don't exist, e.g. the issues of a
+// repository without issue tracker
+var errBitbucketNotFound = errors.New("not found")
+
+// BitbucketDownloaderFactory defines a bitbucket downloader factory
+type BitbucketDownloaderFactory struct{}
+
+// New returns a downloader related to this factory according MigrateOptions, repositories of bitbucket.org are
+// downloaded from Bitbucket Cloud, others from a Bitbucket Server or Data Center instance
+func (f *BitbucketDownloaderFactory) New(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, err
+	}
+	u.User = nil
+
+	username, password, token := bitbucketCredentials(opts)
+
+	if strings.EqualFold(strings.TrimPrefix(u.Host, "www."), bitbucketCloudHost) {
+		fields := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid path: %s", u.Path)
+		}
+		workspace, repoSlug := fields[0], strings.TrimSuffix(fields[1], ".git")
+
+		log.Trace("Create Bitbucket Cloud downloader. Workspace: %s RepoName: %s", workspace, repoSlug)
+		apiURL, _ := url.Parse(bitbucketCloudAPIURL)
+		return NewBitbucketDownloader(ctx, apiURL, workspace, repoSlug, username, password, token), nil
+	}
+
+	baseURL, projectKey, repoSlug, err := parseBitbucketServerURL(u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace("Create Bitbucket Server downloader. BaseURL: %s Project: %s RepoName: %s", baseURL, projectKey, repoSlug)
+	return NewBitbucketServerDownloader(ctx, baseURL, projectKey, repoSlug, username, password, token), nil
+}
+
+// GitServiceType returns the type of git service
+func (f *BitbucketDownloaderFactory) GitServiceType() structs.GitServiceType {
+	return structs.BitbucketService
+}
+
+// bitbucketCredentials returns the credentials of the migration: a password without username is an access token
+func bitbucketCredentials(opts base.MigrateOptions) (username, password, token string) {
+	if opts.AuthToken != "" {
+		return "", "", opts.AuthToken
+	}
+	if opts.AuthUsername == "" {
+		return "", "", opts.AuthPassword
+	}
+	return opts.AuthUsername, opts.AuthPassword, ""
+}
+
+// bitbucketCloneURL adds the credentials of the migration to a clone URL
+func bitbucketCloneURL(opts base.MigrateOptions, remoteAddr string) (string, error) {
+	username, password, token := bitbucketCredentials(opts)
+	if username == "" && token == "" {
+		return remoteAddr, nil
+	}
+	u, err := url.Parse(remoteAddr)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		u.User = url.UserPassword(bitbucketTokenUser, token)
+	} else {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String(), nil
+}
+
+// callBitbucketAPI sends an authenticated GET request to a Bitbucket API and decodes the JSON response
+func callBitbucketAPI(ctx context.Context, client *http.Client, u *url.URL, username, password, token string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	} else if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errBitbucketNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, u.Path, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+type bitbucketUser struct {
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	AccountID   string `json:"account_id"`
+}
+
+// Name returns the name of the user, the nickname of deleted users is empty
+func (u *bitbucketUser) Name() string {
+	if u == nil {
+		return "Ghost"
+	}
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+	return u.DisplayName
+}
+
+type bitbucketContent struct {
+	Raw string `json:"raw"`
+}
+
+type bitbucketPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+type bitbucketIssue struct {
+	ID        int64            `json:"id"`
+	Title     string           `json:"title"`
+	Content   bitbucketContent `json:"content"`
+	State     string           `json:"state"`
+	Kind      string           `json:"kind"`
+	Priority  string           `json:"priority"`
+	Reporter  *bitbucketUser   `json:"reporter"`
+	Assignee  *bitbucketUser   `json:"assignee"`
+	Milestone *struct {
+		Name string `json:"name"`
+	} `json:"milestone"`
+	Component *struct {
+		Name string `json:"name"`
+	} `json:"component"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+type bitbucketComment struct {
+	ID        int64            `json:"id"`
+	Content   bitbucketContent `json:"content"`
+	User      *bitbucketUser   `json:"user"`
+	CreatedOn time.Time        `json:"created_on"`
+	UpdatedOn time.Time        `json:"updated_on"`
+	Deleted   bool             `json:"deleted"`
+	Pending   bool             `json:"pending"`
+	Inline    *struct {
+		Path string `json:"path"`
+		From *int   `json:"from"`
+		To   *int   `json:"to"`
+	} `json:"inline"`
+	Parent *struct {
+		ID int64 `json:"id"`
+	} `json:"parent"`
+}
+
+type bitbucketPullRequestBranch struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit *struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int64                      `json:"id"`
+	Title       string                     `json:"title"`
+	Description string                     `json:"description"`
+	Summary     bitbucketContent           `json:"summary"`
+	State       string                     `json:"state"`
+	Author      *bitbucketUser             `json:"author"`
+	Source      bitbucketPullRequestBranch `json:"source"`
+	Destination bitbucketPullRequestBranch `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	CreatedOn    time.Time `json:"created_on"`
+	UpdatedOn    time.Time `json:"updated_on"`
+	Participants []struct {
+		User           *bitbucketUser `json:"user"`
+		Role           string         `json:"role"`
+		Approved       bool           `json:"approved"`
+		State          string         `json:"state"`
+		ParticipatedOn *time.Time     `json:"participated_on"`
+	} `json:"participants"`
+}
+
+// bitbucketIssueKinds and bitbucketIssuePriorities are the fixed kinds and priorities of Bitbucket Cloud issues,
+// they are migrated as labels
+var (
+	bitbucketIssueKinds      = []string{"bug", "enhancement", "proposal", "task"}
+	bitbucketIssuePriorities = []string{"trivial", "minor", "major", "critical", "blocker"}
+)
+
+// BitbucketDownloader implements a Downloader interface to get repository information from Bitbucket Cloud
+type BitbucketDownloader struct {
+	base.NullDownloader
+	ctx           context.Context
+	client        *http.Client
+	apiURL        *url.URL
+	workspace     string
+	repoSlug      string
+	username      string
+	password      string
+	token         string
+	maxIssueIndex int64
+}
+
+// NewBitbucketDownloader creates a Bitbucket Cloud downloader
+func NewBitbucketDownloader(ctx context.Context, apiURL *url.URL, workspace, repoSlug, username, password, token string) *BitbucketDownloader {
+	return &BitbucketDownloader{
+		ctx:       ctx,
+		client:    NewMigrationHTTPClient(),
+		apiURL:    apiURL,
+		workspace: workspace,
+		repoSlug:  repoSlug,
+		username:  username,
+		password:  password,
+		token:     token,
+	}
+}
+
+// SetContext set context
+func (d *BitbucketDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// String implements Stringer
+func (d *BitbucketDownloader) String() string {
+	return fmt.Sprintf("migration from bitbucket cloud %s/%s", d.workspace, d.repoSlug)
+}
+
+func (d *BitbucketDownloader) LogString() string {
+	if d == nil {
+		return "<BitbucketDownloader nil>"
+	}
+	return fmt.Sprintf("<BitbucketDownloader %s/%s>", d.workspace, d.repoSlug)
+}
+
+// FormatCloneURL add authentication into remote URLs
+func (d *BitbucketDownloader) FormatCloneURL(opts base.MigrateOptions, remoteAddr string) (string, error) {
+	return bitbucketCloneURL(opts, remoteAddr)
+}
+
+func (d *BitbucketDownloader) callAPI(endpoint string, parameter map[string]string, result any) error {
+	u := d.apiURL.JoinPath("repositories", d.workspace, d.repoSlug, endpoint)
+	if parameter != nil {
+		query := u.Query()
+		for k, v := range parameter {
+			query.Set(k, v)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return callBitbucketAPI(d.ctx, d.client, u, d.username, d.password, d.token, result)
+}
+
+// getAllBitbucketPages calls a paginated endpoint of the repository until its last page
+func getAllBitbucketPages[T any](d *BitbucketDownloader, endpoint string) ([]T, error) {
+	var values []T
+	for page := 1; ; page++ {
+		var result bitbucketPage[T]
+		if err := d.callAPI(endpoint, map[string]string{"page": strconv.Itoa(page), "pagelen": "100"}, &result); err != nil {
+			return nil, err
+		}
+		values = append(values, result.Values...)
+		if result.Next == "" {
+			return values, nil
+		}
+	}
+}
+
+// GetRepoInfo returns repository information
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-get
+func (d *BitbucketDownloader) GetRepoInfo() (*base.Repository, error) {
+	var rawRepository struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		IsPrivate   bool   `json:"is_private"`
+		MainBranch  *struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+			Clone []struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			} `json:"clone"`
+		} `json:"links"`
+	}
+
+	if err := d.callAPI("", nil, &rawRepository); err != nil {
+		return nil, err
+	}
+
+	repo := &base.Repository{
+		Name:        rawRepository.Name,
+		Owner:       d.workspace,
+		IsPrivate:   rawRepository.IsPrivate,
+		Description: rawRepository.Description,
+		OriginalURL: rawRepository.Links.HTML.Href,
+	}
+	if rawRepository.MainBranch != nil {
+		repo.DefaultBranch = rawRepository.MainBranch.Name
+	}
+	for _, clone := range rawRepository.Links.Clone {
+		if clone.Name != "https" {
+			continue
+		}
+		cloneURL, err := url.Parse(clone.Href)
+		if err != nil {
+			return nil, err
+		}
+		// the clone URL contains the name of the authenticated user
+		cloneURL.User = nil
+		repo.CloneURL = cloneURL.String()
+	}
+	if repo.CloneURL == "" {
+		repo.CloneURL = fmt.Sprintf("https://%s/%s/%s.git", bitbucketCloudHost, d.workspace, d.repoSlug)
+	}
+	return repo, nil
+}
+
+// GetMilestones returns milestones
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-issue-tracker/#api-repositories-workspace-repo-slug-milestones-get
+func (d *BitbucketDownloader) GetMilestones() ([]*base.Milestone, error) {
+	rawMilestones, err := getAllBitbucketPages[struct {
+		Name string `json:"name"`
+	}](d, "milestones")
+	if errors.Is(err, errBitbucketNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	milestones := make([]*base.Milestone, 0, len(rawMilestones))
+	for _, milestone := range rawMilestones {
+		milestones = append(milestones, &base.Milestone{
+			Title: milestone.Name,
+			State: "open",
+		})
+	}
+	return milestones, nil
+}
+
+// GetLabels returns the kinds and priorities of the issues and the components of the repository as labels
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-issue-tracker/#api-repositories-workspace-repo-slug-components-get
+func (d *BitbucketDownloader) GetLabels() ([]*base.Label, error) {
+	labels := make([]*base.Label, 0, len(bitbucketIssueKinds)+len(bitbucketIssuePriorities))
+	for _, kind := range bitbucketIssueKinds {
+		labels = append(labels, &base.Label{Name: bitbucketLabelName("kind", kind), Color: "1d76db"})
+	}
+	for _, priority := range bitbucketIssuePriorities {
+		labels = append(labels, &base.Label{Name: bitbucketLabelName("priority", priority), Color: "fbca04"})
+	}
+
+	components, err := getAllBitbucketPages[struct {
+		Name string `json:"name"`
+	}](d, "components")
+	if errors.Is(err, errBitbucketNotFound) {
+		return labels, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		labels = append(labels, &base.Label{Name: bitbucketLabelName("component", component.Name), Color: "c5def5"})
+	}
+	return labels, nil
+}
+
+func bitbucketLabelName(scope, name string) string {
+	return scope + "/" + name
+}
+
+// GetIssues returns issues according start and limit
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-issue-tracker/#api-repositories-workspace-repo-slug-issues-get
+func (d *BitbucketDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	var result bitbucketPage[bitbucketIssue]
+	err := d.callAPI("issues", map[string]string{
+		"page":    strconv.Itoa(page),
+		"pagelen": strconv.Itoa(perPage),
+		"sort":    "id",
+	}, &result)
+	if errors.Is(err, errBitbucketNotFound) {
+		// the repository has no issue tracker
+		return nil, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*base.Issue, 0, len(result.Values))
+	for _, issue := range result.Values {
+		labels := make([]*base.Label, 0, 3)
+		if issue.Kind != "" {
+			labels = append(labels, &base.Label{Name: bitbucketLabelName("kind", issue.Kind)})
+		}
+		if issue.Priority != "" {
+			labels = append(labels, &base.Label{Name: bitbucketLabelName("priority", issue.Priority)})
+		}
+		if issue.Component != nil {
+			labels = append(labels, &base.Label{Name: bitbucketLabelName("component", issue.Component.Name)})
+		}
+
+		state := "open"
+		var closed *time.Time
+		switch issue.State {
+		case "new", "open", "on hold":
+		default:
+			state = "closed"
+			closedTime := issue.UpdatedOn
+			closed = &closedTime
+		}
+
+		var milestone string
+		if issue.Milestone != nil {
+			milestone = issue.Milestone.Name
+		}
+		var assignees []string
+		if issue.Assignee != nil {
+			assignees = []string{issue.Assignee.Name()}
+		}
+
+		issues = append(issues, &base.Issue{
+			Number:       issue.ID,
+			Title:        issue.Title,
+			Content:      issue.Content.Raw,
+			PosterName:   issue.Reporter.Name(),
+			Milestone:    milestone,
+			State:        state,
+			Created:      issue.CreatedOn,
+			Updated:      issue.UpdatedOn,
+			Closed:       closed,
+			Labels:       labels,
+			Assignees:    assignees,
+			ForeignIndex: issue.ID,
+		})
+
+		if d.maxIssueIndex < issue.ID {
+			d.maxIssueIndex = issue.ID
+		}
+	}
+
+	return issues, result.Next == "", nil
+}
+
+// GetComments returns the comments of an issue or the general comments of a pull request, the inline comments of
+// pull requests are migrated as reviews
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-issue-tracker/#api-repositories-workspace-repo-slug-issues-issue-id-comments-get
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-get
+func (d *BitbucketDownloader) GetComments(commentable base.Commentable) ([]*base.Comment, bool, error) {
+	endpoint := fmt.Sprintf("issues/%d/comments", commentable.GetForeignIndex())
+	if _, ok := commentable.(*base.PullRequest); ok {
+		endpoint = fmt.Sprintf("pullrequests/%d/comments", commentable.GetForeignIndex())
+	}
+
+	rawComments, err := getAllBitbucketPages[bitbucketComment](d, endpoint)
+	if err != nil {
+		return nil, false, err
+	}
+
+	comments := make([]*base.Comment, 0, len(rawComments))
+	for _, comment := range rawComments {
+		// comments without content record the changes of the issue
+		if comment.Deleted || comment.Pending || comment.Inline != nil || comment.Content.Raw == "" {
+			continue
+		}
+		comments = append(comments, &base.Comment{
+			IssueIndex: commentable.GetLocalIndex(),
+			Index:      comment.ID,
+			PosterName: comment.User.Name(),
+			Content:    comment.Content.Raw,
+			Created:    comment.CreatedOn,
+			Updated:    comment.UpdatedOn,
+		})
+	}
+	return comments, true, nil
+}
+
+// GetPullRequests returns pull requests according page and perPage
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-get
+func (d *BitbucketDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	if perPage > 50 {
+		perPage = 50
+	}
+
+	var result bitbucketPage[bitbucketPullRequest]
+	u := d.apiURL.JoinPath("repositories", d.workspace, d.repoSlug, "pullrequests")
+	query := url.Values{
+		"page":    {strconv.Itoa(page)},
+		"pagelen": {strconv.Itoa(perPage)},
+		"sort":    {"id"},
+		"state":   {"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+	}
+	u.RawQuery = query.Encode()
+	if err := callBitbucketAPI(d.ctx, d.client, u, d.username, d.password, d.token, &result); err != nil {
+		return nil, false, err
+	}
+
+	pullRequests := make([]*base.PullRequest, 0, len(result.Values))
+	for _, pr := range result.Values {
+		state := "open"
+		var closed, mergedTime *time.Time
+		merged := pr.State == "MERGED"
+		if pr.State != "OPEN" {
+			state = "closed"
+			closedTime := pr.UpdatedOn
+			closed = &closedTime
+			if merged {
+				mergedTime = &closedTime
+			}
+		}
+		var mergeCommitSHA string
+		if pr.MergeCommit != nil {
+			mergeCommitSHA = pr.MergeCommit.Hash
+		}
+		content := pr.Summary.Raw
+		if content == "" {
+			content = pr.Description
+		}
+
+		pullRequests = append(pullRequests, &base.PullRequest{
+			Number:         d.maxIssueIndex + pr.ID,
+			Title:          pr.Title,
+			PosterName:     pr.Author.Name(),
+			Content:        content,
+			State:          state,
+			Created:        pr.CreatedOn,
+			Updated:        pr.UpdatedOn,
+			Closed:         closed,
+			Merged:         merged,
+			MergedTime:     mergedTime,
+			MergeCommitSHA: mergeCommitSHA,
+			Head:           d.pullRequestBranch(pr.Source),
+			Base:           d.pullRequestBranch(pr.Destination),
+			ForeignIndex:   pr.ID,
+		})
+
+		// SECURITY: Ensure that the PR is safe
+		_ = CheckAndEnsureSafePR(pullRequests[len(pullRequests)-1], "https://"+bitbucketCloudHost, d)
+	}
+
+	return pullRequests, result.Next == "", nil
+}
+
+func (d *BitbucketDownloader) pullRequestBranch(branch bitbucketPullRequestBranch) base.PullRequestBranch {
+	prBranch := base.PullRequestBranch{
+		Ref:       branch.Branch.Name,
+		OwnerName: d.workspace,
+		RepoName:  d.repoSlug,
+	}
+	if branch.Commit != nil {
+		prBranch.SHA = branch.Commit.Hash
+	}
+	// the branch of a fork
+	if branch.Repository != nil && !strings.EqualFold(branch.Repository.FullName, d.workspace+"/"+d.repoSlug) {
+		prBranch.OwnerName, prBranch.RepoName, _ = strings.Cut(branch.Repository.FullName, "/")
+		prBranch.CloneURL = fmt.Sprintf("https://%s/%s.git", bitbucketCloudHost, branch.Repository.FullName)
+	}
+	return prBranch
+}
+
+// GetReviews returns the approvals and change requests of the participants of a pull request and a review for the
+// inline comments of each user
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-get
+func (d *BitbucketDownloader) GetReviews(reviewable base.Reviewable) ([]*base.Review, error) {
+	var pr bitbucketPullRequest
+	if err := d.callAPI(fmt.Sprintf("pullrequests/%d", reviewable.GetForeignIndex()), nil, &pr); err != nil {
+		return nil, err
+	}
+
+	var reviews []*base.Review
+	for _, participant := range pr.Participants {
+		var state string
+		switch {
+		case participant.State == "changes_requested":
+			state = base.ReviewStateChangesRequested
+		case participant.Approved || participant.State == "approved":
+			state = base.ReviewStateApproved
+		default:
+			continue
+		}
+		review := &base.Review{
+			IssueIndex:   reviewable.GetLocalIndex(),
+			ReviewerName: participant.User.Name(),
+			Official:     participant.Role == "REVIEWER",
+			State:        state,
+		}
+		if participant.ParticipatedOn != nil {
+			review.CreatedAt = *participant.ParticipatedOn
+		}
+		reviews = append(reviews, review)
+	}
+
+	rawComments, err := getAllBitbucketPages[bitbucketComment](d, fmt.Sprintf("pullrequests/%d/comments", reviewable.GetForeignIndex()))
+	if err != nil {
+		return nil, err
+	}
+	commentReviews := make(map[string]*base.Review)
+	for _, comment := range rawComments {
+		if comment.Deleted || comment.Pending || comment.Inline == nil || comment.Content.Raw == "" {
+			continue
+		}
+		reviewer := comment.User.Name()
+		review, ok := commentReviews[reviewer]
+		if !ok {
+			review = &base.Review{
+				IssueIndex:   reviewable.GetLocalIndex(),
+				ReviewerName: reviewer,
+				State:        base.ReviewStateCommented,
+				CreatedAt:    comment.CreatedOn,
+			}
+			commentReviews[reviewer] = review
+		}
+
+		// the lines of the new file are positive, those of the old file negative
+		var line int
+		if comment.Inline.To != nil {
+			line = *comment.Inline.To
+		} else if comment.Inline.From != nil {
+			line = -*comment.Inline.From
+		}
+		reviewComment := &base.ReviewComment{
+			ID:        comment.ID,
+			Content:   comment.Content.Raw,
+			TreePath:  comment.Inline.Path,
+			Line:      line,
+			CreatedAt: comment.CreatedOn,
+			UpdatedAt: comment.UpdatedOn,
+		}
+		if comment.Parent != nil {
+			reviewComment.InReplyTo = comment.Parent.ID
+		}
+		review.Comments = append(review.Comments, reviewComment)
+	}
+
+	reviewers := make([]string, 0, len(commentReviews))
+	for reviewer := range commentReviews {
+		reviewers = append(reviewers, reviewer)
+	}
+	sort.Strings(reviewers)
+	for _, reviewer := range reviewers {
+		reviews = append(reviews, commentReviews[reviewer])
+	}
+	return reviews, nil
+}
@@ -0,0 +1,438 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	base "code.gitea.io/gitea/modules/migration"
+)
+
+var _ base.Downloader = &BitbucketServerDownloader{}
+
+// parseBitbucketServerURL returns the base URL of the instance, the project key and the slug of the repository of a
+// clone URL (/scm/PROJECT/repo.git) or of a web URL (/projects/PROJECT/repos/repo/...). The project key of personal
+// repositories (/scm/~user/repo.git or /users/user/repos/repo) is the username prefixed with "~".
+func parseBitbucketServerURL(u *url.URL) (baseURL *url.URL, projectKey, repoSlug string, err error) {
+	fields := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(fields); i++ {
+		switch {
+		case fields[i] == "scm" && i+2 < len(fields):
+			projectKey, repoSlug = fields[i+1], strings.TrimSuffix(fields[i+2], ".git")
+		case fields[i] == "projects" && i+3 < len(fields) && fields[i+2] == "repos":
+			projectKey, repoSlug = fields[i+1], fields[i+3]
+		case fields[i] == "users" && i+3 < len(fields) && fields[i+2] == "repos":
+			projectKey, repoSlug = "~"+fields[i+1], fields[i+3]
+		default:
+			continue
+		}
+		baseURL = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.Join(fields[:i], "/")}
+		if baseURL.Path != "" {
+			baseURL.Path = "/" + baseURL.Path
+		}
+		return baseURL, projectKey, repoSlug, nil
+	}
+	return nil, "", "", fmt.Errorf("invalid path: %s", u.Path)
+}
+
+type bitbucketServerUser struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+type bitbucketServerRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+type bitbucketServerPullRequest struct {
+	ID          int64              `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	State       string             `json:"state"`
+	CreatedDate int64              `json:"createdDate"`
+	UpdatedDate int64              `json:"updatedDate"`
+	ClosedDate  int64              `json:"closedDate"`
+	FromRef     bitbucketServerRef `json:"fromRef"`
+	ToRef       bitbucketServerRef `json:"toRef"`
+	Author      struct {
+		User bitbucketServerUser `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		User bitbucketServerUser `json:"user"`
+	} `json:"reviewers"`
+	Properties struct {
+		MergeCommit *struct {
+			ID string `json:"id"`
+		} `json:"mergeCommit"`
+	} `json:"properties"`
+}
+
+type bitbucketServerComment struct {
+	ID          int64                     `json:"id"`
+	Text        string                    `json:"text"`
+	Author      bitbucketServerUser       `json:"author"`
+	CreatedDate int64                     `json:"createdDate"`
+	UpdatedDate int64                     `json:"updatedDate"`
+	Comments    []*bitbucketServerComment `json:"comments"`
+}
+
+type bitbucketServerActivity struct {
+	ID            int64                   `json:"id"`
+	CreatedDate   int64                   `json:"createdDate"`
+	User          bitbucketServerUser     `json:"user"`
+	Action        string                  `json:"action"`
+	CommentAction string                  `json:"commentAction"`
+	Comment       *bitbucketServerComment `json:"comment"`
+	CommentAnchor *struct {
+		Path     string `json:"path"`
+		Line     int    `json:"line"`
+		LineType string `json:"lineType"`
+		FileType string `json:"fileType"`
+		ToHash   string `json:"toHash"`
+	} `json:"commentAnchor"`
+}
+
+type bitbucketServerPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// bitbucketServerTime converts the timestamps in milliseconds of the API
+func bitbucketServerTime(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+// BitbucketServerDownloader implements a Downloader interface to get repository information from Bitbucket Server
+// and Data Center, which have no issue tracker
+type BitbucketServerDownloader struct {
+	base.NullDownloader
+	ctx        context.Context
+	client     *http.Client
+	baseURL    *url.URL
+	projectKey string
+	repoSlug   string
+	username   string
+	password   string
+	token      string
+}
+
+// NewBitbucketServerDownloader creates a Bitbucket Server downloader
+func NewBitbucketServerDownloader(ctx context.Context, baseURL *url.URL, projectKey, repoSlug, username, password, token string) *BitbucketServerDownloader {
+	return &BitbucketServerDownloader{
+		ctx:        ctx,
+		client:     NewMigrationHTTPClient(),
+		baseURL:    baseURL,
+		projectKey: projectKey,
+		repoSlug:   repoSlug,
+		username:   username,
+		password:   password,
+		token:      token,
+	}
+}
+
+// SetContext set context
+func (d *BitbucketServerDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// String implements Stringer
+func (d *BitbucketServerDownloader) String() string {
+	return fmt.Sprintf("migration from bitbucket server %s %s/%s", d.baseURL, d.projectKey, d.repoSlug)
+}
+
+func (d *BitbucketServerDownloader) LogString() string {
+	if d == nil {
+		return "<BitbucketServerDownloader nil>"
+	}
+	return fmt.Sprintf("<BitbucketServerDownloader %s %s/%s>", d.baseURL, d.projectKey, d.repoSlug)
+}
+
+// FormatCloneURL add authentication into remote URLs
+func (d *BitbucketServerDownloader) FormatCloneURL(opts base.MigrateOptions, remoteAddr string) (string, error) {
+	return bitbucketCloneURL(opts, remoteAddr)
+}
+
+func (d *BitbucketServerDownloader) callAPI(endpoint string, parameter map[string]string, result any) error {
+	u := d.baseURL.JoinPath("rest/api/1.0/projects", d.projectKey, "repos", d.repoSlug, endpoint)
+	if parameter != nil {
+		query := u.Query()
+		for k, v := range parameter {
+			query.Set(k, v)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return callBitbucketAPI(d.ctx, d.client, u, d.username, d.password, d.token, result)
+}
+
+// GetRepoInfo returns repository information
+// https://developer.atlassian.com/server/bitbucket/rest/v810/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-get
+func (d *BitbucketServerDownloader) GetRepoInfo() (*base.Repository, error) {
+	var rawRepository struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+		Links       struct {
+			Clone []struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			} `json:"clone"`
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	}
+	if err := d.callAPI("", nil, &rawRepository); err != nil {
+		return nil, err
+	}
+
+	repo := &base.Repository{
+		Name:        rawRepository.Name,
+		Owner:       d.projectKey,
+		IsPrivate:   !rawRepository.Public,
+		Description: rawRepository.Description,
+	}
+	for _, clone := range rawRepository.Links.Clone {
+		if clone.Name != "http" {
+			continue
+		}
+		cloneURL, err := url.Parse(clone.Href)
+		if err != nil {
+			return nil, err
+		}
+		// the clone URL contains the name of the authenticated user
+		cloneURL.User = nil
+		repo.CloneURL = cloneURL.String()
+	}
+	if repo.CloneURL == "" {
+		repo.CloneURL = d.baseURL.JoinPath("scm", strings.ToLower(d.projectKey), d.repoSlug+".git").String()
+	}
+	if len(rawRepository.Links.Self) > 0 {
+		repo.OriginalURL = strings.TrimSuffix(rawRepository.Links.Self[0].Href, "/browse")
+	}
+
+	var defaultBranch struct {
+		DisplayID string `json:"displayId"`
+	}
+	if err := d.callAPI("branches/default", nil, &defaultBranch); err == nil {
+		repo.DefaultBranch = defaultBranch.DisplayID
+	} else if !errors.Is(err, errBitbucketNotFound) {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetPullRequests returns pull requests according page and perPage
+// https://developer.atlassian.com/server/bitbucket/rest/v810/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-get
+func (d *BitbucketServerDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, bool, error) {
+	var result bitbucketServerPage[bitbucketServerPullRequest]
+	err := d.callAPI("pull-requests", map[string]string{
+		"state": "ALL",
+		"order": "OLDEST",
+		"start": strconv.Itoa((page - 1) * perPage),
+		"limit": strconv.Itoa(perPage),
+	}, &result)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pullRequests := make([]*base.PullRequest, 0, len(result.Values))
+	for _, pr := range result.Values {
+		state := "open"
+		var closed, mergedTime *time.Time
+		merged := pr.State == "MERGED"
+		if pr.State != "OPEN" {
+			state = "closed"
+			closedTime := bitbucketServerTime(pr.UpdatedDate)
+			if pr.ClosedDate != 0 {
+				closedTime = bitbucketServerTime(pr.ClosedDate)
+			}
+			closed = &closedTime
+			if merged {
+				mergedTime = &closedTime
+			}
+		}
+		var mergeCommitSHA string
+		if pr.Properties.MergeCommit != nil {
+			mergeCommitSHA = pr.Properties.MergeCommit.ID
+		}
+		var assignees []string
+		for _, reviewer := range pr.Reviewers {
+			assignees = append(assignees, reviewer.User.Name)
+		}
+
+		pullRequests = append(pullRequests, &base.PullRequest{
+			Number:         pr.ID,
+			Title:          pr.Title,
+			PosterID:       pr.Author.User.ID,
+			PosterName:     pr.Author.User.Name,
+			PosterEmail:    pr.Author.User.EmailAddress,
+			Content:        pr.Description,
+			State:          state,
+			Created:        bitbucketServerTime(pr.CreatedDate),
+			Updated:        bitbucketServerTime(pr.UpdatedDate),
+			Closed:         closed,
+			Merged:         merged,
+			MergedTime:     mergedTime,
+			MergeCommitSHA: mergeCommitSHA,
+			Assignees:      assignees,
+			Head:           d.pullRequestBranch(pr.FromRef),
+			Base:           d.pullRequestBranch(pr.ToRef),
+			ForeignIndex:   pr.ID,
+		})
+
+		// SECURITY: Ensure that the PR is safe
+		_ = CheckAndEnsureSafePR(pullRequests[len(pullRequests)-1], d.baseURL.String(), d)
+	}
+
+	return pullRequests, result.IsLastPage, nil
+}
+
+func (d *BitbucketServerDownloader) pullRequestBranch(ref bitbucketServerRef) base.PullRequestBranch {
+	branch := base.PullRequestBranch{
+		Ref:       ref.DisplayID,
+		SHA:       ref.LatestCommit,
+		OwnerName: d.projectKey,
+		RepoName:  d.repoSlug,
+	}
+	// the branch of a fork
+	if !strings.EqualFold(ref.Repository.Project.Key, d.projectKey) || !strings.EqualFold(ref.Repository.Slug, d.repoSlug) {
+		branch.OwnerName, branch.RepoName = ref.Repository.Project.Key, ref.Repository.Slug
+		branch.CloneURL = d.baseURL.JoinPath("scm", strings.ToLower(ref.Repository.Project.Key), ref.Repository.Slug+".git").String()
+	}
+	return branch
+}
+
+// getActivities returns all the activities of a pull request
+// https://developer.atlassian.com/server/bitbucket/rest/v810/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-activities-get
+func (d *BitbucketServerDownloader) getActivities(index int64) ([]*bitbucketServerActivity, error) {
+	var activities []*bitbucketServerActivity
+	start := 0
+	for {
+		var result bitbucketServerPage[*bitbucketServerActivity]
+		err := d.callAPI(fmt.Sprintf("pull-requests/%d/activities", index), map[string]string{
+			"start": strconv.Itoa(start),
+			"limit": "100",
+		}, &result)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, result.Values...)
+		if result.IsLastPage || len(result.Values) == 0 {
+			return activities, nil
+		}
+		start = result.NextPageStart
+	}
+}
+
+// flattenBitbucketServerComments returns a comment and its replies
+func flattenBitbucketServerComments(comment *bitbucketServerComment) []*bitbucketServerComment {
+	comments := []*bitbucketServerComment{comment}
+	for _, reply := range comment.Comments {
+		comments = append(comments, flattenBitbucketServerComments(reply)...)
+	}
+	return comments
+}
+
+// GetComments returns the general comments of a pull request and their replies, the comments on the diff are
+// migrated as reviews
+func (d *BitbucketServerDownloader) GetComments(commentable base.Commentable) ([]*base.Comment, bool, error) {
+	activities, err := d.getActivities(commentable.GetForeignIndex())
+	if err != nil {
+		return nil, false, err
+	}
+
+	var comments []*base.Comment
+	// the activities are listed from the newest
+	for i := len(activities) - 1; i >= 0; i-- {
+		activity := activities[i]
+		if activity.Action != "COMMENTED" || activity.CommentAction != "ADDED" || activity.Comment == nil || activity.CommentAnchor != nil {
+			continue
+		}
+		for _, comment := range flattenBitbucketServerComments(activity.Comment) {
+			comments = append(comments, &base.Comment{
+				IssueIndex:  commentable.GetLocalIndex(),
+				Index:       comment.ID,
+				PosterID:    comment.Author.ID,
+				PosterName:  comment.Author.Name,
+				PosterEmail: comment.Author.EmailAddress,
+				Content:     comment.Text,
+				Created:     bitbucketServerTime(comment.CreatedDate),
+				Updated:     bitbucketServerTime(comment.UpdatedDate),
+			})
+		}
+	}
+	return comments, true, nil
+}
+
+// GetReviews returns the approvals, the "needs work" reviews and the comments on the diff of a pull request
+func (d *BitbucketServerDownloader) GetReviews(reviewable base.Reviewable) ([]*base.Review, error) {
+	activities, err := d.getActivities(reviewable.GetForeignIndex())
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []*base.Review
+	for i := len(activities) - 1; i >= 0; i-- {
+		activity := activities[i]
+		review := &base.Review{
+			ID:           activity.ID,
+			IssueIndex:   reviewable.GetLocalIndex(),
+			ReviewerID:   activity.User.ID,
+			ReviewerName: activity.User.Name,
+			CreatedAt:    bitbucketServerTime(activity.CreatedDate),
+		}
+		switch {
+		case activity.Action == "APPROVED":
+			review.State = base.ReviewStateApproved
+			review.Official = true
+		case activity.Action == "REVIEWED":
+			review.State = base.ReviewStateChangesRequested
+			review.Official = true
+		case activity.Action == "COMMENTED" && activity.CommentAction == "ADDED" && activity.Comment != nil && activity.CommentAnchor != nil:
+			anchor := activity.CommentAnchor
+			// the lines of the new file are positive, those of the old file negative
+			line := anchor.Line
+			if anchor.LineType == "REMOVED" || anchor.FileType == "FROM" {
+				line = -line
+			}
+			review.State = base.ReviewStateCommented
+			for _, comment := range flattenBitbucketServerComments(activity.Comment) {
+				review.Comments = append(review.Comments, &base.ReviewComment{
+					ID:        comment.ID,
+					InReplyTo: activity.Comment.ID,
+					Content:   comment.Text,
+					TreePath:  anchor.Path,
+					Line:      line,
+					CommitID:  anchor.ToHash,
+					PosterID:  comment.Author.ID,
+					CreatedAt: bitbucketServerTime(comment.CreatedDate),
+					UpdatedAt: bitbucketServerTime(comment.UpdatedDate),
+				})
+			}
+			review.Comments[0].InReplyTo = 0
+		default:
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
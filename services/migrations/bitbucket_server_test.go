@@ -0,0 +1,211 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	base "code.gitea.io/gitea/modules/migration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBitbucketServerURL(t *testing.T) {
+	kases := []struct {
+		url        string
+		baseURL    string
+		projectKey string
+		repoSlug   string
+	}{
+		{
+			url:        "https://bitbucket.example.com/scm/prj/test-repo.git",
+			baseURL:    "https://bitbucket.example.com",
+			projectKey: "prj",
+			repoSlug:   "test-repo",
+		},
+		{
+			url:        "https://bitbucket.example.com/bitbucket/scm/~alice/test-repo.git",
+			baseURL:    "https://bitbucket.example.com/bitbucket",
+			projectKey: "~alice",
+			repoSlug:   "test-repo",
+		},
+		{
+			url:        "https://bitbucket.example.com/projects/PRJ/repos/test-repo/browse",
+			baseURL:    "https://bitbucket.example.com",
+			projectKey: "PRJ",
+			repoSlug:   "test-repo",
+		},
+		{
+			url:        "http://bitbucket.example.com:7990/users/alice/repos/test-repo",
+			baseURL:    "http://bitbucket.example.com:7990",
+			projectKey: "~alice",
+			repoSlug:   "test-repo",
+		},
+	}
+	for _, kase := range kases {
+		t.Run(kase.url, func(t *testing.T) {
+			u, _ := url.Parse(kase.url)
+			baseURL, projectKey, repoSlug, err := parseBitbucketServerURL(u)
+			if assert.NoError(t, err) {
+				assert.Equal(t, kase.baseURL, baseURL.String())
+				assert.Equal(t, kase.projectKey, projectKey)
+				assert.Equal(t, kase.repoSlug, repoSlug)
+			}
+		})
+	}
+
+	u, _ := url.Parse("https://bitbucket.example.com/prj/test-repo.git")
+	_, _, _, err := parseBitbucketServerURL(u)
+	assert.Error(t, err)
+}
+
+func TestBitbucketServerDownloadRepo(t *testing.T) {
+	server := newBitbucketMockServer(t, "server")
+	baseURL, _ := url.Parse(server.URL + "/bitbucket")
+	downloader := NewBitbucketServerDownloader(context.Background(), baseURL, "PRJ", "test-repo", "", "", "")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assertRepositoryEqual(t, &base.Repository{
+		Name:          "test-repo",
+		Owner:         "PRJ",
+		IsPrivate:     true,
+		Description:   "Test repository for testing the migration from Bitbucket Server",
+		CloneURL:      "https://bitbucket.example.com/bitbucket/scm/prj/test-repo.git",
+		OriginalURL:   "https://bitbucket.example.com/bitbucket/projects/PRJ/repos/test-repo",
+		DefaultBranch: "main",
+	}, repo)
+
+	// Bitbucket Server has no issue tracker
+	_, _, err = downloader.GetIssues(1, 2)
+	assert.ErrorIs(t, err, base.ErrNotSupported{Entity: "Issues"})
+
+	prs, isEnd, err := downloader.GetPullRequests(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	assertPullRequestsEqual(t, []*base.PullRequest{
+		{
+			Number:      1,
+			Title:       "Fix the crash on empty config",
+			PosterID:    101,
+			PosterName:  "alice",
+			PosterEmail: "alice@example.com",
+			Content:     "Fixes the crash on start.",
+			State:       "closed",
+			Created:     time.UnixMilli(1683190800000),
+			Updated:     time.UnixMilli(1683288000000),
+			Closed:      timePtr(time.UnixMilli(1683288000000)),
+			Assignees:   []string{"bob"},
+			Head: base.PullRequestBranch{
+				Ref:       "fix/empty-config",
+				SHA:       "9c4bd2a1f0e3d5c7b9a1f3e5d7c9b1a3f5e7d9c1",
+				OwnerName: "PRJ",
+				RepoName:  "test-repo",
+			},
+			Base: base.PullRequestBranch{
+				Ref:       "main",
+				SHA:       "1d3a77b2c4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9",
+				OwnerName: "PRJ",
+				RepoName:  "test-repo",
+			},
+			Merged:         true,
+			MergedTime:     timePtr(time.UnixMilli(1683288000000)),
+			MergeCommitSHA: "e8f0a2b4c6d8e0f2a4b6c8d0e2f4a6b8c0d2e4f6",
+		},
+		{
+			Number:      2,
+			Title:       "Update the README",
+			PosterID:    103,
+			PosterName:  "carol",
+			PosterEmail: "carol@example.com",
+			State:       "open",
+			Created:     time.UnixMilli(1683536400000),
+			Updated:     time.UnixMilli(1683536400000),
+			Head: base.PullRequestBranch{
+				CloneURL:  server.URL + "/bitbucket/scm/~carol/test-repo.git",
+				Ref:       "docs",
+				SHA:       "5b7c9d1e3f50a2b4c6d8e0f2a4b6c8d0e2f4a6b8",
+				OwnerName: "~CAROL",
+				RepoName:  "test-repo",
+			},
+			Base: base.PullRequestBranch{
+				Ref:       "main",
+				SHA:       "e8f0a2b4c6d8e0f2a4b6c8d0e2f4a6b8c0d2e4f6",
+				OwnerName: "PRJ",
+				RepoName:  "test-repo",
+			},
+		},
+	}, prs)
+
+	comments, _, err := downloader.GetComments(prs[0])
+	assert.NoError(t, err)
+	assertCommentsEqual(t, []*base.Comment{
+		{
+			IssueIndex:  1,
+			PosterID:    102,
+			PosterName:  "bob",
+			PosterEmail: "bob@example.com",
+			Content:     "Thanks for the quick fix!",
+			Created:     time.UnixMilli(1683201600000),
+			Updated:     time.UnixMilli(1683201900000),
+		},
+		{
+			IssueIndex:  1,
+			PosterID:    101,
+			PosterName:  "alice",
+			PosterEmail: "alice@example.com",
+			Content:     "You're welcome.",
+			Created:     time.UnixMilli(1683205200000),
+			Updated:     time.UnixMilli(1683205200000),
+		},
+	}, comments)
+
+	reviews, err := downloader.GetReviews(prs[0])
+	assert.NoError(t, err)
+	assertReviewsEqual(t, []*base.Review{
+		{
+			ID:           303,
+			IssueIndex:   1,
+			ReviewerID:   102,
+			ReviewerName: "bob",
+			State:        base.ReviewStateCommented,
+			CreatedAt:    time.UnixMilli(1683202200000),
+			Comments: []*base.ReviewComment{
+				{
+					ID:        21,
+					Content:   "Check for nil here too.",
+					TreePath:  "config.go",
+					Line:      3,
+					CommitID:  "9c4bd2a1f0e3d5c7b9a1f3e5d7c9b1a3f5e7d9c1",
+					PosterID:  102,
+					CreatedAt: time.UnixMilli(1683202200000),
+					UpdatedAt: time.UnixMilli(1683202200000),
+				},
+				{
+					ID:        22,
+					InReplyTo: 21,
+					Content:   "Done.",
+					TreePath:  "config.go",
+					Line:      3,
+					CommitID:  "9c4bd2a1f0e3d5c7b9a1f3e5d7c9b1a3f5e7d9c1",
+					PosterID:  101,
+					CreatedAt: time.UnixMilli(1683277200000),
+					UpdatedAt: time.UnixMilli(1683277200000),
+				},
+			},
+		},
+		{
+			ID:           304,
+			IssueIndex:   1,
+			ReviewerID:   102,
+			ReviewerName: "bob",
+			Official:     true,
+			State:        base.ReviewStateApproved,
+			CreatedAt:    time.UnixMilli(1683284400000),
+		},
+	}, reviews)
+}
@@ -51,7 +51,8 @@ type GiteaLocalUploader struct {
 	gitRepo        *git.Repository
 	prHeadCache    map[string]string
 	sameApp        bool
-	userMap        map[int64]int64 // external user id mapping to user id
+	userMap        map[int64]int64  // external user id mapping to user id
+	userNameMap    map[string]int64 // external user name mapping to user id, preferred to userMap
 	prCache        map[int64]*issues_model.PullRequest
 	gitServiceType structs.GitServiceType
 }
@@ -362,94 +363,113 @@ func (g *GiteaLocalUploader) SyncTags() error {
 func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 	iss := make([]*issues_model.Issue, 0, len(issues))
 	for _, issue := range issues {
-		var labels []*issues_model.Label
-		for _, label := range issue.Labels {
-			lb, ok := g.labels[label.Name]
-			if ok {
-				labels = append(labels, lb)
-			}
+		is, err := g.newIssue(issue)
+		if err != nil {
+			return err
 		}
+		iss = append(iss, is)
+	}
 
-		milestoneID := g.milestones[issue.Milestone]
-
-		if issue.Created.IsZero() {
-			if issue.Closed != nil {
-				issue.Created = *issue.Closed
-			} else {
-				issue.Created = time.Now()
-			}
-		}
-		if issue.Updated.IsZero() {
-			if issue.Closed != nil {
-				issue.Updated = *issue.Closed
-			} else {
-				issue.Updated = time.Now()
-			}
+	if len(iss) > 0 {
+		if err := issues_model.InsertIssues(iss...); err != nil {
+			return err
 		}
 
-		// SECURITY: issue.Ref needs to be a valid reference
-		if !git.IsValidRefPattern(issue.Ref) {
-			log.Warn("Invalid issue.Ref[%s] in issue #%d in %s/%s", issue.Ref, issue.Number, g.repoOwner, g.repoName)
-			issue.Ref = ""
+		for _, is := range iss {
+			g.issues[is.Index] = is
 		}
+	}
 
-		is := issues_model.Issue{
-			RepoID:      g.repo.ID,
-			Repo:        g.repo,
-			Index:       issue.Number,
-			Title:       issue.Title,
-			Content:     issue.Content,
-			Ref:         issue.Ref,
-			IsClosed:    issue.State == "closed",
-			IsLocked:    issue.IsLocked,
-			MilestoneID: milestoneID,
-			Labels:      labels,
-			CreatedUnix: timeutil.TimeStamp(issue.Created.Unix()),
-			UpdatedUnix: timeutil.TimeStamp(issue.Updated.Unix()),
-		}
-
-		if err := g.remapUser(issue, &is); err != nil {
-			return err
+	return nil
+}
+
+func (g *GiteaLocalUploader) newIssue(issue *base.Issue) (*issues_model.Issue, error) {
+	var labels []*issues_model.Label
+	for _, label := range issue.Labels {
+		lb, ok := g.labels[label.Name]
+		if ok {
+			labels = append(labels, lb)
 		}
+	}
+
+	milestoneID := g.milestones[issue.Milestone]
 
+	if issue.Created.IsZero() {
 		if issue.Closed != nil {
-			is.ClosedUnix = timeutil.TimeStamp(issue.Closed.Unix())
+			issue.Created = *issue.Closed
+		} else {
+			issue.Created = time.Now()
 		}
-		// add reactions
-		for _, reaction := range issue.Reactions {
-			res := issues_model.Reaction{
-				Type:        reaction.Content,
-				CreatedUnix: timeutil.TimeStampNow(),
-			}
-			if err := g.remapUser(reaction, &res); err != nil {
-				return err
-			}
-			is.Reactions = append(is.Reactions, &res)
+	}
+	if issue.Updated.IsZero() {
+		if issue.Closed != nil {
+			issue.Updated = *issue.Closed
+		} else {
+			issue.Updated = time.Now()
 		}
-		iss = append(iss, &is)
 	}
 
-	if len(iss) > 0 {
-		if err := issues_model.InsertIssues(iss...); err != nil {
-			return err
-		}
+	// SECURITY: issue.Ref needs to be a valid reference
+	if !git.IsValidRefPattern(issue.Ref) {
+		log.Warn("Invalid issue.Ref[%s] in issue #%d in %s/%s", issue.Ref, issue.Number, g.repoOwner, g.repoName)
+		issue.Ref = ""
+	}
 
-		for _, is := range iss {
-			g.issues[is.Index] = is
-		}
+	is := issues_model.Issue{
+		RepoID:      g.repo.ID,
+		Repo:        g.repo,
+		Index:       issue.Number,
+		Title:       issue.Title,
+		Content:     issue.Content,
+		Ref:         issue.Ref,
+		IsClosed:    issue.State == "closed",
+		IsLocked:    issue.IsLocked,
+		MilestoneID: milestoneID,
+		Labels:      labels,
+		CreatedUnix: timeutil.TimeStamp(issue.Created.Unix()),
+		UpdatedUnix: timeutil.TimeStamp(issue.Updated.Unix()),
 	}
 
-	return nil
+	if err := g.remapUser(issue, &is); err != nil {
+		return nil, err
+	}
+
+	if issue.Closed != nil {
+		is.ClosedUnix = timeutil.TimeStamp(issue.Closed.Unix())
+	}
+	// add reactions
+	for _, reaction := range issue.Reactions {
+		res := issues_model.Reaction{
+			Type:        reaction.Content,
+			CreatedUnix: timeutil.TimeStampNow(),
+		}
+		if err := g.remapUser(reaction, &res); err != nil {
+			return nil, err
+		}
+		is.Reactions = append(is.Reactions, &res)
+	}
+	return &is, nil
 }
 
 // CreateComments creates comments of issues
 func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
+	cms, err := g.newComments(comments...)
+	if err != nil {
+		return err
+	}
+	if len(cms) == 0 {
+		return nil
+	}
+	return issues_model.InsertIssueComments(g.ctx, cms)
+}
+
+func (g *GiteaLocalUploader) newComments(comments ...*base.Comment) ([]*issues_model.Comment, error) {
 	cms := make([]*issues_model.Comment, 0, len(comments))
 	for _, comment := range comments {
 		var issue *issues_model.Issue
 		issue, ok := g.issues[comment.IssueIndex]
 		if !ok {
-			return fmt.Errorf("comment references non existent IssueIndex %d", comment.IssueIndex)
+			return nil, fmt.Errorf("comment references non existent IssueIndex %d", comment.IssueIndex)
 		}
 
 		if comment.Created.IsZero() {
@@ -489,7 +509,7 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 		}
 
 		if err := g.remapUser(comment, &cm); err != nil {
-			return err
+			return nil, err
 		}
 
 		// add reactions
@@ -499,18 +519,14 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 				CreatedUnix: timeutil.TimeStampNow(),
 			}
 			if err := g.remapUser(reaction, &res); err != nil {
-				return err
+				return nil, err
 			}
 			cm.Reactions = append(cm.Reactions, &res)
 		}
 
 		cms = append(cms, &cm)
 	}
-
-	if len(cms) == 0 {
-		return nil
-	}
-	return issues_model.InsertIssueComments(g.ctx, cms)
+	return cms, nil
 }
 
 // CreatePullRequests creates pull requests
@@ -810,12 +826,20 @@ func convertReviewState(state string) issues_model.ReviewType {
 
 // CreateReviews create pull request reviews of currently migrated issues
 func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
+	cms, err := g.newReviews(reviews...)
+	if err != nil {
+		return err
+	}
+	return issues_model.InsertReviews(g.ctx, cms)
+}
+
+func (g *GiteaLocalUploader) newReviews(reviews ...*base.Review) ([]*issues_model.Review, error) {
 	cms := make([]*issues_model.Review, 0, len(reviews))
 	for _, review := range reviews {
 		var issue *issues_model.Issue
 		issue, ok := g.issues[review.IssueIndex]
 		if !ok {
-			return fmt.Errorf("review references non existent IssueIndex %d", review.IssueIndex)
+			return nil, fmt.Errorf("review references non existent IssueIndex %d", review.IssueIndex)
 		}
 		if review.CreatedAt.IsZero() {
 			review.CreatedAt = time.Unix(int64(issue.CreatedUnix), 0)
@@ -831,7 +855,7 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 		}
 
 		if err := g.remapUser(review, &cm); err != nil {
-			return err
+			return nil, err
 		}
 
 		cms = append(cms, &cm)
@@ -842,7 +866,7 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 			var err error
 			pr, err = issues_model.GetPullRequestByIssueIDWithNoAttributes(issue.ID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			g.prCache[issue.ID] = pr
 		}
@@ -910,14 +934,14 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 			}
 
 			if err := g.remapUser(review, &c); err != nil {
-				return err
+				return nil, err
 			}
 
 			cm.Comments = append(cm.Comments, &c)
 		}
 	}
 
-	return issues_model.InsertReviews(g.ctx, cms)
+	return cms, nil
 }
 
 // Rollback when migrating failed, this will rollback all the changes.
@@ -950,6 +974,10 @@ func (g *GiteaLocalUploader) Finish() error {
 }
 
 func (g *GiteaLocalUploader) remapUser(source user_model.ExternalUserMigrated, target user_model.ExternalUserRemappable) error {
+	if userid, ok := g.userNameMap[source.GetExternalName()]; ok {
+		return target.RemapExternalUser("", 0, userid)
+	}
+
 	var userid int64
 	var err error
 	if g.sameApp {
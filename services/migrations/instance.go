@@ -0,0 +1,880 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	base "code.gitea.io/gitea/modules/migration"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	packages_service "code.gitea.io/gitea/services/packages"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// InstanceMigrationOptions are the options of a migration of organizations and users from another instance
+type InstanceMigrationOptions struct {
+	// BaseURL is the URL of the other instance
+	BaseURL string
+	// AuthToken is a token of the other instance, the token of an administrator is needed to map all the users
+	AuthToken string
+	// Owners are the names of the organizations and users to migrate, the organizations keep their names and
+	// the repositories of a user are migrated to the local user with the same email
+	Owners []string
+	// Units are the repository units to migrate like the units of dump-repo, empty means all units
+	Units []string
+	// StateFile records what was migrated, a migration run again with the same file skips it
+	StateFile string
+	// DryRun only reports what would be migrated
+	DryRun bool
+}
+
+// InstanceMigrationStatus is the status of an item of a migration of organizations and users
+type InstanceMigrationStatus string
+
+const (
+	// InstanceMigrationMigrated is the status of the items migrated by this run
+	InstanceMigrationMigrated InstanceMigrationStatus = "migrated"
+	// InstanceMigrationPlanned is the status of the items a dry run would migrate
+	InstanceMigrationPlanned InstanceMigrationStatus = "planned"
+	// InstanceMigrationDone is the status of the items migrated by a previous run
+	InstanceMigrationDone InstanceMigrationStatus = "done"
+	// InstanceMigrationSkipped is the status of the items which can't be migrated
+	InstanceMigrationSkipped InstanceMigrationStatus = "skipped"
+	// InstanceMigrationFailed is the status of the items whose migration failed, a run again retries them
+	InstanceMigrationFailed InstanceMigrationStatus = "failed"
+)
+
+// InstanceMigrationReportItem is an item of the report of a migration of organizations and users
+type InstanceMigrationReportItem struct {
+	Kind    string
+	Name    string
+	Status  InstanceMigrationStatus
+	Message string
+}
+
+// InstanceMigrationReport reports what a migration of organizations and users migrated, or would migrate
+type InstanceMigrationReport struct {
+	DryRun bool
+	Items  []*InstanceMigrationReportItem
+}
+
+func (r *InstanceMigrationReport) add(kind, name string, status InstanceMigrationStatus, format string, args ...any) {
+	r.Items = append(r.Items, &InstanceMigrationReportItem{
+		Kind:    kind,
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// addStatus adds an item which was migrated, or planned in a dry run
+func (r *InstanceMigrationReport) addStatus(kind, name, format string, args ...any) {
+	status := InstanceMigrationMigrated
+	if r.DryRun {
+		status = InstanceMigrationPlanned
+	}
+	r.add(kind, name, status, format, args...)
+}
+
+// NumFailed returns the number of items whose migration failed
+func (r *InstanceMigrationReport) NumFailed() int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Status == InstanceMigrationFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// String returns the report as a table
+func (r *InstanceMigrationReport) String() string {
+	var sb strings.Builder
+	if r.DryRun {
+		sb.WriteString("Dry run, nothing was migrated\n")
+	}
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tNAME\tSTATUS\tMESSAGE")
+	for _, item := range r.Items {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Kind, item.Name, item.Status, item.Message)
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// instanceMigrationState records the keys of the items migrated, it's saved to a file after each of them
+type instanceMigrationState struct {
+	path string
+	Done map[string]bool `json:"done"`
+}
+
+func loadInstanceMigrationState(path string) (*instanceMigrationState, error) {
+	state := &instanceMigrationState{path: path, Done: map[string]bool{}}
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %q: %w", path, err)
+	}
+	if state.Done == nil {
+		state.Done = map[string]bool{}
+	}
+	return state, nil
+}
+
+func (s *instanceMigrationState) isDone(key string) bool {
+	return s.Done[key]
+}
+
+func (s *instanceMigrationState) markDone(key string) error {
+	s.Done[key] = true
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// write a temporary file first, an interrupted migration must not corrupt the state
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return util.Rename(tmp, s.path)
+}
+
+var (
+	errInstanceNotFound  = errors.New("not found on the other instance")
+	errInstanceForbidden = errors.New("forbidden by the other instance")
+)
+
+// instanceMigrator migrates organizations and users from another instance through its API
+type instanceMigrator struct {
+	ctx    context.Context
+	doer   *user_model.User
+	opts   InstanceMigrationOptions
+	client *http.Client
+	apiURL string
+	state  *instanceMigrationState
+	report *InstanceMigrationReport
+
+	// users maps the names of the users of the other instance to the local users with the same email,
+	// the unmapped users are mapped to nil
+	users map[string]*user_model.User
+	// userNameMap maps the names of the mapped users to the IDs of the local users for the uploader
+	userNameMap map[string]int64
+	// teamRepos maps the names of the repositories of the organization being migrated to the local teams
+	// which have access to them
+	teamRepos map[string][]*organization.Team
+}
+
+// MigrateInstance migrates organizations and users with their repositories, teams, members, webhooks and generic
+// packages from another instance. The repositories are migrated with the Gitea downloader, the issues and the
+// comments of the users of the other instance are attributed to the local users with the same email.
+// Each item is recorded in the state file once migrated, a failed migration can be run again to resume it.
+func MigrateInstance(ctx context.Context, opts InstanceMigrationOptions) (*InstanceMigrationReport, error) {
+	doer, err := user_model.GetAdminUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(opts.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid URL %q", opts.BaseURL)
+	}
+	if err := IsMigrateURLAllowed(opts.BaseURL, doer); err != nil {
+		return nil, err
+	}
+	if len(opts.Owners) == 0 {
+		return nil, errors.New("no organization or user to migrate")
+	}
+	if err := updateOptionsUnits(&base.MigrateOptions{}, opts.Units); err != nil {
+		return nil, err
+	}
+	if opts.StateFile != "" && !opts.DryRun {
+		if isDir, _ := util.IsDir(filepath.Dir(opts.StateFile)); !isDir {
+			return nil, fmt.Errorf("the directory of the state file %q doesn't exist", opts.StateFile)
+		}
+	}
+
+	state, err := loadInstanceMigrationState(opts.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &instanceMigrator{
+		ctx:         ctx,
+		doer:        doer,
+		opts:        opts,
+		client:      NewMigrationHTTPClient(),
+		apiURL:      strings.TrimSuffix(opts.BaseURL, "/") + "/api/v1",
+		state:       state,
+		report:      &InstanceMigrationReport{DryRun: opts.DryRun},
+		users:       map[string]*user_model.User{},
+		userNameMap: map[string]int64{},
+	}
+
+	if err := m.loadUsers(); err != nil {
+		return nil, err
+	}
+	for _, owner := range opts.Owners {
+		if err := m.migrateOwner(owner); err != nil {
+			return m.report, err
+		}
+	}
+	return m.report, nil
+}
+
+// get sends an authenticated GET request to the API of the other instance and decodes the JSON response
+func (m *instanceMigrator) get(path string, result any) error {
+	req, err := http.NewRequestWithContext(m.ctx, "GET", m.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if m.opts.AuthToken != "" {
+		req.Header.Set("Authorization", "token "+m.opts.AuthToken)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errInstanceNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errInstanceForbidden
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// listAll gets all the pages of a list of the API of the other instance
+func listAll[T any](m *instanceMigrator, path string) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var all []T
+	for page := 1; ; page++ {
+		var items []T
+		if err := m.get(fmt.Sprintf("%s%spage=%d&limit=50", path, sep, page), &items); err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return all, nil
+		}
+		all = append(all, items...)
+	}
+}
+
+// loadUsers maps all the users of the other instance by email, only the administrators can list them with
+// their emails, otherwise the users are mapped one by one when they are met
+func (m *instanceMigrator) loadUsers() error {
+	users, err := listAll[*api.User](m, "/admin/users")
+	if err != nil {
+		if errors.Is(err, errInstanceForbidden) {
+			m.report.add("user", "*", InstanceMigrationSkipped, "the token isn't an administrator token, only the users with a public email are mapped")
+			return nil
+		}
+		return err
+	}
+	for _, u := range users {
+		if err := m.mapUser(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapUser maps a user of the other instance to the local user with the same email
+func (m *instanceMigrator) mapUser(u *api.User) error {
+	if _, ok := m.users[u.UserName]; ok {
+		return nil
+	}
+	m.users[u.UserName] = nil
+	if u.Email == "" {
+		return nil
+	}
+	local, err := user_model.GetUserByEmail(m.ctx, u.Email)
+	if user_model.IsErrUserNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	m.users[u.UserName] = local
+	m.userNameMap[u.UserName] = local.ID
+	return nil
+}
+
+// getUser returns the local user a user of the other instance is mapped to, or nil if it isn't mapped
+func (m *instanceMigrator) getUser(name string) (*user_model.User, error) {
+	if local, ok := m.users[name]; ok {
+		return local, nil
+	}
+	u := &api.User{}
+	if err := m.get("/users/"+url.PathEscape(name), u); err != nil {
+		return nil, err
+	}
+	if err := m.mapUser(u); err != nil {
+		return nil, err
+	}
+	return m.users[name], nil
+}
+
+func (m *instanceMigrator) migrateOwner(name string) error {
+	org := &api.Organization{}
+	err := m.get("/orgs/"+url.PathEscape(name), org)
+	if err == nil {
+		return m.migrateOrganization(org)
+	} else if !errors.Is(err, errInstanceNotFound) {
+		return err
+	}
+
+	local, err := m.getUser(name)
+	if errors.Is(err, errInstanceNotFound) {
+		m.report.add("user", name, InstanceMigrationFailed, "no organization or user has this name")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if local == nil {
+		m.report.add("user", name, InstanceMigrationFailed, "no local user has the email of this user")
+		return nil
+	}
+	m.report.addStatus("user", name, "mapped to %s", local.Name)
+	m.teamRepos = nil
+	return m.migrateOwnerItems("/users/"+url.PathEscape(name), name, local, local.Name)
+}
+
+func (m *instanceMigrator) migrateOrganization(org *api.Organization) error {
+	key := "org:" + org.Name
+	local, err := organization.GetOrgByName(m.ctx, org.Name)
+	if err != nil && !organization.IsErrOrgNotExist(err) {
+		return err
+	}
+
+	switch {
+	case local != nil && m.state.isDone(key):
+		m.report.add("organization", org.Name, InstanceMigrationDone, "")
+	case local != nil:
+		m.report.addStatus("organization", org.Name, "merged into the existing organization")
+	case m.opts.DryRun:
+		m.report.addStatus("organization", org.Name, "")
+	default:
+		visibility := api.VisibleTypePublic
+		if v, ok := api.VisibilityModes[org.Visibility]; ok {
+			visibility = v
+		}
+		local = &organization.Organization{
+			Name:                      org.Name,
+			FullName:                  org.FullName,
+			Email:                     org.Email,
+			Description:               org.Description,
+			Website:                   org.Website,
+			Location:                  org.Location,
+			IsActive:                  true,
+			Type:                      user_model.UserTypeOrganization,
+			Visibility:                visibility,
+			RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		}
+		if err := organization.CreateOrganization(local, m.doer); err != nil {
+			m.report.add("organization", org.Name, InstanceMigrationFailed, "%v", err)
+			return nil
+		}
+		m.report.addStatus("organization", org.Name, "")
+	}
+	if local != nil && !m.opts.DryRun {
+		if err := m.state.markDone(key); err != nil {
+			return err
+		}
+	}
+
+	m.teamRepos = map[string][]*organization.Team{}
+	teams, err := listAll[*api.Team](m, "/orgs/"+url.PathEscape(org.Name)+"/teams")
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		if err := m.migrateTeam(org.Name, local, team); err != nil {
+			return err
+		}
+	}
+
+	var owner *user_model.User
+	if local != nil {
+		owner = local.AsUser()
+	}
+	if err := m.migrateOwnerItems("/orgs/"+url.PathEscape(org.Name), org.Name, owner, org.Name); err != nil {
+		return err
+	}
+
+	return m.migrateWebhooks("hooks:"+org.Name, org.Name, "/orgs/"+url.PathEscape(org.Name)+"/hooks", owner, 0)
+}
+
+func (m *instanceMigrator) migrateTeam(orgName string, org *organization.Organization, team *api.Team) error {
+	name := orgName + "/" + team.Name
+	key := "team:" + name
+
+	var local *organization.Team
+	if org != nil {
+		var err error
+		local, err = organization.GetTeam(m.ctx, org.ID, team.Name)
+		if err != nil && !organization.IsErrTeamNotExist(err) {
+			return err
+		}
+	}
+
+	members, err := listAll[*api.User](m, fmt.Sprintf("/teams/%d/members", team.ID))
+	if err != nil {
+		return err
+	}
+	var repos []*api.Repository
+	if !team.IncludesAllRepositories {
+		if repos, err = listAll[*api.Repository](m, fmt.Sprintf("/teams/%d/repos", team.ID)); err != nil {
+			return err
+		}
+	}
+
+	if local != nil && m.state.isDone(key) {
+		m.report.add("team", name, InstanceMigrationDone, "")
+		m.addTeamRepos(local, repos)
+		return nil
+	}
+
+	if local == nil && !m.opts.DryRun {
+		local = newInstanceTeam(org.ID, team)
+		if err := models.NewTeam(m.ctx, local); err != nil {
+			m.report.add("team", name, InstanceMigrationFailed, "%v", err)
+			return nil
+		}
+	}
+
+	mapped := 0
+	for _, member := range members {
+		user, err := m.getUser(member.UserName)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			m.report.add("member", name+"/"+member.UserName, InstanceMigrationSkipped, "no local user has the email of this user")
+			continue
+		}
+		mapped++
+		if m.opts.DryRun {
+			continue
+		}
+		if isMember, err := organization.IsTeamMember(m.ctx, org.ID, local.ID, user.ID); err != nil {
+			return err
+		} else if !isMember {
+			if err := models.AddTeamMember(m.ctx, local, user.ID); err != nil {
+				return err
+			}
+		}
+	}
+	m.report.addStatus("team", name, "%d of %d members mapped", mapped, len(members))
+
+	if local != nil && !m.opts.DryRun {
+		m.addTeamRepos(local, repos)
+		return m.state.markDone(key)
+	}
+	return nil
+}
+
+// newInstanceTeam returns a local team with the permissions of a team of the other instance
+func newInstanceTeam(orgID int64, team *api.Team) *organization.Team {
+	p := perm.ParseAccessMode(team.Permission)
+	unitsMap := make(map[unit_model.Type]perm.AccessMode, len(team.UnitsMap))
+	for unitKey, mode := range team.UnitsMap {
+		unitsMap[unit_model.TypeFromKey(unitKey)] = perm.ParseAccessMode(mode)
+	}
+	if p < perm.AccessModeAdmin && len(unitsMap) > 0 {
+		p = unit_model.MinUnitAccessMode(unitsMap)
+	}
+
+	t := &organization.Team{
+		OrgID:                   orgID,
+		Name:                    team.Name,
+		Description:             team.Description,
+		IncludesAllRepositories: team.IncludesAllRepositories,
+		CanCreateOrgRepo:        team.CanCreateOrgRepo,
+		AccessMode:              p,
+	}
+	if p >= perm.AccessModeAdmin {
+		for _, tp := range unit_model.AllRepoUnitTypes {
+			mode := perm.AccessModeAdmin
+			if tp == unit_model.TypeExternalTracker || tp == unit_model.TypeExternalWiki {
+				mode = perm.AccessModeRead
+			}
+			t.Units = append(t.Units, &organization.TeamUnit{OrgID: orgID, Type: tp, AccessMode: mode})
+		}
+	} else if len(unitsMap) > 0 {
+		for tp, mode := range unitsMap {
+			t.Units = append(t.Units, &organization.TeamUnit{OrgID: orgID, Type: tp, AccessMode: mode})
+		}
+	} else {
+		unitTypes, _ := unit_model.FindUnitTypes(team.Units...)
+		for _, tp := range unitTypes {
+			t.Units = append(t.Units, &organization.TeamUnit{OrgID: orgID, Type: tp, AccessMode: p})
+		}
+	}
+	return t
+}
+
+func (m *instanceMigrator) addTeamRepos(team *organization.Team, repos []*api.Repository) {
+	for _, repo := range repos {
+		m.teamRepos[repo.Name] = append(m.teamRepos[repo.Name], team)
+	}
+}
+
+// migrateOwnerItems migrates the repositories and the packages of an organization or a user, owner is nil
+// in a dry run if the organization doesn't exist yet
+func (m *instanceMigrator) migrateOwnerItems(sourcePath, sourceName string, owner *user_model.User, ownerName string) error {
+	repos, err := listAll[*api.Repository](m, sourcePath+"/repos")
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if err := m.migrateRepository(owner, ownerName, repo); err != nil {
+			return err
+		}
+	}
+
+	packages, err := listAll[*api.Package](m, "/packages/"+url.PathEscape(sourceName))
+	if err != nil {
+		return err
+	}
+	for _, pkg := range packages {
+		if err := m.migratePackage(sourceName, owner, pkg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *instanceMigrator) migrateRepository(owner *user_model.User, ownerName string, repo *api.Repository) error {
+	name := ownerName + "/" + repo.Name
+	key := "repo:" + name
+
+	local, err := repo_model.GetRepositoryByOwnerAndName(m.ctx, ownerName, repo.Name)
+	if err != nil && !repo_model.IsErrRepoNotExist(err) {
+		return err
+	}
+
+	switch {
+	case local != nil && m.state.isDone(key):
+		m.report.add("repository", name, InstanceMigrationDone, "")
+	case local != nil:
+		m.report.add("repository", name, InstanceMigrationSkipped, "a local repository has this name")
+		return nil
+	case m.opts.DryRun:
+		m.report.addStatus("repository", name, "")
+	default:
+		opts := base.MigrateOptions{
+			CloneAddr:      repo.CloneURL,
+			AuthToken:      m.opts.AuthToken,
+			RepoName:       repo.Name,
+			Description:    repo.Description,
+			Private:        repo.Private,
+			OriginalURL:    repo.HTMLURL,
+			GitServiceType: api.GiteaService,
+			LFS:            setting.LFS.StartServer,
+		}
+		if err := updateOptionsUnits(&opts, m.opts.Units); err != nil {
+			return err
+		}
+		opts.Wiki = opts.Wiki && repo.HasWiki
+		opts.Comments = opts.Comments && (opts.Issues || opts.PullRequests)
+
+		log.Info("Migrating repository %s from %s", name, m.opts.BaseURL)
+		local, err = migrateRepositoryWithUserMap(m.ctx, m.doer, ownerName, opts, nil, m.userNameMap)
+		if err != nil {
+			m.report.add("repository", name, InstanceMigrationFailed, "%v", util.SanitizeErrorCredentialURLs(err))
+			return nil
+		}
+		if err := m.state.markDone(key); err != nil {
+			return err
+		}
+		m.report.addStatus("repository", name, "")
+	}
+
+	for _, team := range m.teamRepos[repo.Name] {
+		if local == nil || organization.HasTeamRepo(m.ctx, team.OrgID, team.ID, local.ID) {
+			continue
+		}
+		if err := models.AddRepository(m.ctx, team, local); err != nil {
+			return err
+		}
+	}
+
+	var repoID int64
+	if local != nil {
+		repoID = local.ID
+	}
+	return m.migrateWebhooks("hooks:"+name, name, "/repos/"+url.PathEscape(repo.Owner.UserName)+"/"+url.PathEscape(repo.Name)+"/hooks", nil, repoID)
+}
+
+// migrateWebhooks migrates the webhooks of an organization or a repository, their secrets aren't returned by
+// the API of the other instance and must be set again
+func (m *instanceMigrator) migrateWebhooks(key, name, path string, owner *user_model.User, repoID int64) error {
+	hooks, err := listAll[*api.Hook](m, path)
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	if m.state.isDone(key) {
+		m.report.add("webhooks", name, InstanceMigrationDone, "")
+		return nil
+	}
+	if m.opts.DryRun || (owner == nil && repoID == 0) {
+		m.report.addStatus("webhooks", name, "%d webhooks, their secrets must be set again", len(hooks))
+		return nil
+	}
+
+	var ownerID int64
+	if owner != nil {
+		ownerID = owner.ID
+	}
+	ws := make([]*webhook_model.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		w, err := newInstanceWebhook(ownerID, repoID, hook)
+		if err != nil {
+			m.report.add("webhook", name+" "+hook.Config["url"], InstanceMigrationSkipped, "%v", err)
+			continue
+		}
+		ws = append(ws, w)
+	}
+	if err := webhook_model.CreateWebhooks(m.ctx, ws); err != nil {
+		m.report.add("webhooks", name, InstanceMigrationFailed, "%v", err)
+		return nil
+	}
+	m.report.addStatus("webhooks", name, "%d webhooks, their secrets must be set again", len(ws))
+	return m.state.markDone(key)
+}
+
+// newInstanceWebhook returns a local webhook with the configuration of a webhook of the other instance
+func newInstanceWebhook(ownerID, repoID int64, hook *api.Hook) (*webhook_model.Webhook, error) {
+	if !webhook_service.IsValidHookTaskType(hook.Type) {
+		return nil, fmt.Errorf("unsupported webhook type %q", hook.Type)
+	}
+
+	has := func(events ...webhook_module.HookEventType) bool {
+		for _, event := range events {
+			if util.SliceContainsString(hook.Events, string(event), true) {
+				return true
+			}
+		}
+		return false
+	}
+	w := &webhook_model.Webhook{
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		URL:         hook.Config["url"],
+		ContentType: webhook_model.ToHookContentType(hook.Config["content_type"]),
+		HTTPMethod:  "POST",
+		HookEvent: &webhook_module.HookEvent{
+			ChooseEvents: true,
+			BranchFilter: hook.BranchFilter,
+			HookEvents: webhook_module.HookEvents{
+				Create:                   has(webhook_module.HookEventCreate),
+				Delete:                   has(webhook_module.HookEventDelete),
+				Fork:                     has(webhook_module.HookEventFork),
+				Issues:                   has(webhook_module.HookEventIssues),
+				IssueAssign:              has(webhook_module.HookEventIssueAssign),
+				IssueLabel:               has(webhook_module.HookEventIssueLabel),
+				IssueMilestone:           has(webhook_module.HookEventIssueMilestone),
+				IssueComment:             has(webhook_module.HookEventIssueComment),
+				Push:                     has(webhook_module.HookEventPush),
+				PullRequest:              has(webhook_module.HookEventPullRequest),
+				PullRequestAssign:        has(webhook_module.HookEventPullRequestAssign),
+				PullRequestLabel:         has(webhook_module.HookEventPullRequestLabel),
+				PullRequestMilestone:     has(webhook_module.HookEventPullRequestMilestone),
+				PullRequestComment:       has(webhook_module.HookEventPullRequestComment),
+				PullRequestReview:        has(webhook_module.HookEventPullRequestReviewApproved, webhook_module.HookEventPullRequestReviewRejected, webhook_module.HookEventPullRequestReviewComment),
+				PullRequestReviewRequest: has(webhook_module.HookEventPullRequestReviewRequest),
+				PullRequestSync:          has(webhook_module.HookEventPullRequestSync),
+				Wiki:                     has(webhook_module.HookEventWiki),
+				Repository:               has(webhook_module.HookEventRepository),
+				Release:                  has(webhook_module.HookEventRelease),
+				Package:                  has(webhook_module.HookEventPackage),
+				WorkflowRun:              has(webhook_module.HookEventWorkflowRun),
+				WorkflowJob:              has(webhook_module.HookEventWorkflowJob),
+				BranchProtection:         has(webhook_module.HookEventBranchProtection),
+				Member:                   has(webhook_module.HookEventMember),
+				Membership:               has(webhook_module.HookEventMembership),
+				DeployKey:                has(webhook_module.HookEventDeployKey),
+				RepositoryVisibility:     has(webhook_module.HookEventRepositoryVisibility),
+			},
+		},
+		IsActive: hook.Active,
+		Type:     hook.Type,
+	}
+	if err := w.SetHeaderAuthorization(hook.AuthorizationHeader); err != nil {
+		return nil, err
+	}
+
+	switch w.Type {
+	case webhook_module.SLACK:
+		meta, err := json.Marshal(&webhook_service.SlackMeta{
+			Channel:  hook.Config["channel"],
+			Username: hook.Config["username"],
+			IconURL:  hook.Config["icon_url"],
+			Color:    hook.Config["color"],
+		})
+		if err != nil {
+			return nil, err
+		}
+		w.Meta = string(meta)
+	case webhook_module.CUSTOM:
+		meta := &webhook_service.CustomMeta{
+			ContentType: hook.Config["content_type_template"],
+			Headers:     hook.Config["headers_template"],
+			Body:        hook.Config["body_template"],
+		}
+		if err := webhook_service.ValidateCustomMeta(meta, w); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		w.Meta = string(data)
+	}
+
+	if err := w.UpdateEvent(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// migratePackage migrates a version of a generic package, the packages of the other types hold metadata which
+// the API doesn't return and must be published again
+func (m *instanceMigrator) migratePackage(sourceName string, owner *user_model.User, pkg *api.Package) error {
+	name := fmt.Sprintf("%s/%s/%s/%s", sourceName, pkg.Type, pkg.Name, pkg.Version)
+	key := "package:" + name
+
+	if packages_model.Type(pkg.Type) != packages_model.TypeGeneric {
+		m.report.add("package", name, InstanceMigrationSkipped, "only the generic packages can be migrated")
+		return nil
+	}
+	if m.state.isDone(key) {
+		m.report.add("package", name, InstanceMigrationDone, "")
+		return nil
+	}
+	if owner != nil {
+		_, err := packages_model.GetVersionByNameAndVersion(m.ctx, owner.ID, packages_model.TypeGeneric, pkg.Name, pkg.Version)
+		if err == nil {
+			m.report.add("package", name, InstanceMigrationSkipped, "a local package has this version")
+			return nil
+		} else if !errors.Is(err, packages_model.ErrPackageNotExist) {
+			return err
+		}
+	}
+
+	files, err := listAll[*api.PackageFile](m, fmt.Sprintf("/packages/%s/%s/%s/%s/files", url.PathEscape(sourceName), pkg.Type, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version)))
+	if err != nil {
+		return err
+	}
+	if m.opts.DryRun || owner == nil {
+		m.report.addStatus("package", name, "%d files", len(files))
+		return nil
+	}
+
+	for _, file := range files {
+		if err := m.migratePackageFile(sourceName, owner, pkg, file); err != nil {
+			m.report.add("package", name, InstanceMigrationFailed, "%s: %v", file.Name, err)
+			return nil
+		}
+	}
+	m.report.addStatus("package", name, "%d files", len(files))
+	return m.state.markDone(key)
+}
+
+func (m *instanceMigrator) migratePackageFile(sourceName string, owner *user_model.User, pkg *api.Package, file *api.PackageFile) error {
+	fileURL := fmt.Sprintf("%s/api/packages/%s/generic/%s/%s/%s", strings.TrimSuffix(m.opts.BaseURL, "/"),
+		url.PathEscape(sourceName), url.PathEscape(pkg.Name), url.PathEscape(pkg.Version), url.PathEscape(file.Name))
+	req, err := http.NewRequestWithContext(m.ctx, "GET", fileURL, nil)
+	if err != nil {
+		return err
+	}
+	if m.opts.AuthToken != "" {
+		req.Header.Set("Authorization", "token "+m.opts.AuthToken)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		m.ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       owner,
+				PackageType: packages_model.TypeGeneric,
+				Name:        pkg.Name,
+				Version:     pkg.Version,
+			},
+			Creator: m.doer,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: file.Name,
+			},
+			Creator: m.doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if errors.Is(err, packages_model.ErrDuplicatePackageFile) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newInstanceMockServer serves the lists of the API of another instance, their pages after the first one are empty
+func newInstanceMockServer(t *testing.T) *httptest.Server {
+	responses := map[string]any{
+		"/api/v1/admin/users": []*api.User{
+			{UserName: "alice", Email: "user2@example.com"},
+			{UserName: "bob", Email: "bob@example.com"},
+		},
+		"/api/v1/orgs/team-org": &api.Organization{Name: "team-org", FullName: "Team Org", Visibility: "limited"},
+		"/api/v1/orgs/team-org/teams": []*api.Team{
+			{ID: 1, Name: "Owners", Permission: "owner"},
+			{ID: 2, Name: "developers", Permission: "write", UnitsMap: map[string]string{"repo.code": "write", "repo.issues": "read"}},
+		},
+		"/api/v1/teams/1/members":     []*api.User{{UserName: "alice"}},
+		"/api/v1/teams/1/repos":       []*api.Repository{},
+		"/api/v1/teams/2/members":     []*api.User{{UserName: "alice"}, {UserName: "bob"}},
+		"/api/v1/teams/2/repos":       []*api.Repository{},
+		"/api/v1/orgs/team-org/repos": []*api.Repository{},
+		"/api/v1/orgs/team-org/hooks": []*api.Hook{
+			{Type: "gitea", Active: true, Events: []string{"push", "pull_request_review_approved"}, BranchFilter: "main", Config: map[string]string{"url": "https://example.com/hook", "content_type": "json"}},
+			{Type: "unknown", Config: map[string]string{"url": "https://example.com/unknown"}},
+		},
+		"/api/v1/packages/team-org": []*api.Package{
+			{Type: "generic", Name: "tool", Version: "1.0.0"},
+			{Type: "npm", Name: "lib", Version: "2.0.0"},
+		},
+		"/api/v1/packages/team-org/generic/tool/1.0.0/files": []*api.PackageFile{{Name: "tool.bin"}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/packages/team-org/generic/tool/1.0.0/tool.bin" {
+			_, _ = w.Write([]byte("tool content"))
+			return
+		}
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 1 {
+			resp = []any{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMigrateInstance(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	server := newInstanceMockServer(t)
+
+	opts := InstanceMigrationOptions{
+		BaseURL:   server.URL,
+		Owners:    []string{"team-org", "missing"},
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+		DryRun:    true,
+	}
+
+	report, err := MigrateInstance(db.DefaultContext, opts)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	for _, item := range report.Items {
+		assert.NotEqual(t, InstanceMigrationMigrated, item.Status, item.Name)
+	}
+	unittest.AssertNotExistsBean(t, &user_model.User{Name: "team-org"})
+
+	opts.DryRun = false
+	report, err = MigrateInstance(db.DefaultContext, opts)
+	require.NoError(t, err)
+	statuses := make(map[string]InstanceMigrationStatus, len(report.Items))
+	for _, item := range report.Items {
+		statuses[item.Kind+" "+item.Name] = item.Status
+	}
+	assert.Equal(t, map[string]InstanceMigrationStatus{
+		"organization team-org":                        InstanceMigrationMigrated,
+		"team team-org/Owners":                         InstanceMigrationMigrated,
+		"member team-org/developers/bob":               InstanceMigrationSkipped,
+		"team team-org/developers":                     InstanceMigrationMigrated,
+		"package team-org/generic/tool/1.0.0":          InstanceMigrationMigrated,
+		"package team-org/npm/lib/2.0.0":               InstanceMigrationSkipped,
+		"webhook team-org https://example.com/unknown": InstanceMigrationSkipped,
+		"webhooks team-org":                            InstanceMigrationMigrated,
+		"user missing":                                 InstanceMigrationFailed,
+	}, statuses)
+	assert.Equal(t, 1, report.NumFailed())
+
+	org := unittest.AssertExistsAndLoadBean(t, &organization.Organization{Name: "team-org"})
+	assert.Equal(t, api.VisibleTypeLimited, org.Visibility)
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{OrgID: org.ID, Name: "developers"})
+	isMember, err := organization.IsTeamMember(db.DefaultContext, org.ID, team.ID, 2)
+	assert.NoError(t, err)
+	assert.True(t, isMember)
+
+	hook := unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{OwnerID: org.ID})
+	assert.Equal(t, "https://example.com/hook", hook.URL)
+	assert.Equal(t, "main", hook.BranchFilter)
+	assert.True(t, hook.HookEvents.Push)
+	assert.True(t, hook.HookEvents.PullRequestReview)
+	assert.False(t, hook.HookEvents.Issues)
+
+	_, err = packages_model.GetVersionByNameAndVersion(db.DefaultContext, org.ID, packages_model.TypeGeneric, "tool", "1.0.0")
+	assert.NoError(t, err)
+
+	// a migration run again with the same state file doesn't migrate anything twice
+	opts.Owners = []string{"team-org"}
+	report, err = MigrateInstance(db.DefaultContext, opts)
+	require.NoError(t, err)
+	for _, item := range report.Items {
+		assert.NotEqual(t, InstanceMigrationMigrated, item.Status, item.Name)
+	}
+	unittest.AssertCount(t, &webhook_model.Webhook{OwnerID: org.ID}, 1)
+}
@@ -0,0 +1,321 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// issueCSVHeader are the columns of the CSV export of the issues, the labels and the assignees are separated
+// by new lines in their cells
+var issueCSVHeader = []string{"number", "title", "state", "poster", "created", "updated", "closed", "milestone", "labels", "assignees", "locked", "content"}
+
+// ExportIssues exports issues of a repository with their comments, the labels and the milestones of the
+// repository. The pull requests are skipped.
+func ExportIssues(ctx context.Context, repo *repo_model.Repository, issues issues_model.IssueList) (*base.IssueExport, error) {
+	export := &base.IssueExport{
+		OriginalURL: repo.HTMLURL(),
+	}
+
+	issues = slices.DeleteFunc(slices.Clone(issues), func(issue *issues_model.Issue) bool {
+		return issue.IsPull || issue.RepoID != repo.ID
+	})
+	if err := issues.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	if err := issues.LoadDiscussComments(ctx); err != nil {
+		return nil, err
+	}
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	labelNames := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		labelNames[label.Name] = true
+		export.Labels = append(export.Labels, exportLabel(label))
+	}
+
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		RepoID: repo.ID,
+		State:  api.StateAll,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, milestone := range milestones {
+		export.Milestones = append(export.Milestones, exportMilestone(milestone))
+	}
+
+	var comments issues_model.CommentList
+	for _, issue := range issues {
+		// the labels of the organization are exported with the issues using them
+		for _, label := range issue.Labels {
+			if !labelNames[label.Name] {
+				labelNames[label.Name] = true
+				export.Labels = append(export.Labels, exportLabel(label))
+			}
+		}
+		export.Issues = append(export.Issues, exportIssue(issue))
+		comments = append(comments, issue.Comments...)
+	}
+
+	if err := comments.LoadPosters(ctx); err != nil {
+		return nil, err
+	}
+	issueIndexes := make(map[int64]int64, len(issues))
+	for _, issue := range issues {
+		issueIndexes[issue.ID] = issue.Index
+	}
+	for _, comment := range comments {
+		posterID, posterName := exportPoster(comment.Poster, comment.OriginalAuthorID, comment.OriginalAuthor)
+		export.Comments = append(export.Comments, &base.Comment{
+			IssueIndex:  issueIndexes[comment.IssueID],
+			Index:       comment.ID,
+			CommentType: comment.Type.String(),
+			PosterID:    posterID,
+			PosterName:  posterName,
+			Created:     comment.CreatedUnix.AsTime(),
+			Updated:     comment.UpdatedUnix.AsTime(),
+			Content:     comment.Content,
+		})
+	}
+
+	return export, nil
+}
+
+// exportPoster returns the id and the name of the author of an issue or a comment, the id of a user who doesn't
+// exist on this instance is zero
+func exportPoster(poster *user_model.User, originalAuthorID int64, originalAuthor string) (int64, string) {
+	if originalAuthor != "" {
+		return 0, originalAuthor
+	}
+	if poster == nil || poster.ID <= 0 {
+		return 0, user_model.NewGhostUser().Name
+	}
+	return poster.ID, poster.Name
+}
+
+func exportLabel(label *issues_model.Label) *base.Label {
+	return &base.Label{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+		Exclusive:   label.Exclusive,
+	}
+}
+
+func exportMilestone(milestone *issues_model.Milestone) *base.Milestone {
+	m := &base.Milestone{
+		Title:       milestone.Name,
+		Description: milestone.Content,
+		Created:     milestone.CreatedUnix.AsTime(),
+		State:       string(api.StateOpen),
+	}
+	updated := milestone.UpdatedUnix.AsTime()
+	m.Updated = &updated
+	// the milestones without a deadline have a deadline in year 9999
+	if milestone.DeadlineUnix > 0 && milestone.DeadlineUnix.AsTime().Year() < 9999 {
+		deadline := milestone.DeadlineUnix.AsTime()
+		m.Deadline = &deadline
+	}
+	if milestone.IsClosed {
+		m.State = string(api.StateClosed)
+		closed := milestone.ClosedDateUnix.AsTime()
+		m.Closed = &closed
+	}
+	return m
+}
+
+func exportIssue(issue *issues_model.Issue) *base.Issue {
+	posterID, posterName := exportPoster(issue.Poster, issue.OriginalAuthorID, issue.OriginalAuthor)
+	is := &base.Issue{
+		Number:     issue.Index,
+		PosterID:   posterID,
+		PosterName: posterName,
+		Title:      issue.Title,
+		Content:    issue.Content,
+		Ref:        issue.Ref,
+		State:      string(api.StateOpen),
+		IsLocked:   issue.IsLocked,
+		Created:    issue.CreatedUnix.AsTime(),
+		Updated:    issue.UpdatedUnix.AsTime(),
+	}
+	if issue.Milestone != nil {
+		is.Milestone = issue.Milestone.Name
+	}
+	if issue.IsClosed {
+		is.State = string(api.StateClosed)
+		closed := issue.ClosedUnix.AsTime()
+		is.Closed = &closed
+	}
+	for _, label := range issue.Labels {
+		is.Labels = append(is.Labels, exportLabel(label))
+	}
+	for _, assignee := range issue.Assignees {
+		is.Assignees = append(is.Assignees, assignee.Name)
+	}
+	return is
+}
+
+// WriteIssuesCSV writes the issues of an export as CSV, one issue per row. The comments aren't written.
+func WriteIssuesCSV(w io.Writer, export *base.IssueExport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(issueCSVHeader); err != nil {
+		return err
+	}
+	for _, issue := range export.Issues {
+		var closed string
+		if issue.Closed != nil {
+			closed = issue.Closed.UTC().Format(time.RFC3339)
+		}
+		labels := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		if err := writer.Write([]string{
+			strconv.FormatInt(issue.Number, 10),
+			issue.Title,
+			issue.State,
+			issue.PosterName,
+			issue.Created.UTC().Format(time.RFC3339),
+			issue.Updated.UTC().Format(time.RFC3339),
+			closed,
+			issue.Milestone,
+			strings.Join(labels, "\n"),
+			strings.Join(issue.Assignees, "\n"),
+			strconv.FormatBool(issue.IsLocked),
+			issue.Content,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadIssuesCSV reads issues written by WriteIssuesCSV, only the title column is required. The labels and the
+// milestones referenced by the issues are added to the export.
+func ReadIssuesCSV(r io.Reader) (*base.IssueExport, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &base.IssueExport{}, nil
+	} else if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid csv: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, util.NewInvalidArgumentErrorf("the csv has no title column")
+	}
+
+	export := &base.IssueExport{}
+	labels := make(map[string]bool)
+	milestones := make(map[string]bool)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid csv: %v", err)
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		issue := &base.Issue{
+			Title:      get("title"),
+			PosterName: get("poster"),
+			Milestone:  get("milestone"),
+			State:      string(api.StateOpen),
+			Content:    get("content"),
+		}
+		if number := get("number"); number != "" {
+			if issue.Number, err = strconv.ParseInt(number, 10, 64); err != nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid number %q on line %d", number, line)
+			}
+		} else {
+			issue.Number = int64(len(export.Issues) + 1)
+		}
+		if strings.EqualFold(get("state"), string(api.StateClosed)) {
+			issue.State = string(api.StateClosed)
+		}
+		for column, t := range map[string]*time.Time{"created": &issue.Created, "updated": &issue.Updated} {
+			if *t, err = parseCSVTime(get(column)); err != nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid %s time on line %d: %v", column, line, err)
+			}
+		}
+		if closed, err := parseCSVTime(get("closed")); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid closed time on line %d: %v", line, err)
+		} else if !closed.IsZero() {
+			issue.Closed = &closed
+		}
+		if locked := get("locked"); locked != "" {
+			if issue.IsLocked, err = strconv.ParseBool(locked); err != nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid locked value %q on line %d", locked, line)
+			}
+		}
+		for _, name := range splitCSVList(get("labels")) {
+			issue.Labels = append(issue.Labels, &base.Label{Name: name})
+			if !labels[name] {
+				labels[name] = true
+				export.Labels = append(export.Labels, &base.Label{Name: name, Color: "#ffffff"})
+			}
+		}
+		issue.Assignees = splitCSVList(get("assignees"))
+		if issue.Milestone != "" && !milestones[issue.Milestone] {
+			milestones[issue.Milestone] = true
+			export.Milestones = append(export.Milestones, &base.Milestone{Title: issue.Milestone, State: string(api.StateOpen)})
+		}
+		export.Issues = append(export.Issues, issue)
+	}
+	return export, nil
+}
+
+func parseCSVTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, setting.DefaultUILocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a RFC 3339 time nor a date", s)
+	}
+	return t, nil
+}
+
+func splitCSVList(s string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
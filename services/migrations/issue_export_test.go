@@ -0,0 +1,213 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{RepoIDs: []int64{repo.ID}})
+	assert.NoError(t, err)
+
+	export, err := ExportIssues(db.DefaultContext, repo, issues)
+	assert.NoError(t, err)
+	assert.Equal(t, repo.HTMLURL(), export.OriginalURL)
+	assert.NotEmpty(t, export.Issues)
+	for _, issue := range export.Issues {
+		is := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: issue.Number})
+		assert.False(t, is.IsPull)
+		assert.Equal(t, is.Title, issue.Title)
+	}
+	for _, comment := range export.Comments {
+		assert.Equal(t, "comment", comment.CommentType)
+	}
+
+	labels := make([]string, 0, len(export.Labels))
+	for _, label := range export.Labels {
+		labels = append(labels, label.Name)
+	}
+	assert.Contains(t, labels, "label1")
+	assert.Contains(t, labels, "label2")
+	assert.Len(t, export.Milestones, 3)
+}
+
+func TestIssuesCSV(t *testing.T) {
+	created := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	closed := created.Add(time.Hour)
+	export := &base.IssueExport{
+		Issues: []*base.Issue{
+			{
+				Number:     1,
+				Title:      "First, with a comma",
+				PosterName: "octocat",
+				State:      "closed",
+				Created:    created,
+				Updated:    closed,
+				Closed:     &closed,
+				Milestone:  "v1.0",
+				Labels:     []*base.Label{{Name: "bug"}, {Name: "help wanted"}},
+				Assignees:  []string{"octocat", "hubot"},
+				Content:    "multi\nline \"content\"",
+			},
+			{
+				Number:   3,
+				Title:    "Second",
+				State:    "open",
+				IsLocked: true,
+				Created:  created,
+				Updated:  created,
+				Labels:   []*base.Label{{Name: "bug"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteIssuesCSV(&buf, export))
+
+	read, err := ReadIssuesCSV(&buf)
+	assert.NoError(t, err)
+	assertIssuesEqual(t, export.Issues, read.Issues)
+	assertLabelsEqual(t, []*base.Label{{Name: "bug", Color: "#ffffff"}, {Name: "help wanted", Color: "#ffffff"}}, read.Labels)
+	if assert.Len(t, read.Milestones, 1) {
+		assert.Equal(t, "v1.0", read.Milestones[0].Title)
+	}
+
+	read, err = ReadIssuesCSV(strings.NewReader("Title,Created\nA title,2023-10-01\nAnother title,\n"))
+	assert.NoError(t, err)
+	if assert.Len(t, read.Issues, 2) {
+		assert.EqualValues(t, 1, read.Issues[0].Number)
+		assert.Equal(t, "A title", read.Issues[0].Title)
+		assert.Equal(t, "2023-10-01", read.Issues[0].Created.Format("2006-01-02"))
+		assert.EqualValues(t, 2, read.Issues[1].Number)
+		assert.Equal(t, "open", read.Issues[1].State)
+	}
+
+	_, err = ReadIssuesCSV(strings.NewReader("number,state\n1,open\n"))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = ReadIssuesCSV(strings.NewReader("title,created\nA title,yesterday\n"))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestImportIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	labelCount := unittest.GetCount(t, &issues_model.Label{RepoID: repo.ID})
+	milestoneCount := unittest.GetCount(t, &issues_model.Milestone{RepoID: repo.ID})
+
+	created := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	newExport := func() *base.IssueExport {
+		return &base.IssueExport{
+			OriginalURL: "https://example.com/octocat/hello",
+			Issues: []*base.Issue{
+				{
+					Number:     1,
+					Title:      "Imported issue",
+					PosterName: "octocat",
+					State:      "open",
+					Created:    created,
+					Updated:    created,
+					Milestone:  "milestone1",
+					Labels:     []*base.Label{{Name: "label1"}, {Name: "imported", Color: "#00ff00"}},
+				},
+				{
+					Number:     7,
+					Title:      "Another imported issue",
+					PosterName: "hubot",
+					State:      "closed",
+					Created:    created,
+					Updated:    created,
+					Closed:     &created,
+					Milestone:  "imported milestone",
+				},
+			},
+			Comments: []*base.Comment{
+				{IssueIndex: 7, PosterName: "octocat", Created: created, Updated: created, Content: "an imported comment"},
+			},
+		}
+	}
+
+	// an invalid export doesn't import anything
+	export := newExport()
+	export.Comments[0].IssueIndex = 2
+	_, err := ImportIssues(db.DefaultContext, doer, repo, export, ImportIssuesOptions{})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = ImportIssues(db.DefaultContext, doer, repo, newExport(), ImportIssuesOptions{UserMap: map[string]string{"octocat": "not-exist"}})
+	assert.True(t, user_model.IsErrUserNotExist(err))
+	unittest.AssertNotExistsBean(t, &issues_model.Issue{RepoID: repo.ID, Title: "Imported issue"})
+
+	count, err := ImportIssues(db.DefaultContext, doer, repo, newExport(), ImportIssuesOptions{UserMap: map[string]string{"octocat": "user5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// the issues are numbered after the existing issues
+	first := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Title: "Imported issue"})
+	second := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Title: "Another imported issue"})
+	assert.EqualValues(t, 6, first.Index)
+	assert.EqualValues(t, 7, second.Index)
+	assert.EqualValues(t, 5, first.PosterID)
+	assert.Empty(t, first.OriginalAuthor)
+	assert.Equal(t, doer.ID, second.PosterID)
+	assert.Equal(t, "hubot", second.OriginalAuthor)
+	assert.True(t, second.IsClosed)
+
+	// the existing labels and milestones are reused
+	assert.NoError(t, first.LoadAttributes(db.DefaultContext))
+	assert.EqualValues(t, 1, first.MilestoneID)
+	if assert.Len(t, first.Labels, 2) {
+		assert.ElementsMatch(t, []string{"label1", "imported"}, []string{first.Labels[0].Name, first.Labels[1].Name})
+	}
+	assert.EqualValues(t, labelCount+1, unittest.GetCount(t, &issues_model.Label{RepoID: repo.ID}))
+	assert.EqualValues(t, milestoneCount+1, unittest.GetCount(t, &issues_model.Milestone{RepoID: repo.ID}))
+
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: second.ID, Content: "an imported comment"})
+	assert.EqualValues(t, 5, comment.PosterID)
+}
+
+func TestImportIssuesForgedOriginalURL(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// an export claiming to come from this instance can't attribute its issues to local users
+	created := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	_, err := ImportIssues(db.DefaultContext, doer, repo, &base.IssueExport{
+		OriginalURL: setting.AppURL + "user2/repo1",
+		Issues: []*base.Issue{
+			{Number: 1, Title: "Forged issue", PosterID: admin.ID, PosterName: admin.Name, State: "open", Created: created, Updated: created},
+		},
+		Comments: []*base.Comment{
+			{IssueIndex: 1, PosterID: admin.ID, PosterName: admin.Name, Created: created, Updated: created, Content: "a forged comment"},
+		},
+	}, ImportIssuesOptions{})
+	assert.NoError(t, err)
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Title: "Forged issue"})
+	assert.Equal(t, doer.ID, issue.PosterID)
+	assert.Equal(t, admin.Name, issue.OriginalAuthor)
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: issue.ID, Content: "a forged comment"})
+	assert.Equal(t, doer.ID, comment.PosterID)
+	assert.Equal(t, admin.Name, comment.OriginalAuthor)
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ImportIssuesOptions are the options of an import of issues
+type ImportIssuesOptions struct {
+	// UserMap maps the names of the users of the export to the names of local users. The issues and the comments
+	// of the users who aren't mapped are attributed to the importer, with their original author. The user ids
+	// of an export are never trusted, even when it claims to come from this instance.
+	UserMap map[string]string
+}
+
+// ImportIssues imports the issues of an export with their comments into an existing repository. The issues
+// are numbered after the existing issues, the labels and the milestones are matched by name and created when
+// missing. It returns the number of imported issues.
+func ImportIssues(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, export *base.IssueExport, opts ImportIssuesOptions) (int, error) {
+	if err := validateIssueExport(export); err != nil {
+		return 0, err
+	}
+
+	uploader := NewGiteaLocalUploader(ctx, doer, repo.OwnerName, repo.Name)
+	uploader.repo = repo
+	uploader.gitServiceType = structs.PlainGitService
+	uploader.userNameMap = make(map[string]int64, len(opts.UserMap))
+	for name, localName := range opts.UserMap {
+		user, err := user_model.GetUserByName(ctx, localName)
+		if err != nil {
+			return 0, err
+		}
+		uploader.userNameMap[name] = user.ID
+	}
+
+	if err := importIssueLabels(ctx, uploader, repo, export); err != nil {
+		return 0, err
+	}
+	if err := importIssueMilestones(uploader, repo, export); err != nil {
+		return 0, err
+	}
+
+	// the issues get the next indexes of the repository
+	indexes := make(map[int64]int64, len(export.Issues))
+	for _, issue := range export.Issues {
+		index, err := db.GetNextResourceIndex(ctx, "issue_index", repo.ID)
+		if err != nil {
+			return 0, err
+		}
+		indexes[issue.Number] = index
+		issue.Number = index
+		issue.ForeignIndex = 0
+	}
+	for _, comment := range export.Comments {
+		comment.IssueIndex = indexes[comment.IssueIndex]
+	}
+
+	batchSize := uploader.MaxBatchInsertSize("issue")
+	for i := 0; i < len(export.Issues); i += batchSize {
+		if err := uploader.CreateIssues(export.Issues[i:min(i+batchSize, len(export.Issues))]...); err != nil {
+			return 0, err
+		}
+	}
+	batchSize = uploader.MaxBatchInsertSize("comment")
+	for i := 0; i < len(export.Comments); i += batchSize {
+		if err := uploader.CreateComments(export.Comments[i:min(i+batchSize, len(export.Comments))]...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := uploader.Finish(); err != nil {
+		return 0, err
+	}
+	issue_indexer.UpdateRepoIndexer(ctx, repo.ID)
+	return len(export.Issues), nil
+}
+
+// validateIssueExport checks an export before importing it, so an invalid export doesn't import anything
+func validateIssueExport(export *base.IssueExport) error {
+	numbers := make(map[int64]bool, len(export.Issues))
+	for _, issue := range export.Issues {
+		if strings.TrimSpace(issue.Title) == "" {
+			return util.NewInvalidArgumentErrorf("issue #%d has no title", issue.Number)
+		}
+		if numbers[issue.Number] {
+			return util.NewInvalidArgumentErrorf("issue #%d is duplicated", issue.Number)
+		}
+		numbers[issue.Number] = true
+	}
+	for _, comment := range export.Comments {
+		if !numbers[comment.IssueIndex] {
+			return util.NewInvalidArgumentErrorf("a comment references the unknown issue #%d", comment.IssueIndex)
+		}
+		if comment.CommentType != "" && issues_model.AsCommentType(comment.CommentType) != issues_model.CommentTypeComment {
+			return util.NewInvalidArgumentErrorf("the comments of type %q can't be imported", comment.CommentType)
+		}
+	}
+	return nil
+}
+
+// importIssueLabels creates the labels of an export missing in a repository, the labels of the repository and
+// of its organization are reused
+func importIssueLabels(ctx context.Context, uploader *GiteaLocalUploader, repo *repo_model.Repository, export *base.IssueExport) error {
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if err := repo.LoadOwner(ctx); err != nil {
+		return err
+	}
+	if repo.Owner.IsOrganization() {
+		orgLabels, err := issues_model.GetLabelsByOrgID(ctx, repo.OwnerID, "", db.ListOptions{})
+		if err != nil {
+			return err
+		}
+		// the labels of the repository take precedence
+		labels = append(orgLabels, labels...)
+	}
+	for _, label := range labels {
+		uploader.labels[label.Name] = label
+	}
+
+	var missing []*base.Label
+	addMissing := func(label *base.Label) {
+		if _, ok := uploader.labels[label.Name]; !ok && strings.TrimSpace(label.Name) != "" {
+			uploader.labels[label.Name] = nil
+			missing = append(missing, label)
+		}
+	}
+	for _, label := range export.Labels {
+		addMissing(label)
+	}
+	for _, issue := range export.Issues {
+		for _, label := range issue.Labels {
+			addMissing(label)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return uploader.CreateLabels(missing...)
+}
+
+// importIssueMilestones creates the milestones of an export missing in a repository
+func importIssueMilestones(uploader *GiteaLocalUploader, repo *repo_model.Repository, export *base.IssueExport) error {
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		RepoID: repo.ID,
+		State:  structs.StateAll,
+	})
+	if err != nil {
+		return err
+	}
+	for _, milestone := range milestones {
+		uploader.milestones[milestone.Name] = milestone.ID
+	}
+
+	var missing []*base.Milestone
+	addMissing := func(milestone *base.Milestone) {
+		if _, ok := uploader.milestones[milestone.Title]; !ok && strings.TrimSpace(milestone.Title) != "" {
+			uploader.milestones[milestone.Title] = 0
+			missing = append(missing, milestone)
+		}
+	}
+	for _, milestone := range export.Milestones {
+		addMissing(milestone)
+	}
+	for _, issue := range export.Issues {
+		addMissing(&base.Milestone{Title: issue.Milestone, State: string(structs.StateOpen)})
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return uploader.CreateMilestones(missing...)
+}
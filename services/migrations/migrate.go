@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	base "code.gitea.io/gitea/modules/migration"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -109,6 +110,12 @@ func checkByAllowBlockList(hostName string, addrList []net.IP) error {
 
 // MigrateRepository migrate repository according MigrateOptions
 func MigrateRepository(ctx context.Context, doer *user_model.User, ownerName string, opts base.MigrateOptions, messenger base.Messenger) (*repo_model.Repository, error) {
+	return migrateRepositoryWithUserMap(ctx, doer, ownerName, opts, messenger, nil)
+}
+
+// migrateRepositoryWithUserMap migrates a repository, the issues, comments and reviews of the users of userNameMap
+// are attributed to the local users it maps their names to
+func migrateRepositoryWithUserMap(ctx context.Context, doer *user_model.User, ownerName string, opts base.MigrateOptions, messenger base.Messenger, userNameMap map[string]int64) (*repo_model.Repository, error) {
 	err := IsMigrateURLAllowed(opts.CloneAddr, doer)
 	if err != nil {
 		return nil, err
@@ -126,7 +133,11 @@ func MigrateRepository(ctx context.Context, doer *user_model.User, ownerName str
 
 	uploader := NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
 	uploader.gitServiceType = opts.GitServiceType
+	if userNameMap != nil {
+		uploader.userNameMap = userNameMap
+	}
 
+	migratedUnix := timeutil.TimeStampNow()
 	if err := migrateRepository(ctx, doer, downloader, uploader, opts, messenger); err != nil {
 		if err1 := uploader.Rollback(); err1 != nil {
 			log.Error("rollback failed: %v", err1)
@@ -136,6 +147,10 @@ func MigrateRepository(ctx context.Context, doer *user_model.User, ownerName str
 		}
 		return nil, err
 	}
+
+	if err := CreateMigrationSync(ctx, doer, uploader.repo, opts, migratedUnix); err != nil {
+		log.Error("CreateMigrationSync for %s/%s failed: %v", ownerName, opts.RepoName, err)
+	}
 	return uploader.repo, nil
 }
 
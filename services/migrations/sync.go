@@ -0,0 +1,610 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/pull"
+)
+
+// migrationSyncQueue holds the IDs of the migration syncs to run
+var migrationSyncQueue *queue.WorkerPoolQueue[int64]
+
+// InitMigrationSyncQueue starts the queue which runs the syncs of the migrated repositories
+func InitMigrationSyncQueue() error {
+	migrationSyncQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "migration_sync", handleMigrationSync)
+	if migrationSyncQueue == nil {
+		return errors.New("unable to create migration_sync queue")
+	}
+	go graceful.GetManager().RunWithCancel(migrationSyncQueue)
+	return nil
+}
+
+func handleMigrationSync(ids ...int64) []int64 {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, id := range ids {
+		m, err := repo_model.GetMigrationSyncByID(ctx, id)
+		if err != nil {
+			log.Error("GetMigrationSyncByID[%d]: %v", id, err)
+			continue
+		}
+		if err := runMigrationSync(ctx, m); err != nil {
+			log.Error("runMigrationSync[%d]: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// AddMigrationSyncToQueue adds the migration sync of a repository to the queue to run it as soon as possible
+func AddMigrationSyncToQueue(id int64) {
+	if err := migrationSyncQueue.Push(id); err != nil {
+		log.Error("Unable to push migration sync %d to queue: %v", id, err)
+	}
+}
+
+// SyncMigratedRepositories adds the migration syncs which are due to the queue
+func SyncMigratedRepositories(ctx context.Context, limit int) error {
+	return repo_model.MigrationSyncsIterate(ctx, limit, func(_ int, bean any) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		AddMigrationSyncToQueue(bean.(*repo_model.MigrationSync).ID)
+		return nil
+	})
+}
+
+// CreateMigrationSync starts syncing a migrated repository with its original repository at the given interval
+func CreateMigrationSync(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, opts base.MigrateOptions, migratedUnix timeutil.TimeStamp) error {
+	if opts.SyncInterval == "" || opts.Mirror || opts.GitServiceType == api.PlainGitService {
+		return nil
+	}
+	interval, err := time.ParseDuration(opts.SyncInterval)
+	if err != nil {
+		return err
+	}
+	if interval < setting.Mirror.MinInterval {
+		return fmt.Errorf("sync interval %v is less than the minimum interval %v", interval, setting.Mirror.MinInterval)
+	}
+
+	m := &repo_model.MigrationSync{
+		RepoID:         repo.ID,
+		DoerID:         doer.ID,
+		GitServiceType: opts.GitServiceType,
+		CloneAddr:      util.SanitizeCredentialURLs(opts.CloneAddr),
+		Milestones:     opts.Milestones,
+		Labels:         opts.Labels,
+		Issues:         opts.Issues,
+		Comments:       opts.Comments,
+		PullRequests:   opts.PullRequests,
+		Releases:       opts.Releases,
+		Interval:       interval,
+		MigratedUnix:   migratedUnix,
+		LastSyncUnix:   migratedUnix,
+	}
+	if err := m.SetAuth(opts.AuthUsername, opts.AuthPassword, opts.AuthToken); err != nil {
+		return err
+	}
+	if err := repo_model.InsertMigrationSync(ctx, m); err != nil {
+		return err
+	}
+	m.ScheduleNextSync()
+	return repo_model.UpdateMigrationSync(ctx, m)
+}
+
+func runMigrationSync(ctx context.Context, m *repo_model.MigrationSync) error {
+	startUnix := timeutil.TimeStampNow()
+	if err := SyncMigratedRepository(ctx, m); err != nil {
+		err = util.SanitizeErrorCredentialURLs(err)
+		log.Error("Sync of migrated repository %d failed: %v", m.RepoID, err)
+		m.LastError = err.Error()
+	} else {
+		m.LastError = ""
+		m.LastSyncUnix = startUnix
+	}
+	m.ScheduleNextSync()
+	return repo_model.UpdateMigrationSync(ctx, m)
+}
+
+// SyncMigratedRepository downloads the issues, comments, pull requests and releases updated in the original repository
+// of a migrated repository since its last sync, the records which were already migrated or synced are updated.
+// The git data is not downloaded again, pull mirrors keep it up to date.
+func SyncMigratedRepository(ctx context.Context, m *repo_model.MigrationSync) error {
+	repo, err := m.GetRepository(ctx)
+	if err != nil {
+		return err
+	}
+	if repo.IsArchived {
+		return nil
+	}
+	doer, err := user_model.GetUserByID(ctx, m.DoerID)
+	if err != nil {
+		return err
+	}
+	password, token, err := m.DecryptAuth()
+	if err != nil {
+		return err
+	}
+
+	opts := base.MigrateOptions{
+		CloneAddr:       m.CloneAddr,
+		AuthUsername:    m.AuthUsername,
+		AuthPassword:    password,
+		AuthToken:       token,
+		RepoName:        repo.Name,
+		OriginalURL:     repo.OriginalURL,
+		GitServiceType:  m.GitServiceType,
+		Private:         repo.IsPrivate,
+		Milestones:      m.Milestones,
+		Labels:          m.Labels,
+		Issues:          m.Issues,
+		Comments:        m.Comments,
+		PullRequests:    m.PullRequests,
+		Releases:        m.Releases,
+		MigrateToRepoID: repo.ID,
+	}
+	if err := IsMigrateURLAllowed(opts.CloneAddr, doer); err != nil {
+		return err
+	}
+
+	downloader, err := newDownloader(ctx, repo.OwnerName, opts)
+	if err != nil {
+		return err
+	}
+	if incremental, ok := downloader.(base.IncrementalDownloader); ok && m.LastSyncUnix > 0 {
+		incremental.SetSince(m.LastSyncUnix.AsTime())
+	}
+
+	uploader := newMigrationSyncUploader(ctx, doer, repo, m)
+	return migrateRepository(ctx, doer, downloader, uploader, opts, nil)
+}
+
+var _ base.Uploader = &migrationSyncUploader{}
+
+// migrationSyncUploader uploads the records downloaded again from the original repository of a migrated repository,
+// it updates the records which the migration or a previous sync created instead of inserting them again
+type migrationSyncUploader struct {
+	*GiteaLocalUploader
+	sync *repo_model.MigrationSync
+}
+
+func newMigrationSyncUploader(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, m *repo_model.MigrationSync) *migrationSyncUploader {
+	uploader := NewGiteaLocalUploader(ctx, doer, repo.OwnerName, repo.Name)
+	uploader.gitServiceType = m.GitServiceType
+	uploader.repo = repo
+	return &migrationSyncUploader{GiteaLocalUploader: uploader, sync: m}
+}
+
+// CreateRepo opens the migrated repository and loads its labels and milestones
+func (s *migrationSyncUploader) CreateRepo(repo *base.Repository, opts base.MigrateOptions) error {
+	s.sameApp = strings.HasPrefix(repo.OriginalURL, setting.AppURL)
+
+	labels, err := issues_model.GetLabelsByRepoID(s.ctx, s.repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, lb := range labels {
+		s.labels[lb.Name] = lb
+	}
+
+	milestones, _, err := issues_model.GetMilestones(issues_model.GetMilestonesOption{
+		RepoID: s.repo.ID,
+		State:  api.StateAll,
+	})
+	if err != nil {
+		return err
+	}
+	for _, ms := range milestones {
+		s.milestones[ms.Name] = ms.ID
+	}
+
+	s.gitRepo, err = git.OpenRepository(s.ctx, s.repo.RepoPath())
+	return err
+}
+
+// CreateMilestones creates the new milestones and updates the existing ones
+func (s *migrationSyncUploader) CreateMilestones(milestones ...*base.Milestone) error {
+	newMilestones := make([]*base.Milestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		id, ok := s.milestones[milestone.Title]
+		if !ok {
+			newMilestones = append(newMilestones, milestone)
+			continue
+		}
+
+		ms, err := issues_model.GetMilestoneByRepoID(s.ctx, s.repo.ID, id)
+		if err != nil {
+			return err
+		}
+		oldIsClosed := ms.IsClosed
+		ms.Content = milestone.Description
+		ms.IsClosed = milestone.State == "closed"
+		if milestone.Deadline != nil {
+			ms.DeadlineUnix = timeutil.TimeStamp(milestone.Deadline.Unix())
+		}
+		if err := issues_model.UpdateMilestone(s.ctx, ms, oldIsClosed); err != nil {
+			return err
+		}
+	}
+
+	if len(newMilestones) == 0 {
+		return nil
+	}
+	return s.GiteaLocalUploader.CreateMilestones(newMilestones...)
+}
+
+// CreateLabels creates the labels which don't exist yet
+func (s *migrationSyncUploader) CreateLabels(labels ...*base.Label) error {
+	newLabels := make([]*base.Label, 0, len(labels))
+	for _, l := range labels {
+		if _, ok := s.labels[l.Name]; !ok {
+			newLabels = append(newLabels, l)
+		}
+	}
+
+	if len(newLabels) == 0 {
+		return nil
+	}
+	return s.GiteaLocalUploader.CreateLabels(newLabels...)
+}
+
+// CreateReleases creates the new releases and updates the existing ones of the same tags
+func (s *migrationSyncUploader) CreateReleases(releases ...*base.Release) error {
+	newReleases := make([]*base.Release, 0, len(releases))
+	for _, release := range releases {
+		// without a tag, a draft can't be told apart from the others
+		if release.TagName == "" {
+			continue
+		}
+
+		rel, err := repo_model.GetRelease(s.ctx, s.repo.ID, release.TagName)
+		if repo_model.IsErrReleaseNotExist(err) {
+			newReleases = append(newReleases, release)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		rel.Title = release.Name
+		rel.Note = release.Body
+		rel.IsDraft = release.Draft
+		rel.IsPrerelease = release.Prerelease
+		rel.IsTag = false
+		if err := repo_model.UpdateRelease(s.ctx, rel); err != nil {
+			return err
+		}
+	}
+
+	if len(newReleases) == 0 {
+		return nil
+	}
+	return s.GiteaLocalUploader.CreateReleases(newReleases...)
+}
+
+// getIssue returns the local issue of an upstream issue or pull request, or nil if it doesn't have one yet
+func (s *migrationSyncUploader) getIssue(number int64) (*issues_model.Issue, error) {
+	if issue, ok := s.issues[number]; ok {
+		return issue, nil
+	}
+
+	localIndexes, err := repo_model.GetMigrationSyncRefs(s.ctx, s.repo.ID, repo_model.MigrationSyncRefIssue, []int64{number})
+	if err != nil {
+		return nil, err
+	}
+	index, hasRef := localIndexes[number]
+	if !hasRef {
+		index = number
+	}
+
+	issue, err := issues_model.GetIssueByIndex(s.ctx, s.repo.ID, index)
+	if issues_model.IsErrIssueNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	// without a reference, the issue with the same index is only the copy made by the migration
+	// if it was created before it, the others were created locally after the migration
+	if !hasRef && issue.CreatedUnix >= s.sync.MigratedUnix {
+		return nil, nil
+	}
+
+	s.issues[number] = issue
+	return issue, nil
+}
+
+// newIssueIndex returns the index of the local issue of a new upstream issue, its number unless it's already used
+func (s *migrationSyncUploader) newIssueIndex(number int64) (int64, error) {
+	_, err := issues_model.GetIssueByIndex(s.ctx, s.repo.ID, number)
+	if issues_model.IsErrIssueNotExist(err) {
+		return number, db.SyncMaxResourceIndex(s.ctx, "issue_index", s.repo.ID, number)
+	} else if err != nil {
+		return 0, err
+	}
+	return db.GetNextResourceIndex(s.ctx, "issue_index", s.repo.ID)
+}
+
+// updateIssue updates the local issue with the fields of the issue converted from the upstream one
+func (s *migrationSyncUploader) updateIssue(issue, upstream *issues_model.Issue) error {
+	oldMilestoneID := issue.MilestoneID
+	issue.Title = upstream.Title
+	issue.Content = upstream.Content
+	issue.IsClosed = upstream.IsClosed
+	issue.ClosedUnix = upstream.ClosedUnix
+	issue.IsLocked = upstream.IsLocked
+	issue.MilestoneID = upstream.MilestoneID
+	issue.UpdatedUnix = upstream.UpdatedUnix
+	return issues_model.UpdateMigratedIssue(s.ctx, issue, upstream.Labels, oldMilestoneID)
+}
+
+// CreateIssues creates the new issues and updates the existing ones
+func (s *migrationSyncUploader) CreateIssues(issues ...*base.Issue) error {
+	for _, issue := range issues {
+		is, err := s.getIssue(issue.Number)
+		if err != nil {
+			return err
+		}
+
+		upstream, err := s.newIssue(issue)
+		if err != nil {
+			return err
+		}
+
+		if is != nil {
+			if err := s.updateIssue(is, upstream); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if upstream.Index, err = s.newIssueIndex(issue.Number); err != nil {
+			return err
+		}
+		if err := issues_model.InsertIssues(upstream); err != nil {
+			return err
+		}
+		if err := repo_model.InsertMigrationSyncRef(s.ctx, s.repo.ID, repo_model.MigrationSyncRefIssue, issue.Number, upstream.Index); err != nil {
+			return err
+		}
+		s.issues[issue.Number] = upstream
+	}
+	return nil
+}
+
+// CreatePullRequests creates the new pull requests and updates the existing ones, the git data of the existing
+// ones is left as it is
+func (s *migrationSyncUploader) CreatePullRequests(prs ...*base.PullRequest) error {
+	for _, pr := range prs {
+		is, err := s.getIssue(pr.Number)
+		if err != nil {
+			return err
+		}
+
+		if is != nil {
+			if err := s.updatePullRequest(is, pr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// the git refs of the pull request are named after its local index
+		localPR := *pr
+		if localPR.Number, err = s.newIssueIndex(pr.Number); err != nil {
+			return err
+		}
+		gpr, err := s.newPullRequest(&localPR)
+		if err != nil {
+			return err
+		}
+		if err := issues_model.InsertPullRequests(s.ctx, gpr); err != nil {
+			return err
+		}
+		if err := repo_model.InsertMigrationSyncRef(s.ctx, s.repo.ID, repo_model.MigrationSyncRefIssue, pr.Number, gpr.Index); err != nil {
+			return err
+		}
+		s.issues[pr.Number] = gpr.Issue
+		pull.AddToTaskQueue(s.ctx, gpr)
+	}
+	return nil
+}
+
+func (s *migrationSyncUploader) updatePullRequest(is *issues_model.Issue, pr *base.PullRequest) error {
+	upstream, err := s.newIssue(&base.Issue{
+		Number:      pr.Number,
+		PosterID:    pr.PosterID,
+		PosterName:  pr.PosterName,
+		PosterEmail: pr.PosterEmail,
+		Title:       pr.Title,
+		Content:     pr.Content,
+		Milestone:   pr.Milestone,
+		State:       pr.State,
+		IsLocked:    pr.IsLocked,
+		Created:     pr.Created,
+		Updated:     pr.Updated,
+		Closed:      pr.Closed,
+		Labels:      pr.Labels,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.updateIssue(is, upstream); err != nil {
+		return err
+	}
+
+	gpr, err := issues_model.GetPullRequestByIssueID(s.ctx, is.ID)
+	if err != nil {
+		return err
+	}
+	if gpr.HasMerged || !pr.Merged {
+		return nil
+	}
+	gpr.HasMerged = true
+	gpr.MergedCommitID = pr.MergeCommitSHA
+	gpr.MergerID = s.doer.ID
+	if pr.MergedTime != nil {
+		gpr.MergedUnix = timeutil.TimeStamp(pr.MergedTime.Unix())
+	}
+	return gpr.UpdateCols("has_merged", "merged_commit_id", "merger_id", "merged_unix")
+}
+
+// CreateComments creates the new comments and updates the existing ones
+func (s *migrationSyncUploader) CreateComments(comments ...*base.Comment) error {
+	foreignIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		foreignIDs = append(foreignIDs, comment.Index)
+	}
+	localIDs, err := repo_model.GetMigrationSyncRefs(s.ctx, s.repo.ID, repo_model.MigrationSyncRefComment, foreignIDs)
+	if err != nil {
+		return err
+	}
+
+	newComments := make([]*base.Comment, 0, len(comments))
+	for _, comment := range comments {
+		issue, err := s.getIssue(comment.IssueIndex)
+		if err != nil {
+			return err
+		} else if issue == nil {
+			log.Warn("Comment %d of the migration sync of %s/%s references non existent IssueIndex %d", comment.Index, s.repoOwner, s.repoName, comment.IssueIndex)
+			continue
+		}
+
+		var c *issues_model.Comment
+		if localID, ok := localIDs[comment.Index]; ok && comment.Index > 0 {
+			c, err = issues_model.GetCommentByID(s.ctx, localID)
+			if issues_model.IsErrCommentNotExist(err) {
+				// the comment was deleted locally
+				continue
+			}
+		} else if comment.Index <= 0 || comment.Created.Unix() < int64(s.sync.MigratedUnix) {
+			tp := issues_model.CommentTypeComment
+			if comment.CommentType != "" {
+				tp = issues_model.AsCommentType(comment.CommentType)
+			}
+			c, err = issues_model.GetMigratedComment(s.ctx, issue.ID, tp, timeutil.TimeStamp(comment.Created.Unix()))
+			if issues_model.IsErrCommentNotExist(err) {
+				c, err = nil, nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if c == nil {
+			newComments = append(newComments, comment)
+			continue
+		}
+		c.Content = comment.Content
+		if !comment.Updated.IsZero() {
+			c.UpdatedUnix = timeutil.TimeStamp(comment.Updated.Unix())
+		}
+		if err := issues_model.UpdateMigratedComment(s.ctx, c); err != nil {
+			return err
+		}
+	}
+
+	cms, err := s.newComments(newComments...)
+	if err != nil {
+		return err
+	}
+	if len(cms) == 0 {
+		return nil
+	}
+	if err := issues_model.InsertIssueComments(s.ctx, cms); err != nil {
+		return err
+	}
+	for i, cm := range cms {
+		if newComments[i].Index > 0 {
+			if err := repo_model.InsertMigrationSyncRef(s.ctx, s.repo.ID, repo_model.MigrationSyncRefComment, newComments[i].Index, cm.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateReviews creates the new reviews, the existing ones are left as they are
+func (s *migrationSyncUploader) CreateReviews(reviews ...*base.Review) error {
+	foreignIDs := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		foreignIDs = append(foreignIDs, review.ID)
+	}
+	localIDs, err := repo_model.GetMigrationSyncRefs(s.ctx, s.repo.ID, repo_model.MigrationSyncRefReview, foreignIDs)
+	if err != nil {
+		return err
+	}
+
+	newReviews := make([]*base.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if _, ok := localIDs[review.ID]; ok && review.ID > 0 {
+			continue
+		}
+		issue, err := s.getIssue(review.IssueIndex)
+		if err != nil {
+			return err
+		} else if issue == nil {
+			log.Warn("Review %d of the migration sync of %s/%s references non existent IssueIndex %d", review.ID, s.repoOwner, s.repoName, review.IssueIndex)
+			continue
+		}
+		if review.ID <= 0 || review.CreatedAt.Unix() < int64(s.sync.MigratedUnix) {
+			_, err := issues_model.GetMigratedComment(s.ctx, issue.ID, issues_model.CommentTypeReview, timeutil.TimeStamp(review.CreatedAt.Unix()))
+			if err == nil {
+				continue
+			} else if !issues_model.IsErrCommentNotExist(err) {
+				return err
+			}
+		}
+		newReviews = append(newReviews, review)
+	}
+
+	cms, err := s.newReviews(newReviews...)
+	if err != nil {
+		return err
+	}
+	if len(cms) == 0 {
+		return nil
+	}
+	if err := issues_model.InsertReviews(s.ctx, cms); err != nil {
+		return err
+	}
+	for i, cm := range cms {
+		if newReviews[i].ID > 0 {
+			if err := repo_model.InsertMigrationSyncRef(s.ctx, s.repo.ID, repo_model.MigrationSyncRefReview, newReviews[i].ID, cm.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Rollback does nothing, the records synced before an error are kept and updated again by the next sync
+func (s *migrationSyncUploader) Rollback() error {
+	return nil
+}
+
+// Finish updates the issue index and the statistics of the repository
+func (s *migrationSyncUploader) Finish() error {
+	if err := issues_model.RecalculateIssueIndexForRepo(s.ctx, s.repo.ID); err != nil {
+		return err
+	}
+	return models.UpdateRepoStats(s.ctx, s.repo.ID)
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationSyncUploader(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	// the issues of the repository with the index 5 and above were created after the migration
+	m := &repo_model.MigrationSync{RepoID: repo.ID, DoerID: doer.ID, MigratedUnix: 946684845}
+
+	uploader := newMigrationSyncUploader(db.DefaultContext, doer, repo, m)
+	assert.NoError(t, uploader.CreateRepo(&base.Repository{OriginalURL: "https://example.com/user2/repo1"}, base.MigrateOptions{}))
+	defer uploader.Close()
+
+	created := time.Unix(946684800, 0)
+	updated := time.Now()
+
+	// the issue copied by the migration is updated
+	assert.NoError(t, uploader.CreateIssues(&base.Issue{Number: 1, Title: "issue1 updated", State: "closed", Created: created, Updated: updated}))
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 1})
+	assert.Equal(t, "issue1 updated", issue.Title)
+	assert.True(t, issue.IsClosed)
+
+	// a new upstream issue whose number is used by a local issue gets the next index
+	assert.NoError(t, uploader.CreateIssues(&base.Issue{Number: 5, Title: "upstream issue5", State: "open", Created: updated, Updated: updated}))
+	refs, err := repo_model.GetMigrationSyncRefs(db.DefaultContext, repo.ID, repo_model.MigrationSyncRefIssue, []int64{5})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 6, refs[5])
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 6, Title: "upstream issue5"})
+
+	// the comment copied by the migration is updated and the new comment is only inserted once
+	comments := []*base.Comment{
+		{IssueIndex: 1, Index: 100, Content: "comment updated", Created: time.Unix(946684811, 0), Updated: updated},
+		{IssueIndex: 5, Index: 101, Content: "new comment", Created: updated, Updated: updated},
+	}
+	assert.NoError(t, uploader.CreateComments(comments...))
+	comments[1].Content = "new comment updated"
+	assert.NoError(t, newMigrationSyncUploader(db.DefaultContext, doer, repo, m).CreateComments(comments...))
+
+	assert.Equal(t, "comment updated", unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 2}).Content)
+	unittest.AssertCount(t, &issues_model.Comment{IssueID: issue.ID}, 3)
+	newIssue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 6})
+	newComment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: newIssue.ID})
+	assert.Equal(t, "new comment updated", newComment.Content)
+	assert.Equal(t, timeutil.TimeStamp(updated.Unix()), newComment.CreatedUnix)
+}
@@ -25,6 +25,8 @@ func doMirrorSync(ctx context.Context, req *SyncRequest) {
 		_ = SyncPushMirror(ctx, req.ReferenceID)
 	case PullMirrorType:
 		_ = SyncPullMirror(ctx, req.ReferenceID)
+	case PartialMirrorType:
+		_ = SyncPartialMirror(ctx, req.ReferenceID)
 	default:
 		log.Error("Unknown Request type in queue: %v for MirrorID[%d]", req.Type, req.ReferenceID)
 	}
@@ -61,6 +63,14 @@ func Update(ctx context.Context, pullLimit, pushLimit int) error {
 			repo = m.Repo
 			mirrorType = PushMirrorType
 			referenceID = m.ID
+		} else if m, ok := bean.(*repo_model.PartialMirror); ok {
+			var err error
+			if repo, err = m.GetRepository(ctx); err != nil {
+				log.Error("Disconnected partial mirror found: %d", m.ID)
+				return nil
+			}
+			mirrorType = PartialMirrorType
+			referenceID = m.ID
 		} else {
 			log.Error("Unknown bean: %v", bean)
 			return nil
@@ -76,9 +86,12 @@ func Update(ctx context.Context, pullLimit, pushLimit int) error {
 		// Push to the Queue
 		if err := PushToQueue(mirrorType, referenceID); err != nil {
 			if err == queue.ErrAlreadyInQueue {
-				if mirrorType == PushMirrorType {
+				switch mirrorType {
+				case PushMirrorType:
 					log.Trace("PushMirrors for %-v already queued for sync", repo)
-				} else {
+				case PartialMirrorType:
+					log.Trace("PartialMirrors for %-v already queued for sync", repo)
+				default:
 					log.Trace("PullMirrors for %-v already queued for sync", repo)
 				}
 				return nil
@@ -100,6 +113,23 @@ func Update(ctx context.Context, pullLimit, pushLimit int) error {
 			log.Error("MirrorsIterate: %v", err)
 			return err
 		}
+		// partial mirrors pull from upstream too so they share the limit of the pull mirrors
+		if pullLimit < 0 || pullMirrorsRequested < pullLimit {
+			limit := pullLimit
+			if limit > 0 {
+				limit -= pullMirrorsRequested
+			}
+			if err := repo_model.PartialMirrorsIterate(ctx, limit, func(idx int, bean any) error {
+				if err := handler(idx, bean); err != nil {
+					return err
+				}
+				pullMirrorsRequested++
+				return nil
+			}); err != nil && err != errLimit {
+				log.Error("PartialMirrorsIterate: %v", err)
+				return err
+			}
+		}
 	}
 
 	pushMirrorsRequested := 0
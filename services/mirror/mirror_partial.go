@@ -0,0 +1,365 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/proxy"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// upstreamSyncBranchSuffix is appended to a diverged branch to name the branch of the pull request which merges the
+// upstream changes into it
+const upstreamSyncBranchSuffix = "-upstream"
+
+// AddPartialMirrorRemote registers the remote of the partial mirror
+func AddPartialMirrorRemote(ctx context.Context, m *repo_model.PartialMirror, addr string) error {
+	repo, err := m.GetRepository(ctx)
+	if err != nil {
+		return err
+	}
+	cmd := git.NewCommand(ctx, "remote", "add").AddDynamicArguments(m.RemoteName, addr)
+	if strings.Contains(addr, "://") && strings.Contains(addr, "@") {
+		cmd.SetDescription(fmt.Sprintf("remote add %s %s [repo_path: %s]", m.RemoteName, util.SanitizeCredentialURLs(addr), repo.RepoPath()))
+	} else {
+		cmd.SetDescription(fmt.Sprintf("remote add %s %s [repo_path: %s]", m.RemoteName, addr, repo.RepoPath()))
+	}
+	_, _, err = cmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+	return err
+}
+
+// RemovePartialMirror removes the remote of the partial mirror with its remote-tracking refs, then deletes it
+func RemovePartialMirror(ctx context.Context, m *repo_model.PartialMirror) error {
+	repo, err := m.GetRepository(ctx)
+	if err != nil {
+		return err
+	}
+	if _, _, err := git.NewCommand(ctx, "remote", "rm").AddDynamicArguments(m.RemoteName).RunStdString(&git.RunOpts{Dir: repo.RepoPath()}); err != nil {
+		log.Warn("Remote of PartialMirror[%d] could not be removed: %v", m.ID, err)
+	}
+	return repo_model.DeletePartialMirror(ctx, m.RepoID, m.ID)
+}
+
+// SyncPartialMirror fetches the upstream branches of the partial mirror, updates the branches they are mapped to and
+// schedules the next run
+func SyncPartialMirror(ctx context.Context, mirrorID int64) bool {
+	log.Trace("SyncPartialMirror [mirror: %d]", mirrorID)
+	defer func() {
+		err := recover()
+		if err == nil {
+			return
+		}
+		log.Error("PANIC whilst SyncPartialMirror[%d] Panic: %v\nStacktrace: %s", mirrorID, err, log.Stack(2))
+	}()
+
+	m, err := repo_model.GetPartialMirrorByID(ctx, mirrorID)
+	if err != nil {
+		log.Error("GetPartialMirrorByID [%d]: %v", mirrorID, err)
+		return false
+	}
+	repo, err := m.GetRepository(ctx)
+	if err != nil {
+		log.Error("SyncPartialMirror [mirror: %d]: %v", m.ID, err)
+		return false
+	}
+
+	ctx, _, finished := process.GetManager().AddContext(ctx, fmt.Sprintf("Syncing PartialMirror %s from %s", repo.FullName(), m.RemoteName))
+	defer finished()
+
+	diverged, err := runPartialSync(ctx, m)
+	m.LastError = ""
+	if err != nil {
+		log.Error("SyncPartialMirror [mirror: %d][repo: %-v]: %v", m.ID, repo, err)
+		m.LastError = stripExitStatus.ReplaceAllLiteralString(util.SanitizeCredentialURLs(err.Error()), "")
+	}
+
+	previouslyDiverged := container.SetOf(m.DivergedBranches...)
+	var newlyDiverged []string
+	for _, branch := range diverged {
+		if !previouslyDiverged.Contains(branch) {
+			newlyDiverged = append(newlyDiverged, branch)
+		}
+	}
+	m.DivergedBranches = diverged
+	m.UpdatedUnix = timeutil.TimeStampNow()
+	m.ScheduleNextUpdate()
+	if err := repo_model.UpdatePartialMirror(ctx, m); err != nil {
+		log.Error("UpdatePartialMirror [%d]: %v", m.ID, err)
+		return false
+	}
+
+	if len(newlyDiverged) > 0 {
+		if err := notifyPartialMirrorDiverged(ctx, m, newlyDiverged); err != nil {
+			log.Error("SyncPartialMirror [mirror: %d]: unable to notify the diverged branches: %v", m.ID, err)
+		}
+	}
+
+	log.Trace("SyncPartialMirror [mirror: %d][repo: %-v]: Finished", m.ID, repo)
+	return err == nil
+}
+
+// partialSyncUpdate is the update of a branch of the repository to the commit of an upstream branch
+type partialSyncUpdate struct {
+	upstreamRef string
+	target      string
+	force       bool
+}
+
+// runPartialSync fetches the upstream branches and pushes them to their target branches, it returns the target
+// branches which have diverged from upstream
+func runPartialSync(ctx context.Context, m *repo_model.PartialMirror) ([]string, error) {
+	repoPath := m.Repo.RepoPath()
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+
+	mappings, err := repo_model.ParseBranchMappings(m.BranchMappings)
+	if err != nil {
+		return nil, err
+	}
+	doer, err := partialMirrorDoer(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteURL, err := git.GetRemoteURL(ctx, repoPath, m.RemoteName)
+	if err != nil {
+		return nil, fmt.Errorf("GetRemoteURL: %w", err)
+	}
+
+	log.Trace("SyncPartialMirror [mirror: %d][repo: %-v]: fetching upstream branches...", m.ID, m.Repo)
+	refspecs := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		refspecs = append(refspecs, mapping.Refspec(m.RemoteName))
+	}
+	if _, stderr, err := git.NewCommand(ctx, "fetch", "--prune", "--no-tags").AddDynamicArguments(m.RemoteName).AddDynamicArguments(refspecs...).
+		SetDescription(fmt.Sprintf("PartialMirror.runSync: %s", m.Repo.FullName())).
+		RunStdString(&git.RunOpts{Dir: repoPath, Timeout: timeout, Env: proxy.EnvWithProxy(remoteURL.URL)}); err != nil {
+		return nil, git.ConcatenateError(err, stderr)
+	}
+
+	if m.LFS && setting.LFS.StartServer {
+		log.Trace("SyncPartialMirror [mirror: %d][repo: %-v]: syncing LFS objects...", m.ID, m.Repo)
+		gitRepo, err := git.OpenRepository(ctx, repoPath)
+		if err != nil {
+			return nil, err
+		}
+		endpoint := lfs.DetermineEndpoint(remoteURL.String(), m.LFSEndpoint)
+		err = repo_module.StoreMissingLfsObjectsInRepository(ctx, m.Repo, gitRepo, lfs.NewClient(endpoint, nil))
+		gitRepo.Close()
+		if err != nil {
+			return nil, fmt.Errorf("StoreMissingLfsObjectsInRepository: %w", err)
+		}
+	}
+
+	remotePrefix := git.RemotePrefix + m.RemoteName + "/"
+	upstreamRefs, err := git.GetRefsByPrefix(ctx, repoPath, remotePrefix)
+	if err != nil {
+		return nil, err
+	}
+	localRefs, err := git.GetRefsByPrefix(ctx, repoPath, git.BranchPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, diverged, err := planPartialSync(m, mappings, remotePrefix, upstreamRefs, localRefs, func(ancestor, descendant string) (bool, error) {
+		return git.IsAncestor(ctx, repoPath, ancestor, descendant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return diverged, nil
+	}
+
+	pushRefspecs := make([]string, 0, len(updates))
+	for _, update := range updates {
+		refspec := update.upstreamRef + ":" + git.BranchPrefix + update.target
+		if update.force {
+			refspec = "+" + refspec
+		}
+		pushRefspecs = append(pushRefspecs, refspec)
+	}
+	// push the upstream commits to the repository itself so that the hooks check and record the updates
+	log.Trace("SyncPartialMirror [mirror: %d][repo: %-v]: updating %d branches...", m.ID, m.Repo, len(pushRefspecs))
+	if err := git.Push(ctx, repoPath, git.PushOptions{
+		Remote:   repoPath,
+		Refspecs: pushRefspecs,
+		Env:      repo_module.PushingEnvironment(doer, m.Repo),
+		Timeout:  timeout,
+	}); err != nil {
+		return diverged, err
+	}
+
+	if m.Divergence == repo_model.DivergencePullRequest {
+		for _, target := range diverged {
+			if err := openUpstreamPullRequest(ctx, doer, m, target); err != nil {
+				return diverged, fmt.Errorf("unable to open a pull request into %s: %w", target, err)
+			}
+		}
+	}
+	return diverged, nil
+}
+
+// planPartialSync returns the updates of the branches mapped from the fetched upstream branches and the branches
+// which have diverged from upstream. A branch which is ahead of upstream isn't updated.
+func planPartialSync(m *repo_model.PartialMirror, mappings []repo_model.BranchMapping, remotePrefix string, upstreamRefs, localRefs map[string]string, isAncestor func(ancestor, descendant string) (bool, error)) ([]*partialSyncUpdate, []string, error) {
+	upstreamNames := make([]string, 0, len(upstreamRefs))
+	for refName := range upstreamRefs {
+		upstreamNames = append(upstreamNames, refName)
+	}
+	sort.Strings(upstreamNames)
+
+	var updates []*partialSyncUpdate
+	var diverged []string
+	targets := make(container.Set[string])
+	for _, upstreamRef := range upstreamNames {
+		branch := strings.TrimPrefix(upstreamRef, remotePrefix)
+		var target string
+		for _, mapping := range mappings {
+			if t, ok := mapping.Match(branch); ok {
+				target = t
+				break
+			}
+		}
+		// a target branch is only updated from the first upstream branch mapped to it
+		if target == "" || !targets.Add(target) {
+			continue
+		}
+
+		upstreamID, localID := upstreamRefs[upstreamRef], localRefs[git.BranchPrefix+target]
+		switch {
+		case localID == upstreamID:
+			continue
+		case localID == "":
+			updates = append(updates, &partialSyncUpdate{upstreamRef: upstreamRef, target: target})
+			continue
+		}
+
+		fastForward, err := isAncestor(localID, upstreamID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fastForward {
+			updates = append(updates, &partialSyncUpdate{upstreamRef: upstreamRef, target: target})
+			continue
+		}
+		ahead, err := isAncestor(upstreamID, localID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ahead {
+			continue
+		}
+
+		diverged = append(diverged, target)
+		switch m.Divergence {
+		case repo_model.DivergenceForce:
+			updates = append(updates, &partialSyncUpdate{upstreamRef: upstreamRef, target: target, force: true})
+		case repo_model.DivergencePullRequest:
+			updates = append(updates, &partialSyncUpdate{upstreamRef: upstreamRef, target: target + upstreamSyncBranchSuffix, force: true})
+		}
+	}
+	return updates, diverged, nil
+}
+
+// openUpstreamPullRequest opens a pull request which merges the upstream changes of a diverged branch into it,
+// unless there is already one: it has been updated by the push of the upstream branch
+func openUpstreamPullRequest(ctx context.Context, doer *user_model.User, m *repo_model.PartialMirror, target string) error {
+	headBranch := target + upstreamSyncBranchSuffix
+	_, err := issues_model.GetUnmergedPullRequest(ctx, m.RepoID, m.RepoID, headBranch, target, issues_model.PullRequestFlowGithub)
+	if err == nil {
+		return nil
+	} else if !issues_model.IsErrPullRequestNotExist(err) {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, m.Repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+	mergeBase, _, err := gitRepo.GetMergeBase("", target, headBranch)
+	if err != nil {
+		return fmt.Errorf("GetMergeBase: %w", err)
+	}
+
+	issue := &issues_model.Issue{
+		RepoID:   m.RepoID,
+		Repo:     m.Repo,
+		Title:    fmt.Sprintf("Merge upstream changes into %s", target),
+		PosterID: doer.ID,
+		Poster:   doer,
+		IsPull:   true,
+		Content:  fmt.Sprintf("The branch `%s` has diverged from its upstream branch in %s. This pull request merges the upstream changes, which are mirrored to `%s`, into it.", target, m.RemoteAddress, headBranch),
+	}
+	pr := &issues_model.PullRequest{
+		HeadRepoID: m.RepoID,
+		BaseRepoID: m.RepoID,
+		HeadBranch: headBranch,
+		BaseBranch: target,
+		HeadRepo:   m.Repo,
+		BaseRepo:   m.Repo,
+		MergeBase:  mergeBase,
+		Type:       issues_model.PullRequestGitea,
+	}
+	return pull_service.NewPullRequest(ctx, m.Repo, issue, nil, nil, pr, nil)
+}
+
+// partialMirrorDoer returns the user who created the partial mirror, the branches are updated and the pull requests
+// opened on their behalf
+func partialMirrorDoer(ctx context.Context, m *repo_model.PartialMirror) (*user_model.User, error) {
+	doer, err := user_model.GetUserByID(ctx, m.CreatorID)
+	if err != nil {
+		return nil, fmt.Errorf("the creator of the partial mirror can't be loaded: %w", err)
+	}
+	return doer, nil
+}
+
+// notifyPartialMirrorDiverged emails the creator of the partial mirror and the owners of the repository about the
+// branches which have diverged from upstream
+func notifyPartialMirrorDiverged(ctx context.Context, m *repo_model.PartialMirror, branches []string) error {
+	ids := container.SetOf(m.CreatorID)
+	if err := m.Repo.LoadOwner(ctx); err != nil {
+		return err
+	}
+	if m.Repo.Owner.IsOrganization() {
+		team, err := organization.GetOwnerTeam(ctx, m.Repo.OwnerID)
+		if err != nil {
+			return err
+		}
+		teamUsers, err := organization.GetTeamUsersByTeamID(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+		for _, tu := range teamUsers {
+			ids.Add(tu.UID)
+		}
+	} else {
+		ids.Add(m.Repo.OwnerID)
+	}
+
+	recipients, err := user_model.GetMaileableUsersByIDs(ctx, ids.Values(), false)
+	if err != nil {
+		return err
+	}
+	return mailer.MailPartialMirrorDiverged(ctx, m, branches, recipients)
+}
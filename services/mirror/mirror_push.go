@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -111,6 +112,9 @@ func SyncPushMirror(ctx context.Context, mirrorID int64) bool {
 	if err != nil {
 		log.Error("SyncPushMirror [mirror: %d][repo: %-v]: %v", m.ID, m.Repo, err)
 		m.LastError = stripExitStatus.ReplaceAllLiteralString(err.Error(), "")
+		if err := repo_model.InsertPushMirrorFailure(ctx, m, m.LastError); err != nil {
+			log.Error("InsertPushMirrorFailure [%d]: %v", m.ID, err)
+		}
 	}
 
 	m.LastUpdateUnix = timeutil.TimeStampNow()
@@ -129,7 +133,8 @@ func SyncPushMirror(ctx context.Context, mirrorID int64) bool {
 func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
 
-	performPush := func(path string) error {
+	// matchRef is nil when all the refs are pushed
+	performPush := func(path string, matchRef func(string) bool) error {
 		remoteURL, err := git.GetRemoteURL(ctx, path, m.RemoteName)
 		if err != nil {
 			log.Error("GetRemoteAddress(%s) Error %v", path, err)
@@ -155,6 +160,18 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 
 		log.Trace("Pushing %s mirror[%d] remote %s", path, m.ID, m.RemoteName)
 
+		if matchRef != nil || m.NonDestructive {
+			if matchRef == nil {
+				matchRef = func(string) bool { return true }
+			}
+			if err := pushSelectedRefs(ctx, m, path, matchRef, timeout); err != nil {
+				log.Error("Error pushing %s mirror[%d] remote %s: %v", path, m.ID, m.RemoteName, err)
+
+				return util.SanitizeErrorCredentialURLs(err)
+			}
+			return nil
+		}
+
 		if err := git.Push(ctx, path, git.PushOptions{
 			Remote:  m.RemoteName,
 			Force:   true,
@@ -169,7 +186,11 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 		return nil
 	}
 
-	err := performPush(m.Repo.RepoPath())
+	var matchRef func(string) bool
+	if m.IsRefFiltered() {
+		matchRef = m.MatchRef
+	}
+	err := performPush(m.Repo.RepoPath(), matchRef)
 	if err != nil {
 		return err
 	}
@@ -178,7 +199,8 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 		wikiPath := m.Repo.WikiPath()
 		_, err := git.GetRemoteAddress(ctx, wikiPath, m.RemoteName)
 		if err == nil {
-			err := performPush(wikiPath)
+			// the ref filters only apply to the repository, the wiki is always pushed entirely
+			err := performPush(wikiPath, nil)
 			if err != nil {
 				return err
 			}
@@ -190,6 +212,60 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 	return nil
 }
 
+// pushSelectedRefs pushes the branches and tags matched by matchRef instead of mirroring all the refs. The remote
+// refs which aren't matched are left untouched.
+func pushSelectedRefs(ctx context.Context, m *repo_model.PushMirror, path string, matchRef func(string) bool, timeout time.Duration) error {
+	localRefs, err := git.GetBranchAndTagRefs(ctx, path)
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := git.GetRemoteRefs(ctx, path, m.RemoteName, timeout)
+	if err != nil {
+		return err
+	}
+
+	refspecs := pushMirrorRefspecs(localRefs, remoteRefs, matchRef, m.NonDestructive)
+	if len(refspecs) == 0 {
+		log.Trace("Push mirror[%d] remote %s is up to date", m.ID, m.RemoteName)
+		return nil
+	}
+
+	// the remote is configured as a mirror, which can't be combined with refspecs
+	return git.Push(ctx, path, git.PushOptions{
+		Remote:             m.RemoteName,
+		Refspecs:           refspecs,
+		Timeout:            timeout,
+		IgnoreRemoteMirror: true,
+	})
+}
+
+// pushMirrorRefspecs returns the refspecs which update the matched refs of the remote to the ones of the repository.
+// The matched remote refs which don't exist in the repository are deleted and the others are force-updated, unless
+// the mirror is non-destructive: then the remote refs are never deleted and git rejects the updates which aren't
+// fast-forwards.
+func pushMirrorRefspecs(localRefs, remoteRefs map[string]string, matchRef func(string) bool, nonDestructive bool) []string {
+	var refspecs []string
+	for refName, objectID := range localRefs {
+		if !matchRef(refName) || remoteRefs[refName] == objectID {
+			continue
+		}
+		if nonDestructive {
+			refspecs = append(refspecs, refName+":"+refName)
+		} else {
+			refspecs = append(refspecs, "+"+refName+":"+refName)
+		}
+	}
+	if !nonDestructive {
+		for refName := range remoteRefs {
+			if _, ok := localRefs[refName]; !ok && matchRef(refName) {
+				refspecs = append(refspecs, ":"+refName)
+			}
+		}
+	}
+	sort.Strings(refspecs)
+	return refspecs
+}
+
 func pushAllLFSObjects(ctx context.Context, gitRepo *git.Repository, lfsClient lfs.Client) error {
 	contentStore := lfs.NewContentStore()
 
@@ -6,6 +6,8 @@ package mirror
 import (
 	"testing"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,3 +46,106 @@ func Test_parseRemoteUpdateOutput(t *testing.T) {
 	assert.EqualValues(t, "957a993", results[5].oldCommitID)
 	assert.EqualValues(t, "a87ba5f", results[5].newCommitID)
 }
+
+func Test_pushMirrorRefspecs(t *testing.T) {
+	localRefs := map[string]string{
+		"refs/heads/main":     "1111",
+		"refs/heads/feature":  "2222",
+		"refs/heads/private":  "3333",
+		"refs/tags/v1.0":      "4444",
+		"refs/heads/uptodate": "5555",
+	}
+	remoteRefs := map[string]string{
+		"refs/heads/main":       "0000",
+		"refs/heads/downstream": "6666",
+		"refs/heads/deleted":    "7777",
+		"refs/heads/uptodate":   "5555",
+	}
+	matchRef := func(refName string) bool {
+		return refName != "refs/heads/private" && refName != "refs/heads/downstream"
+	}
+
+	assert.Equal(t, []string{
+		"+refs/heads/feature:refs/heads/feature",
+		"+refs/heads/main:refs/heads/main",
+		"+refs/tags/v1.0:refs/tags/v1.0",
+		":refs/heads/deleted",
+	}, pushMirrorRefspecs(localRefs, remoteRefs, matchRef, false))
+
+	assert.Equal(t, []string{
+		"refs/heads/feature:refs/heads/feature",
+		"refs/heads/main:refs/heads/main",
+		"refs/tags/v1.0:refs/tags/v1.0",
+	}, pushMirrorRefspecs(localRefs, remoteRefs, matchRef, true))
+
+	assert.Empty(t, pushMirrorRefspecs(localRefs, localRefs, matchRef, false))
+}
+
+func Test_planPartialSync(t *testing.T) {
+	mappings := []repo_model.BranchMapping{
+		{Source: "main", Target: "vendor/main"},
+		{Source: "release/*", Target: "vendor/release/*"},
+		{Source: "*", Target: "vendor/main"},
+	}
+	prefix := "refs/remotes/upstream/"
+	upstreamRefs := map[string]string{
+		prefix + "main":        "main-upstream",
+		prefix + "release/1.0": "same",
+		prefix + "release/2.0": "new",
+		prefix + "release/3.0": "ahead-upstream",
+		prefix + "release/4.0": "ff-upstream",
+		prefix + "release/5.0": "diverged-upstream",
+	}
+	localRefs := map[string]string{
+		"refs/heads/vendor/main":        "main-local",
+		"refs/heads/vendor/release/1.0": "same",
+		"refs/heads/vendor/release/3.0": "ahead-local",
+		"refs/heads/vendor/release/4.0": "ff-local",
+		"refs/heads/vendor/release/5.0": "diverged-local",
+	}
+	ancestors := map[string]string{
+		"ff-local":       "ff-upstream",
+		"ahead-upstream": "ahead-local",
+	}
+	isAncestor := func(ancestor, descendant string) (bool, error) {
+		return ancestors[ancestor] == descendant, nil
+	}
+
+	refspecs := func(updates []*partialSyncUpdate) []string {
+		var specs []string
+		for _, u := range updates {
+			spec := u.upstreamRef + ":" + u.target
+			if u.force {
+				spec = "+" + spec
+			}
+			specs = append(specs, spec)
+		}
+		return specs
+	}
+
+	cases := map[repo_model.DivergenceStrategy][]string{
+		repo_model.DivergenceSkip: nil,
+		repo_model.DivergenceForce: {
+			"+" + prefix + "main:vendor/main",
+			"+" + prefix + "release/5.0:vendor/release/5.0",
+		},
+		repo_model.DivergencePullRequest: {
+			"+" + prefix + "main:vendor/main-upstream",
+			"+" + prefix + "release/5.0:vendor/release/5.0-upstream",
+		},
+	}
+	for strategy, divergedSpecs := range cases {
+		updates, diverged, err := planPartialSync(&repo_model.PartialMirror{Divergence: strategy}, mappings, prefix, upstreamRefs, localRefs, isAncestor)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"vendor/main", "vendor/release/5.0"}, diverged, strategy)
+
+		expected := []string{
+			prefix + "release/2.0:vendor/release/2.0",
+			prefix + "release/4.0:vendor/release/4.0",
+		}
+		if len(divergedSpecs) > 0 {
+			expected = []string{divergedSpecs[0], expected[0], expected[1], divergedSpecs[1]}
+		}
+		assert.Equal(t, expected, refspecs(updates), strategy)
+	}
+}
@@ -20,12 +20,14 @@ const (
 	PullMirrorType SyncType = iota
 	// PushMirrorType for push mirrors
 	PushMirrorType
+	// PartialMirrorType for partial pull mirrors
+	PartialMirrorType
 )
 
 // SyncRequest for the mirror queue
 type SyncRequest struct {
 	Type        SyncType
-	ReferenceID int64 // RepoID for pull mirror, MirrorID for push mirror
+	ReferenceID int64 // RepoID for pull mirror, MirrorID for push and partial mirror
 }
 
 // StartSyncMirrors starts a go routine to sync the mirrors
@@ -50,6 +52,11 @@ func AddPushMirrorToQueue(mirrorID int64) {
 	addMirrorToQueue(PushMirrorType, mirrorID)
 }
 
+// AddPartialMirrorToQueue adds the partial mirror to the queue
+func AddPartialMirrorToQueue(mirrorID int64) {
+	addMirrorToQueue(PartialMirrorType, mirrorID)
+}
+
 func addMirrorToQueue(syncType SyncType, referenceID int64) {
 	if !setting.Mirror.Enabled {
 		return
@@ -6,7 +6,11 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
@@ -31,6 +35,9 @@ type Notifier interface {
 	IssueChangeStatus(ctx context.Context, doer *user_model.User, commitID string, issue *issues_model.Issue, actionComment *issues_model.Comment, closeOrReopen bool)
 	DeleteIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Issue)
 	IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64)
+	IssueChangeProject(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldProjectID int64)
+	IssueChangeParent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldParentID int64)
+	IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64)
 	IssueChangeAssignee(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, assignee *user_model.User, removed bool, comment *issues_model.Comment)
 	PullRequestReviewRequest(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, reviewer *user_model.User, isRequest bool, comment *issues_model.Comment)
 	IssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string)
@@ -74,4 +81,16 @@ type Notifier interface {
 	PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor)
 
 	ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository)
+
+	WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun)
+	WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob)
+
+	AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User)
+	RemoveCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User)
+	AddTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User)
+	RemoveTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User)
+	AddDeployKey(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, key *asymkey_model.DeployKey)
+	ChangeRepositoryVisibility(ctx context.Context, doer *user_model.User, repo *repo_model.Repository)
+	UpdateProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch, isNew bool)
+	DeleteProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch)
 }
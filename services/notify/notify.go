@@ -6,7 +6,11 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
@@ -198,6 +202,27 @@ func IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *iss
 	}
 }
 
+// IssueChangeProject notifies change project to notifiers
+func IssueChangeProject(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldProjectID int64) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeProject(ctx, doer, issue, oldProjectID)
+	}
+}
+
+// IssueChangeParent notifies change parent to notifiers
+func IssueChangeParent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldParentID int64) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeParent(ctx, doer, issue, oldParentID)
+	}
+}
+
+// IssueChangeType notifies change type or type field values to notifiers
+func IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeType(ctx, doer, issue, oldTypeID)
+	}
+}
+
 // IssueChangeContent notifies change content to notifiers
 func IssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 	for _, notifier := range notifiers {
@@ -367,3 +392,73 @@ func ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 		notifier.ChangeDefaultBranch(ctx, repo)
 	}
 }
+
+// WorkflowRunStatusUpdate notifies a status change of an Actions run to notifiers
+func WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	for _, notifier := range notifiers {
+		notifier.WorkflowRunStatusUpdate(ctx, repo, sender, run)
+	}
+}
+
+// WorkflowJobStatusUpdate notifies a status change of a job of an Actions run to notifiers
+func WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob) {
+	for _, notifier := range notifiers {
+		notifier.WorkflowJobStatusUpdate(ctx, repo, sender, job)
+	}
+}
+
+// AddCollaborator notifies the addition of a collaborator to a repository to notifiers
+func AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+	for _, notifier := range notifiers {
+		notifier.AddCollaborator(ctx, doer, repo, collaborator)
+	}
+}
+
+// RemoveCollaborator notifies the removal of a collaborator from a repository to notifiers
+func RemoveCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+	for _, notifier := range notifiers {
+		notifier.RemoveCollaborator(ctx, doer, repo, collaborator)
+	}
+}
+
+// AddTeamMember notifies the addition of a member to a team to notifiers
+func AddTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+	for _, notifier := range notifiers {
+		notifier.AddTeamMember(ctx, doer, team, member)
+	}
+}
+
+// RemoveTeamMember notifies the removal of a member from a team to notifiers
+func RemoveTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+	for _, notifier := range notifiers {
+		notifier.RemoveTeamMember(ctx, doer, team, member)
+	}
+}
+
+// AddDeployKey notifies the addition of a deploy key to a repository to notifiers
+func AddDeployKey(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, key *asymkey_model.DeployKey) {
+	for _, notifier := range notifiers {
+		notifier.AddDeployKey(ctx, doer, repo, key)
+	}
+}
+
+// ChangeRepositoryVisibility notifies a visibility change of a repository to notifiers
+func ChangeRepositoryVisibility(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) {
+	for _, notifier := range notifiers {
+		notifier.ChangeRepositoryVisibility(ctx, doer, repo)
+	}
+}
+
+// UpdateProtectedBranch notifies the creation or the update of a protected branch rule to notifiers
+func UpdateProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch, isNew bool) {
+	for _, notifier := range notifiers {
+		notifier.UpdateProtectedBranch(ctx, doer, repo, rule, isNew)
+	}
+}
+
+// DeleteProtectedBranch notifies the deletion of a protected branch rule to notifiers
+func DeleteProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch) {
+	for _, notifier := range notifiers {
+		notifier.DeleteProtectedBranch(ctx, doer, repo, rule)
+	}
+}
@@ -6,7 +6,11 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
@@ -112,6 +116,18 @@ func (*NullNotifier) DeleteRelease(ctx context.Context, doer *user_model.User, r
 func (*NullNotifier) IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) {
 }
 
+// IssueChangeProject places a place holder function
+func (*NullNotifier) IssueChangeProject(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldProjectID int64) {
+}
+
+// IssueChangeParent places a place holder function
+func (*NullNotifier) IssueChangeParent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldParentID int64) {
+}
+
+// IssueChangeType places a place holder function
+func (*NullNotifier) IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+}
+
 // IssueChangeContent places a place holder function
 func (*NullNotifier) IssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 }
@@ -208,3 +224,43 @@ func (*NullNotifier) PackageDelete(ctx context.Context, doer *user_model.User, p
 // ChangeDefaultBranch places a place holder function
 func (*NullNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 }
+
+// WorkflowRunStatusUpdate places a place holder function
+func (*NullNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+}
+
+// WorkflowJobStatusUpdate places a place holder function
+func (*NullNotifier) WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob) {
+}
+
+// AddCollaborator places a place holder function
+func (*NullNotifier) AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+}
+
+// RemoveCollaborator places a place holder function
+func (*NullNotifier) RemoveCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+}
+
+// AddTeamMember places a place holder function
+func (*NullNotifier) AddTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+}
+
+// RemoveTeamMember places a place holder function
+func (*NullNotifier) RemoveTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+}
+
+// AddDeployKey places a place holder function
+func (*NullNotifier) AddDeployKey(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, key *asymkey_model.DeployKey) {
+}
+
+// ChangeRepositoryVisibility places a place holder function
+func (*NullNotifier) ChangeRepositoryVisibility(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) {
+}
+
+// UpdateProtectedBranch places a place holder function
+func (*NullNotifier) UpdateProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch, isNew bool) {
+}
+
+// DeleteProtectedBranch places a place holder function
+func (*NullNotifier) DeleteProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch) {
+}
@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	org_model "code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -48,6 +49,18 @@ func DeleteOrganization(ctx context.Context, org *org_model.Organization, purge
 		return models.ErrUserOwnPackages{UID: org.ID}
 	}
 
+	if err := issues_model.DeleteMilestonesByOrgID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteMilestonesByOrgID: %w", err)
+	}
+
+	if err := issues_model.DeleteIssueTypesByOrgID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteIssueTypesByOrgID: %w", err)
+	}
+
+	if err := issues_model.DeleteSavedSearchesByOwnerID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteSavedSearchesByOwnerID: %w", err)
+	}
+
 	if err := org_model.DeleteOrganization(ctx, org); err != nil {
 		return fmt.Errorf("DeleteOrganization: %w", err)
 	}
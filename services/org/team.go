@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// AddTeamMember adds the user to the team and notifies it if the user wasn't a member yet.
+func AddTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, userID int64) error {
+	isMember, err := organization.IsTeamMember(ctx, team.OrgID, team.ID, userID)
+	if err != nil || isMember {
+		return err
+	}
+	if err := models.AddTeamMember(ctx, team, userID); err != nil {
+		return err
+	}
+
+	member, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	notify_service.AddTeamMember(ctx, doer, team, member)
+	return nil
+}
+
+// RemoveTeamMember removes the user from the team and notifies it if the user was a member.
+func RemoveTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, userID int64) error {
+	isMember, err := organization.IsTeamMember(ctx, team.OrgID, team.ID, userID)
+	if err != nil {
+		return err
+	}
+	if err := models.RemoveTeamMember(ctx, team, userID); err != nil || !isMember {
+		return err
+	}
+
+	member, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	notify_service.RemoveTeamMember(ctx, doer, team, member)
+	return nil
+}
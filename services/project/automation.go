@@ -0,0 +1,76 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// CanUseLabel returns true if the automations of the project can add the label to its issues,
+// the label must belong to the repository of the project or to its owner
+func CanUseLabel(project *project_model.Project, label *issues_model.Label) bool {
+	if label.BelongsToRepo() {
+		return project.RepoID > 0 && label.RepoID == project.RepoID
+	}
+	return label.OrgID == project.OwnerID || (project.RepoID > 0 && project.Repo != nil && label.OrgID == project.Repo.OwnerID)
+}
+
+// RunAutomations applies the automations of the project of the issue triggered by the events, in order
+func RunAutomations(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, events ...project_model.AutomationEvent) error {
+	if err := issue.LoadProject(ctx); err != nil {
+		return err
+	}
+	if issue.Project == nil {
+		return nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		automations, err := project_model.FindAutomations(ctx, []int64{issue.Project.ID}, event)
+		if err != nil {
+			return err
+		}
+		for _, automation := range automations {
+			if err := applyAutomation(ctx, doer, issue, automation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyAutomation(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, automation *project_model.Automation) error {
+	if automation.BoardID > 0 && issue.ProjectBoardID() != automation.BoardID {
+		board, err := project_model.GetBoard(ctx, automation.BoardID)
+		if err != nil {
+			return err
+		}
+		if err := issues_model.MoveIssueAcrossProjectBoards(issue, board); err != nil {
+			return err
+		}
+	}
+
+	if automation.LabelID > 0 {
+		label, err := issues_model.GetLabelByID(ctx, automation.LabelID)
+		if err != nil {
+			return err
+		}
+		// the labels of the organization of an organization project only apply to the issues of its repositories
+		if (label.BelongsToRepo() && label.RepoID != issue.RepoID) || (!label.BelongsToRepo() && label.OrgID != issue.Repo.OwnerID) {
+			return nil
+		}
+		if issues_model.HasIssueLabel(ctx, issue.ID, label.ID) {
+			return nil
+		}
+		return issue_service.AddLabel(ctx, issue, doer, label)
+	}
+	return nil
+}
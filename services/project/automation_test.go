@@ -0,0 +1,48 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAutomations(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	// the closed issues of the project 1 are moved to its "Done" column
+	assert.NoError(t, RunAutomations(db.DefaultContext, doer, issue, project_model.AutomationEventItemClosed))
+	assert.EqualValues(t, 3, issue.ProjectBoardID())
+	assert.False(t, issues_model.HasIssueLabel(db.DefaultContext, issue.ID, 2))
+
+	// the reopened ones are moved to "In Progress" and labeled
+	assert.NoError(t, RunAutomations(db.DefaultContext, doer, issue, project_model.AutomationEventItemReopened))
+	assert.EqualValues(t, 2, issue.ProjectBoardID())
+	assert.True(t, issues_model.HasIssueLabel(db.DefaultContext, issue.ID, 2))
+
+	// nothing happens to the issues without a project
+	other := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+	assert.NoError(t, RunAutomations(db.DefaultContext, doer, other, project_model.AutomationEventItemReopened))
+	assert.False(t, issues_model.HasIssueLabel(db.DefaultContext, other.ID, 2))
+}
+
+func TestCanUseLabel(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	project := unittest.AssertExistsAndLoadBean(t, &project_model.Project{ID: 1})
+	assert.NoError(t, project.LoadRepo(db.DefaultContext))
+	assert.True(t, CanUseLabel(project, unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2})))
+	// a label of an organization not owning the repository
+	assert.False(t, CanUseLabel(project, unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 3})))
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// Init registers the notifier running the automations of the projects
+func Init() error {
+	notify_service.RegisterNotifier(NewNotifier())
+
+	return nil
+}
+
+type automationNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &automationNotifier{}
+
+// NewNotifier creates a new notifier running the automations of the projects
+func NewNotifier() notify_service.Notifier {
+	return &automationNotifier{}
+}
+
+func runAutomations(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, events ...project_model.AutomationEvent) {
+	if err := RunAutomations(ctx, doer, issue, events...); err != nil {
+		log.Error("RunAutomations [issue: %d]: %v", issue.ID, err)
+	}
+}
+
+func (*automationNotifier) IssueChangeProject(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldProjectID int64) {
+	if err := issue.LoadProject(ctx); err != nil {
+		log.Error("LoadProject [issue: %d]: %v", issue.ID, err)
+		return
+	}
+	if issue.Project == nil || issue.Project.ID == oldProjectID {
+		return
+	}
+	if issue.IsPull && !issue.IsClosed {
+		runAutomations(ctx, doer, issue, project_model.AutomationEventItemAdded, project_model.AutomationEventPullRequestOpened)
+		return
+	}
+	runAutomations(ctx, doer, issue, project_model.AutomationEventItemAdded)
+}
+
+func (*automationNotifier) NewPullRequest(ctx context.Context, pr *issues_model.PullRequest, _ []*user_model.User) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue [pr: %d]: %v", pr.ID, err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(ctx); err != nil {
+		log.Error("LoadPoster [issue: %d]: %v", pr.Issue.ID, err)
+		return
+	}
+	runAutomations(ctx, pr.Issue.Poster, pr.Issue, project_model.AutomationEventPullRequestOpened)
+}
+
+func (*automationNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, _ string, issue *issues_model.Issue, _ *issues_model.Comment, isClosed bool) {
+	if isClosed {
+		runAutomations(ctx, doer, issue, project_model.AutomationEventItemClosed)
+	} else {
+		runAutomations(ctx, doer, issue, project_model.AutomationEventItemReopened)
+	}
+}
+
+func (n *automationNotifier) AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	n.MergePullRequest(ctx, doer, pr)
+}
+
+// MergePullRequest runs the automations of the closed items before the ones of the merged pull requests,
+// a merged pull request is closed without a change of status being notified
+func (*automationNotifier) MergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue [pr: %d]: %v", pr.ID, err)
+		return
+	}
+	runAutomations(ctx, doer, pr.Issue, project_model.AutomationEventItemClosed, project_model.AutomationEventPullRequestMerged)
+}
+
+func (*automationNotifier) PullRequestReviewRequest(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, _ *user_model.User, isRequest bool, _ *issues_model.Comment) {
+	if isRequest {
+		runAutomations(ctx, doer, issue, project_model.AutomationEventReviewRequested)
+	}
+}
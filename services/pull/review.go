@@ -6,13 +6,16 @@ package pull
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
@@ -24,6 +27,9 @@ import (
 
 var notEnoughLines = regexp.MustCompile(`fatal: file .* has only \d+ lines?`)
 
+// ErrInvalidCommentLineRange represents a multi-line code comment whose start line doesn't match its end line
+var ErrInvalidCommentLineRange = errors.New("start line must be on the same side as and not after the end line")
+
 // checkInvalidation checks if the line of code comment got changed by another commit.
 // If the line got changed the comment is going to be invalidated.
 func checkInvalidation(ctx context.Context, c *issues_model.Comment, doer *user_model.User, repo *git.Repository, branch string) error {
@@ -69,13 +75,21 @@ func InvalidateCodeComments(ctx context.Context, prs issues_model.PullRequestLis
 	return nil
 }
 
-// CreateCodeComment creates a comment on the code line
-func CreateCodeComment(ctx context.Context, doer *user_model.User, gitRepo *git.Repository, issue *issues_model.Issue, line int64, content, treePath string, pendingReview bool, replyReviewID int64, latestCommitID string) (*issues_model.Comment, error) {
+// CreateCodeComment creates a comment on the code line.
+// If startLine is not zero the comment spans the lines from startLine to line, which must be on the same side of the diff.
+func CreateCodeComment(ctx context.Context, doer *user_model.User, gitRepo *git.Repository, issue *issues_model.Issue, startLine, line int64, content, treePath string, pendingReview bool, replyReviewID int64, latestCommitID string) (*issues_model.Comment, error) {
 	var (
 		existsReview bool
 		err          error
 	)
 
+	if startLine == line {
+		startLine = 0
+	}
+	if startLine != 0 && ((startLine < 0) != (line < 0) || (line > 0 && startLine > line) || (line < 0 && startLine < line)) {
+		return nil, ErrInvalidCommentLineRange
+	}
+
 	// CreateCodeComment() is used for:
 	// - Single comments
 	// - Comments that are part of a review
@@ -101,6 +115,7 @@ func CreateCodeComment(ctx context.Context, doer *user_model.User, gitRepo *git.
 			issue,
 			content,
 			treePath,
+			startLine,
 			line,
 			replyReviewID,
 		)
@@ -141,6 +156,7 @@ func CreateCodeComment(ctx context.Context, doer *user_model.User, gitRepo *git.
 		issue,
 		content,
 		treePath,
+		startLine,
 		line,
 		review.ID,
 	)
@@ -160,8 +176,8 @@ func CreateCodeComment(ctx context.Context, doer *user_model.User, gitRepo *git.
 	return comment, nil
 }
 
-// createCodeComment creates a plain code comment at the specified line / path
-func createCodeComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content, treePath string, line, reviewID int64) (*issues_model.Comment, error) {
+// createCodeComment creates a plain code comment at the specified line range / path
+func createCodeComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content, treePath string, startLine, line, reviewID int64) (*issues_model.Comment, error) {
 	var commitID, patch string
 	if err := issue.LoadPullRequest(ctx); err != nil {
 		return nil, fmt.Errorf("LoadPullRequest: %w", err)
@@ -241,27 +257,64 @@ func createCodeComment(ctx context.Context, doer *user_model.User, repo *repo_mo
 			_ = writer.Close()
 		}()
 
-		patch, err = git.CutDiffAroundLine(reader, int64((&issues_model.Comment{Line: line}).UnsignedLine()), line < 0, setting.UI.CodeCommentLines)
+		// make sure the patch shows all the commented lines
+		numberOfLines := setting.UI.CodeCommentLines
+		if startLine != 0 {
+			numberOfLines = max(numberOfLines, int(max(line-startLine, startLine-line))+1)
+		}
+		patch, err = git.CutDiffAroundLine(reader, int64((&issues_model.Comment{Line: line}).UnsignedLine()), line < 0, numberOfLines)
 		if err != nil {
 			log.Error("Error whilst generating patch: %v", err)
 			return nil, err
 		}
 	}
 	return issues_model.CreateComment(ctx, &issues_model.CreateCommentOptions{
-		Type:        issues_model.CommentTypeCode,
-		Doer:        doer,
-		Repo:        repo,
-		Issue:       issue,
-		Content:     content,
-		LineNum:     line,
-		TreePath:    treePath,
-		CommitSHA:   commitID,
-		ReviewID:    reviewID,
-		Patch:       patch,
-		Invalidated: invalidated,
+		Type:         issues_model.CommentTypeCode,
+		Doer:         doer,
+		Repo:         repo,
+		Issue:        issue,
+		Content:      content,
+		LineNum:      line,
+		StartLineNum: startLine,
+		TreePath:     treePath,
+		CommitSHA:    commitID,
+		ReviewID:     reviewID,
+		Patch:        patch,
+		Invalidated:  invalidated,
 	})
 }
 
+// IsUserAllowedToApplySuggestions returns true if the user can commit suggested changes to the head branch of the pull request
+func IsUserAllowedToApplySuggestions(ctx context.Context, pull *issues_model.PullRequest, user *user_model.User) (bool, error) {
+	if user == nil || pull.HasMerged || pull.Flow == issues_model.PullRequestFlowAGit {
+		return false, nil
+	}
+	if err := pull.LoadHeadRepo(ctx); err != nil {
+		return false, err
+	}
+	if pull.HeadRepo == nil {
+		return false, nil
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, pull.HeadRepo, user)
+	if err != nil {
+		return false, err
+	}
+	if !issues_model.CanMaintainerWriteToBranch(ctx, perm, pull.HeadBranch, user) {
+		return false, nil
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pull.HeadRepoID, pull.HeadBranch)
+	if err != nil {
+		return false, err
+	}
+	if pb != nil {
+		pb.Repo = pull.HeadRepo
+		return pb.CanUserPush(ctx, user), nil
+	}
+	return true, nil
+}
+
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
 func SubmitReview(ctx context.Context, doer *user_model.User, gitRepo *git.Repository, issue *issues_model.Issue, reviewType issues_model.ReviewType, content, commitID string, attachmentUUIDs []string) (*issues_model.Review, *issues_model.Comment, error) {
 	pr, err := issue.GetPullRequest()
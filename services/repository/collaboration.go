@@ -11,39 +11,60 @@ import (
 	"code.gitea.io/gitea/models/db"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
 )
 
+// AddCollaborator adds the user as a collaborator of the repository and notifies it if the user wasn't one yet.
+func AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, u *user_model.User) error {
+	isCollaborator, err := repo_model.IsCollaborator(ctx, repo.ID, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := repo_module.AddCollaborator(ctx, repo, u); err != nil {
+		return err
+	}
+	if !isCollaborator {
+		notify_service.AddCollaborator(ctx, doer, repo, u)
+	}
+	return nil
+}
+
 // DeleteCollaboration removes collaboration relation between the user and repository.
-func DeleteCollaboration(ctx context.Context, repo *repo_model.Repository, uid int64) (err error) {
+func DeleteCollaboration(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, uid int64) (err error) {
 	collaboration := &repo_model.Collaboration{
 		RepoID: repo.ID,
 		UserID: uid,
 	}
 
-	ctx, committer, err := db.TxContext(ctx)
-	if err != nil {
-		return err
-	}
-	defer committer.Close()
+	deleted := false
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if has, err := db.GetEngine(ctx).Delete(collaboration); err != nil || has == 0 {
+			return err
+		} else if err = access_model.RecalculateAccesses(ctx, repo); err != nil {
+			return err
+		}
+		deleted = true
 
-	if has, err := db.GetEngine(ctx).Delete(collaboration); err != nil || has == 0 {
-		return err
-	} else if err = access_model.RecalculateAccesses(ctx, repo); err != nil {
-		return err
-	}
+		if err := repo_model.WatchRepo(ctx, uid, repo.ID, false); err != nil {
+			return err
+		}
 
-	if err = repo_model.WatchRepo(ctx, uid, repo.ID, false); err != nil {
-		return err
-	}
+		if err := models.ReconsiderWatches(ctx, repo, uid); err != nil {
+			return err
+		}
 
-	if err = models.ReconsiderWatches(ctx, repo, uid); err != nil {
+		// Unassign a user from any issue (s)he has been assigned to in the repository
+		return models.ReconsiderRepoIssuesAssignee(ctx, repo, uid)
+	}); err != nil || !deleted {
 		return err
 	}
 
-	// Unassign a user from any issue (s)he has been assigned to in the repository
-	if err := models.ReconsiderRepoIssuesAssignee(ctx, repo, uid); err != nil {
+	collaborator, err := user_model.GetUserByID(ctx, uid)
+	if err != nil {
 		return err
 	}
-
-	return committer.Commit()
+	notify_service.RemoveCollaborator(ctx, doer, repo, collaborator)
+	return nil
 }
@@ -142,11 +142,19 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, uid, r
 		&git_model.LFSLock{RepoID: repoID},
 		&repo_model.LanguageStat{RepoID: repoID},
 		&issues_model.Milestone{RepoID: repoID},
+		&issues_model.SLAPolicy{RepoID: repoID},
+		&issues_model.IssueSLA{RepoID: repoID},
 		&repo_model.Mirror{RepoID: repoID},
 		&activities_model.Notification{RepoID: repoID},
+		&activities_model.NotificationPreference{RepoID: repoID},
+		&activities_model.NotificationDigestItem{RepoID: repoID},
 		&git_model.ProtectedBranch{RepoID: repoID},
 		&git_model.ProtectedTag{RepoID: repoID},
 		&repo_model.PushMirror{RepoID: repoID},
+		&repo_model.PushMirrorFailure{RepoID: repoID},
+		&repo_model.PartialMirror{RepoID: repoID},
+		&repo_model.MigrationSync{RepoID: repoID},
+		&repo_model.MigrationSyncRef{RepoID: repoID},
 		&repo_model.Release{RepoID: repoID},
 		&repo_model.RepoIndexerStatus{RepoID: repoID},
 		&repo_model.Redirect{RedirectRepoID: repoID},
@@ -0,0 +1,259 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrSuggestionNotApplicable represents a code comment whose suggestion can't be applied to the head branch
+type ErrSuggestionNotApplicable struct {
+	CommentID int64
+	Reason    string
+}
+
+// IsErrSuggestionNotApplicable checks if an error is a ErrSuggestionNotApplicable.
+func IsErrSuggestionNotApplicable(err error) bool {
+	_, ok := err.(ErrSuggestionNotApplicable)
+	return ok
+}
+
+func (err ErrSuggestionNotApplicable) Error() string {
+	return fmt.Sprintf("suggestion can not be applied [comment_id: %d, reason: %s]", err.CommentID, err.Reason)
+}
+
+func (err ErrSuggestionNotApplicable) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// suggestion is a replacement of the lines [StartLine, EndLine] of a file
+type suggestion struct {
+	CommentID int64
+	StartLine int
+	EndLine   int
+	Lines     []string
+}
+
+// ApplySuggestions commits the changes suggested by the given code comments to the head branch of the pull request.
+// All the suggestions are applied in a single commit and the conversations are marked as resolved afterwards.
+func ApplySuggestions(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, comments []*issues_model.Comment, message string) (*structs.FilesResponse, error) {
+	if len(comments) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no suggestion to apply")
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil, util.NewInvalidArgumentErrorf("pull request is closed")
+	}
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, err
+	}
+	if pr.HeadRepo == nil {
+		return nil, util.NewNotExistErrorf("head repository of pull request %d does not exist", pr.ID)
+	}
+
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, pr.HeadRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	headCommit, err := gitRepo.GetBranchCommit(pr.HeadBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make(map[string][]suggestion)
+	coAuthors := make(map[int64]*user_model.User)
+	for _, comment := range comments {
+		s, err := suggestionFromComment(ctx, pr, headCommit, comment)
+		if err != nil {
+			return nil, err
+		}
+		suggestions[comment.TreePath] = append(suggestions[comment.TreePath], s)
+		if comment.PosterID != doer.ID && comment.Poster != nil && !comment.Poster.IsGhost() {
+			coAuthors[comment.PosterID] = comment.Poster
+		}
+	}
+
+	opts := &ChangeRepoFilesOptions{
+		LastCommitID: headCommit.ID.String(),
+		OldBranch:    pr.HeadBranch,
+		NewBranch:    pr.HeadBranch,
+		Message:      suggestionCommitMessage(message, len(comments), coAuthors),
+	}
+	for treePath, fileSuggestions := range suggestions {
+		entry, err := headCommit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			return nil, err
+		}
+		content, err := entry.Blob().GetBlobContent(setting.UI.MaxDisplayFileSize)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(content)) >= setting.UI.MaxDisplayFileSize {
+			return nil, ErrSuggestionNotApplicable{CommentID: fileSuggestions[0].CommentID, Reason: "file is too large"}
+		}
+		if p, _ := lfs.ReadPointerFromBuffer([]byte(content)); p.IsValid() {
+			return nil, ErrSuggestionNotApplicable{CommentID: fileSuggestions[0].CommentID, Reason: "file is stored in LFS"}
+		}
+		newContent, err := applySuggestionsToContent(content, fileSuggestions)
+		if err != nil {
+			return nil, err
+		}
+		opts.Files = append(opts.Files, &ChangeRepoFile{
+			Operation:     "update",
+			TreePath:      treePath,
+			ContentReader: strings.NewReader(newContent),
+			SHA:           entry.ID.String(),
+		})
+	}
+	// keep the order of the files stable so the commit doesn't depend on map iteration
+	sort.Slice(opts.Files, func(i, j int) bool {
+		return opts.Files[i].TreePath < opts.Files[j].TreePath
+	})
+
+	resp, err := ChangeRepoFiles(ctx, pr.HeadRepo, doer, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		if err := issues_model.MarkConversation(ctx, comment, doer, true); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// suggestionFromComment validates that the suggestion of a code comment still applies to the head commit
+func suggestionFromComment(ctx context.Context, pr *issues_model.PullRequest, headCommit *git.Commit, comment *issues_model.Comment) (suggestion, error) {
+	notApplicable := func(reason string) (suggestion, error) {
+		return suggestion{}, ErrSuggestionNotApplicable{CommentID: comment.ID, Reason: reason}
+	}
+
+	if comment.Type != issues_model.CommentTypeCode || comment.IssueID != pr.IssueID {
+		return notApplicable("not a code comment of this pull request")
+	}
+	lines, ok := comment.Suggestion()
+	if !ok {
+		return notApplicable("comment has no suggestion")
+	}
+	if comment.Line <= 0 {
+		return notApplicable("suggestions can only be applied to proposed lines")
+	}
+	if comment.Invalidated {
+		return notApplicable("comment is outdated")
+	}
+	if comment.IsResolved() {
+		return notApplicable("conversation is already resolved")
+	}
+	if err := comment.LoadReview(ctx); err != nil && !issues_model.IsErrReviewNotExist(err) {
+		return suggestion{}, err
+	}
+	if comment.Review == nil || comment.Review.Type == issues_model.ReviewTypePending {
+		return notApplicable("review is still pending")
+	}
+	if err := comment.LoadPoster(ctx); err != nil {
+		return suggestion{}, err
+	}
+
+	// the lines are relative to the commit the review was made on, make sure they haven't moved since
+	if comment.Review.CommitID != "" && comment.Review.CommitID != headCommit.ID.String() {
+		changed, err := headCommit.FileChangedSinceCommit(comment.TreePath, comment.Review.CommitID)
+		if err != nil {
+			return suggestion{}, err
+		}
+		if changed {
+			return notApplicable("file has changed since the review")
+		}
+	}
+
+	return suggestion{
+		CommentID: comment.ID,
+		StartLine: int(comment.UnsignedStartLine()),
+		EndLine:   int(comment.UnsignedLine()),
+		Lines:     lines,
+	}, nil
+}
+
+// applySuggestionsToContent replaces the suggested line ranges of a file content, keeping its line endings
+func applySuggestionsToContent(content string, suggestions []suggestion) (string, error) {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(content, eol)
+	trailingEOL := len(lines) > 1 && lines[len(lines)-1] == ""
+	if trailingEOL {
+		lines = lines[:len(lines)-1]
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].StartLine < suggestions[j].StartLine
+	})
+	for i, s := range suggestions {
+		if s.StartLine < 1 || s.StartLine > s.EndLine || s.EndLine > len(lines) {
+			return "", ErrSuggestionNotApplicable{CommentID: s.CommentID, Reason: "lines are out of range"}
+		}
+		if i > 0 && s.StartLine <= suggestions[i-1].EndLine {
+			return "", ErrSuggestionNotApplicable{CommentID: s.CommentID, Reason: "lines overlap with another suggestion"}
+		}
+	}
+
+	result := make([]string, 0, len(lines))
+	last := 0
+	for _, s := range suggestions {
+		result = append(result, lines[last:s.StartLine-1]...)
+		result = append(result, s.Lines...)
+		last = s.EndLine
+	}
+	result = append(result, lines[last:]...)
+
+	newContent := strings.Join(result, eol)
+	if trailingEOL && len(result) > 0 {
+		newContent += eol
+	}
+	return newContent, nil
+}
+
+func suggestionCommitMessage(message string, count int, coAuthors map[int64]*user_model.User) string {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		if count == 1 {
+			message = "Apply suggestion from code review"
+		} else {
+			message = "Apply suggestions from code review"
+		}
+	}
+
+	if len(coAuthors) == 0 {
+		return message
+	}
+	ids := make([]int64, 0, len(coAuthors))
+	for id := range coAuthors {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var sb strings.Builder
+	sb.WriteString(message)
+	sb.WriteString("\n\n")
+	for _, id := range ids {
+		fmt.Fprintf(&sb, "Co-authored-by: %s <%s>\n", coAuthors[id].GetDisplayName(), coAuthors[id].GetEmail())
+	}
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySuggestionsToContent(t *testing.T) {
+	content := "line 1\nline 2\nline 3\nline 4\nline 5\n"
+
+	newContent, err := applySuggestionsToContent(content, []suggestion{
+		{CommentID: 2, StartLine: 4, EndLine: 5, Lines: []string{"line 4 and 5"}},
+		{CommentID: 1, StartLine: 2, EndLine: 2, Lines: []string{"line 2a", "line 2b"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2a\nline 2b\nline 3\nline 4 and 5\n", newContent)
+
+	newContent, err = applySuggestionsToContent("a\r\nb\r\nc", []suggestion{
+		{CommentID: 1, StartLine: 2, EndLine: 2, Lines: nil},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a\r\nc", newContent)
+
+	_, err = applySuggestionsToContent(content, []suggestion{
+		{CommentID: 1, StartLine: 1, EndLine: 3, Lines: []string{"x"}},
+		{CommentID: 2, StartLine: 3, EndLine: 4, Lines: []string{"y"}},
+	})
+	assert.True(t, IsErrSuggestionNotApplicable(err))
+
+	_, err = applySuggestionsToContent(content, []suggestion{
+		{CommentID: 1, StartLine: 5, EndLine: 6, Lines: []string{"x"}},
+	})
+	assert.True(t, IsErrSuggestionNotApplicable(err))
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sla
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+const escalationBatchSize = 50
+
+// EscalateOverdueSLAs marks the issues which missed a deadline of their SLA as breached and notifies the users
+// of their policies, each deadline is escalated once
+func EscalateOverdueSLAs(ctx context.Context) error {
+	now := timeutil.TimeStampNow()
+	for {
+		slas, err := issues_model.FindOverdueIssueSLAs(ctx, now, escalationBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(slas) == 0 {
+			return nil
+		}
+		for _, s := range slas {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before escalating the sla of issue %d", s.IssueID)
+			default:
+			}
+			if err := escalate(ctx, s, now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func escalate(ctx context.Context, s *issues_model.IssueSLA, now timeutil.TimeStamp) error {
+	issue, err := issues_model.GetIssueByID(ctx, s.IssueID)
+	if issues_model.IsErrIssueNotExist(err) {
+		return issues_model.DeleteIssueSLA(ctx, s.IssueID)
+	} else if err != nil {
+		return err
+	}
+
+	firstResponse := s.IsPendingFirstResponse() && s.FirstResponseDeadline < now && !s.FirstResponseEscalated
+	resolve := s.IsPendingResolve() && s.ResolveDeadline < now && !s.ResolveEscalated
+	s.FirstResponseEscalated = s.FirstResponseEscalated || firstResponse
+	s.ResolveEscalated = s.ResolveEscalated || resolve
+	oldState := s.State
+	s.UpdateState(now)
+	if err := issues_model.SaveIssueSLA(ctx, s); err != nil {
+		return err
+	}
+	if s.State != oldState {
+		issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+	}
+
+	if err := s.LoadPolicy(ctx); issues_model.IsErrSLAPolicyNotExist(err) {
+		// the policy has been deleted, nobody to notify
+		return nil
+	} else if err != nil {
+		return err
+	}
+	recipients, err := escalationRecipients(ctx, issue, s.Policy)
+	if err != nil {
+		return err
+	}
+	if err := mailer.MailSLABreached(ctx, issue, s, firstResponse, resolve, recipients); err != nil {
+		log.Error("MailSLABreached [issue: %d]: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// escalationRecipients returns the users notified when an issue breaches a policy, the ones who can't read
+// the issue are skipped
+func escalationRecipients(ctx context.Context, issue *issues_model.Issue, policy *issues_model.SLAPolicy) ([]*user_model.User, error) {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	ids := make(container.Set[int64])
+	if policy.NotifyAssignees {
+		assigneeIDs, err := issues_model.GetAssigneeIDsByIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		ids.AddMultiple(assigneeIDs...)
+	}
+	ids.AddMultiple(policy.EscalateUserIDs...)
+	for _, teamID := range policy.EscalateTeamIDs {
+		team, err := organization.GetTeamByID(ctx, teamID)
+		if organization.IsErrTeamNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if team.OrgID != issue.Repo.OwnerID {
+			continue
+		}
+		teamUsers, err := organization.GetTeamUsersByTeamID(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tu := range teamUsers {
+			ids.Add(tu.UID)
+		}
+	}
+
+	users, err := user_model.GetMaileableUsersByIDs(ctx, ids.Values(), false)
+	if err != nil {
+		return nil, err
+	}
+	recipients := make([]*user_model.User, 0, len(users))
+	for _, user := range users {
+		perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, user)
+		if err != nil {
+			return nil, err
+		}
+		if perm.CanReadIssuesOrPulls(issue.IsPull) {
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sla
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// Init registers the notifier tracking the SLAs of the issues
+func Init() error {
+	notify_service.RegisterNotifier(NewNotifier())
+
+	return nil
+}
+
+type slaNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &slaNotifier{}
+
+// NewNotifier creates a new notifier tracking the SLAs of the issues
+func NewNotifier() notify_service.Notifier {
+	return &slaNotifier{}
+}
+
+func (*slaNotifier) NewIssue(ctx context.Context, issue *issues_model.Issue, _ []*user_model.User) {
+	if err := UpdateIssueSLA(ctx, issue); err != nil {
+		log.Error("UpdateIssueSLA [issue: %d]: %v", issue.ID, err)
+	}
+}
+
+func (*slaNotifier) IssueChangeLabels(ctx context.Context, _ *user_model.User, issue *issues_model.Issue, _, _ []*issues_model.Label) {
+	// the labels may be cached before the change
+	issue.Labels = nil
+	if err := UpdateIssueSLA(ctx, issue); err != nil {
+		log.Error("UpdateIssueSLA [issue: %d]: %v", issue.ID, err)
+	}
+}
+
+func (*slaNotifier) CreateIssueComment(ctx context.Context, doer *user_model.User, _ *repo_model.Repository,
+	issue *issues_model.Issue, comment *issues_model.Comment, _ []*user_model.User,
+) {
+	if issue.IsPull || comment.Type != issues_model.CommentTypeComment {
+		return
+	}
+	if err := RecordResponse(ctx, doer, issue, comment.CreatedUnix); err != nil {
+		log.Error("RecordResponse [issue: %d]: %v", issue.ID, err)
+	}
+}
+
+func (*slaNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, _ string, issue *issues_model.Issue, _ *issues_model.Comment, _ bool) {
+	if issue.IsPull {
+		return
+	}
+	if err := RecordStatusChange(ctx, doer, issue); err != nil {
+		log.Error("RecordStatusChange [issue: %d]: %v", issue.ID, err)
+	}
+}
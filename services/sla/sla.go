@@ -0,0 +1,205 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sla
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// CanRespond checks if a user answers an issue on behalf of the repository: the poster of an issue can't respond
+// to it, only the users who can write to the issues of the repository
+func CanRespond(ctx context.Context, user *user_model.User, issue *issues_model.Issue) (bool, error) {
+	if user == nil || user.ID == issue.PosterID {
+		return false, nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return false, err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, user)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanWriteIssuesOrPulls(issue.IsPull), nil
+}
+
+// UpdateIssueSLA starts, changes or stops tracking an issue according to the SLA policies of its repository,
+// the deadlines are computed from the creation of the issue. The pull requests aren't tracked.
+func UpdateIssueSLA(ctx context.Context, issue *issues_model.Issue) error {
+	if issue.IsPull {
+		return nil
+	}
+	policies, err := issues_model.GetSLAPoliciesByRepoID(ctx, issue.RepoID)
+	if err != nil {
+		return err
+	}
+	return updateIssueSLA(ctx, issue, policies)
+}
+
+func updateIssueSLA(ctx context.Context, issue *issues_model.Issue, policies []*issues_model.SLAPolicy) error {
+	if err := issue.LoadLabels(ctx); err != nil {
+		return err
+	}
+	labelIDs := make([]int64, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labelIDs = append(labelIDs, label.ID)
+	}
+	policy := issues_model.MatchSLAPolicy(policies, labelIDs)
+
+	s, err := issues_model.GetIssueSLA(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		if s == nil {
+			return nil
+		}
+		if err := issues_model.DeleteIssueSLA(ctx, issue.ID); err != nil {
+			return err
+		}
+		issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+		return nil
+	}
+
+	if s == nil {
+		s = &issues_model.IssueSLA{
+			IssueID: issue.ID,
+			RepoID:  issue.RepoID,
+		}
+		if s.FirstResponseUnix, err = findFirstResponse(ctx, issue); err != nil {
+			return err
+		}
+		if issue.IsClosed {
+			s.ResolvedUnix = issue.ClosedUnix
+		}
+	}
+	old := *s
+	s.ApplyPolicy(policy, issue.CreatedUnix)
+	s.UpdateState(timeutil.TimeStampNow())
+	if s.ID > 0 && s.PolicyID == old.PolicyID && s.State == old.State &&
+		s.FirstResponseDeadline == old.FirstResponseDeadline && s.ResolveDeadline == old.ResolveDeadline {
+		return nil
+	}
+	if err := issues_model.SaveIssueSLA(ctx, s); err != nil {
+		return err
+	}
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+	return nil
+}
+
+// findFirstResponse returns the time of the first comment of a user who can respond to an issue, zero if none
+func findFirstResponse(ctx context.Context, issue *issues_model.Issue) (timeutil.TimeStamp, error) {
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IssueID: issue.ID,
+		Type:    issues_model.CommentTypeComment,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := comments.LoadPosters(ctx); err != nil {
+		return 0, err
+	}
+	for _, comment := range comments {
+		if ok, err := CanRespond(ctx, comment.Poster, issue); err != nil {
+			return 0, err
+		} else if ok {
+			return comment.CreatedUnix, nil
+		}
+	}
+	return 0, nil
+}
+
+// RecordResponse records the first response to a tracked issue if the user can respond to it
+func RecordResponse(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, when timeutil.TimeStamp) error {
+	s, err := issues_model.GetIssueSLA(ctx, issue.ID)
+	if err != nil || s == nil || s.FirstResponseUnix > 0 {
+		return err
+	}
+	if ok, err := CanRespond(ctx, doer, issue); err != nil || !ok {
+		return err
+	}
+	s.FirstResponseUnix = when
+	return saveIssueSLA(ctx, issue, s)
+}
+
+// RecordStatusChange records the resolution of a tracked issue when it's closed, closing an issue also counts as
+// its first response, or resumes the resolution deadline when it's reopened
+func RecordStatusChange(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) error {
+	s, err := issues_model.GetIssueSLA(ctx, issue.ID)
+	if err != nil || s == nil {
+		return err
+	}
+	if !issue.IsClosed {
+		s.ResolvedUnix = 0
+		return saveIssueSLA(ctx, issue, s)
+	}
+	s.ResolvedUnix = issue.ClosedUnix
+	if s.ResolvedUnix == 0 {
+		s.ResolvedUnix = timeutil.TimeStampNow()
+	}
+	if s.FirstResponseUnix == 0 {
+		if ok, err := CanRespond(ctx, doer, issue); err != nil {
+			return err
+		} else if ok {
+			s.FirstResponseUnix = s.ResolvedUnix
+		}
+	}
+	return saveIssueSLA(ctx, issue, s)
+}
+
+func saveIssueSLA(ctx context.Context, issue *issues_model.Issue, s *issues_model.IssueSLA) error {
+	oldState := s.State
+	s.UpdateState(timeutil.TimeStampNow())
+	if err := issues_model.SaveIssueSLA(ctx, s); err != nil {
+		return err
+	}
+	if s.State != oldState {
+		issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+	}
+	return nil
+}
+
+// refreshRepoSLAs applies the SLA policies of a repository to its open issues
+func refreshRepoSLAs(ctx context.Context, repoID int64) error {
+	policies, err := issues_model.GetSLAPoliciesByRepoID(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	return db.Iterate(ctx, builder.Eq{"repo_id": repoID, "is_pull": false, "is_closed": false}, func(ctx context.Context, issue *issues_model.Issue) error {
+		return updateIssueSLA(ctx, issue, policies)
+	})
+}
+
+// CreatePolicy creates a SLA policy and applies it to the open issues of its repository
+func CreatePolicy(ctx context.Context, p *issues_model.SLAPolicy) error {
+	if err := issues_model.CreateSLAPolicy(ctx, p); err != nil {
+		return err
+	}
+	return refreshRepoSLAs(ctx, p.RepoID)
+}
+
+// UpdatePolicy updates a SLA policy and applies it again to the open issues of its repository
+func UpdatePolicy(ctx context.Context, p *issues_model.SLAPolicy) error {
+	if err := issues_model.UpdateSLAPolicy(ctx, p); err != nil {
+		return err
+	}
+	return refreshRepoSLAs(ctx, p.RepoID)
+}
+
+// DeletePolicy deletes a SLA policy, the open issues it tracked are tracked by the other matching policies or
+// aren't tracked anymore
+func DeletePolicy(ctx context.Context, p *issues_model.SLAPolicy) error {
+	if err := issues_model.DeleteSLAPolicy(ctx, p.RepoID, p.ID); err != nil {
+		return err
+	}
+	return refreshRepoSLAs(ctx, p.RepoID)
+}
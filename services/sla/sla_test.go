@@ -0,0 +1,99 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sla
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateIssueSLA(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issue 1 has label 1, so it follows the "Urgent" policy rather than the default one
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, UpdateIssueSLA(db.DefaultContext, issue))
+	s := unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, 2, s.PolicyID)
+	assert.EqualValues(t, issue.CreatedUnix+600, s.FirstResponseDeadline)
+	assert.EqualValues(t, issue.CreatedUnix+3600, s.ResolveDeadline)
+	assert.Equal(t, issues_model.SLAStateBreached, s.State)
+
+	// the pull requests aren't tracked
+	pull := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, UpdateIssueSLA(db.DefaultContext, pull))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueSLA{IssueID: 2})
+
+	// without the label the issue falls back to the default policy
+	p := unittest.AssertExistsAndLoadBean(t, &issues_model.SLAPolicy{ID: 2})
+	assert.NoError(t, DeletePolicy(db.DefaultContext, p))
+	s = unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, 1, s.PolicyID)
+	assert.EqualValues(t, issue.CreatedUnix+3600, s.FirstResponseDeadline)
+
+	p = unittest.AssertExistsAndLoadBean(t, &issues_model.SLAPolicy{ID: 1})
+	assert.NoError(t, DeletePolicy(db.DefaultContext, p))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueSLA{IssueID: 1})
+}
+
+func TestRecordResponse(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, UpdateIssueSLA(db.DefaultContext, issue))
+
+	// the poster can't respond to its own issue
+	poster := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.PosterID})
+	assert.NoError(t, RecordResponse(db.DefaultContext, poster, issue, issue.CreatedUnix+60))
+	s := unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, 0, s.FirstResponseUnix)
+
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, RecordResponse(db.DefaultContext, owner, issue, issue.CreatedUnix+60))
+	s = unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, issue.CreatedUnix+60, s.FirstResponseUnix)
+
+	// only the first response counts
+	assert.NoError(t, RecordResponse(db.DefaultContext, owner, issue, issue.CreatedUnix+120))
+	s = unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, issue.CreatedUnix+60, s.FirstResponseUnix)
+
+	issue.IsClosed = true
+	issue.ClosedUnix = issue.CreatedUnix + 1800
+	assert.NoError(t, RecordStatusChange(db.DefaultContext, owner, issue))
+	s = unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, issue.ClosedUnix, s.ResolvedUnix)
+	assert.Equal(t, issues_model.SLAStateMet, s.State)
+
+	issue.IsClosed = false
+	assert.NoError(t, RecordStatusChange(db.DefaultContext, owner, issue))
+	s = unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.EqualValues(t, 0, s.ResolvedUnix)
+	assert.Equal(t, issues_model.SLAStateBreached, s.State)
+}
+
+func TestEscalateOverdueSLAs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, UpdateIssueSLA(db.DefaultContext, issue))
+
+	assert.NoError(t, EscalateOverdueSLAs(db.DefaultContext))
+	s := unittest.AssertExistsAndLoadBean(t, &issues_model.IssueSLA{IssueID: 1})
+	assert.True(t, s.FirstResponseEscalated)
+	assert.True(t, s.ResolveEscalated)
+	assert.Equal(t, issues_model.SLAStateBreached, s.State)
+
+	recipients, err := escalationRecipients(db.DefaultContext, issue, &issues_model.SLAPolicy{EscalateUserIDs: []int64{2, -1}})
+	assert.NoError(t, err)
+	if assert.Len(t, recipients, 1) {
+		assert.EqualValues(t, 2, recipients[0].ID)
+	}
+}
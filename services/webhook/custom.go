@@ -0,0 +1,218 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// customDefaultContentType is the content type of a custom webhook request without a content type template
+const customDefaultContentType = "application/json"
+
+type (
+	// CustomMeta contains the templates of a custom webhook, they are executed with the event name as .Event and
+	// the payload of the event as .Payload
+	CustomMeta struct {
+		ContentType string `json:"content_type"`
+		Headers     string `json:"headers"`
+		Body        string `json:"body"`
+	}
+
+	// CustomPayload is the request rendered by the templates of a custom webhook
+	CustomPayload struct {
+		ContentType string            `json:"content_type"`
+		Headers     map[string]string `json:"headers"`
+		Body        string            `json:"body"`
+	}
+
+	// CustomTemplateError is returned when a template of a custom webhook can't be parsed or executed
+	CustomTemplateError struct {
+		Field string
+		Err   error
+	}
+
+	customTemplateData struct {
+		Event   string
+		Payload api.Payloader
+	}
+)
+
+// Error implements error
+func (err CustomTemplateError) Error() string {
+	return fmt.Sprintf("%s template: %v", err.Field, err.Err)
+}
+
+// Unwrap returns the underlying error
+func (err CustomTemplateError) Unwrap() error {
+	return err.Err
+}
+
+var customTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimSpace":  strings.TrimSpace,
+	"join":       strings.Join,
+	"replace":    strings.ReplaceAll,
+	"contains":   strings.Contains,
+	"hasPrefix":  strings.HasPrefix,
+	"trimPrefix": strings.TrimPrefix,
+}
+
+// GetCustomHook returns the templates of a custom webhook
+func GetCustomHook(w *webhook_model.Webhook) *CustomMeta {
+	s := &CustomMeta{}
+	if err := json.Unmarshal([]byte(w.Meta), s); err != nil {
+		log.Error("webhook.GetCustomHook(%d): %v", w.ID, err)
+	}
+	return s
+}
+
+// JSONPayload marshals the rendered request, it is stored as the content of the hook task and unpacked again
+// when the task is delivered
+func (p *CustomPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+func parseCustomTemplate(field, text string) (*template.Template, error) {
+	tmpl, err := template.New(field).Funcs(customTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, CustomTemplateError{Field: field, Err: err}
+	}
+	return tmpl, nil
+}
+
+func executeCustomTemplate(field, text string, data *customTemplateData) (string, error) {
+	tmpl, err := parseCustomTemplate(field, text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", CustomTemplateError{Field: field, Err: err}
+	}
+	return sb.String(), nil
+}
+
+// parseCustomHeaders parses the rendered headers template, one "Name: value" header per line
+func parseCustomHeaders(text string) (map[string]string, error) {
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, CustomTemplateError{Field: "headers", Err: fmt.Errorf("invalid header %q", line)}
+		}
+		headers[name] = value
+	}
+	return headers, scanner.Err()
+}
+
+// RenderCustomPayload renders the request of a custom webhook for the payload of an event
+func RenderCustomPayload(meta *CustomMeta, p api.Payloader, event webhook_module.HookEventType) (*CustomPayload, error) {
+	data := &customTemplateData{Event: string(event), Payload: p}
+
+	contentType, err := executeCustomTemplate("content_type", meta.ContentType, data)
+	if err != nil {
+		return nil, err
+	}
+	contentType = strings.TrimSpace(contentType)
+	if contentType == "" {
+		contentType = customDefaultContentType
+	} else if !httpguts.ValidHeaderFieldValue(contentType) {
+		return nil, CustomTemplateError{Field: "content_type", Err: fmt.Errorf("invalid content type %q", contentType)}
+	}
+
+	headersText, err := executeCustomTemplate("headers", meta.Headers, data)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := parseCustomHeaders(headersText)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := executeCustomTemplate("body", meta.Body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CustomPayload{ContentType: contentType, Headers: headers, Body: body}, nil
+}
+
+// ValidateCustomMeta checks that the templates of a custom webhook can be parsed and rendered for the sample
+// payloads of the events of the webhook
+func ValidateCustomMeta(meta *CustomMeta, w *webhook_model.Webhook) error {
+	if _, err := parseCustomTemplate("content_type", meta.ContentType); err != nil {
+		return err
+	}
+	if _, err := parseCustomTemplate("headers", meta.Headers); err != nil {
+		return err
+	}
+	if _, err := parseCustomTemplate("body", meta.Body); err != nil {
+		return err
+	}
+	for _, event := range w.EventsArray() {
+		p := SamplePayload(webhook_module.HookEventType(event))
+		if p == nil {
+			continue
+		}
+		if _, err := RenderCustomPayload(meta, p, webhook_module.HookEventType(event)); err != nil {
+			return fmt.Errorf("%s: %w", event, err)
+		}
+	}
+	return nil
+}
+
+// GetCustomPayload renders the request of a custom webhook
+func GetCustomPayload(p api.Payloader, event webhook_module.HookEventType, meta string) (api.Payloader, error) {
+	m := &CustomMeta{}
+	if err := json.Unmarshal([]byte(meta), m); err != nil {
+		return nil, fmt.Errorf("GetCustomPayload meta json: %w", err)
+	}
+	return RenderCustomPayload(m, p, event)
+}
+
+// newCustomHookRequest creates the request of a custom webhook task from its rendered payload, it also returns
+// the body of the request which is signed instead of the stored payload
+func newCustomHookRequest(w *webhook_model.Webhook, t *webhook_model.HookTask) (*http.Request, string, error) {
+	p := &CustomPayload{}
+	if err := json.Unmarshal([]byte(t.PayloadContent), p); err != nil {
+		return nil, "", fmt.Errorf("unable to deliver webhook task[%d] as cannot unmarshal the custom payload: %w", t.ID, err)
+	}
+
+	method := w.HTTPMethod
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, w.URL, strings.NewReader(p.Body))
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to deliver webhook task[%d] as unable to create HTTP request for webhook url %s: %w", t.ID, w.URL, err)
+	}
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", p.ContentType)
+	return req, p.Body, nil
+}
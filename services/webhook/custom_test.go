@@ -0,0 +1,136 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/minio/sha256-simd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCustomPayload(t *testing.T) {
+	meta := &CustomMeta{
+		ContentType: "text/plain",
+		Headers:     "X-Event: {{.Event}}\n\nX-Repo: {{.Payload.Repo.FullName}}\n",
+		Body:        `{{.Payload.Pusher.UserName}} pushed {{len .Payload.Commits}} commits to {{json .Payload.Ref}}`,
+	}
+	p, err := RenderCustomPayload(meta, pushTestPayload(), webhook_module.HookEventPush)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", p.ContentType)
+	assert.Equal(t, map[string]string{"X-Event": "push", "X-Repo": "test/repo"}, p.Headers)
+	assert.Equal(t, `user1 pushed 2 commits to "refs/heads/test"`, p.Body)
+
+	// the content type defaults to json
+	p, err = RenderCustomPayload(&CustomMeta{Body: "{{json .Payload.Issue.Title}}"}, issueTestPayload(), webhook_module.HookEventIssues)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", p.ContentType)
+	assert.Empty(t, p.Headers)
+	assert.Equal(t, `"crash"`, p.Body)
+
+	_, err = RenderCustomPayload(&CustomMeta{Headers: "not a header"}, pushTestPayload(), webhook_module.HookEventPush)
+	var tmplErr CustomTemplateError
+	require.ErrorAs(t, err, &tmplErr)
+	assert.Equal(t, "headers", tmplErr.Field)
+
+	_, err = RenderCustomPayload(&CustomMeta{Body: "{{.Payload.Commits}}"}, issueTestPayload(), webhook_module.HookEventIssues)
+	require.ErrorAs(t, err, &tmplErr)
+	assert.Equal(t, "body", tmplErr.Field)
+}
+
+func TestValidateCustomMeta(t *testing.T) {
+	for _, event := range SampleEvents {
+		assert.NotNil(t, SamplePayload(event), event)
+	}
+
+	push := &webhook_model.Webhook{HookEvent: &webhook_module.HookEvent{PushOnly: true}}
+	all := &webhook_model.Webhook{HookEvent: &webhook_module.HookEvent{SendEverything: true}}
+
+	assert.NoError(t, ValidateCustomMeta(&CustomMeta{Body: "{{json .Payload}}"}, all))
+
+	var tmplErr CustomTemplateError
+	err := ValidateCustomMeta(&CustomMeta{Body: "{{.Payload.Ref"}, push)
+	require.ErrorAs(t, err, &tmplErr)
+	assert.Equal(t, "body", tmplErr.Field)
+
+	// a template using the fields of a push is only valid for webhooks triggered by pushes, unless it is guarded
+	meta := &CustomMeta{Body: "{{.Payload.HeadCommit.Message}}"}
+	assert.NoError(t, ValidateCustomMeta(meta, push))
+	assert.ErrorAs(t, ValidateCustomMeta(meta, all), &tmplErr)
+	meta.Body = `{{if eq .Event "push"}}{{.Payload.HeadCommit.Message}}{{end}}`
+	assert.NoError(t, ValidateCustomMeta(meta, all))
+}
+
+func TestWebhookDeliverCustom(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	done := make(chan struct{}, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+		assert.Equal(t, "push", r.Header.Get("X-Custom-Event"))
+		assert.Equal(t, "push to refs/heads/test", string(body))
+
+		// the rendered body is signed
+		sig := hmac.New(sha256.New, []byte("secret"))
+		_, _ = sig.Write(body)
+		assert.Equal(t, hex.EncodeToString(sig.Sum(nil)), r.Header.Get("X-Gitea-Signature"))
+
+		w.WriteHeader(http.StatusOK)
+		done <- struct{}{}
+	}))
+	t.Cleanup(s.Close)
+
+	meta, err := json.Marshal(&CustomMeta{
+		ContentType: "text/plain",
+		Headers:     "X-Custom-Event: {{.Event}}",
+		Body:        "{{.Event}} to {{.Payload.Ref}}",
+	})
+	require.NoError(t, err)
+	hook := &webhook_model.Webhook{
+		RepoID:      3,
+		URL:         s.URL + "/webhook",
+		HTTPMethod:  http.MethodPut,
+		ContentType: webhook_model.ContentTypeJSON,
+		Secret:      "secret",
+		IsActive:    true,
+		Type:        webhook_module.CUSTOM,
+		Meta:        string(meta),
+	}
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, hook))
+
+	payload, err := GetCustomPayload(pushTestPayload(), webhook_module.HookEventPush, hook.Meta)
+	require.NoError(t, err)
+	hookTask, err := webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{
+		HookID:    hook.ID,
+		EventType: webhook_module.HookEventPush,
+		Payloader: payload,
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, Deliver(context.Background(), hookTask))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waited to long for request to happen")
+	}
+
+	assert.True(t, hookTask.IsSucceed)
+}
@@ -52,60 +52,68 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 
 	var req *http.Request
 
-	switch w.HTTPMethod {
-	case "":
-		log.Info("HTTP Method for webhook %s empty, setting to POST as default", w.URL)
-		fallthrough
-	case http.MethodPost:
-		switch w.ContentType {
-		case webhook_model.ContentTypeJSON:
-			req, err = http.NewRequest("POST", w.URL, strings.NewReader(t.PayloadContent))
-			if err != nil {
-				return err
-			}
+	payload := t.PayloadContent
+	if w.Type == webhook_module.CUSTOM {
+		req, payload, err = newCustomHookRequest(w, t)
+		if err != nil {
+			return err
+		}
+	} else {
+		switch w.HTTPMethod {
+		case "":
+			log.Info("HTTP Method for webhook %s empty, setting to POST as default", w.URL)
+			fallthrough
+		case http.MethodPost:
+			switch w.ContentType {
+			case webhook_model.ContentTypeJSON:
+				req, err = http.NewRequest("POST", w.URL, strings.NewReader(t.PayloadContent))
+				if err != nil {
+					return err
+				}
 
-			req.Header.Set("Content-Type", "application/json")
-		case webhook_model.ContentTypeForm:
-			forms := url.Values{
-				"payload": []string{t.PayloadContent},
-			}
+				req.Header.Set("Content-Type", "application/json")
+			case webhook_model.ContentTypeForm:
+				forms := url.Values{
+					"payload": []string{t.PayloadContent},
+				}
 
-			req, err = http.NewRequest("POST", w.URL, strings.NewReader(forms.Encode()))
-			if err != nil {
-				return err
-			}
+				req, err = http.NewRequest("POST", w.URL, strings.NewReader(forms.Encode()))
+				if err != nil {
+					return err
+				}
 
-			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		}
-	case http.MethodGet:
-		u, err := url.Parse(w.URL)
-		if err != nil {
-			return fmt.Errorf("unable to deliver webhook task[%d] as cannot parse webhook url %s: %w", t.ID, w.URL, err)
-		}
-		vals := u.Query()
-		vals["payload"] = []string{t.PayloadContent}
-		u.RawQuery = vals.Encode()
-		req, err = http.NewRequest("GET", u.String(), nil)
-		if err != nil {
-			return fmt.Errorf("unable to deliver webhook task[%d] as unable to create HTTP request for webhook url %s: %w", t.ID, w.URL, err)
-		}
-	case http.MethodPut:
-		switch w.Type {
-		case webhook_module.MATRIX:
-			txnID, err := getMatrixTxnID([]byte(t.PayloadContent))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+		case http.MethodGet:
+			u, err := url.Parse(w.URL)
 			if err != nil {
-				return err
+				return fmt.Errorf("unable to deliver webhook task[%d] as cannot parse webhook url %s: %w", t.ID, w.URL, err)
 			}
-			url := fmt.Sprintf("%s/%s", w.URL, url.PathEscape(txnID))
-			req, err = http.NewRequest("PUT", url, strings.NewReader(t.PayloadContent))
+			vals := u.Query()
+			vals["payload"] = []string{t.PayloadContent}
+			u.RawQuery = vals.Encode()
+			req, err = http.NewRequest("GET", u.String(), nil)
 			if err != nil {
-				return fmt.Errorf("unable to deliver webhook task[%d] as cannot create matrix request for webhook url %s: %w", t.ID, w.URL, err)
+				return fmt.Errorf("unable to deliver webhook task[%d] as unable to create HTTP request for webhook url %s: %w", t.ID, w.URL, err)
+			}
+		case http.MethodPut:
+			switch w.Type {
+			case webhook_module.MATRIX:
+				txnID, err := getMatrixTxnID([]byte(t.PayloadContent))
+				if err != nil {
+					return err
+				}
+				url := fmt.Sprintf("%s/%s", w.URL, url.PathEscape(txnID))
+				req, err = http.NewRequest("PUT", url, strings.NewReader(t.PayloadContent))
+				if err != nil {
+					return fmt.Errorf("unable to deliver webhook task[%d] as cannot create matrix request for webhook url %s: %w", t.ID, w.URL, err)
+				}
+			default:
+				return fmt.Errorf("invalid http method for webhook task[%d] in webhook %s: %v", t.ID, w.URL, w.HTTPMethod)
 			}
 		default:
 			return fmt.Errorf("invalid http method for webhook task[%d] in webhook %s: %v", t.ID, w.URL, w.HTTPMethod)
 		}
-	default:
-		return fmt.Errorf("invalid http method for webhook task[%d] in webhook %s: %v", t.ID, w.URL, w.HTTPMethod)
 	}
 
 	var signatureSHA1 string
@@ -113,7 +121,7 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 	if len(w.Secret) > 0 {
 		sig1 := hmac.New(sha1.New, []byte(w.Secret))
 		sig256 := hmac.New(sha256.New, []byte(w.Secret))
-		_, err = io.MultiWriter(sig1, sig256).Write([]byte(t.PayloadContent))
+		_, err = io.MultiWriter(sig1, sig256).Write([]byte(payload))
 		if err != nil {
 			log.Error("prepareWebhooks.sigWrite: %v", err)
 		}
@@ -123,17 +131,18 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 
 	event := t.EventType.Event()
 	eventType := string(t.EventType)
-	req.Header.Add("X-Gitea-Delivery", t.UUID)
+	deliveryUUID := t.DeliveryUUID()
+	req.Header.Add("X-Gitea-Delivery", deliveryUUID)
 	req.Header.Add("X-Gitea-Event", event)
 	req.Header.Add("X-Gitea-Event-Type", eventType)
 	req.Header.Add("X-Gitea-Signature", signatureSHA256)
-	req.Header.Add("X-Gogs-Delivery", t.UUID)
+	req.Header.Add("X-Gogs-Delivery", deliveryUUID)
 	req.Header.Add("X-Gogs-Event", event)
 	req.Header.Add("X-Gogs-Event-Type", eventType)
 	req.Header.Add("X-Gogs-Signature", signatureSHA256)
 	req.Header.Add("X-Hub-Signature", "sha1="+signatureSHA1)
 	req.Header.Add("X-Hub-Signature-256", "sha256="+signatureSHA256)
-	req.Header["X-GitHub-Delivery"] = []string{t.UUID}
+	req.Header["X-GitHub-Delivery"] = []string{deliveryUUID}
 	req.Header["X-GitHub-Event"] = []string{event}
 	req.Header["X-GitHub-Event-Type"] = []string{eventType}
 
@@ -174,6 +183,9 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 		return nil
 	}
 
+	// attempted is whether the webhook has been called, only these deliveries are retried
+	attempted := false
+
 	// All code from this point will update the hook task
 	defer func() {
 		t.Delivered = timeutil.TimeStampNanoNow()
@@ -199,6 +211,12 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 			log.Error("UpdateWebhookLastStatus: %v", err)
 			return
 		}
+
+		if attempted {
+			if err := handleDeliveryResult(ctx, w, t); err != nil {
+				log.Error("Unable to handle the delivery result of webhook task[%d]: %v", t.ID, err)
+			}
+		}
 	}()
 
 	if setting.DisableWebhooks {
@@ -210,6 +228,7 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 		return nil
 	}
 
+	attempted = true
 	resp, err := webhookHTTPClient.Do(req.WithContext(ctx))
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
@@ -293,6 +312,7 @@ func Init() error {
 	go graceful.GetManager().RunWithCancel(hookQueue)
 
 	go graceful.GetManager().RunWithShutdownContext(populateWebhookSendingQueue)
+	go graceful.GetManager().RunWithShutdownContext(pollRetryHookTasks)
 
 	return nil
 }
@@ -179,6 +179,55 @@ func (d *DingtalkPayload) Package(p *api.PackagePayload) (api.Payloader, error)
 	return createDingtalkPayload(text, text, "view package", p.Package.HTMLURL), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (d *DingtalkPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view run", p.WorkflowRun.HTMLURL), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (d *DingtalkPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, _ := getWorkflowJobPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view job", p.WorkflowJob.HTMLURL), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (d *DingtalkPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, _ := getBranchProtectionPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view branch protection", p.Repository.HTMLURL+"/settings/branches"), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (d *DingtalkPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, _ := getMemberPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view collaborators", p.Repository.HTMLURL+"/settings/collaboration"), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (d *DingtalkPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, _ := getMembershipPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view team", membershipTeamURL(p)), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (d *DingtalkPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view deploy keys", p.Repository.HTMLURL+"/settings/keys"), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (d *DingtalkPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, _ := getRepositoryVisibilityPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view repository", p.Repository.HTMLURL), nil
+}
+
 func createDingtalkPayload(title, text, singleTitle, singleURL string) *DingtalkPayload {
 	return &DingtalkPayload{
 		MsgType: "actionCard",
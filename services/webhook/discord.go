@@ -262,6 +262,55 @@ func (d *DiscordPayload) Package(p *api.PackagePayload) (api.Payloader, error) {
 	return d.createPayload(p.Sender, text, "", p.Package.HTMLURL, color), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (d *DiscordPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.WorkflowRun.HTMLURL, color), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (d *DiscordPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, color := getWorkflowJobPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.WorkflowJob.HTMLURL, color), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (d *DiscordPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, color := getBranchProtectionPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL+"/settings/branches", color), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (d *DiscordPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, color := getMemberPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL+"/settings/collaboration", color), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (d *DiscordPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, color := getMembershipPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", membershipTeamURL(p), color), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (d *DiscordPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, color := getDeployKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL+"/settings/keys", color), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (d *DiscordPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, color := getRepositoryVisibilityPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL, color), nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event webhook_module.HookEventType, meta string) (api.Payloader, error) {
 	s := new(DiscordPayload)
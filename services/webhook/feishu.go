@@ -181,6 +181,55 @@ func (f *FeishuPayload) Package(p *api.PackagePayload) (api.Payloader, error) {
 	return newFeishuTextPayload(text), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (f *FeishuPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (f *FeishuPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, _ := getWorkflowJobPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (f *FeishuPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, _ := getBranchProtectionPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (f *FeishuPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, _ := getMemberPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (f *FeishuPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, _ := getMembershipPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (f *FeishuPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (f *FeishuPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, _ := getRepositoryVisibilityPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
 // GetFeishuPayload converts a ding talk webhook into a FeishuPayload
 func GetFeishuPayload(p api.Payloader, event webhook_module.HookEventType, _ string) (api.Payloader, error) {
 	return convertPayloader(new(FeishuPayload), p, event)
@@ -311,6 +311,150 @@ func getPackagePayloadInfo(p *api.PackagePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+// workflowStatusInfo returns the description and the color of the status of a workflow run or job
+func workflowStatusInfo(action api.HookWorkflowAction, conclusion string) (status string, color int) {
+	switch action {
+	case api.HookWorkflowRequested, api.HookWorkflowQueued:
+		return "queued", greyColor
+	case api.HookWorkflowInProgress:
+		return "in progress", yellowColor
+	}
+	switch conclusion {
+	case "success":
+		return "succeeded", greenColor
+	case "failure":
+		return "failed", redColor
+	case "cancelled":
+		return "cancelled", orangeColor
+	}
+	return conclusion, greyColor
+}
+
+func getWorkflowRunPayloadInfo(p *api.WorkflowRunPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	runLink := linkFormatter(p.WorkflowRun.HTMLURL, fmt.Sprintf("%s #%d", p.WorkflowRun.WorkflowID, p.WorkflowRun.RunNumber))
+
+	status, color := workflowStatusInfo(p.Action, p.WorkflowRun.Conclusion)
+	text = fmt.Sprintf("[%s] Workflow run %s %s: %s", repoLink, runLink, status, p.WorkflowRun.DisplayTitle)
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getWorkflowJobPayloadInfo(p *api.WorkflowJobPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	jobLink := linkFormatter(p.WorkflowJob.HTMLURL, p.WorkflowJob.Name)
+
+	status, color := workflowStatusInfo(p.Action, p.WorkflowJob.Conclusion)
+	text = fmt.Sprintf("[%s] Workflow job %s of run #%d %s", repoLink, jobLink, p.WorkflowJob.RunNumber, status)
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getBranchProtectionPayloadInfo(p *api.BranchProtectionPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+
+	switch p.Action {
+	case api.HookBranchProtectionCreated:
+		text = fmt.Sprintf("[%s] Branch protection rule created: %s", repoLink, p.Rule.RuleName)
+		color = greenColor
+	case api.HookBranchProtectionEdited:
+		text = fmt.Sprintf("[%s] Branch protection rule edited: %s", repoLink, p.Rule.RuleName)
+		color = yellowColor
+	case api.HookBranchProtectionDeleted:
+		text = fmt.Sprintf("[%s] Branch protection rule deleted: %s", repoLink, p.Rule.RuleName)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getMemberPayloadInfo(p *api.MemberPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	memberLink := linkFormatter(setting.AppURL+url.PathEscape(p.Member.UserName), p.Member.UserName)
+
+	switch p.Action {
+	case api.HookMemberAdded:
+		text = fmt.Sprintf("[%s] Collaborator added: %s", repoLink, memberLink)
+		color = greenColor
+	case api.HookMemberRemoved:
+		text = fmt.Sprintf("[%s] Collaborator removed: %s", repoLink, memberLink)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+// membershipTeamURL returns the link of the team of a membership event
+func membershipTeamURL(p *api.MembershipPayload) string {
+	return setting.AppURL + "org/" + url.PathEscape(p.Organization.UserName) + "/teams/" + url.PathEscape(strings.ToLower(p.Team.Name))
+}
+
+func getMembershipPayloadInfo(p *api.MembershipPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	orgLink := linkFormatter(setting.AppURL+url.PathEscape(p.Organization.UserName), p.Organization.UserName)
+	teamLink := linkFormatter(membershipTeamURL(p), p.Team.Name)
+	memberLink := linkFormatter(setting.AppURL+url.PathEscape(p.Member.UserName), p.Member.UserName)
+
+	switch p.Action {
+	case api.HookMemberAdded:
+		text = fmt.Sprintf("[%s] Member %s added to team %s", orgLink, memberLink, teamLink)
+		color = greenColor
+	case api.HookMemberRemoved:
+		text = fmt.Sprintf("[%s] Member %s removed from team %s", orgLink, memberLink, teamLink)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getDeployKeyPayloadInfo(p *api.DeployKeyPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	access := "read-write"
+	if p.Key.ReadOnly {
+		access = "read-only"
+	}
+
+	text = fmt.Sprintf("[%s] Deploy key added: %s (%s)", repoLink, p.Key.Title, access)
+	color = yellowColor
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getRepositoryVisibilityPayloadInfo(p *api.RepositoryPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+
+	switch p.Action {
+	case api.HookRepoPublicized:
+		text = fmt.Sprintf("[%s] Repository made public", repoLink)
+		color = orangeColor
+	case api.HookRepoPrivatized:
+		text = fmt.Sprintf("[%s] Repository made private", repoLink)
+		color = greyColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
 // ToHook convert models.Webhook to api.Hook
 // This function is not part of the convert package to prevent an import cycle
 func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
@@ -325,6 +469,12 @@ func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
 		config["icon_url"] = s.IconURL
 		config["color"] = s.Color
 	}
+	if w.Type == webhook_module.CUSTOM {
+		c := GetCustomHook(w)
+		config["content_type_template"] = c.ContentType
+		config["headers_template"] = c.Headers
+		config["body_template"] = c.Body
+	}
 
 	authorizationHeader, err := w.HeaderAuthorization()
 	if err != nil {
@@ -6,6 +6,7 @@ package webhook
 import (
 	"testing"
 
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -333,6 +334,79 @@ func packageTestPayload() *api.PackagePayload {
 	}
 }
 
+func workflowRunTestPayload() *api.WorkflowRunPayload {
+	return &api.WorkflowRunPayload{
+		Action: api.HookWorkflowCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		WorkflowRun: &api.ActionWorkflowRun{
+			ID:           1,
+			RunNumber:    3,
+			WorkflowID:   "build.yml",
+			DisplayTitle: "Fix the build",
+			Event:        "push",
+			HeadBranch:   "main",
+			Status:       "completed",
+			Conclusion:   "success",
+			HTMLURL:      "http://localhost:3000/test/repo/actions/runs/3",
+		},
+	}
+}
+
+func workflowJobTestPayload() *api.WorkflowJobPayload {
+	return &api.WorkflowJobPayload{
+		Action: api.HookWorkflowCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		WorkflowJob: &api.ActionWorkflowJob{
+			ID:         1,
+			RunID:      1,
+			RunNumber:  3,
+			Name:       "test",
+			HeadBranch: "main",
+			Status:     "completed",
+			Conclusion: "failure",
+			HTMLURL:    "http://localhost:3000/test/repo/actions/runs/3/jobs/0",
+		},
+	}
+}
+
+func membershipTestPayload() *api.MembershipPayload {
+	return &api.MembershipPayload{
+		Action: api.HookMemberAdded,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Member: &api.User{
+			UserName:  "user2",
+			AvatarURL: "http://localhost:3000/user2/avatar",
+		},
+		Team: &api.Team{
+			ID:   1,
+			Name: "Owners",
+		},
+		Organization: &api.User{
+			UserName:  "org1",
+			AvatarURL: "http://localhost:3000/org1/avatar",
+		},
+	}
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
@@ -639,3 +713,131 @@ func TestGetIssueCommentPayloadInfo(t *testing.T) {
 		assert.Equal(t, c.color, color, "case %d", i)
 	}
 }
+
+func TestGetWorkflowRunPayloadInfo(t *testing.T) {
+	p := workflowRunTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowRequested,
+			"",
+			"[test/repo] Workflow run build.yml #3 queued: Fix the build by user1",
+			greyColor,
+		},
+		{
+			api.HookWorkflowInProgress,
+			"",
+			"[test/repo] Workflow run build.yml #3 in progress: Fix the build by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"success",
+			"[test/repo] Workflow run build.yml #3 succeeded: Fix the build by user1",
+			greenColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"failure",
+			"[test/repo] Workflow run build.yml #3 failed: Fix the build by user1",
+			redColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowRun.Conclusion = c.conclusion
+		text, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetWorkflowJobPayloadInfo(t *testing.T) {
+	p := workflowJobTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowQueued,
+			"",
+			"[test/repo] Workflow job test of run #3 queued by user1",
+			greyColor,
+		},
+		{
+			api.HookWorkflowCompleted,
+			"cancelled",
+			"[test/repo] Workflow job test of run #3 cancelled by user1",
+			orangeColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowJob.Conclusion = c.conclusion
+		text, color := getWorkflowJobPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetBranchProtectionPayloadInfo(t *testing.T) {
+	p := &api.BranchProtectionPayload{
+		Sender:     &api.User{UserName: "user1"},
+		Repository: &api.Repository{HTMLURL: "http://localhost:3000/test/repo", FullName: "test/repo"},
+		Rule:       &api.BranchProtection{RuleName: "main"},
+	}
+
+	cases := []struct {
+		action api.HookBranchProtectionAction
+		text   string
+		color  int
+	}{
+		{
+			api.HookBranchProtectionCreated,
+			"[test/repo] Branch protection rule created: main by user1",
+			greenColor,
+		},
+		{
+			api.HookBranchProtectionEdited,
+			"[test/repo] Branch protection rule edited: main by user1",
+			yellowColor,
+		},
+		{
+			api.HookBranchProtectionDeleted,
+			"[test/repo] Branch protection rule deleted: main by user1",
+			redColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		text, color := getBranchProtectionPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetMembershipPayloadInfo(t *testing.T) {
+	p := membershipTestPayload()
+
+	text, color := getMembershipPayloadInfo(p, noneLinkFormatter, true)
+	assert.Equal(t, "[org1] Member user2 added to team Owners by user1", text)
+	assert.Equal(t, greenColor, color)
+
+	p.Action = api.HookMemberRemoved
+	text, color = getMembershipPayloadInfo(p, noneLinkFormatter, false)
+	assert.Equal(t, "[org1] Member user2 removed from team Owners", text)
+	assert.Equal(t, redColor, color)
+
+	assert.Equal(t, setting.AppURL+"org/org1/teams/owners", membershipTeamURL(p))
+}
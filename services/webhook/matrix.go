@@ -225,6 +225,55 @@ func (m *MatrixPayload) Package(p *api.PackagePayload) (api.Payloader, error) {
 	return getMatrixPayload(text, nil, m.MsgType), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (m *MatrixPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (m *MatrixPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, _ := getWorkflowJobPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (m *MatrixPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, _ := getBranchProtectionPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (m *MatrixPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, _ := getMemberPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (m *MatrixPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, _ := getMembershipPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (m *MatrixPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (m *MatrixPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, _ := getRepositoryVisibilityPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
 // GetMatrixPayload converts a Matrix webhook into a MatrixPayload
 func GetMatrixPayload(p api.Payloader, event webhook_module.HookEventType, meta string) (api.Payloader, error) {
 	s := new(MatrixPayload)
@@ -310,6 +310,111 @@ func (m *MSTeamsPayload) Package(p *api.PackagePayload) (api.Payloader, error) {
 	), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (m *MSTeamsPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	title, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.WorkflowRun.HTMLURL,
+		color,
+		&MSTeamsFact{"Workflow run:", p.WorkflowRun.DisplayTitle},
+	), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (m *MSTeamsPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	title, color := getWorkflowJobPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.WorkflowJob.HTMLURL,
+		color,
+		&MSTeamsFact{"Workflow job:", p.WorkflowJob.Name},
+	), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (m *MSTeamsPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	title, color := getBranchProtectionPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL+"/settings/branches",
+		color,
+		&MSTeamsFact{"Rule:", p.Rule.RuleName},
+	), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (m *MSTeamsPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	title, color := getMemberPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL+"/settings/collaboration",
+		color,
+		&MSTeamsFact{"Collaborator:", p.Member.UserName},
+	), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (m *MSTeamsPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	title, color := getMembershipPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		nil,
+		p.Sender,
+		title,
+		"",
+		membershipTeamURL(p),
+		color,
+		&MSTeamsFact{"Team:", p.Team.Name},
+	), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (m *MSTeamsPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	title, color := getDeployKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL+"/settings/keys",
+		color,
+		&MSTeamsFact{"Deploy key:", p.Key.Title},
+	), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (m *MSTeamsPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	title, color := getRepositoryVisibilityPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL,
+		color,
+		nil,
+	), nil
+}
+
 // GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
 func GetMSTeamsPayload(p api.Payloader, event webhook_module.HookEventType, _ string) (api.Payloader, error) {
 	return convertPayloader(new(MSTeamsPayload), p, event)
@@ -6,7 +6,11 @@ package webhook
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
@@ -887,3 +891,157 @@ func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_mo
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
+
+func (m *webhookNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	apiRun, err := convert.ToActionWorkflowRun(ctx, run)
+	if err != nil {
+		log.Error("ToActionWorkflowRun: %v", err)
+		return
+	}
+
+	var action api.HookWorkflowAction
+	switch apiRun.Status {
+	case "completed":
+		action = api.HookWorkflowCompleted
+	case "in_progress":
+		action = api.HookWorkflowInProgress
+	default:
+		action = api.HookWorkflowRequested
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventWorkflowRun, &api.WorkflowRunPayload{
+		Action:      action,
+		WorkflowRun: apiRun,
+		Repository:  convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:      convert.ToUser(ctx, sender, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob) {
+	apiJob, err := convert.ToActionWorkflowJob(ctx, job)
+	if err != nil {
+		log.Error("ToActionWorkflowJob: %v", err)
+		return
+	}
+
+	var action api.HookWorkflowAction
+	switch apiJob.Status {
+	case "completed":
+		action = api.HookWorkflowCompleted
+	case "in_progress":
+		action = api.HookWorkflowInProgress
+	default:
+		action = api.HookWorkflowQueued
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventWorkflowJob, &api.WorkflowJobPayload{
+		Action:      action,
+		WorkflowJob: apiJob,
+		Repository:  convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:      convert.ToUser(ctx, sender, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) UpdateProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch, isNew bool) {
+	action := api.HookBranchProtectionEdited
+	if isNew {
+		action = api.HookBranchProtectionCreated
+	}
+	notifyBranchProtection(ctx, doer, repo, rule, action)
+}
+
+func (m *webhookNotifier) DeleteProtectedBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch) {
+	notifyBranchProtection(ctx, doer, repo, rule, api.HookBranchProtectionDeleted)
+}
+
+func notifyBranchProtection(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, rule *git_model.ProtectedBranch, action api.HookBranchProtectionAction) {
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventBranchProtection, &api.BranchProtectionPayload{
+		Action:     action,
+		Rule:       convert.ToBranchProtection(ctx, rule),
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+	notifyMember(ctx, doer, repo, collaborator, api.HookMemberAdded)
+}
+
+func (m *webhookNotifier) RemoveCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) {
+	notifyMember(ctx, doer, repo, collaborator, api.HookMemberRemoved)
+}
+
+func notifyMember(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, member *user_model.User, action api.HookMemberAction) {
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventMember, &api.MemberPayload{
+		Action:     action,
+		Member:     convert.ToUser(ctx, member, nil),
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) AddTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+	notifyMembership(ctx, doer, team, member, api.HookMemberAdded)
+}
+
+func (m *webhookNotifier) RemoveTeamMember(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User) {
+	notifyMembership(ctx, doer, team, member, api.HookMemberRemoved)
+}
+
+func notifyMembership(ctx context.Context, doer *user_model.User, team *organization.Team, member *user_model.User, action api.HookMemberAction) {
+	org, err := user_model.GetUserByID(ctx, team.OrgID)
+	if err != nil {
+		log.Error("GetUserByID: %v", err)
+		return
+	}
+	apiTeam, err := convert.ToTeam(ctx, team)
+	if err != nil {
+		log.Error("ToTeam: %v", err)
+		return
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Owner: org}, webhook_module.HookEventMembership, &api.MembershipPayload{
+		Action:       action,
+		Member:       convert.ToUser(ctx, member, nil),
+		Team:         apiTeam,
+		Organization: convert.ToUser(ctx, org, nil),
+		Sender:       convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) AddDeployKey(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, key *asymkey_model.DeployKey) {
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventDeployKey, &api.DeployKeyPayload{
+		Action:     api.HookDeployKeyCreated,
+		Key:        convert.ToDeployKey(repo.APIURL()+"/keys/", key),
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) ChangeRepositoryVisibility(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) {
+	action := api.HookRepoPublicized
+	if repo.IsPrivate {
+		action = api.HookRepoPrivatized
+	}
+
+	if err := PrepareWebhooks(ctx, EventSource{Repository: repo}, webhook_module.HookEventRepositoryVisibility, &api.RepositoryPayload{
+		Action:       action,
+		Repository:   convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
+		Organization: convert.ToUser(ctx, repo.MustOwner(ctx), nil),
+		Sender:       convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
@@ -108,6 +108,41 @@ func (f *PackagistPayload) Package(_ *api.PackagePayload) (api.Payloader, error)
 	return nil, nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (f *PackagistPayload) WorkflowRun(_ *api.WorkflowRunPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (f *PackagistPayload) WorkflowJob(_ *api.WorkflowJobPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (f *PackagistPayload) BranchProtection(_ *api.BranchProtectionPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// Member implements PayloadConvertor Member method
+func (f *PackagistPayload) Member(_ *api.MemberPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (f *PackagistPayload) Membership(_ *api.MembershipPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (f *PackagistPayload) DeployKey(_ *api.DeployKeyPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (f *PackagistPayload) RepositoryVisibility(_ *api.RepositoryPayload) (api.Payloader, error) {
+	return nil, nil
+}
+
 // GetPackagistPayload converts a packagist webhook into a PackagistPayload
 func GetPackagistPayload(p api.Payloader, event webhook_module.HookEventType, meta string) (api.Payloader, error) {
 	s := new(PackagistPayload)
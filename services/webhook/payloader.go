@@ -23,6 +23,13 @@ type PayloadConvertor interface {
 	Release(*api.ReleasePayload) (api.Payloader, error)
 	Wiki(*api.WikiPayload) (api.Payloader, error)
 	Package(*api.PackagePayload) (api.Payloader, error)
+	WorkflowRun(*api.WorkflowRunPayload) (api.Payloader, error)
+	WorkflowJob(*api.WorkflowJobPayload) (api.Payloader, error)
+	BranchProtection(*api.BranchProtectionPayload) (api.Payloader, error)
+	Member(*api.MemberPayload) (api.Payloader, error)
+	Membership(*api.MembershipPayload) (api.Payloader, error)
+	DeployKey(*api.DeployKeyPayload) (api.Payloader, error)
+	RepositoryVisibility(*api.RepositoryPayload) (api.Payloader, error)
 }
 
 func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_module.HookEventType) (api.Payloader, error) {
@@ -56,6 +63,20 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_module.
 		return s.Wiki(p.(*api.WikiPayload))
 	case webhook_module.HookEventPackage:
 		return s.Package(p.(*api.PackagePayload))
+	case webhook_module.HookEventWorkflowRun:
+		return s.WorkflowRun(p.(*api.WorkflowRunPayload))
+	case webhook_module.HookEventWorkflowJob:
+		return s.WorkflowJob(p.(*api.WorkflowJobPayload))
+	case webhook_module.HookEventBranchProtection:
+		return s.BranchProtection(p.(*api.BranchProtectionPayload))
+	case webhook_module.HookEventMember:
+		return s.Member(p.(*api.MemberPayload))
+	case webhook_module.HookEventMembership:
+		return s.Membership(p.(*api.MembershipPayload))
+	case webhook_module.HookEventDeployKey:
+		return s.DeployKey(p.(*api.DeployKeyPayload))
+	case webhook_module.HookEventRepositoryVisibility:
+		return s.RepositoryVisibility(p.(*api.RepositoryPayload))
 	}
 	return s, nil
 }
@@ -0,0 +1,241 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+const (
+	// retryPollInterval is how often the due retries are pushed to the sending queue
+	retryPollInterval = 10 * time.Second
+	// retryPollBatchSize is the number of due retries pushed at once to the sending queue
+	retryPollBatchSize = 100
+	// retryQueuedTimeout is how long a retry pushed to the sending queue waits to be delivered before it is pushed again
+	retryQueuedTimeout = 10 * time.Minute
+)
+
+// retryInterval returns the delay before the given retry of a failed delivery, the delay doubles for each retry up
+// to MaxRetryInterval, 0 doesn't limit the delay
+func retryInterval(attempt int) time.Duration {
+	maxInterval := setting.Webhook.MaxRetryInterval
+	interval := setting.Webhook.RetryInterval
+	for i := 1; i < attempt && (maxInterval <= 0 || interval < maxInterval) && interval <= math.MaxInt64/2; i++ {
+		interval *= 2
+	}
+	if maxInterval > 0 && interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
+// isRetryable returns whether a failed delivery may succeed later: the receiver couldn't be reached, it failed or
+// it asked to try again
+func isRetryable(t *webhook_model.HookTask) bool {
+	if t.ResponseInfo == nil || t.ResponseInfo.Status == 0 {
+		return true
+	}
+	status := t.ResponseInfo.Status
+	return status >= http.StatusInternalServerError || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
+
+// handleDeliveryResult counts the consecutive failed deliveries of a webhook. A failed delivery is retried later,
+// unless the webhook failed too many times in a row, then it is disabled and its owners are notified.
+// Only the first deliveries are counted, the retries of a failed delivery don't count as more failures.
+func handleDeliveryResult(ctx context.Context, w *webhook_model.Webhook, t *webhook_model.HookTask) error {
+	if t.IsSucceed {
+		return webhook_model.ResetWebhookFailureCount(ctx, w)
+	}
+	if t.Attempt > 0 {
+		return scheduleRetry(ctx, t)
+	}
+	if err := webhook_model.IncreaseWebhookFailureCount(ctx, w); err != nil {
+		return err
+	}
+
+	if setting.Webhook.DisableAfterFailures > 0 && w.FailureCount >= setting.Webhook.DisableAfterFailures {
+		disabled, err := webhook_model.DisableWebhook(ctx, w)
+		if err != nil || !disabled {
+			return err
+		}
+		log.Warn("Webhook[%d] has been disabled after %d consecutive failed deliveries", w.ID, w.FailureCount)
+		return notifyWebhookDisabled(ctx, w)
+	}
+	return scheduleRetry(ctx, t)
+}
+
+// scheduleRetry creates the next retry of a failed delivery if it may succeed later and the retries aren't exhausted
+func scheduleRetry(ctx context.Context, t *webhook_model.HookTask) error {
+	if t.Attempt >= setting.Webhook.MaxRetries || !isRetryable(t) {
+		return nil
+	}
+	retryAt := timeutil.TimeStamp(time.Now().Add(retryInterval(t.Attempt + 1)).Unix())
+	retry, err := webhook_model.CreateRetryHookTask(ctx, t, retryAt)
+	if err != nil {
+		return err
+	}
+	log.Trace("Webhook Task[%d] failed, retry %d as Task[%d] at %v", t.ID, retry.Attempt, retry.ID, retryAt.AsTime())
+	return nil
+}
+
+// pollRetryHookTasks pushes the retries to the sending queue once they are due
+func pollRetryHookTasks(ctx context.Context) {
+	ctx, _, finished := process.GetManager().AddContext(ctx, "Webhook: Retry failed deliveries")
+	defer finished()
+
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := pushDueRetryHookTasks(ctx); err != nil {
+			log.Error("Unable to push the due retries to the Webhook Sending queue: %v", err)
+		}
+	}
+}
+
+// pushDueRetryHookTasks pushes the due retries to the sending queue by batches, the pushed retries are postponed
+// so they aren't pushed again by the next polls while they wait in the queue
+func pushDueRetryHookTasks(ctx context.Context) error {
+	for {
+		taskIDs, err := webhook_model.FindDueRetryHookTaskIDs(ctx, retryPollBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(taskIDs) == 0 {
+			return nil
+		}
+		queuedUntil := timeutil.TimeStamp(time.Now().Add(retryQueuedTimeout).Unix())
+		if err := webhook_model.PostponeRetryHookTasks(ctx, taskIDs, queuedUntil); err != nil {
+			return err
+		}
+		for _, taskID := range taskIDs {
+			if err := enqueueHookTask(taskID); err != nil {
+				log.Error("Unable to push HookTask[%d] to the Webhook Sending queue: %v", taskID, err)
+			}
+		}
+		if len(taskIDs) < retryPollBatchSize {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// SettingsLink returns the name of the repository or the owner of a webhook, empty for system and default
+// webhooks, and the link of its settings relative to the root of the instance
+func SettingsLink(ctx context.Context, w *webhook_model.Webhook) (name, link string, err error) {
+	if w.RepoID > 0 {
+		repo, err := repo_model.GetRepositoryByID(ctx, w.RepoID)
+		if err != nil {
+			return "", "", err
+		}
+		return repo.FullName(), fmt.Sprintf("%s/%s/settings/hooks/%d", url.PathEscape(repo.OwnerName), url.PathEscape(repo.Name), w.ID), nil
+	}
+	if w.OwnerID > 0 {
+		owner, err := user_model.GetUserByID(ctx, w.OwnerID)
+		if err != nil {
+			return "", "", err
+		}
+		if owner.IsOrganization() {
+			return owner.Name, fmt.Sprintf("org/%s/settings/hooks/%d", url.PathEscape(owner.Name), w.ID), nil
+		}
+		return owner.Name, fmt.Sprintf("user/settings/hooks/%d", w.ID), nil
+	}
+	return "", fmt.Sprintf("admin/hooks/%d", w.ID), nil
+}
+
+// webhookOwnerIDs returns the ids of the users who manage a webhook: the owner of its repository or the owner of
+// the webhook, the owners of an organization, or the site administrators for system and default webhooks
+func webhookOwnerIDs(ctx context.Context, w *webhook_model.Webhook) ([]int64, error) {
+	ownerID := w.OwnerID
+	if w.RepoID > 0 {
+		repo, err := repo_model.GetRepositoryByID(ctx, w.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		ownerID = repo.OwnerID
+	}
+
+	if ownerID == 0 {
+		admins, _, err := user_model.SearchUsers(ctx, &user_model.SearchUserOptions{
+			ListOptions: db.ListOptions{ListAll: true},
+			Type:        user_model.UserTypeIndividual,
+			IsActive:    util.OptionalBoolTrue,
+			IsAdmin:     util.OptionalBoolTrue,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(admins))
+		for _, admin := range admins {
+			ids = append(ids, admin.ID)
+		}
+		return ids, nil
+	}
+
+	owner, err := user_model.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !owner.IsOrganization() {
+		return []int64{owner.ID}, nil
+	}
+	team, err := organization.GetOwnerTeam(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	teamUsers, err := organization.GetTeamUsersByTeamID(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(container.Set[int64], len(teamUsers))
+	for _, tu := range teamUsers {
+		ids.Add(tu.UID)
+	}
+	return ids.Values(), nil
+}
+
+// notifyWebhookDisabled emails the owners of a webhook which has been disabled after too many failed deliveries
+func notifyWebhookDisabled(ctx context.Context, w *webhook_model.Webhook) error {
+	name, link, err := SettingsLink(ctx, w)
+	if err != nil {
+		return err
+	}
+	ids, err := webhookOwnerIDs(ctx, w)
+	if err != nil {
+		return err
+	}
+	recipients, err := user_model.GetMaileableUsersByIDs(ctx, ids, false)
+	if err != nil {
+		return err
+	}
+	return mailer.MailWebhookDisabled(ctx, w, name, setting.AppURL+link, recipients)
+}
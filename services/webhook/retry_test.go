@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryInterval(t *testing.T) {
+	defer test.MockVariableValue(&setting.Webhook.RetryInterval, time.Minute)()
+	defer test.MockVariableValue(&setting.Webhook.MaxRetryInterval, 5*time.Minute)()
+
+	assert.Equal(t, time.Minute, retryInterval(1))
+	assert.Equal(t, 2*time.Minute, retryInterval(2))
+	assert.Equal(t, 4*time.Minute, retryInterval(3))
+	assert.Equal(t, 5*time.Minute, retryInterval(4))
+	assert.Equal(t, 5*time.Minute, retryInterval(20))
+
+	// 0 doesn't limit the delay
+	setting.Webhook.MaxRetryInterval = 0
+	assert.Equal(t, time.Minute, retryInterval(1))
+	assert.Equal(t, 2*time.Minute, retryInterval(2))
+	assert.Equal(t, 16*time.Minute, retryInterval(5))
+	assert.Positive(t, retryInterval(100))
+}
+
+func TestWebhookDeliverRetries(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Webhook.MaxRetries, 1)()
+	defer test.MockVariableValue(&setting.Webhook.RetryInterval, time.Hour)()
+	defer test.MockVariableValue(&setting.Webhook.DisableAfterFailures, 3)()
+
+	status := http.StatusServiceUnavailable
+	var deliveryUUID string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveryUUID = r.Header.Get("X-Gitea-Delivery")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	hook := &webhook_model.Webhook{
+		RepoID:      3,
+		URL:         s.URL + "/webhook",
+		ContentType: webhook_model.ContentTypeJSON,
+		IsActive:    true,
+		Type:        webhook_module.GITEA,
+	}
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, hook))
+
+	deliver := func(task *webhook_model.HookTask) {
+		assert.NoError(t, Deliver(context.Background(), task))
+		assert.False(t, task.IsSucceed)
+	}
+	lastRetry := func() *webhook_model.HookTask {
+		retries, _, err := webhook_model.FindPendingRetryHookTasks(db.DefaultContext, db.ListOptions{})
+		assert.NoError(t, err)
+		var last *webhook_model.HookTask
+		for _, retry := range retries {
+			if retry.HookID == hook.ID {
+				last = retry
+			}
+		}
+		return last
+	}
+
+	task, err := webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{HookID: hook.ID, EventType: webhook_module.HookEventPush, Payloader: &api.PushPayload{}})
+	assert.NoError(t, err)
+	deliver(task)
+	assert.Equal(t, 1, unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{ID: hook.ID}).FailureCount)
+
+	// the failed delivery is retried later
+	retry := lastRetry()
+	if assert.NotNil(t, retry) {
+		assert.Equal(t, 1, retry.Attempt)
+		assert.Equal(t, task.PayloadContent, retry.PayloadContent)
+		assert.Greater(t, int64(retry.RetryUnix), time.Now().Add(59*time.Minute).Unix())
+
+		// the last retry isn't retried, it is sent with the id of the first delivery and isn't counted as a failure
+		deliver(retry)
+		assert.Nil(t, lastRetry())
+		assert.Equal(t, task.UUID, deliveryUUID)
+	}
+	assert.Equal(t, 1, unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{ID: hook.ID}).FailureCount)
+
+	// a successful delivery resets the failures
+	status = http.StatusOK
+	task, err = webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{HookID: hook.ID, EventType: webhook_module.HookEventPush, Payloader: &api.PushPayload{}})
+	assert.NoError(t, err)
+	assert.NoError(t, Deliver(context.Background(), task))
+	assert.True(t, task.IsSucceed)
+	assert.Equal(t, 0, unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{ID: hook.ID}).FailureCount)
+
+	// client errors aren't retried and the webhook is disabled after too many failures
+	status = http.StatusNotFound
+	for i := 0; i < 3; i++ {
+		task, err = webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{HookID: hook.ID, EventType: webhook_module.HookEventPush, Payloader: &api.PushPayload{}})
+		assert.NoError(t, err)
+		deliver(task)
+		assert.Nil(t, lastRetry())
+	}
+	hook = unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{ID: hook.ID})
+	assert.Equal(t, 3, hook.FailureCount)
+	assert.False(t, hook.IsActive)
+}
@@ -0,0 +1,293 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// SampleEvents are the events which have a sample payload, in the order they are offered for a preview
+var SampleEvents = []webhook_module.HookEventType{
+	webhook_module.HookEventPush,
+	webhook_module.HookEventCreate,
+	webhook_module.HookEventDelete,
+	webhook_module.HookEventFork,
+	webhook_module.HookEventIssues,
+	webhook_module.HookEventIssueComment,
+	webhook_module.HookEventPullRequest,
+	webhook_module.HookEventPullRequestReviewApproved,
+	webhook_module.HookEventWiki,
+	webhook_module.HookEventRepository,
+	webhook_module.HookEventRelease,
+	webhook_module.HookEventPackage,
+	webhook_module.HookEventWorkflowRun,
+	webhook_module.HookEventWorkflowJob,
+	webhook_module.HookEventBranchProtection,
+	webhook_module.HookEventMember,
+	webhook_module.HookEventMembership,
+	webhook_module.HookEventDeployKey,
+	webhook_module.HookEventRepositoryVisibility,
+}
+
+// SamplePayload returns an example of the payload sent for an event, it is used to check and preview the
+// templates of custom webhooks
+func SamplePayload(event webhook_module.HookEventType) api.Payloader {
+	created := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+	sender := &api.User{
+		ID:        1,
+		UserName:  "octo",
+		FullName:  "Octo Cat",
+		Email:     "octo@example.com",
+		AvatarURL: setting.AppURL + "avatars/octo",
+	}
+	member := &api.User{
+		ID:        2,
+		UserName:  "hubot",
+		FullName:  "Hubot",
+		Email:     "hubot@example.com",
+		AvatarURL: setting.AppURL + "avatars/hubot",
+	}
+	repo := &api.Repository{
+		ID:            1,
+		Owner:         sender,
+		Name:          "example",
+		FullName:      "octo/example",
+		Description:   "An example repository",
+		HTMLURL:       setting.AppURL + "octo/example",
+		URL:           setting.AppURL + "api/v1/repos/octo/example",
+		CloneURL:      setting.AppURL + "octo/example.git",
+		DefaultBranch: "main",
+	}
+	commit := &api.PayloadCommit{
+		ID:        "2e1cbf9a4d6b9e3a4d5c7f0e8b1a2c3d4e5f6a7b",
+		Message:   "Fix the example\n",
+		URL:       repo.HTMLURL + "/commit/2e1cbf9a4d6b9e3a4d5c7f0e8b1a2c3d4e5f6a7b",
+		Author:    &api.PayloadUser{Name: sender.FullName, Email: sender.Email, UserName: sender.UserName},
+		Committer: &api.PayloadUser{Name: sender.FullName, Email: sender.Email, UserName: sender.UserName},
+		Timestamp: created,
+		Modified:  []string{"README.md"},
+	}
+	issue := &api.Issue{
+		ID:      1,
+		URL:     repo.URL + "/issues/1",
+		HTMLURL: repo.HTMLURL + "/issues/1",
+		Index:   1,
+		Poster:  sender,
+		Title:   "An example issue",
+		Body:    "The description of the issue",
+		Labels:  []*api.Label{{ID: 1, Name: "bug", Color: "ee0701"}},
+		State:   api.StateOpen,
+		Created: created,
+		Updated: created,
+		Repo:    &api.RepositoryMeta{ID: repo.ID, Name: repo.Name, Owner: sender.UserName, FullName: repo.FullName},
+	}
+	pr := &api.PullRequest{
+		ID:      2,
+		URL:     repo.URL + "/pulls/2",
+		Index:   2,
+		Poster:  sender,
+		Title:   "An example pull request",
+		Body:    "The description of the pull request",
+		State:   api.StateOpen,
+		HTMLURL: repo.HTMLURL + "/pulls/2",
+		Base:    &api.PRBranchInfo{Name: "main", Ref: "main", Sha: commit.ID, RepoID: repo.ID, Repository: repo},
+		Head:    &api.PRBranchInfo{Name: "feature", Ref: "feature", Sha: commit.ID, RepoID: repo.ID, Repository: repo},
+		Created: &created,
+		Updated: &created,
+	}
+
+	switch event {
+	case webhook_module.HookEventPush:
+		return &api.PushPayload{
+			Ref:          "refs/heads/main",
+			Before:       "0b6a4f1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+			After:        commit.ID,
+			CompareURL:   repo.HTMLURL + "/compare/0b6a4f1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a..." + commit.ID,
+			Commits:      []*api.PayloadCommit{commit},
+			TotalCommits: 1,
+			HeadCommit:   commit,
+			Repo:         repo,
+			Pusher:       sender,
+			Sender:       sender,
+		}
+	case webhook_module.HookEventCreate:
+		return &api.CreatePayload{Sha: commit.ID, Ref: "feature", RefType: "branch", Repo: repo, Sender: sender}
+	case webhook_module.HookEventDelete:
+		return &api.DeletePayload{Ref: "feature", RefType: "branch", PusherType: api.PusherTypeUser, Repo: repo, Sender: sender}
+	case webhook_module.HookEventFork:
+		forkee := *repo
+		forkee.ID = 2
+		forkee.FullName = "octo/example-fork"
+		forkee.Name = "example-fork"
+		forkee.Fork = true
+		forkee.Parent = repo
+		return &api.ForkPayload{Forkee: &forkee, Repo: repo, Sender: sender}
+	case webhook_module.HookEventIssues, webhook_module.HookEventIssueAssign, webhook_module.HookEventIssueLabel,
+		webhook_module.HookEventIssueMilestone:
+		return &api.IssuePayload{Action: api.HookIssueOpened, Index: issue.Index, Issue: issue, Repository: repo, Sender: sender}
+	case webhook_module.HookEventIssueComment, webhook_module.HookEventPullRequestComment:
+		return &api.IssueCommentPayload{
+			Action: api.HookIssueCommentCreated,
+			Issue:  issue,
+			Comment: &api.Comment{
+				ID:       1,
+				HTMLURL:  issue.HTMLURL + "#issuecomment-1",
+				IssueURL: issue.HTMLURL,
+				Poster:   sender,
+				Body:     "An example comment",
+				Created:  created,
+				Updated:  created,
+			},
+			Repository: repo,
+			Sender:     sender,
+			IsPull:     event == webhook_module.HookEventPullRequestComment,
+		}
+	case webhook_module.HookEventPullRequest, webhook_module.HookEventPullRequestAssign, webhook_module.HookEventPullRequestLabel,
+		webhook_module.HookEventPullRequestMilestone, webhook_module.HookEventPullRequestSync, webhook_module.HookEventPullRequestReviewRequest:
+		return &api.PullRequestPayload{Action: api.HookIssueOpened, Index: pr.Index, PullRequest: pr, Repository: repo, Sender: sender}
+	case webhook_module.HookEventPullRequestReviewApproved, webhook_module.HookEventPullRequestReviewRejected,
+		webhook_module.HookEventPullRequestReviewComment:
+		return &api.PullRequestPayload{
+			Action:      api.HookIssueReviewed,
+			Index:       pr.Index,
+			PullRequest: pr,
+			Repository:  repo,
+			Sender:      sender,
+			Review:      &api.ReviewPayload{Type: string(event), Content: "An example review"},
+		}
+	case webhook_module.HookEventWiki:
+		return &api.WikiPayload{Action: api.HookWikiEdited, Repository: repo, Sender: sender, Page: "Home", Comment: "Update the home page"}
+	case webhook_module.HookEventRepository:
+		return &api.RepositoryPayload{Action: api.HookRepoCreated, Repository: repo, Organization: sender, Sender: sender}
+	case webhook_module.HookEventRelease:
+		return &api.ReleasePayload{
+			Action: api.HookReleasePublished,
+			Release: &api.Release{
+				ID:          1,
+				TagName:     "v1.0.0",
+				Target:      "main",
+				Title:       "v1.0.0",
+				Note:        "The notes of the release",
+				URL:         repo.URL + "/releases/1",
+				HTMLURL:     repo.HTMLURL + "/releases/tag/v1.0.0",
+				CreatedAt:   created,
+				PublishedAt: created,
+				Publisher:   sender,
+			},
+			Repository: repo,
+			Sender:     sender,
+		}
+	case webhook_module.HookEventPackage:
+		return &api.PackagePayload{
+			Action: api.HookPackageCreated,
+			Package: &api.Package{
+				ID:         1,
+				Owner:      sender,
+				Repository: repo,
+				Creator:    sender,
+				Type:       "generic",
+				Name:       "example",
+				Version:    "1.0.0",
+				HTMLURL:    setting.AppURL + "octo/-/packages/generic/example/1.0.0",
+				CreatedAt:  created,
+			},
+			Organization: sender,
+			Sender:       sender,
+		}
+	case webhook_module.HookEventWorkflowRun:
+		return &api.WorkflowRunPayload{
+			Action: api.HookWorkflowCompleted,
+			WorkflowRun: &api.ActionWorkflowRun{
+				ID:           1,
+				RunNumber:    1,
+				WorkflowID:   "build.yml",
+				DisplayTitle: "Fix the example",
+				Event:        "push",
+				HeadBranch:   "main",
+				HeadSha:      commit.ID,
+				Status:       "completed",
+				Conclusion:   "success",
+				HTMLURL:      repo.HTMLURL + "/actions/runs/1",
+				Actor:        sender,
+				Created:      created,
+				Updated:      created,
+				Started:      &created,
+				Completed:    &created,
+			},
+			Repository: repo,
+			Sender:     sender,
+		}
+	case webhook_module.HookEventWorkflowJob:
+		return &api.WorkflowJobPayload{
+			Action: api.HookWorkflowCompleted,
+			WorkflowJob: &api.ActionWorkflowJob{
+				ID:         1,
+				RunID:      1,
+				RunNumber:  1,
+				RunAttempt: 1,
+				Name:       "build",
+				HeadBranch: "main",
+				HeadSha:    commit.ID,
+				Status:     "completed",
+				Conclusion: "success",
+				HTMLURL:    repo.HTMLURL + "/actions/runs/1/jobs/0",
+				Labels:     []string{"ubuntu-latest"},
+				RunnerID:   1,
+				RunnerName: "runner",
+				Created:    created,
+				Started:    &created,
+				Completed:  &created,
+			},
+			Repository: repo,
+			Sender:     sender,
+		}
+	case webhook_module.HookEventBranchProtection:
+		return &api.BranchProtectionPayload{
+			Action: api.HookBranchProtectionCreated,
+			Rule: &api.BranchProtection{
+				RuleName:          "main",
+				BranchName:        "main",
+				EnablePush:        true,
+				RequiredApprovals: 1,
+				Created:           created,
+				Updated:           created,
+			},
+			Repository: repo,
+			Sender:     sender,
+		}
+	case webhook_module.HookEventMember:
+		return &api.MemberPayload{Action: api.HookMemberAdded, Member: member, Repository: repo, Sender: sender}
+	case webhook_module.HookEventMembership:
+		return &api.MembershipPayload{
+			Action:       api.HookMemberAdded,
+			Member:       member,
+			Team:         &api.Team{ID: 1, Name: "Developers", Permission: "write"},
+			Organization: sender,
+			Sender:       sender,
+		}
+	case webhook_module.HookEventDeployKey:
+		return &api.DeployKeyPayload{
+			Action: api.HookDeployKeyCreated,
+			Key: &api.DeployKey{
+				ID:          1,
+				KeyID:       1,
+				Key:         "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample",
+				Fingerprint: "SHA256:Example",
+				URL:         repo.URL + "/keys/1",
+				Title:       "deploy",
+				Created:     created,
+				ReadOnly:    true,
+			},
+			Repository: repo,
+			Sender:     sender,
+		}
+	case webhook_module.HookEventRepositoryVisibility:
+		return &api.RepositoryPayload{Action: api.HookRepoPublicized, Repository: repo, Organization: sender, Sender: sender}
+	}
+	return nil
+}
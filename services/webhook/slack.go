@@ -177,6 +177,55 @@ func (s *SlackPayload) Package(p *api.PackagePayload) (api.Payloader, error) {
 	return s.createPayload(text, nil), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (s *SlackPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (s *SlackPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, _ := getWorkflowJobPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (s *SlackPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, _ := getBranchProtectionPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (s *SlackPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, _ := getMemberPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (s *SlackPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, _ := getMembershipPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (s *SlackPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (s *SlackPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, _ := getRepositoryVisibilityPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (s *SlackPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	// n new commits
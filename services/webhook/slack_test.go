@@ -156,6 +156,30 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "Package created: <http://localhost:3000/user1/-/packages/container/GiteaContainer/latest|GiteaContainer:latest> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 	})
 
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.WorkflowRun(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow run <http://localhost:3000/test/repo/actions/runs/3|build.yml #3> succeeded: Fix the build by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
+	t.Run("WorkflowJob", func(t *testing.T) {
+		p := workflowJobTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.WorkflowJob(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow job <http://localhost:3000/test/repo/actions/runs/3/jobs/0|test> of run #3 failed by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
 	t.Run("Wiki", func(t *testing.T) {
 		p := wikiTestPayload()
 
@@ -192,6 +192,55 @@ func (t *TelegramPayload) Package(p *api.PackagePayload) (api.Payloader, error)
 	return createTelegramPayload(text), nil
 }
 
+// WorkflowRun implements PayloadConvertor WorkflowRun method
+func (t *TelegramPayload) WorkflowRun(p *api.WorkflowRunPayload) (api.Payloader, error) {
+	text, _ := getWorkflowRunPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// WorkflowJob implements PayloadConvertor WorkflowJob method
+func (t *TelegramPayload) WorkflowJob(p *api.WorkflowJobPayload) (api.Payloader, error) {
+	text, _ := getWorkflowJobPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// BranchProtection implements PayloadConvertor BranchProtection method
+func (t *TelegramPayload) BranchProtection(p *api.BranchProtectionPayload) (api.Payloader, error) {
+	text, _ := getBranchProtectionPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// Member implements PayloadConvertor Member method
+func (t *TelegramPayload) Member(p *api.MemberPayload) (api.Payloader, error) {
+	text, _ := getMemberPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// Membership implements PayloadConvertor Membership method
+func (t *TelegramPayload) Membership(p *api.MembershipPayload) (api.Payloader, error) {
+	text, _ := getMembershipPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (t *TelegramPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// RepositoryVisibility implements PayloadConvertor RepositoryVisibility method
+func (t *TelegramPayload) RepositoryVisibility(p *api.RepositoryPayload) (api.Payloader, error) {
+	text, _ := getRepositoryVisibilityPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
 // GetTelegramPayload converts a telegram webhook into a TelegramPayload
 func GetTelegramPayload(p api.Payloader, event webhook_module.HookEventType, _ string) (api.Payloader, error) {
 	return convertPayloader(new(TelegramPayload), p, event)
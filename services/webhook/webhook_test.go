@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
 	webhook_model "code.gitea.io/gitea/models/webhook"
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
@@ -81,3 +83,51 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks
+
+func TestNotifyMembership(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	member := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	orgHook := &webhook_model.Webhook{
+		OwnerID:     team.OrgID,
+		URL:         "http://localhost/org",
+		ContentType: webhook_model.ContentTypeJSON,
+		IsActive:    true,
+		Type:        webhook_module.GITEA,
+		HookEvent: &webhook_module.HookEvent{
+			ChooseEvents: true,
+			HookEvents:   webhook_module.HookEvents{Membership: true},
+		},
+	}
+	assert.NoError(t, orgHook.UpdateEvent())
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, orgHook))
+	systemHook := &webhook_model.Webhook{
+		URL:             "http://localhost/system",
+		ContentType:     webhook_model.ContentTypeJSON,
+		IsActive:        true,
+		IsSystemWebhook: true,
+		Type:            webhook_module.GITEA,
+		HookEvent: &webhook_module.HookEvent{
+			ChooseEvents: true,
+			HookEvents:   webhook_module.HookEvents{Member: true},
+		},
+	}
+	assert.NoError(t, systemHook.UpdateEvent())
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, systemHook))
+
+	(&webhookNotifier{}).AddTeamMember(db.DefaultContext, doer, team, member)
+
+	task := unittest.AssertExistsAndLoadBean(t, &webhook_model.HookTask{HookID: orgHook.ID, EventType: webhook_module.HookEventMembership})
+	assert.Contains(t, task.PayloadContent, `"action": "added"`)
+	assert.Contains(t, task.PayloadContent, `"login": "user4"`)
+	// the system webhook doesn't subscribe to team membership events
+	unittest.AssertNotExistsBean(t, &webhook_model.HookTask{HookID: systemHook.ID})
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	(&webhookNotifier{}).RemoveCollaborator(db.DefaultContext, doer, repo, member)
+	task = unittest.AssertExistsAndLoadBean(t, &webhook_model.HookTask{HookID: systemHook.ID, EventType: webhook_module.HookEventMember})
+	assert.Contains(t, task.PayloadContent, `"action": "removed"`)
+}
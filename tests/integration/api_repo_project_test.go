@@ -0,0 +1,112 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoProjectFields(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteIssue)
+	urlPrefix := "/api/v1/repos/user2/repo1/projects"
+
+	req := NewRequest(t, "GET", fmt.Sprintf("%s?token=%s", urlPrefix, token))
+	resp := MakeRequest(t, req, http.StatusOK)
+	var projects []*api.Project
+	DecodeJSON(t, resp, &projects)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, 1, projects[0].ID)
+		assert.Equal(t, api.StateOpen, projects[0].State)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/1/fields?token=%s", urlPrefix, token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var fields []*api.ProjectField
+	DecodeJSON(t, resp, &fields)
+	if assert.Len(t, fields, 2) {
+		assert.Equal(t, "single_select", fields[0].Type)
+		assert.Len(t, fields[0].Options, 3)
+	}
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/1/fields?token=%s", urlPrefix, token), &api.CreateProjectFieldOption{
+		Name: "Due",
+		Type: "date",
+	})
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var field api.ProjectField
+	DecodeJSON(t, resp, &field)
+	assert.Equal(t, "Due", field.Name)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/1/fields?token=%s", urlPrefix, token), &api.CreateProjectFieldOption{
+		Name: "Due",
+		Type: "text",
+	})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// set the values of the issue #1
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s/1/items/1/fields/%d?token=%s", urlPrefix, field.ID, token), &api.SetProjectFieldValueOption{
+		Value: "2023-10-01",
+	})
+	resp = MakeRequest(t, req, http.StatusOK)
+	var value api.ProjectFieldValue
+	DecodeJSON(t, resp, &value)
+	assert.Equal(t, "2023-10-01", value.Value)
+
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s/1/items/1/fields/1?token=%s", urlPrefix, token), &api.SetProjectFieldValueOption{
+		Value: "42",
+	})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("%s/1/items/1/fields/1?token=%s", urlPrefix, token), &api.SetProjectFieldValueOption{
+		Value: "3",
+	})
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &value)
+	assert.Equal(t, "Done", value.DisplayValue)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/1/items?filter_field=1&filter_value=done&token=%s", urlPrefix, token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var items []*api.ProjectItem
+	DecodeJSON(t, resp, &items)
+	if assert.Len(t, items, 1) {
+		assert.EqualValues(t, 1, items[0].Issue.Index)
+		assert.Len(t, items[0].FieldValues, 3)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/1/items?sort_field=2&sort_order=desc&token=%s", urlPrefix, token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &items)
+	if assert.Len(t, items, 4) {
+		// the estimates are 5 for the issue #3 and 3 for the issue #1
+		assert.EqualValues(t, 3, items[0].Issue.Index)
+		assert.EqualValues(t, 1, items[1].Issue.Index)
+	}
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/1/items/1/fields/%d?token=%s", urlPrefix, field.ID, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &project_model.FieldValue{FieldID: field.ID, IssueID: 1})
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/1/fields/1?token=%s", urlPrefix, token))
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &project_model.FieldValue{FieldID: 1})
+
+	// the fields of a project can only be changed by the writers of the projects
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteIssue)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/1/fields/2?token=%s", urlPrefix, token))
+	MakeRequest(t, req, http.StatusForbidden)
+}
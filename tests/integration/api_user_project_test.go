@@ -0,0 +1,57 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserProjectFields(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteUser)
+	urlPrefix := "/api/v1/users/user2/projects"
+
+	req := NewRequest(t, "GET", fmt.Sprintf("%s/4?token=%s", urlPrefix, token))
+	resp := MakeRequest(t, req, http.StatusOK)
+	var project api.Project
+	DecodeJSON(t, resp, &project)
+	assert.EqualValues(t, 4, project.ID)
+
+	// the projects of the repositories are not the projects of their owner
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/1?token=%s", urlPrefix, token))
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/4/fields?token=%s", urlPrefix, token), &api.CreateProjectFieldOption{
+		Name: "Due",
+		Type: "date",
+	})
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var field api.ProjectField
+	DecodeJSON(t, resp, &field)
+	assert.Equal(t, "Due", field.Name)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/4/fields?token=%s", urlPrefix, token))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var fields []*api.ProjectField
+	DecodeJSON(t, resp, &fields)
+	if assert.Len(t, fields, 1) {
+		assert.Equal(t, field.ID, fields[0].ID)
+	}
+
+	// only the owner can change the fields of the projects of a user
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteUser)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/4/fields/%d?token=%s", urlPrefix, field.ID, token))
+	MakeRequest(t, req, http.StatusForbidden)
+}
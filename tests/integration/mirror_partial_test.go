@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/migrations"
+	mirror_service "code.gitea.io/gitea/services/mirror"
+	repo_service "code.gitea.io/gitea/services/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorPartial(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		setting.Migrations.AllowLocalNetworks = true
+		assert.NoError(t, migrations.Init())
+
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		srcRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user, user, repo_service.CreateRepoOptions{
+			Name:          "test-partial-mirror",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "main",
+		})
+		assert.NoError(t, err)
+
+		session := loginUser(t, user.Name)
+		link := fmt.Sprintf("/%s/%s/settings", user.Name, repo.Name)
+		req := NewRequestWithValues(t, "POST", link, map[string]string{
+			"_csrf":                     GetCSRF(t, session, link),
+			"action":                    "partial-mirror-add",
+			"partial_mirror_address":    fmt.Sprintf("%s%s/%s", u.String(), url.PathEscape(user.Name), url.PathEscape(srcRepo.Name)),
+			"partial_mirror_username":   user.Name,
+			"partial_mirror_password":   userPassword,
+			"partial_mirror_branches":   "master:vendor/master\ndevelop",
+			"partial_mirror_divergence": string(repo_model.DivergencePullRequest),
+			"partial_mirror_interval":   "0",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		mirrors, err := repo_model.GetPartialMirrorsByRepoID(db.DefaultContext, repo.ID)
+		assert.NoError(t, err)
+		assert.Len(t, mirrors, 1)
+		m := mirrors[0]
+		assert.True(t, mirror_service.SyncPartialMirror(context.Background(), m.ID))
+
+		srcGitRepo, err := git.OpenRepository(git.DefaultContext, srcRepo.RepoPath())
+		assert.NoError(t, err)
+		defer srcGitRepo.Close()
+		gitRepo, err := git.OpenRepository(git.DefaultContext, repo.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+
+		assertBranchCommit := func(branch, srcBranch string) {
+			srcCommitID, err := srcGitRepo.GetBranchCommitID(srcBranch)
+			assert.NoError(t, err)
+			commitID, err := gitRepo.GetBranchCommitID(branch)
+			assert.NoError(t, err)
+			assert.Equal(t, srcCommitID, commitID, branch)
+		}
+		assertBranchCommit("vendor/master", "master")
+		assertBranchCommit("develop", "develop")
+		assert.False(t, gitRepo.IsBranchExist("branch2"))
+		assert.True(t, gitRepo.IsBranchExist("main"))
+
+		// both the upstream and the mirrored branch get new commits, a pull request merges the upstream changes
+		testEditFile(t, session, user.Name, repo.Name, "vendor/master", "README.md", "downstream change")
+		testEditFile(t, session, user.Name, srcRepo.Name, "master", "README.md", "upstream change")
+		assert.True(t, mirror_service.SyncPartialMirror(context.Background(), m.ID))
+
+		assertBranchCommit("vendor/master-upstream", "master")
+		m, err = repo_model.GetPartialMirrorByID(db.DefaultContext, m.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"vendor/master"}, m.DivergedBranches)
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{BaseRepoID: repo.ID, HeadBranch: "vendor/master-upstream", BaseBranch: "vendor/master"})
+
+		// the next sync updates the existing pull request
+		assert.True(t, mirror_service.SyncPartialMirror(context.Background(), m.ID))
+		unittest.AssertCount(t, &issues_model.PullRequest{BaseRepoID: repo.ID}, 1)
+		assert.False(t, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: pr.IssueID}).IsClosed)
+
+		req = NewRequestWithValues(t, "POST", link, map[string]string{
+			"_csrf":             GetCSRF(t, session, link),
+			"action":            "partial-mirror-remove",
+			"partial_mirror_id": fmt.Sprint(m.ID),
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+		unittest.AssertNotExistsBean(t, &repo_model.PartialMirror{ID: m.ID})
+		assert.False(t, git.IsReferenceExist(git.DefaultContext, repo.RepoPath(), git.RemotePrefix+m.RemoteName+"/master"))
+	})
+}
@@ -78,6 +78,76 @@ func testMirrorPush(t *testing.T, u *url.URL) {
 	assert.Len(t, mirrors, 0)
 }
 
+func TestMirrorPushFiltered(t *testing.T) {
+	onGiteaRun(t, testMirrorPushFiltered)
+}
+
+func testMirrorPushFiltered(t *testing.T, u *url.URL) {
+	defer tests.PrepareTestEnv(t)()
+
+	setting.Migrations.AllowLocalNetworks = true
+	assert.NoError(t, migrations.Init())
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	srcRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// the mirror has a branch which only exists downstream
+	mirrorRepo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user, user, repo_service.CreateRepoOptions{
+		Name:          "test-push-mirror-filtered",
+		AutoInit:      true,
+		Readme:        "Default",
+		DefaultBranch: "downstream",
+	})
+	assert.NoError(t, err)
+
+	ctx := NewAPITestContext(t, user.LowerName, srcRepo.Name)
+	csrf := GetCSRF(t, ctx.Session, fmt.Sprintf("/%s/%s/settings", url.PathEscape(ctx.Username), url.PathEscape(ctx.Reponame)))
+	req := NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/settings", url.PathEscape(ctx.Username), url.PathEscape(ctx.Reponame)), map[string]string{
+		"_csrf":                       csrf,
+		"action":                      "push-mirror-add",
+		"push_mirror_address":         fmt.Sprintf("%s%s/%s", u.String(), url.PathEscape(ctx.Username), url.PathEscape(mirrorRepo.Name)),
+		"push_mirror_username":        user.LowerName,
+		"push_mirror_password":        userPassword,
+		"push_mirror_interval":        "0",
+		"push_mirror_ref_excludes":    "branch2\nrefs/tags/*",
+		"push_mirror_non_destructive": "on",
+	})
+	ctx.Session.MakeRequest(t, req, http.StatusSeeOther)
+
+	mirrors, _, err := repo_model.GetPushMirrorsByRepoID(db.DefaultContext, srcRepo.ID, db.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, mirrors, 1)
+	assert.True(t, mirrors[0].NonDestructive)
+
+	assert.True(t, mirror_service.SyncPushMirror(context.Background(), mirrors[0].ID))
+
+	mirrorGitRepo, err := git.OpenRepository(git.DefaultContext, mirrorRepo.RepoPath())
+	assert.NoError(t, err)
+	defer mirrorGitRepo.Close()
+
+	assert.True(t, mirrorGitRepo.IsBranchExist("master"))
+	assert.True(t, mirrorGitRepo.IsBranchExist("develop"))
+	assert.True(t, mirrorGitRepo.IsBranchExist("downstream"))
+	assert.False(t, mirrorGitRepo.IsBranchExist("branch2"))
+	assert.False(t, mirrorGitRepo.IsTagExist("v1.1"))
+
+	// a non-destructive mirror refuses to overwrite a branch which diverged downstream
+	downstreamCommit, err := mirrorGitRepo.GetBranchCommitID("downstream")
+	assert.NoError(t, err)
+	assert.NoError(t, git.NewCommand(git.DefaultContext, "update-ref", "refs/heads/develop").AddDynamicArguments(downstreamCommit).Run(&git.RunOpts{Dir: mirrorRepo.RepoPath()}))
+	assert.False(t, mirror_service.SyncPushMirror(context.Background(), mirrors[0].ID))
+	developCommit, err := mirrorGitRepo.GetBranchCommitID("develop")
+	assert.NoError(t, err)
+	assert.Equal(t, downstreamCommit, developCommit)
+
+	failures, err := repo_model.GetPushMirrorFailures(db.DefaultContext, srcRepo.ID)
+	assert.NoError(t, err)
+	assert.Len(t, failures[mirrors[0].ID], 1)
+
+	doRemovePushMirror(ctx, "", user.LowerName, userPassword, int(mirrors[0].ID))(t)
+	unittest.AssertNotExistsBean(t, &repo_model.PushMirrorFailure{PushMirrorID: mirrors[0].ID})
+}
+
 func doCreatePushMirror(ctx APITestContext, address, username, password string) func(t *testing.T) {
 	return func(t *testing.T) {
 		csrf := GetCSRF(t, ctx.Session, fmt.Sprintf("/%s/%s/settings", url.PathEscape(ctx.Username), url.PathEscape(ctx.Reponame)))
@@ -4,10 +4,16 @@
 package integration
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
+	project_model "code.gitea.io/gitea/models/project"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPrivateRepoProject(t *testing.T) {
@@ -21,3 +27,32 @@ func TestPrivateRepoProject(t *testing.T) {
 	req = NewRequest(t, "GET", "/user31/-/projects")
 	sess.MakeRequest(t, req, http.StatusOK)
 }
+
+func TestRepoProjectViews(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/user2/repo1/projects/1/views/1")
+	resp := MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".project-view-tabs a.item.active").Length())
+
+	sess := loginUser(t, "user2")
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/projects/1/views/new", map[string]string{
+		"_csrf":    GetCSRF(t, sess, "/user2/repo1/projects/1"),
+		"name":     "Roadmap",
+		"type":     "roadmap",
+		"group_by": "milestone",
+	})
+	resp = sess.MakeRequest(t, req, http.StatusSeeOther)
+	view := unittest.AssertExistsAndLoadBean(t, &project_model.View{ProjectID: 1, Name: "Roadmap"})
+	assert.Equal(t, fmt.Sprintf("/user2/repo1/projects/1/views/%d", view.ID), test.RedirectURL(resp))
+
+	req = NewRequest(t, "GET", test.RedirectURL(resp))
+	sess.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/user2/repo1/projects/1/views/%d/delete", view.ID), map[string]string{
+		"_csrf": GetCSRF(t, sess, "/user2/repo1/projects/1"),
+	})
+	sess.MakeRequest(t, req, http.StatusOK)
+	unittest.AssertNotExistsBean(t, &project_model.View{ID: view.ID})
+}
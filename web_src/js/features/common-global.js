@@ -366,6 +366,8 @@ function initGlobalShowModal() {
 
       if (attrTargetAttr) {
         $attrTarget[0][attrTargetAttr] = attrib.value;
+      } else if ($attrTarget.is('input[type="checkbox"]')) {
+        $attrTarget.prop('checked', attrib.value === 'true');
       } else if ($attrTarget.is('input') || $attrTarget.is('textarea')) {
         $attrTarget.val(attrib.value); // FIXME: add more supports like checkbox
       } else {
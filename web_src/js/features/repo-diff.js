@@ -30,6 +30,29 @@ function initRepoDiffReviewButton() {
   });
 }
 
+function initRepoDiffSuggestionBatch() {
+  const $batch = $('#suggestion-batch');
+  if (!$batch.length) return;
+  const $input = $batch.find('input[name="comment_ids"]');
+  const $counter = $batch.find('.suggestion-batch-counter');
+  const batch = new Set();
+
+  $(document).on('click', '.toggle-suggestion-batch', function () {
+    const $this = $(this);
+    const commentId = $this.attr('data-comment-id');
+    if (batch.has(commentId)) {
+      batch.delete(commentId);
+      $this.text($this.attr('data-add-text'));
+    } else {
+      batch.add(commentId);
+      $this.text($this.attr('data-remove-text'));
+    }
+    $input.val(Array.from(batch).join(','));
+    $counter.text(batch.size);
+    $batch.toggleClass('gt-hidden', batch.size === 0);
+  });
+}
+
 function initRepoDiffFileViewToggle() {
   $('.file-view-toggle').on('click', function () {
     const $this = $(this);
@@ -202,6 +225,7 @@ export function initRepoDiffView() {
   initDiffCommitSelect();
   initRepoDiffShowMore();
   initRepoDiffReviewButton();
+  initRepoDiffSuggestionBatch();
   initRepoDiffFileViewToggle();
   initViewedCheckboxListenerFor();
   initExpandAndCollapseFilesButton();
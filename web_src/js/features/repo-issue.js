@@ -95,27 +95,39 @@ export function initRepoIssueSidebarList() {
   if (crossRepoSearch === 'true') {
     issueSearchUrl = `${appSubUrl}/issues/search?q={query}&priority_repo_id=${repoId}&type=${tp}`;
   }
+  // Parse the response from the api to work with our dropdown, the current issue is not listed
+  const issueSearchResponse = (currIssueId) => (response) => {
+    const filteredResponse = {success: true, results: []};
+    $.each(response, (_i, issue) => {
+      if (issue.id === currIssueId) {
+        return;
+      }
+      filteredResponse.results.push({
+        name: `#${issue.number} ${htmlEscape(issue.title)
+        }<div class="text small gt-word-break">${htmlEscape(issue.repository.full_name)}</div>`,
+        value: issue.id,
+      });
+    });
+    return filteredResponse;
+  };
+
   $('#new-dependency-drop-list')
     .dropdown({
       apiSettings: {
         url: issueSearchUrl,
-        onResponse(response) {
-          const filteredResponse = {success: true, results: []};
-          const currIssueId = $('#new-dependency-drop-list').data('issue-id');
-          // Parse the response from the api to work with our dropdown
-          $.each(response, (_i, issue) => {
-            // Don't list current issue in the dependency list.
-            if (issue.id === currIssueId) {
-              return;
-            }
-            filteredResponse.results.push({
-              name: `#${issue.number} ${htmlEscape(issue.title)
-              }<div class="text small gt-word-break">${htmlEscape(issue.repository.full_name)}</div>`,
-              value: issue.id,
-            });
-          });
-          return filteredResponse;
-        },
+        onResponse: issueSearchResponse($('#new-dependency-drop-list').data('issue-id')),
+        cache: false,
+      },
+
+      fullTextSearch: true,
+    });
+
+  // The sub-issues can be any issue of the repositories of the owner
+  $('#new-sub-issue-drop-list')
+    .dropdown({
+      apiSettings: {
+        url: $('#new-sub-issue-drop-list').data('search-url'),
+        onResponse: issueSearchResponse($('#new-sub-issue-drop-list').data('issue-id')),
         cache: false,
       },
 
@@ -481,6 +493,9 @@ export function initRepoPullRequestReview() {
     });
   }
 
+  // the last line a comment was started on, shift-clicking a later line of the same file side comments on the whole range
+  let lastCommentLine = null;
+
   $(document).on('click', '.add-code-comment', async function (e) {
     if ($(e.target).hasClass('btn-add-single')) return; // https://github.com/go-gitea/gitea/issues/4745
     e.preventDefault();
@@ -489,6 +504,11 @@ export function initRepoPullRequestReview() {
     const side = $(this).data('side');
     const idx = $(this).data('idx');
     const path = $(this).closest('[data-path]').data('path');
+    let startIdx = 0;
+    if (e.shiftKey && lastCommentLine && lastCommentLine.path === path && lastCommentLine.side === side && lastCommentLine.idx < idx) {
+      startIdx = lastCommentLine.idx;
+    }
+    lastCommentLine = {path, side, idx};
     const tr = $(this).closest('tr');
     const lineType = tr.data('line-type');
 
@@ -512,6 +532,12 @@ export function initRepoPullRequestReview() {
       const html = await $.get($(this).closest('[data-new-comment-url]').attr('data-new-comment-url'));
       td.html(html);
       td.find("input[name='line']").val(idx);
+      if (startIdx) {
+        td.find("input[name='start_line']").val(startIdx);
+        const $range = td.find('.code-comment-range');
+        $range.text($range.attr('data-text-template').replace('%[1]d', startIdx).replace('%[2]d', idx));
+        $range.removeClass('gt-hidden');
+      }
       td.find("input[name='side']").val(side === 'left' ? 'previous' : 'proposed');
       td.find("input[name='path']").val(path);
 